@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -194,9 +195,12 @@ func printResolvedConfig(cmd *cobra.Command, rc *config.ResolvedConfig) {
 	printField(out, "task_state_file", fmtStr(p.TaskStateFile), rc.Sources["project.task_state_file"])
 	printField(out, "phases_conf", fmtStr(p.PhasesConf), rc.Sources["project.phases_conf"])
 	printField(out, "progress_file", fmtStr(p.ProgressFile), rc.Sources["project.progress_file"])
+	printField(out, "progress_formats", fmtSlice(p.ProgressFormats), rc.Sources["project.progress_formats"])
 	printField(out, "log_dir", fmtStr(p.LogDir), rc.Sources["project.log_dir"])
 	printField(out, "prompt_dir", fmtStr(p.PromptDir), rc.Sources["project.prompt_dir"])
 	printField(out, "branch_template", fmtStr(p.BranchTemplate), rc.Sources["project.branch_template"])
+	printField(out, "task_id_prefix", fmtStr(p.TaskIDPrefix), rc.Sources["project.task_id_prefix"])
+	printField(out, "task_strategy", fmtStr(p.TaskStrategy), rc.Sources["project.task_strategy"])
 	printField(out, "verification_commands", fmtSlice(p.VerificationCommands), rc.Sources["project.verification_commands"])
 	fmt.Fprintln(out)
 
@@ -229,8 +233,96 @@ func printResolvedConfig(cmd *cobra.Command, rc *config.ResolvedConfig) {
 	printField(out, "prompts_dir", fmtStr(r.PromptsDir), rc.Sources["review.prompts_dir"])
 	printField(out, "rules_dir", fmtStr(r.RulesDir), rc.Sources["review.rules_dir"])
 	printField(out, "project_brief_file", fmtStr(r.ProjectBriefFile), rc.Sources["review.project_brief_file"])
+	printField(out, "invalid_findings", fmtStr(r.InvalidFindings), rc.Sources["review.invalid_findings"])
+	printField(out, "dedup", fmtStr(r.Dedup), rc.Sources["review.dedup"])
+	printField(out, "dedup_line_window", fmtIntPtr(r.DedupLineWindow), rc.Sources["review.dedup_line_window"])
+	printField(out, "dedup_threshold", strconv.FormatFloat(r.DedupThreshold, 'g', -1, 64), rc.Sources["review.dedup_threshold"])
+	printField(out, "token_budget", strconv.Itoa(r.TokenBudget), rc.Sources["review.token_budget"])
 	fmt.Fprintln(out)
 
+	// --- [review.token_budgets] (sorted for determinism) ---
+	if len(r.TokenBudgets) > 0 {
+		budgetAgents := make([]string, 0, len(r.TokenBudgets))
+		for n := range r.TokenBudgets {
+			budgetAgents = append(budgetAgents, n)
+		}
+		sort.Strings(budgetAgents)
+
+		fmt.Fprintln(out, styleSection.Render("[review.token_budgets]"))
+		for _, name := range budgetAgents {
+			printField(out, name, strconv.Itoa(r.TokenBudgets[name]), rc.Sources["review.token_budgets."+name])
+		}
+		fmt.Fprintln(out)
+	}
+
+	// --- [review.personas.*] (sorted for determinism) ---
+	if len(r.Personas) > 0 {
+		personaNames := make([]string, 0, len(r.Personas))
+		for n := range r.Personas {
+			personaNames = append(personaNames, n)
+		}
+		sort.Strings(personaNames)
+
+		for _, name := range personaNames {
+			persona := r.Personas[name]
+			prefix := "review.personas." + name
+			fmt.Fprintln(out, styleSection.Render(fmt.Sprintf("[review.personas.%s]", name)))
+			printField(out, "agent", fmtStr(persona.Agent), rc.Sources[prefix+".agent"])
+			printField(out, "model", fmtStr(persona.Model), rc.Sources[prefix+".model"])
+			printField(out, "prompt", fmtStr(persona.Prompt), rc.Sources[prefix+".prompt"])
+			printField(out, "rules", fmtSlice(persona.Rules), rc.Sources[prefix+".rules"])
+			printField(out, "paths", fmtSlice(persona.Paths), rc.Sources[prefix+".paths"])
+			fmt.Fprintln(out)
+		}
+	}
+
+	// --- [review.analyzers.*] (sorted for determinism) ---
+	if len(r.Analyzers) > 0 {
+		analyzerNames := make([]string, 0, len(r.Analyzers))
+		for n := range r.Analyzers {
+			analyzerNames = append(analyzerNames, n)
+		}
+		sort.Strings(analyzerNames)
+
+		for _, name := range analyzerNames {
+			analyzer := r.Analyzers[name]
+			prefix := "review.analyzers." + name
+			fmt.Fprintln(out, styleSection.Render(fmt.Sprintf("[review.analyzers.%s]", name)))
+			printField(out, "command", fmtStr(analyzer.Command), rc.Sources[prefix+".command"])
+			printField(out, "format", fmtStr(analyzer.Format), rc.Sources[prefix+".format"])
+			printField(out, "pattern", fmtStr(analyzer.Pattern), rc.Sources[prefix+".pattern"])
+			printField(out, "severity", fmtStr(analyzer.Severity), rc.Sources[prefix+".severity"])
+			printField(out, "category", fmtStr(analyzer.Category), rc.Sources[prefix+".category"])
+			printField(out, "context", strconv.FormatBool(analyzer.Context), rc.Sources[prefix+".context"])
+			fmt.Fprintln(out)
+		}
+	}
+
+	// --- [review.policy] ---
+	policy := r.Policy
+	fmt.Fprintln(out, styleSection.Render("[review.policy]"))
+	printField(out, "blocking_quorum", strconv.FormatFloat(policy.BlockingQuorum, 'g', -1, 64), rc.Sources["review.policy.blocking_quorum"])
+	printField(out, "changes_quorum", strconv.FormatFloat(policy.ChangesQuorum, 'g', -1, 64), rc.Sources["review.policy.changes_quorum"])
+	printField(out, "block_severity", fmtStr(policy.BlockSeverity), rc.Sources["review.policy.block_severity"])
+	printField(out, "approve_below", fmtStr(policy.ApproveBelow), rc.Sources["review.policy.approve_below"])
+	printField(out, "agent_failure", fmtStr(policy.AgentFailure), rc.Sources["review.policy.agent_failure"])
+	fmt.Fprintln(out)
+
+	// --- [review.policy.weights] (sorted for determinism) ---
+	if len(policy.Weights) > 0 {
+		weighted := make([]string, 0, len(policy.Weights))
+		for n := range policy.Weights {
+			weighted = append(weighted, n)
+		}
+		sort.Strings(weighted)
+
+		fmt.Fprintln(out, styleSection.Render("[review.policy.weights]"))
+		for _, name := range weighted {
+			printField(out, name, strconv.FormatFloat(policy.Weights[name], 'g', -1, 64), rc.Sources["review.policy.weights."+name])
+		}
+		fmt.Fprintln(out)
+	}
+
 	// --- [workflows.*] (sorted for determinism) ---
 	if len(rc.Config.Workflows) > 0 {
 		wfNames := make([]string, 0, len(rc.Config.Workflows))
@@ -263,6 +355,14 @@ func fmtStr(s string) string {
 	return fmt.Sprintf("%q", s)
 }
 
+// fmtIntPtr formats an optional int for display; nil is shown as "unset".
+func fmtIntPtr(n *int) string {
+	if n == nil {
+		return "unset"
+	}
+	return strconv.Itoa(*n)
+}
+
 // fmtSlice formats a string slice for display.
 func fmtSlice(ss []string) string {
 	if len(ss) == 0 {
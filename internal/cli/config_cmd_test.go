@@ -530,6 +530,25 @@ func TestPrintResolvedConfig_FileSources(t *testing.T) {
 	assert.Contains(t, output, "(source: default)")
 }
 
+func TestPrintResolvedConfig_ReviewPersonas(t *testing.T) {
+	fileCfg := &config.Config{
+		Review: config.ReviewConfig{Personas: map[string]config.PersonaConfig{
+			"security": {Agent: "claude", Paths: []string{"internal/**"}},
+		}},
+	}
+	resolved := config.Resolve(config.NewDefaults(), fileCfg, func(string) (string, bool) { return "", false }, nil)
+
+	var buf bytes.Buffer
+	configDebugCmd.SetOut(&buf)
+	printResolvedConfig(configDebugCmd, resolved)
+	configDebugCmd.SetOut(nil)
+
+	output := buf.String()
+
+	assert.Contains(t, output, "[review.personas.security]")
+	assert.Contains(t, output, `["internal/**"]`)
+}
+
 func TestPrintValidationResult_NoIssues(t *testing.T) {
 	result := &config.ValidationResult{}
 
@@ -759,3 +778,71 @@ func TestSourceStyle_AllSources(t *testing.T) {
 			"sourceStyle should produce non-empty render for source %q", src)
 	}
 }
+
+func TestPrintResolvedConfig_ReviewAnalyzers(t *testing.T) {
+	fileCfg := &config.Config{
+		Review: config.ReviewConfig{Analyzers: map[string]config.AnalyzerConfig{
+			"vet": {Command: "go vet ./...", Format: "line", Context: true},
+		}},
+	}
+	resolved := config.Resolve(config.NewDefaults(), fileCfg, func(string) (string, bool) { return "", false }, nil)
+
+	var buf bytes.Buffer
+	configDebugCmd.SetOut(&buf)
+	printResolvedConfig(configDebugCmd, resolved)
+	configDebugCmd.SetOut(nil)
+
+	output := buf.String()
+
+	assert.Contains(t, output, "[review.analyzers.vet]")
+	assert.Contains(t, output, `"go vet ./..."`)
+	assert.Contains(t, output, "true")
+}
+
+func TestPrintResolvedConfig_ReviewTokenBudgets(t *testing.T) {
+	fileCfg := &config.Config{
+		Review: config.ReviewConfig{
+			TokenBudget:  60000,
+			TokenBudgets: map[string]int{"codex": 100000, "claude": 150000},
+		},
+	}
+	resolved := config.Resolve(config.NewDefaults(), fileCfg, func(string) (string, bool) { return "", false }, nil)
+
+	var buf bytes.Buffer
+	configDebugCmd.SetOut(&buf)
+	printResolvedConfig(configDebugCmd, resolved)
+	configDebugCmd.SetOut(nil)
+
+	output := buf.String()
+
+	assert.Contains(t, output, "60000")
+	assert.Contains(t, output, "[review.token_budgets]")
+	assert.Contains(t, output, "150000")
+	assert.Less(t, strings.Index(output, "150000"), strings.Index(output, "100000"))
+}
+
+func TestPrintResolvedConfig_ReviewPolicy(t *testing.T) {
+	fileCfg := &config.Config{
+		Review: config.ReviewConfig{
+			Policy: config.PolicyConfig{
+				BlockingQuorum: 2,
+				ApproveBelow:   "medium",
+				Weights:        map[string]float64{"codex": 0.5, "claude": 2},
+			},
+		},
+	}
+	resolved := config.Resolve(config.NewDefaults(), fileCfg, func(string) (string, bool) { return "", false }, nil)
+
+	var buf bytes.Buffer
+	configDebugCmd.SetOut(&buf)
+	printResolvedConfig(configDebugCmd, resolved)
+	configDebugCmd.SetOut(nil)
+
+	output := buf.String()
+
+	assert.Contains(t, output, "[review.policy]")
+	assert.Contains(t, output, `"medium"`)
+	assert.Contains(t, output, "[review.policy.weights]")
+	assert.Contains(t, output, "0.5")
+	assert.Less(t, strings.Index(output, "claude"), strings.LastIndex(output, "codex"))
+}
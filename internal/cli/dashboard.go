@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -10,9 +11,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/AbdelazizMoustafa10m/Raven/internal/buildinfo"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/config"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/logging"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/loop"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/pipeline"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/tui"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/workflow"
 )
@@ -111,6 +114,15 @@ func runDashboard(cmd *cobra.Command, _ []string) error {
 		TaskProgress:   taskProgress,
 		Engine:         engine,
 	}
+	if resolved != nil {
+		membership, completed, phasesErr := dashboardPhases(resolved.Config)
+		if phasesErr != nil {
+			logger.Warn("loading phases failed; phase progress disabled", "error", phasesErr)
+		} else {
+			cfg.Phases = membership
+			cfg.CompletedTasks = completed
+		}
+	}
 
 	logger.Info("launching TUI dashboard",
 		"version", info.Version,
@@ -119,3 +131,36 @@ func runDashboard(cmd *cobra.Command, _ []string) error {
 
 	return tui.RunTUI(cfg)
 }
+
+// dashboardPhases loads the phase membership and the completed task IDs shown
+// in the sidebar's phase progress. Returns a nil membership when no phases
+// file is configured or it does not exist.
+func dashboardPhases(cfg *config.Config) (*task.PhaseMembership, []string, error) {
+	if cfg.Project.PhasesConf == "" {
+		return nil, nil, nil
+	}
+	phases, err := task.LoadPhases(cfg.Project.PhasesConf, cfg.Project.TaskIDPrefix)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("loading phases: %w", err)
+	}
+
+	specs, err := task.DiscoverTasks(cfg.Project.TasksDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("discovering tasks: %w", err)
+	}
+	stateMap, err := task.NewStateManager(cfg.Project.TaskStateFile).LoadMap()
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading task state: %w", err)
+	}
+
+	var completed []string
+	for _, spec := range specs {
+		if ts, ok := stateMap[spec.ID]; ok && ts.Status == task.StatusCompleted {
+			completed = append(completed, spec.ID)
+		}
+	}
+	return task.NewPhaseMembership(phases, specs), completed, nil
+}
@@ -42,7 +42,7 @@ func buildRuntimeHandlerDeps(
 
 	// --- 2. Load phases if not provided ---
 	if len(phases) == 0 && cfg.Project.PhasesConf != "" {
-		loaded, loadErr := task.LoadPhases(cfg.Project.PhasesConf)
+		loaded, loadErr := task.LoadPhases(cfg.Project.PhasesConf, cfg.Project.TaskIDPrefix)
 		if loadErr == nil {
 			phases = loaded
 		}
@@ -51,6 +51,11 @@ func buildRuntimeHandlerDeps(
 
 	// --- 3. Create task selector ---
 	selector := task.NewTaskSelector(specs, stateManager, phases)
+	strategy, err := task.NewSelectionStrategy(cfg.Project.TaskStrategy)
+	if err != nil {
+		return nil, err
+	}
+	selector.SetStrategy(strategy)
 
 	// --- 4. Build agent registry ---
 	agentRegistry, err := buildAgentRegistry(cfg.Agents, implementFlags{})
@@ -94,6 +99,7 @@ func buildRuntimeHandlerDeps(
 		nil, // events channel (nil = no event fan-out)
 		runnerLog,
 	)
+	runner.SetAgentRegistry(agentRegistry)
 
 	// --- 9. Create ReviewOrchestrator ---
 	reviewCfg := configToReviewConfig(cfg.Review)
@@ -120,7 +126,18 @@ func buildRuntimeHandlerDeps(
 		}
 
 		promptBuilder := review.NewPromptBuilder(reviewCfg, reviewLogger)
-		consolidator := review.NewConsolidator(reviewLogger)
+		consolidator := newReviewConsolidator(reviewCfg, reviewLogger)
+		if baseline, baselineErr := review.LoadBaseline(defaultReviewBaselineFile); baselineErr != nil {
+			logger.Warn("review baseline unavailable", "error", baselineErr)
+		} else {
+			consolidator.WithBaseline(baseline)
+		}
+		policy := configToVerdictPolicy(cfg.Review.Policy)
+		if policyErr := policy.Validate(); policyErr != nil {
+			logger.Warn("invalid review policy; using the default", "error", policyErr)
+		} else {
+			consolidator.WithPolicy(policy)
+		}
 
 		concurrency := opts.ReviewConcurrency
 		if concurrency <= 0 {
@@ -135,8 +152,16 @@ func buildRuntimeHandlerDeps(
 			concurrency,
 			reviewLogger,
 			nil, // events channel
-		)
+		).WithTokenBudget(cfg.Review.TokenBudget, cfg.Review.TokenBudgets)
+	}
+
+	// All configured personas review in the pipeline; without personas the
+	// review step uses the agents named in the workflow state.
+	reviewPersonas, err := resolveReviewPersonas(reviewFlags{}, cfg.Review.Personas)
+	if err != nil {
+		return nil, fmt.Errorf("resolving review personas: %w", err)
 	}
+	reviewAnalyzers := configToAnalyzers(cfg.Review.Analyzers)
 
 	// --- 10. Create FixEngine ---
 	fixAgentName := opts.FixAgent
@@ -178,6 +203,7 @@ func buildRuntimeHandlerDeps(
 
 	// --- 11. Create PRCreator ---
 	prCreator := review.NewPRCreator("", logging.New("pr"))
+	reviewPublisher := review.NewReviewPublisher(review.NewCLIRunner(""), logging.New("publish"))
 
 	// --- 12. Build RunConfig with reasonable defaults ---
 	runCfg := loop.RunConfig{
@@ -191,7 +217,11 @@ func buildRuntimeHandlerDeps(
 		Runner:             runner,
 		RunConfig:          runCfg,
 		ReviewOrchestrator: orchestrator,
+		ReviewPersonas:     reviewPersonas,
+		ReviewAnalyzers:    reviewAnalyzers,
+		ReviewLedgers:      review.NewReviewStateStore(defaultReviewStateDir),
 		FixEngine:          fixEngine,
 		PRCreator:          prCreator,
+		ReviewPublisher:    reviewPublisher,
 	}, nil
 }
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+// graphFormats lists the output formats accepted by --format.
+var graphFormats = []string{"dot", "mermaid", "json"}
+
+// graphFlags holds the flag values for the graph command.
+type graphFlags struct {
+	Format string // --format dot|mermaid|json
+	Phase  int    // --phase <id>, 0 means all phases
+	Output string // --output <file>, empty means stdout
+}
+
+// newGraphCmd creates the "raven graph" command.
+func newGraphCmd() *cobra.Command {
+	var flags graphFlags
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Export the task dependency graph with live status",
+		Long: `Build the task dependency graph from the task specs and the task state file
+and export it as Graphviz DOT, Mermaid, or JSON.
+
+Nodes are coloured by status: green for completed, yellow for in progress,
+red for blocked, grey for skipped, and white for not started. The critical
+path -- the longest chain of unfinished tasks, weighted by estimated effort
+-- is outlined in red. Tasks on the frontier (not started with every
+dependency completed, so they can be worked on in parallel) get a heavier
+border.
+
+With --phase, only that phase's tasks are included, plus any tasks from
+other phases they depend on (drawn dashed), so cross-phase blockers remain
+visible.
+
+For DOT and Mermaid output, a short critical-path and frontier summary is
+printed to stderr.`,
+		Example: `  # Render the whole graph with Graphviz
+  raven graph | dot -Tsvg -o tasks.svg
+
+  # Mermaid graph for phase 2
+  raven graph --format mermaid --phase 2
+
+  # Machine-readable graph with critical path and frontier
+  raven graph --format json --output graph.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGraph(cmd, flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.Format, "format", "dot", `Output format: "dot", "mermaid", or "json"`)
+	cmd.Flags().IntVar(&flags.Phase, "phase", 0, "Filter to a single phase (0 = all phases)")
+	cmd.Flags().StringVar(&flags.Output, "output", "", "Write the graph to file instead of stdout")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return graphFormats, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newGraphCmd())
+}
+
+// runGraph is the command's RunE function. Loads config, discovers tasks,
+// builds the dependency graph, and renders it in the requested format.
+func runGraph(cmd *cobra.Command, flags graphFlags) error {
+	format := strings.ToLower(strings.TrimSpace(flags.Format))
+	if !isGraphFormat(format) {
+		return fmt.Errorf("invalid --format %q: must be one of %s", flags.Format, strings.Join(graphFormats, ", "))
+	}
+
+	resolved, _, err := loadAndResolveConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	cfg := resolved.Config
+
+	specs, err := task.DiscoverTasks(cfg.Project.TasksDir)
+	if err != nil {
+		return fmt.Errorf("discovering tasks: %w", err)
+	}
+
+	stateMap, err := task.NewStateManager(cfg.Project.TaskStateFile).LoadMap()
+	if err != nil {
+		return fmt.Errorf("loading task state: %w", err)
+	}
+
+	// Load phases -- gracefully handle missing phases.conf.
+	var phases []task.Phase
+	if cfg.Project.PhasesConf != "" {
+		phases, err = task.LoadPhases(cfg.Project.PhasesConf, cfg.Project.TaskIDPrefix)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("loading phases: %w", err)
+		}
+	}
+
+	var scope func(*task.ParsedTaskSpec) bool
+	if flags.Phase != 0 {
+		phase := task.PhaseByID(phases, flags.Phase)
+		if phase == nil {
+			return fmt.Errorf("phase %d not found", flags.Phase)
+		}
+		membership := task.NewPhaseMembership(phases, specs)
+		scope = func(spec *task.ParsedTaskSpec) bool {
+			return membership.Contains(phase.ID, spec.ID)
+		}
+	}
+
+	graph, err := task.BuildTaskGraph(specs, stateMap, phases, scope)
+	if err != nil {
+		return err
+	}
+
+	rendered, err := renderGraph(graph, format)
+	if err != nil {
+		return err
+	}
+
+	if flags.Output != "" {
+		if err := os.WriteFile(flags.Output, []byte(rendered), 0o644); err != nil {
+			return fmt.Errorf("writing graph to %q: %w", flags.Output, err)
+		}
+	} else {
+		fmt.Fprint(cmd.OutOrStdout(), rendered)
+	}
+
+	if format != "json" && !flagQuiet {
+		writeGraphSummary(cmd.ErrOrStderr(), graph)
+	}
+	return nil
+}
+
+// isGraphFormat reports whether format is one of graphFormats.
+func isGraphFormat(format string) bool {
+	for _, f := range graphFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// renderGraph renders graph in the given format.
+func renderGraph(graph *task.TaskGraph, format string) (string, error) {
+	switch format {
+	case "mermaid":
+		return graph.Mermaid(), nil
+	case "json":
+		data, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("encoding graph: %w", err)
+		}
+		return string(data) + "\n", nil
+	default:
+		return graph.DOT(), nil
+	}
+}
+
+// writeGraphSummary writes the critical path and the frontier to w.
+//
+//	Critical path (15.0h): T-003 -> T-004
+//	Ready now (2): T-002, T-005
+func writeGraphSummary(w io.Writer, graph *task.TaskGraph) {
+	if len(graph.CriticalPath) == 0 {
+		fmt.Fprintln(w, "Critical path: none (all tasks done)")
+	} else {
+		fmt.Fprintf(w, "Critical path (%.1fh): %s\n",
+			graph.CriticalPathHours, strings.Join(graph.CriticalPath, " -> "))
+	}
+	if len(graph.Frontier) == 0 {
+		fmt.Fprintln(w, "Ready now: none")
+		return
+	}
+	fmt.Fprintf(w, "Ready now (%d): %s\n", len(graph.Frontier), strings.Join(graph.Frontier, ", "))
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+// resetGraphFlags resets the graph command's local flags for inter-test isolation.
+func resetGraphFlags(t *testing.T) {
+	t.Helper()
+	resetRootCmd(t)
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Use == "graph" {
+			cmd.Flags().VisitAll(func(f *pflag.Flag) {
+				f.Changed = false
+				if err := f.Value.Set(f.DefValue); err != nil {
+					t.Logf("resetting flag %q: %v", f.Name, err)
+				}
+			})
+			break
+		}
+	}
+}
+
+// writeGraphProject writes a small project with three tasks across two phases
+// and returns the raven.toml path.
+//
+//	T-001 (completed) -> T-002 (Large) -> T-003 (Small, phase 2)
+func writeGraphProject(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	spec := func(id, title, effort, deps string) string {
+		return fmt.Sprintf("# %s: %s\n\n| Field | Value |\n|-------|-------|\n| Estimated Effort | %s |\n| Dependencies | %s |\n",
+			id, title, effort, deps)
+	}
+	files := map[string]string{
+		"T-001-setup.md": spec("T-001", "Setup", "Small: 1-3hrs", "None"),
+		"T-002-core.md":  spec("T-002", "Core", "Large: 10-16hrs", "T-001"),
+		"T-003-cli.md":   spec("T-003", "CLI", "Small: 1-3hrs", "T-002"),
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o644))
+	}
+
+	statePath := filepath.Join(tmpDir, "task-state.conf")
+	require.NoError(t, os.WriteFile(statePath, []byte("T-001|completed|claude||\n"), 0o644))
+
+	phasesPath := filepath.Join(tmpDir, "phases.conf")
+	require.NoError(t, os.WriteFile(phasesPath, []byte("1|Foundation|T-001|T-002\n2|Interface|T-003|T-003\n"), 0o644))
+
+	tomlContent := fmt.Sprintf("[project]\nname = \"graph-project\"\ntasks_dir = %q\ntask_state_file = %q\nphases_conf = %q\n",
+		tmpDir, statePath, phasesPath)
+	tomlPath := filepath.Join(tmpDir, "raven.toml")
+	require.NoError(t, os.WriteFile(tomlPath, []byte(tomlContent), 0o644))
+	return tomlPath
+}
+
+func TestGraphCmd_JSON(t *testing.T) {
+	tomlPath := writeGraphProject(t)
+	resetGraphFlags(t)
+
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	rootCmd.SetArgs([]string{"--config", tomlPath, "graph", "--format", "json"})
+	code := Execute()
+	require.Equal(t, 0, code, stderr.String())
+
+	var graph task.TaskGraph
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &graph))
+	assert.Len(t, graph.Nodes, 3)
+	assert.Len(t, graph.Edges, 2)
+	assert.Equal(t, []string{"T-002", "T-003"}, graph.CriticalPath)
+	assert.InDelta(t, 15.0, graph.CriticalPathHours, 0.001)
+	assert.Equal(t, []string{"T-002"}, graph.Frontier)
+	assert.Empty(t, stderr.String(), "no summary for JSON output")
+}
+
+func TestGraphCmd_PhaseFilterDOT(t *testing.T) {
+	tomlPath := writeGraphProject(t)
+	resetGraphFlags(t)
+
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	rootCmd.SetArgs([]string{"--config", tomlPath, "graph", "--phase", "2"})
+	code := Execute()
+	require.Equal(t, 0, code, stderr.String())
+
+	dot := stdout.String()
+	assert.Contains(t, dot, "digraph tasks {")
+	assert.Contains(t, dot, `"T-003" [label="T-003\nCLI"`)
+	assert.Contains(t, dot, `style="rounded,filled,dashed"`, "T-002 is drawn as an external dependency")
+	assert.NotContains(t, dot, `"T-001"`, "dependencies of external tasks are not followed")
+	assert.Contains(t, stderr.String(), "Critical path (15.0h): T-002 -> T-003")
+	assert.Contains(t, stderr.String(), "Ready now: none")
+}
+
+func TestGraphCmd_MermaidToFile(t *testing.T) {
+	tomlPath := writeGraphProject(t)
+	resetGraphFlags(t)
+
+	outPath := filepath.Join(t.TempDir(), "graph.mmd")
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	rootCmd.SetArgs([]string{"--config", tomlPath, "graph", "--format", "mermaid", "--output", outPath})
+	code := Execute()
+	require.Equal(t, 0, code, stderr.String())
+
+	data, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "graph LR")
+	assert.Contains(t, string(data), "class T_001 completed")
+	assert.Empty(t, stdout.String())
+}
+
+func TestRunGraph_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   graphFlags
+		wantErr string
+	}{
+		{name: "invalid format", flags: graphFlags{Format: "svg"}, wantErr: `invalid --format "svg"`},
+		{name: "unknown phase", flags: graphFlags{Format: "dot", Phase: 9}, wantErr: "phase 9 not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tomlPath := writeGraphProject(t)
+			resetGraphFlags(t)
+			flagConfig = tomlPath
+
+			cmd := newGraphCmd()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			err := runGraph(cmd, tt.flags)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestGraphCmd_FlagsRegistered(t *testing.T) {
+	var graphCmd *cobra.Command
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Use == "graph" {
+			graphCmd = cmd
+			break
+		}
+	}
+	require.NotNil(t, graphCmd, "graph command must be registered in rootCmd")
+
+	for _, name := range []string{"format", "phase", "output"} {
+		assert.NotNil(t, graphCmd.Flags().Lookup(name), "--%s flag must be registered", name)
+	}
+}
+
+func TestRenderGraph_EmptyGraphJSON(t *testing.T) {
+	t.Parallel()
+
+	g, err := task.BuildTaskGraph(nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	out, err := renderGraph(g, "json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"nodes":[],"edges":[],"critical_path":[],"critical_path_hours":0,"frontier":[]}`, out)
+}
@@ -39,6 +39,9 @@ type implementFlags struct {
 	DryRun bool
 	// Model overrides the agent's configured model.
 	Model string
+	// Strategy overrides project.task_strategy for picking the next ready
+	// task in phase mode.
+	Strategy string
 }
 
 // newImplementCmd creates the "raven implement" command.
@@ -74,6 +77,9 @@ the agent.`,
   # Override model for this run
   raven implement --agent claude --phase 2 --model claude-opus-4-6
 
+  # Work on the task that unblocks the most others first
+  raven implement --agent claude --phase 2 --strategy unblock
+
   # Custom iteration and wait limits
   raven implement --agent claude --phase 2 --max-iterations 100 --max-limit-waits 3 --sleep 10`,
 		Args: cobra.NoArgs,
@@ -96,11 +102,16 @@ the agent.`,
 	cmd.Flags().IntVar(&flags.Sleep, "sleep", 5, "Seconds to sleep between iterations")
 	cmd.Flags().BoolVar(&flags.DryRun, "dry-run", false, "Show prompts and commands without invoking the agent")
 	cmd.Flags().StringVar(&flags.Model, "model", "", "Override the agent's configured model for this run")
+	cmd.Flags().StringVar(&flags.Strategy, "strategy", "",
+		"Task selection strategy: id, priority, effort, critical-path, unblock (default: project.task_strategy)")
 
 	// Shell completion for --agent: provide list of known agent names.
 	_ = cmd.RegisterFlagCompletionFunc("agent", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"claude", "codex", "gemini"}, cobra.ShellCompDirectiveNoFileComp
 	})
+	_ = cmd.RegisterFlagCompletionFunc("strategy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return task.SelectionStrategyNames(), cobra.ShellCompDirectiveNoFileComp
+	})
 
 	return cmd
 }
@@ -144,7 +155,7 @@ func runImplement(cmd *cobra.Command, flags implementFlags) error {
 	// Step 5: Load phases (gracefully handle missing file for --task mode).
 	var phases []task.Phase
 	if cfg.Project.PhasesConf != "" {
-		phases, err = task.LoadPhases(cfg.Project.PhasesConf)
+		phases, err = task.LoadPhases(cfg.Project.PhasesConf, cfg.Project.TaskIDPrefix)
 		if err != nil {
 			if !errors.Is(err, os.ErrNotExist) {
 				return fmt.Errorf("loading phases from %q: %w", cfg.Project.PhasesConf, err)
@@ -164,8 +175,19 @@ func runImplement(cmd *cobra.Command, flags implementFlags) error {
 		}
 	}
 
-	// Step 6: Create task selector.
+	// Step 6: Create task selector with the requested selection strategy
+	// (--strategy > project.task_strategy > lowest ID first).
 	selector := task.NewTaskSelector(specs, stateManager, phases)
+	strategyName := cfg.Project.TaskStrategy
+	if flags.Strategy != "" {
+		strategyName = flags.Strategy
+	}
+	strategy, err := task.NewSelectionStrategy(strategyName)
+	if err != nil {
+		return err
+	}
+	selector.SetStrategy(strategy)
+	logger.Info("task selection strategy", "strategy", strategy.Name())
 
 	// Step 7: Build agent registry and register all known agents.
 	registry, err := buildAgentRegistry(cfg.Agents, flags)
@@ -214,15 +236,18 @@ func runImplement(cmd *cobra.Command, flags implementFlags) error {
 		nil, // events channel (nil = no event fan-out in CLI mode)
 		logger,
 	)
+	runner.SetAgentRegistry(registry)
 
-	// Step 12b: Wire progress generator for PROGRESS.md regeneration.
+	// Step 12b: Wire progress generator for PROGRESS.md (and the reports
+	// written next to it) regeneration.
 	if cfg.Project.ProgressFile != "" {
 		pg, pgErr := task.NewProgressGenerator(specs, stateManager, phases)
 		if pgErr != nil {
 			logger.Info("progress generator disabled", "error", pgErr)
 		} else {
+			pg.SetFormats(configuredProgressFormats(cfg)...)
 			runner.SetProgressGenerator(pg, cfg.Project.ProgressFile)
-			logger.Info("progress generator enabled", "path", cfg.Project.ProgressFile)
+			logger.Info("progress generator enabled", "path", cfg.Project.ProgressFile, "formats", pg.Formats())
 		}
 	}
 
@@ -235,6 +260,7 @@ func runImplement(cmd *cobra.Command, flags implementFlags) error {
 		MaxLimitWaits: flags.MaxLimitWaits,
 		SleepBetween:  time.Duration(flags.Sleep) * time.Second,
 		DryRun:        flags.DryRun || flagDryRun, // honour global --dry-run too
+		RunID:         fmt.Sprintf("implement-%d", time.Now().UnixNano()),
 	}
 
 	// Determine template name from agent config.
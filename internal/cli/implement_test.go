@@ -153,6 +153,7 @@ func TestNewImplementCmd_FlagExists(t *testing.T) {
 		"sleep",
 		"dry-run",
 		"model",
+		"strategy",
 	}
 	for _, name := range expectedFlags {
 		flag := cmd.Flags().Lookup(name)
@@ -362,6 +363,31 @@ func TestNewImplementCmd_AgentFlagHasShellCompletion(t *testing.T) {
 	assert.Len(t, completions, 3, "completion should list exactly the three known agents")
 }
 
+func TestNewImplementCmd_StrategyFlagHasShellCompletion(t *testing.T) {
+	cmd := newImplementCmd()
+
+	completionFn, exists := cmd.GetFlagCompletionFunc("strategy")
+	require.True(t, exists, "--strategy flag should have a completion function registered")
+
+	completions, directive := completionFn(cmd, []string{}, "")
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	assert.Equal(t, []string{"critical-path", "effort", "id", "priority", "unblock"}, completions)
+}
+
+func TestRunImplement_UnknownStrategy(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+	resetRootCmd(t)
+	flagConfig = tomlPath
+
+	err := runImplement(newImplementCmd(), implementFlags{
+		Agent:    "claude",
+		PhaseStr: "1",
+		Strategy: "random",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown task selection strategy "random"`)
+}
+
 // ---- runnerLogger adapter tests ---------------------------------------------
 
 // captureLogger is a minimal charmLogger that records calls for assertion.
@@ -62,6 +62,9 @@ type pipelineFlags struct {
 	// SkipPR skips the PR creation stage.
 	SkipPR bool
 
+	// PublishReview posts the last review to the created PR.
+	PublishReview bool
+
 	// Interactive launches the configuration wizard before running.
 	Interactive bool
 
@@ -117,6 +120,9 @@ Exit codes:
   # Skip review and PR stages
   raven pipeline --phase 1 --skip-review --skip-pr
 
+  # Post the review findings as comments on each phase PR
+  raven pipeline --phase all --publish-review
+
   # Preview execution plan without running
   raven pipeline --phase all --dry-run
 
@@ -146,6 +152,7 @@ Exit codes:
 	cmd.Flags().BoolVar(&flags.SkipReview, "skip-review", false, "Skip the review stage")
 	cmd.Flags().BoolVar(&flags.SkipFix, "skip-fix", false, "Skip the fix stage")
 	cmd.Flags().BoolVar(&flags.SkipPR, "skip-pr", false, "Skip the PR creation stage")
+	cmd.Flags().BoolVar(&flags.PublishReview, "publish-review", false, "Post the last review to the created PR as GitHub review comments")
 
 	// Mode flags.
 	cmd.Flags().BoolVar(&flags.Interactive, "interactive", false, "Launch configuration wizard before running")
@@ -177,7 +184,7 @@ func completePipelinePhase(_ *cobra.Command, _ []string, _ string) ([]string, co
 		return []string{"all", "1", "2", "3"}, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	phases, err := task.LoadPhases(resolved.Config.Project.PhasesConf)
+	phases, err := task.LoadPhases(resolved.Config.Project.PhasesConf, resolved.Config.Project.TaskIDPrefix)
 	if err != nil {
 		return []string{"all", "1", "2", "3"}, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -214,7 +221,7 @@ func runPipeline(cmd *cobra.Command, flags pipelineFlags) error {
 	// Step 3: Load phases for validation and wizard.
 	var phases []task.Phase
 	if cfg.Project.PhasesConf != "" {
-		phases, err = task.LoadPhases(cfg.Project.PhasesConf)
+		phases, err = task.LoadPhases(cfg.Project.PhasesConf, cfg.Project.TaskIDPrefix)
 		if err != nil {
 			if !errors.Is(err, os.ErrNotExist) {
 				return fmt.Errorf("loading phases from %q: %w", cfg.Project.PhasesConf, err)
@@ -357,6 +364,7 @@ func runPipeline(cmd *cobra.Command, flags pipelineFlags) error {
 		"skip_review", opts.SkipReview,
 		"skip_fix", opts.SkipFix,
 		"skip_pr", opts.SkipPR,
+		"publish_review", opts.PublishReview,
 	)
 
 	// Step 15: Run the pipeline orchestrator.
@@ -399,7 +407,7 @@ func runPipelineDryRun(cmd *cobra.Command, opts pipeline.PipelineOpts, phases []
 	phasesConf := cfg.Project.PhasesConf
 	if len(phases) == 0 && phasesConf != "" {
 		var err error
-		phases, err = task.LoadPhases(phasesConf)
+		phases, err = task.LoadPhases(phasesConf, cfg.Project.TaskIDPrefix)
 		if err != nil {
 			return fmt.Errorf("loading phases for dry-run: %w", err)
 		}
@@ -445,10 +453,10 @@ func runPipelineDryRun(cmd *cobra.Command, opts pipeline.PipelineOpts, phases []
 
 	phaseDryRunDetails := make([]workflow.PhaseDryRunDetail, len(filtered))
 	for i, ph := range filtered {
-		branchName := branchMgr.ResolveBranchName(ph.ID, ph.Name, projectName)
+		branchName := branchMgr.ResolvePhaseBranchName(ph, projectName)
 		baseBranch := baseBranchName
 		if i > 0 {
-			baseBranch = branchMgr.ResolveBranchName(filtered[i-1].ID, filtered[i-1].Name, projectName)
+			baseBranch = branchMgr.ResolvePhaseBranchName(filtered[i-1], projectName)
 		}
 
 		// Build step-level dry-run details from the workflow definition.
@@ -563,6 +571,9 @@ func printPipelineSummary(cmd *cobra.Command, result *pipeline.PipelineResult) {
 		if ph.Error != "" {
 			line += fmt.Sprintf("  Error: %s", ph.Error)
 		}
+		if ph.PublishError != "" {
+			line += fmt.Sprintf("  Review not published: %s", ph.PublishError)
+		}
 		fmt.Fprintln(out, line)
 	}
 	fmt.Fprintln(out)
@@ -582,6 +593,7 @@ func buildPipelineOpts(flags pipelineFlags) pipeline.PipelineOpts {
 		SkipReview:        flags.SkipReview,
 		SkipFix:           flags.SkipFix,
 		SkipPR:            flags.SkipPR,
+		PublishReview:     flags.PublishReview,
 		DryRun:            flags.DryRun,
 		Interactive:       flags.Interactive,
 		BaseBranch:        flags.Base,
@@ -647,6 +659,11 @@ func validatePipelineFlags(flags pipelineFlags, phases []task.Phase) error {
 		return fmt.Errorf("all pipeline stages are skipped: at least one stage must be active")
 	}
 
+	// --publish-review posts the review to the PR, so it needs both stages.
+	if flags.PublishReview && (flags.SkipReview || flags.SkipPR) {
+		return fmt.Errorf("--publish-review cannot be combined with --skip-review or --skip-pr")
+	}
+
 	// Validate specific phase ID when provided (not "all" and not empty).
 	if flags.Phase != "" && !strings.EqualFold(flags.Phase, "all") {
 		if _, err := strconv.Atoi(flags.Phase); err != nil {
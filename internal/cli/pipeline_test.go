@@ -186,6 +186,21 @@ func TestValidatePipelineFlags_AllSkipped(t *testing.T) {
 	assert.Contains(t, err.Error(), "all pipeline stages are skipped")
 }
 
+func TestValidatePipelineFlags_PublishReviewNeedsReviewAndPR(t *testing.T) {
+	flags := pipelineFlags{
+		Phase:             "1",
+		ReviewConcurrency: 2,
+		MaxReviewCycles:   3,
+		PublishReview:     true,
+	}
+	require.NoError(t, validatePipelineFlags(flags, nil))
+
+	flags.SkipPR = true
+	err := validatePipelineFlags(flags, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--publish-review cannot be combined")
+}
+
 func TestValidatePipelineFlags_InvalidPhaseString(t *testing.T) {
 	flags := pipelineFlags{
 		Phase:             "xyz",
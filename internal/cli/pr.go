@@ -7,14 +7,17 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"sort"
 	"strings"
 	"syscall"
 
 	"github.com/spf13/cobra"
 
 	"github.com/AbdelazizMoustafa10m/Raven/internal/agent"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/config"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/logging"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/review"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
 )
 
 // prFlags holds parsed flag values for the pr command.
@@ -39,6 +42,10 @@ type prFlags struct {
 
 	// NoSummary skips AI summary generation when true.
 	NoSummary bool
+
+	// Phase lists the completed tasks of this phase, with their recorded
+	// metrics, in the PR body. Zero lists no tasks.
+	Phase int
 }
 
 // newPRCmd creates the "raven pr" command.
@@ -52,7 +59,9 @@ func newPRCmd() *cobra.Command {
 
 The PR body is automatically generated from the review pipeline results and an
 optional AI-generated summary. Use --review-report to include a review report
-in the PR body.
+in the PR body. Use --phase to list the phase's completed tasks in the PR body,
+together with the iterations, wall time, tokens and cost "raven implement"
+recorded for each.
 
 Exit codes:
   0 - PR created successfully
@@ -74,6 +83,9 @@ Use --dry-run to preview the PR title and body without creating the PR.`,
   # Include a review report in the PR body
   raven pr --review-report .raven/logs/review-report.md
 
+  # List phase 2's completed tasks and their metrics
+  raven pr --phase 2
+
   # Skip AI summary generation
   raven pr --no-summary
 
@@ -92,6 +104,7 @@ Use --dry-run to preview the PR title and body without creating the PR.`,
 	cmd.Flags().StringArrayVar(&flags.Assignees, "assignee", nil, "GitHub username to assign to the PR (can be repeated)")
 	cmd.Flags().StringVar(&flags.ReviewReport, "review-report", "", "Path to review report to include in PR body")
 	cmd.Flags().BoolVar(&flags.NoSummary, "no-summary", false, "Skip AI summary generation")
+	cmd.Flags().IntVar(&flags.Phase, "phase", 0, "List this phase's completed tasks and their metrics in the PR body")
 
 	return cmd
 }
@@ -111,6 +124,17 @@ func runPR(cmd *cobra.Command, flags prFlags) error {
 	}
 	cfg := resolved.Config
 
+	// Collect the phase's completed tasks before touching git, so that a bad
+	// --phase fails early.
+	tasks := []review.TaskSummary{}
+	phaseName := ""
+	if flags.Phase != 0 {
+		phaseName, tasks, err = phaseTaskSummaries(cfg, flags.Phase)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Step 2: Set up signal context for graceful cancellation.
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
@@ -180,8 +204,20 @@ func runPR(cmd *cobra.Command, flags prFlags) error {
 		reviewReportContent = string(data)
 	}
 
-	// Step 10: Generate AI summary (empty diff, no tasks -- user hasn't specified tasks).
-	summary, err := bodyGen.GenerateSummary(ctx, "", []review.TaskSummary{})
+	// Step 9b: Summarise the branch's finding ledger, if it has been reviewed.
+	var lifecycle *review.FindingLifecycle
+	if branchName != "" {
+		ledger, ledgerErr := review.NewReviewStateStore(defaultReviewStateDir).LoadLedger(branchName)
+		if ledgerErr != nil {
+			// Non-fatal: the PR body omits the lifecycle section.
+			logger.Warn("could not load finding ledger", "error", ledgerErr)
+		} else if ledger.Reviews > 0 {
+			lifecycle = ledger.Lifecycle()
+		}
+	}
+
+	// Step 10: Generate AI summary (empty diff) from the phase's tasks, if any.
+	summary, err := bodyGen.GenerateSummary(ctx, "", tasks)
 	if err != nil {
 		// Non-fatal: proceed with empty summary.
 		logger.Warn("could not generate PR summary", "error", err)
@@ -190,10 +226,13 @@ func runPR(cmd *cobra.Command, flags prFlags) error {
 
 	// Step 11: Build PRBodyData.
 	data := review.PRBodyData{
-		Summary:      summary,
-		ReviewReport: reviewReportContent,
-		BaseBranch:   flags.BaseBranch,
-		BranchName:   branchName,
+		Summary:         summary,
+		TasksCompleted:  tasks,
+		ReviewReport:    reviewReportContent,
+		ReviewLifecycle: lifecycle,
+		BaseBranch:      flags.BaseBranch,
+		BranchName:      branchName,
+		PhaseName:       phaseName,
 	}
 
 	// Step 12: Generate PR title (use flag override if provided).
@@ -270,3 +309,53 @@ func currentGitBranch(ctx context.Context) (string, error) {
 	}
 	return branch, nil
 }
+
+// phaseTaskSummaries returns the name of phase phaseID and its completed
+// tasks in ID order, each with the metrics recorded by the implementation
+// loop.
+func phaseTaskSummaries(cfg *config.Config, phaseID int) (string, []review.TaskSummary, error) {
+	phases, err := task.LoadPhases(cfg.Project.PhasesConf, cfg.Project.TaskIDPrefix)
+	if err != nil {
+		return "", nil, fmt.Errorf("loading phases: %w", err)
+	}
+	phase := task.PhaseByID(phases, phaseID)
+	if phase == nil {
+		return "", nil, fmt.Errorf("phase %d not found", phaseID)
+	}
+
+	specs, err := task.DiscoverTasks(cfg.Project.TasksDir)
+	if err != nil {
+		return "", nil, fmt.Errorf("discovering tasks: %w", err)
+	}
+	sm := task.NewStateManager(cfg.Project.TaskStateFile)
+	stateMap, err := sm.LoadMap()
+	if err != nil {
+		return "", nil, fmt.Errorf("loading task state: %w", err)
+	}
+	metrics, err := sm.Metrics()
+	if err != nil {
+		return "", nil, fmt.Errorf("loading task metrics: %w", err)
+	}
+
+	membership := task.NewPhaseMembership(phases, specs)
+	summaries := []review.TaskSummary{}
+	for _, spec := range specs {
+		if !membership.Contains(phaseID, spec.ID) {
+			continue
+		}
+		if st, ok := stateMap[spec.ID]; !ok || st.Status != task.StatusCompleted {
+			continue
+		}
+		m := metrics[spec.ID]
+		summaries = append(summaries, review.TaskSummary{
+			ID:         spec.ID,
+			Title:      spec.Title,
+			Iterations: m.Iterations,
+			WallTime:   m.WallTime,
+			Tokens:     m.TokensIn + m.TokensOut,
+			CostUSD:    m.CostUSD,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return task.CompareTaskIDs(summaries[i].ID, summaries[j].ID) < 0 })
+	return phase.Name, summaries, nil
+}
@@ -3,12 +3,17 @@ package cli
 import (
 	"bytes"
 	"context"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/review"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
 )
 
 // ---- newPRCmd registration test ---------------------------------------------
@@ -506,3 +511,36 @@ func TestPRFlags_StructZeroValues(t *testing.T) {
 	assert.Equal(t, "", flags.ReviewReport, "zero-value ReviewReport should be empty")
 	assert.False(t, flags.NoSummary, "zero-value NoSummary should be false")
 }
+
+// ---- phaseTaskSummaries tests -----------------------------------------------
+
+func TestPhaseTaskSummaries(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+	sm := task.NewStateManager(filepath.Join(filepath.Dir(tomlPath), "task-state.conf"))
+	require.NoError(t, sm.RecordMetrics(task.TaskMetrics{
+		TaskID: "T-001", Iterations: 3, WallTime: 20 * time.Minute, TokensIn: 1000, TokensOut: 200, CostUSD: 0.9,
+	}))
+
+	resetRootCmd(t)
+	flagConfig = tomlPath
+	t.Cleanup(func() { flagConfig = "" })
+	resolved, _, err := loadAndResolveConfig()
+	require.NoError(t, err)
+
+	name, tasks, err := phaseTaskSummaries(resolved.Config, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Foundation", name)
+	// T-002 is blocked, so only T-001 is listed.
+	assert.Equal(t, []review.TaskSummary{{
+		ID: "T-001", Title: "Setup", Iterations: 3, WallTime: 20 * time.Minute, Tokens: 1200, CostUSD: 0.9,
+	}}, tasks)
+
+	name, tasks, err = phaseTaskSummaries(resolved.Config, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Interface", name)
+	assert.Empty(t, tasks)
+
+	_, _, err = phaseTaskSummaries(resolved.Config, 9)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "phase 9 not found")
+}
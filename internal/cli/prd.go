@@ -18,6 +18,7 @@ import (
 	"github.com/AbdelazizMoustafa10m/Raven/internal/config"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/logging"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/prd"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
 )
 
 // prdFlags holds parsed flag values for the prd command.
@@ -40,6 +41,8 @@ type prdFlags struct {
 	Force bool
 	// StartID is the starting task number for global ID assignment (default: 1).
 	StartID int
+	// IDPrefix is the task ID prefix (default: project.task_id_prefix, or "T").
+	IDPrefix string
 }
 
 // prdPipeline orchestrates the full PRD decomposition pipeline.
@@ -56,6 +59,7 @@ type prdPipeline struct {
 	dryRun      bool
 	force       bool
 	startID     int
+	idPrefix    string
 }
 
 // errPartialSuccess signals that the pipeline completed but some epics failed.
@@ -107,7 +111,10 @@ Use --dry-run to preview planned steps without invoking the agent.`,
   raven prd --file docs/prd/PRD.md --force
 
   # Start task numbering at T-050
-  raven prd --file docs/prd/PRD.md --start-id 50`,
+  raven prd --file docs/prd/PRD.md --start-id 50
+
+  # Number a second PRD as API-001, API-002, ...
+  raven prd --file docs/prd/API.md --id-prefix API`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runPRD(cmd, flags)
@@ -127,6 +134,7 @@ Use --dry-run to preview planned steps without invoking the agent.`,
 	cmd.Flags().BoolVar(&flags.DryRun, "dry-run", false, "Show planned steps without executing")
 	cmd.Flags().BoolVar(&flags.Force, "force", false, "Overwrite existing output files")
 	cmd.Flags().IntVar(&flags.StartID, "start-id", 1, "Starting task number for ID assignment (e.g. 50 -> T-050)")
+	cmd.Flags().StringVar(&flags.IDPrefix, "id-prefix", "", "Task ID prefix (e.g. API -> API-001; default: from config, or \"T\")")
 
 	// Shell completion for --agent.
 	_ = cmd.RegisterFlagCompletionFunc("agent", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -197,6 +205,18 @@ func runPRD(cmd *cobra.Command, flags prdFlags) error { //nolint:unparam // cmd
 		outputDir = "docs/tasks"
 	}
 
+	// Step 6b: Resolve task ID prefix (flag > config.Project.TaskIDPrefix > "T").
+	idPrefix := flags.IDPrefix
+	if idPrefix == "" {
+		idPrefix = cfg.Project.TaskIDPrefix
+	}
+	if idPrefix == "" {
+		idPrefix = task.DefaultTaskIDPrefix
+	}
+	if err := task.ValidateTaskIDPrefix(idPrefix); err != nil {
+		return err
+	}
+
 	// Step 7: Create temp working directory for intermediate files.
 	workDir, err := os.MkdirTemp("", "raven-prd-*")
 	if err != nil {
@@ -229,6 +249,7 @@ func runPRD(cmd *cobra.Command, flags prdFlags) error { //nolint:unparam // cmd
 		dryRun:      dryRun,
 		force:       flags.Force,
 		startID:     flags.StartID,
+		idPrefix:    idPrefix,
 	}
 
 	// Step 9: Dry-run: print planned steps and return.
@@ -254,6 +275,7 @@ func runPRD(cmd *cobra.Command, flags prdFlags) error { //nolint:unparam // cmd
 		"singlePass", singlePass,
 		"force", flags.Force,
 		"startID", flags.StartID,
+		"idPrefix", idPrefix,
 	)
 
 	// Step 11: Run the pipeline.
@@ -486,7 +508,7 @@ func (p *prdPipeline) runConcurrent(ctx context.Context) error {
 	}
 
 	// 3b: Assign global IDs.
-	mergedTasks, idMapping := prd.AssignGlobalIDs(epicOrder, resultsMap)
+	mergedTasks, idMapping := prd.AssignGlobalIDsWithPrefix(epicOrder, resultsMap, p.idPrefix)
 	p.logger.Debug("assigned global IDs", "tasks", len(mergedTasks))
 
 	// 3c: Build per-epic task map for cross-epic dependency resolution.
@@ -564,6 +586,7 @@ func (p *prdPipeline) runConcurrent(ctx context.Context) error {
 		Validation: dagValidation,
 		Epics:      shredResult.Breakdown,
 		StartID:    p.startID,
+		IDPrefix:   p.idPrefix,
 	})
 	if err != nil {
 		return fmt.Errorf("phase 4 (emit): %w", err)
@@ -600,6 +623,14 @@ func (p *prdPipeline) runSinglePass(ctx context.Context) error {
 	return p.runConcurrent(ctx)
 }
 
+// taskIDPrefix returns the configured task ID prefix, or the default "T".
+func (p *prdPipeline) taskIDPrefix() string {
+	if p.idPrefix == "" {
+		return task.DefaultTaskIDPrefix
+	}
+	return p.idPrefix
+}
+
 // printDryRun shows the planned pipeline steps without executing them.
 func (p *prdPipeline) printDryRun() error { //nolint:unparam // error return reserved for future use
 	stderr := os.Stderr
@@ -618,7 +649,7 @@ func (p *prdPipeline) printDryRun() error { //nolint:unparam // error return res
 	} else {
 		fmt.Fprintln(stderr, "Mode:         sequential")
 	}
-	fmt.Fprintf(stderr, "Start ID:     T-%03d\n", p.startID)
+	fmt.Fprintf(stderr, "Start ID:     %s\n", task.FormatTaskID(p.taskIDPrefix(), p.startID, task.MinTaskIDDigits))
 	fmt.Fprintf(stderr, "Force:        %v\n", p.force)
 	fmt.Fprintln(stderr, "")
 	fmt.Fprintln(stderr, "Pipeline steps:")
@@ -628,7 +659,7 @@ func (p *prdPipeline) printDryRun() error { //nolint:unparam // error return res
 	fmt.Fprintln(stderr, "                     -> epic-<ID>.json per epic")
 	fmt.Fprintln(stderr, "  Phase 3 (Merge):   Assign global IDs, remap deps, dedup, validate DAG")
 	fmt.Fprintln(stderr, "  Phase 4 (Emit):    Write output files:")
-	fmt.Fprintf(stderr, "                     -> %s/T-NNN-slug.md (one per task)\n", p.outputDir)
+	fmt.Fprintf(stderr, "                     -> %s/%s-NNN-slug.md (one per task)\n", p.outputDir, p.taskIDPrefix())
 	fmt.Fprintf(stderr, "                     -> %s/task-state.conf\n", p.outputDir)
 	fmt.Fprintf(stderr, "                     -> %s/phases.conf\n", p.outputDir)
 	fmt.Fprintf(stderr, "                     -> %s/PROGRESS.md\n", p.outputDir)
@@ -659,7 +690,7 @@ func (p *prdPipeline) printSummary(
 	fmt.Fprintf(stderr, "Total phases:      %d\n", result.TotalPhases)
 	fmt.Fprintln(stderr, "")
 	fmt.Fprintln(stderr, "Files generated:")
-	fmt.Fprintf(stderr, "  Task specs:      %d files (T-XXX-slug.md)\n", len(result.TaskFiles))
+	fmt.Fprintf(stderr, "  Task specs:      %d files (%s-NNN-slug.md)\n", len(result.TaskFiles), p.taskIDPrefix())
 	fmt.Fprintf(stderr, "  Task state:      %s\n", result.TaskStateFile)
 	fmt.Fprintf(stderr, "  Phases:          %s\n", result.PhasesFile)
 	fmt.Fprintf(stderr, "  Progress:        %s\n", result.ProgressFile)
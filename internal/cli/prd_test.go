@@ -555,6 +555,19 @@ func TestPrdFlags_StartIDDefault(t *testing.T) {
 	assert.Equal(t, "1", startIDFlag.DefValue, "start-id flag should default to 1")
 }
 
+func TestPrdFlags_IDPrefixDefault(t *testing.T) {
+	// Validate that id-prefix defaults to empty (resolved from config).
+	cmd := NewPRDCmd()
+	idPrefixFlag := cmd.Flags().Lookup("id-prefix")
+	require.NotNil(t, idPrefixFlag)
+	assert.Equal(t, "", idPrefixFlag.DefValue, "id-prefix flag should default to empty")
+}
+
+func TestPrdPipeline_TaskIDPrefix(t *testing.T) {
+	assert.Equal(t, "T", (&prdPipeline{}).taskIDPrefix())
+	assert.Equal(t, "API", (&prdPipeline{idPrefix: "API"}).taskIDPrefix())
+}
+
 func TestPrdFlags_ConcurrencyDefault(t *testing.T) {
 	// Validate that concurrency defaults to 3.
 	cmd := NewPRDCmd()
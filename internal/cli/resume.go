@@ -67,7 +67,8 @@ automatically.
 
 The state directory defaults to .raven/state/ relative to the current working
 directory. Checkpoints are written there by the workflow engine after every
-step transition.`,
+step transition, and by the implementation loop after every iteration, so an
+interrupted implementation step continues from the task it was working on.`,
 		Example: `  # List all resumable workflow runs
   raven resume --list
 
@@ -253,6 +254,12 @@ func runResumeMode(ctx context.Context, cmd *cobra.Command, store *workflow.Stat
 			state.WorkflowName, state.ID, state.CurrentStep)
 		fmt.Fprintf(cmd.ErrOrStderr(), "  Steps completed: %d\n", len(state.StepHistory))
 		fmt.Fprintf(cmd.ErrOrStderr(), "  Last updated:    %s\n", state.UpdatedAt.Format(time.RFC3339))
+		if cp := workflow.LoopCheckpointFromState(state); cp != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "  Loop iteration:  %d (rate-limit waits: %d)\n", cp.Iteration, cp.RateLimitWaits)
+			if cp.CurrentTask != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "  Interrupted on:  %s\n", cp.CurrentTask)
+			}
+		}
 		return nil
 	}
 
@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"os/signal"
+	"slices"
+	"sort"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 
 	"github.com/AbdelazizMoustafa10m/Raven/internal/config"
@@ -35,8 +40,58 @@ type reviewFlags struct {
 	// Output is an optional file path to write the report to.
 	// When empty, the report is written to stdout.
 	Output string
+
+	// Format is the report format: markdown, json, sarif, or
+	// github-annotations.
+	Format string
+
+	// Incremental reviews only the commits made since the last review of the
+	// current branch and re-checks the findings that were still open.
+	Incremental bool
+
+	// Personas is a comma-separated subset of the [review.personas.*]
+	// reviewers to run. When empty, all configured personas run.
+	Personas string
+
+	// NoAnalyzers skips the [review.analyzers.*] static-analysis commands.
+	NoAnalyzers bool
+
+	// UpdateBaseline waives all findings of the review in the baseline file,
+	// accepting the current state of the code.
+	UpdateBaseline bool
+
+	// Staged reviews the changes staged for the next commit.
+	Staged bool
+
+	// Worktree reviews all uncommitted changes to tracked files.
+	Worktree bool
+
+	// Range reviews an explicit commit range, "A..B" or "A...B".
+	Range string
+
+	// Commit reviews a single commit.
+	Commit string
+
+	// PR reviews a GitHub pull request by number, fetched through gh.
+	PR int
+
+	// Publish posts the review to the pull request given by --pr, or else to
+	// the pull request of the current branch.
+	Publish bool
 }
 
+// reviewRemote is the git remote pull requests reviewed with --pr are
+// fetched from.
+const reviewRemote = "origin"
+
+// defaultReviewStateDir is where the last review of each branch is recorded
+// for incremental reviews.
+const defaultReviewStateDir = ".raven/review"
+
+// defaultReviewBaselineFile holds the waivers of accepted findings. It is
+// meant to be committed so that the whole team shares it.
+const defaultReviewBaselineFile = ".raven/review-baseline.json"
+
 // newReviewCmd creates the "raven review" command.
 func newReviewCmd() *cobra.Command {
 	var flags reviewFlags
@@ -45,11 +100,57 @@ func newReviewCmd() *cobra.Command {
 		Use:   "review",
 		Short: "Run multi-agent parallel code review",
 		Long: `Run a code review pipeline that fans out review requests to multiple AI agents
-concurrently, consolidates the findings, and outputs a structured markdown report.
+concurrently, consolidates the findings, and outputs a structured report.
+
+The report is markdown by default. --format json writes the consolidated
+review as JSON, --format sarif writes a SARIF 2.1.0 log for code-scanning
+UIs, and --format github-annotations writes GitHub Actions workflow commands
+that annotate the changed lines in CI.
 
 In "all" mode (default), every agent receives the full diff. In "split" mode,
 files are partitioned across agents so each reviews a non-overlapping subset.
 
+When raven.toml defines [review.personas.<name>] sections, each persona is a
+specialised reviewer (e.g. security, performance, tests, API design) that runs
+its agent and model with its own prompt and rule files on the changed files
+matching its path globs, and the report groups findings by persona. --personas
+runs a subset of them; --agents runs a plain review with the named agents
+instead.
+
+[review.analyzers.<name>] sections run local static-analysis commands (go vet,
+golangci-lint, staticcheck, ...) before the agents. Their SARIF, golangci-lint
+JSON, or "file:line:col: message" output is converted into findings attributed
+to "analyzer:<name>" and consolidated with the agents' findings; only findings
+on changed files are kept. Analyzers with context = true also list their
+findings in the agents' prompts. --no-analyzers skips them.
+
+Every completed review records the reviewed HEAD and its open findings under
+.raven/review. With --incremental, only the commits made since the last review
+of the current branch are reviewed. Open findings from that review are listed
+in the prompt for re-checking: findings on files the new commits touched are
+resolved unless an agent reports them again, and findings on untouched files
+are carried forward. Without a usable previous review (first review, or the
+reviewed commit was rebased away) the full diff is reviewed.
+
+By default the changes on the current branch since it diverged from --base are
+reviewed. --staged reviews the changes staged for the next commit, --worktree
+all uncommitted changes to tracked files, --range an explicit commit range,
+--commit a single commit, and --pr a GitHub pull request, whose base branch and
+head are fetched from origin through gh without checking it out. Reviews of
+these targets are not recorded for --incremental.
+
+--publish posts the review to the pull request given by --pr, or else to the
+pull request of the current branch. Findings on changed lines become inline
+comments, the others are listed in the review summary, and the review
+approves, comments, or requests changes for an APPROVED, CHANGES_NEEDED, or
+BLOCKING verdict. Comments from an earlier --publish are updated in place.
+
+Findings waived in .raven/review-baseline.json are listed in a "Waived
+Findings" section and do not count toward the verdict. Each finding in the
+report has a short ID; "raven review waive <id> --reason ..." waives one
+finding of the last review, and --update-baseline waives every finding of this
+review, e.g. when adopting Raven on an existing codebase. Waivers can expire.
+
 The exit code encodes the review verdict:
   0 - APPROVED: no blocking issues found
   1 - Error during review execution
@@ -71,6 +172,34 @@ Use --dry-run to preview the planned review actions without invoking any agent.`
   # Write report to file
   raven review --output review-report.md
 
+  # Review only the commits made since the last review
+  raven review --incremental
+
+  # Review staged changes before committing
+  raven review --staged
+
+  # Review a teammate's pull request and post the findings as review comments
+  raven review --pr 123 --publish
+
+  # Review a single commit or a range of commits
+  raven review --commit 3f2a9c0
+  raven review --range v1.2.0..v1.3.0
+
+  # Run only the security and tests personas
+  raven review --personas security,tests
+
+  # Review without running the configured static analyzers
+  raven review --no-analyzers
+
+  # Accept all current findings as the baseline
+  raven review --update-baseline
+
+  # Upload findings to GitHub code scanning
+  raven review --format sarif --output review.sarif
+
+  # Annotate the diff in a GitHub Actions job
+  raven review --format github-annotations
+
   # Dry-run: show plan without invoking agents
   raven review --dry-run`,
 		Args: cobra.NoArgs,
@@ -85,12 +214,34 @@ Use --dry-run to preview the planned review actions without invoking any agent.`
 	cmd.Flags().StringVar(&flags.Mode, "mode", "all", `Review mode: "all" (full diff to every agent) or "split" (partition files across agents)`)
 	cmd.Flags().StringVar(&flags.BaseBranch, "base", "main", "Base branch for diff")
 	cmd.Flags().StringVar(&flags.Output, "output", "", "Write report to file instead of stdout")
+	cmd.Flags().StringVar(&flags.Format, "format", "markdown", "Report format: markdown, json, sarif, or github-annotations")
+	cmd.Flags().BoolVar(&flags.Incremental, "incremental", false, "Review only the commits since the last review of this branch")
+	cmd.Flags().StringVar(&flags.Personas, "personas", "", "Comma-separated subset of the configured review personas to run (default: all)")
+	cmd.Flags().BoolVar(&flags.NoAnalyzers, "no-analyzers", false, "Skip the static analyzers configured in [review.analyzers.*]")
+	cmd.Flags().BoolVar(&flags.UpdateBaseline, "update-baseline", false, "Waive all findings of this review in "+defaultReviewBaselineFile)
+	cmd.Flags().BoolVar(&flags.Staged, "staged", false, "Review the staged changes instead of the branch")
+	cmd.Flags().BoolVar(&flags.Worktree, "worktree", false, "Review all uncommitted changes instead of the branch")
+	cmd.Flags().StringVar(&flags.Range, "range", "", "Review a commit range (A..B or A...B) instead of the branch")
+	cmd.Flags().StringVar(&flags.Commit, "commit", "", "Review a single commit instead of the branch")
+	cmd.Flags().IntVar(&flags.PR, "pr", 0, "Review a GitHub pull request by number (requires gh)")
+	cmd.Flags().BoolVar(&flags.Publish, "publish", false, "Post the review to the pull request as GitHub review comments (requires gh)")
+
+	cmd.AddCommand(newReviewWaiveCmd())
 
 	// Shell completion for --mode: provide the two valid mode values.
 	_ = cmd.RegisterFlagCompletionFunc("mode", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"all", "split"}, cobra.ShellCompDirectiveNoFileComp
 	})
 
+	// Shell completion for --format: list the report formats.
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		formats := make([]string, len(review.ReportFormats))
+		for i, f := range review.ReportFormats {
+			formats[i] = string(f)
+		}
+		return formats, cobra.ShellCompDirectiveNoFileComp
+	})
+
 	// Shell completion for --agents: list the known agent names.
 	_ = cmd.RegisterFlagCompletionFunc("agents", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"claude", "codex", "gemini"}, cobra.ShellCompDirectiveNoFileComp
@@ -114,6 +265,14 @@ func runReview(cmd *cobra.Command, flags reviewFlags) error {
 	if err != nil {
 		return err
 	}
+	format, err := review.ParseReportFormat(flags.Format)
+	if err != nil {
+		return fmt.Errorf("invalid --format: %w", err)
+	}
+	target, err := resolveReviewTarget(flags)
+	if err != nil {
+		return err
+	}
 
 	// Step 2: Load and resolve configuration.
 	resolved, _, err := loadAndResolveConfig()
@@ -122,15 +281,36 @@ func runReview(cmd *cobra.Command, flags reviewFlags) error {
 	}
 	cfg := resolved.Config
 
-	// Step 3: Resolve the agent list from --agents flag or config.
-	agents, err := resolveReviewAgents(flags.Agents, *cfg)
+	// Step 3: Resolve the reviewer personas, or the agent list from --agents
+	// flag or config when no personas are in use.
+	personas, err := resolveReviewPersonas(flags, cfg.Review.Personas)
 	if err != nil {
 		return err
 	}
+	var agents []string
+	if len(personas) > 0 {
+		for _, p := range personas {
+			if !slices.Contains(agents, p.Agent) {
+				agents = append(agents, p.Agent)
+			}
+		}
+	} else {
+		agents, err = resolveReviewAgents(flags.Agents, *cfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	var analyzers []review.Analyzer
+	if !flags.NoAnalyzers {
+		analyzers = configToAnalyzers(cfg.Review.Analyzers)
+	}
 
 	if flagVerbose {
 		logger.Info("review configuration",
 			"agents", agents,
+			"personas", len(personas),
+			"analyzers", len(analyzers),
 			"mode", reviewMode,
 			"base_branch", flags.BaseBranch,
 			"concurrency", flags.Concurrency,
@@ -175,7 +355,17 @@ func runReview(cmd *cobra.Command, flags reviewFlags) error {
 	}
 
 	promptBuilder := review.NewPromptBuilder(reviewCfg, logger)
-	consolidator := review.NewConsolidator(logger)
+	consolidator := newReviewConsolidator(reviewCfg, logger)
+	baseline, err := review.LoadBaseline(defaultReviewBaselineFile)
+	if err != nil {
+		return fmt.Errorf("loading review baseline: %w", err)
+	}
+	consolidator.WithBaseline(baseline)
+	policy := configToVerdictPolicy(cfg.Review.Policy)
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	consolidator.WithPolicy(policy)
 
 	// Step 9: Build review opts.
 	// The global --dry-run flag (flagDryRun) is honoured alongside any command-level dry-run state.
@@ -185,6 +375,9 @@ func runReview(cmd *cobra.Command, flags reviewFlags) error {
 		Concurrency: flags.Concurrency,
 		Mode:        reviewMode,
 		BaseBranch:  flags.BaseBranch,
+		Personas:    personas,
+		Analyzers:   analyzers,
+		Target:      target,
 		DryRun:      dryRun,
 	}
 
@@ -192,6 +385,50 @@ func runReview(cmd *cobra.Command, flags reviewFlags) error {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	// Step 10a: Fetch the pull request to review with --pr.
+	if flags.PR > 0 {
+		pr, prErr := review.FetchPullRequest(ctx, review.NewCLIRunner(""), gitClient, reviewRemote, flags.PR)
+		if prErr != nil {
+			return prErr
+		}
+		logger.Info("reviewing pull request", "number", pr.Number, "title", pr.Title, "base", pr.BaseRefName)
+		opts.Target = pr.Target(reviewRemote)
+	}
+
+	// Step 10b: Resolve the review state of the current branch. An incremental
+	// review diffs from the last reviewed HEAD and re-checks its open findings.
+	stateStore := review.NewReviewStateStore(defaultReviewStateDir)
+	branch, headSHA := currentReviewHead(ctx, gitClient, logger)
+	if flags.Incremental {
+		prior, planErr := planIncrementalReview(ctx, gitClient, stateStore, branch, headSHA, logger)
+		if planErr != nil {
+			return planErr
+		}
+		if prior != nil && prior.HeadSHA == headSHA {
+			// Nothing to review: report the findings still open from the
+			// last review, so that --format and --output behave as usual.
+			fmt.Fprintf(cmd.ErrOrStderr(), "No new commits since the last review (%s); %d finding(s) still open.\n",
+				shortSHA(prior.HeadSHA), len(prior.Findings))
+			if dryRun {
+				return nil
+			}
+			consolidated, stats, diffResult := carriedReview(prior)
+			report, renderErr := review.NewReportGenerator(logger).Render(format, consolidated, stats, diffResult)
+			if renderErr != nil {
+				return fmt.Errorf("generating review report: %w", renderErr)
+			}
+			if writeErr := writeReviewReport(cmd, flags.Output, report, logger); writeErr != nil {
+				return writeErr
+			}
+			return exitForVerdict(prior.Verdict)
+		}
+		if prior != nil {
+			opts.Since = prior.HeadSHA
+			promptBuilder.WithPriorFindings(prior.Findings)
+			consolidator.WithCarriedFindings(prior.Findings)
+		}
+	}
+
 	// Step 11: Construct the orchestrator.
 	// Pass nil for the events channel -- the CLI uses the logger directly.
 	orchestrator := review.NewReviewOrchestrator(
@@ -202,7 +439,7 @@ func runReview(cmd *cobra.Command, flags reviewFlags) error {
 		flags.Concurrency,
 		logger,
 		nil, // events channel (nil = no event fan-out in CLI mode)
-	)
+	).WithTokenBudget(cfg.Review.TokenBudget, cfg.Review.TokenBudgets)
 
 	// Step 12: Handle dry-run mode -- print the plan and exit 0.
 	if dryRun {
@@ -235,9 +472,14 @@ func runReview(cmd *cobra.Command, flags reviewFlags) error {
 		return fmt.Errorf("running review pipeline: %w", err)
 	}
 
-	// Step 14: Handle empty diff -- no changed files to review.
-	if result.DiffResult != nil && len(result.DiffResult.Files) == 0 {
-		fmt.Fprintln(cmd.OutOrStdout(), "No changes to review (empty diff against base branch).")
+	// Step 14: Handle empty diff -- no changed files to review. Machine-readable
+	// formats still get an (empty) report so that consumers can parse stdout.
+	if result.DiffResult != nil && len(result.DiffResult.Files) == 0 && format == review.FormatMarkdown {
+		if opts.Target.Kind != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "No changes to review (empty diff of %s).\n", opts.Target)
+		} else {
+			fmt.Fprintln(cmd.OutOrStdout(), "No changes to review (empty diff against base branch).")
+		}
 		return nil
 	}
 
@@ -249,31 +491,162 @@ func runReview(cmd *cobra.Command, flags reviewFlags) error {
 		)
 	}
 
-	// Step 16: Generate the markdown report.
+	// Step 15b: Compare the findings with the earlier reviews of the branch
+	// in its finding ledger. Like the review state, the ledger only records
+	// complete reviews of the current branch: a failed agent's missing
+	// findings must not count as resolved.
+	var ledger *review.FindingLedger
+	if opts.Target.Kind == "" && branch != "" && headSHA != "" && len(result.AgentErrors) == 0 {
+		loaded, ledgerErr := stateStore.LoadLedger(branch)
+		if ledgerErr != nil {
+			logger.Warn("finding lifecycle not tracked", "error", ledgerErr)
+		} else {
+			ledger = loaded
+			lc := ledger.Record(result.Consolidated, time.Now())
+			logger.Info("finding lifecycle",
+				"review", lc.Review,
+				"new", lc.New,
+				"open", lc.Open,
+				"resolved", lc.Resolved,
+				"regressed", lc.Regressed,
+			)
+		}
+	}
+
+	// Step 16: Generate the report in the requested format.
 	reportGen := review.NewReportGenerator(logger)
-	report, err := reportGen.Generate(result.Consolidated, result.Stats, result.DiffResult)
+	report, err := reportGen.Render(format, result.Consolidated, result.Stats, result.DiffResult)
 	if err != nil {
 		return fmt.Errorf("generating review report: %w", err)
 	}
 
 	// Step 17: Write report to file or stdout.
-	if flags.Output != "" {
-		if writeErr := os.WriteFile(flags.Output, []byte(report), 0o600); writeErr != nil {
-			return fmt.Errorf("writing report to %q: %w", flags.Output, writeErr)
-		}
-		logger.Info("report written", "path", flags.Output)
-	} else {
-		fmt.Fprint(cmd.OutOrStdout(), report)
+	if err := writeReviewReport(cmd, flags.Output, report, logger); err != nil {
+		return err
 	}
 
-	// Step 18: Map verdict to exit code.
+	// Step 18: Record the reviewed HEAD and open findings for the next
+	// incremental review. A review with failed agents is not recorded, so the
+	// commits they missed are reviewed again next time.
 	verdict := result.Consolidated.Verdict
+	switch {
+	case opts.Target.Kind != "":
+		logger.Debug("review state not recorded: not a review of the current branch", "target", opts.Target)
+	case branch == "" || headSHA == "":
+		logger.Debug("review state not recorded: no current branch")
+	case len(result.AgentErrors) > 0:
+		logger.Warn("review state not recorded: some agents failed")
+	default:
+		state := &review.ReviewState{
+			Branch:     branch,
+			BaseBranch: flags.BaseBranch,
+			HeadSHA:    headSHA,
+			ReviewedAt: time.Now().UTC(),
+			Verdict:    verdict,
+			Findings:   result.Consolidated.Findings,
+		}
+		if saveErr := stateStore.Save(state); saveErr != nil {
+			logger.Warn("failed to record review state", "error", saveErr)
+		}
+		if ledger != nil {
+			if saveErr := stateStore.SaveLedger(ledger); saveErr != nil {
+				logger.Warn("failed to record finding ledger", "error", saveErr)
+			}
+		}
+	}
+
 	logger.Info("review complete",
 		"verdict", verdict,
 		"findings", len(result.Consolidated.Findings),
+		"waived", len(result.Consolidated.Waived),
 		"duration", result.Duration,
 	)
 
+	// Step 18b: With --publish, post the review to the pull request.
+	if flags.Publish {
+		publisher := review.NewReviewPublisher(review.NewCLIRunner(""), logger)
+		if flags.PR == 0 {
+			// The review diffed the local branch, so it may only be posted
+			// to a pull request with the same head commit and base.
+			if headSHA == "" {
+				return fmt.Errorf("publishing review: cannot resolve the reviewed HEAD commit")
+			}
+			publisher.WithReviewedHead(headSHA, strings.TrimPrefix(flags.BaseBranch, reviewRemote+"/"))
+		}
+		published, pubErr := publisher.Publish(ctx, flags.PR, result.Consolidated)
+		if pubErr != nil {
+			return fmt.Errorf("publishing review: %w", pubErr)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(),
+			"Review published to %s (%s): %d new inline, %d updated, %d resolved, %d in summary.\n",
+			published.URL, published.Event, published.Inline, published.Updated, published.Resolved, published.Summary,
+		)
+	}
+
+	// Step 19: With --update-baseline, waive the remaining findings. The
+	// findings are accepted from then on, so the command succeeds. A review
+	// with failed agents is incomplete and must not become the baseline.
+	if flags.UpdateBaseline {
+		if len(result.AgentErrors) > 0 {
+			return fmt.Errorf("baseline not updated: %d agent(s) failed", len(result.AgentErrors))
+		}
+		author, _ := gitClient.UserName(ctx)
+		added := updateReviewBaseline(baseline, result.Consolidated.Findings, author, time.Now())
+		if saveErr := baseline.Save(defaultReviewBaselineFile); saveErr != nil {
+			return fmt.Errorf("updating review baseline: %w", saveErr)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Baseline updated: %d finding(s) waived in %s.\n", added, defaultReviewBaselineFile)
+		return nil
+	}
+
+	// Step 20: Map verdict to exit code.
+	return exitForVerdict(verdict)
+}
+
+// writeReviewReport writes report to output, or to stdout when output is
+// empty.
+func writeReviewReport(cmd *cobra.Command, output, report string, logger *log.Logger) error {
+	if output == "" {
+		fmt.Fprint(cmd.OutOrStdout(), report)
+		return nil
+	}
+	if err := os.WriteFile(output, []byte(report), 0o600); err != nil {
+		return fmt.Errorf("writing report to %q: %w", output, err)
+	}
+	logger.Info("report written", "path", output)
+	return nil
+}
+
+// carriedReview rebuilds the review of a branch without new commits from its
+// recorded state: the findings still open, the recorded verdict, and an empty
+// diff since the reviewed HEAD.
+func carriedReview(prior *review.ReviewState) (*review.ConsolidatedReview, *review.ConsolidationStats, *review.DiffResult) {
+	consolidated := &review.ConsolidatedReview{
+		Findings: prior.Findings,
+		Verdict:  prior.Verdict,
+	}
+	stats := &review.ConsolidationStats{
+		UniqueFindings:      len(prior.Findings),
+		FindingsPerAgent:    make(map[string]int),
+		FindingsPerPersona:  make(map[string]int),
+		FindingsPerSeverity: make(map[review.Severity]int),
+		FindingsPerLocation: make(map[review.FindingLocation]int),
+		InvalidPerAgent:     make(map[string]int),
+		HallucinationRate:   make(map[string]float64),
+	}
+	for _, f := range prior.Findings {
+		stats.FindingsPerSeverity[f.Severity]++
+	}
+	diffResult := &review.DiffResult{
+		BaseBranch: prior.BaseBranch,
+		Since:      prior.HeadSHA,
+	}
+	return consolidated, stats, diffResult
+}
+
+// exitForVerdict maps a review verdict to the command's exit code: nil (exit
+// 0) for APPROVED, and exit code 2 for CHANGES_NEEDED or BLOCKING.
+func exitForVerdict(verdict review.Verdict) error {
 	switch verdict {
 	case review.VerdictApproved:
 		// Exit code 0: review passed with no issues.
@@ -288,6 +661,73 @@ func runReview(cmd *cobra.Command, flags reviewFlags) error {
 	}
 }
 
+// currentReviewHead returns the current branch and the full SHA of HEAD, the
+// key and commit under which a review is recorded. Both are empty when they
+// cannot be determined, e.g. on a detached HEAD; the review then runs but is
+// not recorded.
+func currentReviewHead(ctx context.Context, gitClient *git.GitClient, logger *log.Logger) (string, string) {
+	branch, err := gitClient.CurrentBranch(ctx)
+	if err != nil {
+		logger.Debug("cannot determine current branch", "error", err)
+		return "", ""
+	}
+	headSHA, err := gitClient.ResolveCommit(ctx, "HEAD")
+	if err != nil {
+		logger.Debug("cannot resolve HEAD", "error", err)
+		return "", ""
+	}
+	return branch, headSHA
+}
+
+// planIncrementalReview loads the last recorded review of branch. It returns
+// nil, meaning a full review, when there is no usable previous review: the
+// branch is unknown, has not been reviewed, or its reviewed commit is no
+// longer an ancestor of HEAD after a rebase or force-push.
+func planIncrementalReview(
+	ctx context.Context,
+	gitClient *git.GitClient,
+	store *review.ReviewStateStore,
+	branch, headSHA string,
+	logger *log.Logger,
+) (*review.ReviewState, error) {
+	if branch == "" || headSHA == "" {
+		logger.Warn("incremental review needs a checked-out branch; reviewing the full diff")
+		return nil, nil
+	}
+	state, err := store.Load(branch)
+	if err != nil {
+		return nil, fmt.Errorf("loading review state: %w", err)
+	}
+	if state == nil || state.HeadSHA == "" {
+		logger.Info("no previous review of this branch; reviewing the full diff", "branch", branch)
+		return nil, nil
+	}
+	if state.HeadSHA == headSHA {
+		return state, nil
+	}
+	ok, err := gitClient.IsAncestor(ctx, state.HeadSHA, "HEAD")
+	if err != nil || !ok {
+		logger.Warn("last reviewed commit is not an ancestor of HEAD; reviewing the full diff",
+			"commit", shortSHA(state.HeadSHA),
+		)
+		return nil, nil
+	}
+	logger.Info("incremental review",
+		"branch", branch,
+		"since", shortSHA(state.HeadSHA),
+		"open_findings", len(state.Findings),
+	)
+	return state, nil
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
 // validateReviewFlags validates the review command flags and returns the parsed
 // ReviewMode. Returns an error if the mode value is not a recognised option.
 func validateReviewFlags(flags reviewFlags) (review.ReviewMode, error) {
@@ -301,6 +741,51 @@ func validateReviewFlags(flags reviewFlags) (review.ReviewMode, error) {
 	}
 }
 
+// resolveReviewTarget returns the diff target selected by --staged,
+// --worktree, --range, or --commit. At most one target flag, including --pr,
+// may be given, none with --incremental, and only --pr with --publish. The
+// zero DiffTarget means a review of the current branch against --base; --pr
+// is resolved later, once the pull request has been fetched.
+func resolveReviewTarget(flags reviewFlags) (git.DiffTarget, error) {
+	var set []string
+	var target git.DiffTarget
+	if flags.Staged {
+		set = append(set, "--staged")
+		target = git.DiffTarget{Kind: git.TargetStaged}
+	}
+	if flags.Worktree {
+		set = append(set, "--worktree")
+		target = git.DiffTarget{Kind: git.TargetWorktree}
+	}
+	if flags.Range != "" {
+		set = append(set, "--range")
+		target = git.DiffTarget{Kind: git.TargetRange, Ref: strings.TrimSpace(flags.Range)}
+	}
+	if flags.Commit != "" {
+		set = append(set, "--commit")
+		target = git.DiffTarget{Kind: git.TargetCommit, Ref: strings.TrimSpace(flags.Commit)}
+	}
+	if flags.PR != 0 {
+		if flags.PR < 0 {
+			return git.DiffTarget{}, fmt.Errorf("invalid --pr %d: must be a pull request number", flags.PR)
+		}
+		set = append(set, "--pr")
+	}
+
+	if len(set) > 1 {
+		return git.DiffTarget{}, fmt.Errorf("%s cannot be combined; choose one review target", strings.Join(set, " and "))
+	}
+	if len(set) == 1 && flags.Incremental {
+		return git.DiffTarget{}, fmt.Errorf("--incremental cannot be combined with %s", set[0])
+	}
+	if flags.Publish && target.Kind != "" {
+		// The review is posted on the pull request's head commit, so only
+		// the branch or the pull request itself can be published.
+		return git.DiffTarget{}, fmt.Errorf("--publish cannot be combined with %s", set[0])
+	}
+	return target, nil
+}
+
 // resolveReviewAgents returns the ordered list of agent names for the review.
 // If the --agents flag is non-empty, that value is split on commas and used
 // directly. Otherwise, any agent in config that has a non-empty Command or
@@ -338,6 +823,134 @@ func resolveReviewAgents(agentsFlag string, cfg config.Config) ([]string, error)
 	return agents, nil
 }
 
+// resolveReviewPersonas returns the configured review personas to run, sorted
+// by name. It returns nil, meaning a plain agent review, when --agents is set
+// or no personas are configured. --personas selects a subset by name.
+func resolveReviewPersonas(flags reviewFlags, configured map[string]config.PersonaConfig) ([]review.Persona, error) {
+	if flags.Agents != "" {
+		if flags.Personas != "" {
+			return nil, fmt.Errorf("--agents and --personas cannot be used together")
+		}
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(configured))
+	if flags.Personas != "" {
+		for _, name := range strings.Split(flags.Personas, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" || slices.Contains(names, name) {
+				continue
+			}
+			if _, ok := configured[name]; !ok {
+				available := slices.Sorted(maps.Keys(configured))
+				if len(available) == 0 {
+					return nil, fmt.Errorf("unknown persona %q: no [review.personas.*] sections in raven.toml", name)
+				}
+				return nil, fmt.Errorf("unknown persona %q: configured personas are: %s", name, strings.Join(available, ", "))
+			}
+			names = append(names, name)
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("--personas value %q produced an empty persona list", flags.Personas)
+		}
+	} else {
+		for name := range configured {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	personas := make([]review.Persona, 0, len(names))
+	for _, name := range names {
+		personas = append(personas, configToPersona(name, configured[name]))
+	}
+	return personas, nil
+}
+
+// configToPersona converts a [review.personas.<name>] section to a
+// review.Persona.
+func configToPersona(name string, c config.PersonaConfig) review.Persona {
+	return review.Persona{
+		Name:       name,
+		Agent:      c.Agent,
+		Model:      c.Model,
+		PromptFile: c.Prompt,
+		RuleFiles:  c.Rules,
+		Paths:      c.Paths,
+	}
+}
+
+// configToAnalyzers converts the [review.analyzers.<name>] sections to
+// review.Analyzers, sorted by name.
+func configToAnalyzers(configured map[string]config.AnalyzerConfig) []review.Analyzer {
+	names := slices.Sorted(maps.Keys(configured))
+	analyzers := make([]review.Analyzer, 0, len(names))
+	for _, name := range names {
+		c := configured[name]
+		analyzers = append(analyzers, review.Analyzer{
+			Name:     name,
+			Command:  c.Command,
+			Format:   review.AnalyzerFormat(c.Format),
+			Pattern:  c.Pattern,
+			Severity: review.Severity(c.Severity),
+			Category: c.Category,
+			Context:  c.Context,
+		})
+	}
+	return analyzers
+}
+
+// newReviewConsolidator creates a Consolidator configured from the [review]
+// settings: invalid finding handling and, unless dedup is "exact", semantic
+// deduplication with the configured thresholds.
+func newReviewConsolidator(cfg review.ReviewConfig, logger *log.Logger) *review.Consolidator {
+	c := review.NewConsolidator(logger).
+		WithInvalidFindings(review.InvalidFindingMode(cfg.InvalidFindings))
+	if review.DedupMode(cfg.Dedup) != review.DedupExact {
+		window := review.DefaultDedupLineWindow
+		if cfg.DedupLineWindow != nil {
+			window = *cfg.DedupLineWindow
+		}
+		c.WithSemanticDedup(review.DedupConfig{
+			LineWindow: window,
+			Threshold:  cfg.DedupThreshold,
+		})
+	}
+	return c
+}
+
+// configToVerdictPolicy converts the [review.policy] section to a
+// review.VerdictPolicy.
+func configToVerdictPolicy(c config.PolicyConfig) review.VerdictPolicy {
+	return review.VerdictPolicy{
+		Weights:        c.Weights,
+		BlockingQuorum: c.BlockingQuorum,
+		ChangesQuorum:  c.ChangesQuorum,
+		BlockSeverity:  review.Severity(c.BlockSeverity),
+		ApproveBelow:   review.Severity(c.ApproveBelow),
+		AgentFailure:   review.AgentFailureMode(c.AgentFailure),
+	}
+}
+
+// baselineUpdateReason is the waiver reason of findings accepted by
+// --update-baseline.
+const baselineUpdateReason = "accepted when the baseline was updated"
+
+// updateReviewBaseline waives findings in b and returns the number of new
+// waivers. Findings that are already waived keep their waiver.
+func updateReviewBaseline(b *review.Baseline, findings []*review.Finding, author string, now time.Time) int {
+	added := 0
+	for _, f := range findings {
+		if b.Match(f, now) != nil {
+			continue
+		}
+		if b.Add(review.NewWaiver(f, baselineUpdateReason, author, nil, now)) {
+			added++
+		}
+	}
+	return added
+}
+
 // configToReviewConfig converts a config.ReviewConfig to a review.ReviewConfig.
 // Both types have identical fields; the conversion is required because they live
 // in separate packages.
@@ -348,5 +961,9 @@ func configToReviewConfig(c config.ReviewConfig) review.ReviewConfig {
 		PromptsDir:       c.PromptsDir,
 		RulesDir:         c.RulesDir,
 		ProjectBriefFile: c.ProjectBriefFile,
+		InvalidFindings:  c.InvalidFindings,
+		Dedup:            c.Dedup,
+		DedupLineWindow:  c.DedupLineWindow,
+		DedupThreshold:   c.DedupThreshold,
 	}
 }
@@ -2,11 +2,14 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -14,6 +17,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/AbdelazizMoustafa10m/Raven/internal/config"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/git"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/logging"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/review"
 )
 
@@ -195,12 +200,16 @@ func TestResolveReviewAgents_ConfigAgentWithEmptyCommandAndModel(t *testing.T) {
 // ---- configToReviewConfig tests ---------------------------------------------
 
 func TestConfigToReviewConfig_FieldMapping(t *testing.T) {
+	window := 5
 	in := config.ReviewConfig{
 		Extensions:       `\.go$`,
 		RiskPatterns:     `auth|crypto`,
 		PromptsDir:       "prompts/review",
 		RulesDir:         "rules",
 		ProjectBriefFile: "PROJECT.md",
+		Dedup:            "exact",
+		DedupLineWindow:  &window,
+		DedupThreshold:   0.75,
 	}
 	out := configToReviewConfig(in)
 	assert.Equal(t, in.Extensions, out.Extensions)
@@ -208,6 +217,9 @@ func TestConfigToReviewConfig_FieldMapping(t *testing.T) {
 	assert.Equal(t, in.PromptsDir, out.PromptsDir)
 	assert.Equal(t, in.RulesDir, out.RulesDir)
 	assert.Equal(t, in.ProjectBriefFile, out.ProjectBriefFile)
+	assert.Equal(t, in.Dedup, out.Dedup)
+	assert.Equal(t, in.DedupLineWindow, out.DedupLineWindow)
+	assert.Equal(t, in.DedupThreshold, out.DedupThreshold)
 }
 
 func TestConfigToReviewConfig_ZeroValue(t *testing.T) {
@@ -234,6 +246,15 @@ func TestNewReviewCmd_FlagsExist(t *testing.T) {
 		"mode",
 		"base",
 		"output",
+		"personas",
+		"no-analyzers",
+		"update-baseline",
+		"staged",
+		"worktree",
+		"range",
+		"commit",
+		"pr",
+		"publish",
 	}
 	for _, name := range expectedFlags {
 		flag := cmd.Flags().Lookup(name)
@@ -1247,3 +1268,350 @@ func TestRunReview_EmptyDiff_ViaExecute(t *testing.T) {
 	assert.Contains(t, out.String(), "No changes to review",
 		"stdout must report no changes")
 }
+
+// ---- incremental review tests -----------------------------------------------
+
+// newReviewTestRepo creates a git repository on branch "feature" with one
+// commit and returns a client for it.
+func newReviewTestRepo(t *testing.T) *git.GitClient {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-b", "feature"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+		{"commit", "--allow-empty", "-m", "first"},
+	} {
+		reviewTestGit(t, dir, args...)
+	}
+	c, err := git.NewGitClient(dir)
+	require.NoError(t, err)
+	return c
+}
+
+func reviewTestGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v: %s", args, out)
+}
+
+func TestPlanIncrementalReview(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.New("review")
+	gc := newReviewTestRepo(t)
+	store := review.NewReviewStateStore(filepath.Join(t.TempDir(), "review"))
+
+	branch, first := currentReviewHead(ctx, gc, logger)
+	require.Equal(t, "feature", branch)
+	require.Len(t, first, 40)
+
+	// No previous review: full review.
+	prior, err := planIncrementalReview(ctx, gc, store, branch, first, logger)
+	require.NoError(t, err)
+	assert.Nil(t, prior)
+
+	findings := []*review.Finding{{Severity: review.SeverityHigh, File: "a.go", Line: 1}}
+	require.NoError(t, store.Save(&review.ReviewState{Branch: branch, HeadSHA: first, Findings: findings}))
+
+	// Same HEAD: the recorded state is returned unchanged.
+	prior, err = planIncrementalReview(ctx, gc, store, branch, first, logger)
+	require.NoError(t, err)
+	require.NotNil(t, prior)
+	assert.Equal(t, first, prior.HeadSHA)
+
+	// A new commit on top: incremental from the recorded HEAD.
+	reviewTestGit(t, gc.WorkDir, "commit", "--allow-empty", "-m", "second")
+	_, second := currentReviewHead(ctx, gc, logger)
+	prior, err = planIncrementalReview(ctx, gc, store, branch, second, logger)
+	require.NoError(t, err)
+	require.NotNil(t, prior)
+	assert.Equal(t, findings, prior.Findings)
+
+	// The recorded HEAD was rewritten: full review.
+	require.NoError(t, store.Save(&review.ReviewState{Branch: branch, HeadSHA: second}))
+	reviewTestGit(t, gc.WorkDir, "commit", "--amend", "--allow-empty", "-m", "second (amended)")
+	_, amended := currentReviewHead(ctx, gc, logger)
+	prior, err = planIncrementalReview(ctx, gc, store, branch, amended, logger)
+	require.NoError(t, err)
+	assert.Nil(t, prior)
+
+	// Detached HEAD: no branch to key the state on.
+	prior, err = planIncrementalReview(ctx, gc, store, "", "", logger)
+	require.NoError(t, err)
+	assert.Nil(t, prior)
+}
+
+func TestCurrentReviewHead_DetachedHead(t *testing.T) {
+	gc := newReviewTestRepo(t)
+	reviewTestGit(t, gc.WorkDir, "checkout", "--detach")
+
+	branch, head := currentReviewHead(context.Background(), gc, logging.New("review"))
+	assert.Empty(t, branch)
+	assert.Empty(t, head)
+}
+
+func TestCarriedReview_RendersEveryFormat(t *testing.T) {
+	prior := &review.ReviewState{
+		Branch:     "feature",
+		BaseBranch: "main",
+		HeadSHA:    "0123456789abcdef0123456789abcdef01234567",
+		Verdict:    review.VerdictChangesNeeded,
+		Findings: []*review.Finding{
+			{Severity: review.SeverityHigh, Category: "security", File: "a.go", Line: 1, Description: "still open", Agent: "claude"},
+		},
+	}
+	consolidated, stats, diffResult := carriedReview(prior)
+	assert.Equal(t, prior.Verdict, consolidated.Verdict)
+	assert.Equal(t, 1, stats.FindingsPerSeverity[review.SeverityHigh])
+	assert.Equal(t, prior.HeadSHA, diffResult.Since)
+
+	rg := review.NewReportGenerator(logging.New("review"))
+	for _, format := range review.ReportFormats {
+		report, err := rg.Render(format, consolidated, stats, diffResult)
+		require.NoError(t, err, format)
+		assert.Contains(t, report, "still open", format)
+	}
+}
+
+func TestWriteReviewReport_Output(t *testing.T) {
+	cmd := &cobra.Command{}
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, writeReviewReport(cmd, path, "{}", logging.New("review")))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(data))
+	assert.Empty(t, stdout.String())
+
+	require.NoError(t, writeReviewReport(cmd, "", "report", logging.New("review")))
+	assert.Equal(t, "report", stdout.String())
+}
+
+func TestNewReviewCmd_IncrementalFlag(t *testing.T) {
+	cmd := newReviewCmd()
+	f := cmd.Flags().Lookup("incremental")
+	require.NotNil(t, f)
+	assert.Equal(t, "false", f.DefValue)
+}
+
+func TestResolveReviewPersonas(t *testing.T) {
+	configured := map[string]config.PersonaConfig{
+		"tests":    {Agent: "codex", Paths: []string{"*_test.go"}},
+		"security": {Agent: "claude", Model: "opus", Prompt: "sec.md", Rules: []string{"owasp.md"}, Paths: []string{"internal/**"}},
+		"api":      {Agent: "claude"},
+	}
+
+	// All configured personas, sorted by name.
+	personas, err := resolveReviewPersonas(reviewFlags{}, configured)
+	require.NoError(t, err)
+	require.Len(t, personas, 3)
+	assert.Equal(t, "api", personas[0].Name)
+	assert.Equal(t, review.Persona{
+		Name:       "security",
+		Agent:      "claude",
+		Model:      "opus",
+		PromptFile: "sec.md",
+		RuleFiles:  []string{"owasp.md"},
+		Paths:      []string{"internal/**"},
+	}, personas[1])
+
+	// --personas selects a subset.
+	personas, err = resolveReviewPersonas(reviewFlags{Personas: "tests, security,tests"}, configured)
+	require.NoError(t, err)
+	require.Len(t, personas, 2)
+	assert.Equal(t, "security", personas[0].Name)
+	assert.Equal(t, "tests", personas[1].Name)
+
+	_, err = resolveReviewPersonas(reviewFlags{Personas: "style"}, configured)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "configured personas are: api, security, tests")
+
+	_, err = resolveReviewPersonas(reviewFlags{Personas: "security"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no [review.personas.*] sections")
+
+	_, err = resolveReviewPersonas(reviewFlags{Personas: " , "}, configured)
+	require.Error(t, err)
+
+	// --agents runs a plain review.
+	personas, err = resolveReviewPersonas(reviewFlags{Agents: "claude"}, configured)
+	require.NoError(t, err)
+	assert.Nil(t, personas)
+	_, err = resolveReviewPersonas(reviewFlags{Agents: "claude", Personas: "security"}, configured)
+	require.Error(t, err)
+
+	// No personas configured: plain review.
+	personas, err = resolveReviewPersonas(reviewFlags{}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, personas)
+}
+
+func TestRunReview_DryRun_Personas(t *testing.T) {
+	tmpDir := t.TempDir()
+	tomlPath := writeMinimalReviewToml(t, tmpDir, `
+[review.personas.security]
+agent = "claude"
+paths = ["**/*.go"]
+
+[review.personas.docs]
+agent = "codex"
+paths = ["no-such-dir/**"]
+`)
+
+	origConfig := flagConfig
+	origDryRun := flagDryRun
+	flagConfig = tomlPath
+	flagDryRun = true
+	t.Cleanup(func() {
+		flagConfig = origConfig
+		flagDryRun = origDryRun
+	})
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+
+	// Diffing HEAD against itself leaves no files for either persona.
+	err := runReview(cmd, reviewFlags{Concurrency: 2, Mode: "all", BaseBranch: "HEAD", Format: "markdown"})
+	require.NoError(t, err)
+
+	output := out.String()
+	assert.Contains(t, output, "Personas: 2")
+	assert.Contains(t, output, "docs (codex): skipped, no matching files")
+	assert.Contains(t, output, "security (claude): skipped, no matching files")
+	assert.NotContains(t, output, "Mode:")
+}
+
+func TestConfigToAnalyzers(t *testing.T) {
+	analyzers := configToAnalyzers(map[string]config.AnalyzerConfig{
+		"vet":         {Command: "go vet ./...", Format: "line", Severity: "high", Context: true},
+		"staticcheck": {Command: "staticcheck -f sarif ./...", Format: "sarif", Category: "lint"},
+	})
+	require.Len(t, analyzers, 2)
+	assert.Equal(t, review.Analyzer{
+		Name:     "staticcheck",
+		Command:  "staticcheck -f sarif ./...",
+		Format:   review.AnalyzerFormatSARIF,
+		Category: "lint",
+	}, analyzers[0])
+	assert.Equal(t, "vet", analyzers[1].Name)
+	assert.Equal(t, review.SeverityHigh, analyzers[1].Severity)
+	assert.True(t, analyzers[1].Context)
+	assert.Empty(t, configToAnalyzers(nil))
+}
+
+func TestConfigToVerdictPolicy(t *testing.T) {
+	policy := configToVerdictPolicy(config.PolicyConfig{
+		BlockingQuorum: 2,
+		ChangesQuorum:  1.5,
+		BlockSeverity:  "critical",
+		ApproveBelow:   "medium",
+		AgentFailure:   "ignore",
+		Weights:        map[string]float64{"claude": 2},
+	})
+	assert.Equal(t, review.VerdictPolicy{
+		Weights:        map[string]float64{"claude": 2},
+		BlockingQuorum: 2,
+		ChangesQuorum:  1.5,
+		BlockSeverity:  review.SeverityCritical,
+		ApproveBelow:   review.SeverityMedium,
+		AgentFailure:   review.AgentFailureIgnore,
+	}, policy)
+	assert.NoError(t, policy.Validate())
+	assert.Equal(t, review.VerdictPolicy{}, configToVerdictPolicy(config.PolicyConfig{}))
+}
+
+func TestRunReview_DryRun_Analyzers(t *testing.T) {
+	tmpDir := t.TempDir()
+	tomlPath := writeMinimalReviewToml(t, tmpDir, `
+[review.analyzers.vet]
+command = "go vet ./..."
+format = "line"
+`)
+
+	origConfig := flagConfig
+	origDryRun := flagDryRun
+	flagConfig = tomlPath
+	flagDryRun = true
+	t.Cleanup(func() {
+		flagConfig = origConfig
+		flagDryRun = origDryRun
+	})
+
+	run := func(flags reviewFlags) string {
+		var out bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		require.NoError(t, runReview(cmd, flags))
+		return out.String()
+	}
+
+	flags := reviewFlags{Agents: "claude", Concurrency: 2, Mode: "all", BaseBranch: "HEAD", Format: "markdown"}
+	output := run(flags)
+	assert.Contains(t, output, "Analyzers: 1")
+	assert.Contains(t, output, "vet (line): go vet ./...")
+
+	flags.NoAnalyzers = true
+	assert.NotContains(t, run(flags), "Analyzers:")
+}
+
+func TestUpdateReviewBaseline(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	existing := &review.Finding{Severity: review.SeverityLow, Category: "style", File: "a.go", Line: 1, Description: "Long line"}
+	b := &review.Baseline{}
+	b.Add(review.NewWaiver(existing, "house style", "bob", nil, now))
+
+	findings := []*review.Finding{
+		existing,
+		{Severity: review.SeverityHigh, Category: "security", File: "b.go", Line: 9, Description: "SQL built from user input"},
+	}
+	added := updateReviewBaseline(b, findings, "alice", now)
+	assert.Equal(t, 1, added)
+	require.Len(t, b.Waivers, 2)
+	// The existing waiver keeps its reason and author.
+	assert.Equal(t, "house style", b.Waivers[0].Reason)
+	assert.Equal(t, baselineUpdateReason, b.Waivers[1].Reason)
+	assert.Equal(t, "alice", b.Waivers[1].Author)
+	assert.Equal(t, "b.go", b.Waivers[1].File)
+}
+
+func TestResolveReviewTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   reviewFlags
+		want    git.DiffTarget
+		wantErr string
+	}{
+		{name: "branch by default", flags: reviewFlags{}, want: git.DiffTarget{}},
+		{name: "staged", flags: reviewFlags{Staged: true}, want: git.DiffTarget{Kind: git.TargetStaged}},
+		{name: "worktree", flags: reviewFlags{Worktree: true}, want: git.DiffTarget{Kind: git.TargetWorktree}},
+		{name: "range", flags: reviewFlags{Range: " v1.0..v1.1 "}, want: git.DiffTarget{Kind: git.TargetRange, Ref: "v1.0..v1.1"}},
+		{name: "commit", flags: reviewFlags{Commit: "abc1234"}, want: git.DiffTarget{Kind: git.TargetCommit, Ref: "abc1234"}},
+		{name: "pr resolved later", flags: reviewFlags{PR: 12}, want: git.DiffTarget{}},
+		{name: "two targets", flags: reviewFlags{Staged: true, Commit: "abc1234"}, wantErr: "--staged and --commit cannot be combined"},
+		{name: "pr and range", flags: reviewFlags{Range: "a..b", PR: 3}, wantErr: "--range and --pr cannot be combined"},
+		{name: "incremental", flags: reviewFlags{Worktree: true, Incremental: true}, wantErr: "--incremental cannot be combined with --worktree"},
+		{name: "negative pr", flags: reviewFlags{PR: -1}, wantErr: "invalid --pr"},
+		{name: "publish branch", flags: reviewFlags{Publish: true}, want: git.DiffTarget{}},
+		{name: "publish pr", flags: reviewFlags{Publish: true, PR: 5}, want: git.DiffTarget{}},
+		{name: "publish staged", flags: reviewFlags{Publish: true, Staged: true}, wantErr: "--publish cannot be combined with --staged"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveReviewTarget(tt.flags)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/git"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/review"
+)
+
+// reviewWaiveFlags holds parsed flag values for the review waive command.
+type reviewWaiveFlags struct {
+	// Reason explains why the finding is accepted. Required.
+	Reason string
+
+	// Author records who waived the finding. Defaults to git user.name.
+	Author string
+
+	// Expires is the date (YYYY-MM-DD) or RFC 3339 time at which the waiver
+	// stops applying. Empty never expires.
+	Expires string
+}
+
+// newReviewWaiveCmd creates the "raven review waive" command.
+func newReviewWaiveCmd() *cobra.Command {
+	var flags reviewWaiveFlags
+
+	cmd := &cobra.Command{
+		Use:   "waive <id>",
+		Short: "Accept a finding of the last review in the review baseline",
+		Long: `Waive a finding of the last review of the current branch by adding it to
+.raven/review-baseline.json. <id> is the finding ID shown in the review report;
+a unique prefix is enough.
+
+Waived findings are still listed in the report, in a "Waived Findings" section,
+but no longer count toward the verdict. A waiver with --expires stops applying
+on that date, after which the finding is reported again.`,
+		Example: `  raven review waive 3f2a9c01b7de --reason "false positive: input is validated upstream"
+  raven review waive 3f2a --reason "tracked in #142" --expires 2026-12-31`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReviewWaive(cmd, args[0], flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.Reason, "reason", "", "Why the finding is accepted (required)")
+	cmd.Flags().StringVar(&flags.Author, "author", "", "Who waives the finding (default: git user.name)")
+	cmd.Flags().StringVar(&flags.Expires, "expires", "", "Date the waiver stops applying (YYYY-MM-DD or RFC 3339)")
+	_ = cmd.MarkFlagRequired("reason")
+
+	return cmd
+}
+
+// runReviewWaive is the RunE implementation for the review waive command.
+func runReviewWaive(cmd *cobra.Command, id string, flags reviewWaiveFlags) error {
+	if strings.TrimSpace(flags.Reason) == "" {
+		return fmt.Errorf("--reason is required")
+	}
+	expiresAt, err := parseWaiverExpiry(flags.Expires)
+	if err != nil {
+		return err
+	}
+
+	gitClient, err := git.NewGitClient(".")
+	if err != nil {
+		return fmt.Errorf("initializing git client: %w", err)
+	}
+	ctx := context.Background()
+	branch, err := gitClient.CurrentBranch(ctx)
+	if err != nil {
+		return fmt.Errorf("determining current branch: %w", err)
+	}
+	author := flags.Author
+	if author == "" {
+		author, _ = gitClient.UserName(ctx)
+	}
+
+	w, err := waiveFinding(
+		review.NewReviewStateStore(defaultReviewStateDir),
+		defaultReviewBaselineFile,
+		branch,
+		id,
+		flags.Reason,
+		author,
+		expiresAt,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Waived %s (%s:%d %s) in %s.\n",
+		w.Fingerprint, w.File, w.Line, w.Category, defaultReviewBaselineFile)
+	return nil
+}
+
+// waiveFinding looks up the finding id (a fingerprint or a unique prefix of
+// one) among the open findings of the last review of branch, adds a waiver
+// for it to the baseline at baselinePath, and saves the baseline.
+func waiveFinding(
+	store *review.ReviewStateStore,
+	baselinePath, branch, id, reason, author string,
+	expiresAt *time.Time,
+	now time.Time,
+) (review.Waiver, error) {
+	state, err := store.Load(branch)
+	if err != nil {
+		return review.Waiver{}, fmt.Errorf("loading review state: %w", err)
+	}
+	if state == nil {
+		return review.Waiver{}, fmt.Errorf("no review recorded for branch %q: run raven review first", branch)
+	}
+
+	id = strings.ToLower(strings.TrimSpace(id))
+	var match *review.Finding
+	for _, f := range state.Findings {
+		fingerprint := f.Fingerprint
+		if fingerprint == "" {
+			fingerprint = review.FindingFingerprint(f)
+		}
+		if id == "" || !strings.HasPrefix(fingerprint, id) {
+			continue
+		}
+		if match != nil {
+			return review.Waiver{}, fmt.Errorf("finding ID %q is ambiguous: use more characters", id)
+		}
+		match = f
+	}
+	if match == nil {
+		return review.Waiver{}, fmt.Errorf("no open finding with ID %q in the last review of %q", id, branch)
+	}
+
+	baseline, err := review.LoadBaseline(baselinePath)
+	if err != nil {
+		return review.Waiver{}, err
+	}
+	w := review.NewWaiver(match, reason, author, expiresAt, now)
+	baseline.Add(w)
+	if err := baseline.Save(baselinePath); err != nil {
+		return review.Waiver{}, err
+	}
+	return w, nil
+}
+
+// parseWaiverExpiry parses the --expires flag. A date means midnight UTC at
+// the start of that day. Empty returns nil: the waiver never expires.
+func parseWaiverExpiry(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			t = t.UTC()
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid --expires %q: use YYYY-MM-DD or RFC 3339", value)
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/review"
+)
+
+func TestWaiveFinding(t *testing.T) {
+	dir := t.TempDir()
+	store := review.NewReviewStateStore(filepath.Join(dir, "review"))
+	baselinePath := filepath.Join(dir, "review-baseline.json")
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	sqlFinding := &review.Finding{Severity: review.SeverityHigh, Category: "security", File: "db.go", Line: 4, Description: "SQL built from user input"}
+	sqlFinding.Fingerprint = review.FindingFingerprint(sqlFinding)
+	// Recorded before findings carried fingerprints.
+	styleFinding := &review.Finding{Severity: review.SeverityLow, Category: "style", File: "main.go", Line: 1, Description: "Long line"}
+	require.NoError(t, store.Save(&review.ReviewState{
+		Branch:   "feature",
+		Findings: []*review.Finding{sqlFinding, styleFinding},
+	}))
+
+	expires := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	w, err := waiveFinding(store, baselinePath, "feature", sqlFinding.Fingerprint[:6], "validated upstream", "alice", &expires, now)
+	require.NoError(t, err)
+	assert.Equal(t, sqlFinding.Fingerprint, w.Fingerprint)
+
+	styleID := review.FindingFingerprint(styleFinding)
+	_, err = waiveFinding(store, baselinePath, "feature", styleID, "house style", "bob", nil, now)
+	require.NoError(t, err)
+
+	b, err := review.LoadBaseline(baselinePath)
+	require.NoError(t, err)
+	require.Len(t, b.Waivers, 2)
+	assert.Equal(t, "db.go", b.Waivers[0].File)
+	assert.Equal(t, "validated upstream", b.Waivers[0].Reason)
+	assert.Equal(t, "alice", b.Waivers[0].Author)
+	require.NotNil(t, b.Waivers[0].ExpiresAt)
+	assert.True(t, expires.Equal(*b.Waivers[0].ExpiresAt))
+	assert.NotNil(t, b.Match(sqlFinding, now))
+	assert.Nil(t, b.Match(sqlFinding, expires))
+	assert.NotNil(t, b.Match(styleFinding, now))
+}
+
+func TestWaiveFinding_Errors(t *testing.T) {
+	dir := t.TempDir()
+	store := review.NewReviewStateStore(filepath.Join(dir, "review"))
+	baselinePath := filepath.Join(dir, "review-baseline.json")
+	now := time.Now()
+
+	_, err := waiveFinding(store, baselinePath, "feature", "abc", "r", "", nil, now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `no review recorded for branch "feature"`)
+
+	require.NoError(t, store.Save(&review.ReviewState{
+		Branch: "feature",
+		Findings: []*review.Finding{
+			{File: "a.go", Fingerprint: "abc111111111"},
+			{File: "b.go", Fingerprint: "abc222222222"},
+		},
+	}))
+
+	_, err = waiveFinding(store, baselinePath, "feature", "abc", "r", "", nil, now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ambiguous")
+
+	_, err = waiveFinding(store, baselinePath, "feature", "fff", "r", "", nil, now)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `no open finding with ID "fff"`)
+
+	_, err = waiveFinding(store, baselinePath, "feature", "", "r", "", nil, now)
+	require.Error(t, err)
+	assert.NoFileExists(t, baselinePath)
+}
+
+func TestParseWaiverExpiry(t *testing.T) {
+	got, err := parseWaiverExpiry("")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	got, err = parseWaiverExpiry("2027-03-01")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC), *got)
+
+	got, err = parseWaiverExpiry("2027-03-01T12:00:00+02:00")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, time.Date(2027, 3, 1, 10, 0, 0, 0, time.UTC), *got)
+
+	_, err = parseWaiverExpiry("next week")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --expires")
+}
+
+func TestNewReviewWaiveCmd(t *testing.T) {
+	cmd := newReviewCmd()
+	waive, _, err := cmd.Find([]string{"waive"})
+	require.NoError(t, err)
+	assert.Equal(t, "waive", waive.Name())
+	for _, name := range []string{"reason", "author", "expires"} {
+		assert.NotNil(t, waive.Flags().Lookup(name), "expected flag --%s to exist", name)
+	}
+
+	err = runReviewWaive(waive, "abc", reviewWaiveFlags{Reason: "  "})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--reason is required")
+}
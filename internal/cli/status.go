@@ -8,6 +8,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/lipgloss"
@@ -19,9 +20,12 @@ import (
 
 // statusFlags holds the flag values for the status command.
 type statusFlags struct {
-	Phase   int  // --phase <id>, 0 means all phases
-	JSON    bool // --json for structured output
-	Verbose bool // --verbose for per-task details (overrides global --verbose for output control)
+	Phase    int    // --phase <id>, 0 means all phases
+	JSON     bool   // --json for structured output
+	Verbose  bool   // --verbose for per-task details (overrides global --verbose for output control)
+	At       string // --at <timestamp> to reconstruct past state from history
+	Format   string // --format text|markdown|json|html|burndown
+	Forecast bool   // --forecast to estimate remaining time and cost
 }
 
 // statusPhaseOutput is the JSON output type for a single phase.
@@ -44,7 +48,20 @@ type statusOutput struct {
 	TotalDone    int                 `json:"total_done"`
 	OverallPct   float64             `json:"overall_percent"`
 	CurrentPhase int                 `json:"current_phase"`
+	At           string              `json:"at,omitempty"`
 	Phases       []statusPhaseOutput `json:"phases"`
+	Forecast     *task.Forecast      `json:"forecast,omitempty"`
+}
+
+// statusAtLayouts are the absolute time layouts accepted by --at, tried in
+// order. Layouts without a zone are interpreted in local time.
+var statusAtLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
 }
 
 // newStatusCmd creates the "raven status" command.
@@ -57,8 +74,25 @@ func newStatusCmd() *cobra.Command {
 		Long: `Display a summary of task progress for all phases or a single phase.
 Each phase shows a progress bar, completion fraction, and counts.
 
-Use --verbose to see per-task status details. Use --json for structured
-output suitable for scripting.`,
+Use --verbose to see per-task status details. Use --json for a structured
+summary suitable for scripting.
+
+Use --format to print one of the progress reports that "raven implement"
+keeps next to PROGRESS.md: markdown (PROGRESS.md itself), json (the full
+report with per-task rows, blocked tasks, recent activity, burndown, and the
+dependency graph), html (a self-contained dashboard), or burndown (CSV of
+completed and remaining tasks per day).
+
+Use --at to show progress as it was at a past moment, reconstructed from the
+task state history. It accepts a timestamp ("2026-03-01T09:00:00Z",
+"2026-03-01 09:00", "2026-03-01") or a duration ago ("36h"). Tasks with no
+recorded change by then count as not started.
+
+Use --forecast to estimate the time and cost left in each phase. Every task
+worked on by "raven implement" has its iterations, wall time, agent time,
+tokens and cost recorded next to the task state file. The forecast assumes a
+remaining task takes as long and costs as much as the average completed task
+of its effort bucket (small, medium or large, from its Estimated Effort).`,
 		Example: `  # Show all phases
   raven status
 
@@ -69,7 +103,16 @@ output suitable for scripting.`,
   raven status --verbose
 
   # Structured JSON output
-  raven status --json`,
+  raven status --json
+
+  # Progress as of yesterday morning
+  raven status --at "2026-03-01 09:00"
+
+  # Estimate the time and cost left per phase
+  raven status --forecast
+
+  # Write the HTML dashboard
+  raven status --format html > progress.html`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runStatus(cmd, args, flags)
@@ -79,6 +122,17 @@ output suitable for scripting.`,
 	cmd.Flags().IntVar(&flags.Phase, "phase", 0, "Filter to a single phase (0 = all phases)")
 	cmd.Flags().BoolVar(&flags.JSON, "json", false, "Output structured JSON to stdout")
 	cmd.Flags().BoolVar(&flags.Verbose, "verbose", false, "Show per-task status details within each phase")
+	cmd.Flags().StringVar(&flags.At, "at", "", "Show state as of a past timestamp or duration ago, from the task history")
+	cmd.Flags().StringVar(&flags.Format, "format", "text", "Output format: text, markdown, json, html, burndown")
+	cmd.Flags().BoolVar(&flags.Forecast, "forecast", false, "Estimate remaining time and cost per phase from recorded task metrics")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		formats := []string{"text"}
+		for _, f := range task.ProgressFormats() {
+			formats = append(formats, string(f))
+		}
+		return formats, cobra.ShellCompDirectiveNoFileComp
+	})
 
 	return cmd
 }
@@ -90,6 +144,24 @@ func init() {
 // runStatus is the command's RunE function. Loads config, discovers tasks,
 // computes progress, and renders output.
 func runStatus(cmd *cobra.Command, _ []string, flags statusFlags) error {
+	var format task.ProgressFormat
+	if flags.Format != "" && flags.Format != "text" {
+		f, err := task.ParseProgressFormat(flags.Format)
+		if err != nil {
+			return fmt.Errorf("invalid --format: %w", err)
+		}
+		if flags.JSON {
+			return fmt.Errorf("--json and --format %s cannot be used together", flags.Format)
+		}
+		if flags.Phase != 0 {
+			return fmt.Errorf("--phase cannot be used with --format %s: progress reports cover all phases", flags.Format)
+		}
+		if flags.Forecast {
+			return fmt.Errorf("--forecast cannot be used with --format %s", flags.Format)
+		}
+		format = f
+	}
+
 	// Load and resolve configuration.
 	resolved, _, err := loadAndResolveConfig()
 	if err != nil {
@@ -104,19 +176,43 @@ func runStatus(cmd *cobra.Command, _ []string, flags statusFlags) error {
 		return fmt.Errorf("discovering tasks: %w", err)
 	}
 
-	// Load task state.
+	// Load task state, or reconstruct it from history for --at.
 	stateManager := task.NewStateManager(cfg.Project.TaskStateFile)
+	var at time.Time
+	if flags.At != "" {
+		at, err = parseStatusAt(flags.At, time.Now())
+		if err != nil {
+			return err
+		}
+		stateManager, err = stateManager.At(at)
+		if err != nil {
+			return fmt.Errorf("loading task history: %w", err)
+		}
+	}
 
 	// Load phases -- gracefully handle missing phases.conf.
 	var phases []task.Phase
 	if cfg.Project.PhasesConf != "" {
-		phases, err = task.LoadPhases(cfg.Project.PhasesConf)
+		phases, err = task.LoadPhases(cfg.Project.PhasesConf, cfg.Project.TaskIDPrefix)
 		if err != nil && !errors.Is(err, os.ErrNotExist) {
 			return fmt.Errorf("loading phases: %w", err)
 		}
 		// If file not found, phases remains nil -- handled gracefully below.
 	}
 
+	// Progress report output: write to stdout.
+	if format != "" {
+		pg, err := task.NewProgressGenerator(specs, stateManager, phases)
+		if err != nil {
+			return fmt.Errorf("creating progress generator: %w", err)
+		}
+		projectName := cfg.Project.Name
+		if projectName == "" {
+			projectName = "Raven"
+		}
+		return pg.Render(cmd.OutOrStdout(), format, projectName)
+	}
+
 	// Build task selector.
 	selector := task.NewTaskSelector(specs, stateManager, phases)
 
@@ -161,9 +257,19 @@ func runStatus(cmd *cobra.Command, _ []string, flags statusFlags) error {
 		}
 	}
 
+	// Forecast remaining effort from the recorded task metrics.
+	var forecast *task.Forecast
+	if flags.Forecast {
+		fc, err := buildStatusForecast(stateManager, specs, phases, flags.Phase)
+		if err != nil {
+			return err
+		}
+		forecast = &fc
+	}
+
 	// JSON output mode: write to stdout.
 	if flags.JSON {
-		return renderJSON(cmd.OutOrStdout(), cfg, phases, allProgress)
+		return renderJSON(cmd.OutOrStdout(), cfg, phases, allProgress, at, forecast)
 	}
 
 	// Human-readable output: write to stderr per PRD conventions.
@@ -174,6 +280,9 @@ func runStatus(cmd *cobra.Command, _ []string, flags statusFlags) error {
 	}
 
 	fmt.Fprintln(out, renderSummary(allProgress, projectName))
+	if !at.IsZero() {
+		fmt.Fprintf(out, "As of: %s (reconstructed from task history)\n\n", at.Format(time.RFC3339))
+	}
 
 	for _, prog := range allProgress {
 		phaseName := phaseNameFor(phases, prog.PhaseID)
@@ -191,11 +300,17 @@ func runStatus(cmd *cobra.Command, _ []string, flags statusFlags) error {
 		}
 	}
 
+	if forecast != nil {
+		fmt.Fprint(out, renderForecast(*forecast))
+	}
+
 	return nil
 }
 
-// renderJSON serialises progress data to JSON and writes it to w.
-func renderJSON(w io.Writer, cfg *config.Config, phases []task.Phase, allProgress []task.PhaseProgress) error {
+// renderJSON serialises progress data to JSON and writes it to w. A non-zero
+// at is included as the reconstruction time, and a non-nil forecast as the
+// forecast.
+func renderJSON(w io.Writer, cfg *config.Config, phases []task.Phase, allProgress []task.PhaseProgress, at time.Time, forecast *task.Forecast) error {
 	phaseOutputs := make([]statusPhaseOutput, 0, len(allProgress))
 	for _, prog := range allProgress {
 		pct := 0.0
@@ -234,6 +349,10 @@ func renderJSON(w io.Writer, cfg *config.Config, phases []task.Phase, allProgres
 		OverallPct:   overallPct,
 		CurrentPhase: currentPhase,
 		Phases:       phaseOutputs,
+		Forecast:     forecast,
+	}
+	if !at.IsZero() {
+		out.At = at.UTC().Format(time.RFC3339)
 	}
 
 	enc := json.NewEncoder(w)
@@ -372,7 +491,7 @@ func renderTaskDetails(
 		return "", nil
 	}
 
-	taskIDs := task.TasksInPhase(*ph)
+	taskIDs := task.NewPhaseMembership(phases, specs).Tasks(phaseID)
 	stateMap, err := stateManager.LoadMap()
 	if err != nil {
 		return "", fmt.Errorf("loading state map: %w", err)
@@ -389,6 +508,15 @@ func renderTaskDetails(
 	blockedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))     // red
 	skippedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))     // dark gray
 
+	// Pad IDs to the widest one so titles stay aligned when a phase crosses a
+	// digit boundary (e.g. T-999 and T-1000).
+	idWidth := 0
+	for _, id := range taskIDs {
+		if _, ok := specMap[id]; ok && len(id) > idWidth {
+			idWidth = len(id)
+		}
+	}
+
 	var sb strings.Builder
 	for _, id := range taskIDs {
 		spec, hasSpec := specMap[id]
@@ -423,7 +551,7 @@ func renderTaskDetails(
 			title = title[:47] + "..."
 		}
 
-		line := fmt.Sprintf("  %s  %-50s  %s", id, title, statusLabel)
+		line := fmt.Sprintf("  %-*s  %-50s  %s", idWidth, id, title, statusLabel)
 		if agent != "" {
 			line += fmt.Sprintf("  (%s)", agent)
 		}
@@ -508,3 +636,117 @@ func currentPhaseID(allProgress []task.PhaseProgress) int {
 	}
 	return 0
 }
+
+// parseStatusAt parses the --at value: an absolute timestamp in one of
+// statusAtLayouts, or a Go duration ("90m", "36h") meaning that long before
+// now.
+func parseStatusAt(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range statusAtLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --at value %q: expected a timestamp such as 2026-03-01T09:00:00Z or 2026-03-01, or a duration such as 36h", value)
+}
+
+// configuredProgressFormats returns the valid entries of
+// project.progress_formats. Invalid entries are reported by config
+// validation and skipped here.
+func configuredProgressFormats(cfg *config.Config) []task.ProgressFormat {
+	var formats []task.ProgressFormat
+	for _, name := range cfg.Project.ProgressFormats {
+		if f, err := task.ParseProgressFormat(name); err == nil {
+			formats = append(formats, f)
+		}
+	}
+	return formats
+}
+
+// buildStatusForecast forecasts the remaining effort from the metrics
+// recorded next to the state file. A non-zero phaseID restricts the phases
+// and the total to that phase.
+func buildStatusForecast(sm *task.StateManager, specs []*task.ParsedTaskSpec, phases []task.Phase, phaseID int) (task.Forecast, error) {
+	stateMap, err := sm.LoadMap()
+	if err != nil {
+		return task.Forecast{}, fmt.Errorf("loading task state: %w", err)
+	}
+	metrics, err := sm.Metrics()
+	if err != nil {
+		return task.Forecast{}, fmt.Errorf("loading task metrics: %w", err)
+	}
+
+	fc := task.BuildForecast(specs, stateMap, metrics, phases)
+	if phaseID != 0 {
+		for _, pf := range fc.Phases {
+			if pf.PhaseID == phaseID {
+				fc.Phases = []task.PhaseForecast{pf}
+				fc.Total = pf
+				fc.Total.PhaseID = 0
+				fc.Total.PhaseName = "Total"
+				break
+			}
+		}
+	}
+	return fc, nil
+}
+
+// renderForecast renders the forecast as a plain-text section: the
+// velocity per effort bucket, then the estimate per phase and in total.
+func renderForecast(fc task.Forecast) string {
+	var sb strings.Builder
+	sb.WriteString("Forecast\n")
+
+	samples := 0
+	for _, v := range fc.Velocity {
+		samples += v.Samples
+	}
+	if samples == 0 {
+		sb.WriteString("  No completed tasks with recorded metrics yet; run \"raven implement\" to collect them.\n")
+	}
+
+	sb.WriteString("  Velocity per task:\n")
+	for _, v := range fc.Velocity {
+		if v.Samples == 0 {
+			fmt.Fprintf(&sb, "    %-7s  no data\n", v.Bucket)
+			continue
+		}
+		fmt.Fprintf(&sb, "    %-7s  %s wall, %s agent, $%.2f  (%d tasks)\n",
+			v.Bucket, formatForecastDuration(v.WallTime), formatForecastDuration(v.AgentTime), v.CostUSD, v.Samples)
+	}
+
+	sb.WriteString("  Remaining:\n")
+	rows := make([]task.PhaseForecast, 0, len(fc.Phases)+1)
+	rows = append(rows, fc.Phases...)
+	if len(fc.Phases) > 1 {
+		rows = append(rows, fc.Total)
+	}
+	for _, pf := range rows {
+		label := pf.PhaseName
+		if pf.PhaseID != 0 {
+			label = fmt.Sprintf("Phase %d: %s", pf.PhaseID, pf.PhaseName)
+		}
+		fmt.Fprintf(&sb, "    %-28s  %3d tasks", label, pf.Remaining)
+		if pf.Remaining > pf.Unestimated {
+			fmt.Fprintf(&sb, "  ~%s wall, ~%s agent, ~$%.2f",
+				formatForecastDuration(pf.WallTime), formatForecastDuration(pf.AgentTime), pf.CostUSD)
+		}
+		if pf.Unestimated > 0 {
+			fmt.Fprintf(&sb, "  (%d unestimated)", pf.Unestimated)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// formatForecastDuration rounds d for display: to the minute from one hour
+// up ("2h15m"), to the second below.
+func formatForecastDuration(d time.Duration) string {
+	if d >= time.Hour {
+		return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+	}
+	return d.Round(time.Second).String()
+}
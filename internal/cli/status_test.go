@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/AbdelazizMoustafa10m/Raven/internal/config"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
 )
 
@@ -345,6 +348,7 @@ func TestStatusCmd_FlagsRegistered(t *testing.T) {
 	assert.NotNil(t, statusCmd.Flags().Lookup("phase"), "--phase flag must be registered")
 	assert.NotNil(t, statusCmd.Flags().Lookup("json"), "--json flag must be registered")
 	assert.NotNil(t, statusCmd.Flags().Lookup("verbose"), "--verbose flag must be registered")
+	assert.NotNil(t, statusCmd.Flags().Lookup("format"), "--format flag must be registered")
 }
 
 // --- phaseNameFor tests -------------------------------------------------------
@@ -449,3 +453,212 @@ func TestBuildUngroupedProgress_Empty(t *testing.T) {
 	assert.Equal(t, 0, prog.Total)
 	assert.Equal(t, 0, prog.Completed)
 }
+
+// --- --at tests ----------------------------------------------------------------
+
+func TestParseStatusAt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2026-03-01T09:00:00Z", want: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)},
+		{value: "2026-03-01T09:00:00+02:00", want: time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)},
+		{value: "2026-03-01 09:00", want: time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)},
+		{value: "2026-03-01", want: time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)},
+		{value: "36h", want: now.Add(-36 * time.Hour)},
+		{value: "yesterday", wantErr: true},
+		{value: "-2h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseStatusAt(tt.value, now)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestStatusJSON_At(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	specContent := "# T-001: Setup\n\n| Field | Value |\n|-------|-------|\n| Dependencies | None |\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "T-001-setup.md"), []byte(specContent), 0o644))
+
+	statePath := filepath.Join(tmpDir, "task-state.conf")
+	phasesPath := filepath.Join(tmpDir, "phases.conf")
+	require.NoError(t, os.WriteFile(phasesPath, []byte("1|Foundation|T-001|T-001\n"), 0o644))
+
+	// Write history directly so the timestamps are known.
+	history := `{"timestamp":"2026-03-01T09:00:00Z","task_id":"T-001","status":"in_progress"}` + "\n" +
+		`{"timestamp":"2026-03-01T11:00:00Z","task_id":"T-001","previous_status":"in_progress","status":"completed"}` + "\n"
+	require.NoError(t, os.WriteFile(task.HistoryFilePath(statePath), []byte(history), 0o644))
+	require.NoError(t, os.WriteFile(statePath, []byte("T-001|completed|claude||\n"), 0o644))
+
+	tomlContent := fmt.Sprintf("[project]\nname = \"test-project\"\ntasks_dir = %q\ntask_state_file = %q\nphases_conf = %q\n",
+		tmpDir, statePath, phasesPath)
+	tomlPath := filepath.Join(tmpDir, "raven.toml")
+	require.NoError(t, os.WriteFile(tomlPath, []byte(tomlContent), 0o644))
+
+	tests := []struct {
+		at       string
+		wantDone int
+	}{
+		{at: "2026-03-01T10:00:00Z", wantDone: 0},
+		{at: "2026-03-01T12:00:00Z", wantDone: 1},
+	}
+	for _, tt := range tests {
+		resetStatusFlags(t)
+
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs([]string{"--config", tomlPath, "status", "--json", "--at", tt.at})
+		require.Equal(t, 0, Execute())
+
+		var out statusOutput
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+		assert.Equal(t, tt.wantDone, out.TotalDone, "at %s", tt.at)
+		assert.Equal(t, tt.at, out.At)
+	}
+}
+
+func TestStatusCmd_Format(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+
+	tests := []struct {
+		format string
+		check  func(t *testing.T, out string)
+	}{
+		{format: "json", check: func(t *testing.T, out string) {
+			var report task.ProgressReport
+			require.NoError(t, json.Unmarshal([]byte(out), &report))
+			assert.Equal(t, 3, report.Total)
+			assert.Equal(t, 1, report.Completed)
+			require.Len(t, report.Blocked, 1)
+			assert.Equal(t, "T-002", report.Blocked[0].ID)
+		}},
+		{format: "html", check: func(t *testing.T, out string) {
+			assert.True(t, strings.HasPrefix(out, "<!DOCTYPE html>"))
+			assert.Contains(t, out, "Phase 2: Interface")
+		}},
+		{format: "burndown", check: func(t *testing.T, out string) {
+			assert.True(t, strings.HasPrefix(out, "date,completed,remaining,total\n"))
+		}},
+		{format: "markdown", check: func(t *testing.T, out string) {
+			assert.Contains(t, out, "# Progress")
+			assert.Contains(t, out, "Foundation")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			resetStatusFlags(t)
+
+			var buf bytes.Buffer
+			rootCmd.SetOut(&buf)
+			rootCmd.SetArgs([]string{"--config", tomlPath, "status", "--format", tt.format})
+			require.Equal(t, 0, Execute())
+			tt.check(t, buf.String())
+		})
+	}
+}
+
+func TestStatusCmd_FormatErrors(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+	resetStatusFlags(t)
+	flagConfig = tomlPath
+	t.Cleanup(func() { flagConfig = "" })
+
+	tests := []struct {
+		name    string
+		flags   statusFlags
+		wantErr string
+	}{
+		{name: "unknown format", flags: statusFlags{Format: "pdf"}, wantErr: "unknown progress format"},
+		{name: "with json", flags: statusFlags{Format: "html", JSON: true}, wantErr: "--json and --format html"},
+		{name: "with phase", flags: statusFlags{Format: "json", Phase: 1}, wantErr: "--phase cannot be used"},
+		{name: "with forecast", flags: statusFlags{Format: "html", Forecast: true}, wantErr: "--forecast cannot be used"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runStatus(newStatusCmd(), nil, tt.flags)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestConfiguredProgressFormats(t *testing.T) {
+	cfg := &config.Config{Project: config.ProjectConfig{ProgressFormats: []string{"markdown", "pdf", "html"}}}
+	assert.Equal(t, []task.ProgressFormat{task.ProgressFormatMarkdown, task.ProgressFormatHTML}, configuredProgressFormats(cfg))
+}
+
+func TestStatusCmd_Forecast(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+	sm := task.NewStateManager(filepath.Join(filepath.Dir(tomlPath), "task-state.conf"))
+	require.NoError(t, sm.RecordMetrics(task.TaskMetrics{
+		TaskID: "T-001", Iterations: 2, WallTime: 40 * time.Minute, AgentTime: 30 * time.Minute, CostUSD: 1.5,
+	}))
+
+	t.Run("json", func(t *testing.T) {
+		resetStatusFlags(t)
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs([]string{"--config", tomlPath, "status", "--json", "--forecast"})
+		require.Equal(t, 0, Execute())
+
+		var out statusOutput
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+		require.NotNil(t, out.Forecast)
+		require.Len(t, out.Forecast.Phases, 2)
+		assert.Equal(t, 1, out.Forecast.Phases[0].Remaining)
+		assert.Equal(t, 40*time.Minute, out.Forecast.Phases[0].WallTime)
+		assert.Equal(t, 2, out.Forecast.Total.Remaining)
+		assert.InDelta(t, 3.0, out.Forecast.Total.CostUSD, 1e-9)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		resetStatusFlags(t)
+		var stderr bytes.Buffer
+		rootCmd.SetErr(&stderr)
+		rootCmd.SetArgs([]string{"--config", tomlPath, "status", "--forecast", "--phase", "2"})
+		require.Equal(t, 0, Execute())
+
+		out := stderr.String()
+		assert.Contains(t, out, "Forecast")
+		assert.Contains(t, out, "medium   40m0s wall, 30m0s agent, $1.50  (1 tasks)")
+		assert.Contains(t, out, "Phase 2: Interface")
+		assert.NotContains(t, out, "Phase 1: Foundation")
+	})
+
+	t.Run("without json", func(t *testing.T) {
+		resetStatusFlags(t)
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs([]string{"--config", tomlPath, "status", "--json"})
+		require.Equal(t, 0, Execute())
+		assert.NotContains(t, buf.String(), "forecast")
+	})
+}
+
+func TestRenderForecast_NoMetrics(t *testing.T) {
+	fc := task.BuildForecast([]*task.ParsedTaskSpec{{ID: "T-001"}}, nil, nil, nil)
+	out := renderForecast(fc)
+	assert.Contains(t, out, "No completed tasks with recorded metrics yet")
+	assert.Contains(t, out, "All Tasks")
+	assert.Contains(t, out, "(1 unestimated)")
+}
+
+func TestFormatForecastDuration(t *testing.T) {
+	assert.Equal(t, "45s", formatForecastDuration(45*time.Second+300*time.Millisecond))
+	assert.Equal(t, "12m5s", formatForecastDuration(12*time.Minute+5*time.Second))
+	assert.Equal(t, "2h15m", formatForecastDuration(2*time.Hour+15*time.Minute+20*time.Second))
+}
@@ -0,0 +1,542 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/config"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+// taskListFlags holds the flag values for the task list command.
+type taskListFlags struct {
+	Phase  int    // --phase <id>, 0 means all phases
+	Status string // --status <status>, empty means any
+	Label  string // --label <label>, empty means any
+	JSON   bool   // --json for structured output
+}
+
+// taskShowFlags holds the flag values for the task show command.
+type taskShowFlags struct {
+	JSON bool // --json for structured output
+}
+
+// taskHistoryFlags holds the flag values for the task history command.
+type taskHistoryFlags struct {
+	JSON bool // --json for structured output
+}
+
+// taskOutput is the JSON output type for a single task.
+type taskOutput struct {
+	ID           string          `json:"id"`
+	Title        string          `json:"title"`
+	Status       task.TaskStatus `json:"status"`
+	Phase        int             `json:"phase,omitempty"`
+	Priority     string          `json:"priority,omitempty"`
+	Effort       string          `json:"effort,omitempty"`
+	Dependencies []string        `json:"dependencies"`
+	Labels       []string        `json:"labels,omitempty"`
+	Agent        string          `json:"agent,omitempty"`
+	Updated      string          `json:"updated,omitempty"`
+	Notes        string          `json:"notes,omitempty"`
+	ClaimedBy    string          `json:"claimed_by,omitempty"`
+	LeaseExpiry  string          `json:"lease_expiry,omitempty"`
+	Ready        bool            `json:"ready"`
+	SpecFile     string          `json:"spec_file"`
+}
+
+// taskDetailOutput is the JSON output type for "raven task show".
+type taskDetailOutput struct {
+	taskOutput
+	// DependencyStatus maps each dependency to its current status.
+	DependencyStatus map[string]task.TaskStatus `json:"dependency_status"`
+	// Dependents are the tasks that list this task as a dependency.
+	Dependents []string `json:"dependents"`
+}
+
+// taskEnv bundles the task specs, state, and phases loaded from the resolved
+// configuration for the task subcommands.
+type taskEnv struct {
+	cfg    *config.Config
+	specs  []*task.ParsedTaskSpec
+	state  *task.StateManager
+	phases []task.Phase
+	// membership places specs in phases.
+	membership *task.PhaseMembership
+}
+
+// newTaskCmd creates the "raven task" namespace command. It has no action of
+// its own -- it groups the per-task subcommands.
+func newTaskCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "task",
+		Aliases: []string{"tasks"},
+		Short:   "Inspect and manage individual tasks",
+		Long: `Inspect and manage individual tasks and their recorded state.
+
+These commands edit the task state file through Raven, so every change is
+validated and recorded in the task history -- use them instead of editing
+task-state.conf by hand.`,
+		// RunE shows help when invoked with no subcommand.
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newTaskListCmd())
+	cmd.AddCommand(newTaskShowCmd())
+	cmd.AddCommand(newTaskHistoryCmd())
+	cmd.AddCommand(newTaskSetStatusCmd())
+	cmd.AddCommand(newTaskResetCmd())
+	cmd.AddCommand(newTaskSkipCmd())
+	cmd.AddCommand(newTaskUnblockCmd())
+	cmd.AddCommand(newTaskNoteCmd())
+	cmd.AddCommand(newTaskNewCmd())
+	cmd.AddCommand(newTaskLintCmd())
+	cmd.AddCommand(newTaskExportCmd())
+	cmd.AddCommand(newTaskImportCmd())
+	return cmd
+}
+
+// newTaskListCmd creates the "raven task list" command.
+func newTaskListCmd() *cobra.Command {
+	var flags taskListFlags
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List tasks with their current status",
+		Long: `List every task spec with its status, phase, and title. Filter by phase,
+status, or label.`,
+		Example: `  # All tasks
+  raven task list
+
+  # Blocked tasks in phase 2
+  raven task list --phase 2 --status blocked
+
+  # Structured output
+  raven task list --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskList(cmd, flags)
+		},
+	}
+
+	cmd.Flags().IntVar(&flags.Phase, "phase", 0, "Filter to a single phase (0 = all phases)")
+	cmd.Flags().StringVar(&flags.Status, "status", "", "Filter by status (not_started, in_progress, completed, blocked, skipped)")
+	cmd.Flags().StringVar(&flags.Label, "label", "", "Filter by front matter label")
+	cmd.Flags().BoolVar(&flags.JSON, "json", false, "Output structured JSON to stdout")
+
+	_ = cmd.RegisterFlagCompletionFunc("status", completeTaskStatuses)
+
+	return cmd
+}
+
+// newTaskShowCmd creates the "raven task show" command.
+func newTaskShowCmd() *cobra.Command {
+	var flags taskShowFlags
+
+	cmd := &cobra.Command{
+		Use:   "show <task-id>",
+		Short: "Show a task's spec metadata and state",
+		Long: `Show a task's spec metadata (title, priority, effort, dependencies,
+labels), its current state, the status of each dependency, and the tasks that
+depend on it.`,
+		Example: `  raven task show T-012
+  raven task show T-012 --json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskShow(cmd, args[0], flags)
+		},
+	}
+
+	cmd.Flags().BoolVar(&flags.JSON, "json", false, "Output structured JSON to stdout")
+
+	return cmd
+}
+
+// newTaskHistoryCmd creates the "raven task history" command.
+func newTaskHistoryCmd() *cobra.Command {
+	var flags taskHistoryFlags
+
+	cmd := &cobra.Command{
+		Use:   "history <task-id>",
+		Short: "Show the recorded state changes of a task",
+		Long: `Show every recorded state change of a task, oldest first: when it
+happened, the previous and new status, the agent, the workflow run, and any
+notes.
+
+State changes are appended to the task state history file (the task state
+file with a ".history.jsonl" extension) whenever Raven updates a task.`,
+		Example: `  # Show how T-012 got to its current state
+  raven task history T-012
+
+  # Structured output for scripting
+  raven task history T-012 --json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskHistory(cmd, args[0], flags)
+		},
+	}
+
+	cmd.Flags().BoolVar(&flags.JSON, "json", false, "Output structured JSON to stdout")
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newTaskCmd())
+}
+
+// loadTaskEnv loads the resolved configuration, discovers task specs, and
+// loads phases. A missing phases.conf is not an error.
+func loadTaskEnv() (*taskEnv, error) {
+	resolved, _, err := loadAndResolveConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	cfg := resolved.Config
+
+	specs, err := task.DiscoverTasks(cfg.Project.TasksDir)
+	if err != nil {
+		return nil, fmt.Errorf("discovering tasks: %w", err)
+	}
+
+	var phases []task.Phase
+	if cfg.Project.PhasesConf != "" {
+		phases, err = task.LoadPhases(cfg.Project.PhasesConf, cfg.Project.TaskIDPrefix)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("loading phases: %w", err)
+		}
+	}
+
+	return &taskEnv{
+		cfg:    cfg,
+		specs:  specs,
+		state:  task.NewStateManager(cfg.Project.TaskStateFile),
+		phases: phases,
+
+		membership: task.NewPhaseMembership(phases, specs),
+	}, nil
+}
+
+// spec returns the spec for taskID, or an error when the ID is malformed or
+// has no spec file.
+func (e *taskEnv) spec(taskID string) (*task.ParsedTaskSpec, error) {
+	if !task.IsTaskID(taskID) {
+		return nil, fmt.Errorf("invalid task ID %q", taskID)
+	}
+	for _, s := range e.specs {
+		if s.ID == taskID {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("task %s not found in %s", taskID, e.cfg.Project.TasksDir)
+}
+
+// taskOutputFor builds the output row for spec from the state snapshot.
+func (e *taskEnv) taskOutputFor(spec *task.ParsedTaskSpec, stateMap map[string]*task.TaskState) taskOutput {
+	out := taskOutput{
+		ID:           spec.ID,
+		Title:        spec.Title,
+		Status:       task.StatusNotStarted,
+		Priority:     spec.Priority,
+		Effort:       spec.Effort,
+		Dependencies: spec.Dependencies,
+		Labels:       spec.Labels,
+		SpecFile:     spec.SpecFile,
+	}
+	if ph := e.membership.PhaseOf(spec.ID); ph != nil {
+		out.Phase = ph.ID
+	}
+	if ts, ok := stateMap[spec.ID]; ok {
+		out.Status = ts.Status
+		out.Agent = ts.Agent
+		out.Notes = ts.Notes
+		if !ts.Timestamp.IsZero() {
+			out.Updated = ts.Timestamp.UTC().Format(time.RFC3339)
+		}
+		if ts.ClaimedBy != "" {
+			out.ClaimedBy = ts.ClaimedBy
+			out.LeaseExpiry = ts.LeaseExpiry.UTC().Format(time.RFC3339)
+		}
+	}
+	if out.Status == task.StatusNotStarted {
+		out.Ready = task.DependenciesMet(spec, stateMap)
+	}
+	return out
+}
+
+// runTaskList is the RunE function of the task list command.
+func runTaskList(cmd *cobra.Command, flags taskListFlags) error {
+	if flags.Status != "" && !task.ValidStatus(task.TaskStatus(flags.Status)) {
+		return fmt.Errorf("invalid --status %q: must be one of %s", flags.Status, statusList())
+	}
+
+	env, err := loadTaskEnv()
+	if err != nil {
+		return err
+	}
+	if flags.Phase != 0 && task.PhaseByID(env.phases, flags.Phase) == nil {
+		return fmt.Errorf("phase %d not found", flags.Phase)
+	}
+
+	stateMap, err := env.state.LoadMap()
+	if err != nil {
+		return fmt.Errorf("loading task state: %w", err)
+	}
+
+	rows := make([]taskOutput, 0, len(env.specs))
+	for _, spec := range env.specs {
+		if flags.Label != "" && !spec.HasLabel(flags.Label) {
+			continue
+		}
+		row := env.taskOutputFor(spec, stateMap)
+		if flags.Phase != 0 && row.Phase != flags.Phase {
+			continue
+		}
+		if flags.Status != "" && row.Status != task.TaskStatus(flags.Status) {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	if flags.JSON {
+		return writeJSON(cmd.OutOrStdout(), rows)
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(cmd.ErrOrStderr(), "No matching tasks.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tPHASE\tPRIORITY\tTITLE")
+	for _, r := range rows {
+		status := string(r.Status)
+		if r.Ready {
+			status += " (ready)"
+		}
+		phase := "-"
+		if r.Phase != 0 {
+			phase = fmt.Sprint(r.Phase)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.ID, status, phase, orDash(r.Priority), r.Title)
+	}
+	return tw.Flush()
+}
+
+// runTaskShow is the RunE function of the task show command.
+func runTaskShow(cmd *cobra.Command, taskID string, flags taskShowFlags) error {
+	env, err := loadTaskEnv()
+	if err != nil {
+		return err
+	}
+	spec, err := env.spec(taskID)
+	if err != nil {
+		return err
+	}
+	stateMap, err := env.state.LoadMap()
+	if err != nil {
+		return fmt.Errorf("loading task state: %w", err)
+	}
+
+	detail := taskDetailOutput{
+		taskOutput:       env.taskOutputFor(spec, stateMap),
+		DependencyStatus: make(map[string]task.TaskStatus, len(spec.Dependencies)),
+		Dependents:       []string{},
+	}
+	for _, dep := range spec.Dependencies {
+		status := task.StatusNotStarted
+		if ts, ok := stateMap[dep]; ok {
+			status = ts.Status
+		}
+		detail.DependencyStatus[dep] = status
+	}
+	for _, s := range env.specs {
+		for _, dep := range s.Dependencies {
+			if dep == spec.ID {
+				detail.Dependents = append(detail.Dependents, s.ID)
+				break
+			}
+		}
+	}
+
+	if flags.JSON {
+		return writeJSON(cmd.OutOrStdout(), detail)
+	}
+	renderTaskDetail(cmd.OutOrStdout(), detail)
+	return nil
+}
+
+// renderTaskDetail writes a human-readable view of a task to w.
+func renderTaskDetail(w io.Writer, d taskDetailOutput) {
+	fmt.Fprintf(w, "%s: %s\n", d.ID, d.Title)
+	fmt.Fprintf(w, "  Status:     %s", d.Status)
+	if d.Ready {
+		fmt.Fprint(w, " (ready)")
+	}
+	fmt.Fprintln(w)
+	if d.Phase != 0 {
+		fmt.Fprintf(w, "  Phase:      %d\n", d.Phase)
+	}
+	fmt.Fprintf(w, "  Priority:   %s\n", orDash(d.Priority))
+	fmt.Fprintf(w, "  Effort:     %s\n", orDash(d.Effort))
+	if len(d.Labels) > 0 {
+		fmt.Fprintf(w, "  Labels:     %s\n", strings.Join(d.Labels, ", "))
+	}
+	if d.Agent != "" {
+		fmt.Fprintf(w, "  Agent:      %s\n", d.Agent)
+	}
+	if d.Updated != "" {
+		fmt.Fprintf(w, "  Updated:    %s\n", d.Updated)
+	}
+	if d.ClaimedBy != "" {
+		fmt.Fprintf(w, "  Claimed by: %s (lease until %s)\n", d.ClaimedBy, d.LeaseExpiry)
+	}
+	if d.Notes != "" {
+		fmt.Fprintf(w, "  Notes:      %s\n", d.Notes)
+	}
+	fmt.Fprintf(w, "  Spec:       %s\n", d.SpecFile)
+
+	if len(d.Dependencies) == 0 {
+		fmt.Fprintln(w, "  Depends on: none")
+	} else {
+		fmt.Fprintln(w, "  Depends on:")
+		for _, dep := range d.Dependencies {
+			fmt.Fprintf(w, "    %s  %s\n", dep, d.DependencyStatus[dep])
+		}
+	}
+	if len(d.Dependents) == 0 {
+		fmt.Fprintln(w, "  Blocks:     none")
+	} else {
+		fmt.Fprintf(w, "  Blocks:     %s\n", strings.Join(d.Dependents, ", "))
+	}
+}
+
+// runTaskHistory is the RunE function of the task history command.
+func runTaskHistory(cmd *cobra.Command, taskID string, flags taskHistoryFlags) error {
+	if !task.IsTaskID(taskID) {
+		return fmt.Errorf("invalid task ID %q", taskID)
+	}
+
+	resolved, _, err := loadAndResolveConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	cfg := resolved.Config
+
+	stateManager := task.NewStateManager(cfg.Project.TaskStateFile)
+	history, err := stateManager.History(taskID)
+	if err != nil {
+		return fmt.Errorf("loading history for %s: %w", taskID, err)
+	}
+
+	if flags.JSON {
+		return writeJSON(cmd.OutOrStdout(), history)
+	}
+
+	if len(history) == 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "No recorded history for %s.\n", taskID)
+		return nil
+	}
+	return renderTaskHistory(cmd.OutOrStdout(), taskID, history)
+}
+
+// renderTaskHistory writes history as an aligned table followed by a one-line
+// summary.
+//
+//	TIME                  FROM         TO           AGENT   RUN    NOTES
+//	2026-03-01 09:00:00Z  -            in_progress  claude  run-1
+//
+//	T-012: 3 changes, 2 attempts, now completed
+func renderTaskHistory(w io.Writer, taskID string, history []task.HistoryEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tFROM\tTO\tAGENT\tRUN\tNOTES")
+	attempts := 0
+	for _, e := range history {
+		if e.Status == task.StatusInProgress && e.PreviousStatus != task.StatusInProgress {
+			attempts++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Timestamp.UTC().Format("2006-01-02 15:04:05Z"),
+			orDash(string(e.PreviousStatus)),
+			e.Status,
+			orDash(e.Agent),
+			orDash(e.RunID),
+			strings.ReplaceAll(e.Notes, "\n", " "),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("writing history: %w", err)
+	}
+
+	last := history[len(history)-1]
+	_, err := fmt.Fprintf(w, "\n%s: %d changes, %d attempts, now %s (since %s)\n",
+		taskID, len(history), attempts, last.Status, last.Timestamp.UTC().Format(time.RFC3339))
+	return err
+}
+
+// completeTaskIDs provides shell completion of task IDs (with titles as
+// descriptions) for the first positional argument.
+func completeTaskIDs(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	resolved, _, err := loadAndResolveConfig()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	specs, err := task.DiscoverTasks(resolved.Config.Project.TasksDir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	out := make([]string, 0, len(specs))
+	for _, s := range specs {
+		out = append(out, s.ID+"\t"+s.Title)
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTaskStatuses provides shell completion of task status values.
+func completeTaskStatuses(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	statuses := task.ValidStatuses()
+	out := make([]string, len(statuses))
+	for i, s := range statuses {
+		out[i] = string(s)
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// statusList returns the valid task statuses as a comma-separated string.
+func statusList() string {
+	statuses := task.ValidStatuses()
+	names := make([]string, len(statuses))
+	for i, s := range statuses {
+		names[i] = string(s)
+	}
+	return strings.Join(names, ", ")
+}
+
+// writeJSON writes v to w as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// orDash returns s, or "-" when s is empty, for table cells.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+// taskEditFlags holds the flag values shared by the task state editing
+// commands (set-status, reset, skip, unblock, note).
+type taskEditFlags struct {
+	JSON   bool   // --json for structured output
+	Agent  string // --agent recorded with the new state (set-status only)
+	Note   string // --note / --reason replacing the task notes, when set
+	Append bool   // --append adds to the existing notes (note only)
+}
+
+// taskEdit describes a state change applied by runTaskEdit.
+type taskEdit struct {
+	// name identifies the command in the task history run ID, e.g. "reset".
+	name string
+	// apply performs the change given the task's current status.
+	apply func(sm *task.StateManager, current *task.TaskState) error
+}
+
+// newTaskSetStatusCmd creates the "raven task set-status" command.
+func newTaskSetStatusCmd() *cobra.Command {
+	var flags taskEditFlags
+
+	cmd := &cobra.Command{
+		Use:   "set-status <task-id> <status>",
+		Short: "Set a task's status",
+		Long: `Set a task's status to one of: not_started, in_progress, completed,
+blocked, skipped. Existing notes are kept unless --note is given.`,
+		Example: `  raven task set-status T-012 completed
+  raven task set-status T-012 blocked --note "waiting on API keys"`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeTaskIDThenStatus,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status := task.TaskStatus(args[1])
+			if !task.ValidStatus(status) {
+				return fmt.Errorf("invalid status %q: must be one of %s", args[1], statusList())
+			}
+			return runTaskEdit(cmd, args[0], flags, taskEdit{
+				name: "set-status",
+				apply: func(sm *task.StateManager, _ *task.TaskState) error {
+					if err := sm.UpdateStatus(args[0], status, flags.Agent); err != nil {
+						return err
+					}
+					if cmd.Flags().Changed("note") {
+						return sm.UpdateNotes(args[0], flags.Note)
+					}
+					return nil
+				},
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.Agent, "agent", "", "Agent to record with the new status")
+	cmd.Flags().StringVar(&flags.Note, "note", "", "Replace the task notes")
+	cmd.Flags().BoolVar(&flags.JSON, "json", false, "Output the updated task as JSON")
+
+	return cmd
+}
+
+// newTaskResetCmd creates the "raven task reset" command.
+func newTaskResetCmd() *cobra.Command {
+	var flags taskEditFlags
+
+	cmd := &cobra.Command{
+		Use:   "reset <task-id>",
+		Short: "Reset a task to not_started",
+		Long: `Reset a task to not_started and clear its agent so the implementation
+loop picks it up again. Notes are kept.`,
+		Example:           `  raven task reset T-012`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskEdit(cmd, args[0], flags, taskEdit{
+				name: "reset",
+				apply: func(sm *task.StateManager, _ *task.TaskState) error {
+					return sm.UpdateStatus(args[0], task.StatusNotStarted, "")
+				},
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&flags.JSON, "json", false, "Output the updated task as JSON")
+
+	return cmd
+}
+
+// newTaskSkipCmd creates the "raven task skip" command.
+func newTaskSkipCmd() *cobra.Command {
+	var flags taskEditFlags
+
+	cmd := &cobra.Command{
+		Use:   "skip <task-id>",
+		Short: "Mark a task as skipped",
+		Long: `Mark a task as skipped. Skipped tasks count as done for phase progress
+but do not satisfy the dependencies of other tasks.`,
+		Example:           `  raven task skip T-012 --reason "superseded by T-020"`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskEdit(cmd, args[0], flags, taskEdit{
+				name: "skip",
+				apply: func(sm *task.StateManager, current *task.TaskState) error {
+					if err := sm.UpdateStatus(args[0], task.StatusSkipped, current.Agent); err != nil {
+						return err
+					}
+					if flags.Note != "" {
+						return sm.UpdateNotes(args[0], flags.Note)
+					}
+					return nil
+				},
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.Note, "reason", "", "Record why the task was skipped in its notes")
+	cmd.Flags().BoolVar(&flags.JSON, "json", false, "Output the updated task as JSON")
+
+	return cmd
+}
+
+// newTaskUnblockCmd creates the "raven task unblock" command.
+func newTaskUnblockCmd() *cobra.Command {
+	var flags taskEditFlags
+
+	cmd := &cobra.Command{
+		Use:   "unblock <task-id>",
+		Short: "Return a blocked task to not_started",
+		Long: `Return a blocked task to not_started so it can be selected again. Fails
+when the task is not blocked.`,
+		Example:           `  raven task unblock T-012`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskEdit(cmd, args[0], flags, taskEdit{
+				name: "unblock",
+				apply: func(sm *task.StateManager, current *task.TaskState) error {
+					if current.Status != task.StatusBlocked {
+						return fmt.Errorf("task %s is not blocked (status: %s)", args[0], current.Status)
+					}
+					return sm.UpdateStatus(args[0], task.StatusNotStarted, current.Agent)
+				},
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&flags.JSON, "json", false, "Output the updated task as JSON")
+
+	return cmd
+}
+
+// newTaskNoteCmd creates the "raven task note" command.
+func newTaskNoteCmd() *cobra.Command {
+	var flags taskEditFlags
+
+	cmd := &cobra.Command{
+		Use:   "note <task-id> <text>...",
+		Short: "Set or append to a task's notes",
+		Long: `Replace a task's notes with the given text, or add to them with --append.
+An empty text ("") clears the notes. The status and agent are unchanged.`,
+		Example: `  raven task note T-012 "flaky test in CI, rerun before review"
+  raven task note T-012 --append "fixed upstream in v1.2"
+  raven task note T-012 ""`,
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			text := strings.Join(args[1:], " ")
+			return runTaskEdit(cmd, args[0], flags, taskEdit{
+				name: "note",
+				apply: func(sm *task.StateManager, current *task.TaskState) error {
+					notes := text
+					if flags.Append && current.Notes != "" && text != "" {
+						notes = current.Notes + "; " + text
+					} else if flags.Append && text == "" {
+						notes = current.Notes
+					}
+					return sm.UpdateNotes(args[0], notes)
+				},
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&flags.Append, "append", false, "Append to the existing notes instead of replacing them")
+	cmd.Flags().BoolVar(&flags.JSON, "json", false, "Output the updated task as JSON")
+
+	return cmd
+}
+
+// runTaskEdit loads the task environment, verifies taskID has a spec, applies
+// edit, and reports the resulting state. Changes are recorded in the task
+// history under the run ID "task-<edit.name>".
+func runTaskEdit(cmd *cobra.Command, taskID string, flags taskEditFlags, edit taskEdit) error {
+	env, err := loadTaskEnv()
+	if err != nil {
+		return err
+	}
+	spec, err := env.spec(taskID)
+	if err != nil {
+		return err
+	}
+
+	current, err := env.state.Get(taskID)
+	if err != nil {
+		return fmt.Errorf("loading state for %s: %w", taskID, err)
+	}
+	if current == nil {
+		current = &task.TaskState{TaskID: taskID, Status: task.StatusNotStarted}
+	}
+
+	env.state.SetRunID("task-" + edit.name)
+	if err := edit.apply(env.state, current); err != nil {
+		return fmt.Errorf("%s %s: %w", edit.name, taskID, err)
+	}
+
+	stateMap, err := env.state.LoadMap()
+	if err != nil {
+		return fmt.Errorf("loading task state: %w", err)
+	}
+	out := env.taskOutputFor(spec, stateMap)
+
+	if flags.JSON {
+		return writeJSON(cmd.OutOrStdout(), out)
+	}
+	if current.Status == out.Status {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", taskID, out.Status)
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: %s -> %s\n", taskID, current.Status, out.Status)
+	}
+	if out.Notes != "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "  Notes: %s\n", out.Notes)
+	}
+	return nil
+}
+
+// completeTaskIDThenStatus completes a task ID for the first argument and a
+// status for the second.
+func completeTaskIDThenStatus(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeTaskIDs(cmd, args, toComplete)
+	case 1:
+		return completeTaskStatuses(cmd, args, toComplete)
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
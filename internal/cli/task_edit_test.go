@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+// loadTaskState returns the state of taskID in the project at tomlPath.
+func loadTaskState(t *testing.T, tomlPath, taskID string) *task.TaskState {
+	t.Helper()
+	ts, err := task.NewStateManager(filepath.Join(filepath.Dir(tomlPath), "task-state.conf")).Get(taskID)
+	require.NoError(t, err)
+	require.NotNil(t, ts)
+	return ts
+}
+
+func TestTaskSetStatusCmd(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+
+	code, out, stderr := executeTask(t, tomlPath, "set-status", "T-003", "in_progress", "--agent", "codex", "--note", "started by hand")
+	require.Equal(t, 0, code, stderr)
+	assert.Equal(t, "T-003: not_started -> in_progress\n  Notes: started by hand\n", out)
+
+	ts := loadTaskState(t, tomlPath, "T-003")
+	assert.Equal(t, task.StatusInProgress, ts.Status)
+	assert.Equal(t, "codex", ts.Agent)
+	assert.Equal(t, "started by hand", ts.Notes)
+}
+
+func TestTaskSetStatusCmd_KeepsNotes(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+
+	code, out, stderr := executeTask(t, tomlPath, "set-status", "T-002", "in_progress", "--json")
+	require.Equal(t, 0, code, stderr)
+
+	var row taskOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &row))
+	assert.Equal(t, task.StatusInProgress, row.Status)
+	assert.Equal(t, "waiting on keys", row.Notes)
+}
+
+func TestTaskEditCmds_RecordHistory(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+
+	code, _, stderr := executeTask(t, tomlPath, "unblock", "T-002")
+	require.Equal(t, 0, code, stderr)
+
+	statePath := filepath.Join(filepath.Dir(tomlPath), "task-state.conf")
+	history, err := task.NewStateManager(statePath).History("T-002")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, task.StatusBlocked, history[0].PreviousStatus)
+	assert.Equal(t, task.StatusNotStarted, history[0].Status)
+	assert.Equal(t, "task-unblock", history[0].RunID)
+}
+
+func TestTaskResetCmd(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+
+	code, out, stderr := executeTask(t, tomlPath, "reset", "T-001")
+	require.Equal(t, 0, code, stderr)
+	assert.Equal(t, "T-001: completed -> not_started\n", out)
+
+	ts := loadTaskState(t, tomlPath, "T-001")
+	assert.Equal(t, task.StatusNotStarted, ts.Status)
+	assert.Empty(t, ts.Agent)
+}
+
+func TestTaskSkipCmd(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+
+	code, _, stderr := executeTask(t, tomlPath, "skip", "T-003", "--reason", "superseded")
+	require.Equal(t, 0, code, stderr)
+
+	ts := loadTaskState(t, tomlPath, "T-003")
+	assert.Equal(t, task.StatusSkipped, ts.Status)
+	assert.Equal(t, "superseded", ts.Notes)
+}
+
+func TestTaskNoteCmd(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "replace", args: []string{"note", "T-002", "keys", "arrived"}, want: "keys arrived"},
+		{name: "append", args: []string{"note", "T-002", "--append", "ETA friday"}, want: "waiting on keys; ETA friday"},
+		{name: "clear", args: []string{"note", "T-002", ""}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tomlPath, _ := writeTaskProject(t)
+
+			code, _, stderr := executeTask(t, tomlPath, tt.args...)
+			require.Equal(t, 0, code, stderr)
+
+			ts := loadTaskState(t, tomlPath, "T-002")
+			assert.Equal(t, task.StatusBlocked, ts.Status)
+			assert.Equal(t, tt.want, ts.Notes)
+		})
+	}
+}
+
+func TestTaskEditCmds_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr string
+	}{
+		{
+			name: "unblock requires blocked",
+			run: func() error {
+				cmd := newTaskUnblockCmd()
+				return cmd.RunE(cmd, []string{"T-001"})
+			},
+			wantErr: "task T-001 is not blocked (status: completed)",
+		},
+		{
+			name: "invalid status",
+			run: func() error {
+				cmd := newTaskSetStatusCmd()
+				return cmd.RunE(cmd, []string{"T-001", "done"})
+			},
+			wantErr: `invalid status "done"`,
+		},
+		{
+			name: "unknown task",
+			run: func() error {
+				cmd := newTaskResetCmd()
+				return cmd.RunE(cmd, []string{"T-099"})
+			},
+			wantErr: "task T-099 not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tomlPath, _ := writeTaskProject(t)
+			resetTaskFlags(t)
+			flagConfig = tomlPath
+
+			err := tt.run()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
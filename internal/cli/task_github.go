@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/review"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+// taskSyncTargets lists the issue trackers "raven task export" and
+// "raven task import" support.
+var taskSyncTargets = []string{"github"}
+
+// taskExportFlags holds the flag values for the task export command.
+type taskExportFlags struct {
+	To    string // --to
+	Phase int    // --phase; 0 exports every task
+	JSON  bool   // --json for structured output
+}
+
+// taskImportFlags holds the flag values for the task import command.
+type taskImportFlags struct {
+	From  string // --from
+	Label string // --label
+	State string // --state
+	Limit int    // --limit
+	JSON  bool   // --json for structured output
+}
+
+// newTaskExportCmd creates the "raven task export" command.
+func newTaskExportCmd() *cobra.Command {
+	var flags taskExportFlags
+
+	cmd := &cobra.Command{
+		Use:   "export [task-id...]",
+		Short: "Export task specs as GitHub issues",
+		Long: `Create a GitHub issue for every task spec, or update the issue a spec is
+already linked to. The issue title and body come from the spec; the issue is
+labelled with the task's phase (phase:<id>), its priority (priority:<value>),
+and the spec's own labels. Missing labels are created. Dependencies are listed
+as references to the dependencies' issues.
+
+Tasks are exported in dependency order. The number of each created issue is
+written to the spec's front matter as github_issue, so later exports update
+the same issue.
+
+Requires the gh CLI, authenticated for the current repository. With the
+global --dry-run flag the issues are printed instead of sent.`,
+		Example: `  # Export every task
+  raven task export --to github
+
+  # Export one phase, showing what would be sent
+  raven task export --to github --phase 2 --dry-run
+
+  # Export specific tasks
+  raven task export --to github T-004 T-005`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskExport(cmd, args, flags)
+		},
+		ValidArgsFunction: completeTaskIDs,
+	}
+
+	cmd.Flags().StringVar(&flags.To, "to", "", "Issue tracker to export to (github)")
+	cmd.Flags().IntVar(&flags.Phase, "phase", 0, "Only export tasks in this phase")
+	cmd.Flags().BoolVar(&flags.JSON, "json", false, "Output the exported issues as JSON")
+	_ = cmd.MarkFlagRequired("to")
+	_ = cmd.RegisterFlagCompletionFunc("to", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return taskSyncTargets, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// newTaskImportCmd creates the "raven task import" command.
+func newTaskImportCmd() *cobra.Command {
+	var flags taskImportFlags
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Create task specs from labelled GitHub issues",
+		Long: `Create a task spec for every GitHub issue carrying the import label that is
+not yet linked to a task, and add it to the task state file as not_started.
+
+The spec takes the issue title, its body as the goal, and its checklist items
+as acceptance criteria. A priority:<value> label sets the priority; other
+labels except the import label and phase:<id> labels become spec labels, so
+label-based phases pick the task up. Issue references on a "Depends on:" line
+become dependencies when the referenced issue belongs to a task. The issue
+number is stored in the spec as github_issue.
+
+Requires the gh CLI, authenticated for the current repository. With the
+global --dry-run flag the specs are listed instead of written.`,
+		Example: `  # Import open issues labelled "raven"
+  raven task import --from github
+
+  # Import every issue labelled "backlog"
+  raven task import --from github --label backlog --state all`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskImport(cmd, flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.From, "from", "", "Issue tracker to import from (github)")
+	cmd.Flags().StringVar(&flags.Label, "label", "raven", "Only import issues with this label")
+	cmd.Flags().StringVar(&flags.State, "state", "open", "Issue state: open, closed, or all")
+	cmd.Flags().IntVar(&flags.Limit, "limit", 100, "Maximum number of issues to fetch")
+	cmd.Flags().BoolVar(&flags.JSON, "json", false, "Output the created tasks as JSON")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.RegisterFlagCompletionFunc("from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return taskSyncTargets, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("state", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"open", "closed", "all"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// runTaskExport is the RunE function of the task export command.
+func runTaskExport(cmd *cobra.Command, args []string, flags taskExportFlags) error {
+	if !slices.Contains(taskSyncTargets, flags.To) {
+		return fmt.Errorf("unsupported export target %q: must be github", flags.To)
+	}
+	env, err := loadTaskEnv()
+	if err != nil {
+		return err
+	}
+	if flags.Phase != 0 && env.membership.Phase(flags.Phase) == nil {
+		return fmt.Errorf("phase %d not found", flags.Phase)
+	}
+
+	var specs []*task.ParsedTaskSpec
+	if len(args) > 0 {
+		for _, id := range args {
+			spec, err := env.spec(id)
+			if err != nil {
+				return err
+			}
+			specs = append(specs, spec)
+		}
+	} else {
+		for _, spec := range env.specs {
+			if flags.Phase == 0 || env.membership.Contains(flags.Phase, spec.ID) {
+				specs = append(specs, spec)
+			}
+		}
+	}
+	if len(specs) == 0 {
+		fmt.Fprintln(cmd.ErrOrStderr(), "No tasks to export.")
+		return nil
+	}
+
+	syncer := task.NewIssueSyncer(review.NewCLIRunner("."), env.phases, env.specs)
+	exports, err := syncer.Export(cmd.Context(), specs, env.specs, flagDryRun)
+	if flags.JSON && exports != nil {
+		if jsonErr := writeJSON(cmd.OutOrStdout(), exports); jsonErr != nil && err == nil {
+			err = jsonErr
+		}
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	for _, exp := range exports {
+		switch {
+		case flagDryRun && exp.Action == "create":
+			fmt.Fprintf(w, "Would create issue for %s: %s\n", exp.TaskID, exp.Title)
+		case flagDryRun:
+			fmt.Fprintf(w, "Would update #%d for %s: %s\n", exp.Number, exp.TaskID, exp.Title)
+		case exp.Action == "create":
+			fmt.Fprintf(w, "Created #%d for %s\n", exp.Number, exp.TaskID)
+		default:
+			fmt.Fprintf(w, "Updated #%d for %s\n", exp.Number, exp.TaskID)
+		}
+		if flagDryRun && len(exp.Labels) > 0 {
+			fmt.Fprintf(w, "  Labels: %s\n", strings.Join(exp.Labels, ", "))
+		}
+	}
+	return err
+}
+
+// runTaskImport is the RunE function of the task import command.
+func runTaskImport(cmd *cobra.Command, flags taskImportFlags) error {
+	if !slices.Contains(taskSyncTargets, flags.From) {
+		return fmt.Errorf("unsupported import source %q: must be github", flags.From)
+	}
+	if !slices.Contains([]string{"open", "closed", "all"}, flags.State) {
+		return fmt.Errorf("invalid --state %q: must be open, closed, or all", flags.State)
+	}
+	if flags.Limit <= 0 {
+		return fmt.Errorf("--limit must be positive, got %d", flags.Limit)
+	}
+	env, err := loadTaskEnv()
+	if err != nil {
+		return err
+	}
+
+	syncer := task.NewIssueSyncer(review.NewCLIRunner("."), env.phases, env.specs)
+	issues, err := syncer.ListIssues(cmd.Context(), flags.Label, flags.State, flags.Limit)
+	if err != nil {
+		return err
+	}
+	drafts := task.IssueDrafts(issues, env.specs, env.cfg.Project.TaskIDPrefix, flags.Label)
+
+	outputs := make([]taskOutput, 0, len(drafts))
+	for _, draft := range drafts {
+		if !slices.Contains(taskPriorities, draft.Priority) {
+			draft.Priority = "should-have"
+		}
+		if flagDryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "Would create %s from #%d: %s\n", draft.ID, draft.GitHubIssue, draft.Title)
+			continue
+		}
+
+		path, err := writeTaskSpec(env.cfg.Project.TasksDir, draft)
+		if err != nil {
+			return err
+		}
+		if err := env.state.Initialize([]string{draft.ID}); err != nil {
+			return fmt.Errorf("adding %s to task state: %w", draft.ID, err)
+		}
+		spec, err := task.ParseTaskFile(path)
+		if err != nil {
+			return fmt.Errorf("reading back %s: %w", path, err)
+		}
+		env.specs = append(env.specs, spec)
+		if !flags.JSON {
+			fmt.Fprintf(cmd.OutOrStdout(), "Created %s from #%d: %s\n  Spec: %s\n", draft.ID, draft.GitHubIssue, spec.Title, path)
+		}
+	}
+	if flagDryRun {
+		return nil
+	}
+
+	env.membership = task.NewPhaseMembership(env.phases, env.specs)
+	stateMap, err := env.state.LoadMap()
+	if err != nil {
+		return fmt.Errorf("loading task state: %w", err)
+	}
+	for _, draft := range drafts {
+		spec, err := env.spec(draft.ID)
+		if err != nil {
+			return err
+		}
+		outputs = append(outputs, env.taskOutputFor(spec, stateMap))
+	}
+	if flags.JSON {
+		return writeJSON(cmd.OutOrStdout(), outputs)
+	}
+	if len(drafts) == 0 {
+		fmt.Fprintln(cmd.ErrOrStderr(), "No new issues to import.")
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+// installFakeGH puts a fake gh script on PATH that logs its arguments, one
+// invocation per line, to the returned file. "gh issue create" prints the
+// URL of issue 21, "gh label list" prints no labels, and "gh issue list"
+// prints issuesJSON.
+func installFakeGH(t *testing.T, issuesJSON string) string {
+	t.Helper()
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "gh.log")
+	issuesPath := filepath.Join(dir, "issues.json")
+	require.NoError(t, os.WriteFile(issuesPath, []byte(issuesJSON), 0o644))
+
+	script := `#!/bin/sh
+echo "$*" >> "` + logPath + `"
+case "$1 $2" in
+  "label list") echo '[]' ;;
+  "issue create") echo "https://github.com/o/r/issues/21" ;;
+  "issue list") cat "` + issuesPath + `" ;;
+esac
+`
+	p := filepath.Join(dir, "gh")
+	require.NoError(t, os.WriteFile(p, []byte(script), 0o600))
+	require.NoError(t, os.Chmod(p, 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return logPath
+}
+
+func TestTaskExportCmd(t *testing.T) {
+	tomlPath, tasksDir := writeTaskProject(t)
+	logPath := installFakeGH(t, "[]")
+
+	code, out, stderr := executeTask(t, tomlPath, "export", "--to", "github", "--phase", "2")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, out, "Created #21 for T-003")
+
+	log, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(log), "issue create --title T-003: CLI")
+	assert.Contains(t, string(log), "--label phase:2 --label priority:must-have")
+	assert.NotContains(t, string(log), "T-001")
+
+	spec, err := task.ParseTaskFile(filepath.Join(tasksDir, "T-003-cli.md"))
+	require.NoError(t, err)
+	assert.Equal(t, 21, spec.GitHubIssue)
+}
+
+func TestTaskExportCmd_DryRun(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+	logPath := installFakeGH(t, "[]")
+
+	code, out, stderr := executeTask(t, tomlPath, "export", "--to", "github", "--dry-run", "T-002")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, out, "Would create issue for T-002: T-002: Core")
+	assert.Contains(t, out, "Labels: phase:1, priority:must-have, api")
+	assert.NoFileExists(t, logPath)
+}
+
+func TestTaskExportCmd_UnsupportedTarget(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+
+	code, _, _ := executeTask(t, tomlPath, "export", "--to", "jira")
+	assert.NotEqual(t, 0, code)
+}
+
+func TestTaskImportCmd(t *testing.T) {
+	tomlPath, tasksDir := writeTaskProject(t)
+	logPath := installFakeGH(t, `[{"number":30,"title":"Add export","body":"Export tasks.\n\n- [ ] Issues are created\n","labels":[{"name":"raven"},{"name":"api"}]}]`)
+
+	code, out, stderr := executeTask(t, tomlPath, "import", "--from", "github")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, out, "Created T-004 from #30: Add export")
+
+	log, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(log), "issue list --state open --limit 100")
+	assert.Contains(t, string(log), "--label raven")
+
+	matches, err := filepath.Glob(filepath.Join(tasksDir, "T-004-*.md"))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	spec, err := task.ParseTaskFile(matches[0])
+	require.NoError(t, err)
+	assert.Equal(t, 30, spec.GitHubIssue)
+	assert.Equal(t, []string{"api"}, spec.Labels)
+	assert.True(t, strings.Contains(spec.Content, "- [ ] Issues are created"))
+
+	// Importing again skips the linked issue.
+	code, _, stderr = executeTask(t, tomlPath, "import", "--from", "github")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stderr, "No new issues to import.")
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+// taskLintFlags holds the flag values for the task lint command.
+type taskLintFlags struct {
+	Fix    bool // --fix applies safe normalisations
+	Strict bool // --strict fails on warnings too
+	JSON   bool // --json for structured output
+}
+
+// newTaskLintCmd creates the "raven task lint" command.
+func newTaskLintCmd() *cobra.Command {
+	var flags taskLintFlags
+
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Validate task specs, phases, and task state",
+		Long: `Validate the task specs in the tasks directory together with phases.conf
+and the task state file, reporting every problem with its file and line:
+
+  - spec files that do not parse, or whose name hides them from discovery
+  - duplicate task IDs, and file names that disagree with the spec's ID
+  - dependencies on missing tasks, on the task itself, or in a cycle
+  - malformed, overlapping, or duplicate phase ranges
+  - tasks outside every phase range
+  - state rows with an invalid status, duplicate rows, and rows for tasks
+    that have no spec
+
+--fix applies safe normalisations: renaming misnamed spec files to
+<ID>-<slug>.md, stripping byte-order marks and Windows line endings, and
+dropping duplicate or orphaned state rows. Orphaned rows are kept while any
+spec fails to parse.
+
+Exits non-zero when errors remain (or warnings, with --strict).`,
+		Example: `  raven task lint
+  raven task lint --fix
+  raven task lint --json | jq '.issues[] | select(.severity == "error")'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskLint(cmd, flags)
+		},
+	}
+
+	cmd.Flags().BoolVar(&flags.Fix, "fix", false, "Apply safe normalisations")
+	cmd.Flags().BoolVar(&flags.Strict, "strict", false, "Treat warnings as errors")
+	cmd.Flags().BoolVar(&flags.JSON, "json", false, "Output the report as JSON")
+
+	return cmd
+}
+
+// runTaskLint is the RunE function of the task lint command.
+func runTaskLint(cmd *cobra.Command, flags taskLintFlags) error {
+	resolved, _, err := loadAndResolveConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	cfg := resolved.Config
+
+	report, err := task.Validate(task.ValidateOptions{
+		TasksDir:     cfg.Project.TasksDir,
+		PhasesFile:   cfg.Project.PhasesConf,
+		StateFile:    cfg.Project.TaskStateFile,
+		TaskIDPrefix: cfg.Project.TaskIDPrefix,
+		Fix:          flags.Fix && !flagDryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if flags.JSON {
+		if err := writeJSON(cmd.OutOrStdout(), report); err != nil {
+			return err
+		}
+	} else {
+		for _, issue := range report.Issues {
+			fmt.Fprintln(cmd.OutOrStdout(), issue)
+		}
+		if !flagQuiet {
+			writeTaskLintSummary(cmd, report)
+		}
+	}
+
+	errs, warnings := report.Errors(), report.Warnings()
+	if errs > 0 || (flags.Strict && warnings > 0) {
+		return fmt.Errorf("task lint: %d errors, %d warnings", errs, warnings)
+	}
+	return nil
+}
+
+// writeTaskLintSummary prints the one-line result of a lint run to stderr.
+func writeTaskLintSummary(cmd *cobra.Command, report *task.ValidationReport) {
+	w := cmd.ErrOrStderr()
+	if len(report.Issues) == 0 {
+		fmt.Fprintf(w, "No problems found in %d tasks.\n", report.Tasks)
+		return
+	}
+	fmt.Fprintf(w, "%d tasks: %d errors, %d warnings", report.Tasks, report.Errors(), report.Warnings())
+	if fixed := report.Fixed(); fixed > 0 {
+		fmt.Fprintf(w, ", %d fixed", fixed)
+	} else if fixable := countFixable(report); fixable > 0 {
+		fmt.Fprintf(w, " (%d fixable with --fix)", fixable)
+	}
+	fmt.Fprintln(w)
+}
+
+// countFixable returns the number of unfixed issues that --fix can repair.
+func countFixable(report *task.ValidationReport) int {
+	n := 0
+	for _, i := range report.Issues {
+		if i.Fixable && !i.Fixed {
+			n++
+		}
+	}
+	return n
+}
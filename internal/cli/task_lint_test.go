@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+func TestTaskLintCmd_Clean(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+
+	code, out, stderr := executeTask(t, tomlPath, "lint")
+	require.Equal(t, 0, code, stderr)
+	assert.Empty(t, out)
+	assert.Contains(t, stderr, "No problems found in 3 tasks.")
+}
+
+func TestTaskLintCmd_TasksAlias(t *testing.T) {
+	tomlPath, tasksDir := writeTaskProject(t)
+	require.NoError(t, os.WriteFile(filepath.Join(tasksDir, "T-004-extra.md"),
+		[]byte("# T-004: Extra\n\n| Dependencies | T-042 |\n"), 0o644))
+	resetTaskFlags(t)
+
+	flagConfig = tomlPath
+	err := runTaskLint(newTaskLintCmd(), taskLintFlags{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task lint: 1 errors, 1 warnings")
+
+	// "raven tasks lint" reaches the same command.
+	cmd, _, err := rootCmd.Find([]string{"tasks", "lint"})
+	require.NoError(t, err)
+	assert.Equal(t, "lint", cmd.Name())
+}
+
+func TestTaskLintCmd_JSONAndFix(t *testing.T) {
+	tomlPath, tasksDir := writeTaskProject(t)
+	require.NoError(t, os.Rename(filepath.Join(tasksDir, "T-003-cli.md"), filepath.Join(tasksDir, "cli.md")))
+
+	code, out, _ := executeTask(t, tomlPath, "lint", "--json")
+	require.Equal(t, 0, code, "warnings alone do not fail")
+
+	var report task.ValidationReport
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, task.IssueFilename, report.Issues[0].Code)
+	assert.True(t, report.Issues[0].Fixable)
+
+	code, _, stderr := executeTask(t, tomlPath, "lint", "--strict")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "(1 fixable with --fix)")
+
+	code, out, stderr = executeTask(t, tomlPath, "lint", "--fix")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, out, "[fixed]")
+	assert.Contains(t, stderr, "1 fixed")
+	assert.FileExists(t, filepath.Join(tasksDir, "T-003-cli.md"))
+}
@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/prd"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+// taskNewFormWidth is the width of the interactive "raven task new" form.
+const taskNewFormWidth = 80
+
+// taskPriorities and taskEfforts are the values offered by "raven task new";
+// they match the values emitted by "raven prd".
+var (
+	taskPriorities = []string{"must-have", "should-have", "nice-to-have"}
+	taskEfforts    = []string{"small", "medium", "large"}
+)
+
+// taskNewFlags holds the flag values for the task new command.
+type taskNewFlags struct {
+	Title    string   // --title; when empty the interactive form is shown
+	Priority string   // --priority
+	Effort   string   // --effort
+	Deps     []string // --deps
+	Goal     string   // --goal
+	Criteria []string // --criteria (repeatable)
+	JSON     bool     // --json for structured output
+}
+
+// newTaskNewCmd creates the "raven task new" command.
+func newTaskNewCmd() *cobra.Command {
+	var flags taskNewFlags
+
+	cmd := &cobra.Command{
+		Use:   "new",
+		Short: "Create a task spec with the next free task ID",
+		Long: `Create a new task spec file in the tasks directory using the next free task
+ID for the configured prefix, and add it to the task state file as
+not_started.
+
+Without --title, an interactive form asks for the title, priority, effort,
+dependencies, goal, and acceptance criteria (requires a terminal). With
+--title, the spec is created from flags alone.
+
+The new task is not added to phases.conf; extend a phase range if the task
+should be picked up by phase runs.`,
+		Example: `  # Interactive form
+  raven task new
+
+  # Non-interactive
+  raven task new --title "Add retry budget" --priority must-have --effort small \
+    --deps T-010,T-011 --goal "Stop retrying forever." \
+    --criteria "Retries stop after the budget" --criteria "Budget is configurable"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskNew(cmd, flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.Title, "title", "", "Task title (skips the interactive form)")
+	cmd.Flags().StringVar(&flags.Priority, "priority", "should-have", "Priority: must-have, should-have, or nice-to-have")
+	cmd.Flags().StringVar(&flags.Effort, "effort", "medium", "Estimated effort: small, medium, or large")
+	cmd.Flags().StringSliceVar(&flags.Deps, "deps", nil, "Comma-separated IDs of tasks this task depends on")
+	cmd.Flags().StringVar(&flags.Goal, "goal", "", "What the task should achieve")
+	cmd.Flags().StringArrayVar(&flags.Criteria, "criteria", nil, "Acceptance criterion (repeatable)")
+	cmd.Flags().BoolVar(&flags.JSON, "json", false, "Output the created task as JSON")
+
+	_ = cmd.RegisterFlagCompletionFunc("priority", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return taskPriorities, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("effort", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return taskEfforts, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("deps", completeTaskIDs)
+
+	return cmd
+}
+
+// runTaskNew is the RunE function of the task new command.
+func runTaskNew(cmd *cobra.Command, flags taskNewFlags) error {
+	env, err := loadTaskEnv()
+	if err != nil {
+		return err
+	}
+
+	draft := task.SpecDraft{
+		ID:                 task.NextTaskID(env.specs, env.cfg.Project.TaskIDPrefix),
+		Title:              flags.Title,
+		Priority:           flags.Priority,
+		Effort:             flags.Effort,
+		Dependencies:       flags.Deps,
+		Goal:               flags.Goal,
+		AcceptanceCriteria: flags.Criteria,
+	}
+
+	if strings.TrimSpace(flags.Title) == "" {
+		if !isStdinTTY() {
+			return fmt.Errorf("--title is required when stdin is not a terminal")
+		}
+		if err := runTaskNewForm(&draft, env.specs); err != nil {
+			if errors.Is(err, huh.ErrUserAborted) {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Cancelled.")
+				return nil
+			}
+			return fmt.Errorf("task form: %w", err)
+		}
+	}
+
+	if err := validateTaskDraft(&draft, env.specs); err != nil {
+		return err
+	}
+
+	path, err := writeTaskSpec(env.cfg.Project.TasksDir, draft)
+	if err != nil {
+		return err
+	}
+	if err := env.state.Initialize([]string{draft.ID}); err != nil {
+		return fmt.Errorf("adding %s to task state: %w", draft.ID, err)
+	}
+
+	spec, err := task.ParseTaskFile(path)
+	if err != nil {
+		return fmt.Errorf("reading back %s: %w", path, err)
+	}
+	env.specs = append(env.specs, spec)
+	env.membership = task.NewPhaseMembership(env.phases, env.specs)
+	stateMap, err := env.state.LoadMap()
+	if err != nil {
+		return fmt.Errorf("loading task state: %w", err)
+	}
+	out := env.taskOutputFor(spec, stateMap)
+
+	if flags.JSON {
+		return writeJSON(cmd.OutOrStdout(), out)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Created %s: %s\n  Spec: %s\n", draft.ID, spec.Title, path)
+	if len(env.phases) > 0 && out.Phase == 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Note: %s is not in any phase; extend a phase in the phases file to include it.\n", draft.ID)
+	}
+	return nil
+}
+
+// validateTaskDraft normalises and validates the user-supplied fields of
+// draft against the existing specs.
+func validateTaskDraft(draft *task.SpecDraft, specs []*task.ParsedTaskSpec) error {
+	draft.Title = strings.TrimSpace(draft.Title)
+	if draft.Title == "" {
+		return fmt.Errorf("task title must not be empty")
+	}
+	if !slices.Contains(taskPriorities, draft.Priority) {
+		return fmt.Errorf("invalid priority %q: must be one of %s", draft.Priority, strings.Join(taskPriorities, ", "))
+	}
+	if !slices.Contains(taskEfforts, draft.Effort) {
+		return fmt.Errorf("invalid effort %q: must be one of %s", draft.Effort, strings.Join(taskEfforts, ", "))
+	}
+
+	known := make(map[string]bool, len(specs))
+	for _, s := range specs {
+		known[s.ID] = true
+	}
+	deps := make([]string, 0, len(draft.Dependencies))
+	for _, dep := range draft.Dependencies {
+		dep = strings.TrimSpace(dep)
+		if dep == "" {
+			continue
+		}
+		if !known[dep] {
+			return fmt.Errorf("dependency %s not found", dep)
+		}
+		deps = append(deps, dep)
+	}
+	draft.Dependencies = deps
+	if strings.TrimSpace(draft.Goal) == "" {
+		draft.Goal = draft.Title + "."
+	}
+	return nil
+}
+
+// writeTaskSpec renders draft to "<ID>-<slug>.md" in dir. It refuses to
+// overwrite an existing file.
+func writeTaskSpec(dir string, draft task.SpecDraft) (string, error) {
+	content, err := task.RenderTaskSpec(draft)
+	if err != nil {
+		return "", err
+	}
+
+	slug := prd.Slugify(draft.Title)
+	if slug == "" {
+		slug = "task"
+	}
+	path := filepath.Join(dir, draft.ID+"-"+slug+".md")
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating tasks directory %q: %w", dir, err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("creating task spec %q: %w", path, err)
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close() //nolint:errcheck
+		return "", fmt.Errorf("writing task spec %q: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("closing task spec %q: %w", path, err)
+	}
+	return path, nil
+}
+
+// runTaskNewForm shows the interactive form for a new task and fills draft
+// from the answers. Returns huh.ErrUserAborted when the user cancels.
+func runTaskNewForm(draft *task.SpecDraft, specs []*task.ParsedTaskSpec) error {
+	depOptions := make([]huh.Option[string], 0, len(specs))
+	for _, s := range specs {
+		depOptions = append(depOptions, huh.NewOption(s.ID+": "+s.Title, s.ID))
+	}
+
+	var criteria string
+	fields := []huh.Field{
+		huh.NewInput().
+			Title(fmt.Sprintf("Title for %s", draft.ID)).
+			Value(&draft.Title).
+			Validate(func(s string) error {
+				if strings.TrimSpace(s) == "" {
+					return fmt.Errorf("title must not be empty")
+				}
+				return nil
+			}),
+		huh.NewSelect[string]().
+			Title("Priority").
+			Options(huh.NewOptions(taskPriorities...)...).
+			Value(&draft.Priority),
+		huh.NewSelect[string]().
+			Title("Estimated effort").
+			Options(huh.NewOptions(taskEfforts...)...).
+			Value(&draft.Effort),
+	}
+	if len(depOptions) > 0 {
+		fields = append(fields, huh.NewMultiSelect[string]().
+			Title("Dependencies").
+			Options(depOptions...).
+			Filterable(true).
+			Value(&draft.Dependencies))
+	}
+	fields = append(fields,
+		huh.NewText().
+			Title("Goal").
+			Value(&draft.Goal),
+		huh.NewText().
+			Title("Acceptance criteria (one per line)").
+			Value(&criteria),
+	)
+
+	err := huh.NewForm(huh.NewGroup(fields...)).
+		WithTheme(huh.ThemeCharm()).
+		WithWidth(taskNewFormWidth).
+		Run()
+	if err != nil {
+		return err
+	}
+	draft.AcceptanceCriteria = strings.Split(criteria, "\n")
+	return nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+func TestTaskNewCmd_Flags(t *testing.T) {
+	tomlPath, tasksDir := writeTaskProject(t)
+
+	code, out, stderr := executeTask(t, tomlPath, "new",
+		"--title", "Add retry budget",
+		"--priority", "must-have",
+		"--effort", "small",
+		"--deps", "T-001,T-002",
+		"--criteria", "Retries stop after the budget",
+		"--criteria", "Budget is configurable, per agent",
+	)
+	require.Equal(t, 0, code, stderr)
+
+	specPath := filepath.Join(tasksDir, "T-004-add-retry-budget.md")
+	assert.Equal(t, "Created T-004: Add retry budget\n  Spec: "+specPath+"\n", out)
+	assert.Contains(t, stderr, "T-004 is not in any phase")
+
+	spec, err := task.ParseTaskFile(specPath)
+	require.NoError(t, err)
+	assert.Equal(t, "must-have", spec.Priority)
+	assert.Equal(t, "small", spec.Effort)
+	assert.Equal(t, []string{"T-001", "T-002"}, spec.Dependencies)
+	assert.Contains(t, spec.Content, "## Goal\nAdd retry budget.\n")
+	assert.Contains(t, spec.Content, "- [ ] Budget is configurable, per agent\n")
+
+	ts := loadTaskState(t, tomlPath, "T-004")
+	assert.Equal(t, task.StatusNotStarted, ts.Status)
+}
+
+func TestTaskNewCmd_JSON(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+
+	code, out, stderr := executeTask(t, tomlPath, "new", "--title", "Docs", "--json")
+	require.Equal(t, 0, code, stderr)
+
+	var row taskOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &row))
+	assert.Equal(t, "T-004", row.ID)
+	assert.Equal(t, "should-have", row.Priority)
+	assert.Equal(t, "medium", row.Effort)
+	assert.True(t, row.Ready)
+}
+
+func TestRunTaskNew_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   taskNewFlags
+		wantErr string
+	}{
+		{name: "bad priority", flags: taskNewFlags{Title: "X", Priority: "urgent", Effort: "small"}, wantErr: `invalid priority "urgent"`},
+		{name: "bad effort", flags: taskNewFlags{Title: "X", Priority: "must-have", Effort: "huge"}, wantErr: `invalid effort "huge"`},
+		{name: "unknown dependency", flags: taskNewFlags{Title: "X", Priority: "must-have", Effort: "small", Deps: []string{"T-099"}}, wantErr: "dependency T-099 not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tomlPath, tasksDir := writeTaskProject(t)
+			resetTaskFlags(t)
+			flagConfig = tomlPath
+
+			err := runTaskNew(newTaskNewCmd(), tt.flags)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			entries, err := os.ReadDir(tasksDir)
+			require.NoError(t, err)
+			assert.Len(t, entries, 3, "no spec written on error")
+		})
+	}
+}
+
+func TestWriteTaskSpec_NoOverwrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	draft := task.SpecDraft{ID: "T-001", Title: "Setup", Priority: "must-have", Effort: "small"}
+	path, err := writeTaskSpec(dir, draft)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "T-001-setup.md"), path)
+
+	_, err = writeTaskSpec(dir, draft)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "creating task spec")
+}
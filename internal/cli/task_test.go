@@ -0,0 +1,316 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+// resetTaskFlags resets the flags of every "raven task" subcommand for
+// inter-test isolation.
+func resetTaskFlags(t *testing.T) {
+	t.Helper()
+	resetRootCmd(t)
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Use != "task" {
+			continue
+		}
+		for _, sub := range cmd.Commands() {
+			sub.Flags().VisitAll(func(f *pflag.Flag) {
+				f.Changed = false
+				if sv, ok := f.Value.(pflag.SliceValue); ok {
+					if err := sv.Replace(nil); err != nil {
+						t.Logf("resetting flag %q: %v", f.Name, err)
+					}
+					return
+				}
+				if err := f.Value.Set(f.DefValue); err != nil {
+					t.Logf("resetting flag %q: %v", f.Name, err)
+				}
+			})
+		}
+	}
+}
+
+// writeTaskProject writes a project with three task specs in two phases and
+// returns the raven.toml path and the tasks directory.
+//
+//	T-001 (completed) -> T-002 (blocked, label "api") -> T-003 (phase 2)
+func writeTaskProject(t *testing.T) (string, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	tasksDir := filepath.Join(tmpDir, "tasks")
+	require.NoError(t, os.MkdirAll(tasksDir, 0o755))
+
+	spec := func(id, title, deps string) string {
+		return fmt.Sprintf("# %s: %s\n\n| Field | Value |\n|-------|-------|\n| Priority | must-have |\n| Dependencies | %s |\n",
+			id, title, deps)
+	}
+	files := map[string]string{
+		"T-001-setup.md": spec("T-001", "Setup", "None"),
+		"T-002-core.md":  "---\nlabels: [api]\n---\n" + spec("T-002", "Core", "T-001"),
+		"T-003-cli.md":   spec("T-003", "CLI", "T-002"),
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tasksDir, name), []byte(content), 0o644))
+	}
+
+	statePath := filepath.Join(tmpDir, "task-state.conf")
+	require.NoError(t, os.WriteFile(statePath,
+		[]byte("T-001|completed|claude|2026-03-01T09:00:00Z|\nT-002|blocked|claude|2026-03-01T10:00:00Z|waiting on keys\n"), 0o644))
+
+	phasesPath := filepath.Join(tmpDir, "phases.conf")
+	require.NoError(t, os.WriteFile(phasesPath, []byte("1|Foundation|T-001|T-002\n2|Interface|T-003|T-003\n"), 0o644))
+
+	tomlContent := fmt.Sprintf("[project]\nname = \"task-project\"\ntasks_dir = %q\ntask_state_file = %q\nphases_conf = %q\n",
+		tasksDir, statePath, phasesPath)
+	tomlPath := filepath.Join(tmpDir, "raven.toml")
+	require.NoError(t, os.WriteFile(tomlPath, []byte(tomlContent), 0o644))
+	return tomlPath, tasksDir
+}
+
+// executeTask runs "raven --config tomlPath task args..." and returns the exit
+// code, stdout, and stderr.
+func executeTask(t *testing.T, tomlPath string, args ...string) (int, string, string) {
+	t.Helper()
+	resetTaskFlags(t)
+
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	rootCmd.SetArgs(append([]string{"--config", tomlPath, "task"}, args...))
+	code := Execute()
+	return code, stdout.String(), stderr.String()
+}
+
+func TestTaskListCmd(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+
+	code, out, stderr := executeTask(t, tomlPath, "list")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, out, "ID")
+	assert.Contains(t, out, "T-001")
+	assert.Contains(t, out, "blocked")
+	assert.Contains(t, out, "must-have")
+}
+
+func TestTaskListCmd_Filters(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "phase", args: []string{"--phase", "2"}, want: []string{"T-003"}},
+		{name: "status", args: []string{"--status", "blocked"}, want: []string{"T-002"}},
+		{name: "label", args: []string{"--label", "api"}, want: []string{"T-002"}},
+		{name: "no match", args: []string{"--status", "skipped"}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tomlPath, _ := writeTaskProject(t)
+
+			code, out, stderr := executeTask(t, tomlPath, append([]string{"list", "--json"}, tt.args...)...)
+			require.Equal(t, 0, code, stderr)
+
+			var rows []taskOutput
+			require.NoError(t, json.Unmarshal([]byte(out), &rows))
+			ids := make([]string, 0, len(rows))
+			for _, r := range rows {
+				ids = append(ids, r.ID)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestTaskListCmd_SkippedDependencyNotReady(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+
+	code, _, stderr := executeTask(t, tomlPath, "skip", "T-002")
+	require.Equal(t, 0, code, stderr)
+
+	// As in the selector, a skipped dependency does not make T-003 ready.
+	code, out, stderr := executeTask(t, tomlPath, "list", "--json", "--phase", "2")
+	require.Equal(t, 0, code, stderr)
+	var rows []taskOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	require.Len(t, rows, 1)
+	assert.Equal(t, "T-003", rows[0].ID)
+	assert.False(t, rows[0].Ready)
+}
+
+func TestRunTaskList_InvalidStatus(t *testing.T) {
+	resetTaskFlags(t)
+
+	err := runTaskList(newTaskListCmd(), taskListFlags{Status: "done"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid --status "done"`)
+}
+
+func TestTaskShowCmd(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+
+	code, out, stderr := executeTask(t, tomlPath, "show", "T-003")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, out, "T-003: CLI")
+	assert.Contains(t, out, "T-002  blocked")
+
+	code, out, stderr = executeTask(t, tomlPath, "show", "T-002", "--json")
+	require.Equal(t, 0, code, stderr)
+	var detail taskDetailOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &detail))
+	assert.Equal(t, task.StatusBlocked, detail.Status)
+	assert.Equal(t, "waiting on keys", detail.Notes)
+	assert.Equal(t, []string{"api"}, detail.Labels)
+	assert.Equal(t, task.StatusCompleted, detail.DependencyStatus["T-001"])
+	assert.Equal(t, []string{"T-003"}, detail.Dependents)
+}
+
+func TestTaskShowCmd_Claim(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+	sm := task.NewStateManager(filepath.Join(filepath.Dir(tomlPath), "task-state.conf"))
+	require.NoError(t, sm.Claim("T-003", "ci-runner:7", "claude", time.Hour))
+
+	code, out, stderr := executeTask(t, tomlPath, "show", "T-003")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, out, "Claimed by: ci-runner:7 (lease until ")
+
+	code, out, stderr = executeTask(t, tomlPath, "show", "T-003", "--json")
+	require.Equal(t, 0, code, stderr)
+	var detail taskDetailOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &detail))
+	assert.Equal(t, task.StatusInProgress, detail.Status)
+	assert.Equal(t, "ci-runner:7", detail.ClaimedBy)
+	assert.NotEmpty(t, detail.LeaseExpiry)
+}
+
+func TestRunTaskShow_NotFound(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+	resetTaskFlags(t)
+	flagConfig = tomlPath
+
+	err := runTaskShow(newTaskShowCmd(), "T-099", taskShowFlags{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task T-099 not found")
+}
+
+func TestCompleteTaskIDs(t *testing.T) {
+	tomlPath, _ := writeTaskProject(t)
+	resetTaskFlags(t)
+	flagConfig = tomlPath
+
+	got, directive := completeTaskIDs(nil, nil, "")
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	assert.Equal(t, []string{"T-001\tSetup", "T-002\tCore", "T-003\tCLI"}, got)
+
+	got, _ = completeTaskIDs(nil, []string{"T-001"}, "")
+	assert.Empty(t, got, "only the first argument is a task ID")
+}
+
+// writeTaskHistoryProject writes a raven.toml whose state file has recorded
+// history for T-001 and returns the config path and state file path.
+func writeTaskHistoryProject(t *testing.T) (string, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	statePath := filepath.Join(tmpDir, "task-state.conf")
+	sm := task.NewStateManager(statePath)
+	sm.SetRunID("run-1")
+	require.NoError(t, sm.UpdateStatus("T-001", task.StatusInProgress, "claude"))
+	require.NoError(t, sm.UpdateStatus("T-001", task.StatusBlocked, "claude"))
+	sm.SetRunID("run-2")
+	require.NoError(t, sm.UpdateStatus("T-001", task.StatusInProgress, "codex"))
+	require.NoError(t, sm.UpdateStatus("T-001", task.StatusCompleted, "codex"))
+
+	tomlContent := fmt.Sprintf("[project]\nname = \"history-project\"\ntasks_dir = %q\ntask_state_file = %q\n",
+		tmpDir, statePath)
+	tomlPath := filepath.Join(tmpDir, "raven.toml")
+	require.NoError(t, os.WriteFile(tomlPath, []byte(tomlContent), 0o644))
+	return tomlPath, statePath
+}
+
+func TestTaskHistoryCmd_Table(t *testing.T) {
+	tomlPath, _ := writeTaskHistoryProject(t)
+	resetTaskFlags(t)
+
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	rootCmd.SetArgs([]string{"--config", tomlPath, "task", "history", "T-001"})
+	code := Execute()
+	require.Equal(t, 0, code, stderr.String())
+
+	out := stdout.String()
+	assert.Contains(t, out, "TIME")
+	assert.Contains(t, out, "run-1")
+	assert.Contains(t, out, "run-2")
+	assert.Contains(t, out, "blocked")
+	assert.Contains(t, out, "T-001: 4 changes, 2 attempts, now completed")
+}
+
+func TestTaskHistoryCmd_JSON(t *testing.T) {
+	tomlPath, _ := writeTaskHistoryProject(t)
+	resetTaskFlags(t)
+
+	var stdout bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetArgs([]string{"--config", tomlPath, "task", "history", "T-001", "--json"})
+	code := Execute()
+	require.Equal(t, 0, code)
+
+	var entries []task.HistoryEntry
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &entries))
+	require.Len(t, entries, 4)
+	assert.Equal(t, task.StatusBlocked, entries[2].PreviousStatus)
+	assert.Equal(t, "run-2", entries[2].RunID)
+}
+
+func TestTaskHistoryCmd_NoHistory(t *testing.T) {
+	tomlPath, _ := writeTaskHistoryProject(t)
+	resetTaskFlags(t)
+
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	rootCmd.SetArgs([]string{"--config", tomlPath, "task", "history", "T-002"})
+	code := Execute()
+	require.Equal(t, 0, code)
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "No recorded history for T-002")
+}
+
+func TestRunTaskHistory_InvalidID(t *testing.T) {
+	resetTaskFlags(t)
+
+	err := runTaskHistory(newTaskHistoryCmd(), "t-1", taskHistoryFlags{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid task ID "t-1"`)
+}
+
+func TestRenderTaskHistory(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	history := []task.HistoryEntry{
+		{Timestamp: ts, TaskID: "T-012", Status: task.StatusInProgress, Agent: "claude"},
+		{Timestamp: ts.Add(time.Hour), TaskID: "T-012", PreviousStatus: task.StatusInProgress, Status: task.StatusNotStarted, Notes: "reset\nby hand"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderTaskHistory(&buf, "T-012", history))
+	out := buf.String()
+	assert.Contains(t, out, "2026-03-01 09:00:00Z")
+	assert.Contains(t, out, "reset by hand")
+	assert.Contains(t, out, "T-012: 2 changes, 1 attempts, now not_started (since 2026-03-01T10:00:00Z)")
+}
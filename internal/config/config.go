@@ -16,9 +16,12 @@ type ProjectConfig struct {
 	TaskStateFile        string   `toml:"task_state_file"`
 	PhasesConf           string   `toml:"phases_conf"`
 	ProgressFile         string   `toml:"progress_file"`
+	ProgressFormats      []string `toml:"progress_formats"`
 	LogDir               string   `toml:"log_dir"`
 	PromptDir            string   `toml:"prompt_dir"`
 	BranchTemplate       string   `toml:"branch_template"`
+	TaskIDPrefix         string   `toml:"task_id_prefix"`
+	TaskStrategy         string   `toml:"task_strategy"`
 	VerificationCommands []string `toml:"verification_commands"`
 }
 
@@ -33,11 +36,54 @@ type AgentConfig struct {
 
 // ReviewConfig maps to the [review] section in raven.toml.
 type ReviewConfig struct {
-	Extensions       string `toml:"extensions"`
-	RiskPatterns     string `toml:"risk_patterns"`
-	PromptsDir       string `toml:"prompts_dir"`
-	RulesDir         string `toml:"rules_dir"`
-	ProjectBriefFile string `toml:"project_brief_file"`
+	Extensions       string  `toml:"extensions"`
+	RiskPatterns     string  `toml:"risk_patterns"`
+	PromptsDir       string  `toml:"prompts_dir"`
+	RulesDir         string  `toml:"rules_dir"`
+	ProjectBriefFile string  `toml:"project_brief_file"`
+	InvalidFindings  string  `toml:"invalid_findings"`
+	Dedup            string  `toml:"dedup"`
+	DedupThreshold   float64 `toml:"dedup_threshold"`
+	TokenBudget      int     `toml:"token_budget"`
+
+	// DedupLineWindow is a pointer because 0 is meaningful (merge findings on
+	// the same line only); nil means unset.
+	DedupLineWindow *int `toml:"dedup_line_window"`
+
+	TokenBudgets map[string]int            `toml:"token_budgets"`
+	Personas     map[string]PersonaConfig  `toml:"personas"`
+	Analyzers    map[string]AnalyzerConfig `toml:"analyzers"`
+	Policy       PolicyConfig              `toml:"policy"`
+}
+
+// PolicyConfig maps to the [review.policy] section in raven.toml.
+type PolicyConfig struct {
+	BlockingQuorum float64 `toml:"blocking_quorum"`
+	ChangesQuorum  float64 `toml:"changes_quorum"`
+	BlockSeverity  string  `toml:"block_severity"`
+	ApproveBelow   string  `toml:"approve_below"`
+	AgentFailure   string  `toml:"agent_failure"`
+
+	Weights map[string]float64 `toml:"weights"`
+}
+
+// PersonaConfig maps to a [review.personas.<name>] section in raven.toml.
+type PersonaConfig struct {
+	Agent  string   `toml:"agent"`
+	Model  string   `toml:"model"`
+	Prompt string   `toml:"prompt"`
+	Rules  []string `toml:"rules"`
+	Paths  []string `toml:"paths"`
+}
+
+// AnalyzerConfig maps to a [review.analyzers.<name>] section in raven.toml.
+type AnalyzerConfig struct {
+	Command  string `toml:"command"`
+	Format   string `toml:"format"`
+	Pattern  string `toml:"pattern"`
+	Severity string `toml:"severity"`
+	Category string `toml:"category"`
+	Context  bool   `toml:"context"`
 }
 
 // WorkflowConfig maps to a [workflows.<name>] section in raven.toml.
@@ -5,15 +5,29 @@ package config
 func NewDefaults() *Config {
 	return &Config{
 		Project: ProjectConfig{
-			TasksDir:       "docs/tasks",
-			TaskStateFile:  "docs/tasks/task-state.conf",
-			PhasesConf:     "docs/tasks/phases.conf",
-			ProgressFile:   "docs/tasks/PROGRESS.md",
-			LogDir:         "scripts/logs",
-			PromptDir:      "prompts",
-			BranchTemplate: "phase/{phase_id}-{slug}",
+			TasksDir:        "docs/tasks",
+			TaskStateFile:   "docs/tasks/task-state.conf",
+			PhasesConf:      "docs/tasks/phases.conf",
+			ProgressFile:    "docs/tasks/PROGRESS.md",
+			LogDir:          "scripts/logs",
+			PromptDir:       "prompts",
+			BranchTemplate:  "phase/{phase_id}-{slug}",
+			TaskIDPrefix:    "T",
+			TaskStrategy:    "id",
+			ProgressFormats: []string{"markdown"},
+		},
+		Review: ReviewConfig{
+			InvalidFindings: "drop",
+			Dedup:           "semantic",
+			DedupLineWindow: intPtr(3),
+			DedupThreshold:  0.6,
 		},
 		Agents:    map[string]AgentConfig{},
 		Workflows: map[string]WorkflowConfig{},
 	}
 }
+
+// intPtr returns a pointer to n, for optional int settings.
+func intPtr(n int) *int {
+	return &n
+}
@@ -24,6 +24,8 @@ func TestNewDefaults(t *testing.T) {
 		{name: "LogDir", got: cfg.Project.LogDir, want: "scripts/logs"},
 		{name: "PromptDir", got: cfg.Project.PromptDir, want: "prompts"},
 		{name: "BranchTemplate", got: cfg.Project.BranchTemplate, want: "phase/{phase_id}-{slug}"},
+		{name: "TaskIDPrefix", got: cfg.Project.TaskIDPrefix, want: "T"},
+		{name: "TaskStrategy", got: cfg.Project.TaskStrategy, want: "id"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -36,6 +38,7 @@ func TestNewDefaults(t *testing.T) {
 	assert.Empty(t, cfg.Project.Name, "project name should be empty by default")
 	assert.Empty(t, cfg.Project.Language, "project language should be empty by default")
 	assert.Nil(t, cfg.Project.VerificationCommands, "verification commands should be nil by default")
+	assert.Equal(t, []string{"markdown"}, cfg.Project.ProgressFormats)
 }
 
 func TestNewDefaults_EmptyAgents(t *testing.T) {
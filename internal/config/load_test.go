@@ -271,3 +271,125 @@ func TestFindConfigFile_ReturnsAbsolutePath(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, filepath.IsAbs(found), "expected absolute path, got %s", found)
 }
+
+func TestLoadFromFile_ReviewPersonas(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "raven.toml")
+	content := `
+[review.personas.security]
+agent = "claude"
+model = "claude-opus-4-6"
+prompt = ".github/review/personas/security.md"
+rules = [".github/review/rules/owasp.md"]
+paths = ["internal/auth/**", "**/*crypto*.go"]
+
+[review.personas.tests]
+agent = "codex"
+paths = ["*_test.go"]
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cfg, md, err := LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, md.Undecoded())
+	require.Len(t, cfg.Review.Personas, 2)
+	assert.Equal(t, PersonaConfig{
+		Agent:  "claude",
+		Model:  "claude-opus-4-6",
+		Prompt: ".github/review/personas/security.md",
+		Rules:  []string{".github/review/rules/owasp.md"},
+		Paths:  []string{"internal/auth/**", "**/*crypto*.go"},
+	}, cfg.Review.Personas["security"])
+	assert.Equal(t, []string{"*_test.go"}, cfg.Review.Personas["tests"].Paths)
+}
+
+func TestLoadFromFile_ReviewAnalyzers(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "raven.toml")
+	content := `
+[review.analyzers.vet]
+command = "go vet ./..."
+format = "line"
+pattern = '^(?P<file>[^:]+):(?P<line>\d+):(?P<message>.+)$'
+severity = "high"
+context = true
+
+[review.analyzers.staticcheck]
+command = "staticcheck -f sarif ./..."
+format = "sarif"
+category = "static-analysis"
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cfg, md, err := LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, md.Undecoded())
+	require.Len(t, cfg.Review.Analyzers, 2)
+	assert.Equal(t, AnalyzerConfig{
+		Command:  "go vet ./...",
+		Format:   "line",
+		Pattern:  `^(?P<file>[^:]+):(?P<line>\d+):(?P<message>.+)$`,
+		Severity: "high",
+		Context:  true,
+	}, cfg.Review.Analyzers["vet"])
+	assert.Equal(t, "static-analysis", cfg.Review.Analyzers["staticcheck"].Category)
+}
+
+func TestLoadFromFile_ReviewTokenBudgets(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "raven.toml")
+	content := `
+[review]
+token_budget = 60000
+
+[review.token_budgets]
+claude = 150000
+codex = 0
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cfg, md, err := LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, md.Undecoded())
+	assert.Equal(t, 60000, cfg.Review.TokenBudget)
+	assert.Equal(t, map[string]int{"claude": 150000, "codex": 0}, cfg.Review.TokenBudgets)
+}
+
+func TestLoadFromFile_ReviewDedupLineWindow(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "raven.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[review]\ndedup_line_window = 0\n"), 0o600))
+
+	cfg, _, err := LoadFromFile(path)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Review.DedupLineWindow, "an explicit 0 is set")
+	assert.Equal(t, 0, *cfg.Review.DedupLineWindow)
+}
+
+func TestLoadFromFile_ReviewPolicy(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "raven.toml")
+	content := `
+[review.policy]
+blocking_quorum = 2
+approve_below = "medium"
+block_severity = "critical"
+agent_failure = "ignore"
+
+[review.policy.weights]
+claude = 2
+"analyzer:vet" = 0
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cfg, md, err := LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, md.Undecoded())
+	assert.Equal(t, PolicyConfig{
+		BlockingQuorum: 2,
+		ApproveBelow:   "medium",
+		BlockSeverity:  "critical",
+		AgentFailure:   "ignore",
+		Weights:        map[string]float64{"claude": 2, "analyzer:vet": 0},
+	}, cfg.Review.Policy)
+}
@@ -1,5 +1,7 @@
 package config
 
+import "slices"
+
 // ConfigSource identifies where a configuration value came from.
 type ConfigSource string
 
@@ -108,12 +110,20 @@ func resolveProjectFromDefaults(rc *ResolvedConfig, defaults *Config) {
 	setString(&p.LogDir, d.LogDir, "project.log_dir", SourceDefault, rc.Sources)
 	setString(&p.PromptDir, d.PromptDir, "project.prompt_dir", SourceDefault, rc.Sources)
 	setString(&p.BranchTemplate, d.BranchTemplate, "project.branch_template", SourceDefault, rc.Sources)
+	setString(&p.TaskIDPrefix, d.TaskIDPrefix, "project.task_id_prefix", SourceDefault, rc.Sources)
+	setString(&p.TaskStrategy, d.TaskStrategy, "project.task_strategy", SourceDefault, rc.Sources)
 
 	if len(d.VerificationCommands) > 0 {
 		rc.Config.Project.VerificationCommands = make([]string, len(d.VerificationCommands))
 		copy(rc.Config.Project.VerificationCommands, d.VerificationCommands)
 	}
 	rc.Sources["project.verification_commands"] = SourceDefault
+
+	if len(d.ProgressFormats) > 0 {
+		rc.Config.Project.ProgressFormats = make([]string, len(d.ProgressFormats))
+		copy(rc.Config.Project.ProgressFormats, d.ProgressFormats)
+	}
+	rc.Sources["project.progress_formats"] = SourceDefault
 }
 
 func resolveReviewFromDefaults(rc *ResolvedConfig, defaults *Config) {
@@ -125,6 +135,42 @@ func resolveReviewFromDefaults(rc *ResolvedConfig, defaults *Config) {
 	setString(&r.PromptsDir, d.PromptsDir, "review.prompts_dir", SourceDefault, rc.Sources)
 	setString(&r.RulesDir, d.RulesDir, "review.rules_dir", SourceDefault, rc.Sources)
 	setString(&r.ProjectBriefFile, d.ProjectBriefFile, "review.project_brief_file", SourceDefault, rc.Sources)
+	setString(&r.InvalidFindings, d.InvalidFindings, "review.invalid_findings", SourceDefault, rc.Sources)
+	setString(&r.Dedup, d.Dedup, "review.dedup", SourceDefault, rc.Sources)
+	setIntPtr(&r.DedupLineWindow, d.DedupLineWindow, "review.dedup_line_window", SourceDefault, rc.Sources)
+	setFloat(&r.DedupThreshold, d.DedupThreshold, "review.dedup_threshold", SourceDefault, rc.Sources)
+	setInt(&r.TokenBudget, d.TokenBudget, "review.token_budget", SourceDefault, rc.Sources)
+
+	r.TokenBudgets = make(map[string]int)
+	for name, budget := range d.TokenBudgets {
+		r.TokenBudgets[name] = budget
+		rc.Sources["review.token_budgets."+name] = SourceDefault
+	}
+
+	r.Personas = make(map[string]PersonaConfig)
+	for name, persona := range d.Personas {
+		r.Personas[name] = copyPersonaConfig(persona)
+		setPersonaSources(rc.Sources, name, SourceDefault)
+	}
+
+	r.Analyzers = make(map[string]AnalyzerConfig)
+	for name, analyzer := range d.Analyzers {
+		r.Analyzers[name] = analyzer
+		setAnalyzerSources(rc.Sources, name, SourceDefault)
+	}
+
+	p, dp := &r.Policy, &d.Policy
+	setFloat(&p.BlockingQuorum, dp.BlockingQuorum, "review.policy.blocking_quorum", SourceDefault, rc.Sources)
+	setFloat(&p.ChangesQuorum, dp.ChangesQuorum, "review.policy.changes_quorum", SourceDefault, rc.Sources)
+	setString(&p.BlockSeverity, dp.BlockSeverity, "review.policy.block_severity", SourceDefault, rc.Sources)
+	setString(&p.ApproveBelow, dp.ApproveBelow, "review.policy.approve_below", SourceDefault, rc.Sources)
+	setString(&p.AgentFailure, dp.AgentFailure, "review.policy.agent_failure", SourceDefault, rc.Sources)
+
+	p.Weights = make(map[string]float64)
+	for name, weight := range dp.Weights {
+		p.Weights[name] = weight
+		rc.Sources["review.policy.weights."+name] = SourceDefault
+	}
 }
 
 func resolveAgentsFromDefaults(rc *ResolvedConfig, defaults *Config) {
@@ -161,12 +207,20 @@ func resolveProjectFromFile(rc *ResolvedConfig, file *Config) {
 	mergeString(&p.LogDir, f.LogDir, "project.log_dir", SourceFile, rc.Sources)
 	mergeString(&p.PromptDir, f.PromptDir, "project.prompt_dir", SourceFile, rc.Sources)
 	mergeString(&p.BranchTemplate, f.BranchTemplate, "project.branch_template", SourceFile, rc.Sources)
+	mergeString(&p.TaskIDPrefix, f.TaskIDPrefix, "project.task_id_prefix", SourceFile, rc.Sources)
+	mergeString(&p.TaskStrategy, f.TaskStrategy, "project.task_strategy", SourceFile, rc.Sources)
 
 	if len(f.VerificationCommands) > 0 {
 		rc.Config.Project.VerificationCommands = make([]string, len(f.VerificationCommands))
 		copy(rc.Config.Project.VerificationCommands, f.VerificationCommands)
 		rc.Sources["project.verification_commands"] = SourceFile
 	}
+
+	if len(f.ProgressFormats) > 0 {
+		rc.Config.Project.ProgressFormats = make([]string, len(f.ProgressFormats))
+		copy(rc.Config.Project.ProgressFormats, f.ProgressFormats)
+		rc.Sources["project.progress_formats"] = SourceFile
+	}
 }
 
 func resolveReviewFromFile(rc *ResolvedConfig, file *Config) {
@@ -178,6 +232,44 @@ func resolveReviewFromFile(rc *ResolvedConfig, file *Config) {
 	mergeString(&r.PromptsDir, f.PromptsDir, "review.prompts_dir", SourceFile, rc.Sources)
 	mergeString(&r.RulesDir, f.RulesDir, "review.rules_dir", SourceFile, rc.Sources)
 	mergeString(&r.ProjectBriefFile, f.ProjectBriefFile, "review.project_brief_file", SourceFile, rc.Sources)
+	mergeString(&r.InvalidFindings, f.InvalidFindings, "review.invalid_findings", SourceFile, rc.Sources)
+	mergeString(&r.Dedup, f.Dedup, "review.dedup", SourceFile, rc.Sources)
+	mergeIntPtr(&r.DedupLineWindow, f.DedupLineWindow, "review.dedup_line_window", SourceFile, rc.Sources)
+	mergeFloat(&r.DedupThreshold, f.DedupThreshold, "review.dedup_threshold", SourceFile, rc.Sources)
+	mergeInt(&r.TokenBudget, f.TokenBudget, "review.token_budget", SourceFile, rc.Sources)
+
+	// Token budgets merge by agent name.
+	for name, budget := range f.TokenBudgets {
+		r.TokenBudgets[name] = budget
+		rc.Sources["review.token_budgets."+name] = SourceFile
+	}
+
+	// Personas merge by name, like agents: a persona in the file replaces the
+	// default persona of the same name.
+	for name, persona := range f.Personas {
+		r.Personas[name] = copyPersonaConfig(persona)
+		setPersonaSources(rc.Sources, name, SourceFile)
+	}
+
+	// Analyzers merge by name in the same way.
+	for name, analyzer := range f.Analyzers {
+		r.Analyzers[name] = analyzer
+		setAnalyzerSources(rc.Sources, name, SourceFile)
+	}
+
+	p, fp := &r.Policy, &f.Policy
+	mergeFloat(&p.BlockingQuorum, fp.BlockingQuorum, "review.policy.blocking_quorum", SourceFile, rc.Sources)
+	mergeFloat(&p.ChangesQuorum, fp.ChangesQuorum, "review.policy.changes_quorum", SourceFile, rc.Sources)
+	mergeString(&p.BlockSeverity, fp.BlockSeverity, "review.policy.block_severity", SourceFile, rc.Sources)
+	mergeString(&p.ApproveBelow, fp.ApproveBelow, "review.policy.approve_below", SourceFile, rc.Sources)
+	mergeString(&p.AgentFailure, fp.AgentFailure, "review.policy.agent_failure", SourceFile, rc.Sources)
+
+	// Policy weights merge by reviewer name. A weight of 0 is meaningful (an
+	// advisory reviewer), so every listed weight overrides.
+	for name, weight := range fp.Weights {
+		p.Weights[name] = weight
+		rc.Sources["review.policy.weights."+name] = SourceFile
+	}
 }
 
 func resolveAgentsFromFile(rc *ResolvedConfig, file *Config) {
@@ -208,6 +300,8 @@ func resolveWorkflowsFromFile(rc *ResolvedConfig, file *Config) {
 //	RAVEN_LOG_DIR            -> project.log_dir
 //	RAVEN_PROMPT_DIR         -> project.prompt_dir
 //	RAVEN_BRANCH_TEMPLATE    -> project.branch_template
+//	RAVEN_TASK_ID_PREFIX     -> project.task_id_prefix
+//	RAVEN_TASK_STRATEGY      -> project.task_strategy
 //	RAVEN_AGENT_MODEL        -> agents.*.model (applies to all agents)
 //	RAVEN_AGENT_EFFORT       -> agents.*.effort (applies to all agents)
 func resolveFromEnv(rc *ResolvedConfig, envFn EnvFunc) {
@@ -234,6 +328,14 @@ func resolveFromEnv(rc *ResolvedConfig, envFn EnvFunc) {
 		p.BranchTemplate = val
 		rc.Sources["project.branch_template"] = SourceEnv
 	}
+	if val, ok := envFn("RAVEN_TASK_ID_PREFIX"); ok {
+		p.TaskIDPrefix = val
+		rc.Sources["project.task_id_prefix"] = SourceEnv
+	}
+	if val, ok := envFn("RAVEN_TASK_STRATEGY"); ok {
+		p.TaskStrategy = val
+		rc.Sources["project.task_strategy"] = SourceEnv
+	}
 
 	// Agent-level env vars apply to ALL agents in the merged map.
 	modelVal, modelSet := envFn("RAVEN_AGENT_MODEL")
@@ -306,6 +408,57 @@ func mergeString(target *string, value string, path string, source ConfigSource,
 	}
 }
 
+// setInt is the int counterpart of setString.
+func setInt(target *int, value int, path string, source ConfigSource, sources map[string]ConfigSource) { //nolint:unparam // consistent API with mergeInt
+	*target = value
+	sources[path] = source
+}
+
+// mergeInt is the int counterpart of mergeString: a zero value means "not
+// set" and does not override.
+func mergeInt(target *int, value int, path string, source ConfigSource, sources map[string]ConfigSource) { //nolint:unparam // consistent API with setInt
+	if value != 0 {
+		*target = value
+		sources[path] = source
+	}
+}
+
+// setIntPtr is the counterpart of setInt for optional settings where zero is
+// meaningful. The value is copied so the resolved config does not share it.
+func setIntPtr(target **int, value *int, path string, source ConfigSource, sources map[string]ConfigSource) { //nolint:unparam // consistent API with mergeIntPtr
+	*target = nil
+	if value != nil {
+		v := *value
+		*target = &v
+	}
+	sources[path] = source
+}
+
+// mergeIntPtr is the counterpart of mergeInt for optional settings: nil means
+// "not set" and does not override, while an explicit 0 does.
+func mergeIntPtr(target **int, value *int, path string, source ConfigSource, sources map[string]ConfigSource) { //nolint:unparam // consistent API with setIntPtr
+	if value != nil {
+		v := *value
+		*target = &v
+		sources[path] = source
+	}
+}
+
+// setFloat is the float64 counterpart of setString.
+func setFloat(target *float64, value float64, path string, source ConfigSource, sources map[string]ConfigSource) { //nolint:unparam // consistent API with mergeFloat
+	*target = value
+	sources[path] = source
+}
+
+// mergeFloat is the float64 counterpart of mergeString: a zero value means
+// "not set" and does not override.
+func mergeFloat(target *float64, value float64, path string, source ConfigSource, sources map[string]ConfigSource) { //nolint:unparam // consistent API with setFloat
+	if value != 0 {
+		*target = value
+		sources[path] = source
+	}
+}
+
 // copyAgentConfig returns a deep copy of an AgentConfig.
 func copyAgentConfig(src AgentConfig) AgentConfig {
 	return AgentConfig{
@@ -327,6 +480,38 @@ func setAgentSources(sources map[string]ConfigSource, name string, source Config
 	sources[prefix+".allowed_tools"] = source
 }
 
+// copyPersonaConfig returns a deep copy of a PersonaConfig.
+func copyPersonaConfig(src PersonaConfig) PersonaConfig {
+	return PersonaConfig{
+		Agent:  src.Agent,
+		Model:  src.Model,
+		Prompt: src.Prompt,
+		Rules:  slices.Clone(src.Rules),
+		Paths:  slices.Clone(src.Paths),
+	}
+}
+
+// setPersonaSources records the source for all fields of a named persona.
+func setPersonaSources(sources map[string]ConfigSource, name string, source ConfigSource) {
+	prefix := "review.personas." + name
+	sources[prefix+".agent"] = source
+	sources[prefix+".model"] = source
+	sources[prefix+".prompt"] = source
+	sources[prefix+".rules"] = source
+	sources[prefix+".paths"] = source
+}
+
+// setAnalyzerSources records the source for all fields of a named analyzer.
+func setAnalyzerSources(sources map[string]ConfigSource, name string, source ConfigSource) {
+	prefix := "review.analyzers." + name
+	sources[prefix+".command"] = source
+	sources[prefix+".format"] = source
+	sources[prefix+".pattern"] = source
+	sources[prefix+".severity"] = source
+	sources[prefix+".category"] = source
+	sources[prefix+".context"] = source
+}
+
 // copyWorkflowConfig returns a deep copy of a WorkflowConfig.
 func copyWorkflowConfig(src WorkflowConfig) WorkflowConfig {
 	wf := WorkflowConfig{
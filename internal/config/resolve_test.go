@@ -534,6 +534,25 @@ func TestResolve_FileVerificationCommands(t *testing.T) {
 	assert.Equal(t, SourceFile, rc.Sources["project.verification_commands"])
 }
 
+func TestResolve_FileProgressFormats(t *testing.T) {
+	t.Parallel()
+	defaults := NewDefaults()
+	fileConfig := &Config{
+		Project: ProjectConfig{
+			ProgressFormats: []string{"markdown", "json", "html", "burndown"},
+		},
+	}
+
+	rc := Resolve(defaults, fileConfig, noEnv, nil)
+
+	assert.Equal(t, []string{"markdown", "json", "html", "burndown"}, rc.Config.Project.ProgressFormats)
+	assert.Equal(t, SourceFile, rc.Sources["project.progress_formats"])
+
+	rc = Resolve(defaults, nil, noEnv, nil)
+	assert.Equal(t, []string{"markdown"}, rc.Config.Project.ProgressFormats)
+	assert.Equal(t, SourceDefault, rc.Sources["project.progress_formats"])
+}
+
 func TestResolve_DefaultVerificationCommands(t *testing.T) {
 	t.Parallel()
 	defaults := &Config{
@@ -619,12 +638,20 @@ func TestResolve_SourcesMap_Complete(t *testing.T) {
 		"project.log_dir",
 		"project.prompt_dir",
 		"project.branch_template",
+		"project.task_id_prefix",
+		"project.task_strategy",
 		"project.verification_commands",
+		"project.progress_formats",
 		"review.extensions",
 		"review.risk_patterns",
 		"review.prompts_dir",
 		"review.rules_dir",
 		"review.project_brief_file",
+		"review.invalid_findings",
+		"review.dedup",
+		"review.dedup_line_window",
+		"review.dedup_threshold",
+		"review.token_budget",
 	}
 	for _, key := range expectedKeys {
 		_, ok := rc.Sources[key]
@@ -889,3 +916,133 @@ func TestResolve_FileEmpty_KeepsDefaults(t *testing.T) {
 	assert.Equal(t, "scripts/logs", rc.Config.Project.LogDir)
 	assert.Equal(t, SourceDefault, rc.Sources["project.log_dir"])
 }
+
+func TestResolve_ReviewDedup_FileOverridesDefaults(t *testing.T) {
+	t.Parallel()
+
+	defaults := NewDefaults()
+	rc := Resolve(defaults, &Config{}, noEnv, nil)
+	assert.Equal(t, "semantic", rc.Config.Review.Dedup)
+	assert.Equal(t, intPtr(3), rc.Config.Review.DedupLineWindow)
+	assert.Equal(t, 0.6, rc.Config.Review.DedupThreshold)
+	assert.Equal(t, SourceDefault, rc.Sources["review.dedup_threshold"])
+
+	fileConfig := &Config{Review: ReviewConfig{Dedup: "exact", DedupLineWindow: intPtr(5), DedupThreshold: 0.8}}
+	rc = Resolve(defaults, fileConfig, noEnv, nil)
+	assert.Equal(t, "exact", rc.Config.Review.Dedup)
+	assert.Equal(t, intPtr(5), rc.Config.Review.DedupLineWindow)
+	assert.Equal(t, 0.8, rc.Config.Review.DedupThreshold)
+	assert.Equal(t, SourceFile, rc.Sources["review.dedup_line_window"])
+	assert.Equal(t, SourceFile, rc.Sources["review.dedup_threshold"])
+
+	// An explicit 0 window overrides the default and is not shared with
+	// the file config.
+	fileConfig = &Config{Review: ReviewConfig{DedupLineWindow: intPtr(0)}}
+	rc = Resolve(defaults, fileConfig, noEnv, nil)
+	assert.Equal(t, intPtr(0), rc.Config.Review.DedupLineWindow)
+	assert.NotSame(t, fileConfig.Review.DedupLineWindow, rc.Config.Review.DedupLineWindow)
+	assert.Equal(t, SourceFile, rc.Sources["review.dedup_line_window"])
+}
+
+func TestResolve_ReviewTokenBudgets(t *testing.T) {
+	t.Parallel()
+
+	defaults := NewDefaults()
+	defaults.Review.TokenBudgets = map[string]int{"claude": 100000, "gemini": 500000}
+	rc := Resolve(defaults, &Config{}, noEnv, nil)
+	assert.Zero(t, rc.Config.Review.TokenBudget)
+	assert.Equal(t, SourceDefault, rc.Sources["review.token_budget"])
+
+	fileConfig := &Config{Review: ReviewConfig{
+		TokenBudget:  60000,
+		TokenBudgets: map[string]int{"claude": 150000},
+	}}
+	rc = Resolve(defaults, fileConfig, noEnv, nil)
+	assert.Equal(t, 60000, rc.Config.Review.TokenBudget)
+	assert.Equal(t, SourceFile, rc.Sources["review.token_budget"])
+	assert.Equal(t, map[string]int{"claude": 150000, "gemini": 500000}, rc.Config.Review.TokenBudgets)
+	assert.Equal(t, SourceFile, rc.Sources["review.token_budgets.claude"])
+	assert.Equal(t, SourceDefault, rc.Sources["review.token_budgets.gemini"])
+
+	// The defaults are not modified by the merge.
+	assert.Equal(t, 100000, defaults.Review.TokenBudgets["claude"])
+}
+
+func TestResolve_ReviewPolicy(t *testing.T) {
+	t.Parallel()
+
+	defaults := NewDefaults()
+	defaults.Review.Policy = PolicyConfig{
+		AgentFailure: "changes_needed",
+		Weights:      map[string]float64{"claude": 2, "codex": 1},
+	}
+
+	fileConfig := &Config{Review: ReviewConfig{Policy: PolicyConfig{
+		BlockingQuorum: 2,
+		ApproveBelow:   "medium",
+		Weights:        map[string]float64{"codex": 0},
+	}}}
+	rc := Resolve(defaults, fileConfig, noEnv, nil)
+	p := rc.Config.Review.Policy
+	assert.InDelta(t, 2, p.BlockingQuorum, 0)
+	assert.Zero(t, p.ChangesQuorum)
+	assert.Equal(t, "medium", p.ApproveBelow)
+	assert.Equal(t, "changes_needed", p.AgentFailure)
+	assert.Equal(t, map[string]float64{"claude": 2, "codex": 0}, p.Weights)
+	assert.Equal(t, SourceFile, rc.Sources["review.policy.blocking_quorum"])
+	assert.Equal(t, SourceDefault, rc.Sources["review.policy.changes_quorum"])
+	assert.Equal(t, SourceDefault, rc.Sources["review.policy.agent_failure"])
+	assert.Equal(t, SourceFile, rc.Sources["review.policy.weights.codex"])
+	assert.Equal(t, SourceDefault, rc.Sources["review.policy.weights.claude"])
+
+	// The defaults are not modified by the merge.
+	assert.InDelta(t, 1, defaults.Review.Policy.Weights["codex"], 0)
+}
+
+func TestResolve_ReviewPersonas_MergeByName(t *testing.T) {
+	t.Parallel()
+
+	defaults := NewDefaults()
+	defaults.Review.Personas = map[string]PersonaConfig{
+		"security": {Agent: "claude", Paths: []string{"internal/**"}},
+		"tests":    {Agent: "codex"},
+	}
+	fileConfig := &Config{Review: ReviewConfig{Personas: map[string]PersonaConfig{
+		"security": {Agent: "gemini", Paths: []string{"auth/**"}},
+		"api":      {Agent: "claude"},
+	}}}
+
+	rc := Resolve(defaults, fileConfig, noEnv, nil)
+	require.Len(t, rc.Config.Review.Personas, 3)
+	assert.Equal(t, "gemini", rc.Config.Review.Personas["security"].Agent)
+	assert.Equal(t, []string{"auth/**"}, rc.Config.Review.Personas["security"].Paths)
+	assert.Equal(t, "codex", rc.Config.Review.Personas["tests"].Agent)
+	assert.Equal(t, SourceFile, rc.Sources["review.personas.security.agent"])
+	assert.Equal(t, SourceDefault, rc.Sources["review.personas.tests.paths"])
+	assert.Equal(t, SourceFile, rc.Sources["review.personas.api.model"])
+
+	// The resolved personas do not share slices with the inputs.
+	fileConfig.Review.Personas["security"].Paths[0] = "changed"
+	assert.Equal(t, []string{"auth/**"}, rc.Config.Review.Personas["security"].Paths)
+}
+
+func TestResolve_ReviewAnalyzers_MergeByName(t *testing.T) {
+	t.Parallel()
+
+	defaults := NewDefaults()
+	defaults.Review.Analyzers = map[string]AnalyzerConfig{
+		"vet":  {Command: "go vet ./...", Format: "line"},
+		"lint": {Command: "golangci-lint run", Format: "golangci-json"},
+	}
+	fileConfig := &Config{Review: ReviewConfig{Analyzers: map[string]AnalyzerConfig{
+		"vet": {Command: "go vet -tags integration ./...", Format: "line", Context: true},
+	}}}
+
+	rc := Resolve(defaults, fileConfig, noEnv, nil)
+	require.Len(t, rc.Config.Review.Analyzers, 2)
+	assert.Equal(t, "go vet -tags integration ./...", rc.Config.Review.Analyzers["vet"].Command)
+	assert.True(t, rc.Config.Review.Analyzers["vet"].Context)
+	assert.Equal(t, "golangci-lint run", rc.Config.Review.Analyzers["lint"].Command)
+	assert.Equal(t, SourceFile, rc.Sources["review.analyzers.vet.command"])
+	assert.Equal(t, SourceDefault, rc.Sources["review.analyzers.lint.format"])
+}
@@ -3,10 +3,13 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"regexp"
 	"strings"
 
 	"github.com/BurntSushi/toml"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
 )
 
 // ValidationSeverity indicates whether a validation issue is an error or warning.
@@ -84,6 +87,78 @@ var recognizedLanguages = map[string]bool{
 	"java":       true,
 }
 
+// validProgressFormats is the set of valid entries of
+// project.progress_formats. It mirrors task.ProgressFormats.
+var validProgressFormats = map[string]bool{
+	"markdown": true,
+	"json":     true,
+	"html":     true,
+	"burndown": true,
+}
+
+// validTaskStrategies is the set of valid values for project.task_strategy.
+// It mirrors the built-in strategies of task.NewSelectionStrategy.
+var validTaskStrategies = map[string]bool{
+	"":              true,
+	"id":            true,
+	"priority":      true,
+	"effort":        true,
+	"critical-path": true,
+	"unblock":       true,
+}
+
+// validInvalidFindingModes is the set of valid values for
+// review.invalid_findings. It mirrors review.InvalidFindingMode.
+var validInvalidFindingModes = map[string]bool{
+	"":          true,
+	"drop":      true,
+	"downgrade": true,
+}
+
+// validDedupModes is the set of valid values for review.dedup. It mirrors
+// review.DedupMode.
+var validDedupModes = map[string]bool{
+	"":         true,
+	"semantic": true,
+	"exact":    true,
+}
+
+// validPersonaAgents is the set of agents a review persona may use. It
+// mirrors the agents registered for review.
+var validPersonaAgents = map[string]bool{
+	"claude": true,
+	"codex":  true,
+	"gemini": true,
+}
+
+// validAnalyzerFormats is the set of valid values for a review analyzer's
+// format. It mirrors review.AnalyzerFormat.
+var validAnalyzerFormats = map[string]bool{
+	"sarif":         true,
+	"golangci-json": true,
+	"line":          true,
+}
+
+// validAgentFailureModes is the set of valid values for
+// review.policy.agent_failure. It mirrors review.AgentFailureMode.
+var validAgentFailureModes = map[string]bool{
+	"":               true,
+	"changes_needed": true,
+	"ignore":         true,
+	"blocking":       true,
+}
+
+// validAnalyzerSeverities is the set of valid values for a review analyzer's
+// default severity. It mirrors review.Severity.
+var validAnalyzerSeverities = map[string]bool{
+	"":         true,
+	"info":     true,
+	"low":      true,
+	"medium":   true,
+	"high":     true,
+	"critical": true,
+}
+
 // validEfforts is the set of valid values for agent effort.
 var validEfforts = map[string]bool{
 	"":       true,
@@ -138,6 +213,26 @@ func validateProject(vr *ValidationResult, p *ProjectConfig) {
 		}
 	}
 
+	// Error: task_id_prefix must be usable in task IDs and file names.
+	if p.TaskIDPrefix != "" {
+		if err := task.ValidateTaskIDPrefix(p.TaskIDPrefix); err != nil {
+			addError(vr, "project.task_id_prefix", err.Error())
+		}
+	}
+
+	// Error: task_strategy must name a built-in selection strategy.
+	if !validTaskStrategies[p.TaskStrategy] {
+		addError(vr, "project.task_strategy",
+			fmt.Sprintf("unknown strategy %q; must be one of: id, priority, effort, critical-path, unblock", p.TaskStrategy))
+	}
+
+	for i, f := range p.ProgressFormats {
+		if !validProgressFormats[f] {
+			addError(vr, fmt.Sprintf("project.progress_formats[%d]", i),
+				fmt.Sprintf("unknown format %q; must be one of: markdown, json, html, burndown", f))
+		}
+	}
+
 	// Warning: tasks_dir does not exist.
 	if p.TasksDir != "" {
 		if _, err := os.Stat(p.TasksDir); err != nil {
@@ -199,6 +294,45 @@ func validateReview(vr *ValidationResult, r *ReviewConfig) {
 		}
 	}
 
+	// Error: invalid_findings must name a known mode.
+	if !validInvalidFindingModes[r.InvalidFindings] {
+		addError(vr, "review.invalid_findings",
+			fmt.Sprintf("unknown mode %q; must be one of: drop, downgrade", r.InvalidFindings))
+	}
+
+	// Error: dedup must name a known mode, with thresholds in range.
+	if !validDedupModes[r.Dedup] {
+		addError(vr, "review.dedup",
+			fmt.Sprintf("unknown mode %q; must be one of: semantic, exact", r.Dedup))
+	}
+	if r.DedupLineWindow != nil && *r.DedupLineWindow < 0 {
+		addError(vr, "review.dedup_line_window",
+			fmt.Sprintf("must not be negative, got %d", *r.DedupLineWindow))
+	}
+	if r.DedupThreshold < 0 || r.DedupThreshold > 1 {
+		addError(vr, "review.dedup_threshold",
+			fmt.Sprintf("must be between 0 and 1, got %g", r.DedupThreshold))
+	}
+
+	if r.TokenBudget < 0 {
+		addError(vr, "review.token_budget",
+			fmt.Sprintf("must not be negative, got %d", r.TokenBudget))
+	}
+	for name, budget := range r.TokenBudgets {
+		if budget < 0 {
+			addError(vr, "review.token_budgets."+name,
+				fmt.Sprintf("must not be negative, got %d", budget))
+		}
+		if !validPersonaAgents[name] {
+			addWarning(vr, "review.token_budgets."+name,
+				fmt.Sprintf("unknown agent %q; must be one of: claude, codex, gemini", name))
+		}
+	}
+
+	validatePersonas(vr, r.Personas)
+	validateAnalyzers(vr, r.Analyzers)
+	validatePolicy(vr, r)
+
 	// Warning: prompts_dir does not exist.
 	if r.PromptsDir != "" {
 		if _, err := os.Stat(r.PromptsDir); err != nil {
@@ -216,6 +350,152 @@ func validateReview(vr *ValidationResult, r *ReviewConfig) {
 	}
 }
 
+// validatePersonas checks all [review.personas.*] sections.
+func validatePersonas(vr *ValidationResult, personas map[string]PersonaConfig) {
+	for name, persona := range personas {
+		prefix := "review.personas." + name
+
+		// Error: agent must name a review agent.
+		if persona.Agent == "" {
+			addError(vr, prefix+".agent", "must not be empty")
+		} else if !validPersonaAgents[persona.Agent] {
+			addError(vr, prefix+".agent",
+				fmt.Sprintf("unknown agent %q; must be one of: claude, codex, gemini", persona.Agent))
+		}
+
+		// Error: paths must be well-formed globs.
+		for i, glob := range persona.Paths {
+			if err := validatePathGlob(glob); err != nil {
+				addError(vr, fmt.Sprintf("%s.paths[%d]", prefix, i), err.Error())
+			}
+		}
+
+		// Warning: prompt and rule files do not exist.
+		if persona.Prompt != "" {
+			if _, err := os.Stat(persona.Prompt); err != nil {
+				addWarning(vr, prefix+".prompt",
+					fmt.Sprintf("file %q does not exist", persona.Prompt))
+			}
+		}
+		for i, rule := range persona.Rules {
+			if _, err := os.Stat(rule); err != nil {
+				addWarning(vr, fmt.Sprintf("%s.rules[%d]", prefix, i),
+					fmt.Sprintf("file %q does not exist", rule))
+			}
+		}
+	}
+}
+
+// validateAnalyzers checks all [review.analyzers.*] sections.
+func validateAnalyzers(vr *ValidationResult, analyzers map[string]AnalyzerConfig) {
+	for name, analyzer := range analyzers {
+		prefix := "review.analyzers." + name
+
+		// Error: command must be set.
+		if strings.TrimSpace(analyzer.Command) == "" {
+			addError(vr, prefix+".command", "must not be empty")
+		}
+
+		// Error: format and severity must name known values.
+		if analyzer.Format == "" {
+			addError(vr, prefix+".format", "must not be empty; must be one of: sarif, golangci-json, line")
+		} else if !validAnalyzerFormats[analyzer.Format] {
+			addError(vr, prefix+".format",
+				fmt.Sprintf("unknown format %q; must be one of: sarif, golangci-json, line", analyzer.Format))
+		}
+		if !validAnalyzerSeverities[analyzer.Severity] {
+			addError(vr, prefix+".severity",
+				fmt.Sprintf("unknown severity %q; must be one of: info, low, medium, high, critical", analyzer.Severity))
+		}
+
+		// Error: pattern must compile with the groups review.Analyzer reads.
+		// Warning: pattern is only used by the line format.
+		if analyzer.Pattern != "" {
+			if analyzer.Format != "line" {
+				addWarning(vr, prefix+".pattern",
+					fmt.Sprintf("ignored for format %q; only the line format uses a pattern", analyzer.Format))
+			} else if err := validateAnalyzerPattern(analyzer.Pattern); err != nil {
+				addError(vr, prefix+".pattern", err.Error())
+			}
+		}
+	}
+}
+
+// validatePolicy checks the [review.policy] section. Weights may name review
+// agents, personas of r, or analyzers of r as "analyzer:<name>".
+func validatePolicy(vr *ValidationResult, r *ReviewConfig) {
+	p := &r.Policy
+
+	// Error: quorums and weights must not be negative.
+	if p.BlockingQuorum < 0 {
+		addError(vr, "review.policy.blocking_quorum",
+			fmt.Sprintf("must not be negative, got %g", p.BlockingQuorum))
+	}
+	if p.ChangesQuorum < 0 {
+		addError(vr, "review.policy.changes_quorum",
+			fmt.Sprintf("must not be negative, got %g", p.ChangesQuorum))
+	}
+	for name, weight := range p.Weights {
+		path := "review.policy.weights." + name
+		if weight < 0 {
+			addError(vr, path, fmt.Sprintf("must not be negative, got %g", weight))
+		}
+
+		// Warning: the weight names no reviewer, so it has no effect.
+		_, isPersona := r.Personas[name]
+		_, isAnalyzer := r.Analyzers[strings.TrimPrefix(name, "analyzer:")]
+		if !validPersonaAgents[name] && !isPersona && !(strings.HasPrefix(name, "analyzer:") && isAnalyzer) {
+			addWarning(vr, path,
+				fmt.Sprintf("unknown reviewer %q; must be a review agent, a persona, or analyzer:<name>", name))
+		}
+	}
+
+	// Error: severities and the failure mode must name known values.
+	if !validAnalyzerSeverities[p.BlockSeverity] {
+		addError(vr, "review.policy.block_severity",
+			fmt.Sprintf("unknown severity %q; must be one of: info, low, medium, high, critical", p.BlockSeverity))
+	}
+	if !validAnalyzerSeverities[p.ApproveBelow] {
+		addError(vr, "review.policy.approve_below",
+			fmt.Sprintf("unknown severity %q; must be one of: info, low, medium, high, critical", p.ApproveBelow))
+	}
+	if !validAgentFailureModes[p.AgentFailure] {
+		addError(vr, "review.policy.agent_failure",
+			fmt.Sprintf("unknown mode %q; must be one of: changes_needed, ignore, blocking", p.AgentFailure))
+	}
+}
+
+// validateAnalyzerPattern checks a line-format pattern the way
+// review.Analyzer does: it must compile and have the named groups file,
+// line, and message.
+func validateAnalyzerPattern(pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid regular expression: %v", err)
+	}
+	for _, name := range []string{"file", "line", "message"} {
+		if re.SubexpIndex(name) < 0 {
+			return fmt.Errorf("missing named group (?P<%s>...)", name)
+		}
+	}
+	return nil
+}
+
+// validatePathGlob checks a persona path glob the way review.ValidatePathGlob
+// does: it must not be empty and each "/"-separated segment must be a valid
+// path.Match pattern.
+func validatePathGlob(glob string) error {
+	if strings.TrimSpace(glob) == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	for _, seg := range strings.Split(glob, "/") {
+		if _, err := path.Match(seg, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %v", glob, err)
+		}
+	}
+	return nil
+}
+
 // validateWorkflows checks all [workflows.*] sections.
 func validateWorkflows(vr *ValidationResult, workflows map[string]WorkflowConfig) {
 	for name, wf := range workflows {
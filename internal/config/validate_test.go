@@ -264,6 +264,97 @@ func TestValidate_InvalidProjectLanguage(t *testing.T) {
 	}
 }
 
+func TestValidate_TaskIDPrefix(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		prefix  string
+		wantErr bool
+	}{
+		{name: "empty uses default", prefix: "", wantErr: false},
+		{name: "default T", prefix: "T", wantErr: false},
+		{name: "multi-letter", prefix: "API", wantErr: false},
+		{name: "letters and digits", prefix: "WEB2", wantErr: false},
+		{name: "lowercase", prefix: "api", wantErr: true},
+		{name: "leading digit", prefix: "2WEB", wantErr: true},
+		{name: "trailing hyphen", prefix: "T-", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := validConfig()
+			cfg.Project.TaskIDPrefix = tt.prefix
+			vr := Validate(cfg, nil)
+			hasErr := false
+			for _, e := range vr.Errors() {
+				if e.Field == "project.task_id_prefix" {
+					hasErr = true
+				}
+			}
+			assert.Equal(t, tt.wantErr, hasErr, "prefix=%q", tt.prefix)
+		})
+	}
+}
+
+func TestValidate_TaskStrategy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		strategy string
+		wantErr  bool
+	}{
+		{strategy: "", wantErr: false},
+		{strategy: "id", wantErr: false},
+		{strategy: "critical-path", wantErr: false},
+		{strategy: "unblock", wantErr: false},
+		{strategy: "random", wantErr: true},
+		{strategy: "Priority", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			t.Parallel()
+			cfg := validConfig()
+			cfg.Project.TaskStrategy = tt.strategy
+			vr := Validate(cfg, nil)
+			hasErr := false
+			for _, e := range vr.Errors() {
+				if e.Field == "project.task_strategy" {
+					hasErr = true
+				}
+			}
+			assert.Equal(t, tt.wantErr, hasErr, "strategy=%q", tt.strategy)
+		})
+	}
+}
+
+func TestValidate_ProgressFormats(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		formats []string
+		wantErr bool
+	}{
+		{name: "none", formats: nil, wantErr: false},
+		{name: "all", formats: []string{"markdown", "json", "html", "burndown"}, wantErr: false},
+		{name: "unknown", formats: []string{"markdown", "pdf"}, wantErr: true},
+		{name: "case sensitive", formats: []string{"JSON"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := validConfig()
+			cfg.Project.ProgressFormats = tt.formats
+			vr := Validate(cfg, nil)
+			hasErr := false
+			for _, e := range vr.Errors() {
+				if strings.HasPrefix(e.Field, "project.progress_formats") {
+					hasErr = true
+				}
+			}
+			assert.Equal(t, tt.wantErr, hasErr, "formats=%v", tt.formats)
+		})
+	}
+}
+
 func TestValidate_EmptyVerificationCommand(t *testing.T) {
 	t.Parallel()
 	cfg := validConfig()
@@ -1353,3 +1444,200 @@ func TestValidate_EmptyPathFields_NoWarning(t *testing.T) {
 		}
 	}
 }
+
+func TestValidate_ReviewInvalidFindings(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []string{"", "drop", "downgrade"} {
+		cfg := validConfig()
+		cfg.Review.InvalidFindings = mode
+		vr := Validate(cfg, nil)
+		for _, e := range vr.Errors() {
+			assert.NotEqual(t, "review.invalid_findings", e.Field, "mode %q", mode)
+		}
+	}
+
+	cfg := validConfig()
+	cfg.Review.InvalidFindings = "ignore"
+	vr := Validate(cfg, nil)
+	found := false
+	for _, e := range vr.Errors() {
+		if e.Field == "review.invalid_findings" {
+			found = true
+			assert.Contains(t, e.Message, "drop, downgrade")
+		}
+	}
+	assert.True(t, found, "expected error on unknown review.invalid_findings")
+}
+
+func TestValidate_ReviewDedup(t *testing.T) {
+	t.Parallel()
+
+	errorFields := func(cfg *Config) []string {
+		var fields []string
+		for _, e := range Validate(cfg, nil).Errors() {
+			fields = append(fields, e.Field)
+		}
+		return fields
+	}
+
+	for _, mode := range []string{"", "semantic", "exact"} {
+		cfg := validConfig()
+		cfg.Review.Dedup = mode
+		cfg.Review.DedupLineWindow = intPtr(5)
+		cfg.Review.DedupThreshold = 1
+		assert.NotContains(t, errorFields(cfg), "review.dedup", "mode %q", mode)
+	}
+
+	cfg := validConfig()
+	cfg.Review.Dedup = "fuzzy"
+	cfg.Review.DedupLineWindow = intPtr(-1)
+	cfg.Review.DedupThreshold = 1.5
+	fields := errorFields(cfg)
+	assert.Contains(t, fields, "review.dedup")
+	assert.Contains(t, fields, "review.dedup_line_window")
+	assert.Contains(t, fields, "review.dedup_threshold")
+}
+
+func TestValidate_ReviewTokenBudgets(t *testing.T) {
+	t.Parallel()
+
+	cfg := validConfig()
+	cfg.Review.TokenBudget = 60000
+	cfg.Review.TokenBudgets = map[string]int{"claude": 0, "codex": 100000}
+	result := Validate(cfg, nil)
+	assert.Empty(t, result.Errors())
+	for _, w := range result.Warnings() {
+		assert.NotContains(t, w.Field, "review.token_budget")
+	}
+
+	cfg.Review.TokenBudget = -1
+	cfg.Review.TokenBudgets = map[string]int{"claude": -5, "gpt": 1000}
+	result = Validate(cfg, nil)
+	var errs, warnings []string
+	for _, e := range result.Errors() {
+		errs = append(errs, e.Field)
+	}
+	for _, w := range result.Warnings() {
+		warnings = append(warnings, w.Field)
+	}
+	assert.ElementsMatch(t, []string{"review.token_budget", "review.token_budgets.claude"}, errs)
+	assert.Contains(t, warnings, "review.token_budgets.gpt")
+}
+
+func TestValidate_ReviewPolicy(t *testing.T) {
+	t.Parallel()
+
+	cfg := validConfig()
+	cfg.Review.Personas = map[string]PersonaConfig{"security": {Agent: "claude"}}
+	cfg.Review.Analyzers = map[string]AnalyzerConfig{"vet": {Command: "go vet ./...", Format: "line"}}
+	cfg.Review.Policy = PolicyConfig{
+		BlockingQuorum: 2,
+		ChangesQuorum:  1.5,
+		BlockSeverity:  "critical",
+		ApproveBelow:   "medium",
+		AgentFailure:   "ignore",
+		Weights:        map[string]float64{"claude": 2, "security": 3, "analyzer:vet": 0},
+	}
+	result := Validate(cfg, nil)
+	assert.Empty(t, result.Errors())
+	for _, w := range result.Warnings() {
+		assert.NotContains(t, w.Field, "review.policy")
+	}
+
+	cfg.Review.Policy = PolicyConfig{
+		BlockingQuorum: -1,
+		ChangesQuorum:  -1,
+		BlockSeverity:  "severe",
+		ApproveBelow:   "minor",
+		AgentFailure:   "retry",
+		Weights:        map[string]float64{"claude": -1, "gpt": 1, "analyzer:lint": 1},
+	}
+	result = Validate(cfg, nil)
+	var errs, warnings []string
+	for _, e := range result.Errors() {
+		errs = append(errs, e.Field)
+	}
+	for _, w := range result.Warnings() {
+		warnings = append(warnings, w.Field)
+	}
+	assert.ElementsMatch(t, []string{
+		"review.policy.blocking_quorum",
+		"review.policy.changes_quorum",
+		"review.policy.block_severity",
+		"review.policy.approve_below",
+		"review.policy.agent_failure",
+		"review.policy.weights.claude",
+	}, errs)
+	assert.Contains(t, warnings, "review.policy.weights.gpt")
+	assert.Contains(t, warnings, "review.policy.weights.analyzer:lint")
+}
+
+func TestValidate_ReviewPersonas(t *testing.T) {
+	t.Parallel()
+
+	issueFields := func(issues []ValidationIssue) []string {
+		var fields []string
+		for _, i := range issues {
+			fields = append(fields, i.Field)
+		}
+		return fields
+	}
+
+	cfg := validConfig()
+	cfg.Review.Personas = map[string]PersonaConfig{
+		"security": {Agent: "claude", Paths: []string{"internal/**/*.go", "*_test.go"}},
+	}
+	result := Validate(cfg, nil)
+	assert.NotContains(t, issueFields(result.Errors()), "review.personas.security.agent")
+
+	cfg.Review.Personas = map[string]PersonaConfig{
+		"security": {Agent: "", Paths: []string{"internal/[a-"}},
+		"api":      {Agent: "copilot", Paths: []string{""}},
+		"tests":    {Agent: "codex", Prompt: "/nonexistent/tests.md", Rules: []string{"/nonexistent/rule.md"}},
+	}
+	result = Validate(cfg, nil)
+	errs := issueFields(result.Errors())
+	assert.Contains(t, errs, "review.personas.security.agent")
+	assert.Contains(t, errs, "review.personas.security.paths[0]")
+	assert.Contains(t, errs, "review.personas.api.agent")
+	assert.Contains(t, errs, "review.personas.api.paths[0]")
+	warns := issueFields(result.Warnings())
+	assert.Contains(t, warns, "review.personas.tests.prompt")
+	assert.Contains(t, warns, "review.personas.tests.rules[0]")
+}
+
+func TestValidate_ReviewAnalyzers(t *testing.T) {
+	t.Parallel()
+
+	issueFields := func(issues []ValidationIssue) []string {
+		var fields []string
+		for _, i := range issues {
+			fields = append(fields, i.Field)
+		}
+		return fields
+	}
+
+	cfg := validConfig()
+	cfg.Review.Analyzers = map[string]AnalyzerConfig{
+		"vet":  {Command: "go vet ./...", Format: "line", Severity: "high"},
+		"lint": {Command: "golangci-lint run --out-format json", Format: "golangci-json"},
+	}
+	result := Validate(cfg, nil)
+	assert.Empty(t, result.Errors())
+
+	cfg.Review.Analyzers = map[string]AnalyzerConfig{
+		"vet":    {Command: "", Format: "line", Pattern: `^(?P<file>.+):(\d+)$`},
+		"sc":     {Command: "staticcheck", Format: "xml", Severity: "urgent"},
+		"nofmt":  {Command: "x"},
+		"ignore": {Command: "x", Format: "sarif", Pattern: "(.*)"},
+	}
+	result = Validate(cfg, nil)
+	errs := issueFields(result.Errors())
+	assert.Contains(t, errs, "review.analyzers.vet.command")
+	assert.Contains(t, errs, "review.analyzers.vet.pattern")
+	assert.Contains(t, errs, "review.analyzers.sc.format")
+	assert.Contains(t, errs, "review.analyzers.sc.severity")
+	assert.Contains(t, errs, "review.analyzers.nofmt.format")
+	assert.Contains(t, issueFields(result.Warnings()), "review.analyzers.ignore.pattern")
+}
@@ -12,6 +12,11 @@ import (
 
 // Client is the interface for git operations used by the review pipeline.
 // It exposes only the diff-related methods required by diff generation.
+//
+// The base argument of every method is either a ref, in which case the diff
+// covers base...HEAD (changes on HEAD since it forked from base), or an
+// explicit commit range containing "..", such as "abc123..HEAD", which is
+// passed to git unchanged.
 type Client interface {
 	// DiffFiles returns the list of files changed between base and HEAD.
 	DiffFiles(ctx context.Context, base string) ([]DiffEntry, error)
@@ -25,6 +30,14 @@ type Client interface {
 	// DiffNumStat returns per-file line-change counts between base and HEAD.
 	// Each NumStatEntry holds the path, lines added, and lines deleted.
 	DiffNumStat(ctx context.Context, base string) ([]NumStatEntry, error)
+
+	// DiffTargetFiles, DiffTargetUnified, and DiffTargetNumStat are
+	// DiffFiles, DiffUnified, and DiffNumStat for the changes selected by
+	// target: a branch, commit range, single commit, the index, or the
+	// working tree.
+	DiffTargetFiles(ctx context.Context, target DiffTarget) ([]DiffEntry, error)
+	DiffTargetUnified(ctx context.Context, target DiffTarget) (string, error)
+	DiffTargetNumStat(ctx context.Context, target DiffTarget) ([]NumStatEntry, error)
 }
 
 // Compile-time check: *GitClient must satisfy Client.
@@ -178,7 +191,7 @@ type DiffEntry struct {
 
 // DiffFiles returns a list of files changed between base and HEAD.
 func (g *GitClient) DiffFiles(ctx context.Context, base string) ([]DiffEntry, error) {
-	out, err := g.run(ctx, "diff", "--name-status", base+"...HEAD")
+	out, err := g.run(ctx, "diff", "--name-status", revisionRange(base))
 	if err != nil {
 		return nil, fmt.Errorf("git: diff files from %q: %w", base, err)
 	}
@@ -221,7 +234,7 @@ type DiffStats struct {
 
 // DiffStat returns aggregate change statistics between base and HEAD.
 func (g *GitClient) DiffStat(ctx context.Context, base string) (*DiffStats, error) {
-	out, err := g.run(ctx, "diff", "--stat", base+"...HEAD")
+	out, err := g.run(ctx, "diff", "--stat", revisionRange(base))
 	if err != nil {
 		return nil, fmt.Errorf("git: diff stat from %q: %w", base, err)
 	}
@@ -297,13 +310,23 @@ func parseLeadingInt(s string) (int, error) {
 
 // DiffUnified returns the full unified diff between base and HEAD.
 func (g *GitClient) DiffUnified(ctx context.Context, base string) (string, error) {
-	out, err := g.run(ctx, "diff", base+"...HEAD")
+	out, err := g.run(ctx, "diff", revisionRange(base))
 	if err != nil {
 		return "", fmt.Errorf("git: diff unified from %q: %w", base, err)
 	}
 	return out, nil
 }
 
+// revisionRange returns the git revision range for a diff base: explicit
+// ranges such as "abc123..HEAD" are used as-is, anything else is treated as a
+// ref and diffed as base...HEAD.
+func revisionRange(base string) string {
+	if strings.Contains(base, "..") {
+		return base
+	}
+	return base + "...HEAD"
+}
+
 // NumStatEntry holds per-file line-change counts from git diff --numstat.
 type NumStatEntry struct {
 	// Path is the file path relative to the repository root.
@@ -323,7 +346,7 @@ type NumStatEntry struct {
 // DiffNumStat returns per-file line-change counts between base and HEAD using
 // git diff --numstat. Binary files are returned with Added and Deleted set to -1.
 func (g *GitClient) DiffNumStat(ctx context.Context, base string) ([]NumStatEntry, error) {
-	out, err := g.run(ctx, "diff", "--numstat", base+"...HEAD")
+	out, err := g.run(ctx, "diff", "--numstat", revisionRange(base))
 	if err != nil {
 		return nil, fmt.Errorf("git: diff numstat from %q: %w", base, err)
 	}
@@ -410,6 +433,20 @@ func parseRenamePath(rawPath string) (oldPath, newPath string) {
 	return "", rawPath
 }
 
+// UserName returns the configured git user.name, or "" when it is not set.
+func (g *GitClient) UserName(ctx context.Context) (string, error) {
+	code, stdout, _, err := g.runSilent(ctx, "config", "user.name")
+	switch {
+	case err == nil:
+		return strings.TrimSpace(stdout), nil
+	case code == 1:
+		// git config exits 1 when the key is not set.
+		return "", nil
+	default:
+		return "", fmt.Errorf("git: config user.name: %w", err)
+	}
+}
+
 // --- Log Operations ---
 
 // HeadCommit returns the short SHA of the current HEAD commit.
@@ -421,6 +458,30 @@ func (g *GitClient) HeadCommit(ctx context.Context) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
+// ResolveCommit returns the full SHA of the commit ref points to.
+func (g *GitClient) ResolveCommit(ctx context.Context, ref string) (string, error) {
+	out, err := g.run(ctx, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("git: resolve commit %q: %w", ref, err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// IsAncestor reports whether ancestor is reachable from ref, i.e. whether ref
+// contains every commit of ancestor. It returns false for a commit that was
+// rewritten by a rebase or force-push.
+func (g *GitClient) IsAncestor(ctx context.Context, ancestor, ref string) (bool, error) {
+	code, _, _, err := g.runSilent(ctx, "merge-base", "--is-ancestor", ancestor, ref)
+	switch {
+	case err == nil:
+		return true, nil
+	case code == 1:
+		return false, nil
+	default:
+		return false, fmt.Errorf("git: merge-base --is-ancestor %q %q: %w", ancestor, ref, err)
+	}
+}
+
 // LogEntry represents a single commit in the log.
 type LogEntry struct {
 	SHA     string
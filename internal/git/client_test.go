@@ -190,6 +190,13 @@ func TestStash_DirtyRepo(t *testing.T) {
 // Log tests
 // ---------------------------------------------------------------------------
 
+func TestUserName(t *testing.T) {
+	c := newTestRepo(t)
+	name, err := c.UserName(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Test", name)
+}
+
 func TestHeadCommit(t *testing.T) {
 	c := newTestRepo(t)
 	sha, err := c.HeadCommit(context.Background())
@@ -275,6 +282,83 @@ func TestDiffUnified(t *testing.T) {
 	assert.Contains(t, diff, "README.md")
 }
 
+func TestDiff_ExplicitRange(t *testing.T) {
+	c := newTestRepo(t)
+	ctx := context.Background()
+
+	writeFile(t, c.WorkDir, "first.txt", "first\n")
+	mustRun(t, c.WorkDir, "git", "add", ".")
+	mustRun(t, c.WorkDir, "git", "commit", "-m", "First")
+	mid, err := c.ResolveCommit(ctx, "HEAD")
+	require.NoError(t, err)
+
+	writeFile(t, c.WorkDir, "second.txt", "second\n")
+	mustRun(t, c.WorkDir, "git", "add", ".")
+	mustRun(t, c.WorkDir, "git", "commit", "-m", "Second")
+
+	entries, err := c.DiffFiles(ctx, mid+"..HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, []DiffEntry{{Status: "A", Path: "second.txt"}}, entries)
+
+	numStats, err := c.DiffNumStat(ctx, mid+"..HEAD")
+	require.NoError(t, err)
+	require.Len(t, numStats, 1)
+	assert.Equal(t, "second.txt", numStats[0].Path)
+
+	diff, err := c.DiffUnified(ctx, mid+"..HEAD")
+	require.NoError(t, err)
+	assert.Contains(t, diff, "second.txt")
+	assert.NotContains(t, diff, "first.txt")
+}
+
+func TestRevisionRange(t *testing.T) {
+	assert.Equal(t, "main...HEAD", revisionRange("main"))
+	assert.Equal(t, "abc123..HEAD", revisionRange("abc123..HEAD"))
+	assert.Equal(t, "a...b", revisionRange("a...b"))
+}
+
+func TestResolveCommit(t *testing.T) {
+	c := newTestRepo(t)
+	ctx := context.Background()
+
+	sha, err := c.ResolveCommit(ctx, "HEAD")
+	require.NoError(t, err)
+	assert.Len(t, sha, 40)
+
+	short, err := c.HeadCommit(ctx)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sha, short))
+
+	_, err = c.ResolveCommit(ctx, "no-such-ref")
+	require.Error(t, err)
+}
+
+func TestIsAncestor(t *testing.T) {
+	c := newTestRepo(t)
+	ctx := context.Background()
+
+	first, err := c.ResolveCommit(ctx, "HEAD")
+	require.NoError(t, err)
+	writeFile(t, c.WorkDir, "second.txt", "second\n")
+	mustRun(t, c.WorkDir, "git", "add", ".")
+	mustRun(t, c.WorkDir, "git", "commit", "-m", "Second")
+	second, err := c.ResolveCommit(ctx, "HEAD")
+	require.NoError(t, err)
+
+	ok, err := c.IsAncestor(ctx, first, "HEAD")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Rewrite the second commit: the old SHA is no longer reachable.
+	mustRun(t, c.WorkDir, "git", "commit", "--amend", "-m", "Second (amended)")
+	ok, err = c.IsAncestor(ctx, second, "HEAD")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = c.IsAncestor(ctx, "no-such-ref", "HEAD")
+	require.Error(t, err)
+}
+
 // ---------------------------------------------------------------------------
 // EnsureClean tests
 // ---------------------------------------------------------------------------
@@ -0,0 +1,159 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DiffTargetKind selects which changes a DiffTarget covers.
+type DiffTargetKind string
+
+const (
+	// TargetBranch diffs HEAD against the merge base with Ref, i.e.
+	// Ref...HEAD. An explicit range in Ref is used as-is, as by DiffFiles.
+	TargetBranch DiffTargetKind = "branch"
+
+	// TargetRange diffs an explicit commit range, "A..B" or "A...B", given
+	// in Ref.
+	TargetRange DiffTargetKind = "range"
+
+	// TargetCommit diffs the single commit Ref against its first parent, or
+	// against the empty tree when Ref is a root commit.
+	TargetCommit DiffTargetKind = "commit"
+
+	// TargetStaged diffs the index against HEAD: the changes that the next
+	// commit would contain.
+	TargetStaged DiffTargetKind = "staged"
+
+	// TargetWorktree diffs the working tree against HEAD: all uncommitted
+	// changes to tracked files, staged or not.
+	TargetWorktree DiffTargetKind = "worktree"
+)
+
+// DiffTarget describes the changes a diff covers. Ref is the base branch,
+// range, or commit for the kinds that take one, and empty for TargetStaged
+// and TargetWorktree.
+type DiffTarget struct {
+	Kind DiffTargetKind
+	Ref  string
+}
+
+// String returns a short human-readable description of the target, e.g.
+// "main...HEAD", "commit abc1234", or "staged changes".
+func (t DiffTarget) String() string {
+	switch t.Kind {
+	case TargetRange:
+		return t.Ref
+	case TargetCommit:
+		return "commit " + t.Ref
+	case TargetStaged:
+		return "staged changes"
+	case TargetWorktree:
+		return "working tree"
+	default:
+		return revisionRange(t.Ref)
+	}
+}
+
+// Revision returns the revision holding the new side of the target's
+// changes when it is not the checked-out tree: the end of a range (HEAD when
+// omitted) or the commit. It returns "" for branch, staged, and worktree
+// targets, whose new side is the working tree.
+func (t DiffTarget) Revision() string {
+	switch t.Kind {
+	case TargetRange:
+		_, to, ok := strings.Cut(t.Ref, "...")
+		if !ok {
+			_, to, _ = strings.Cut(t.Ref, "..")
+		}
+		if to == "" {
+			return "HEAD"
+		}
+		return to
+	case TargetCommit:
+		return t.Ref
+	default:
+		return ""
+	}
+}
+
+// diffArgs returns the git diff arguments that select the target's changes.
+func (t DiffTarget) diffArgs() []string {
+	switch t.Kind {
+	case TargetRange:
+		return []string{t.Ref}
+	case TargetCommit:
+		return []string{t.Ref + "^", t.Ref}
+	case TargetStaged:
+		return []string{"--cached", "HEAD"}
+	case TargetWorktree:
+		return []string{"HEAD"}
+	default:
+		return []string{revisionRange(t.Ref)}
+	}
+}
+
+// diffTarget runs git diff with flags over target's changes. A root commit
+// has no parent to diff against, so its changes are listed by
+// "git diff-tree --root" instead.
+func (g *GitClient) diffTarget(ctx context.Context, target DiffTarget, flags ...string) (string, error) {
+	if target.Kind == TargetCommit {
+		root, err := g.isRootCommit(ctx, target.Ref)
+		if err != nil {
+			return "", err
+		}
+		if root {
+			args := append([]string{"diff-tree", "-r", "--root", "--no-commit-id"}, flags...)
+			return g.run(ctx, append(args, target.Ref)...)
+		}
+	}
+	args := append([]string{"diff"}, flags...)
+	return g.run(ctx, append(args, target.diffArgs()...)...)
+}
+
+// isRootCommit reports whether ref is a commit without parents.
+func (g *GitClient) isRootCommit(ctx context.Context, ref string) (bool, error) {
+	out, err := g.run(ctx, "rev-list", "--parents", "-n", "1", ref)
+	if err != nil {
+		return false, err
+	}
+	return len(strings.Fields(out)) == 1, nil
+}
+
+// DiffTargetFiles returns the list of files changed in target.
+func (g *GitClient) DiffTargetFiles(ctx context.Context, target DiffTarget) ([]DiffEntry, error) {
+	out, err := g.diffTarget(ctx, target, "--name-status")
+	if err != nil {
+		return nil, fmt.Errorf("git: diff files of %s: %w", target, err)
+	}
+	return parseDiffNameStatus(out), nil
+}
+
+// DiffTargetUnified returns the full unified diff of target.
+func (g *GitClient) DiffTargetUnified(ctx context.Context, target DiffTarget) (string, error) {
+	out, err := g.diffTarget(ctx, target, "-p")
+	if err != nil {
+		return "", fmt.Errorf("git: diff unified of %s: %w", target, err)
+	}
+	return out, nil
+}
+
+// DiffTargetNumStat returns per-file line-change counts of target.
+func (g *GitClient) DiffTargetNumStat(ctx context.Context, target DiffTarget) ([]NumStatEntry, error) {
+	out, err := g.diffTarget(ctx, target, "--numstat")
+	if err != nil {
+		return nil, fmt.Errorf("git: diff numstat of %s: %w", target, err)
+	}
+	return parseNumStat(out), nil
+}
+
+// FetchRefs fetches refspecs from remote without updating the working tree,
+// making their commits available to diffs.
+func (g *GitClient) FetchRefs(ctx context.Context, remote string, refspecs ...string) error {
+	args := append([]string{"fetch", "--quiet", remote}, refspecs...)
+	if _, err := g.run(ctx, args...); err != nil {
+		return fmt.Errorf("git: fetch %v from %s: %w", refspecs, remote, err)
+	}
+	return nil
+}
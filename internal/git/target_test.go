@@ -0,0 +1,166 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffTarget_String(t *testing.T) {
+	tests := []struct {
+		target DiffTarget
+		want   string
+	}{
+		{DiffTarget{Kind: TargetBranch, Ref: "main"}, "main...HEAD"},
+		{DiffTarget{Ref: "develop"}, "develop...HEAD"},
+		{DiffTarget{Kind: TargetRange, Ref: "v1.0..v1.1"}, "v1.0..v1.1"},
+		{DiffTarget{Kind: TargetCommit, Ref: "abc1234"}, "commit abc1234"},
+		{DiffTarget{Kind: TargetStaged}, "staged changes"},
+		{DiffTarget{Kind: TargetWorktree}, "working tree"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.target.String())
+	}
+}
+
+func TestDiffTarget_Revision(t *testing.T) {
+	tests := []struct {
+		target DiffTarget
+		want   string
+	}{
+		{DiffTarget{Kind: TargetBranch, Ref: "main"}, ""},
+		{DiffTarget{Ref: "develop"}, ""},
+		{DiffTarget{Kind: TargetRange, Ref: "v1.0..v1.1"}, "v1.1"},
+		{DiffTarget{Kind: TargetRange, Ref: "main...feature"}, "feature"},
+		{DiffTarget{Kind: TargetRange, Ref: "HEAD~3.."}, "HEAD"},
+		{DiffTarget{Kind: TargetCommit, Ref: "abc1234"}, "abc1234"},
+		{DiffTarget{Kind: TargetStaged}, ""},
+		{DiffTarget{Kind: TargetWorktree}, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.target.Revision(), tt.target.String())
+	}
+}
+
+func TestDiffTarget_Commit(t *testing.T) {
+	c := newTestRepo(t)
+	ctx := context.Background()
+
+	writeFile(t, c.WorkDir, "first.txt", "first\n")
+	mustRun(t, c.WorkDir, "git", "add", ".")
+	mustRun(t, c.WorkDir, "git", "commit", "-m", "First")
+	first, err := c.ResolveCommit(ctx, "HEAD")
+	require.NoError(t, err)
+
+	writeFile(t, c.WorkDir, "second.txt", "second\n")
+	mustRun(t, c.WorkDir, "git", "add", ".")
+	mustRun(t, c.WorkDir, "git", "commit", "-m", "Second")
+
+	target := DiffTarget{Kind: TargetCommit, Ref: first}
+	entries, err := c.DiffTargetFiles(ctx, target)
+	require.NoError(t, err)
+	assert.Equal(t, []DiffEntry{{Status: "A", Path: "first.txt"}}, entries)
+
+	numStats, err := c.DiffTargetNumStat(ctx, target)
+	require.NoError(t, err)
+	require.Len(t, numStats, 1)
+	assert.Equal(t, 1, numStats[0].Added)
+
+	diff, err := c.DiffTargetUnified(ctx, target)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+first")
+	assert.NotContains(t, diff, "second.txt")
+
+	// A range covers several commits.
+	entries, err = c.DiffTargetFiles(ctx, DiffTarget{Kind: TargetRange, Ref: first + "^..HEAD"})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestDiffTarget_RootCommit(t *testing.T) {
+	c := newTestRepo(t)
+	ctx := context.Background()
+
+	root, err := c.ResolveCommit(ctx, "HEAD")
+	require.NoError(t, err)
+	writeFile(t, c.WorkDir, "second.txt", "second\n")
+	mustRun(t, c.WorkDir, "git", "add", ".")
+	mustRun(t, c.WorkDir, "git", "commit", "-m", "Second")
+
+	target := DiffTarget{Kind: TargetCommit, Ref: root}
+	entries, err := c.DiffTargetFiles(ctx, target)
+	require.NoError(t, err)
+	assert.Equal(t, []DiffEntry{{Status: "A", Path: "README.md"}}, entries)
+
+	numStats, err := c.DiffTargetNumStat(ctx, target)
+	require.NoError(t, err)
+	require.Len(t, numStats, 1)
+	assert.Equal(t, "README.md", numStats[0].Path)
+	assert.Positive(t, numStats[0].Added)
+
+	diff, err := c.DiffTargetUnified(ctx, target)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "diff --git a/README.md b/README.md")
+	assert.Contains(t, diff, "+# Test")
+	assert.NotContains(t, diff, "second.txt")
+}
+
+func TestDiffTarget_StagedAndWorktree(t *testing.T) {
+	c := newTestRepo(t)
+	ctx := context.Background()
+
+	writeFile(t, c.WorkDir, "staged.txt", "staged\n")
+	mustRun(t, c.WorkDir, "git", "add", "staged.txt")
+	writeFile(t, c.WorkDir, "README.md", "# Test\n\nUnstaged edit.\n")
+	writeFile(t, c.WorkDir, "untracked.txt", "untracked\n")
+
+	staged, err := c.DiffTargetFiles(ctx, DiffTarget{Kind: TargetStaged})
+	require.NoError(t, err)
+	assert.Equal(t, []DiffEntry{{Status: "A", Path: "staged.txt"}}, staged)
+
+	// The working tree has the staged and unstaged changes to tracked files.
+	worktree, err := c.DiffTargetFiles(ctx, DiffTarget{Kind: TargetWorktree})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []DiffEntry{
+		{Status: "M", Path: "README.md"},
+		{Status: "A", Path: "staged.txt"},
+	}, worktree)
+
+	diff, err := c.DiffTargetUnified(ctx, DiffTarget{Kind: TargetWorktree})
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+Unstaged edit.")
+	assert.NotContains(t, diff, "untracked")
+}
+
+func TestDiffTarget_InvalidRef(t *testing.T) {
+	c := newTestRepo(t)
+
+	_, err := c.DiffTargetFiles(context.Background(), DiffTarget{Kind: TargetCommit, Ref: "deadbeef"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "commit deadbeef")
+}
+
+func TestFetchRefs(t *testing.T) {
+	remote := newTestRepo(t)
+	ctx := context.Background()
+	writeFile(t, remote.WorkDir, "feature.txt", "feature\n")
+	mustRun(t, remote.WorkDir, "git", "checkout", "-b", "feature")
+	mustRun(t, remote.WorkDir, "git", "add", ".")
+	mustRun(t, remote.WorkDir, "git", "commit", "-m", "Feature")
+	featureSHA, err := remote.ResolveCommit(ctx, "HEAD")
+	require.NoError(t, err)
+
+	c := newTestRepo(t)
+	mustRun(t, c.WorkDir, "git", "remote", "add", "origin", remote.WorkDir)
+	require.NoError(t, c.FetchRefs(ctx, "origin", "feature"))
+
+	resolved, err := c.ResolveCommit(ctx, featureSHA)
+	require.NoError(t, err)
+	assert.Equal(t, featureSHA, resolved)
+
+	err = c.FetchRefs(ctx, "origin", "no-such-branch")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no-such-branch")
+}
@@ -0,0 +1,177 @@
+package loop
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+// LoopCheckpoint captures the in-flight progress of an implementation loop so
+// that an interrupted run can continue exactly where it stopped instead of
+// restarting from iteration one. It is serialized into the workflow state
+// metadata by the run_implement step handler after every iteration.
+type LoopCheckpoint struct {
+	// PhaseID is the phase the loop was running (0 in single-task mode).
+	PhaseID int `json:"phase_id"`
+	// TaskID is the single-task target (empty in phase mode).
+	TaskID string `json:"task_id,omitempty"`
+	// CurrentTask is the task being worked on when the checkpoint was taken.
+	// It is empty once the iteration finished and the task state was updated.
+	CurrentTask string `json:"current_task,omitempty"`
+	// Iteration is the number of the last iteration that was started.
+	Iteration int `json:"iteration"`
+	// RateLimitWaits is the number of rate-limit waits consumed so far.
+	RateLimitWaits int `json:"rate_limit_waits"`
+	// RecentTasks holds the most recent task selections used for stale-task
+	// detection.
+	RecentTasks []string `json:"recent_tasks,omitempty"`
+	// TaskAttempts counts how many times each task has been handed to the agent.
+	TaskAttempts map[string]int `json:"task_attempts,omitempty"`
+	// SessionIDs maps task IDs to the last agent session ID observed for them.
+	SessionIDs map[string]string `json:"session_ids,omitempty"`
+	// UpdatedAt is the time the checkpoint was taken.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CheckpointFunc is called by the Runner whenever the loop checkpoint changes.
+// Returning an error does not stop the loop; the error is logged.
+type CheckpointFunc func(cp LoopCheckpoint) error
+
+// Matches reports whether the checkpoint was taken for the same run target as
+// runCfg (same phase in phase mode, same task in single-task mode). A
+// checkpoint for a different target must not be applied.
+func (cp *LoopCheckpoint) Matches(runCfg RunConfig) bool {
+	if cp == nil {
+		return false
+	}
+	if runCfg.TaskID != "" || cp.TaskID != "" {
+		return cp.TaskID == runCfg.TaskID
+	}
+	return cp.PhaseID == runCfg.PhaseID
+}
+
+// SetCheckpointFunc configures a callback that receives a LoopCheckpoint after
+// each task selection and at the end of every iteration. Pass nil to disable
+// checkpointing. This should be called before Run or RunSingleTask.
+func (r *Runner) SetCheckpointFunc(fn CheckpointFunc) {
+	r.checkpointFn = fn
+}
+
+// restoreCheckpoint initialises the runner's loop progress from runCfg.Resume
+// when it matches the run target, and returns the iteration number the loop
+// should start from. When the checkpoint records an interrupted task that is
+// still marked in_progress, the task is reverted to not_started so that the
+// selector picks it up again.
+func (r *Runner) restoreCheckpoint(runCfg RunConfig) (int, error) {
+	r.progress = LoopCheckpoint{
+		PhaseID:      runCfg.PhaseID,
+		TaskID:       runCfg.TaskID,
+		TaskAttempts: map[string]int{},
+		SessionIDs:   map[string]string{},
+	}
+
+	cp := runCfg.Resume
+	if !cp.Matches(runCfg) {
+		return 1, nil
+	}
+
+	r.rateLimitWaits = cp.RateLimitWaits
+	r.progress.Iteration = cp.Iteration
+	r.progress.RateLimitWaits = cp.RateLimitWaits
+	r.progress.RecentTasks = append([]string(nil), cp.RecentTasks...)
+	for id, n := range cp.TaskAttempts {
+		r.progress.TaskAttempts[id] = n
+	}
+	for id, sid := range cp.SessionIDs {
+		r.progress.SessionIDs[id] = sid
+	}
+
+	start := cp.Iteration + 1
+	if cp.CurrentTask != "" {
+		// The interrupted iteration never finished; run it again.
+		start = cp.Iteration
+		if start < 1 {
+			start = 1
+		}
+		ts, err := r.stateManager.Get(cp.CurrentTask)
+		if err != nil {
+			return 0, fmt.Errorf("restoring loop checkpoint: %w", err)
+		}
+		if ts != nil && ts.Status == task.StatusInProgress {
+			if err := r.stateManager.UpdateStatus(cp.CurrentTask, task.StatusNotStarted, ts.Agent); err != nil {
+				return 0, fmt.Errorf("restoring loop checkpoint: reverting task %s: %w", cp.CurrentTask, err)
+			}
+		}
+	}
+
+	r.logger.Info("resuming implementation loop from checkpoint",
+		"phase", cp.PhaseID,
+		"task", cp.TaskID,
+		"iteration", start,
+		"interruptedTask", cp.CurrentTask,
+		"rateLimitWaits", cp.RateLimitWaits,
+	)
+	return start, nil
+}
+
+// beginIteration records the start of an iteration on taskID and saves a
+// checkpoint.
+func (r *Runner) beginIteration(iteration int, taskID string, recent []string) {
+	r.progress.Iteration = iteration
+	r.progress.CurrentTask = taskID
+	r.progress.RecentTasks = append([]string(nil), recent...)
+	if r.progress.TaskAttempts == nil {
+		r.progress.TaskAttempts = map[string]int{}
+	}
+	r.progress.TaskAttempts[taskID]++
+	r.saveCheckpoint()
+}
+
+// endIteration records that the current iteration finished and its task state
+// has been persisted, then saves a checkpoint.
+func (r *Runner) endIteration() {
+	r.progress.CurrentTask = ""
+	r.saveCheckpoint()
+}
+
+// recordSessionID remembers the agent session ID observed for taskID.
+func (r *Runner) recordSessionID(taskID, sessionID string) {
+	if taskID == "" || sessionID == "" {
+		return
+	}
+	if r.progress.SessionIDs == nil {
+		r.progress.SessionIDs = map[string]string{}
+	}
+	r.progress.SessionIDs[taskID] = sessionID
+}
+
+// saveCheckpoint passes a snapshot of the loop progress to the configured
+// CheckpointFunc. Errors are logged but do not interrupt the loop --
+// checkpointing is best-effort, like PROGRESS.md regeneration.
+func (r *Runner) saveCheckpoint() {
+	if r.checkpointFn == nil {
+		return
+	}
+	r.progress.RateLimitWaits = r.rateLimitWaits
+	r.progress.UpdatedAt = time.Now().UTC()
+	if err := r.checkpointFn(cloneCheckpoint(r.progress)); err != nil {
+		r.logger.Info("failed to save loop checkpoint", "iteration", r.progress.Iteration, "err", err)
+	}
+}
+
+// cloneCheckpoint returns a deep copy of cp so that callers can retain it
+// safely while the loop keeps mutating its own copy.
+func cloneCheckpoint(cp LoopCheckpoint) LoopCheckpoint {
+	out := cp
+	out.RecentTasks = append([]string(nil), cp.RecentTasks...)
+	out.TaskAttempts = make(map[string]int, len(cp.TaskAttempts))
+	for k, v := range cp.TaskAttempts {
+		out.TaskAttempts[k] = v
+	}
+	out.SessionIDs = make(map[string]string, len(cp.SessionIDs))
+	for k, v := range cp.SessionIDs {
+		out.SessionIDs[k] = v
+	}
+	return out
+}
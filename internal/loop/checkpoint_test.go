@@ -0,0 +1,178 @@
+package loop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/agent"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+func TestLoopCheckpoint_Matches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		cp     *LoopCheckpoint
+		runCfg RunConfig
+		want   bool
+	}{
+		{name: "nil checkpoint", cp: nil, runCfg: RunConfig{PhaseID: 1}, want: false},
+		{name: "same phase", cp: &LoopCheckpoint{PhaseID: 1}, runCfg: RunConfig{PhaseID: 1}, want: true},
+		{name: "different phase", cp: &LoopCheckpoint{PhaseID: 2}, runCfg: RunConfig{PhaseID: 1}, want: false},
+		{name: "same task", cp: &LoopCheckpoint{TaskID: "T-003"}, runCfg: RunConfig{TaskID: "T-003"}, want: true},
+		{name: "different task", cp: &LoopCheckpoint{TaskID: "T-003"}, runCfg: RunConfig{TaskID: "T-004"}, want: false},
+		{name: "task checkpoint in phase mode", cp: &LoopCheckpoint{TaskID: "T-003", PhaseID: 1}, runCfg: RunConfig{PhaseID: 1}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.cp.Matches(tt.runCfg))
+		})
+	}
+}
+
+func TestRun_CheckpointAfterEveryIteration(t *testing.T) {
+	t.Parallel()
+
+	specs := []*task.ParsedTaskSpec{
+		makeTestSpec("T-001", "Task 1", "# T-001: Task 1\n"),
+		makeTestSpec("T-002", "Task 2", "# T-002: Task 2\n"),
+	}
+	phases := makePhases(1, "T-001", "T-002")
+	ag := agent.NewMockAgent("mock")
+
+	runner, _, _ := makeRunnerDeps(t, specs, nil, phases, ag)
+
+	var checkpoints []LoopCheckpoint
+	runner.SetCheckpointFunc(func(cp LoopCheckpoint) error {
+		checkpoints = append(checkpoints, cp)
+		return nil
+	})
+
+	err := runner.Run(context.Background(), RunConfig{AgentName: "mock", PhaseID: 1})
+	require.NoError(t, err)
+
+	// One checkpoint when each task starts, one when each iteration ends.
+	require.Len(t, checkpoints, 4)
+	assert.Equal(t, "T-001", checkpoints[0].CurrentTask)
+	assert.Equal(t, 1, checkpoints[0].Iteration)
+	assert.Equal(t, "", checkpoints[1].CurrentTask)
+	assert.Equal(t, "T-002", checkpoints[2].CurrentTask)
+	assert.Equal(t, 2, checkpoints[2].Iteration)
+	assert.Equal(t, []string{"T-001", "T-002"}, checkpoints[3].RecentTasks)
+	assert.Equal(t, map[string]int{"T-001": 1, "T-002": 1}, checkpoints[3].TaskAttempts)
+	assert.Equal(t, 1, checkpoints[3].PhaseID)
+	assert.False(t, checkpoints[3].UpdatedAt.IsZero())
+}
+
+func TestRun_ResumeFromCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	specs := []*task.ParsedTaskSpec{
+		makeTestSpec("T-001", "Task 1", "# T-001: Task 1\n"),
+		makeTestSpec("T-002", "Task 2", "# T-002: Task 2\n"),
+	}
+	phases := makePhases(1, "T-001", "T-002")
+	ag := agent.NewMockAgent("mock")
+
+	// T-001 finished, T-002 was interrupted mid-iteration and left in_progress.
+	runner, sm, events := makeRunnerDeps(t, specs, []string{
+		"T-001|completed|mock||",
+		"T-002|in_progress|mock||",
+	}, phases, ag)
+
+	var checkpoints []LoopCheckpoint
+	runner.SetCheckpointFunc(func(cp LoopCheckpoint) error {
+		checkpoints = append(checkpoints, cp)
+		return nil
+	})
+
+	err := runner.Run(context.Background(), RunConfig{
+		AgentName: "mock",
+		PhaseID:   1,
+		Resume: &LoopCheckpoint{
+			PhaseID:        1,
+			CurrentTask:    "T-002",
+			Iteration:      7,
+			RateLimitWaits: 2,
+			RecentTasks:    []string{"T-001", "T-002"},
+			TaskAttempts:   map[string]int{"T-001": 1, "T-002": 1},
+			SessionIDs:     map[string]string{"T-001": "sess-1"},
+		},
+	})
+	require.NoError(t, err)
+
+	// The interrupted task was picked up again and completed.
+	require.Len(t, ag.GetCalls(), 1)
+	ts, err := sm.Get("T-002")
+	require.NoError(t, err)
+	require.NotNil(t, ts)
+	assert.Equal(t, task.StatusCompleted, ts.Status)
+
+	// The interrupted iteration number is reused and counters carry over.
+	require.NotEmpty(t, checkpoints)
+	assert.Equal(t, 7, checkpoints[0].Iteration)
+	assert.Equal(t, 2, checkpoints[0].RateLimitWaits)
+	assert.Equal(t, 2, checkpoints[0].TaskAttempts["T-002"])
+	assert.Equal(t, "sess-1", checkpoints[0].SessionIDs["T-001"])
+
+	var selected []LoopEvent
+	for _, e := range drainEvents(events) {
+		if e.Type == EventTaskSelected {
+			selected = append(selected, e)
+		}
+	}
+	require.Len(t, selected, 1)
+	assert.Equal(t, 7, selected[0].Iteration)
+}
+
+func TestRun_ResumeCheckpointForOtherPhaseIgnored(t *testing.T) {
+	t.Parallel()
+
+	specs := []*task.ParsedTaskSpec{
+		makeTestSpec("T-001", "Task 1", "# T-001: Task 1\n"),
+	}
+	phases := makePhases(1, "T-001", "T-001")
+	ag := agent.NewMockAgent("mock")
+
+	runner, _, _ := makeRunnerDeps(t, specs, nil, phases, ag)
+
+	var first *LoopCheckpoint
+	runner.SetCheckpointFunc(func(cp LoopCheckpoint) error {
+		if first == nil {
+			first = &cp
+		}
+		return nil
+	})
+
+	err := runner.Run(context.Background(), RunConfig{
+		AgentName: "mock",
+		PhaseID:   1,
+		Resume:    &LoopCheckpoint{PhaseID: 3, Iteration: 40, RateLimitWaits: 4},
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, first)
+	assert.Equal(t, 1, first.Iteration)
+	assert.Equal(t, 0, first.RateLimitWaits)
+}
+
+func TestConsumeStreamEvents_RecordsSessionID(t *testing.T) {
+	t.Parallel()
+
+	runner, _, _ := makeRunnerDeps(t, nil, nil, nil, agent.NewMockAgent("mock"))
+
+	ch := make(chan agent.StreamEvent, 2)
+	ch <- agent.StreamEvent{Type: agent.StreamEventSystem, SessionID: "sess-42"}
+	ch <- agent.StreamEvent{Type: agent.StreamEventResult, SessionID: "sess-42"}
+	close(ch)
+
+	runner.consumeStreamEvents(context.Background(), ch, 1, "T-009", "mock")
+
+	assert.Equal(t, "sess-42", runner.progress.SessionIDs["T-009"])
+}
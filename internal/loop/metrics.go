@@ -0,0 +1,84 @@
+package loop
+
+import (
+	"sync"
+	"time"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+// metricsCollector aggregates per-task metrics from the loop's own events and
+// records them with the state manager once per iteration. It observes every
+// emitted event, including events dropped because the events channel is
+// full, so the metrics do not depend on a consumer keeping up.
+type metricsCollector struct {
+	record func(task.TaskMetrics) error
+	onErr  func(taskID string, err error)
+
+	// mu guards the fields below: stream events are emitted from the
+	// goroutine consuming the agent's output.
+	mu sync.Mutex
+	// current is the iteration in progress; nil between iterations.
+	current *task.TaskMetrics
+	// started is when the current task was selected.
+	started time.Time
+}
+
+// newMetricsCollector creates a collector that passes each finished
+// iteration's metrics to record. Record errors are passed to onErr.
+func newMetricsCollector(record func(task.TaskMetrics) error, onErr func(taskID string, err error)) *metricsCollector {
+	return &metricsCollector{record: record, onErr: onErr}
+}
+
+// observe folds event into the current iteration. An iteration starts when a
+// task is selected and ends, and is recorded, when the agent completes,
+// fails, or the loop is aborted while the agent runs. Dry runs record
+// nothing.
+func (c *metricsCollector) observe(event LoopEvent) {
+	ts := event.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch event.Type {
+	case EventTaskSelected:
+		c.current = &task.TaskMetrics{TaskID: event.TaskID, Agent: event.AgentName}
+		c.started = ts
+	case EventSessionStats:
+		if c.current != nil && c.current.TaskID == event.TaskID {
+			c.current.TokensIn += event.TokensIn
+			c.current.TokensOut += event.TokensOut
+			c.current.CostUSD += event.CostUSD
+		}
+	case EventAgentCompleted:
+		if c.current != nil && c.current.TaskID == event.TaskID {
+			c.current.AgentTime += event.Duration
+			c.flush(ts)
+		}
+	case EventAgentError, EventLoopAborted:
+		if c.current != nil && event.TaskID != "" && c.current.TaskID == event.TaskID {
+			c.flush(ts)
+		}
+	case EventDryRun:
+		c.current = nil
+	}
+}
+
+// flush records the current iteration as ending at end. Callers must hold
+// c.mu.
+func (c *metricsCollector) flush(end time.Time) {
+	m := *c.current
+	c.current = nil
+
+	m.Iterations = 1
+	if end.After(c.started) {
+		m.WallTime = end.Sub(c.started)
+	}
+	m.Updated = end.UTC()
+	if err := c.record(m); err != nil && c.onErr != nil {
+		c.onErr(m.TaskID, err)
+	}
+}
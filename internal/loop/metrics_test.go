@@ -0,0 +1,120 @@
+package loop
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/agent"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
+)
+
+func TestMetricsCollector_Observe(t *testing.T) {
+	t.Parallel()
+
+	var recorded []task.TaskMetrics
+	c := newMetricsCollector(func(m task.TaskMetrics) error {
+		recorded = append(recorded, m)
+		return nil
+	}, nil)
+
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	events := []LoopEvent{
+		{Type: EventTaskSelected, TaskID: "T-001", AgentName: "claude", Timestamp: at(0)},
+		{Type: EventAgentStarted, TaskID: "T-001", Timestamp: at(time.Second)},
+		{Type: EventSessionStats, TaskID: "T-001", TokensIn: 100, TokensOut: 20, CostUSD: 0.5, Timestamp: at(2 * time.Minute)},
+		// A rate-limit retry reports a second session.
+		{Type: EventSessionStats, TaskID: "T-001", TokensIn: 50, TokensOut: 10, CostUSD: 0.25, Timestamp: at(4 * time.Minute)},
+		{Type: EventAgentCompleted, TaskID: "T-001", Duration: 3 * time.Minute, Timestamp: at(5 * time.Minute)},
+		{Type: EventTaskCompleted, TaskID: "T-001", Timestamp: at(5 * time.Minute)},
+
+		// Stats for a task that is not current are ignored.
+		{Type: EventSessionStats, TaskID: "T-009", TokensIn: 999, Timestamp: at(6 * time.Minute)},
+
+		{Type: EventTaskSelected, TaskID: "T-002", AgentName: "codex", Timestamp: at(10 * time.Minute)},
+		{Type: EventAgentError, TaskID: "T-002", Timestamp: at(11 * time.Minute)},
+
+		// Dry runs record nothing.
+		{Type: EventTaskSelected, TaskID: "T-003", Timestamp: at(20 * time.Minute)},
+		{Type: EventDryRun, TaskID: "T-003", Timestamp: at(20 * time.Minute)},
+		{Type: EventAgentCompleted, TaskID: "T-003", Timestamp: at(21 * time.Minute)},
+
+		// Aborts without a task ID (between iterations) record nothing.
+		{Type: EventLoopAborted, Timestamp: at(30 * time.Minute)},
+	}
+	for _, e := range events {
+		c.observe(e)
+	}
+
+	require.Len(t, recorded, 2)
+	assert.Equal(t, task.TaskMetrics{
+		TaskID:     "T-001",
+		Agent:      "claude",
+		Iterations: 1,
+		WallTime:   5 * time.Minute,
+		AgentTime:  3 * time.Minute,
+		TokensIn:   150,
+		TokensOut:  30,
+		CostUSD:    0.75,
+		Updated:    at(5 * time.Minute),
+	}, recorded[0])
+	assert.Equal(t, task.TaskMetrics{
+		TaskID:     "T-002",
+		Agent:      "codex",
+		Iterations: 1,
+		WallTime:   time.Minute,
+		Updated:    at(11 * time.Minute),
+	}, recorded[1])
+}
+
+func TestMetricsCollector_RecordError(t *testing.T) {
+	t.Parallel()
+
+	var failed string
+	c := newMetricsCollector(func(task.TaskMetrics) error {
+		return errors.New("disk full")
+	}, func(taskID string, err error) {
+		failed = taskID + ": " + err.Error()
+	})
+
+	c.observe(LoopEvent{Type: EventTaskSelected, TaskID: "T-001"})
+	c.observe(LoopEvent{Type: EventAgentCompleted, TaskID: "T-001"})
+	assert.Equal(t, "T-001: disk full", failed)
+}
+
+func TestRunner_RecordsTaskMetrics(t *testing.T) {
+	t.Parallel()
+
+	specs := []*task.ParsedTaskSpec{makeTestSpec("T-001", "Task 1", "# T-001: Task 1\n")}
+	ag := agent.NewMockAgent("mock").WithRunFunc(func(_ context.Context, opts agent.RunOpts) (*agent.RunResult, error) {
+		opts.StreamEvents <- agent.StreamEvent{
+			Type:    agent.StreamEventAssistant,
+			Message: &agent.StreamMessage{Usage: &agent.StreamUsage{InputTokens: 120, OutputTokens: 30}},
+		}
+		opts.StreamEvents <- agent.StreamEvent{Type: agent.StreamEventResult, CostUSD: 0.42}
+		return &agent.RunResult{ExitCode: 0, Duration: 2 * time.Second}, nil
+	})
+	// No events channel: metrics must not depend on a consumer.
+	runner, sm, _ := makeRunnerDeps(t, specs, nil, makePhases(1, "T-001", "T-001"), ag)
+	runner.events = nil
+
+	require.NoError(t, runner.RunSingleTask(context.Background(), RunConfig{AgentName: "mock", TaskID: "T-001"}))
+
+	metrics, err := sm.Metrics()
+	require.NoError(t, err)
+	m, ok := metrics["T-001"]
+	require.True(t, ok)
+	assert.Equal(t, 1, m.Iterations)
+	assert.Equal(t, "mock", m.Agent)
+	assert.Equal(t, 2*time.Second, m.AgentTime)
+	assert.Equal(t, 120, m.TokensIn)
+	assert.Equal(t, 30, m.TokensOut)
+	assert.InDelta(t, 0.42, m.CostUSD, 1e-9)
+	assert.Positive(t, m.WallTime)
+}
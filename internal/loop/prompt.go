@@ -23,7 +23,12 @@ const DefaultImplementTemplate = `You are implementing task [[.TaskID]]: [[.Task
 ## Task Specification
 
 [[.TaskSpec]]
-
+[[if .AcceptanceCriteria]]
+## Acceptance Criteria
+[[range .AcceptanceCriteria]]
+- [[.]]
+[[end]]
+[[end]]
 ## Phase Context
 
 Phase [[.PhaseID]]: [[.PhaseName]] ([[.PhaseRange]])
@@ -62,6 +67,14 @@ type PromptContext struct {
 	TaskID    string // e.g., "T-016"
 	TaskTitle string // e.g., "Task Spec Markdown Parser"
 
+	// Task metadata, populated from the task spec front matter when present.
+	TaskPriority       string   // e.g., "Must Have"
+	TaskEffort         string   // e.g., "Medium: 4-8hrs"
+	TaskLabels         []string // e.g., ["api", "reliability"]
+	TaskOwners         []string // e.g., ["alice"]
+	TaskFiles          []string // Target files or globs, e.g., ["internal/agent/*.go"]
+	AcceptanceCriteria []string // Front matter acceptance criteria, one per entry.
+
 	// Phase context.
 	PhaseID    int    // Current phase number.
 	PhaseName  string // e.g., "Task System & Agent Adapters"
@@ -72,7 +85,7 @@ type PromptContext struct {
 	ProjectLanguage string // From raven.toml project.language.
 
 	// Verification.
-	VerificationCommands []string // From raven.toml project.verification_commands.
+	VerificationCommands []string // From raven.toml project.verification_commands, or the task's override.
 	VerificationString   string   // Commands joined with " && ".
 
 	// Task progress context.
@@ -229,6 +242,8 @@ func (pg *PromptGenerator) execute(tmpl *template.Template, ctx PromptContext) (
 //
 // The selector is used to populate CompletedTasks and RemainingTasks. The
 // agentName is matched against cfg.Agents to look up the configured model.
+// Verification commands and the model declared in the task spec front matter
+// take precedence over the project and agent configuration.
 func BuildContext(
 	spec *task.ParsedTaskSpec,
 	phase *task.Phase,
@@ -266,12 +281,17 @@ func BuildContext(
 		}
 	}
 
+	verifyCmds := cfg.Project.VerificationCommands
+	if len(spec.VerificationCommands) > 0 {
+		verifyCmds = spec.VerificationCommands
+	}
+
 	// Build VerificationString from the slice (no trailing " && ").
-	verifyStr := strings.Join(cfg.Project.VerificationCommands, " && ")
+	verifyStr := strings.Join(verifyCmds, " && ")
 
-	// Determine model from agent configuration.
-	model := ""
-	if agentCfg, ok := cfg.Agents[agentName]; ok {
+	// Determine model from the task override or the agent configuration.
+	model := spec.Model
+	if agentCfg, ok := cfg.Agents[agentName]; ok && model == "" {
 		model = agentCfg.Model
 	}
 
@@ -281,15 +301,22 @@ func BuildContext(
 		TaskID:    spec.ID,
 		TaskTitle: spec.Title,
 
+		TaskPriority:       spec.Priority,
+		TaskEffort:         spec.Effort,
+		TaskLabels:         spec.Labels,
+		TaskOwners:         spec.Owners,
+		TaskFiles:          spec.Files,
+		AcceptanceCriteria: spec.AcceptanceCriteria,
+
 		// Phase fields.
 		PhaseID:    phase.ID,
 		PhaseName:  phase.Name,
-		PhaseRange: phase.StartTask + " to " + phase.EndTask,
+		PhaseRange: phaseRange(phase, selector.Membership()),
 
 		// Project fields.
 		ProjectName:          cfg.Project.Name,
 		ProjectLanguage:      cfg.Project.Language,
-		VerificationCommands: cfg.Project.VerificationCommands,
+		VerificationCommands: verifyCmds,
 		VerificationString:   verifyStr,
 
 		// Progress fields.
@@ -314,3 +341,14 @@ func formatIDSummary(ids []string) string {
 	}
 	return strings.Join(ids, ", ")
 }
+
+// phaseRange describes the tasks in phase as "first to last". Phases defined
+// by task lists or labels have no range of their own; their lowest and
+// highest member tasks are used instead.
+func phaseRange(phase *task.Phase, membership *task.PhaseMembership) string {
+	first, last := phase.StartTask, phase.EndTask
+	if !phase.HasRange() {
+		first, last = membership.Bounds(phase.ID)
+	}
+	return first + " to " + last
+}
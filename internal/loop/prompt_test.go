@@ -601,6 +601,58 @@ func TestBuildContext_UnknownAgentModelIsEmpty(t *testing.T) {
 	assert.Equal(t, "", ctx.Model)
 }
 
+func TestBuildContext_FrontMatterOverrides(t *testing.T) {
+	t.Parallel()
+
+	spec := makeTestSpec("T-001", "Setup", "# T-001: Setup\n")
+	spec.Priority = "Must Have"
+	spec.Effort = "small"
+	spec.Labels = []string{"api"}
+	spec.Owners = []string{"alice"}
+	spec.Files = []string{"internal/api/*.go"}
+	spec.AcceptanceCriteria = []string{"Handles retries"}
+	spec.Model = "claude-sonnet-4-5"
+	spec.VerificationCommands = []string{"go test ./internal/api/..."}
+
+	phase := makeTestPhase(1, "P1", "T-001", "T-001")
+	agents := map[string]config.AgentConfig{"claude": {Model: "claude-opus-4-6"}}
+	cfg := makeTestConfig("Raven", "Go", []string{"go build ./...", "go test ./..."}, agents)
+	sel := makeSelector(t, []*task.ParsedTaskSpec{spec}, nil, []task.Phase{*phase})
+
+	ctx, err := BuildContext(spec, phase, cfg, sel, "claude")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Must Have", ctx.TaskPriority)
+	assert.Equal(t, "small", ctx.TaskEffort)
+	assert.Equal(t, []string{"api"}, ctx.TaskLabels)
+	assert.Equal(t, []string{"alice"}, ctx.TaskOwners)
+	assert.Equal(t, []string{"internal/api/*.go"}, ctx.TaskFiles)
+	assert.Equal(t, []string{"Handles retries"}, ctx.AcceptanceCriteria)
+	assert.Equal(t, "claude-sonnet-4-5", ctx.Model)
+	assert.Equal(t, []string{"go test ./internal/api/..."}, ctx.VerificationCommands)
+	assert.Equal(t, "go test ./internal/api/...", ctx.VerificationString)
+}
+
+func TestGenerate_DefaultTemplateAcceptanceCriteria(t *testing.T) {
+	t.Parallel()
+
+	pg, err := NewPromptGenerator("")
+	require.NoError(t, err)
+
+	without, err := pg.Generate("", PromptContext{TaskID: "T-001"})
+	require.NoError(t, err)
+	assert.NotContains(t, without, "## Acceptance Criteria")
+
+	with, err := pg.Generate("", PromptContext{
+		TaskID:             "T-001",
+		AcceptanceCriteria: []string{"Handles retries", "Logs failures"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, with, "## Acceptance Criteria")
+	assert.Contains(t, with, "- Handles retries")
+	assert.Contains(t, with, "- Logs failures")
+}
+
 func TestBuildContext_CompletedAndRemainingTasks(t *testing.T) {
 	t.Parallel()
 
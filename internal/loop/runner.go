@@ -26,6 +26,13 @@ type RunConfig struct {
 	SleepBetween  time.Duration // default: 5s
 	DryRun        bool
 	TemplateName  string
+	Model         string // Overrides the configured agent model when non-empty.
+	RunID         string // Recorded with task state history entries; empty when unknown.
+
+	// Resume, when non-nil, restores loop progress (iteration count,
+	// rate-limit waits, stale-task history) from a previous interrupted run.
+	// It is ignored unless it was taken for the same phase or task.
+	Resume *LoopCheckpoint
 }
 
 // LoopEventType identifies the type of loop event.
@@ -68,6 +75,10 @@ type LoopEvent struct {
 	Duration  time.Duration
 	WaitTime  time.Duration
 
+	// Reason explains why the task was chosen (EventTaskSelected), e.g.
+	// "highest priority (must-have) of 3 ready".
+	Reason string
+
 	// Stream-level observability fields (populated for tool/thinking/stats events).
 	ToolName  string  // Name of the tool called (EventToolStarted) or tool_use_id (EventToolCompleted).
 	CostUSD   float64 // Session cost in USD (EventSessionStats).
@@ -106,6 +117,7 @@ type Runner struct {
 	selector       *task.TaskSelector
 	promptGen      *PromptGenerator
 	agent          agent.Agent
+	agents         *agent.Registry // optional; resolves per-task agent overrides
 	stateManager   *task.StateManager
 	rateLimiter    *agent.RateLimitCoordinator
 	config         *config.Config
@@ -114,6 +126,11 @@ type Runner struct {
 	progressGen    *task.ProgressGenerator
 	progressPath   string
 	rateLimitWaits int // tracks rate-limit wait count within a single Run/RunSingleTask call
+	checkpointFn   CheckpointFunc
+	progress       LoopCheckpoint // loop progress reported to checkpointFn
+	leaseOwner     string         // owner recorded on task claims
+	leaseTTL       time.Duration  // lease duration of task claims
+	metrics        *metricsCollector
 	logger         interface {
 		Info(msg string, kv ...interface{})
 		Debug(msg string, kv ...interface{})
@@ -123,6 +140,9 @@ type Runner struct {
 // NewRunner creates an implementation loop runner with all dependencies.
 // The events channel receives structured LoopEvent values; pass nil to disable
 // event emission. The logger must not be nil.
+//
+// The runner records each task's iterations, wall and agent time, tokens and
+// cost with stateManager.RecordMetrics, aggregated from the events it emits.
 func NewRunner(
 	selector *task.TaskSelector,
 	promptGen *PromptGenerator,
@@ -137,7 +157,7 @@ func NewRunner(
 		Debug(msg string, kv ...interface{})
 	},
 ) *Runner {
-	return &Runner{
+	r := &Runner{
 		selector:     selector,
 		promptGen:    promptGen,
 		agent:        ag,
@@ -147,13 +167,33 @@ func NewRunner(
 		phases:       phases,
 		events:       events,
 		logger:       logger,
+		leaseOwner:   task.DefaultClaimOwner(),
+		leaseTTL:     task.DefaultLeaseDuration,
+	}
+	r.metrics = newMetricsCollector(stateManager.RecordMetrics, func(taskID string, err error) {
+		r.logger.Info("failed to record task metrics", "task", taskID, "err", err)
+	})
+	return r
+}
+
+// SetLease overrides the owner and duration of the leases the runner takes
+// on the tasks it works on (by default task.DefaultClaimOwner and
+// task.DefaultLeaseDuration). Empty or non-positive values keep the
+// defaults. The lease is renewed every third of its duration while the
+// agent runs.
+func (r *Runner) SetLease(owner string, ttl time.Duration) {
+	if owner != "" {
+		r.leaseOwner = owner
+	}
+	if ttl > 0 {
+		r.leaseTTL = ttl
 	}
 }
 
 // SetProgressGenerator configures a ProgressGenerator that regenerates
-// PROGRESS.md at progressPath after each task state change. If not set,
-// progress file regeneration is skipped. This should be called before Run
-// or RunSingleTask.
+// PROGRESS.md at progressPath, and the generator's other formats next to it,
+// after each task state change. If not set, progress file regeneration is
+// skipped. This should be called before Run or RunSingleTask.
 func (r *Runner) SetProgressGenerator(pg *task.ProgressGenerator, progressPath string) {
 	r.progressGen = pg
 	r.progressPath = progressPath
@@ -165,6 +205,12 @@ func (r *Runner) SetProgressGenerator(pg *task.ProgressGenerator, progressPath s
 func (r *Runner) Run(ctx context.Context, runCfg RunConfig) error {
 	applyDefaults(&runCfg)
 	r.rateLimitWaits = 0 // reset per-run rate-limit wait counter
+	r.stateManager.SetRunID(runCfg.RunID)
+
+	startIteration, err := r.restoreCheckpoint(runCfg)
+	if err != nil {
+		return fmt.Errorf("implementation loop: %w", err)
+	}
 
 	r.logger.Info("starting implementation loop",
 		"agent", runCfg.AgentName,
@@ -182,8 +228,9 @@ func (r *Runner) Run(ctx context.Context, runCfg RunConfig) error {
 	// recentTaskIDs holds the last staleTaskThreshold task IDs selected,
 	// used for stale-task detection.
 	recentTaskIDs := make([]string, 0, staleTaskThreshold)
+	recentTaskIDs = append(recentTaskIDs, r.progress.RecentTasks...)
 
-	for iteration := 1; iteration <= runCfg.MaxIterations; iteration++ {
+	for iteration := startIteration; iteration <= runCfg.MaxIterations; iteration++ {
 		// Check for context cancellation before each iteration.
 		if err := ctx.Err(); err != nil {
 			r.emit(LoopEvent{
@@ -197,12 +244,12 @@ func (r *Runner) Run(ctx context.Context, runCfg RunConfig) error {
 		}
 
 		// Select the next task.
-		spec, err := r.selectTask(runCfg)
+		sel, err := r.selectTask(runCfg)
 		if err != nil {
 			r.emit(loopErrorEvent(iteration, runCfg.AgentName, err.Error()))
 			return fmt.Errorf("implementation loop iteration %d: %w", iteration, err)
 		}
-		if spec == nil {
+		if sel == nil {
 			// Phase complete: no more actionable tasks.
 			r.logger.Info("phase complete", "phase", runCfg.PhaseID, "iteration", iteration)
 			r.emit(LoopEvent{
@@ -215,17 +262,25 @@ func (r *Runner) Run(ctx context.Context, runCfg RunConfig) error {
 			return nil
 		}
 
+		spec := sel.Spec
 		r.logger.Info("selected task",
 			"task", spec.ID,
 			"title", spec.Title,
 			"iteration", iteration,
+			"strategy", sel.Strategy,
+			"reason", sel.Reason,
 		)
+
+		// Apply the task's agent and model overrides from its front matter.
+		taskCfg := r.taskRunConfig(runCfg, spec)
+
 		r.emit(LoopEvent{
 			Type:      EventTaskSelected,
 			Iteration: iteration,
 			TaskID:    spec.ID,
-			AgentName: runCfg.AgentName,
+			AgentName: taskCfg.AgentName,
 			Message:   spec.Title,
+			Reason:    sel.Reason,
 			Timestamp: time.Now(),
 		})
 
@@ -240,41 +295,50 @@ func (r *Runner) Run(ctx context.Context, runCfg RunConfig) error {
 				Type:      EventLoopError,
 				Iteration: iteration,
 				TaskID:    spec.ID,
-				AgentName: runCfg.AgentName,
+				AgentName: taskCfg.AgentName,
 				Message:   fmt.Sprintf("stale task warning: %s selected %d times in a row", spec.ID, staleTaskThreshold),
 				Timestamp: time.Now(),
 			})
 		}
 
-		// Mark task in_progress.
-		if err := r.stateManager.UpdateStatus(spec.ID, task.StatusInProgress, runCfg.AgentName); err != nil {
-			return fmt.Errorf("updating task %s to in_progress: %w", spec.ID, err)
+		// Claim the task and mark it in_progress. Losing the race to another
+		// process sharing the state file is not an error: select again.
+		if err := r.stateManager.Claim(spec.ID, r.leaseOwner, taskCfg.AgentName, r.leaseTTL); err != nil {
+			if errors.Is(err, task.ErrTaskUnavailable) {
+				r.logger.Info("task claimed by another process, selecting again", "task", spec.ID, "err", err)
+				continue
+			}
+			return fmt.Errorf("claiming task %s: %w", spec.ID, err)
 		}
+		r.beginIteration(iteration, spec.ID, recentTaskIDs)
 
 		if runCfg.DryRun {
-			if err := r.handleDryRun(ctx, spec, runCfg, iteration); err != nil {
+			if err := r.handleDryRun(ctx, spec, taskCfg, iteration); err != nil {
 				return err
 			}
+			r.endIteration()
 			continue
 		}
 
 		// Generate prompt.
-		prompt, err := r.generatePrompt(spec, runCfg)
+		prompt, err := r.generatePrompt(spec, taskCfg)
 		if err != nil {
-			r.emit(loopErrorEvent(iteration, runCfg.AgentName, err.Error()))
+			r.emit(loopErrorEvent(iteration, taskCfg.AgentName, err.Error()))
 			return fmt.Errorf("generating prompt for task %s: %w", spec.ID, err)
 		}
 		r.emit(LoopEvent{
 			Type:      EventPromptGenerated,
 			Iteration: iteration,
 			TaskID:    spec.ID,
-			AgentName: runCfg.AgentName,
+			AgentName: taskCfg.AgentName,
 			Message:   fmt.Sprintf("prompt generated (%d bytes)", len(prompt)),
 			Timestamp: time.Now(),
 		})
 
 		// Invoke agent (with rate-limit retry).
-		result, err := r.invokeAgentWithRetry(ctx, prompt, runCfg, iteration, spec.ID)
+		releaseLease := r.holdLease(spec.ID)
+		result, err := r.invokeAgentWithRetry(ctx, prompt, taskCfg, iteration, spec.ID)
+		releaseLease()
 		if err != nil {
 			// Check if it's a max-waits-exceeded abort.
 			if errors.Is(err, agent.ErrMaxWaitsExceeded) {
@@ -282,7 +346,7 @@ func (r *Runner) Run(ctx context.Context, runCfg RunConfig) error {
 					Type:      EventLoopAborted,
 					Iteration: iteration,
 					TaskID:    spec.ID,
-					AgentName: runCfg.AgentName,
+					AgentName: taskCfg.AgentName,
 					Message:   "rate-limit max waits exceeded",
 					Timestamp: time.Now(),
 				})
@@ -294,7 +358,7 @@ func (r *Runner) Run(ctx context.Context, runCfg RunConfig) error {
 					Type:      EventLoopAborted,
 					Iteration: iteration,
 					TaskID:    spec.ID,
-					AgentName: runCfg.AgentName,
+					AgentName: taskCfg.AgentName,
 					Message:   "context cancelled during agent invocation",
 					Timestamp: time.Now(),
 				})
@@ -304,7 +368,7 @@ func (r *Runner) Run(ctx context.Context, runCfg RunConfig) error {
 				Type:      EventAgentError,
 				Iteration: iteration,
 				TaskID:    spec.ID,
-				AgentName: runCfg.AgentName,
+				AgentName: taskCfg.AgentName,
 				Message:   err.Error(),
 				Timestamp: time.Now(),
 			})
@@ -315,7 +379,7 @@ func (r *Runner) Run(ctx context.Context, runCfg RunConfig) error {
 			Type:      EventAgentCompleted,
 			Iteration: iteration,
 			TaskID:    spec.ID,
-			AgentName: runCfg.AgentName,
+			AgentName: taskCfg.AgentName,
 			Message:   fmt.Sprintf("exit code %d", result.ExitCode),
 			Timestamp: time.Now(),
 			Duration:  result.Duration,
@@ -323,9 +387,10 @@ func (r *Runner) Run(ctx context.Context, runCfg RunConfig) error {
 
 		// Detect completion signals.
 		signal, detail := r.detectSignals(result.Stdout)
-		if err := r.handleCompletion(signal, detail, spec.ID, runCfg.AgentName); err != nil {
+		if err := r.handleCompletion(signal, detail, spec.ID, taskCfg.AgentName); err != nil {
 			return err
 		}
+		r.endIteration()
 
 		// If PHASE_COMPLETE detected, stop the loop.
 		if signal == SignalPhaseComplete {
@@ -333,7 +398,7 @@ func (r *Runner) Run(ctx context.Context, runCfg RunConfig) error {
 				Type:      EventPhaseComplete,
 				Iteration: iteration,
 				TaskID:    spec.ID,
-				AgentName: runCfg.AgentName,
+				AgentName: taskCfg.AgentName,
 				Message:   "PHASE_COMPLETE signal detected in output",
 				Timestamp: time.Now(),
 			})
@@ -352,8 +417,8 @@ func (r *Runner) Run(ctx context.Context, runCfg RunConfig) error {
 		// selectTask call (which would require an extra loop cycle that may not be
 		// available when MaxIterations is tight).
 		if signal != SignalTaskBlocked {
-			nextSpec, checkErr := r.selectTask(runCfg)
-			if checkErr == nil && nextSpec == nil {
+			next, checkErr := r.selectTask(runCfg)
+			if checkErr == nil && next == nil {
 				r.logger.Info("phase complete", "phase", runCfg.PhaseID, "iteration", iteration)
 				r.emit(LoopEvent{
 					Type:      EventPhaseComplete,
@@ -407,6 +472,12 @@ func (r *Runner) Run(ctx context.Context, runCfg RunConfig) error {
 func (r *Runner) RunSingleTask(ctx context.Context, runCfg RunConfig) error {
 	applyDefaults(&runCfg)
 	r.rateLimitWaits = 0 // reset per-run rate-limit wait counter
+	r.stateManager.SetRunID(runCfg.RunID)
+
+	startIteration, err := r.restoreCheckpoint(runCfg)
+	if err != nil {
+		return fmt.Errorf("single-task loop: %w", err)
+	}
 
 	r.logger.Info("starting single-task implementation",
 		"agent", runCfg.AgentName,
@@ -421,7 +492,7 @@ func (r *Runner) RunSingleTask(ctx context.Context, runCfg RunConfig) error {
 		Timestamp: time.Now(),
 	})
 
-	for iteration := 1; iteration <= runCfg.MaxIterations; iteration++ {
+	for iteration := startIteration; iteration <= runCfg.MaxIterations; iteration++ {
 		if err := ctx.Err(); err != nil {
 			r.emit(LoopEvent{
 				Type:      EventLoopAborted,
@@ -435,58 +506,70 @@ func (r *Runner) RunSingleTask(ctx context.Context, runCfg RunConfig) error {
 		}
 
 		// Select the specific task.
-		spec, err := r.selectTask(runCfg)
+		sel, err := r.selectTask(runCfg)
 		if err != nil {
 			r.emit(loopErrorEvent(iteration, runCfg.AgentName, err.Error()))
 			return fmt.Errorf("single-task loop: selecting task %s: %w", runCfg.TaskID, err)
 		}
-		if spec == nil {
+		if sel == nil {
 			// Task is nil -- this would be unusual in single-task mode.
 			return fmt.Errorf("task %s not found or not actionable", runCfg.TaskID)
 		}
+		spec := sel.Spec
+
+		// Apply the task's agent and model overrides from its front matter.
+		taskCfg := r.taskRunConfig(runCfg, spec)
 
 		r.emit(LoopEvent{
 			Type:      EventTaskSelected,
 			Iteration: iteration,
 			TaskID:    spec.ID,
-			AgentName: runCfg.AgentName,
+			AgentName: taskCfg.AgentName,
 			Message:   spec.Title,
+			Reason:    sel.Reason,
 			Timestamp: time.Now(),
 		})
 
-		// Mark in_progress.
-		if err := r.stateManager.UpdateStatus(spec.ID, task.StatusInProgress, runCfg.AgentName); err != nil {
-			return fmt.Errorf("updating task %s to in_progress: %w", spec.ID, err)
+		// Claim the task and mark it in_progress.
+		if err := r.stateManager.Claim(spec.ID, r.leaseOwner, taskCfg.AgentName, r.leaseTTL); err != nil {
+			return fmt.Errorf("claiming task %s: %w", spec.ID, err)
 		}
+		r.beginIteration(iteration, spec.ID, nil)
 
 		if runCfg.DryRun {
-			return r.handleDryRun(ctx, spec, runCfg, iteration)
+			if err := r.handleDryRun(ctx, spec, taskCfg, iteration); err != nil {
+				return err
+			}
+			r.endIteration()
+			return nil
 		}
 
 		// Generate prompt.
-		prompt, err := r.generatePrompt(spec, runCfg)
+		prompt, err := r.generatePrompt(spec, taskCfg)
 		if err != nil {
-			r.emit(loopErrorEvent(iteration, runCfg.AgentName, err.Error()))
+			r.emit(loopErrorEvent(iteration, taskCfg.AgentName, err.Error()))
 			return fmt.Errorf("generating prompt for task %s: %w", spec.ID, err)
 		}
 		r.emit(LoopEvent{
 			Type:      EventPromptGenerated,
 			Iteration: iteration,
 			TaskID:    spec.ID,
-			AgentName: runCfg.AgentName,
+			AgentName: taskCfg.AgentName,
 			Message:   fmt.Sprintf("prompt generated (%d bytes)", len(prompt)),
 			Timestamp: time.Now(),
 		})
 
 		// Invoke agent with rate-limit retry.
-		result, err := r.invokeAgentWithRetry(ctx, prompt, runCfg, iteration, spec.ID)
+		releaseLease := r.holdLease(spec.ID)
+		result, err := r.invokeAgentWithRetry(ctx, prompt, taskCfg, iteration, spec.ID)
+		releaseLease()
 		if err != nil {
 			if errors.Is(err, agent.ErrMaxWaitsExceeded) {
 				r.emit(LoopEvent{
 					Type:      EventLoopAborted,
 					Iteration: iteration,
 					TaskID:    spec.ID,
-					AgentName: runCfg.AgentName,
+					AgentName: taskCfg.AgentName,
 					Message:   "rate-limit max waits exceeded",
 					Timestamp: time.Now(),
 				})
@@ -497,7 +580,7 @@ func (r *Runner) RunSingleTask(ctx context.Context, runCfg RunConfig) error {
 					Type:      EventLoopAborted,
 					Iteration: iteration,
 					TaskID:    spec.ID,
-					AgentName: runCfg.AgentName,
+					AgentName: taskCfg.AgentName,
 					Message:   "context cancelled during agent invocation",
 					Timestamp: time.Now(),
 				})
@@ -507,7 +590,7 @@ func (r *Runner) RunSingleTask(ctx context.Context, runCfg RunConfig) error {
 				Type:      EventAgentError,
 				Iteration: iteration,
 				TaskID:    spec.ID,
-				AgentName: runCfg.AgentName,
+				AgentName: taskCfg.AgentName,
 				Message:   err.Error(),
 				Timestamp: time.Now(),
 			})
@@ -518,7 +601,7 @@ func (r *Runner) RunSingleTask(ctx context.Context, runCfg RunConfig) error {
 			Type:      EventAgentCompleted,
 			Iteration: iteration,
 			TaskID:    spec.ID,
-			AgentName: runCfg.AgentName,
+			AgentName: taskCfg.AgentName,
 			Message:   fmt.Sprintf("exit code %d", result.ExitCode),
 			Timestamp: time.Now(),
 			Duration:  result.Duration,
@@ -526,9 +609,10 @@ func (r *Runner) RunSingleTask(ctx context.Context, runCfg RunConfig) error {
 
 		// Detect completion signals.
 		signal, detail := r.detectSignals(result.Stdout)
-		if err := r.handleCompletion(signal, detail, spec.ID, runCfg.AgentName); err != nil {
+		if err := r.handleCompletion(signal, detail, spec.ID, taskCfg.AgentName); err != nil {
 			return err
 		}
+		r.endIteration()
 
 		// After a successful PHASE_COMPLETE or task completion, stop.
 		if signal == SignalPhaseComplete || signal == "" {
@@ -536,7 +620,7 @@ func (r *Runner) RunSingleTask(ctx context.Context, runCfg RunConfig) error {
 				Type:      EventPhaseComplete,
 				Iteration: iteration,
 				TaskID:    spec.ID,
-				AgentName: runCfg.AgentName,
+				AgentName: taskCfg.AgentName,
 				Message:   fmt.Sprintf("single task %s complete", spec.ID),
 				Timestamp: time.Now(),
 			})
@@ -600,21 +684,91 @@ func DetectSignals(output string) (CompletionSignal, string) {
 // ------- internal methods -------
 
 // selectTask picks the next task based on run mode. In single-task mode
-// (runCfg.TaskID non-empty) it uses SelectByID; otherwise it uses
-// SelectNext for phase mode.
-func (r *Runner) selectTask(runCfg RunConfig) (*task.ParsedTaskSpec, error) {
+// (runCfg.TaskID non-empty) it uses SelectByID; otherwise it uses the
+// selector's strategy for phase mode. Returns nil when the phase has no
+// actionable task.
+func (r *Runner) selectTask(runCfg RunConfig) (*task.Selection, error) {
 	if runCfg.TaskID != "" {
 		spec, err := r.selector.SelectByID(runCfg.TaskID)
 		if err != nil {
 			return nil, fmt.Errorf("selecting task by ID %s: %w", runCfg.TaskID, err)
 		}
-		return spec, nil
+		return &task.Selection{Spec: spec, Reason: "requested by task ID", Candidates: 1}, nil
 	}
-	spec, err := r.selector.SelectNext(runCfg.PhaseID)
+	sel, err := r.selector.Select(runCfg.PhaseID)
 	if err != nil {
 		return nil, fmt.Errorf("selecting next task in phase %d: %w", runCfg.PhaseID, err)
 	}
-	return spec, nil
+	return sel, nil
+}
+
+// holdLease renews the runner's lease on taskID every third of the lease
+// duration until the returned function is called, which stops renewing and
+// releases the lease. The task keeps its status; the caller records the
+// outcome with UpdateStatus.
+func (r *Runner) holdLease(taskID string) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(max(r.leaseTTL/3, time.Millisecond))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := r.stateManager.Renew(taskID, r.leaseOwner, r.leaseTTL); err != nil {
+					r.logger.Info("failed to renew task lease", "task", taskID, "err", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+		if err := r.stateManager.Release(taskID, r.leaseOwner); err != nil {
+			r.logger.Info("failed to release task lease", "task", taskID, "err", err)
+		}
+	}
+}
+
+// SetAgentRegistry configures the registry used to resolve per-task agent
+// overrides declared in task spec front matter. Without a registry, agent
+// overrides are ignored and every task runs on the runner's agent.
+func (r *Runner) SetAgentRegistry(reg *agent.Registry) {
+	r.agents = reg
+}
+
+// taskRunConfig returns runCfg with the agent and model overrides from the
+// task spec applied. An agent override naming an unregistered agent is logged
+// and ignored.
+func (r *Runner) taskRunConfig(runCfg RunConfig, spec *task.ParsedTaskSpec) RunConfig {
+	if spec.Agent != "" && spec.Agent != runCfg.AgentName {
+		if r.agents != nil && r.agents.Has(spec.Agent) {
+			runCfg.AgentName = spec.Agent
+		} else {
+			r.logger.Info("ignoring task agent override: agent not registered",
+				"task", spec.ID,
+				"agent", spec.Agent,
+			)
+		}
+	}
+	if spec.Model != "" {
+		runCfg.Model = spec.Model
+	}
+	return runCfg
+}
+
+// agentFor returns the agent to invoke for name: the runner's own agent, or
+// the registered agent when a task override selected a different one.
+func (r *Runner) agentFor(name string) agent.Agent {
+	if r.agents != nil && (r.agent == nil || r.agent.Name() != name) {
+		if ag, err := r.agents.Get(name); err == nil {
+			return ag
+		}
+	}
+	return r.agent
 }
 
 // generatePrompt builds the prompt for the given task spec by looking up the
@@ -665,6 +819,9 @@ func (r *Runner) invokeAgent(ctx context.Context, prompt string, runCfg RunConfi
 		OutputFormat: agent.OutputFormatStreamJSON,
 		StreamEvents: streamCh,
 	}
+	if runCfg.Model != "" {
+		opts.Model = runCfg.Model
+	}
 
 	r.logger.Debug("invoking agent",
 		"agent", runCfg.AgentName,
@@ -679,7 +836,7 @@ func (r *Runner) invokeAgent(ctx context.Context, prompt string, runCfg RunConfi
 		r.consumeStreamEvents(ctx, streamCh, iteration, taskID, runCfg.AgentName)
 	}()
 
-	result, err := r.agent.Run(ctx, opts)
+	result, err := r.agentFor(runCfg.AgentName).Run(ctx, opts)
 
 	// Close the channel now that Run has returned; the consumer will drain any
 	// remaining buffered events then exit.
@@ -709,6 +866,7 @@ func (r *Runner) consumeStreamEvents(ctx context.Context, streamCh <-chan agent.
 			if !ok {
 				return
 			}
+			r.recordSessionID(taskID, event.SessionID)
 			switch event.Type {
 			case agent.StreamEventAssistant:
 				// Accumulate token usage from assistant messages.
@@ -954,9 +1112,10 @@ func (r *Runner) handleCompletion(signal CompletionSignal, detail string, taskID
 	return nil
 }
 
-// regenerateProgress writes an updated PROGRESS.md if a ProgressGenerator has
-// been configured via SetProgressGenerator. Errors are logged but do not
-// interrupt the loop -- progress file generation is best-effort.
+// regenerateProgress writes an updated PROGRESS.md (and the generator's other
+// formats) if a ProgressGenerator has been configured via
+// SetProgressGenerator. Errors are logged but do not interrupt the loop --
+// progress file generation is best-effort.
 func (r *Runner) regenerateProgress() {
 	if r.progressGen == nil || r.progressPath == "" {
 		return
@@ -965,7 +1124,7 @@ func (r *Runner) regenerateProgress() {
 	if projectName == "" {
 		projectName = "Raven"
 	}
-	if err := r.progressGen.WriteFile(r.progressPath, projectName); err != nil {
+	if err := r.progressGen.WriteFiles(r.progressPath, projectName); err != nil {
 		r.logger.Info("failed to regenerate PROGRESS.md", "path", r.progressPath, "err", err)
 	}
 }
@@ -987,7 +1146,10 @@ func (r *Runner) handleDryRun(_ context.Context, spec *task.ParsedTaskSpec, runC
 		Effort:       agentCfg.Effort,
 		AllowedTools: agentCfg.AllowedTools,
 	}
-	cmd := r.agent.DryRunCommand(opts)
+	if runCfg.Model != "" {
+		opts.Model = runCfg.Model
+	}
+	cmd := r.agentFor(runCfg.AgentName).DryRunCommand(opts)
 
 	log.Info("[DRY RUN] would execute", "command", cmd, "task", spec.ID)
 	fmt.Fprintf(os.Stderr, "\n--- DRY RUN: task %s ---\n%s\n\n--- PROMPT ---\n%s\n", spec.ID, cmd, prompt)
@@ -1011,8 +1173,12 @@ func (r *Runner) handleDryRun(_ context.Context, spec *task.ParsedTaskSpec, runC
 }
 
 // emit sends a LoopEvent to the events channel in a non-blocking fashion.
-// If the channel is full, the event is dropped.
+// If the channel is full, the event is dropped. Every event is first passed
+// to the metrics collector.
 func (r *Runner) emit(event LoopEvent) {
+	if r.metrics != nil {
+		r.metrics.observe(event)
+	}
 	if r.events == nil {
 		return
 	}
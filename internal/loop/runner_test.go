@@ -1199,6 +1199,48 @@ func TestRun_EventsHaveCorrectFields(t *testing.T) {
 	}
 }
 
+func TestRun_TaskSelectedCarriesStrategyReason(t *testing.T) {
+	t.Parallel()
+
+	low := makeTestSpec("T-001", "Task 1", "# T-001: Task 1\n")
+	low.Priority = "nice-to-have"
+	high := makeTestSpec("T-002", "Task 2", "# T-002: Task 2\n")
+	high.Priority = "must-have"
+	specs := []*task.ParsedTaskSpec{low, high}
+	phases := makePhases(1, "T-001", "T-002")
+
+	sm := task.NewStateManager(filepath.Join(t.TempDir(), "task-state.conf"))
+	sel := task.NewTaskSelector(specs, sm, phases)
+	strategy, err := task.NewSelectionStrategy(task.StrategyPriority)
+	require.NoError(t, err)
+	sel.SetStrategy(strategy)
+
+	pg, err := NewPromptGenerator("")
+	require.NoError(t, err)
+	events := make(chan LoopEvent, 64)
+	runner := NewRunner(sel, pg, agent.NewMockAgent("mock"), sm,
+		agent.NewRateLimitCoordinator(agent.DefaultBackoffConfig()),
+		&config.Config{}, phases, events, &testLogger{t: t})
+
+	_ = runner.Run(context.Background(), RunConfig{
+		AgentName:     "mock",
+		PhaseID:       1,
+		MaxIterations: 1,
+		DryRun:        true,
+	})
+
+	var selected *LoopEvent
+	for _, e := range drainEvents(events) {
+		if e.Type == EventTaskSelected {
+			selected = &e
+			break
+		}
+	}
+	require.NotNil(t, selected)
+	assert.Equal(t, "T-002", selected.TaskID)
+	assert.Equal(t, "highest priority (must-have) of 2 ready", selected.Reason)
+}
+
 // ---- MaxIterations stops the loop with an error ----
 
 func TestRun_MaxIterationsError(t *testing.T) {
@@ -1590,3 +1632,152 @@ func TestRun_RateLimitMaxWaitsExceeded(t *testing.T) {
 	assert.True(t, errors.Is(err, agent.ErrMaxWaitsExceeded) || strings.Contains(err.Error(), "max waits") || strings.Contains(err.Error(), "aborted"),
 		"expected max waits error, got: %v", err)
 }
+
+func TestRun_TaskModelOverride(t *testing.T) {
+	t.Parallel()
+
+	spec := makeTestSpec("T-001", "Task 1", "# T-001: Task 1\n")
+	spec.Model = "override-model"
+	ag := agent.NewMockAgent("mock")
+
+	runner, _, _ := makeRunnerDeps(t, []*task.ParsedTaskSpec{spec}, nil, makePhases(1, "T-001", "T-001"), ag)
+
+	err := runner.Run(context.Background(), RunConfig{AgentName: "mock", PhaseID: 1})
+	require.NoError(t, err)
+
+	calls := ag.GetCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "override-model", calls[0].Model)
+}
+
+func TestRun_TaskAgentOverride(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		register     bool
+		wantDefault  int
+		wantOverride int
+		wantAgent    string
+	}{
+		{name: "registered agent is used", register: true, wantDefault: 0, wantOverride: 1, wantAgent: "other"},
+		{name: "unregistered agent is ignored", register: false, wantDefault: 1, wantOverride: 0, wantAgent: "mock"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			spec := makeTestSpec("T-001", "Task 1", "# T-001: Task 1\n")
+			spec.Agent = "other"
+			def := agent.NewMockAgent("mock")
+			other := agent.NewMockAgent("other")
+
+			runner, sm, events := makeRunnerDeps(t, []*task.ParsedTaskSpec{spec}, nil, makePhases(1, "T-001", "T-001"), def)
+			reg := agent.NewRegistry()
+			require.NoError(t, reg.Register(def))
+			if tt.register {
+				require.NoError(t, reg.Register(other))
+			}
+			runner.SetAgentRegistry(reg)
+
+			err := runner.Run(context.Background(), RunConfig{AgentName: "mock", PhaseID: 1})
+			require.NoError(t, err)
+
+			assert.Len(t, def.GetCalls(), tt.wantDefault)
+			assert.Len(t, other.GetCalls(), tt.wantOverride)
+
+			// The state and the task's events record the agent that ran it.
+			ts, err := sm.Get("T-001")
+			require.NoError(t, err)
+			require.NotNil(t, ts)
+			assert.Equal(t, tt.wantAgent, ts.Agent)
+			close(events)
+			var taskEvents int
+			for ev := range events {
+				if ev.TaskID == "T-001" {
+					taskEvents++
+					assert.Equal(t, tt.wantAgent, ev.AgentName, "event %s", ev.Type)
+				}
+			}
+			assert.NotZero(t, taskEvents)
+		})
+	}
+}
+
+// ---- Task leases ----
+
+func TestRun_SkipsTaskClaimedElsewhere(t *testing.T) {
+	t.Parallel()
+
+	specs := []*task.ParsedTaskSpec{
+		makeTestSpec("T-001", "Task 1", "# T-001: Task 1\n"),
+		makeTestSpec("T-002", "Task 2", "# T-002: Task 2\n"),
+	}
+	phases := makePhases(1, "T-001", "T-002")
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	runner, sm, _ := makeRunnerDeps(t, specs, []string{
+		"T-001|in_progress|mock||started|other-host:1|" + future + "|" + future,
+	}, phases, agent.NewMockAgent("mock"))
+
+	_ = runner.Run(context.Background(), RunConfig{AgentName: "mock", PhaseID: 1, MaxIterations: 1})
+
+	first, err := sm.Get("T-001")
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusInProgress, first.Status)
+	assert.Equal(t, "other-host:1", first.ClaimedBy)
+
+	second, err := sm.Get("T-002")
+	require.NoError(t, err)
+	require.NotNil(t, second)
+	assert.Equal(t, task.StatusCompleted, second.Status)
+	assert.Empty(t, second.ClaimedBy, "completing a task ends the claim")
+}
+
+func TestRunSingleTask_ClaimedElsewhere(t *testing.T) {
+	t.Parallel()
+
+	specs := []*task.ParsedTaskSpec{makeTestSpec("T-001", "Task 1", "# T-001: Task 1\n")}
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	ag := agent.NewMockAgent("mock")
+	runner, _, _ := makeRunnerDeps(t, specs, []string{
+		"T-001|in_progress|mock||started|other-host:1|" + future + "|" + future,
+	}, makePhases(1, "T-001", "T-001"), ag)
+
+	err := runner.RunSingleTask(context.Background(), RunConfig{AgentName: "mock", TaskID: "T-001"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, task.ErrTaskUnavailable))
+	assert.Empty(t, ag.GetCalls())
+}
+
+func TestRunSingleTask_HoldsLeaseWhileAgentRuns(t *testing.T) {
+	t.Parallel()
+
+	specs := []*task.ParsedTaskSpec{makeTestSpec("T-001", "Task 1", "# T-001: Task 1\n")}
+
+	// Lease timestamps have one-second resolution, so the lease must outlive
+	// a few seconds of renewals for the check to be meaningful: without
+	// heartbeats, a 3s lease has expired by the time the agent checks.
+	var sm *task.StateManager
+	var during *task.TaskState
+	ag := agent.NewMockAgent("mock").WithRunFunc(func(ctx context.Context, _ agent.RunOpts) (*agent.RunResult, error) {
+		time.Sleep(3200 * time.Millisecond)
+		during, _ = sm.Get("T-001")
+		return &agent.RunResult{ExitCode: 0}, nil
+	})
+	runner, stateManager, _ := makeRunnerDeps(t, specs, nil, makePhases(1, "T-001", "T-001"), ag)
+	sm = stateManager
+	runner.SetLease("test-runner", 3*time.Second)
+
+	require.NoError(t, runner.RunSingleTask(context.Background(), RunConfig{AgentName: "mock", TaskID: "T-001"}))
+
+	require.NotNil(t, during)
+	assert.Equal(t, task.StatusInProgress, during.Status)
+	assert.Equal(t, "test-runner", during.ClaimedBy)
+	assert.True(t, during.LeaseActive(time.Now()), "lease should have been renewed")
+
+	after, err := sm.Get("T-001")
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusCompleted, after.Status)
+	assert.Empty(t, after.ClaimedBy)
+}
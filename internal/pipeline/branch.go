@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/log"
 
 	"github.com/AbdelazizMoustafa10m/Raven/internal/git"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
 )
 
 // defaultBranchTemplate is the branch name pattern used when no template is
@@ -63,6 +64,11 @@ type PhaseBranchOpts struct {
 	// PhaseName is the human-readable phase name used to build the slug.
 	PhaseName string
 
+	// StartTask and EndTask are substituted for the {start_task} and
+	// {end_task} template variables.
+	StartTask string
+	EndTask   string
+
 	// ProjectName is substituted for the {project} template variable.
 	ProjectName string
 
@@ -82,12 +88,26 @@ type PhaseBranchOpts struct {
 //   - {phase_id}  — the numeric phase identifier (e.g., "1")
 //   - {slug}      — a kebab-case slug derived from phaseName
 //   - {project}   — projectName as supplied by the caller
+//
+// The task-range variables {start_task} and {end_task} are only available via
+// ResolvePhaseBranchName; here they resolve to empty strings.
 func (b *BranchManager) ResolveBranchName(phaseID int, phaseName string, projectName string) string {
-	slug := slugify(phaseName)
+	return b.ResolvePhaseBranchName(task.Phase{ID: phaseID, Name: phaseName}, projectName)
+}
+
+// ResolvePhaseBranchName is ResolveBranchName for a full phase definition. In
+// addition to {phase_id}, {slug} and {project} it substitutes:
+//
+//   - {start_task} — the phase's first task ID as written (e.g., "T-1000", "API-0042")
+//   - {end_task}   — the phase's last task ID as written
+func (b *BranchManager) ResolvePhaseBranchName(phase task.Phase, projectName string) string {
+	slug := slugify(phase.Name)
 	r := strings.NewReplacer(
-		"{phase_id}", fmt.Sprintf("%d", phaseID),
+		"{phase_id}", fmt.Sprintf("%d", phase.ID),
 		"{slug}", slug,
 		"{project}", projectName,
+		"{start_task}", phase.StartTask,
+		"{end_task}", phase.EndTask,
 	)
 	return r.Replace(b.branchTemplate)
 }
@@ -111,7 +131,7 @@ func (b *BranchManager) CreatePhaseBranch(ctx context.Context, opts PhaseBranchO
 		}
 	}
 
-	branchName := b.ResolveBranchName(opts.PhaseID, opts.PhaseName, opts.ProjectName)
+	branchName := b.ResolvePhaseBranchName(opts.phase(), opts.ProjectName)
 
 	if err := b.gitClient.CreateBranch(ctx, branchName, base); err != nil {
 		return "", fmt.Errorf("branch manager: create phase branch %q from %q: %w", branchName, base, err)
@@ -150,7 +170,7 @@ func (b *BranchManager) BranchExists(ctx context.Context, branchName string) (bo
 // switches to it if it does. This method is idempotent and is the primary
 // entry point for resume logic. It returns the resolved branch name.
 func (b *BranchManager) EnsureBranch(ctx context.Context, opts PhaseBranchOpts) (string, error) {
-	branchName := b.ResolveBranchName(opts.PhaseID, opts.PhaseName, opts.ProjectName)
+	branchName := b.ResolvePhaseBranchName(opts.phase(), opts.ProjectName)
 
 	exists, err := b.gitClient.BranchExists(ctx, branchName)
 	if err != nil {
@@ -174,6 +194,16 @@ func (b *BranchManager) EnsureBranch(ctx context.Context, opts PhaseBranchOpts)
 
 // --- internal helpers ---
 
+// phase returns the task.Phase described by opts for branch name resolution.
+func (opts PhaseBranchOpts) phase() task.Phase {
+	return task.Phase{
+		ID:        opts.PhaseID,
+		Name:      opts.PhaseName,
+		StartTask: opts.StartTask,
+		EndTask:   opts.EndTask,
+	}
+}
+
 // slugify converts an arbitrary string into a URL-safe kebab-case slug. It
 // lowercases all input, replaces any sequence of non-alphanumeric characters
 // with a single hyphen, and trims leading/trailing hyphens.
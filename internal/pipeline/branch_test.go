@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/AbdelazizMoustafa10m/Raven/internal/git"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
 )
 
 // --- TestSlugify ----------------------------------------------------------
@@ -687,6 +688,41 @@ func TestSlugify_EdgeCases(t *testing.T) {
 
 // --- Additional ResolveBranchName edge cases ---------------------------------
 
+func TestResolvePhaseBranchName_TaskRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		phase    task.Phase
+		want     string
+	}{
+		{
+			name:     "default prefix beyond 999",
+			template: "phase/{phase_id}-{start_task}-{end_task}",
+			phase:    task.Phase{ID: 7, Name: "Scale", StartTask: "T-998", EndTask: "T-1004"},
+			want:     "phase/7-T-998-T-1004",
+		},
+		{
+			name:     "custom prefix",
+			template: "{project}/{start_task}-{slug}",
+			phase:    task.Phase{ID: 2, Name: "API Work", StartTask: "API-0042", EndTask: "API-0050"},
+			want:     "raven/API-0042-api-work",
+		},
+		{
+			name:     "template without task variables",
+			template: "phase/{phase_id}-{slug}",
+			phase:    task.Phase{ID: 1, Name: "Foundation", StartTask: "T-001", EndTask: "T-010"},
+			want:     "phase/1-foundation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bm := NewBranchManager(nil, tt.template, "main")
+			assert.Equal(t, tt.want, bm.ResolvePhaseBranchName(tt.phase, "raven"))
+		})
+	}
+}
+
 func TestResolveBranchName_EdgeCases(t *testing.T) {
 	tests := []struct {
 		name        string
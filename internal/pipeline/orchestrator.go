@@ -52,6 +52,10 @@ type PipelineOpts struct {
 	// SkipPR bypasses create_pr; the pipeline completes after review/fix.
 	SkipPR bool
 
+	// PublishReview posts the last review of each phase to its PR as GitHub
+	// review comments once the PR is created.
+	PublishReview bool
+
 	// ImplAgent names the agent to use for the implementation step.
 	ImplAgent string
 
@@ -118,6 +122,9 @@ type PhaseResult struct {
 	BranchName    string        `json:"branch_name"`
 	Duration      time.Duration `json:"duration"`
 	Error         string        `json:"error,omitempty"`
+
+	// PublishError is set when the review could not be posted to the PR.
+	PublishError string `json:"publish_error,omitempty"`
 }
 
 // PipelineResult is the final outcome of a complete pipeline run, containing
@@ -318,7 +325,7 @@ func (p *PipelineOrchestrator) DryRun(opts PipelineOpts) string {
 	for _, ph := range phases {
 		var branchName string
 		if branchMgr != nil {
-			branchName = branchMgr.ResolveBranchName(ph.ID, ph.Name, projectName)
+			branchName = branchMgr.ResolvePhaseBranchName(ph, projectName)
 		} else {
 			branchName = phaseBranchName(strconv.Itoa(ph.ID))
 		}
@@ -347,10 +354,11 @@ func (p *PipelineOrchestrator) resolvePhases(opts PipelineOpts) ([]task.Phase, e
 		return nil, fmt.Errorf("pipeline orchestrator: project.phases_conf is not configured")
 	}
 
-	phases, err := task.LoadPhases(phasesPath)
+	phases, err := task.LoadPhases(phasesPath, p.config.Project.TaskIDPrefix)
 	if err != nil {
 		return nil, fmt.Errorf("pipeline orchestrator: load phases: %w", err)
 	}
+	p.fillPhaseSpans(phases)
 
 	// "all" or empty string: return every phase, optionally filtered by FromPhase.
 	if opts.PhaseID == "" || strings.EqualFold(opts.PhaseID, "all") {
@@ -372,6 +380,39 @@ func (p *PipelineOrchestrator) resolvePhases(opts PipelineOpts) ([]task.Phase, e
 	return []task.Phase{*ph}, nil
 }
 
+// fillPhaseSpans sets StartTask and EndTask on phases defined only by task
+// lists or labels to their first and last member task, so that dry-run plans,
+// branch names ({start_task}/{end_task}) and workflow metadata can show the
+// phase's span. Task selection is unaffected: it reloads the phase
+// definitions itself.
+func (p *PipelineOrchestrator) fillPhaseSpans(phases []task.Phase) {
+	needed := false
+	for _, ph := range phases {
+		if !ph.HasRange() {
+			needed = true
+			break
+		}
+	}
+	if !needed {
+		return
+	}
+
+	var specs []*task.ParsedTaskSpec
+	if p.config.Project.TasksDir != "" {
+		var err error
+		specs, err = task.DiscoverTasks(p.config.Project.TasksDir)
+		if err != nil {
+			p.log("discovering tasks for phase spans failed", "error", err)
+		}
+	}
+	membership := task.NewPhaseMembership(phases, specs)
+	for i := range phases {
+		if !phases[i].HasRange() {
+			phases[i].StartTask, phases[i].EndTask = membership.Bounds(phases[i].ID)
+		}
+	}
+}
+
 // filterFromPhase returns phases whose numeric ID is >= the fromPhase value.
 func filterFromPhase(phases []task.Phase, fromPhase string) ([]task.Phase, error) {
 	from, err := strconv.Atoi(fromPhase)
@@ -406,7 +447,7 @@ func (p *PipelineOrchestrator) runPhase(ctx context.Context, ph task.Phase, opts
 			projectName = p.config.Project.Name
 		}
 		branchMgr := NewBranchManager(p.gitClient, opts.BranchTemplate, opts.BaseBranch)
-		branchName = branchMgr.ResolveBranchName(ph.ID, ph.Name, projectName)
+		branchName = branchMgr.ResolvePhaseBranchName(ph, projectName)
 	} else {
 		branchName = phaseBranchName(phaseID)
 	}
@@ -463,6 +504,7 @@ func (p *PipelineOrchestrator) runPhase(ctx context.Context, ph task.Phase, opts
 	state.Metadata["review_concurrency"] = opts.ReviewConcurrency
 	state.Metadata["max_fix_cycles"] = opts.MaxReviewCycles
 	state.Metadata["base_branch"] = opts.BaseBranch
+	state.Metadata["publish_review"] = opts.PublishReview
 	state.Metadata["branch_name"] = branchName
 	state.Metadata["phase_name"] = ph.Name
 	state.Metadata["start_task"] = ph.StartTask
@@ -646,6 +688,9 @@ func extractPhaseResult(result PhaseResult, state *workflow.WorkflowState) Phase
 	if v, ok := state.Metadata["pr_url"]; ok {
 		result.PRURL = fmt.Sprintf("%v", v)
 	}
+	if v, ok := state.Metadata["review_publish_error"]; ok {
+		result.PublishError = fmt.Sprintf("%v", v)
+	}
 	if v, ok := state.Metadata["error"]; ok && result.Error == "" {
 		result.Error = fmt.Sprintf("%v", v)
 	}
@@ -315,6 +315,41 @@ func TestResolvePhases_NotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "phase 99 not found")
 }
 
+func TestResolvePhases_FillsSpansForListPhases(t *testing.T) {
+	dir := t.TempDir()
+	tasksDir := filepath.Join(dir, "tasks")
+	require.NoError(t, os.MkdirAll(tasksDir, 0o755))
+	for _, id := range []string{"T-021", "T-034"} {
+		require.NoError(t, os.WriteFile(filepath.Join(tasksDir, id+"-task.md"),
+			[]byte("---\nlabels: [api]\n---\n# "+id+": Task\n"), 0o644))
+	}
+	phasesPath := filepath.Join(dir, "phases.toml")
+	require.NoError(t, os.WriteFile(phasesPath, []byte(`[[phase]]
+id = 1
+name = "Foundation"
+start_task = "T-001"
+end_task = "T-010"
+
+[[phase]]
+id = 2
+name = "API"
+tasks = ["T-012"]
+labels = ["api"]
+`), 0o644))
+
+	cfg := makeConfig(phasesPath)
+	cfg.Project.TasksDir = tasksDir
+	orch := NewPipelineOrchestrator(nil, nil, nil, cfg)
+
+	phases, err := orch.resolvePhases(PipelineOpts{})
+	require.NoError(t, err)
+	require.Len(t, phases, 2)
+	assert.Equal(t, "T-001", phases[0].StartTask)
+	assert.Equal(t, "T-010", phases[0].EndTask)
+	assert.Equal(t, "T-012", phases[1].StartTask)
+	assert.Equal(t, "T-034", phases[1].EndTask)
+}
+
 func TestResolvePhases_NoPhasesConf(t *testing.T) {
 	orch := NewPipelineOrchestrator(nil, nil, nil, &config.Config{})
 	_, err := orch.resolvePhases(PipelineOpts{})
@@ -1285,3 +1320,13 @@ func findStep(def *workflow.WorkflowDefinition, name string) *workflow.StepDefin
 	}
 	return nil
 }
+
+func TestExtractPhaseResult_PublishError(t *testing.T) {
+	state := workflow.NewWorkflowState("id", "wf", "step")
+	state.Metadata["review_publish_error"] = "publish: gh not found"
+
+	result := extractPhaseResult(PhaseResult{PhaseID: "1"}, state)
+
+	assert.Equal(t, "publish: gh not found", result.PublishError)
+	assert.Empty(t, result.Error)
+}
@@ -57,13 +57,15 @@ type EmitOpts struct {
 	Epics *EpicBreakdown
 	// StartID is the starting task number for re-sequencing (default 1).
 	StartID int
+	// IDPrefix is the task ID prefix for re-sequenced IDs (default "T").
+	IDPrefix string
 }
 
 // EmitResult summarises all files generated by Emit.
 type EmitResult struct {
 	// OutputDir is the directory in which all files were written.
 	OutputDir string
-	// TaskFiles lists the paths of all generated T-XXX-slug.md files.
+	// TaskFiles lists the paths of all generated <TASK-ID>-slug.md files.
 	TaskFiles []string
 	// TaskStateFile is the path to the generated task-state.conf.
 	TaskStateFile string
@@ -133,7 +135,7 @@ func (e *Emitter) Emit(opts EmitOpts) (*EmitResult, error) {
 
 	// Re-sequence GlobalIDs to close gaps from deduplication, starting from
 	// the configured StartID (defaults to 1 when zero or negative).
-	tasks, idMap := ResequenceIDs(opts.Tasks, opts.StartID)
+	tasks, idMap := ResequenceIDsWithPrefix(opts.Tasks, opts.IDPrefix, opts.StartID)
 	if len(idMap) > 0 {
 		e.logger.Debug("re-sequenced task IDs", "remapped", len(idMap))
 	}
@@ -262,25 +264,32 @@ func (e *Emitter) writeFile(path string, content []byte) error {
 // Returns the updated task slice and an IDMapping from old GlobalID to new GlobalID.
 // Only IDs that actually changed are included in the mapping.
 func ResequenceIDs(tasks []MergedTask, startID ...int) ([]MergedTask, IDMapping) {
+	start := 1
+	if len(startID) > 0 {
+		start = startID[0]
+	}
+	return ResequenceIDsWithPrefix(tasks, "", start)
+}
+
+// ResequenceIDsWithPrefix is ResequenceIDs with a configurable task ID prefix
+// (e.g. "API" produces API-050, API-051, ...). An empty prefix falls back to
+// the default "T".
+func ResequenceIDsWithPrefix(tasks []MergedTask, prefix string, startID int) ([]MergedTask, IDMapping) {
 	if len(tasks) == 0 {
 		return nil, IDMapping{}
 	}
 
 	start := 1
-	if len(startID) > 0 && startID[0] > 0 {
-		start = startID[0]
-	}
-
-	format := "T-%03d"
-	if start+len(tasks)-1 >= 1000 {
-		format = "T-%04d"
+	if startID > 0 {
+		start = startID
 	}
+	digits := globalIDDigits(start + len(tasks) - 1)
 
 	// Build the old->new mapping.
 	mapping := make(IDMapping, len(tasks))
 	hasChanges := false
 	for i, task := range tasks {
-		newID := fmt.Sprintf(format, start+i)
+		newID := formatGlobalID(prefix, start+i, digits)
 		if task.GlobalID != newID {
 			mapping[task.GlobalID] = newID
 			hasChanges = true
@@ -297,7 +306,7 @@ func ResequenceIDs(tasks []MergedTask, startID ...int) ([]MergedTask, IDMapping)
 	// Apply new IDs and remap dependencies.
 	out := make([]MergedTask, len(tasks))
 	for i, task := range tasks {
-		newID := fmt.Sprintf(format, start+i)
+		newID := formatGlobalID(prefix, start+i, digits)
 		task.GlobalID = newID
 
 		if len(task.Dependencies) > 0 {
@@ -361,7 +370,7 @@ func AssignPhases(tasks []MergedTask, depths map[string]int, epics *EpicBreakdow
 
 		// Sort tasks in this phase by GlobalID.
 		sort.Slice(group, func(i, j int) bool {
-			return group[i].GlobalID < group[j].GlobalID
+			return lessGlobalID(group[i].GlobalID, group[j].GlobalID)
 		})
 
 		// Determine phase name from the most common epic title.
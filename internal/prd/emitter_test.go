@@ -147,6 +147,22 @@ func TestResequenceIDs_WithGaps(t *testing.T) {
 	assert.Equal(t, []string{"T-001", "T-002"}, out[2].Dependencies)
 }
 
+func TestResequenceIDsWithPrefix_CustomPrefixAndStart(t *testing.T) {
+	t.Parallel()
+
+	tasks := []MergedTask{
+		{GlobalID: "T-001", Title: "Task A"},
+		{GlobalID: "T-002", Title: "Task B", Dependencies: []string{"T-001"}},
+	}
+
+	out, mapping := ResequenceIDsWithPrefix(tasks, "API", 42)
+
+	assert.Equal(t, "API-042", out[0].GlobalID)
+	assert.Equal(t, "API-043", out[1].GlobalID)
+	assert.Equal(t, []string{"API-042"}, out[1].Dependencies)
+	assert.Equal(t, "API-042", mapping["T-001"])
+}
+
 func TestResequenceIDs_Empty(t *testing.T) {
 	t.Parallel()
 
@@ -1506,7 +1522,7 @@ func TestIntegration_RoundTrip_PhasesConf(t *testing.T) {
 	require.NoError(t, err)
 
 	// --- Round-trip: read phases.conf back via task.LoadPhases ---
-	phases, err := task.LoadPhases(result.PhasesFile)
+	phases, err := task.LoadPhases(result.PhasesFile, "")
 	require.NoError(t, err, "task.LoadPhases must parse the generated phases.conf without error")
 
 	assert.Equal(t, result.TotalPhases, len(phases),
@@ -1526,6 +1542,39 @@ func TestIntegration_RoundTrip_PhasesConf(t *testing.T) {
 	}
 }
 
+func TestIntegration_RoundTrip_PrefixBeyond999(t *testing.T) {
+	t.Parallel()
+
+	outDir := t.TempDir()
+	emitter := NewEmitter(outDir, WithForce(true))
+
+	tasks, validation, epics := buildRealisticDataset()
+	require.True(t, validation.Valid)
+
+	result, err := emitter.Emit(EmitOpts{
+		Tasks:      tasks,
+		Validation: validation,
+		Epics:      epics,
+		StartID:    995,
+		IDPrefix:   "API",
+	})
+	require.NoError(t, err)
+
+	// Every generated task file must be discoverable by the task parser.
+	specs, err := task.DiscoverTasks(outDir)
+	require.NoError(t, err)
+	require.Len(t, specs, len(tasks))
+	assert.Equal(t, "API-0995", specs[0].ID)
+	assert.Equal(t, "API-1009", specs[len(specs)-1].ID)
+
+	// Phase ranges must resolve to the emitted task IDs.
+	phases, err := task.LoadPhases(result.PhasesFile, "")
+	require.NoError(t, err)
+	for _, spec := range specs {
+		assert.NotNil(t, task.PhaseForTask(phases, spec.ID), "task %s must belong to a phase", spec.ID)
+	}
+}
+
 func TestIntegration_RoundTrip_TaskStateConf(t *testing.T) {
 	t.Parallel()
 
@@ -1758,7 +1807,7 @@ func TestIntegration_FullPipelineWithEpicsAndValidation(t *testing.T) {
 	assert.GreaterOrEqual(t, result.TotalPhases, 1)
 
 	// Round-trip: phases.conf.
-	phases, err := task.LoadPhases(result.PhasesFile)
+	phases, err := task.LoadPhases(result.PhasesFile, "")
 	require.NoError(t, err)
 	assert.Equal(t, result.TotalPhases, len(phases))
 
@@ -1836,7 +1885,7 @@ func TestIntegration_PhaseForTask_WorksWithGeneratedPhases(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	phases, err := task.LoadPhases(result.PhasesFile)
+	phases, err := task.LoadPhases(result.PhasesFile, "")
 	require.NoError(t, err)
 
 	// task.PhaseForTask should find a phase for each task.
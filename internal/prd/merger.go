@@ -6,6 +6,8 @@ import (
 	"sort"
 	"strings"
 	"unicode"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
 )
 
 // IDMapping maps a task's temp_id to its assigned global_id.
@@ -15,7 +17,8 @@ type IDMapping map[string]string
 // MergedTask holds a task with its assigned global ID, retaining the original
 // temp ID and all fields from the source TaskDef.
 type MergedTask struct {
-	// GlobalID is the sequential global identifier in T-NNN (or T-NNNN) format.
+	// GlobalID is the sequential global identifier in PREFIX-NNN (or
+	// PREFIX-NNNN) format, e.g. "T-001" or "API-0042".
 	GlobalID string
 	// TempID is the original temporary task identifier (e.g., E001-T01).
 	TempID string
@@ -207,6 +210,7 @@ func SortEpicsByDependency(breakdown *EpicBreakdown) ([]string, error) {
 
 // AssignGlobalIDs assigns T-001, T-002, ... to all tasks across epics in the
 // order determined by epicOrder. Tasks within each epic retain their original order.
+// It is AssignGlobalIDsWithPrefix with task.DefaultTaskIDPrefix.
 //
 // Epics present in results but absent from epicOrder are appended at the end,
 // sorted by epic ID, for deterministic output.
@@ -220,17 +224,24 @@ func SortEpicsByDependency(breakdown *EpicBreakdown) ([]string, error) {
 func AssignGlobalIDs(
 	epicOrder []string,
 	results map[string]*EpicTaskResult,
+) ([]MergedTask, IDMapping) {
+	return AssignGlobalIDsWithPrefix(epicOrder, results, task.DefaultTaskIDPrefix)
+}
+
+// AssignGlobalIDsWithPrefix is AssignGlobalIDs with a configurable task ID
+// prefix, e.g. "API" produces API-001, API-002, ... An empty prefix falls back
+// to task.DefaultTaskIDPrefix.
+func AssignGlobalIDsWithPrefix(
+	epicOrder []string,
+	results map[string]*EpicTaskResult,
+	prefix string,
 ) ([]MergedTask, IDMapping) {
 	// Count total tasks to determine zero-padding width.
 	total := 0
 	for _, etr := range results {
 		total += len(etr.Tasks)
 	}
-
-	format := "T-%03d"
-	if total >= 1000 {
-		format = "T-%04d"
-	}
+	digits := globalIDDigits(total)
 
 	// Build the final ordered list of epic IDs to process.
 	// Start with the topologically sorted order, skipping epics not in results.
@@ -266,7 +277,7 @@ func AssignGlobalIDs(
 				// lenient here since validation should have caught them earlier.
 				continue
 			}
-			globalID := fmt.Sprintf(format, counter)
+			globalID := formatGlobalID(prefix, counter, digits)
 			counter++
 
 			mapping[task.TempID] = globalID
@@ -498,7 +509,7 @@ func findDuplicateGroups(tasks []MergedTask) []DedupGroup {
 		}
 		// Sort by GlobalID so the keeper (lowest ID) is first.
 		sort.Slice(group, func(i, j int) bool {
-			return group[i].GlobalID < group[j].GlobalID
+			return lessGlobalID(group[i].GlobalID, group[j].GlobalID)
 		})
 		groups = append(groups, DedupGroup{
 			NormalizedTitle: norm,
@@ -945,3 +956,24 @@ func DeduplicateTasks(tasks []MergedTask) ([]MergedTask, *DedupReport) {
 	report.FinalCount = len(out)
 	return out, report
 }
+
+// globalIDDigits returns the zero-padding width for global IDs whose highest
+// number is maxNumber: 3 digits below 1000, 4 digits from 1000 upwards.
+func globalIDDigits(maxNumber int) int {
+	if maxNumber >= 1000 {
+		return 4
+	}
+	return task.MinTaskIDDigits
+}
+
+// formatGlobalID formats n as a global task ID with the given prefix and
+// zero-padding width.
+func formatGlobalID(prefix string, n, digits int) string {
+	return task.FormatTaskID(prefix, n, digits)
+}
+
+// lessGlobalID orders global IDs by prefix and then numerically, so that
+// "T-999" sorts before "T-1000".
+func lessGlobalID(a, b string) bool {
+	return task.CompareTaskIDs(a, b) < 0
+}
@@ -191,6 +191,31 @@ func TestAssignGlobalIDs_SingleEpic_ThreeTasks(t *testing.T) {
 	assert.Equal(t, "T-003", mapping["E001-T03"])
 }
 
+func TestAssignGlobalIDsWithPrefix_CustomPrefix(t *testing.T) {
+	t.Parallel()
+
+	order := []string{"E-001"}
+	results := map[string]*EpicTaskResult{
+		"E-001": {
+			EpicID: "E-001",
+			Tasks: []TaskDef{
+				{TempID: "E001-T01", Title: "First task"},
+				{TempID: "E001-T02", Title: "Second task"},
+			},
+		},
+	}
+
+	merged, mapping := AssignGlobalIDsWithPrefix(order, results, "API")
+
+	require.Len(t, merged, 2)
+	assert.Equal(t, "API-001", merged[0].GlobalID)
+	assert.Equal(t, "API-002", merged[1].GlobalID)
+	assert.Equal(t, "API-002", mapping["E001-T02"])
+
+	fallback, _ := AssignGlobalIDsWithPrefix(order, results, "")
+	assert.Equal(t, "T-001", fallback[0].GlobalID)
+}
+
 func TestAssignGlobalIDs_LinearChain_EpicOrder(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,350 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AnalyzerFormat is the output format of a static-analysis command.
+type AnalyzerFormat string
+
+const (
+	// AnalyzerFormatSARIF reads a SARIF 2.1.0 log from stdout.
+	AnalyzerFormatSARIF AnalyzerFormat = "sarif"
+
+	// AnalyzerFormatGolangci reads golangci-lint's JSON report from stdout.
+	AnalyzerFormatGolangci AnalyzerFormat = "golangci-json"
+
+	// AnalyzerFormatLine matches each line of stdout and stderr against a
+	// regular expression, "file:line:col: message" by default, as printed by
+	// go vet and staticcheck.
+	AnalyzerFormatLine AnalyzerFormat = "line"
+)
+
+// validAnalyzerFormats is the set of all known AnalyzerFormat values.
+var validAnalyzerFormats = map[AnalyzerFormat]bool{
+	AnalyzerFormatSARIF:    true,
+	AnalyzerFormatGolangci: true,
+	AnalyzerFormatLine:     true,
+}
+
+// DefaultAnalyzerPattern is the line pattern used when an analyzer with the
+// line format has none. It matches "file:line:col: message" and
+// "file:line: message".
+const DefaultAnalyzerPattern = `^(?P<file>[^:\s][^:]*):(?P<line>\d+)(?::(?P<col>\d+))?:\s*(?P<message>.+)$`
+
+// analyzerAgentPrefix prefixes the analyzer name in the Agent of its results
+// and findings, so "vet" reports as "analyzer:vet".
+const analyzerAgentPrefix = "analyzer:"
+
+// analyzerTimeout bounds a single analyzer command.
+const analyzerTimeout = 10 * time.Minute
+
+// Analyzer is a local static-analysis command whose output is converted into
+// review findings and consolidated with the agents' findings.
+type Analyzer struct {
+	// Name identifies the analyzer; its findings are attributed to the agent
+	// "analyzer:<name>".
+	Name string
+
+	// Command is the shell command to run (e.g. "go vet ./...").
+	Command string
+
+	// Format is the format of the command's output.
+	Format AnalyzerFormat
+
+	// Pattern is the regular expression for AnalyzerFormatLine, with the
+	// named groups "file", "line", and "message" and optionally "col",
+	// "severity", and "rule". Empty uses DefaultAnalyzerPattern.
+	Pattern string
+
+	// Severity is given to findings the tool does not rate. Empty means
+	// medium.
+	Severity Severity
+
+	// Category overrides the finding category. Empty uses the rule or
+	// linter that reported the finding, or else the analyzer's name.
+	Category string
+
+	// Context adds the analyzer's findings to the review prompts so the
+	// agents can build on them instead of reporting them again.
+	Context bool
+}
+
+// AgentName returns the agent name the analyzer's findings are attributed to.
+func (a Analyzer) AgentName() string {
+	return analyzerAgentPrefix + a.Name
+}
+
+// Validate checks that the analyzer has a name, a command, a known format,
+// and a usable pattern and severity.
+func (a Analyzer) Validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("analyzer name is required")
+	}
+	if strings.TrimSpace(a.Command) == "" {
+		return fmt.Errorf("analyzer %q: command is required", a.Name)
+	}
+	if !validAnalyzerFormats[a.Format] {
+		return fmt.Errorf("analyzer %q: unknown format %q: must be one of sarif, golangci-json, line", a.Name, a.Format)
+	}
+	if a.Severity != "" && !validSeverities[a.Severity] {
+		return fmt.Errorf("analyzer %q: invalid severity %q: must be one of info, low, medium, high, critical", a.Name, a.Severity)
+	}
+	if a.Format == AnalyzerFormatLine {
+		if _, err := a.linePattern(); err != nil {
+			return fmt.Errorf("analyzer %q: %w", a.Name, err)
+		}
+	}
+	return nil
+}
+
+// Run executes the analyzer's command in workDir (empty for the process
+// working directory) and converts its output to findings. Linters exit
+// non-zero when they report issues, so a failing command is only an error
+// when it produced nothing to parse.
+func (a Analyzer) Run(ctx context.Context, workDir string) ([]Finding, error) {
+	execCtx, cancel := context.WithTimeout(ctx, analyzerTimeout)
+	defer cancel()
+
+	cmd := shellCommand(execCtx, a.Command)
+	cmd.Dir = workDir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	if err := execCtx.Err(); err != nil {
+		return nil, fmt.Errorf("review: analyzer %s: %w", a.Name, err)
+	}
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		return nil, fmt.Errorf("review: analyzer %s: running %q: %w", a.Name, a.Command, runErr)
+	}
+
+	output := stdout.String()
+	if a.Format == AnalyzerFormatLine {
+		// go vet and friends print their diagnostics on stderr.
+		output += "\n" + stderr.String()
+	}
+
+	root := workDir
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	findings, err := a.Parse(output, root)
+	if err != nil {
+		return nil, err
+	}
+	if runErr != nil && len(findings) == 0 {
+		return nil, fmt.Errorf("review: analyzer %s: %q exited with code %d: %s",
+			a.Name, a.Command, exitErr.ExitCode(), strings.TrimSpace(stderr.String()))
+	}
+	return findings, nil
+}
+
+// Parse converts analyzer output in the analyzer's format to findings.
+// Absolute file paths are made relative to root.
+func (a Analyzer) Parse(output, root string) ([]Finding, error) {
+	var findings []Finding
+	var err error
+	switch a.Format {
+	case AnalyzerFormatSARIF:
+		findings, err = a.parseSARIF(output, root)
+	case AnalyzerFormatGolangci:
+		findings, err = a.parseGolangci(output, root)
+	case AnalyzerFormatLine:
+		findings, err = a.parseLines(output, root)
+	default:
+		err = fmt.Errorf("unknown format %q", a.Format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("review: analyzer %s: parsing %s output: %w", a.Name, a.Format, err)
+	}
+	return findings, nil
+}
+
+// parseSARIF converts the results of every run in a SARIF log.
+func (a Analyzer) parseSARIF(output, root string) ([]Finding, error) {
+	if strings.TrimSpace(output) == "" {
+		return nil, nil
+	}
+	var doc sarifLog
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, run := range doc.Runs {
+		for _, res := range run.Results {
+			var file string
+			var line int
+			if len(res.Locations) > 0 {
+				loc := res.Locations[0].PhysicalLocation
+				file = loc.ArtifactLocation.URI
+				if loc.Region != nil {
+					line = loc.Region.StartLine
+				}
+			}
+			findings = append(findings, a.newFinding(file, line, res.Message.Text, res.Level, res.RuleID, root))
+		}
+	}
+	return findings, nil
+}
+
+// golangciReport is the part of golangci-lint's JSON report Raven reads.
+type golangciReport struct {
+	Issues []struct {
+		FromLinter string `json:"FromLinter"`
+		Text       string `json:"Text"`
+		Severity   string `json:"Severity"`
+		Pos        struct {
+			Filename string `json:"Filename"`
+			Line     int    `json:"Line"`
+		} `json:"Pos"`
+	} `json:"Issues"`
+}
+
+// parseGolangci converts the issues of a golangci-lint JSON report.
+func (a Analyzer) parseGolangci(output, root string) ([]Finding, error) {
+	if strings.TrimSpace(output) == "" {
+		return nil, nil
+	}
+	var report golangciReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		return nil, err
+	}
+	findings := make([]Finding, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		findings = append(findings, a.newFinding(issue.Pos.Filename, issue.Pos.Line, issue.Text, issue.Severity, issue.FromLinter, root))
+	}
+	return findings, nil
+}
+
+// parseLines converts every output line matching the analyzer's pattern.
+// Lines that do not match, such as go vet's "# package" headers, are skipped.
+func (a Analyzer) parseLines(output, root string) ([]Finding, error) {
+	re, err := a.linePattern()
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, line := range strings.Split(output, "\n") {
+		m := re.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+		group := func(name string) string {
+			if i := re.SubexpIndex(name); i >= 0 {
+				return strings.TrimSpace(m[i])
+			}
+			return ""
+		}
+		lineNo, _ := strconv.Atoi(group("line"))
+		findings = append(findings, a.newFinding(group("file"), lineNo, group("message"), group("severity"), group("rule"), root))
+	}
+	return findings, nil
+}
+
+// linePattern compiles the analyzer's line pattern and checks that it has the
+// required named groups.
+func (a Analyzer) linePattern() (*regexp.Regexp, error) {
+	pattern := a.Pattern
+	if pattern == "" {
+		pattern = DefaultAnalyzerPattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	for _, name := range []string{"file", "line", "message"} {
+		if re.SubexpIndex(name) < 0 {
+			return nil, fmt.Errorf("pattern %q has no (?P<%s>...) group", pattern, name)
+		}
+	}
+	return re, nil
+}
+
+// newFinding builds a finding from one reported issue. level is the tool's
+// severity and rule the rule or linter that reported it; both may be empty.
+func (a Analyzer) newFinding(file string, line int, message, level, rule, root string) Finding {
+	category := a.Category
+	if category == "" {
+		category = rule
+	}
+	if category == "" {
+		category = a.Name
+	}
+	description := strings.TrimSpace(message)
+	if rule != "" && rule != category {
+		description = fmt.Sprintf("%s (%s)", description, rule)
+	}
+	return Finding{
+		Severity:    a.severity(level),
+		Category:    category,
+		File:        analyzerPath(file, root),
+		Line:        line,
+		Description: description,
+	}
+}
+
+// severity maps a tool's severity to a Severity: Raven's own severities are
+// kept, SARIF levels map error to high, warning to medium, note to low, and
+// none to info, and anything else gets the analyzer's default severity.
+func (a Analyzer) severity(level string) Severity {
+	level = strings.ToLower(strings.TrimSpace(level))
+	if s := Severity(level); validSeverities[s] {
+		return s
+	}
+	switch level {
+	case "error":
+		return SeverityHigh
+	case "warning", "warn":
+		return SeverityMedium
+	case "note":
+		return SeverityLow
+	case "none":
+		return SeverityInfo
+	}
+	if a.Severity != "" {
+		return a.Severity
+	}
+	return SeverityMedium
+}
+
+// analyzerPath converts a reported file, possibly a file:// URI or an
+// absolute path, to a slash-separated path relative to root.
+func analyzerPath(file, root string) string {
+	if strings.HasPrefix(file, "file://") {
+		if u, err := url.Parse(file); err == nil {
+			file = filepath.FromSlash(u.Path)
+		}
+	}
+	if filepath.IsAbs(file) && root != "" {
+		if rel, err := filepath.Rel(root, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	return normalizeFindingPath(file)
+}
+
+// analyzerVerdict derives a verdict for an analyzer's findings: BLOCKING for
+// a critical finding, CHANGES_NEEDED for any finding above info, and
+// APPROVED otherwise.
+func analyzerVerdict(findings []Finding) Verdict {
+	verdicts := make([]Verdict, 0, len(findings))
+	for _, f := range findings {
+		verdicts = append(verdicts, severityVerdict(f.Severity))
+	}
+	return AggregateVerdicts(verdicts)
+}
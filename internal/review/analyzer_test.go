@@ -0,0 +1,206 @@
+package review
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeAnalyzerOutput writes content to a file in a temporary directory and
+// returns a command that prints it.
+func writeAnalyzerOutput(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "out.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return "cat '" + path + "'"
+}
+
+func TestAnalyzer_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		analyzer Analyzer
+		wantErr  string
+	}{
+		{name: "valid line", analyzer: Analyzer{Name: "vet", Command: "go vet ./...", Format: AnalyzerFormatLine}},
+		{name: "valid sarif", analyzer: Analyzer{Name: "sc", Command: "staticcheck -f sarif ./...", Format: AnalyzerFormatSARIF, Severity: SeverityLow}},
+		{name: "missing name", analyzer: Analyzer{Command: "x", Format: AnalyzerFormatLine}, wantErr: "name is required"},
+		{name: "missing command", analyzer: Analyzer{Name: "vet", Format: AnalyzerFormatLine}, wantErr: "command is required"},
+		{name: "unknown format", analyzer: Analyzer{Name: "vet", Command: "x", Format: "xml"}, wantErr: `unknown format "xml"`},
+		{name: "bad severity", analyzer: Analyzer{Name: "vet", Command: "x", Format: AnalyzerFormatLine, Severity: "urgent"}, wantErr: `invalid severity "urgent"`},
+		{name: "bad pattern", analyzer: Analyzer{Name: "vet", Command: "x", Format: AnalyzerFormatLine, Pattern: "("}, wantErr: "invalid pattern"},
+		{name: "pattern without groups", analyzer: Analyzer{Name: "vet", Command: "x", Format: AnalyzerFormatLine, Pattern: `^(.*):(\d+): (.*)$`}, wantErr: "no (?P<file>...) group"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.analyzer.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAnalyzer_ParseLine(t *testing.T) {
+	t.Parallel()
+
+	output := "# github.com/example/pkg\n" +
+		"./internal/pkg/a.go:12:2: printf: Sprintf format %d has arg of wrong type\n" +
+		"/repo/internal/pkg/b.go:7: unreachable code\n" +
+		"vet: exit status 1\n"
+	a := Analyzer{Name: "vet", Format: AnalyzerFormatLine}
+
+	findings, err := a.Parse(output, "/repo")
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	assert.Equal(t, Finding{
+		Severity:    SeverityMedium,
+		Category:    "vet",
+		File:        "internal/pkg/a.go",
+		Line:        12,
+		Description: "printf: Sprintf format %d has arg of wrong type",
+	}, findings[0])
+	assert.Equal(t, "internal/pkg/b.go", findings[1].File)
+	assert.Equal(t, 7, findings[1].Line)
+}
+
+func TestAnalyzer_ParseLine_CustomPattern(t *testing.T) {
+	t.Parallel()
+
+	a := Analyzer{
+		Name:    "custom",
+		Format:  AnalyzerFormatLine,
+		Pattern: `^(?P<severity>\w+) (?P<file>[^:]+):(?P<line>\d+) \[(?P<rule>[\w-]+)\] (?P<message>.+)$`,
+	}
+	findings, err := a.Parse("error main.go:3 [no-shadow] x shadows x\n", "")
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, SeverityHigh, findings[0].Severity)
+	assert.Equal(t, "no-shadow", findings[0].Category)
+	assert.Equal(t, "x shadows x", findings[0].Description)
+}
+
+func TestAnalyzer_ParseSARIF(t *testing.T) {
+	t.Parallel()
+
+	output := `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "staticcheck"}},
+    "results": [
+      {"ruleId": "SA4006", "level": "error", "message": {"text": "value never used"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "file:///repo/cmd/main.go"}, "region": {"startLine": 9}}}]},
+      {"ruleId": "ST1000", "message": {"text": "missing package comment"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "cmd/doc.go"}}}]}
+    ]
+  }]
+}`
+	a := Analyzer{Name: "staticcheck", Format: AnalyzerFormatSARIF, Severity: SeverityLow}
+
+	findings, err := a.Parse(output, "/repo")
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	assert.Equal(t, Finding{
+		Severity:    SeverityHigh,
+		Category:    "SA4006",
+		File:        "cmd/main.go",
+		Line:        9,
+		Description: "value never used",
+	}, findings[0])
+	// No level: the analyzer's default severity applies.
+	assert.Equal(t, SeverityLow, findings[1].Severity)
+	assert.Equal(t, "cmd/doc.go", findings[1].File)
+	assert.Zero(t, findings[1].Line)
+}
+
+func TestAnalyzer_ParseGolangci(t *testing.T) {
+	t.Parallel()
+
+	output := `{"Issues":[
+  {"FromLinter":"errcheck","Text":"Error return value is not checked","Severity":"","Pos":{"Filename":"internal/x.go","Line":4,"Column":2}},
+  {"FromLinter":"gosec","Text":"G401: weak hash","Severity":"warning","Pos":{"Filename":"internal/y.go","Line":8}}
+]}`
+	a := Analyzer{Name: "lint", Format: AnalyzerFormatGolangci, Category: "lint"}
+
+	findings, err := a.Parse(output, "")
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	assert.Equal(t, Finding{
+		Severity:    SeverityMedium,
+		Category:    "lint",
+		File:        "internal/x.go",
+		Line:        4,
+		Description: "Error return value is not checked (errcheck)",
+	}, findings[0])
+	assert.Equal(t, SeverityMedium, findings[1].Severity)
+	assert.Equal(t, "G401: weak hash (gosec)", findings[1].Description)
+}
+
+func TestAnalyzer_Parse_InvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []AnalyzerFormat{AnalyzerFormatSARIF, AnalyzerFormatGolangci} {
+		a := Analyzer{Name: "x", Format: format}
+		_, err := a.Parse("not json", "")
+		require.Error(t, err, format)
+		assert.Contains(t, err.Error(), "parsing "+string(format)+" output")
+
+		findings, err := a.Parse("  \n", "")
+		require.NoError(t, err, format)
+		assert.Empty(t, findings)
+	}
+}
+
+func TestAnalyzer_Severity(t *testing.T) {
+	t.Parallel()
+
+	a := Analyzer{Severity: SeverityInfo}
+	assert.Equal(t, SeverityCritical, a.severity("critical"))
+	assert.Equal(t, SeverityHigh, a.severity("Error"))
+	assert.Equal(t, SeverityMedium, a.severity("warning"))
+	assert.Equal(t, SeverityLow, a.severity("note"))
+	assert.Equal(t, SeverityInfo, a.severity("none"))
+	assert.Equal(t, SeverityInfo, a.severity(""))
+	assert.Equal(t, SeverityMedium, Analyzer{}.severity("unknown"))
+}
+
+func TestAnalyzer_Run(t *testing.T) {
+	t.Parallel()
+
+	// Linters exit non-zero when they report issues.
+	cmd := writeAnalyzerOutput(t, "main.go:3:1: bad thing\n")
+	a := Analyzer{Name: "vet", Command: cmd + " >&2; exit 1", Format: AnalyzerFormatLine}
+
+	findings, err := a.Run(context.Background(), "")
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "main.go", findings[0].File)
+	assert.Equal(t, "analyzer:vet", a.AgentName())
+}
+
+func TestAnalyzer_Run_FailureWithoutFindings(t *testing.T) {
+	t.Parallel()
+
+	a := Analyzer{Name: "vet", Command: "echo 'package not found' >&2; exit 2", Format: AnalyzerFormatLine}
+	_, err := a.Run(context.Background(), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exited with code 2: package not found")
+}
+
+func TestAnalyzerVerdict(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, VerdictApproved, analyzerVerdict(nil))
+	assert.Equal(t, VerdictApproved, analyzerVerdict([]Finding{{Severity: SeverityInfo}}))
+	assert.Equal(t, VerdictChangesNeeded, analyzerVerdict([]Finding{{Severity: SeverityInfo}, {Severity: SeverityLow}}))
+	assert.Equal(t, VerdictBlocking, analyzerVerdict([]Finding{{Severity: SeverityLow}, {Severity: SeverityCritical}}))
+}
@@ -0,0 +1,325 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DiffHunk is a single "@@" hunk of a unified diff.
+type DiffHunk struct {
+	// OldStart and OldLines give the line range the hunk replaces in the base
+	// version of the file.
+	OldStart int
+	OldLines int
+
+	// NewStart and NewLines give the line range the hunk covers in the new
+	// version of the file. NewLines is 0 for hunks that only delete lines.
+	NewStart int
+	NewLines int
+
+	// Lines holds the hunk body lines including their " ", "+" or "-" prefix.
+	Lines []string
+}
+
+// NewEnd returns the last new-file line covered by the hunk, or NewStart-1
+// when the hunk covers no new lines.
+func (h DiffHunk) NewEnd() int {
+	return h.NewStart + h.NewLines - 1
+}
+
+// ContainsNewLine reports whether line falls in the hunk's new-file range.
+func (h DiffHunk) ContainsNewLine(line int) bool {
+	return h.NewLines > 0 && line >= h.NewStart && line <= h.NewEnd()
+}
+
+// newSide returns the hunk's context and added lines keyed by their line
+// number in the new version of the file.
+func (h DiffHunk) newSide() map[int]string {
+	out := make(map[int]string, h.NewLines)
+	n := h.NewStart
+	for _, l := range h.Lines {
+		if l == "" {
+			// Some tools strip the leading space of empty context lines.
+			out[n] = ""
+			n++
+			continue
+		}
+		switch l[0] {
+		case ' ', '+':
+			out[n] = l[1:]
+			n++
+		}
+	}
+	return out
+}
+
+// hunkHeaderRe matches a unified diff hunk header such as
+// "@@ -10,7 +10,8 @@ func main() {". Omitted counts default to 1.
+var hunkHeaderRe = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseUnifiedDiff splits a unified diff (as produced by "git diff") into
+// hunks per file. Files are keyed by their new path; deleted files are keyed
+// by their old path. Binary files and files without hunks are omitted.
+func ParseUnifiedDiff(diff string) map[string][]DiffHunk {
+	result := make(map[string][]DiffHunk)
+
+	var oldPath, path string
+	var cur *DiffHunk
+	flush := func() {
+		if cur != nil && path != "" {
+			result[path] = append(result[path], *cur)
+		}
+		cur = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(diff, "\r\n", "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			oldPath, path = "", ""
+		case cur == nil && strings.HasPrefix(line, "--- "):
+			oldPath = diffPath(strings.TrimPrefix(line, "--- "))
+		case cur == nil && strings.HasPrefix(line, "+++ "):
+			path = diffPath(strings.TrimPrefix(line, "+++ "))
+			if path == "" {
+				path = oldPath
+			}
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			m := hunkHeaderRe.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			cur = &DiffHunk{
+				OldStart: atoiDefault(m[1], 0),
+				OldLines: atoiDefault(m[2], 1),
+				NewStart: atoiDefault(m[3], 0),
+				NewLines: atoiDefault(m[4], 1),
+			}
+		case cur != nil:
+			if strings.HasPrefix(line, `\`) {
+				// "\ No newline at end of file"
+				continue
+			}
+			cur.Lines = append(cur.Lines, line)
+		}
+	}
+	flush()
+	return result
+}
+
+// diffPath strips the "a/" or "b/" prefix from a "---"/"+++" header path and
+// returns "" for /dev/null.
+func diffPath(p string) string {
+	p = strings.TrimSpace(p)
+	if i := strings.IndexByte(p, '\t'); i >= 0 {
+		p = p[:i]
+	}
+	if p == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(p, "a/") || strings.HasPrefix(p, "b/") {
+		return p[2:]
+	}
+	return p
+}
+
+// atoiDefault parses s, returning def when s is empty or not a number.
+func atoiDefault(s string, def int) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// FindingLocation classifies where a finding's file and line point relative
+// to the reviewed diff.
+type FindingLocation string
+
+const (
+	// LocationInDiff marks a finding on a line covered by a diff hunk.
+	LocationInDiff FindingLocation = "in_diff"
+
+	// LocationInContext marks a finding on an existing line of a changed file
+	// that lies outside every hunk, or a file-level finding (line 0).
+	LocationInContext FindingLocation = "in_context"
+
+	// LocationInvalid marks a finding on a file that is not part of the diff,
+	// on a deleted file, or on a line that does not exist.
+	LocationInvalid FindingLocation = "invalid"
+)
+
+// InvalidFindingMode controls what consolidation does with findings
+// classified as LocationInvalid.
+type InvalidFindingMode string
+
+const (
+	// InvalidFindingsDrop removes invalid findings from the consolidated review.
+	InvalidFindingsDrop InvalidFindingMode = "drop"
+
+	// InvalidFindingsDowngrade keeps invalid findings at SeverityInfo.
+	InvalidFindingsDowngrade InvalidFindingMode = "downgrade"
+)
+
+// snippetRadius is the number of lines shown on each side of a finding's
+// line in its snippet.
+const snippetRadius = 2
+
+// FindingValidator classifies findings against the files and hunks of a diff
+// and extracts a short excerpt around each valid finding's line.
+type FindingValidator struct {
+	files   map[string]ChangedFile
+	workDir string
+	// readFile reads a file of the new tree: os.ReadFile relative to workDir,
+	// or "git show" of the diff target's revision. It is replaced in tests.
+	readFile func(path string) ([]byte, error)
+	// lines caches the split contents of files read for validation; a nil
+	// entry records a file that could not be read.
+	lines map[string][]string
+}
+
+// NewFindingValidator creates a FindingValidator for diff. Lines outside the
+// hunks are checked against the files in workDir (the current directory when
+// empty). When the diff's target reviews a revision other than the working
+// tree, such as a range or a single commit, the files are read from that
+// revision of the repository in workDir instead.
+func NewFindingValidator(diff *DiffResult, workDir string) *FindingValidator {
+	v := &FindingValidator{
+		files:   make(map[string]ChangedFile),
+		workDir: workDir,
+		lines:   make(map[string][]string),
+	}
+	v.readFile = func(path string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(v.workDir, filepath.FromSlash(path)))
+	}
+	if diff == nil {
+		return v
+	}
+	if rev := diff.Target.Revision(); rev != "" {
+		runner := NewCLIRunner(workDir)
+		v.readFile = func(path string) ([]byte, error) {
+			_, out, _, err := runner.Run(context.Background(), "git", "show", rev+":"+path)
+			if err != nil {
+				return nil, fmt.Errorf("review: git show %s:%s: %w", rev, path, err)
+			}
+			return []byte(out), nil
+		}
+	}
+	for _, f := range diff.Files {
+		v.files[f.Path] = f
+	}
+	return v
+}
+
+// Classify returns the location of f and, for valid findings, a snippet of
+// the lines around f.Line, with the finding's line marked by ">".
+func (v *FindingValidator) Classify(f *Finding) (FindingLocation, string) {
+	path := normalizeFindingPath(f.File)
+	cf, ok := v.files[path]
+	if !ok || cf.ChangeType == ChangeDeleted || f.Line < 0 {
+		return LocationInvalid, ""
+	}
+	if f.Line == 0 {
+		return LocationInContext, ""
+	}
+
+	if h, ok := hunkAt(cf, f.Line); ok {
+		return LocationInDiff, formatSnippet(h.newSide(), f.Line)
+	}
+
+	lines := v.fileLines(path)
+	if lines == nil {
+		// The file cannot be read, so the line cannot be disproved.
+		return LocationInContext, ""
+	}
+	if f.Line > len(lines) {
+		return LocationInvalid, ""
+	}
+	byNumber := make(map[int]string, 2*snippetRadius+1)
+	for n := f.Line - snippetRadius; n <= f.Line+snippetRadius; n++ {
+		if n >= 1 && n <= len(lines) {
+			byNumber[n] = lines[n-1]
+		}
+	}
+	return LocationInContext, formatSnippet(byNumber, f.Line)
+}
+
+// InDiff reports whether f points at a line covered by one of the diff's
+// hunks, i.e. whether Classify would return LocationInDiff.
+func (v *FindingValidator) InDiff(f *Finding) bool {
+	cf, ok := v.files[normalizeFindingPath(f.File)]
+	if !ok || cf.ChangeType == ChangeDeleted || f.Line <= 0 {
+		return false
+	}
+	_, ok = hunkAt(cf, f.Line)
+	return ok
+}
+
+// hunkAt returns the hunk of cf whose new-file range contains line.
+func hunkAt(cf ChangedFile, line int) (DiffHunk, bool) {
+	for _, h := range cf.Hunks {
+		if h.ContainsNewLine(line) {
+			return h, true
+		}
+	}
+	return DiffHunk{}, false
+}
+
+// fileLines returns the lines of path, reading it on first use. Returns nil
+// when the file cannot be read.
+func (v *FindingValidator) fileLines(path string) []string {
+	if lines, ok := v.lines[path]; ok {
+		return lines
+	}
+	data, err := v.readFile(path)
+	if err != nil {
+		v.lines[path] = nil
+		return nil
+	}
+	text := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	lines := strings.Split(text, "\n")
+	if text == "" {
+		lines = []string{}
+	}
+	v.lines[path] = lines
+	return lines
+}
+
+// normalizeFindingPath maps the path an agent reported to the repository
+// relative form used by the diff: "./x", "a/x" and "b/x" become "x".
+func normalizeFindingPath(p string) string {
+	p = filepath.ToSlash(strings.TrimSpace(p))
+	p = strings.TrimPrefix(p, "./")
+	if strings.HasPrefix(p, "a/") || strings.HasPrefix(p, "b/") {
+		p = p[2:]
+	}
+	return p
+}
+
+// formatSnippet renders the lines within snippetRadius of target, numbered,
+// with the target line marked by ">". Returns "" when target is not present.
+func formatSnippet(byNumber map[int]string, target int) string {
+	if _, ok := byNumber[target]; !ok {
+		return ""
+	}
+	width := len(strconv.Itoa(target + snippetRadius))
+	var sb strings.Builder
+	for n := target - snippetRadius; n <= target+snippetRadius; n++ {
+		text, ok := byNumber[n]
+		if !ok {
+			continue
+		}
+		marker := " "
+		if n == target {
+			marker = ">"
+		}
+		fmt.Fprintf(&sb, "%s %*d | %s\n", marker, width, n, text)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
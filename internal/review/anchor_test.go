@@ -0,0 +1,197 @@
+package review
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/git"
+)
+
+const anchorTestDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -10,4 +10,5 @@ func main() {
+ 	a := 1
+-	b := 2
++	b := 3
++	c := 4
+ 	fmt.Println(a, b)
+ }
+@@ -30 +31,2 @@
+-x
++y
++z
+\ No newline at end of file
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package old
+-
+diff --git a/img.png b/img.png
+Binary files a/img.png and b/img.png differ
+`
+
+func TestParseUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	hunks := ParseUnifiedDiff(anchorTestDiff)
+	require.Len(t, hunks, 2)
+
+	main := hunks["main.go"]
+	require.Len(t, main, 2)
+	assert.Equal(t, 10, main[0].OldStart)
+	assert.Equal(t, 4, main[0].OldLines)
+	assert.Equal(t, 10, main[0].NewStart)
+	assert.Equal(t, 5, main[0].NewLines)
+	assert.Len(t, main[0].Lines, 6)
+	assert.Equal(t, 30, main[1].OldStart)
+	assert.Equal(t, 1, main[1].OldLines)
+	assert.Equal(t, 31, main[1].NewStart)
+	assert.Equal(t, 2, main[1].NewLines)
+	assert.Equal(t, []string{"-x", "+y", "+z"}, main[1].Lines)
+
+	old := hunks["old.go"]
+	require.Len(t, old, 1)
+	assert.Equal(t, 0, old[0].NewLines)
+	assert.False(t, old[0].ContainsNewLine(0))
+}
+
+func TestDiffHunk_ContainsNewLine(t *testing.T) {
+	t.Parallel()
+
+	h := DiffHunk{NewStart: 10, NewLines: 5}
+	assert.False(t, h.ContainsNewLine(9))
+	assert.True(t, h.ContainsNewLine(10))
+	assert.True(t, h.ContainsNewLine(14))
+	assert.False(t, h.ContainsNewLine(15))
+	assert.Equal(t, 14, h.NewEnd())
+}
+
+// anchorTestWorkDir returns a temp dir holding a 40-line main.go, the new
+// version of the file changed by anchorTestDiff.
+func anchorTestWorkDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(strings.Repeat("line\n", 40)), 0o644))
+	return dir
+}
+
+// anchorTestDiffResult returns the DiffResult for anchorTestDiff plus an
+// added file that does not exist on disk.
+func anchorTestDiffResult() *DiffResult {
+	hunks := ParseUnifiedDiff(anchorTestDiff)
+	return &DiffResult{
+		FullDiff: anchorTestDiff,
+		Files: []ChangedFile{
+			{Path: "main.go", ChangeType: ChangeModified, Hunks: hunks["main.go"]},
+			{Path: "old.go", ChangeType: ChangeDeleted, Hunks: hunks["old.go"]},
+			{Path: "gone.go", ChangeType: ChangeAdded},
+		},
+	}
+}
+
+// anchorTestValidator returns a validator for anchorTestDiffResult.
+func anchorTestValidator(t *testing.T) *FindingValidator {
+	t.Helper()
+	return NewFindingValidator(anchorTestDiffResult(), anchorTestWorkDir(t))
+}
+
+func TestFindingValidator_Classify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		file string
+		line int
+		want FindingLocation
+	}{
+		{name: "added line", file: "main.go", line: 12, want: LocationInDiff},
+		{name: "hunk context line", file: "main.go", line: 10, want: LocationInDiff},
+		{name: "prefixed path", file: "./main.go", line: 32, want: LocationInDiff},
+		{name: "outside hunks", file: "main.go", line: 3, want: LocationInContext},
+		{name: "file level", file: "main.go", line: 0, want: LocationInContext},
+		{name: "past end of file", file: "main.go", line: 41, want: LocationInvalid},
+		{name: "file not in diff", file: "other.go", line: 1, want: LocationInvalid},
+		{name: "deleted file", file: "old.go", line: 1, want: LocationInvalid},
+		{name: "negative line", file: "main.go", line: -1, want: LocationInvalid},
+		{name: "unreadable file", file: "gone.go", line: 500, want: LocationInContext},
+	}
+
+	v := anchorTestValidator(t)
+	for _, tt := range tests {
+		got, _ := v.Classify(&Finding{File: tt.file, Line: tt.line})
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func TestFindingValidator_Snippet(t *testing.T) {
+	t.Parallel()
+
+	v := anchorTestValidator(t)
+
+	_, snippet := v.Classify(&Finding{File: "main.go", Line: 12})
+	assert.Equal(t, "  10 | \ta := 1\n  11 | \tb := 3\n> 12 | \tc := 4\n  13 | \tfmt.Println(a, b)\n  14 | }", snippet)
+
+	_, snippet = v.Classify(&Finding{File: "main.go", Line: 1})
+	assert.Equal(t, "> 1 | line\n  2 | line\n  3 | line", snippet)
+}
+
+func TestFindingValidator_ReadsTargetRevision(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	integMustRun(t, dir, "git", "init", "-b", "main")
+	integMustRun(t, dir, "git", "config", "user.email", "test@example.com")
+	integMustRun(t, dir, "git", "config", "user.name", "Test")
+	integWriteFile(t, dir, "main.go", strings.Repeat("line\n", 10))
+	integMustRun(t, dir, "git", "add", ".")
+	integMustRun(t, dir, "git", "commit", "-m", "initial")
+
+	// The working tree no longer matches the reviewed commit: main.go is
+	// down to two lines.
+	integWriteFile(t, dir, "main.go", "line\nline\n")
+
+	hunks := ParseUnifiedDiff("diff --git a/main.go b/main.go\n--- /dev/null\n+++ b/main.go\n@@ -0,0 +1,2 @@\n+line\n+line\n")
+	diff := &DiffResult{
+		Files:  []ChangedFile{{Path: "main.go", ChangeType: ChangeModified, Hunks: hunks["main.go"]}},
+		Target: git.DiffTarget{Kind: git.TargetCommit, Ref: "HEAD"},
+	}
+
+	v := NewFindingValidator(diff, dir)
+	loc, snippet := v.Classify(&Finding{File: "main.go", Line: 8})
+	assert.Equal(t, LocationInContext, loc)
+	assert.Contains(t, snippet, ">  8 | line")
+	loc, _ = v.Classify(&Finding{File: "main.go", Line: 11})
+	assert.Equal(t, LocationInvalid, loc)
+
+	// A working tree review checks the files on disk.
+	diff.Target = git.DiffTarget{Kind: git.TargetWorktree}
+	loc, _ = NewFindingValidator(diff, dir).Classify(&Finding{File: "main.go", Line: 8})
+	assert.Equal(t, LocationInvalid, loc)
+}
+
+func TestFindingValidator_CachesFileReads(t *testing.T) {
+	t.Parallel()
+
+	v := anchorTestValidator(t)
+	reads := 0
+	v.readFile = func(string) ([]byte, error) {
+		reads++
+		return nil, errors.New("boom")
+	}
+	for i := 0; i < 3; i++ {
+		loc, snippet := v.Classify(&Finding{File: "main.go", Line: 3})
+		assert.Equal(t, LocationInContext, loc)
+		assert.Empty(t, snippet)
+	}
+	assert.Equal(t, 1, reads)
+}
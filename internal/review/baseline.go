@@ -0,0 +1,223 @@
+package review
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// baselineVersion is the format version written to new baseline files.
+const baselineVersion = 1
+
+// waiverMatchThreshold is the minimum DescriptionSimilarity at which a
+// finding in the same file and category as a waiver matches it even though
+// its fingerprint differs, because an agent described the same issue in
+// other words.
+const waiverMatchThreshold = 0.6
+
+// waiverLineWindow is the maximum distance in lines between a finding and a
+// waiver that it matches by description rather than by fingerprint.
+const waiverLineWindow = DefaultDedupLineWindow
+
+// FindingFingerprint returns a short, stable ID for a finding: a hash of its
+// file, normalized category, and the significant words of its description.
+// The line is left out so that the fingerprint survives code moving around
+// the finding.
+func FindingFingerprint(f *Finding) string {
+	tokens := make([]string, 0)
+	for t := range descriptionTokens(f.Description) {
+		tokens = append(tokens, t)
+	}
+	sort.Strings(tokens)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s", normalizeFindingPath(f.File), NormalizeCategory(f.Category), strings.Join(tokens, " "))
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// Waiver records a finding that was consciously accepted. Findings matching
+// an active waiver are listed as waived and do not count toward the verdict.
+type Waiver struct {
+	// Fingerprint is the FindingFingerprint of the waived finding.
+	Fingerprint string `json:"fingerprint"`
+
+	// File, Line, Category, and Description describe the waived finding, for
+	// readers of the baseline and for matching reworded reports of it.
+	File        string `json:"file"`
+	Line        int    `json:"line,omitempty"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+
+	// Reason explains why the finding was accepted.
+	Reason string `json:"reason"`
+
+	// Author is who waived the finding.
+	Author string `json:"author,omitempty"`
+
+	// CreatedAt is when the waiver was added.
+	CreatedAt time.Time `json:"created_at"`
+
+	// ExpiresAt is when the waiver stops applying. Nil never expires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// NewWaiver creates a waiver for f, created now. expiresAt may be nil.
+func NewWaiver(f *Finding, reason, author string, expiresAt *time.Time, now time.Time) Waiver {
+	fingerprint := f.Fingerprint
+	if fingerprint == "" {
+		fingerprint = FindingFingerprint(f)
+	}
+	return Waiver{
+		Fingerprint: fingerprint,
+		File:        normalizeFindingPath(f.File),
+		Line:        f.Line,
+		Category:    f.Category,
+		Description: f.Description,
+		Reason:      reason,
+		Author:      author,
+		CreatedAt:   now.UTC(),
+		ExpiresAt:   expiresAt,
+	}
+}
+
+// Expired reports whether the waiver has expired at now.
+func (w *Waiver) Expired(now time.Time) bool {
+	return w.ExpiresAt != nil && !now.Before(*w.ExpiresAt)
+}
+
+// Matches reports whether f is the waived finding: it has the waiver's
+// fingerprint, or is in the same file and category, within waiverLineWindow
+// lines of the waiver, with a similar description.
+func (w *Waiver) Matches(f *Finding) bool {
+	fingerprint := f.Fingerprint
+	if fingerprint == "" {
+		fingerprint = FindingFingerprint(f)
+	}
+	if fingerprint == w.Fingerprint {
+		return true
+	}
+	return rewordedMatch(f, &Finding{
+		File:        w.File,
+		Line:        w.Line,
+		Category:    w.Category,
+		Description: w.Description,
+	})
+}
+
+// rewordedMatch reports whether f is a rewording of known: in the same file
+// and category, within waiverLineWindow lines, and with a description at
+// least waiverMatchThreshold similar. File-level findings (line 0) only
+// match other file-level findings.
+func rewordedMatch(f, known *Finding) bool {
+	if normalizeFindingPath(f.File) != normalizeFindingPath(known.File) ||
+		NormalizeCategory(f.Category) != NormalizeCategory(known.Category) {
+		return false
+	}
+	if (f.Line == 0) != (known.Line == 0) {
+		return false
+	}
+	dist := f.Line - known.Line
+	if dist < 0 {
+		dist = -dist
+	}
+	if dist > waiverLineWindow {
+		return false
+	}
+	return DescriptionSimilarity(f.Description, known.Description) >= waiverMatchThreshold
+}
+
+// Baseline is the set of waived findings of a repository, stored as JSON
+// (by default in .raven/review-baseline.json) so that it can be committed
+// and shared.
+type Baseline struct {
+	Version int      `json:"version"`
+	Waivers []Waiver `json:"waivers"`
+}
+
+// LoadBaseline reads the baseline at path. A missing file is an empty
+// baseline.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Baseline{Version: baselineVersion}, nil
+		}
+		return nil, fmt.Errorf("review: baseline: read %q: %w", path, err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("review: baseline: parse %q: %w", path, err)
+	}
+	if b.Version == 0 {
+		b.Version = baselineVersion
+	}
+	return &b, nil
+}
+
+// Save writes the baseline to path with its waivers sorted by file, line,
+// and fingerprint. The file is written to a .tmp path and then renamed.
+func (b *Baseline) Save(path string) error {
+	slices.SortStableFunc(b.Waivers, func(x, y Waiver) int {
+		if x.File != y.File {
+			return strings.Compare(x.File, y.File)
+		}
+		if x.Line != y.Line {
+			return x.Line - y.Line
+		}
+		return strings.Compare(x.Fingerprint, y.Fingerprint)
+	})
+	if b.Waivers == nil {
+		b.Waivers = []Waiver{}
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("review: baseline: encode: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("review: baseline: create dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("review: baseline: write %q: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("review: baseline: rename %q: %w", tmp, err)
+	}
+	return nil
+}
+
+// Add adds w, replacing a waiver with the same fingerprint. It reports
+// whether w was new.
+func (b *Baseline) Add(w Waiver) bool {
+	for i := range b.Waivers {
+		if b.Waivers[i].Fingerprint == w.Fingerprint {
+			b.Waivers[i] = w
+			return false
+		}
+	}
+	b.Waivers = append(b.Waivers, w)
+	return true
+}
+
+// Match returns the first waiver that is active at now and matches f, or nil.
+func (b *Baseline) Match(f *Finding, now time.Time) *Waiver {
+	if b == nil {
+		return nil
+	}
+	for i := range b.Waivers {
+		w := &b.Waivers[i]
+		if !w.Expired(now) && w.Matches(f) {
+			return w
+		}
+	}
+	return nil
+}
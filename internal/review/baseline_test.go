@@ -0,0 +1,135 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindingFingerprint(t *testing.T) {
+	t.Parallel()
+
+	f := &Finding{Severity: SeverityHigh, Category: "security", File: "auth/login.go", Line: 42, Description: "SQL injection in the login query"}
+	fp := FindingFingerprint(f)
+	assert.Len(t, fp, 12)
+
+	// The line, severity, path prefix, and category spelling do not matter.
+	moved := *f
+	moved.Line = 80
+	moved.Severity = SeverityLow
+	moved.File = "./auth/login.go"
+	moved.Category = "Security"
+	assert.Equal(t, fp, FindingFingerprint(&moved))
+
+	other := *f
+	other.File = "auth/logout.go"
+	assert.NotEqual(t, fp, FindingFingerprint(&other))
+	other = *f
+	other.Description = "Password compared in non-constant time"
+	assert.NotEqual(t, fp, FindingFingerprint(&other))
+}
+
+func TestWaiver_Matches(t *testing.T) {
+	t.Parallel()
+
+	waived := &Finding{Category: "error-handling", File: "store.go", Line: 10, Description: "Error returned by file Close is ignored"}
+	w := NewWaiver(waived, "best effort", "alice", nil, time.Now())
+	assert.Equal(t, FindingFingerprint(waived), w.Fingerprint)
+
+	assert.True(t, w.Matches(&Finding{Category: "error-handling", File: "store.go", Line: 30, Description: "Error returned by file Close is ignored"}))
+	// Reworded by another agent, near the waived line.
+	assert.True(t, w.Matches(&Finding{Category: "error handling", File: "store.go", Line: 12, Description: "Close error on the file is silently ignored"}))
+	// A similar description far from the waived line is another issue.
+	assert.False(t, w.Matches(&Finding{Category: "error handling", File: "store.go", Line: 60, Description: "Close error on the file is silently ignored"}))
+	assert.False(t, w.Matches(&Finding{Category: "error handling", File: "store.go", Description: "Close error on the file is silently ignored"}))
+	assert.False(t, w.Matches(&Finding{Category: "error-handling", File: "other.go", Description: "Error returned by file Close is ignored"}))
+	assert.False(t, w.Matches(&Finding{Category: "error-handling", File: "store.go", Description: "Missing timeout on HTTP client"}))
+}
+
+func TestWaiver_Expired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.False(t, (&Waiver{}).Expired(now))
+	assert.True(t, (&Waiver{ExpiresAt: &past}).Expired(now))
+	assert.False(t, (&Waiver{ExpiresAt: &future}).Expired(now))
+}
+
+func TestBaseline_Match(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	past := now.Add(-24 * time.Hour)
+	expired := &Finding{Category: "style", File: "a.go", Description: "Function is too long"}
+	active := &Finding{Category: "security", File: "b.go", Description: "Weak hash algorithm"}
+
+	b := &Baseline{}
+	b.Add(NewWaiver(expired, "later", "bob", &past, past))
+	b.Add(NewWaiver(active, "legacy API", "bob", nil, now))
+
+	assert.Nil(t, b.Match(expired, now))
+	w := b.Match(active, now)
+	require.NotNil(t, w)
+	assert.Equal(t, "legacy API", w.Reason)
+
+	var nilBaseline *Baseline
+	assert.Nil(t, nilBaseline.Match(active, now))
+}
+
+func TestBaseline_AddReplacesSameFingerprint(t *testing.T) {
+	t.Parallel()
+
+	f := &Finding{Category: "style", File: "a.go", Description: "Function is too long"}
+	b := &Baseline{}
+	assert.True(t, b.Add(NewWaiver(f, "first", "", nil, time.Now())))
+	assert.False(t, b.Add(NewWaiver(f, "second", "", nil, time.Now())))
+	require.Len(t, b.Waivers, 1)
+	assert.Equal(t, "second", b.Waivers[0].Reason)
+}
+
+func TestBaseline_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".raven", "review-baseline.json")
+
+	// A missing file is an empty baseline.
+	b, err := LoadBaseline(path)
+	require.NoError(t, err)
+	assert.Empty(t, b.Waivers)
+
+	expires := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	b.Add(NewWaiver(&Finding{Category: "style", File: "z.go", Line: 3, Description: "Long"}, "ok", "alice", nil, created))
+	b.Add(NewWaiver(&Finding{Category: "security", File: "a.go", Line: 9, Description: "Weak hash"}, "legacy", "bob", &expires, created))
+	require.NoError(t, b.Save(path))
+
+	loaded, err := LoadBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loaded.Version)
+	require.Len(t, loaded.Waivers, 2)
+	assert.Equal(t, "a.go", loaded.Waivers[0].File)
+	assert.Equal(t, "legacy", loaded.Waivers[0].Reason)
+	require.NotNil(t, loaded.Waivers[0].ExpiresAt)
+	assert.True(t, expires.Equal(*loaded.Waivers[0].ExpiresAt))
+	assert.Nil(t, loaded.Waivers[1].ExpiresAt)
+
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLoadBaseline_Invalid(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	_, err := LoadBaseline(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "review: baseline: parse")
+}
@@ -0,0 +1,165 @@
+package review
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// charsPerToken is the number of diff characters assumed per token when
+// estimating prompt sizes. Code and diff markup tokenize more densely than
+// prose, so this is lower than the usual four to keep estimates on the safe
+// side.
+const charsPerToken = 3
+
+// EstimateTokens returns a rough, conservative estimate of the number of
+// tokens in s.
+func EstimateTokens(s string) int {
+	return (len(s) + charsPerToken - 1) / charsPerToken
+}
+
+// DiffChunk is a part of a diff that fits an agent's token budget and is
+// reviewed in a prompt of its own.
+type DiffChunk struct {
+	// Files are the changed files in the chunk, high-risk files first.
+	Files []ChangedFile
+
+	// Diff is the unified diff of Files.
+	Diff string
+
+	// Tokens is the estimated size of Diff.
+	Tokens int
+}
+
+// diffResult returns the DiffResult a prompt for the chunk is built from:
+// the chunk's files, diff, and stats, marked as part of parts.
+func (c DiffChunk) diffResult(parent *DiffResult, part, parts int) *DiffResult {
+	return &DiffResult{
+		Files:      c.Files,
+		FullDiff:   c.Diff,
+		BaseBranch: parent.BaseBranch,
+		Target:     parent.Target,
+		Since:      parent.Since,
+		Stats:      computeStats(c.Files),
+		Part:       part,
+		Parts:      parts,
+	}
+}
+
+// ChunkDiff packs the diffs of files into chunks of at most budget estimated
+// tokens. High-risk files are placed first so that they land in the earliest
+// chunks, and smaller files fill the remaining room of earlier chunks. A file
+// whose diff alone exceeds the budget gets a chunk of its own: a high-risk
+// file is kept whole, any other file is truncated to the budget. Every such
+// file is reported in the returned warnings.
+//
+// Sections of diff that belong to none of files are dropped. A budget <= 0
+// disables chunking and returns all files in a single chunk.
+func ChunkDiff(diff string, files []ChangedFile, budget int) ([]DiffChunk, []string) {
+	sections := make(map[string]string)
+	for _, section := range splitDiffSections(diff) {
+		for _, p := range diffSectionPaths(section) {
+			if _, ok := sections[p]; !ok {
+				sections[p] = section
+			}
+		}
+	}
+	sectionOf := func(f ChangedFile) string {
+		if s, ok := sections[f.Path]; ok {
+			return s
+		}
+		return sections[f.OldPath]
+	}
+
+	if budget <= 0 || len(files) == 0 {
+		chunk := DiffChunk{Files: files}
+		var sb strings.Builder
+		for _, f := range files {
+			sb.WriteString(sectionOf(f))
+		}
+		chunk.Diff = sb.String()
+		chunk.Tokens = EstimateTokens(chunk.Diff)
+		return []DiffChunk{chunk}, nil
+	}
+
+	sorted := make([]ChangedFile, len(files))
+	copy(sorted, files)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return riskOrder(sorted[i].Risk) < riskOrder(sorted[j].Risk)
+	})
+
+	type builder struct {
+		files    []ChangedFile
+		sections []string
+		tokens   int
+		full     bool
+	}
+	var builders []*builder
+	var warnings []string
+
+	for _, f := range sorted {
+		section := sectionOf(f)
+		tokens := EstimateTokens(section)
+
+		if tokens > budget {
+			if f.Risk == RiskHigh {
+				warnings = append(warnings, fmt.Sprintf(
+					"high-risk file %s (~%d tokens) exceeds the %d-token budget and is sent whole", f.Path, tokens, budget))
+			} else {
+				section = truncateSection(section, f.Path, budget)
+				warnings = append(warnings, fmt.Sprintf(
+					"%s (~%d tokens) exceeds the %d-token budget and is truncated", f.Path, tokens, budget))
+			}
+			builders = append(builders, &builder{
+				files:    []ChangedFile{f},
+				sections: []string{section},
+				tokens:   EstimateTokens(section),
+				full:     true,
+			})
+			continue
+		}
+
+		var target *builder
+		for _, b := range builders {
+			if !b.full && b.tokens+tokens <= budget {
+				target = b
+				break
+			}
+		}
+		if target == nil {
+			target = &builder{}
+			builders = append(builders, target)
+		}
+		target.files = append(target.files, f)
+		target.sections = append(target.sections, section)
+		target.tokens += tokens
+	}
+
+	chunks := make([]DiffChunk, len(builders))
+	for i, b := range builders {
+		chunks[i] = DiffChunk{
+			Files:  b.files,
+			Diff:   strings.Join(b.sections, ""),
+			Tokens: b.tokens,
+		}
+	}
+	return chunks, warnings
+}
+
+// truncateSection cuts a file's diff section at the last line that fits in
+// budget tokens, leaving room for a note that names the truncated file.
+func truncateSection(section, path string, budget int) string {
+	note := fmt.Sprintf("... [diff of %s truncated to fit the token budget] ...\n", path)
+	limit := budget*charsPerToken - len(note)
+	if limit <= 0 {
+		return note
+	}
+	if limit < len(section) {
+		cut := section[:limit]
+		if i := strings.LastIndexByte(cut, '\n'); i >= 0 {
+			cut = cut[:i+1]
+		}
+		section = cut
+	}
+	return section + note
+}
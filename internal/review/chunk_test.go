@@ -0,0 +1,163 @@
+package review
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkTestSection returns a diff section for path with n added lines.
+func chunkTestSection(path string, n int) string {
+	var sb strings.Builder
+	sb.WriteString("diff --git a/" + path + " b/" + path + "\n")
+	sb.WriteString("--- a/" + path + "\n+++ b/" + path + "\n@@ -1 +1 @@\n")
+	for i := 0; i < n; i++ {
+		sb.WriteString("+line of code\n")
+	}
+	return sb.String()
+}
+
+func chunkPaths(c DiffChunk) []string {
+	paths := make([]string, len(c.Files))
+	for i, f := range c.Files {
+		paths[i] = f.Path
+	}
+	return paths
+}
+
+func TestEstimateTokens(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 0, EstimateTokens(""))
+	assert.Equal(t, 1, EstimateTokens("ab"))
+	assert.Equal(t, 1, EstimateTokens("abc"))
+	assert.Equal(t, 2, EstimateTokens("abcd"))
+}
+
+func TestChunkDiff_PacksUnderBudget(t *testing.T) {
+	t.Parallel()
+
+	sections := map[string]string{
+		"a.go":    chunkTestSection("a.go", 20),
+		"b.go":    chunkTestSection("b.go", 20),
+		"c.go":    chunkTestSection("c.go", 5),
+		"auth.go": chunkTestSection("auth.go", 20),
+	}
+	files := []ChangedFile{
+		{Path: "a.go", Risk: RiskNormal},
+		{Path: "b.go", Risk: RiskNormal},
+		{Path: "c.go", Risk: RiskLow},
+		{Path: "auth.go", Risk: RiskHigh},
+	}
+	diff := sections["a.go"] + sections["b.go"] + sections["c.go"] + sections["auth.go"] +
+		chunkTestSection("ignored.go", 3)
+	budget := EstimateTokens(sections["auth.go"]) + EstimateTokens(sections["a.go"])
+
+	chunks, warnings := ChunkDiff(diff, files, budget)
+	assert.Empty(t, warnings)
+	require.Len(t, chunks, 2)
+	// The high-risk file comes first, and the low-risk file last.
+	assert.Equal(t, []string{"auth.go", "a.go"}, chunkPaths(chunks[0]))
+	assert.Equal(t, []string{"b.go", "c.go"}, chunkPaths(chunks[1]))
+	assert.Equal(t, sections["auth.go"]+sections["a.go"], chunks[0].Diff)
+	for _, c := range chunks {
+		assert.LessOrEqual(t, c.Tokens, budget)
+		assert.NotContains(t, c.Diff, "ignored.go")
+	}
+}
+
+func TestChunkDiff_OversizedFiles(t *testing.T) {
+	t.Parallel()
+
+	big := chunkTestSection("big.go", 200)
+	auth := chunkTestSection("auth.go", 200)
+	small := chunkTestSection("small.go", 2)
+	files := []ChangedFile{
+		{Path: "big.go", Risk: RiskNormal},
+		{Path: "small.go", Risk: RiskNormal},
+		{Path: "auth.go", Risk: RiskHigh},
+	}
+	budget := 300
+
+	chunks, warnings := ChunkDiff(big+small+auth, files, budget)
+	require.Len(t, chunks, 3)
+	require.Len(t, warnings, 2)
+
+	// The high-risk file is kept whole in a chunk of its own.
+	assert.Equal(t, []string{"auth.go"}, chunkPaths(chunks[0]))
+	assert.Equal(t, auth, chunks[0].Diff)
+	assert.Contains(t, warnings[0], "high-risk file auth.go")
+	assert.Contains(t, warnings[0], "sent whole")
+
+	// The other oversized file is truncated to the budget.
+	assert.Equal(t, []string{"big.go"}, chunkPaths(chunks[1]))
+	assert.LessOrEqual(t, chunks[1].Tokens, budget)
+	assert.True(t, strings.HasSuffix(chunks[1].Diff, "+line of code\n... [diff of big.go truncated to fit the token budget] ...\n"))
+	assert.Contains(t, warnings[1], "big.go")
+	assert.Contains(t, warnings[1], "truncated")
+
+	// Oversized chunks are not filled up with other files.
+	assert.Equal(t, []string{"small.go"}, chunkPaths(chunks[2]))
+}
+
+func TestChunkDiff_NoBudget(t *testing.T) {
+	t.Parallel()
+
+	a := chunkTestSection("a.go", 100)
+	files := []ChangedFile{{Path: "a.go"}, {Path: "image.png"}}
+
+	chunks, warnings := ChunkDiff(a+chunkTestSection("other.go", 1), files, 0)
+	assert.Empty(t, warnings)
+	require.Len(t, chunks, 1)
+	assert.Equal(t, files, chunks[0].Files)
+	assert.Equal(t, a, chunks[0].Diff)
+
+	// Files without a diff section, e.g. binaries, take no room.
+	chunks, _ = ChunkDiff(a, files, EstimateTokens(a))
+	require.Len(t, chunks, 1)
+	assert.Len(t, chunks[0].Files, 2)
+}
+
+func TestChunkDiff_RenamedFileUsesOldPath(t *testing.T) {
+	t.Parallel()
+
+	section := "diff --git a/old.go b/new.go\nsimilarity index 100%\nrename from old.go\nrename to new.go\n"
+	chunks, _ := ChunkDiff(section, []ChangedFile{{Path: "new.go", OldPath: "old.go"}}, 100)
+	require.Len(t, chunks, 1)
+	assert.Equal(t, section, chunks[0].Diff)
+}
+
+func TestMergeChunkResults(t *testing.T) {
+	t.Parallel()
+
+	f1 := Finding{Severity: SeverityLow, Category: "style", File: "a.go", Line: 1, Description: "x"}
+	f2 := Finding{Severity: SeverityHigh, Category: "security", File: "b.go", Line: 2, Description: "y"}
+	ok1 := AgentReviewResult{Agent: "claude", Persona: "sec", Result: &ReviewResult{Findings: []Finding{f1}, Verdict: VerdictApproved}, RawOutput: "one"}
+	ok2 := AgentReviewResult{Agent: "claude", Persona: "sec", Result: &ReviewResult{Findings: []Finding{f2}, Verdict: VerdictChangesNeeded}, RawOutput: "two"}
+	failed := AgentReviewResult{Agent: "claude", Persona: "sec", Err: errors.New("boom")}
+
+	single := mergeChunkResults([]AgentReviewResult{ok1})
+	assert.Equal(t, ok1, single)
+
+	merged := mergeChunkResults([]AgentReviewResult{ok1, ok2})
+	require.NoError(t, merged.Err)
+	require.NotNil(t, merged.Result)
+	assert.Equal(t, "sec", merged.Persona)
+	assert.Equal(t, []Finding{f1, f2}, merged.Result.Findings)
+	assert.Equal(t, VerdictChangesNeeded, merged.Result.Verdict)
+	assert.Equal(t, "one\ntwo", merged.RawOutput)
+
+	// A failed chunk keeps the other chunks' findings and its error, which
+	// the verdict policy treats as an agent failure.
+	merged = mergeChunkResults([]AgentReviewResult{ok1, failed})
+	require.NoError(t, merged.Err)
+	require.EqualError(t, merged.ChunkErr, "boom")
+	assert.Equal(t, []Finding{f1}, merged.Result.Findings)
+	assert.Equal(t, VerdictApproved, merged.Result.Verdict)
+
+	merged = mergeChunkResults([]AgentReviewResult{failed, failed})
+	require.Error(t, merged.Err)
+	assert.Nil(t, merged.Result)
+}
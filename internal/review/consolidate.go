@@ -2,8 +2,11 @@ package review
 
 import (
 	"fmt"
+	"math"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 )
@@ -11,7 +14,13 @@ import (
 // Consolidator merges findings from multiple agent reviews into a single
 // deduplicated, severity-escalated result with an aggregated verdict.
 type Consolidator struct {
-	logger *log.Logger
+	logger      *log.Logger
+	invalidMode InvalidFindingMode
+	workDir     string
+	carried     []*Finding
+	dedup       *DedupConfig
+	baseline    *Baseline
+	policy      VerdictPolicy
 }
 
 // ConsolidationStats captures metrics about the consolidation process,
@@ -41,29 +50,148 @@ type ConsolidationStats struct {
 	// (before deduplication).
 	FindingsPerAgent map[string]int
 
+	// FindingsPerPersona maps persona name to the number of findings its
+	// agent contributed (before deduplication). Empty for a review without
+	// personas.
+	FindingsPerPersona map[string]int
+
 	// FindingsPerSeverity maps severity level to the number of unique findings
 	// at that level in the consolidated output.
 	FindingsPerSeverity map[Severity]int
+
+	// FindingsPerLocation maps each FindingLocation to the number of input
+	// findings classified there. Empty when consolidated without a diff.
+	FindingsPerLocation map[FindingLocation]int
+
+	// InvalidFindings is the number of input findings classified as
+	// LocationInvalid.
+	InvalidFindings int
+
+	// FindingsDropped and FindingsDowngraded count how invalid findings were
+	// handled, according to the consolidator's InvalidFindingMode.
+	FindingsDropped    int
+	FindingsDowngraded int
+
+	// InvalidPerAgent maps agent name to the number of its findings classified
+	// as LocationInvalid.
+	InvalidPerAgent map[string]int
+
+	// HallucinationRate maps agent name to the percentage (0-100) of its
+	// findings classified as LocationInvalid. Only agents that reported
+	// findings appear.
+	HallucinationRate map[string]float64
+	// CarriedFindings is the number of previous findings carried forward
+	// unchanged because the diff did not touch their file. CarriedResolved is
+	// the number of previous findings on touched files that no agent reported
+	// again, which are considered fixed.
+	CarriedFindings int
+	CarriedResolved int
+
+	// SimilarMerged is the number of findings merged into a similar finding
+	// by the semantic deduplication pass, on top of DuplicatesRemoved.
+	SimilarMerged int
+
+	// WaivedFindings is the number of unique findings that matched an active
+	// baseline waiver.
+	WaivedFindings int
 }
 
 // NewConsolidator creates a Consolidator. logger may be nil; when non-nil it
 // receives structured log output for skipped agents and severity escalations.
 func NewConsolidator(logger *log.Logger) *Consolidator {
-	return &Consolidator{logger: logger}
+	return &Consolidator{logger: logger, invalidMode: InvalidFindingsDrop}
+}
+
+// WithInvalidFindings sets how ConsolidateDiff handles findings classified as
+// LocationInvalid. An empty mode restores the default, InvalidFindingsDrop.
+func (c *Consolidator) WithInvalidFindings(mode InvalidFindingMode) *Consolidator {
+	if mode == "" {
+		mode = InvalidFindingsDrop
+	}
+	c.invalidMode = mode
+	return c
+}
+
+// WithWorkDir sets the directory holding the reviewed revision, used to check
+// that findings outside the diff hunks point at existing lines. Empty means
+// the current directory.
+func (c *Consolidator) WithWorkDir(dir string) *Consolidator {
+	c.workDir = dir
+	return c
+}
+
+// WithSemanticDedup enables a second deduplication pass that merges findings
+// from different agents which are close in the same file and similar
+// according to FindingSimilarity. A negative line window or a zero threshold
+// in cfg is replaced by the default; a line window of 0 only merges findings
+// on the same line.
+func (c *Consolidator) WithSemanticDedup(cfg DedupConfig) *Consolidator {
+	if cfg.LineWindow < 0 {
+		cfg.LineWindow = DefaultDedupLineWindow
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultDedupThreshold
+	}
+	c.dedup = &cfg
+	return c
+}
+
+// WithCarriedFindings sets the open findings of a previous review, used by
+// incremental reviews. ConsolidateDiff re-checks each one against the diff: a
+// finding reported again by an agent is kept as reported, a finding on a file
+// the diff touched but that no agent reported again is resolved, and a finding
+// on a file the diff did not touch is carried forward unchanged.
+func (c *Consolidator) WithCarriedFindings(findings []*Finding) *Consolidator {
+	c.carried = findings
+	return c
+}
+
+// WithBaseline sets the waivers of accepted findings. ConsolidateDiff moves
+// the findings matching an active waiver to ConsolidatedReview.Waived and
+// lowers the verdict to what the remaining findings justify.
+func (c *Consolidator) WithBaseline(b *Baseline) *Consolidator {
+	c.baseline = b
+	return c
+}
+
+// WithPolicy sets the policy ConsolidateDiff reaches the verdict by. The zero
+// VerdictPolicy, the default, lets the worst agent verdict win.
+func (c *Consolidator) WithPolicy(p VerdictPolicy) *Consolidator {
+	c.policy = p
+	return c
 }
 
 // Consolidate merges findings from multiple agent reviews into a single
 // deduplicated, severity-escalated result with an aggregated verdict.
 //
-// Results with a non-nil Err are excluded from finding aggregation; their
-// effective verdict is treated as CHANGES_NEEDED.
+// Results with a non-nil Err are excluded from finding aggregation; how they
+// count toward the verdict is up to the verdict policy, by default as
+// CHANGES_NEEDED.
 //
 // The returned ConsolidatedReview has its Findings sorted by severity
 // (critical first), then by file path, then by line number.
 func (c *Consolidator) Consolidate(results []AgentReviewResult) (*ConsolidatedReview, *ConsolidationStats) {
+	return c.ConsolidateDiff(results, nil)
+}
+
+// ConsolidateDiff is Consolidate with every finding validated against diff
+// first. Each finding is classified as in the diff, in the context of a
+// changed file, or invalid, and gets a snippet of the lines around it.
+// Invalid findings are dropped or downgraded to info severity according to
+// WithInvalidFindings. A nil diff skips validation.
+func (c *Consolidator) ConsolidateDiff(results []AgentReviewResult, diff *DiffResult) (*ConsolidatedReview, *ConsolidationStats) {
 	stats := &ConsolidationStats{
 		FindingsPerAgent:    make(map[string]int),
+		FindingsPerPersona:  make(map[string]int),
 		FindingsPerSeverity: make(map[Severity]int),
+		FindingsPerLocation: make(map[FindingLocation]int),
+		InvalidPerAgent:     make(map[string]int),
+		HallucinationRate:   make(map[string]float64),
+	}
+
+	var validator *FindingValidator
+	if diff != nil {
+		validator = NewFindingValidator(diff, c.workDir)
 	}
 
 	if len(results) == 0 {
@@ -80,19 +208,25 @@ func (c *Consolidator) Consolidate(results []AgentReviewResult) (*ConsolidatedRe
 	// agentsByKey tracks which agents reported each finding key.
 	agentsByKey := make(map[string][]string)
 
-	// verdicts collects per-agent verdicts for later aggregation.
-	verdicts := make([]Verdict, 0, len(results))
+	// agentWeights tracks, per finding key, how strongly each agent (or
+	// persona, when the review used personas) reported it: 1 for an exact
+	// report, the similarity score for a merged one.
+	agentWeights := make(map[string]map[string]float64)
+	reportingAgents := 0
+
+	// votes collects per-agent verdicts for the verdict policy.
+	votes := make([]verdictVote, 0, len(results))
 
 	for _, ar := range results {
 		if ar.Err != nil {
-			// Log the failure and treat it as CHANGES_NEEDED for verdict purposes.
+			// Log the failure and leave its vote to the verdict policy.
 			if c.logger != nil {
 				c.logger.Warn("skipping agent findings due to error",
 					"agent", ar.Agent,
 					"error", ar.Err,
 				)
 			}
-			verdicts = append(verdicts, VerdictChangesNeeded)
+			votes = append(votes, verdictVote{agent: ar.Agent, persona: ar.Persona, failed: true})
 			continue
 		}
 
@@ -103,17 +237,59 @@ func (c *Consolidator) Consolidate(results []AgentReviewResult) (*ConsolidatedRe
 					"agent", ar.Agent,
 				)
 			}
-			verdicts = append(verdicts, VerdictChangesNeeded)
+			votes = append(votes, verdictVote{agent: ar.Agent, persona: ar.Persona, failed: true})
 			continue
 		}
 
-		verdicts = append(verdicts, ar.Result.Verdict)
+		votes = append(votes, verdictVote{agent: ar.Agent, persona: ar.Persona, verdict: ar.Result.Verdict})
+		if ar.ChunkErr != nil {
+			// Part of the diff went unreviewed: the agent votes on what it
+			// saw and also counts as failed.
+			if c.logger != nil {
+				c.logger.Warn("agent failed on part of the diff",
+					"agent", ar.Agent,
+					"error", ar.ChunkErr,
+				)
+			}
+			votes = append(votes, verdictVote{agent: ar.Agent, persona: ar.Persona, failed: true})
+		}
+		reportingAgents++
+		reviewer := ar.Agent
+		if ar.Persona != "" {
+			reviewer = ar.Persona
+		}
 
 		for i := range ar.Result.Findings {
 			f := &ar.Result.Findings[i]
 
 			stats.TotalInputFindings++
 			stats.FindingsPerAgent[ar.Agent]++
+			if ar.Persona != "" {
+				stats.FindingsPerPersona[ar.Persona]++
+			}
+
+			var location FindingLocation
+			var snippet string
+			if validator != nil {
+				location, snippet = validator.Classify(f)
+				stats.FindingsPerLocation[location]++
+				if location == LocationInvalid {
+					stats.InvalidFindings++
+					stats.InvalidPerAgent[ar.Agent]++
+					if c.invalidMode != InvalidFindingsDowngrade {
+						stats.FindingsDropped++
+						if c.logger != nil {
+							c.logger.Debug("dropping finding outside the diff",
+								"agent", ar.Agent,
+								"file", f.File,
+								"line", f.Line,
+							)
+						}
+						continue
+					}
+					stats.FindingsDowngraded++
+				}
+			}
 
 			key := f.DeduplicationKey()
 
@@ -122,15 +298,29 @@ func (c *Consolidator) Consolidate(results []AgentReviewResult) (*ConsolidatedRe
 				// First time we see this finding: store a copy with agent attribution.
 				copied := *f
 				copied.Agent = ar.Agent
+				copied.Location = location
+				copied.Snippet = snippet
+				if location == LocationInvalid {
+					copied.Severity = SeverityInfo
+				}
+				copied.Personas = nil
+				if ar.Persona != "" {
+					copied.Personas = []string{ar.Persona}
+				}
 				findingMap[key] = &copied
 				agentsByKey[key] = []string{ar.Agent}
+				agentWeights[key] = map[string]float64{reviewer: 1}
 				continue
 			}
 
 			// Duplicate: escalate severity, merge description, track agents.
 			stats.DuplicatesRemoved++
 
-			escalated := EscalateSeverity(existing.Severity, f.Severity)
+			severity := f.Severity
+			if location == LocationInvalid {
+				severity = SeverityInfo
+			}
+			escalated := EscalateSeverity(existing.Severity, severity)
 			if escalated != existing.Severity {
 				if c.logger != nil {
 					c.logger.Debug("severity escalated",
@@ -148,18 +338,49 @@ func (c *Consolidator) Consolidate(results []AgentReviewResult) (*ConsolidatedRe
 			// note agreement from additional agents, capped to avoid bloat.
 			existing.Description = mergeDescriptions(existing.Description, f.Description)
 
-			// Track which agents reported this finding.
-			agentsByKey[key] = append(agentsByKey[key], ar.Agent)
+			// Track which agents and personas reported this finding. With
+			// personas one agent may report the same finding more than once.
+			if !slices.Contains(agentsByKey[key], ar.Agent) {
+				agentsByKey[key] = append(agentsByKey[key], ar.Agent)
+			}
+			agentWeights[key][reviewer] = 1
+			if ar.Persona != "" && !slices.Contains(existing.Personas, ar.Persona) {
+				existing.Personas = append(existing.Personas, ar.Persona)
+			}
 		}
 	}
 
+	// Merge findings that describe the same issue in different words.
+	if c.dedup != nil {
+		c.mergeSimilar(findingMap, agentsByKey, agentWeights, stats)
+	}
+
+	// Re-check the findings carried over from a previous review. The verdict
+	// policy counts the ones that stay open.
+	c.carryForward(findingMap, agentsByKey, diff, stats)
+
 	// Build final agent attribution and collect unique findings.
 	findings := make([]*Finding, 0, len(findingMap))
+	var waived []*Finding
 	multiAgentCount := 0
+	now := time.Now()
 
 	for key, f := range findingMap {
 		agents := agentsByKey[key]
 		f.Agent = strings.Join(agents, ", ")
+		if weights, ok := agentWeights[key]; ok && reportingAgents > 0 {
+			total := 0.0
+			for _, w := range weights {
+				total += w
+			}
+			f.Confidence = math.Round(total/float64(reportingAgents)*100) / 100
+		}
+		f.Fingerprint = FindingFingerprint(f)
+		if w := c.baseline.Match(f, now); w != nil {
+			f.Waiver = w
+			waived = append(waived, f)
+			continue
+		}
 		if len(agents) > 1 {
 			multiAgentCount++
 		}
@@ -173,7 +394,180 @@ func (c *Consolidator) Consolidate(results []AgentReviewResult) (*ConsolidatedRe
 		stats.OverlapRate = float64(multiAgentCount) / float64(stats.UniqueFindings) * 100
 	}
 
-	// Sort: critical first (descending severity rank), then file path, then line.
+	if validator != nil {
+		for agentName, n := range stats.FindingsPerAgent {
+			if n > 0 {
+				stats.HallucinationRate[agentName] = float64(stats.InvalidPerAgent[agentName]) / float64(n) * 100
+			}
+		}
+		if c.logger != nil && stats.InvalidFindings > 0 {
+			c.logger.Info("invalid findings",
+				"count", stats.InvalidFindings,
+				"dropped", stats.FindingsDropped,
+				"downgraded", stats.FindingsDowngraded,
+			)
+		}
+	}
+
+	sortFindings(findings)
+	sortFindings(waived)
+
+	verdict, reasons := c.policy.decide(votes, findings, len(waived))
+	if len(waived) > 0 {
+		stats.WaivedFindings = len(waived)
+		if c.logger != nil {
+			c.logger.Info("waived findings", "count", len(waived), "verdict", verdict)
+		}
+	}
+
+	consolidated := &ConsolidatedReview{
+		Findings:       findings,
+		Waived:         waived,
+		Verdict:        verdict,
+		VerdictReasons: reasons,
+		AgentResults:   results,
+		TotalAgents:    len(results),
+	}
+
+	return consolidated, stats
+}
+
+// mergeSimilar merges findings that FindingSimilarity scores at or above the
+// consolidator's threshold. Findings are visited in file and line order and
+// each is merged into the most similar earlier finding that survived, which
+// keeps its file, line, and category; severity is escalated and descriptions
+// and agents are combined as for exact duplicates. A finding is never merged
+// into one that all of its agents also reported: an agent reporting two
+// similar findings means two distinct problems.
+func (c *Consolidator) mergeSimilar(
+	findingMap map[string]*Finding,
+	agentsByKey map[string][]string,
+	agentWeights map[string]map[string]float64,
+	stats *ConsolidationStats,
+) {
+	keys := make([]string, 0, len(findingMap))
+	for k := range findingMap {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := findingMap[keys[i]], findingMap[keys[j]]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return keys[i] < keys[j]
+	})
+
+	var kept []string
+	for _, key := range keys {
+		f := findingMap[key]
+		bestKey, bestScore := "", 0.0
+		for _, k := range kept {
+			if reportedByAll(agentsByKey[key], agentsByKey[k]) {
+				continue
+			}
+			if score := FindingSimilarity(findingMap[k], f, c.dedup.LineWindow); score >= c.dedup.Threshold && score > bestScore {
+				bestKey, bestScore = k, score
+			}
+		}
+		if bestKey == "" {
+			kept = append(kept, key)
+			continue
+		}
+
+		target := findingMap[bestKey]
+		if escalated := EscalateSeverity(target.Severity, f.Severity); escalated != target.Severity {
+			target.Severity = escalated
+			stats.SeverityEscalations++
+		}
+		target.Description = mergeDescriptions(target.Description, f.Description)
+		for _, p := range f.Personas {
+			if !slices.Contains(target.Personas, p) {
+				target.Personas = append(target.Personas, p)
+			}
+		}
+		for _, a := range agentsByKey[key] {
+			if !slices.Contains(agentsByKey[bestKey], a) {
+				agentsByKey[bestKey] = append(agentsByKey[bestKey], a)
+			}
+		}
+		for a, w := range agentWeights[key] {
+			if w*bestScore > agentWeights[bestKey][a] {
+				agentWeights[bestKey][a] = w * bestScore
+			}
+		}
+		delete(findingMap, key)
+		delete(agentsByKey, key)
+		delete(agentWeights, key)
+		stats.SimilarMerged++
+
+		if c.logger != nil {
+			c.logger.Debug("similar findings merged",
+				"into", bestKey,
+				"merged", key,
+				"score", bestScore,
+			)
+		}
+	}
+}
+
+// reportedByAll reports whether every agent in agents is also in reporters.
+func reportedByAll(agents, reporters []string) bool {
+	for _, a := range agents {
+		if !slices.Contains(reporters, a) {
+			return false
+		}
+	}
+	return true
+}
+
+// carryForward merges the consolidator's carried findings into findingMap as
+// described on WithCarriedFindings.
+func (c *Consolidator) carryForward(
+	findingMap map[string]*Finding,
+	agentsByKey map[string][]string,
+	diff *DiffResult,
+	stats *ConsolidationStats,
+) {
+	touched := make(map[string]bool)
+	if diff != nil {
+		for _, f := range diff.Files {
+			touched[f.Path] = true
+			if f.OldPath != "" {
+				touched[f.OldPath] = true
+			}
+		}
+	}
+
+	for _, prior := range c.carried {
+		key := prior.DeduplicationKey()
+		if _, reported := findingMap[key]; reported {
+			continue
+		}
+		if touched[normalizeFindingPath(prior.File)] {
+			stats.CarriedResolved++
+			if c.logger != nil {
+				c.logger.Debug("carried finding resolved",
+					"file", prior.File,
+					"line", prior.Line,
+					"category", prior.Category,
+				)
+			}
+			continue
+		}
+		copied := *prior
+		copied.Carried = true
+		findingMap[key] = &copied
+		agentsByKey[key] = []string{prior.Agent}
+		stats.CarriedFindings++
+	}
+}
+
+// sortFindings sorts critical first (descending severity rank), then by file
+// path, then by line.
+func sortFindings(findings []*Finding) {
 	sort.Slice(findings, func(i, j int) bool {
 		ri := severityRank(findings[i].Severity)
 		rj := severityRank(findings[j].Severity)
@@ -186,15 +580,18 @@ func (c *Consolidator) Consolidate(results []AgentReviewResult) (*ConsolidatedRe
 		}
 		return findings[i].Line < findings[j].Line
 	})
+}
 
-	consolidated := &ConsolidatedReview{
-		Findings:     findings,
-		Verdict:      AggregateVerdicts(verdicts),
-		AgentResults: results,
-		TotalAgents:  len(results),
+// severityVerdict is the verdict a single finding of severity s calls for.
+func severityVerdict(s Severity) Verdict {
+	switch s {
+	case SeverityCritical:
+		return VerdictBlocking
+	case SeverityInfo:
+		return VerdictApproved
+	default:
+		return VerdictChangesNeeded
 	}
-
-	return consolidated, stats
 }
 
 // AggregateVerdicts computes the final verdict from per-agent verdicts using
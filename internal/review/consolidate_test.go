@@ -5,6 +5,7 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/stretchr/testify/assert"
@@ -1766,3 +1767,320 @@ func BenchmarkConsolidate1000Findings(b *testing.B) {
 		_, _ = c.Consolidate(allResults)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// ConsolidateDiff
+// ---------------------------------------------------------------------------
+
+func TestConsolidateDiff_DropsInvalidFindings(t *testing.T) {
+	t.Parallel()
+
+	c := NewConsolidator(nil).WithWorkDir(anchorTestWorkDir(t))
+	results := []AgentReviewResult{
+		makeAgentResult("claude", VerdictChangesNeeded, []Finding{
+			{Severity: SeverityHigh, Category: "bug", File: "main.go", Line: 12, Description: "off by one"},
+			{Severity: SeverityHigh, Category: "bug", File: "nope.go", Line: 3, Description: "made up"},
+		}, nil),
+		makeAgentResult("codex", VerdictChangesNeeded, []Finding{
+			{Severity: SeverityLow, Category: "style", File: "main.go", Line: 2, Description: "naming"},
+			{Severity: SeverityLow, Category: "style", File: "main.go", Line: 99, Description: "past the end"},
+			{Severity: SeverityLow, Category: "style", File: "old.go", Line: 1, Description: "deleted"},
+			{Severity: SeverityLow, Category: "bug", File: "main.go", Line: 12, Description: "off by one"},
+		}, nil),
+	}
+
+	cr, stats := c.ConsolidateDiff(results, anchorTestDiffResult())
+	require.Len(t, cr.Findings, 2)
+	assert.Equal(t, LocationInDiff, cr.Findings[0].Location)
+	assert.Contains(t, cr.Findings[0].Snippet, "> 12 |")
+	assert.Equal(t, "claude, codex", cr.Findings[0].Agent)
+	assert.Equal(t, LocationInContext, cr.Findings[1].Location)
+
+	assert.Equal(t, 6, stats.TotalInputFindings)
+	assert.Equal(t, 3, stats.InvalidFindings)
+	assert.Equal(t, 3, stats.FindingsDropped)
+	assert.Zero(t, stats.FindingsDowngraded)
+	assert.Equal(t, 2, stats.FindingsPerLocation[LocationInDiff])
+	assert.Equal(t, 1, stats.FindingsPerLocation[LocationInContext])
+	assert.Equal(t, map[string]int{"claude": 1, "codex": 2}, stats.InvalidPerAgent)
+	assert.InDelta(t, 50.0, stats.HallucinationRate["claude"], 0.001)
+	assert.InDelta(t, 50.0, stats.HallucinationRate["codex"], 0.001)
+	assert.Equal(t, 2, stats.UniqueFindings)
+}
+
+func TestConsolidateDiff_DowngradesInvalidFindings(t *testing.T) {
+	t.Parallel()
+
+	c := NewConsolidator(nil).WithWorkDir(anchorTestWorkDir(t)).WithInvalidFindings(InvalidFindingsDowngrade)
+	results := []AgentReviewResult{
+		makeAgentResult("claude", VerdictBlocking, []Finding{
+			{Severity: SeverityCritical, Category: "security", File: "nope.go", Line: 3, Description: "made up"},
+		}, nil),
+		makeAgentResult("codex", VerdictApproved, []Finding{
+			{Severity: SeverityHigh, Category: "security", File: "nope.go", Line: 3, Description: "made up"},
+		}, nil),
+	}
+
+	cr, stats := c.ConsolidateDiff(results, anchorTestDiffResult())
+	require.Len(t, cr.Findings, 1)
+	assert.Equal(t, SeverityInfo, cr.Findings[0].Severity)
+	assert.Equal(t, LocationInvalid, cr.Findings[0].Location)
+	assert.Empty(t, cr.Findings[0].Snippet)
+	assert.Equal(t, 2, stats.FindingsDowngraded)
+	assert.Zero(t, stats.SeverityEscalations)
+}
+
+func TestConsolidate_SkipsValidationWithoutDiff(t *testing.T) {
+	t.Parallel()
+
+	results := []AgentReviewResult{
+		makeAgentResult("claude", VerdictChangesNeeded, []Finding{
+			{Severity: SeverityHigh, Category: "bug", File: "nope.go", Line: 3},
+		}, nil),
+	}
+	cr, stats := NewConsolidator(nil).Consolidate(results)
+	require.Len(t, cr.Findings, 1)
+	assert.Empty(t, cr.Findings[0].Location)
+	assert.Zero(t, stats.InvalidFindings)
+	assert.Empty(t, stats.HallucinationRate)
+}
+
+// ---------------------------------------------------------------------------
+// Carried findings
+// ---------------------------------------------------------------------------
+
+func TestConsolidateDiff_CarriedFindings(t *testing.T) {
+	t.Parallel()
+
+	prior := []*Finding{
+		{Severity: SeverityMedium, Category: "correctness", File: "main.go", Line: 12, Description: "still there", Agent: "codex"},
+		{Severity: SeverityLow, Category: "style", File: "main.go", Line: 3, Description: "fixed", Agent: "codex"},
+		{Severity: SeverityHigh, Category: "security", File: "other.go", Line: 5, Description: "untouched", Agent: "claude, codex"},
+	}
+	results := []AgentReviewResult{
+		makeAgentResult("claude", VerdictApproved, []Finding{
+			{Severity: SeverityMedium, Category: "correctness", File: "main.go", Line: 12, Description: "still there"},
+		}, nil),
+	}
+
+	c := NewConsolidator(nil).WithWorkDir(anchorTestWorkDir(t)).WithCarriedFindings(prior)
+	cr, stats := c.ConsolidateDiff(results, anchorTestDiffResult())
+
+	require.Len(t, cr.Findings, 2)
+	assert.Equal(t, "other.go", cr.Findings[0].File)
+	assert.True(t, cr.Findings[0].Carried)
+	assert.Equal(t, "claude, codex", cr.Findings[0].Agent)
+	assert.Equal(t, "main.go", cr.Findings[1].File)
+	assert.False(t, cr.Findings[1].Carried)
+	assert.Equal(t, "claude", cr.Findings[1].Agent)
+
+	assert.Equal(t, 1, stats.CarriedFindings)
+	assert.Equal(t, 1, stats.CarriedResolved)
+	assert.Equal(t, 2, stats.UniqueFindings)
+	// The open carried finding keeps the review from being approved.
+	assert.Equal(t, VerdictChangesNeeded, cr.Verdict)
+
+	// The prior findings are not modified.
+	assert.False(t, prior[2].Carried)
+}
+
+func TestConsolidateDiff_CarriedInfoFindingsKeepApproval(t *testing.T) {
+	t.Parallel()
+
+	prior := []*Finding{{Severity: SeverityInfo, Category: "style", File: "other.go", Line: 1, Agent: "claude"}}
+	results := []AgentReviewResult{makeAgentResult("claude", VerdictApproved, nil, nil)}
+
+	cr, stats := NewConsolidator(nil).WithCarriedFindings(prior).ConsolidateDiff(results, anchorTestDiffResult())
+	assert.Equal(t, VerdictApproved, cr.Verdict)
+	assert.Equal(t, 1, stats.CarriedFindings)
+	require.Len(t, cr.Findings, 1)
+	assert.True(t, cr.Findings[0].Carried)
+}
+
+// ---------------------------------------------------------------------------
+// Semantic deduplication
+// ---------------------------------------------------------------------------
+
+func semanticDedupResults() []AgentReviewResult {
+	return []AgentReviewResult{
+		makeAgentResult("claude", VerdictChangesNeeded, []Finding{
+			{Severity: SeverityMedium, Category: "bug", File: "svc/user.go", Line: 41, Description: "nil deref"},
+			{Severity: SeverityLow, Category: "style", File: "svc/user.go", Line: 43, Description: "exported function lacks doc comment"},
+		}, nil),
+		makeAgentResult("codex", VerdictChangesNeeded, []Finding{
+			{Severity: SeverityHigh, Category: "correctness", File: "svc/user.go", Line: 42, Description: "possible nil pointer dereference"},
+		}, nil),
+	}
+}
+
+func TestConsolidate_SemanticDedup(t *testing.T) {
+	t.Parallel()
+
+	c := NewConsolidator(nil).WithSemanticDedup(DedupConfig{LineWindow: DefaultDedupLineWindow})
+	cr, stats := c.Consolidate(semanticDedupResults())
+
+	require.Len(t, cr.Findings, 2)
+	merged := cr.Findings[0]
+	assert.Equal(t, 41, merged.Line)
+	assert.Equal(t, "bug", merged.Category)
+	assert.Equal(t, SeverityHigh, merged.Severity)
+	assert.Equal(t, "claude, codex", merged.Agent)
+	assert.Contains(t, merged.Description, "nil pointer dereference")
+	// claude reported it exactly (1), codex with similarity 0.8375.
+	assert.InDelta(t, 0.92, merged.Confidence, 0.001)
+
+	assert.Equal(t, "style", cr.Findings[1].Category)
+	assert.InDelta(t, 0.5, cr.Findings[1].Confidence, 0.001)
+
+	assert.Equal(t, 1, stats.SimilarMerged)
+	assert.Equal(t, 1, stats.SeverityEscalations)
+	assert.Equal(t, 2, stats.UniqueFindings)
+	assert.InDelta(t, 50.0, stats.OverlapRate, 0.001)
+}
+
+func TestConsolidate_SemanticDedup_Thresholds(t *testing.T) {
+	t.Parallel()
+
+	// A window of 0 only merges findings on the same line.
+	_, stats := NewConsolidator(nil).WithSemanticDedup(DedupConfig{LineWindow: 0, Threshold: 0.6}).Consolidate(semanticDedupResults())
+	assert.Zero(t, stats.SimilarMerged, "findings one line apart are not merged")
+
+	// A negative window falls back to the default.
+	_, stats = NewConsolidator(nil).WithSemanticDedup(DedupConfig{LineWindow: -1, Threshold: 0.6}).Consolidate(semanticDedupResults())
+	assert.Equal(t, 1, stats.SimilarMerged)
+
+	cr, stats := NewConsolidator(nil).WithSemanticDedup(DedupConfig{LineWindow: 3, Threshold: 0.9}).Consolidate(semanticDedupResults())
+	assert.Zero(t, stats.SimilarMerged)
+	assert.Len(t, cr.Findings, 3)
+}
+
+func TestConsolidate_SemanticDedup_SameAgent(t *testing.T) {
+	t.Parallel()
+
+	// Two similar findings from one agent are distinct problems; codex's
+	// report of one of them is still merged.
+	results := []AgentReviewResult{
+		makeAgentResult("claude", VerdictChangesNeeded, []Finding{
+			{Severity: SeverityMedium, Category: "error-handling", File: "io.go", Line: 10, Description: "missing error check on Close"},
+			{Severity: SeverityMedium, Category: "error-handling", File: "io.go", Line: 12, Description: "missing error check on Write"},
+		}, nil),
+		makeAgentResult("codex", VerdictChangesNeeded, []Finding{
+			{Severity: SeverityMedium, Category: "error-handling", File: "io.go", Line: 11, Description: "missing error check on Close"},
+		}, nil),
+	}
+
+	cr, stats := NewConsolidator(nil).WithSemanticDedup(DedupConfig{Threshold: 0.6, LineWindow: DefaultDedupLineWindow}).Consolidate(results)
+	require.Len(t, cr.Findings, 2)
+	assert.Equal(t, 1, stats.SimilarMerged)
+	assert.Equal(t, "claude, codex", cr.Findings[0].Agent)
+	assert.Contains(t, cr.Findings[0].Description, "Close")
+	assert.Equal(t, "claude", cr.Findings[1].Agent)
+	assert.Contains(t, cr.Findings[1].Description, "Write")
+}
+
+func TestConsolidate_ExactDedupByDefault(t *testing.T) {
+	t.Parallel()
+
+	cr, stats := NewConsolidator(nil).Consolidate(semanticDedupResults())
+	assert.Len(t, cr.Findings, 3)
+	assert.Zero(t, stats.SimilarMerged)
+	for _, f := range cr.Findings {
+		assert.InDelta(t, 0.5, f.Confidence, 0.001)
+	}
+}
+
+func TestConsolidate_Personas(t *testing.T) {
+	t.Parallel()
+
+	security := makeAgentResult("claude", VerdictChangesNeeded, []Finding{
+		{Severity: SeverityHigh, Category: "security", File: "auth.go", Line: 10, Description: "SQL injection"},
+	}, nil)
+	security.Persona = "security"
+	api := makeAgentResult("claude", VerdictChangesNeeded, []Finding{
+		{Severity: SeverityMedium, Category: "security", File: "auth.go", Line: 10, Description: "SQL injection"},
+		{Severity: SeverityLow, Category: "api", File: "api.go", Line: 3, Description: "Breaking change"},
+	}, nil)
+	api.Persona = "api"
+	tests := makeAgentResult("codex", VerdictApproved, nil, nil)
+	tests.Persona = "tests"
+
+	cr, stats := NewConsolidator(nil).Consolidate([]AgentReviewResult{security, api, tests})
+	require.Len(t, cr.Findings, 2)
+
+	// One agent reporting the same finding as two personas is attributed once.
+	assert.Equal(t, "claude", cr.Findings[0].Agent)
+	assert.Equal(t, []string{"security", "api"}, cr.Findings[0].Personas)
+	assert.Equal(t, SeverityHigh, cr.Findings[0].Severity)
+	// Confidence is the share of personas that reported the finding.
+	assert.InDelta(t, 0.67, cr.Findings[0].Confidence, 0.001)
+	assert.Equal(t, []string{"api"}, cr.Findings[1].Personas)
+	assert.Equal(t, map[string]int{"security": 1, "api": 2}, stats.FindingsPerPersona)
+	assert.Equal(t, 3, stats.FindingsPerAgent["claude"])
+}
+
+func TestConsolidate_Baseline(t *testing.T) {
+	t.Parallel()
+
+	accepted := Finding{Severity: SeverityCritical, Category: "security", File: "auth.go", Line: 5, Description: "Hard-coded credentials in test fixture"}
+	open := Finding{Severity: SeverityLow, Category: "style", File: "main.go", Line: 1, Description: "Exported function lacks a doc comment"}
+
+	baseline := &Baseline{}
+	baseline.Add(NewWaiver(&accepted, "fixture only", "alice", nil, time.Now()))
+
+	results := []AgentReviewResult{
+		makeAgentResult("claude", VerdictBlocking, []Finding{accepted, open}, nil),
+	}
+	c := NewConsolidator(nil).WithBaseline(baseline)
+	consolidated, stats := c.Consolidate(results)
+
+	require.Len(t, consolidated.Findings, 1)
+	assert.Equal(t, "main.go", consolidated.Findings[0].File)
+	assert.Equal(t, FindingFingerprint(&open), consolidated.Findings[0].Fingerprint)
+	require.Len(t, consolidated.Waived, 1)
+	assert.Equal(t, "auth.go", consolidated.Waived[0].File)
+	require.NotNil(t, consolidated.Waived[0].Waiver)
+	assert.Equal(t, "fixture only", consolidated.Waived[0].Waiver.Reason)
+	assert.Equal(t, 1, stats.WaivedFindings)
+	assert.Equal(t, 1, stats.UniqueFindings)
+
+	// The agent blocked on the waived finding; the low finding left only
+	// calls for changes.
+	assert.Equal(t, VerdictChangesNeeded, consolidated.Verdict)
+}
+
+func TestConsolidate_Baseline_AllWaivedApproves(t *testing.T) {
+	t.Parallel()
+
+	accepted := Finding{Severity: SeverityHigh, Category: "security", File: "auth.go", Line: 5, Description: "Weak hash"}
+	baseline := &Baseline{}
+	baseline.Add(NewWaiver(&accepted, "legacy", "", nil, time.Now()))
+
+	consolidated, _ := NewConsolidator(nil).WithBaseline(baseline).Consolidate([]AgentReviewResult{
+		makeAgentResult("claude", VerdictChangesNeeded, []Finding{accepted}, nil),
+		makeAgentResult("codex", VerdictApproved, nil, nil),
+	})
+	assert.Empty(t, consolidated.Findings)
+	assert.Len(t, consolidated.Waived, 1)
+	assert.Equal(t, VerdictApproved, consolidated.Verdict)
+
+	// A failed agent still keeps the review from being approved.
+	consolidated, _ = NewConsolidator(nil).WithBaseline(baseline).Consolidate([]AgentReviewResult{
+		makeAgentResult("claude", VerdictChangesNeeded, []Finding{accepted}, nil),
+		makeAgentResult("codex", "", nil, errors.New("boom")),
+	})
+	assert.Equal(t, VerdictChangesNeeded, consolidated.Verdict)
+}
+
+func TestConsolidate_Baseline_NeverRaisesVerdict(t *testing.T) {
+	t.Parallel()
+
+	accepted := Finding{Severity: SeverityLow, Category: "style", File: "a.go", Line: 1, Description: "Long line"}
+	open := Finding{Severity: SeverityHigh, Category: "correctness", File: "b.go", Line: 2, Description: "Off by one"}
+	baseline := &Baseline{}
+	baseline.Add(NewWaiver(&accepted, "ok", "", nil, time.Now()))
+
+	consolidated, _ := NewConsolidator(nil).WithBaseline(baseline).Consolidate([]AgentReviewResult{
+		makeAgentResult("claude", VerdictApproved, []Finding{accepted, open}, nil),
+	})
+	assert.Equal(t, VerdictApproved, consolidated.Verdict)
+}
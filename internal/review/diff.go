@@ -67,33 +67,37 @@ type ChangedFile struct {
 
 	// OldPath is the original path before a rename. Empty for non-renames.
 	OldPath string
+
+	// Hunks are the file's hunks parsed from the unified diff, used to anchor
+	// findings to changed lines. Empty for binary files.
+	Hunks []DiffHunk
 }
 
 // DiffStats summarises the overall diff at a high level.
 type DiffStats struct {
 	// TotalFiles is the total number of files touched.
-	TotalFiles int
+	TotalFiles int `json:"total_files"`
 
 	// FilesAdded is the count of new files.
-	FilesAdded int
+	FilesAdded int `json:"files_added"`
 
 	// FilesModified is the count of modified files.
-	FilesModified int
+	FilesModified int `json:"files_modified"`
 
 	// FilesDeleted is the count of deleted files.
-	FilesDeleted int
+	FilesDeleted int `json:"files_deleted"`
 
 	// FilesRenamed is the count of renamed files.
-	FilesRenamed int
+	FilesRenamed int `json:"files_renamed"`
 
 	// TotalLinesAdded is the aggregate number of inserted lines.
-	TotalLinesAdded int
+	TotalLinesAdded int `json:"total_lines_added"`
 
 	// TotalLinesDeleted is the aggregate number of deleted lines.
-	TotalLinesDeleted int
+	TotalLinesDeleted int `json:"total_lines_deleted"`
 
 	// HighRiskFiles is the number of files classified as RiskHigh.
-	HighRiskFiles int
+	HighRiskFiles int `json:"high_risk_files"`
 }
 
 // DiffResult holds the complete output of a diff generation run.
@@ -104,11 +108,26 @@ type DiffResult struct {
 	// FullDiff is the unified diff text, ready for inclusion in review prompts.
 	FullDiff string
 
-	// BaseBranch is the git ref that was diffed against HEAD.
+	// BaseBranch is the git ref that was diffed against HEAD. Empty when
+	// Target is not a branch diff.
 	BaseBranch string
 
+	// Target is the set of changes that was diffed.
+	Target git.DiffTarget
+
+	// Since is the commit an incremental review diffed from, in which case
+	// the diff covers Since..HEAD rather than BaseBranch...HEAD. Empty for a
+	// full review.
+	Since string
+
 	// Stats is the aggregate summary of the diff.
 	Stats DiffStats
+
+	// Part and Parts are set when the diff is one of Parts chunks of a diff
+	// that exceeded an agent's token budget; Part is 1-based. Both are zero
+	// for a whole diff.
+	Part  int
+	Parts int
 }
 
 // validBranchName is compiled once and used to guard against branch names that
@@ -117,6 +136,10 @@ type DiffResult struct {
 // range operators that could alter command semantics.
 var validBranchName = regexp.MustCompile(`^[a-zA-Z0-9_./-]+$`)
 
+// validRevision extends validBranchName with the "~" and "^" ancestry
+// suffixes, so that revisions such as "HEAD~1" or "abc1234^2" are accepted.
+var validRevision = regexp.MustCompile(`^[a-zA-Z0-9_./~^-]+$`)
+
 // DiffGenerator orchestrates diff generation, file filtering, and risk
 // classification for the review pipeline.
 type DiffGenerator struct {
@@ -163,24 +186,107 @@ func NewDiffGenerator(gitClient git.Client, cfg ReviewConfig, logger *log.Logger
 // An empty diff (no changed files) returns a valid DiffResult with an empty
 // Files slice, empty FullDiff, and zero Stats.
 func (d *DiffGenerator) Generate(ctx context.Context, baseBranch string) (*DiffResult, error) {
-	if !validBranchName.MatchString(baseBranch) || strings.Contains(baseBranch, "..") {
-		return nil, fmt.Errorf("review: generate: invalid base branch %q: must match ^[a-zA-Z0-9_./-]+$ with no consecutive dots", baseBranch)
+	return d.GenerateTarget(ctx, git.DiffTarget{Kind: git.TargetBranch, Ref: baseBranch})
+}
+
+// GenerateTarget produces a DiffResult for the changes selected by target: a
+// base branch, a commit range, a single commit, the staged changes, or the
+// working tree. Refs are validated before they are passed to git.
+func (d *DiffGenerator) GenerateTarget(ctx context.Context, target git.DiffTarget) (*DiffResult, error) {
+	if target.Kind == "" {
+		target.Kind = git.TargetBranch
+	}
+	if err := validateTarget(target); err != nil {
+		return nil, err
+	}
+	result, err := d.generate(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if target.Kind == git.TargetBranch {
+		result.BaseBranch = target.Ref
+	}
+	d.logGenerated(result, target)
+	return result, nil
+}
+
+// GenerateSince produces a DiffResult covering only the commits after since,
+// i.e. since..HEAD. It is used by incremental reviews, where since is the
+// HEAD of the previous review. baseBranch is recorded on the result but not
+// diffed against.
+func (d *DiffGenerator) GenerateSince(ctx context.Context, baseBranch, since string) (*DiffResult, error) {
+	if !validRef(baseBranch) {
+		return nil, fmt.Errorf("review: generate: invalid base branch %q: must match ^[a-zA-Z0-9_./~^-]+$ with no consecutive dots", baseBranch)
+	}
+	if !validRef(since) {
+		return nil, fmt.Errorf("review: generate: invalid commit %q: must match ^[a-zA-Z0-9_./~^-]+$ with no consecutive dots", since)
+	}
+	target := git.DiffTarget{Kind: git.TargetRange, Ref: since + "..HEAD"}
+	result, err := d.generate(ctx, target)
+	if err != nil {
+		return nil, err
 	}
+	result.BaseBranch = baseBranch
+	result.Since = since
+	d.logGenerated(result, target)
+	return result, nil
+}
+
+// validRef reports whether ref is safe to pass to git as a single revision:
+// it must match validRevision, must not contain a ".." range operator, and
+// must not start with "-" where git would read it as a flag.
+func validRef(ref string) bool {
+	return validRevision.MatchString(ref) && !strings.Contains(ref, "..") && !strings.HasPrefix(ref, "-")
+}
+
+// validateTarget checks that target names a known kind and that its refs are
+// safe to pass to git. A range is "A..B" or "A...B" where either side, but
+// not both, may be empty to mean HEAD.
+func validateTarget(target git.DiffTarget) error {
+	switch target.Kind {
+	case git.TargetBranch:
+		if !validRef(target.Ref) {
+			return fmt.Errorf("review: generate: invalid base branch %q: must match ^[a-zA-Z0-9_./~^-]+$ with no consecutive dots", target.Ref)
+		}
+	case git.TargetCommit:
+		if !validRef(target.Ref) {
+			return fmt.Errorf("review: generate: invalid commit %q: must match ^[a-zA-Z0-9_./~^-]+$ with no consecutive dots", target.Ref)
+		}
+	case git.TargetRange:
+		from, to, ok := strings.Cut(target.Ref, "...")
+		if !ok {
+			from, to, ok = strings.Cut(target.Ref, "..")
+		}
+		if !ok || from == "" && to == "" ||
+			from != "" && !validRef(from) || to != "" && !validRef(to) {
+			return fmt.Errorf("review: generate: invalid range %q: must be A..B or A...B with refs matching ^[a-zA-Z0-9_./~^-]+$", target.Ref)
+		}
+	case git.TargetStaged, git.TargetWorktree:
+		if target.Ref != "" {
+			return fmt.Errorf("review: generate: %s takes no ref, got %q", target, target.Ref)
+		}
+	default:
+		return fmt.Errorf("review: generate: unknown diff target %q", target.Kind)
+	}
+	return nil
+}
 
+// generate diffs target and filters and classifies the changed files.
+func (d *DiffGenerator) generate(ctx context.Context, target git.DiffTarget) (*DiffResult, error) {
 	// Fetch all three data sources concurrently would be nice, but the git
 	// client is a sequential CLI wrapper. Run them sequentially to keep the
 	// implementation simple and avoid interleaved stderr.
-	entries, err := d.gitClient.DiffFiles(ctx, baseBranch)
+	entries, err := d.gitClient.DiffTargetFiles(ctx, target)
 	if err != nil {
 		return nil, fmt.Errorf("review: generate: listing changed files: %w", err)
 	}
 
-	numStats, err := d.gitClient.DiffNumStat(ctx, baseBranch)
+	numStats, err := d.gitClient.DiffTargetNumStat(ctx, target)
 	if err != nil {
 		return nil, fmt.Errorf("review: generate: fetching numstat: %w", err)
 	}
 
-	fullDiff, err := d.gitClient.DiffUnified(ctx, baseBranch)
+	fullDiff, err := d.gitClient.DiffTargetUnified(ctx, target)
 	if err != nil {
 		return nil, fmt.Errorf("review: generate: fetching unified diff: %w", err)
 	}
@@ -191,11 +297,14 @@ func (d *DiffGenerator) Generate(ctx context.Context, baseBranch string) (*DiffR
 		numStatByPath[ns.Path] = ns
 	}
 
+	hunksByPath := ParseUnifiedDiff(fullDiff)
+
 	// Convert git.DiffEntry list into []ChangedFile, applying filtering and
 	// risk classification.
 	files := make([]ChangedFile, 0, len(entries))
 	for _, entry := range entries {
 		cf := d.buildChangedFile(entry, numStatByPath)
+		cf.Hunks = hunksByPath[cf.Path]
 
 		// Apply extension filter if configured.
 		if d.extensions != nil && !d.extensions.MatchString(cf.Path) {
@@ -211,24 +320,26 @@ func (d *DiffGenerator) Generate(ctx context.Context, baseBranch string) (*DiffR
 		files = append(files, cf)
 	}
 
-	result := &DiffResult{
-		Files:      files,
-		FullDiff:   fullDiff,
-		BaseBranch: baseBranch,
-		Stats:      computeStats(files),
-	}
+	return &DiffResult{
+		Files:    files,
+		FullDiff: fullDiff,
+		Target:   target,
+		Stats:    computeStats(files),
+	}, nil
+}
 
-	if d.logger != nil {
-		d.logger.Info("diff generated",
-			"base", baseBranch,
-			"files", result.Stats.TotalFiles,
-			"high_risk", result.Stats.HighRiskFiles,
-			"lines_added", result.Stats.TotalLinesAdded,
-			"lines_deleted", result.Stats.TotalLinesDeleted,
-		)
+// logGenerated logs a summary of a generated diff.
+func (d *DiffGenerator) logGenerated(result *DiffResult, target git.DiffTarget) {
+	if d.logger == nil {
+		return
 	}
-
-	return result, nil
+	d.logger.Info("diff generated",
+		"target", target.String(),
+		"files", result.Stats.TotalFiles,
+		"high_risk", result.Stats.HighRiskFiles,
+		"lines_added", result.Stats.TotalLinesAdded,
+		"lines_deleted", result.Stats.TotalLinesDeleted,
+	)
 }
 
 // buildChangedFile converts a single git.DiffEntry and its associated numstat
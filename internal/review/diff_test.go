@@ -27,9 +27,15 @@ type mockGitClient struct {
 	unifiedErr      error
 	diffStatResult  *git.DiffStats
 	diffStatErr     error
+	// diffFilesBase records the base passed to the last DiffFiles call, or
+	// the ref of the target passed to the last DiffTargetFiles call.
+	diffFilesBase string
+	// diffTarget records the target passed to the last DiffTargetFiles call.
+	diffTarget git.DiffTarget
 }
 
-func (m *mockGitClient) DiffFiles(_ context.Context, _ string) ([]git.DiffEntry, error) {
+func (m *mockGitClient) DiffFiles(_ context.Context, base string) ([]git.DiffEntry, error) {
+	m.diffFilesBase = base
 	return m.diffFilesResult, m.diffFilesErr
 }
 
@@ -45,6 +51,20 @@ func (m *mockGitClient) DiffNumStat(_ context.Context, _ string) ([]git.NumStatE
 	return m.numStatResult, m.numStatErr
 }
 
+func (m *mockGitClient) DiffTargetFiles(_ context.Context, target git.DiffTarget) ([]git.DiffEntry, error) {
+	m.diffFilesBase = target.Ref
+	m.diffTarget = target
+	return m.diffFilesResult, m.diffFilesErr
+}
+
+func (m *mockGitClient) DiffTargetUnified(_ context.Context, _ git.DiffTarget) (string, error) {
+	return m.unifiedResult, m.unifiedErr
+}
+
+func (m *mockGitClient) DiffTargetNumStat(_ context.Context, _ git.DiffTarget) ([]git.NumStatEntry, error) {
+	return m.numStatResult, m.numStatErr
+}
+
 // ---------------------------------------------------------------------------
 // NewDiffGenerator tests
 // ---------------------------------------------------------------------------
@@ -1368,3 +1388,135 @@ func FuzzGenerate_BranchNameValidation(f *testing.F) {
 		}
 	})
 }
+
+// ---------------------------------------------------------------------------
+// Generate — hunks
+// ---------------------------------------------------------------------------
+
+func TestGenerate_PopulatesHunks(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockGitClient{
+		diffFilesResult: []git.DiffEntry{{Status: "M", Path: "main.go"}, {Status: "D", Path: "old.go"}, {Status: "M", Path: "img.png"}},
+		unifiedResult:   anchorTestDiff,
+	}
+	dg, err := NewDiffGenerator(mock, ReviewConfig{}, nil)
+	require.NoError(t, err)
+
+	result, err := dg.Generate(context.Background(), "main")
+	require.NoError(t, err)
+	require.Len(t, result.Files, 3)
+	assert.Len(t, result.Files[0].Hunks, 2)
+	assert.Len(t, result.Files[1].Hunks, 1)
+	assert.Empty(t, result.Files[2].Hunks)
+}
+
+func TestGenerateSince(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockGitClient{
+		diffFilesResult: []git.DiffEntry{{Status: "M", Path: "main.go"}},
+		unifiedResult:   anchorTestDiff,
+	}
+	dg, err := NewDiffGenerator(mock, ReviewConfig{}, nil)
+	require.NoError(t, err)
+
+	result, err := dg.GenerateSince(context.Background(), "main", "abc1234")
+	require.NoError(t, err)
+	assert.Equal(t, "abc1234..HEAD", mock.diffFilesBase)
+	assert.Equal(t, "main", result.BaseBranch)
+	assert.Equal(t, "abc1234", result.Since)
+	require.Len(t, result.Files, 1)
+
+	result, err = dg.Generate(context.Background(), "main")
+	require.NoError(t, err)
+	assert.Equal(t, "main", mock.diffFilesBase)
+	assert.Empty(t, result.Since)
+}
+
+func TestGenerateTarget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		target     git.DiffTarget
+		wantBase   string
+		wantTarget git.DiffTarget
+	}{
+		{target: git.DiffTarget{Ref: "main"}, wantBase: "main", wantTarget: git.DiffTarget{Kind: git.TargetBranch, Ref: "main"}},
+		{target: git.DiffTarget{Kind: git.TargetRange, Ref: "v1.0..v1.1"}},
+		{target: git.DiffTarget{Kind: git.TargetRange, Ref: "origin/main...abc1234"}},
+		{target: git.DiffTarget{Kind: git.TargetRange, Ref: "v1.0.."}},
+		{target: git.DiffTarget{Kind: git.TargetRange, Ref: "HEAD~3..HEAD"}},
+		{target: git.DiffTarget{Kind: git.TargetCommit, Ref: "abc1234"}},
+		{target: git.DiffTarget{Kind: git.TargetCommit, Ref: "HEAD~1"}},
+		{target: git.DiffTarget{Kind: git.TargetCommit, Ref: "HEAD^2"}},
+		{target: git.DiffTarget{Kind: git.TargetStaged}},
+		{target: git.DiffTarget{Kind: git.TargetWorktree}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target.String(), func(t *testing.T) {
+			t.Parallel()
+			mock := &mockGitClient{
+				diffFilesResult: []git.DiffEntry{{Status: "M", Path: "main.go"}},
+				unifiedResult:   anchorTestDiff,
+			}
+			dg, err := NewDiffGenerator(mock, ReviewConfig{}, nil)
+			require.NoError(t, err)
+
+			want := tt.wantTarget
+			if want.Kind == "" {
+				want = tt.target
+			}
+			result, err := dg.GenerateTarget(context.Background(), tt.target)
+			require.NoError(t, err)
+			assert.Equal(t, want, mock.diffTarget)
+			assert.Equal(t, want, result.Target)
+			assert.Equal(t, tt.wantBase, result.BaseBranch)
+			require.Len(t, result.Files, 1)
+		})
+	}
+}
+
+func TestGenerateTarget_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dg, err := NewDiffGenerator(&mockGitClient{}, ReviewConfig{}, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		target  git.DiffTarget
+		wantErr string
+	}{
+		{git.DiffTarget{Kind: git.TargetBranch, Ref: "main..HEAD"}, "invalid base branch"},
+		{git.DiffTarget{Kind: git.TargetCommit, Ref: "-p"}, "invalid commit"},
+		{git.DiffTarget{Kind: git.TargetCommit, Ref: "a..b"}, "invalid commit"},
+		{git.DiffTarget{Kind: git.TargetRange, Ref: "main"}, "invalid range"},
+		{git.DiffTarget{Kind: git.TargetRange, Ref: ".."}, "invalid range"},
+		{git.DiffTarget{Kind: git.TargetRange, Ref: "--output=x..HEAD"}, "invalid range"},
+		{git.DiffTarget{Kind: git.TargetRange, Ref: "a;rm..b"}, "invalid range"},
+		{git.DiffTarget{Kind: git.TargetStaged, Ref: "main"}, "takes no ref"},
+		{git.DiffTarget{Kind: "tag"}, "unknown diff target"},
+	}
+	for _, tt := range tests {
+		_, err := dg.GenerateTarget(context.Background(), tt.target)
+		require.Error(t, err, tt.target.Ref)
+		assert.Contains(t, err.Error(), tt.wantErr)
+	}
+}
+
+func TestGenerateSince_InvalidRefs(t *testing.T) {
+	t.Parallel()
+
+	dg, err := NewDiffGenerator(&mockGitClient{}, ReviewConfig{}, nil)
+	require.NoError(t, err)
+
+	for _, tt := range []struct{ base, since string }{
+		{"main", "abc..HEAD"},
+		{"main", "--output=x"},
+		{"main", ""},
+		{"ma in", "abc1234"},
+	} {
+		_, err := dg.GenerateSince(context.Background(), tt.base, tt.since)
+		assert.Error(t, err, "%q %q", tt.base, tt.since)
+	}
+}
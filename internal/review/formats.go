@@ -0,0 +1,507 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/buildinfo"
+)
+
+// ReportFormat selects the output format of a review report.
+type ReportFormat string
+
+const (
+	// FormatMarkdown is the human-readable markdown report.
+	FormatMarkdown ReportFormat = "markdown"
+
+	// FormatJSON is a machine-readable dump of the consolidated review.
+	FormatJSON ReportFormat = "json"
+
+	// FormatSARIF is a SARIF 2.1.0 log for code-scanning UIs.
+	FormatSARIF ReportFormat = "sarif"
+
+	// FormatGitHubAnnotations is a list of GitHub Actions workflow commands
+	// ("::error file=...,line=...::message") that annotate the diff in CI.
+	FormatGitHubAnnotations ReportFormat = "github-annotations"
+)
+
+// ReportFormats lists every supported ReportFormat in display order.
+var ReportFormats = []ReportFormat{FormatMarkdown, FormatJSON, FormatSARIF, FormatGitHubAnnotations}
+
+// ParseReportFormat converts s to a ReportFormat. An empty string selects
+// FormatMarkdown.
+func ParseReportFormat(s string) (ReportFormat, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return FormatMarkdown, nil
+	}
+	for _, f := range ReportFormats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	names := make([]string, len(ReportFormats))
+	for i, f := range ReportFormats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown report format %q: must be one of %s", s, strings.Join(names, ", "))
+}
+
+// Render produces the report in the given format. FormatMarkdown is
+// equivalent to Generate.
+func (rg *ReportGenerator) Render(
+	format ReportFormat,
+	consolidated *ConsolidatedReview,
+	stats *ConsolidationStats,
+	diffResult *DiffResult,
+) (string, error) {
+	if format == FormatMarkdown || format == "" {
+		return rg.Generate(consolidated, stats, diffResult)
+	}
+	if consolidated == nil {
+		return "", fmt.Errorf("review: report: consolidated review is required")
+	}
+
+	var (
+		out string
+		err error
+	)
+	switch format {
+	case FormatJSON:
+		out, err = GenerateJSON(consolidated, stats, diffResult)
+	case FormatSARIF:
+		out, err = GenerateSARIF(consolidated)
+	case FormatGitHubAnnotations:
+		out = GenerateAnnotations(consolidated)
+	default:
+		return "", fmt.Errorf("review: report: unknown format %q", format)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if rg.logger != nil {
+		rg.logger.Info("review report generated",
+			"format", format,
+			"verdict", consolidated.Verdict,
+			"findings", len(consolidated.Findings),
+			"bytes", len(out),
+		)
+	}
+	return out, nil
+}
+
+// JSONReport is the document written by FormatJSON.
+type JSONReport struct {
+	Verdict        Verdict           `json:"verdict"`
+	VerdictReasons []string          `json:"verdict_reasons,omitempty"`
+	GeneratedAt    time.Time         `json:"generated_at"`
+	Findings       []*Finding        `json:"findings"`
+	Waived         []*Finding        `json:"waived,omitempty"`
+	Agents         []JSONAgentResult `json:"agents"`
+	Stats          *JSONStats        `json:"stats,omitempty"`
+	Diff           *DiffStats        `json:"diff,omitempty"`
+	// Since is the previously reviewed commit for an incremental review.
+	Since string `json:"since,omitempty"`
+	// Target describes the reviewed changes when they are not a branch diff.
+	Target string `json:"target,omitempty"`
+	// Lifecycle compares the findings with the previous review of the
+	// branch, when the review was recorded in its finding ledger.
+	Lifecycle *FindingLifecycle `json:"lifecycle,omitempty"`
+}
+
+// JSONAgentResult summarises one agent's review pass in a JSONReport.
+type JSONAgentResult struct {
+	Agent      string  `json:"agent"`
+	Persona    string  `json:"persona,omitempty"`
+	Verdict    Verdict `json:"verdict,omitempty"`
+	Findings   int     `json:"findings"`
+	DurationMS int64   `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// JSONStats is ConsolidationStats with JSON field names.
+type JSONStats struct {
+	TotalInputFindings  int                     `json:"total_input_findings"`
+	UniqueFindings      int                     `json:"unique_findings"`
+	DuplicatesRemoved   int                     `json:"duplicates_removed"`
+	SeverityEscalations int                     `json:"severity_escalations"`
+	OverlapRate         float64                 `json:"overlap_rate"`
+	FindingsPerAgent    map[string]int          `json:"findings_per_agent"`
+	FindingsPerPersona  map[string]int          `json:"findings_per_persona,omitempty"`
+	FindingsPerSeverity map[Severity]int        `json:"findings_per_severity"`
+	FindingsPerLocation map[FindingLocation]int `json:"findings_per_location,omitempty"`
+	InvalidFindings     int                     `json:"invalid_findings"`
+	FindingsDropped     int                     `json:"findings_dropped"`
+	FindingsDowngraded  int                     `json:"findings_downgraded"`
+	HallucinationRate   map[string]float64      `json:"hallucination_rate,omitempty"`
+	CarriedFindings     int                     `json:"carried_findings,omitempty"`
+	CarriedResolved     int                     `json:"carried_resolved,omitempty"`
+	SimilarMerged       int                     `json:"similar_merged"`
+	WaivedFindings      int                     `json:"waived_findings,omitempty"`
+}
+
+// GenerateJSON renders the consolidated review as an indented JSONReport.
+// stats and diffResult may be nil.
+func GenerateJSON(consolidated *ConsolidatedReview, stats *ConsolidationStats, diffResult *DiffResult) (string, error) {
+	report := JSONReport{
+		Verdict:        consolidated.Verdict,
+		VerdictReasons: consolidated.VerdictReasons,
+		GeneratedAt:    time.Now().UTC(),
+		Findings:       consolidated.Findings,
+		Waived:         consolidated.Waived,
+		Agents:         make([]JSONAgentResult, 0, len(consolidated.AgentResults)),
+		Lifecycle:      consolidated.Lifecycle,
+	}
+	if report.Findings == nil {
+		report.Findings = []*Finding{}
+	}
+	for _, ar := range consolidated.AgentResults {
+		jr := JSONAgentResult{Agent: ar.Agent, Persona: ar.Persona, DurationMS: ar.Duration.Milliseconds()}
+		if ar.Err != nil {
+			jr.Error = ar.Err.Error()
+		} else if ar.Result != nil {
+			jr.Verdict = ar.Result.Verdict
+			jr.Findings = len(ar.Result.Findings)
+		}
+		report.Agents = append(report.Agents, jr)
+	}
+	if stats != nil {
+		report.Stats = &JSONStats{
+			TotalInputFindings:  stats.TotalInputFindings,
+			UniqueFindings:      stats.UniqueFindings,
+			DuplicatesRemoved:   stats.DuplicatesRemoved,
+			SeverityEscalations: stats.SeverityEscalations,
+			OverlapRate:         stats.OverlapRate,
+			FindingsPerAgent:    stats.FindingsPerAgent,
+			FindingsPerPersona:  stats.FindingsPerPersona,
+			FindingsPerSeverity: stats.FindingsPerSeverity,
+			FindingsPerLocation: stats.FindingsPerLocation,
+			InvalidFindings:     stats.InvalidFindings,
+			FindingsDropped:     stats.FindingsDropped,
+			FindingsDowngraded:  stats.FindingsDowngraded,
+			HallucinationRate:   stats.HallucinationRate,
+			CarriedFindings:     stats.CarriedFindings,
+			CarriedResolved:     stats.CarriedResolved,
+			SimilarMerged:       stats.SimilarMerged,
+			WaivedFindings:      stats.WaivedFindings,
+		}
+	}
+	if diffResult != nil {
+		ds := diffResult.Stats
+		report.Diff = &ds
+		report.Since = diffResult.Since
+		report.Target = targetLabel(diffResult.Target)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("review: report: encoding JSON: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// SARIF 2.1.0 document types. Only the properties Raven fills are modelled.
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name,omitempty"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	RuleIndex    int                `json:"ruleIndex"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations,omitempty"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+	Properties   map[string]any     `json:"properties,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status,omitempty"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifLevel maps a Severity to a SARIF result level.
+func sarifLevel(s Severity) string {
+	switch s {
+	case SeverityCritical, SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifRuleID derives a rule ID from a finding category: lowercased, with
+// runs of non-alphanumeric characters replaced by "-". An empty category
+// becomes "general".
+func sarifRuleID(category string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(category)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+			dash = false
+			continue
+		}
+		if sb.Len() > 0 && !dash {
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+	id := strings.TrimSuffix(sb.String(), "-")
+	if id == "" {
+		return "general"
+	}
+	return id
+}
+
+// GenerateSARIF renders the consolidated findings as a SARIF 2.1.0 log with
+// one run. Each finding category becomes a rule whose default level is that
+// of its most severe finding; each finding becomes a result with its
+// severity, agents, suggestion, and diff location in the result properties.
+// Waived findings are included as results with an accepted external
+// suppression carrying the waiver's reason.
+func GenerateSARIF(consolidated *ConsolidatedReview) (string, error) {
+	all := slices.Concat(consolidated.Findings, consolidated.Waived)
+	ruleIndex := make(map[string]int)
+	var rules []sarifRule
+	for _, f := range all {
+		id := sarifRuleID(f.Category)
+		idx, ok := ruleIndex[id]
+		if !ok {
+			name := strings.TrimSpace(f.Category)
+			if name == "" {
+				name = id
+			}
+			ruleIndex[id] = len(rules)
+			rules = append(rules, sarifRule{
+				ID:                   id,
+				Name:                 name,
+				ShortDescription:     sarifMessage{Text: "Raven review finding: " + name},
+				DefaultConfiguration: sarifConfiguration{Level: sarifLevel(f.Severity)},
+			})
+			continue
+		}
+		if lvl := sarifLevel(f.Severity); sarifLevelRank(lvl) > sarifLevelRank(rules[idx].DefaultConfiguration.Level) {
+			rules[idx].DefaultConfiguration.Level = lvl
+		}
+	}
+	if rules == nil {
+		rules = []sarifRule{}
+	}
+
+	results := make([]sarifResult, 0, len(all))
+	for _, f := range all {
+		id := sarifRuleID(f.Category)
+		res := sarifResult{
+			RuleID:    id,
+			RuleIndex: ruleIndex[id],
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: findingMessage(f)},
+			Properties: map[string]any{
+				"severity": string(f.Severity),
+				"agents":   splitAgents(f.Agent),
+			},
+		}
+		if f.Suggestion != "" {
+			res.Properties["suggestion"] = f.Suggestion
+		}
+		if f.Location != "" {
+			res.Properties["diffLocation"] = string(f.Location)
+		}
+		if f.Confidence > 0 {
+			res.Properties["confidence"] = f.Confidence
+		}
+		if len(f.Personas) > 0 {
+			res.Properties["personas"] = f.Personas
+		}
+		if f.Fingerprint != "" {
+			res.Properties["fingerprint"] = f.Fingerprint
+		}
+		if f.Waiver != nil {
+			res.Suppressions = []sarifSuppression{{
+				Kind:          "external",
+				Status:        "accepted",
+				Justification: f.Waiver.Reason,
+			}}
+		}
+		if f.File != "" {
+			loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: normalizeFindingPath(f.File)},
+			}}
+			if f.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line}
+			}
+			res.Locations = []sarifLocation{loc}
+		}
+		results = append(results, res)
+	}
+
+	doc := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "Raven",
+				Version:        buildinfo.Version,
+				InformationURI: "https://github.com/AbdelazizMoustafa10m/Raven",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("review: report: encoding SARIF: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// sarifLevelRank orders SARIF levels: error > warning > note.
+func sarifLevelRank(level string) int {
+	switch level {
+	case "error":
+		return 3
+	case "warning":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// splitAgents splits a consolidated "claude, codex" attribution into names.
+func splitAgents(attribution string) []string {
+	agents := []string{}
+	for _, a := range strings.Split(attribution, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			agents = append(agents, a)
+		}
+	}
+	sort.Strings(agents)
+	return agents
+}
+
+// findingMessage returns the description of f followed by its suggestion.
+func findingMessage(f *Finding) string {
+	msg := strings.TrimSpace(f.Description)
+	if s := strings.TrimSpace(f.Suggestion); s != "" {
+		msg += "\nSuggestion: " + s
+	}
+	return msg
+}
+
+// annotationCommand maps a Severity to a GitHub Actions workflow command.
+func annotationCommand(s Severity) string {
+	switch s {
+	case SeverityCritical, SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// GenerateAnnotations renders each finding as a GitHub Actions workflow
+// command, e.g. "::error file=main.go,line=12,title=[high] bug::message",
+// which GitHub shows as an annotation on the changed line.
+func GenerateAnnotations(consolidated *ConsolidatedReview) string {
+	var sb strings.Builder
+	for _, f := range consolidated.Findings {
+		var props []string
+		if f.File != "" {
+			props = append(props, "file="+escapeAnnotationProperty(normalizeFindingPath(f.File)))
+			if f.Line > 0 {
+				props = append(props, fmt.Sprintf("line=%d", f.Line))
+			}
+		}
+		title := fmt.Sprintf("[%s] %s", f.Severity, strings.TrimSpace(f.Category))
+		if f.Agent != "" {
+			title += " (" + f.Agent + ")"
+		}
+		props = append(props, "title="+escapeAnnotationProperty(strings.TrimSpace(title)))
+
+		fmt.Fprintf(&sb, "::%s %s::%s\n",
+			annotationCommand(f.Severity),
+			strings.Join(props, ","),
+			escapeAnnotationData(findingMessage(f)),
+		)
+	}
+	return sb.String()
+}
+
+// escapeAnnotationData escapes a workflow command message.
+func escapeAnnotationData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeAnnotationProperty escapes a workflow command property value.
+func escapeAnnotationProperty(s string) string {
+	s = escapeAnnotationData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
@@ -0,0 +1,261 @@
+package review
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// formatTestReview returns a consolidated review with findings across two
+// categories, one file-level finding, and one errored agent.
+func formatTestReview() *ConsolidatedReview {
+	findings := []*Finding{
+		{Severity: SeverityCritical, Category: "Security", File: "./auth/login.go", Line: 42,
+			Description: "SQL injection", Suggestion: "Use placeholders", Agent: "codex, claude", Location: LocationInDiff},
+		{Severity: SeverityLow, Category: "security", File: "auth/token.go", Line: 7, Description: "Weak: 50% entropy, really", Agent: "claude"},
+		{Severity: SeverityMedium, Category: "Error handling", File: "main.go", Line: 0, Description: "Errors ignored\nin two places", Agent: "codex"},
+	}
+	agents := []AgentReviewResult{
+		{Agent: "claude", Result: &ReviewResult{Verdict: VerdictBlocking, Findings: make([]Finding, 2)}, Duration: 1500 * time.Millisecond},
+		{Agent: "codex", Err: errors.New("rate limited"), Duration: time.Second},
+	}
+	return makeConsolidatedReview(VerdictBlocking, findings, agents)
+}
+
+func TestParseReportFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		in   string
+		want ReportFormat
+	}{
+		{"", FormatMarkdown},
+		{"markdown", FormatMarkdown},
+		{"JSON", FormatJSON},
+		{" sarif ", FormatSARIF},
+		{"github-annotations", FormatGitHubAnnotations},
+	} {
+		got, err := ParseReportFormat(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	_, err := ParseReportFormat("html")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "markdown, json, sarif, github-annotations")
+}
+
+func TestGenerateSARIF(t *testing.T) {
+	t.Parallel()
+
+	out, err := GenerateSARIF(formatTestReview())
+	require.NoError(t, err)
+
+	var doc sarifLog
+	require.NoError(t, json.Unmarshal([]byte(out), &doc))
+	assert.Equal(t, "2.1.0", doc.Version)
+	assert.Contains(t, out, `"$schema": "https://json.schemastore.org/sarif-2.1.0.json"`)
+	require.Len(t, doc.Runs, 1)
+
+	driver := doc.Runs[0].Tool.Driver
+	assert.Equal(t, "Raven", driver.Name)
+	require.Len(t, driver.Rules, 2)
+	assert.Equal(t, "security", driver.Rules[0].ID)
+	assert.Equal(t, "error", driver.Rules[0].DefaultConfiguration.Level)
+	assert.Equal(t, "error-handling", driver.Rules[1].ID)
+	assert.Equal(t, "warning", driver.Rules[1].DefaultConfiguration.Level)
+
+	results := doc.Runs[0].Results
+	require.Len(t, results, 3)
+	assert.Equal(t, "security", results[0].RuleID)
+	assert.Equal(t, 0, results[0].RuleIndex)
+	assert.Equal(t, "error", results[0].Level)
+	assert.Equal(t, "SQL injection\nSuggestion: Use placeholders", results[0].Message.Text)
+	require.Len(t, results[0].Locations, 1)
+	assert.Equal(t, "auth/login.go", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 42, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, []any{"claude", "codex"}, results[0].Properties["agents"])
+	assert.Equal(t, "critical", results[0].Properties["severity"])
+	assert.Equal(t, "in_diff", results[0].Properties["diffLocation"])
+
+	assert.Equal(t, "note", results[1].Level)
+	assert.Equal(t, 0, results[1].RuleIndex)
+
+	// File-level findings have no region.
+	assert.Equal(t, 1, results[2].RuleIndex)
+	assert.Nil(t, results[2].Locations[0].PhysicalLocation.Region)
+}
+
+func TestGenerateSARIF_NoFindings(t *testing.T) {
+	t.Parallel()
+
+	out, err := GenerateSARIF(makeConsolidatedReview(VerdictApproved, nil, nil))
+	require.NoError(t, err)
+	assert.Contains(t, out, `"rules": []`)
+	assert.Contains(t, out, `"results": []`)
+}
+
+func TestGenerateAnnotations(t *testing.T) {
+	t.Parallel()
+
+	out := GenerateAnnotations(formatTestReview())
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "::error file=auth/login.go,line=42,title=[critical] Security (codex%2C claude)::SQL injection%0ASuggestion: Use placeholders", lines[0])
+	assert.Equal(t, "::notice file=auth/token.go,line=7,title=[low] security (claude)::Weak: 50%25 entropy, really", lines[1])
+	assert.Equal(t, "::warning file=main.go,title=[medium] Error handling (codex)::Errors ignored%0Ain two places", lines[2])
+}
+
+func TestGenerateJSON(t *testing.T) {
+	t.Parallel()
+
+	stats := makeStats(3, 3, 0, 0, 0)
+	stats.FindingsPerAgent = map[string]int{"claude": 2, "codex": 1}
+	out, err := GenerateJSON(formatTestReview(), stats, makeDiffResult(4, 10, 2))
+	require.NoError(t, err)
+
+	var doc struct {
+		Verdict  Verdict `json:"verdict"`
+		Findings []struct {
+			File     string `json:"file"`
+			Location string `json:"location"`
+		} `json:"findings"`
+		Agents []JSONAgentResult `json:"agents"`
+		Stats  struct {
+			UniqueFindings   int            `json:"unique_findings"`
+			FindingsPerAgent map[string]int `json:"findings_per_agent"`
+		} `json:"stats"`
+		Diff struct {
+			TotalFiles int `json:"total_files"`
+		} `json:"diff"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &doc))
+	assert.Equal(t, VerdictBlocking, doc.Verdict)
+	require.Len(t, doc.Findings, 3)
+	assert.Equal(t, "in_diff", doc.Findings[0].Location)
+	assert.Equal(t, []JSONAgentResult{
+		{Agent: "claude", Verdict: VerdictBlocking, Findings: 2, DurationMS: 1500},
+		{Agent: "codex", DurationMS: 1000, Error: "rate limited"},
+	}, doc.Agents)
+	assert.Equal(t, 3, doc.Stats.UniqueFindings)
+	assert.Equal(t, 2, doc.Stats.FindingsPerAgent["claude"])
+	assert.Equal(t, 4, doc.Diff.TotalFiles)
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	rg := NewReportGenerator(nil)
+	cr := formatTestReview()
+	stats := makeStats(3, 3, 0, 0, 0)
+	diff := makeDiffResult(1, 1, 1)
+
+	md, err := rg.Render(FormatMarkdown, cr, stats, diff)
+	require.NoError(t, err)
+	assert.Contains(t, md, "# Code Review Report")
+
+	js, err := rg.Render(FormatJSON, cr, stats, diff)
+	require.NoError(t, err)
+	assert.True(t, json.Valid([]byte(js)))
+
+	sarif, err := rg.Render(FormatSARIF, cr, stats, diff)
+	require.NoError(t, err)
+	assert.Contains(t, sarif, `"version": "2.1.0"`)
+
+	ann, err := rg.Render(FormatGitHubAnnotations, cr, stats, diff)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ann, "::error "))
+
+	_, err = rg.Render(FormatJSON, nil, stats, diff)
+	require.Error(t, err)
+	_, err = rg.Render("xml", cr, stats, diff)
+	require.Error(t, err)
+}
+
+func TestGenerateSARIF_WaivedFindingsSuppressed(t *testing.T) {
+	t.Parallel()
+
+	consolidated := formatTestReview()
+	consolidated.Waived = []*Finding{
+		{Severity: SeverityHigh, Category: "performance", File: "db.go", Line: 3, Description: "N+1 query",
+			Fingerprint: "ba9876543210", Waiver: &Waiver{Reason: "batch job only"}},
+	}
+
+	out, err := GenerateSARIF(consolidated)
+	require.NoError(t, err)
+
+	var doc sarifLog
+	require.NoError(t, json.Unmarshal([]byte(out), &doc))
+	results := doc.Runs[0].Results
+	require.Len(t, results, 4)
+	for _, res := range results[:3] {
+		assert.Empty(t, res.Suppressions)
+	}
+	waived := results[3]
+	assert.Equal(t, "performance", waived.RuleID)
+	assert.Equal(t, []sarifSuppression{{Kind: "external", Status: "accepted", Justification: "batch job only"}}, waived.Suppressions)
+	assert.Equal(t, "ba9876543210", waived.Properties["fingerprint"])
+}
+
+func TestGenerateJSON_Waived(t *testing.T) {
+	t.Parallel()
+
+	consolidated := formatTestReview()
+	consolidated.Waived = []*Finding{{Severity: SeverityHigh, Category: "performance", File: "db.go", Waiver: &Waiver{Reason: "ok"}}}
+
+	out, err := GenerateJSON(consolidated, &ConsolidationStats{WaivedFindings: 1}, nil)
+	require.NoError(t, err)
+
+	var report JSONReport
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	require.Len(t, report.Waived, 1)
+	assert.Equal(t, "ok", report.Waived[0].Waiver.Reason)
+	assert.Equal(t, 1, report.Stats.WaivedFindings)
+
+	out, err = GenerateJSON(formatTestReview(), nil, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, out, `"waived"`)
+}
+
+func TestGenerateJSON_VerdictReasons(t *testing.T) {
+	t.Parallel()
+
+	consolidated := formatTestReview()
+	consolidated.VerdictReasons = []string{"claude voted BLOCKING (weight 1, quorum 1)"}
+
+	out, err := GenerateJSON(consolidated, nil, nil)
+	require.NoError(t, err)
+
+	var report JSONReport
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	assert.Equal(t, consolidated.VerdictReasons, report.VerdictReasons)
+
+	out, err = GenerateJSON(formatTestReview(), nil, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, out, `"verdict_reasons"`)
+}
+
+func TestGenerateJSON_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	consolidated := formatTestReview()
+	consolidated.Findings[0].Status = FindingNew
+	consolidated.Lifecycle = &FindingLifecycle{Review: 2, New: 1, Resolved: 3}
+
+	out, err := GenerateJSON(consolidated, nil, nil)
+	require.NoError(t, err)
+
+	var report JSONReport
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	assert.Equal(t, consolidated.Lifecycle, report.Lifecycle)
+	assert.Equal(t, FindingNew, report.Findings[0].Status)
+
+	out, err = GenerateJSON(formatTestReview(), nil, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, out, `"lifecycle"`)
+}
@@ -0,0 +1,201 @@
+package review
+
+import (
+	"time"
+)
+
+// FindingStatus is the lifecycle status of a finding across the reviews of a
+// branch, as tracked by a FindingLedger.
+type FindingStatus string
+
+const (
+	// FindingNew marks a finding first reported by the latest review.
+	FindingNew FindingStatus = "new"
+
+	// FindingOpen marks a finding that an earlier review reported and the
+	// latest review still reports.
+	FindingOpen FindingStatus = "open"
+
+	// FindingResolved marks a finding that an earlier review reported and the
+	// latest review no longer reports.
+	FindingResolved FindingStatus = "resolved"
+
+	// FindingRegressed marks a finding that was resolved and is reported
+	// again by the latest review.
+	FindingRegressed FindingStatus = "regressed"
+)
+
+// ledgerVersion is the current FindingLedger file format version.
+const ledgerVersion = 1
+
+// LedgerEntry tracks one finding across the reviews of a branch.
+type LedgerEntry struct {
+	// Finding is the latest report of the finding.
+	Finding Finding `json:"finding"`
+
+	// Status is the finding's status after the latest review.
+	Status FindingStatus `json:"status"`
+
+	// FirstReview and LastReview are the numbers of the first and the most
+	// recent review that reported the finding.
+	FirstReview int `json:"first_review"`
+	LastReview  int `json:"last_review"`
+
+	// ResolvedReview is the number of the review that last resolved the
+	// finding, zero while it has never been resolved.
+	ResolvedReview int `json:"resolved_review,omitempty"`
+
+	// Regressions counts how often the finding was reported again after
+	// being resolved.
+	Regressions int `json:"regressions,omitempty"`
+}
+
+// FindingLedger records every finding reported on a branch and its status,
+// so that each review can be compared with the previous one: which findings
+// are new, still open, resolved, or regressed.
+type FindingLedger struct {
+	Version int    `json:"version"`
+	Branch  string `json:"branch"`
+
+	// Reviews is the number of reviews recorded in the ledger.
+	Reviews int `json:"reviews"`
+
+	// UpdatedAt is when the last review was recorded.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Entries []*LedgerEntry `json:"entries"`
+}
+
+// FindingLifecycle summarises how a review changed the findings of a branch
+// compared with the previous review recorded in its ledger.
+type FindingLifecycle struct {
+	// Review is the number of the review in the branch's ledger, starting
+	// at 1.
+	Review int `json:"review"`
+
+	// New, Open, Resolved, and Regressed count the findings in each status.
+	New       int `json:"new"`
+	Open      int `json:"open"`
+	Resolved  int `json:"resolved"`
+	Regressed int `json:"regressed"`
+
+	// ResolvedFindings are the findings this review resolved, as last
+	// reported.
+	ResolvedFindings []*Finding `json:"resolved_findings,omitempty"`
+}
+
+// NewFindingLedger creates an empty ledger for branch.
+func NewFindingLedger(branch string) *FindingLedger {
+	return &FindingLedger{Version: ledgerVersion, Branch: branch}
+}
+
+// Record adds review cr to the ledger at time at and returns how it changed
+// the branch's findings. Each finding of cr is matched to an entry by
+// fingerprint or, like a waiver, by a similar description in the same file
+// and category, so that findings survive line drift and rewording. Record
+// sets the Status of every finding in cr.Findings and cr.Lifecycle.
+//
+// Entries matching a waived finding are left unchanged: a waived finding is
+// accepted, not resolved.
+func (l *FindingLedger) Record(cr *ConsolidatedReview, at time.Time) *FindingLifecycle {
+	l.Reviews++
+	l.UpdatedAt = at.UTC()
+	lc := &FindingLifecycle{Review: l.Reviews}
+
+	matched := make(map[*LedgerEntry]bool)
+	for _, f := range cr.Waived {
+		if e := l.match(f, matched); e != nil {
+			matched[e] = true
+		}
+	}
+
+	for _, f := range cr.Findings {
+		e := l.match(f, matched)
+		switch {
+		case e == nil:
+			e = &LedgerEntry{FirstReview: l.Reviews}
+			l.Entries = append(l.Entries, e)
+			f.Status = FindingNew
+			lc.New++
+		case e.Status == FindingResolved:
+			e.Regressions++
+			f.Status = FindingRegressed
+			lc.Regressed++
+		default:
+			f.Status = FindingOpen
+			lc.Open++
+		}
+		matched[e] = true
+		e.Finding = *f
+		e.Finding.Waiver = nil
+		e.Status = f.Status
+		e.LastReview = l.Reviews
+	}
+
+	for _, e := range l.Entries {
+		if matched[e] || e.Status == FindingResolved {
+			continue
+		}
+		e.Status = FindingResolved
+		e.ResolvedReview = l.Reviews
+		e.Finding.Status = FindingResolved
+		resolved := e.Finding
+		lc.ResolvedFindings = append(lc.ResolvedFindings, &resolved)
+		lc.Resolved++
+	}
+
+	cr.Lifecycle = lc
+	return lc
+}
+
+// match returns the entry of f that is not in skip: the entry with f's
+// fingerprint, or else the first entry that f rewords (see rewordedMatch).
+// Returns nil when no entry matches.
+func (l *FindingLedger) match(f *Finding, skip map[*LedgerEntry]bool) *LedgerEntry {
+	fingerprint := f.Fingerprint
+	if fingerprint == "" {
+		fingerprint = FindingFingerprint(f)
+	}
+	for _, e := range l.Entries {
+		if !skip[e] && ledgerFingerprint(e) == fingerprint {
+			return e
+		}
+	}
+	for _, e := range l.Entries {
+		if !skip[e] && rewordedMatch(f, &e.Finding) {
+			return e
+		}
+	}
+	return nil
+}
+
+// ledgerFingerprint returns the fingerprint of the entry's finding.
+func ledgerFingerprint(e *LedgerEntry) string {
+	if e.Finding.Fingerprint != "" {
+		return e.Finding.Fingerprint
+	}
+	return FindingFingerprint(&e.Finding)
+}
+
+// Lifecycle summarises the whole ledger rather than a single review: the
+// findings in each status after the latest review, with every finding the
+// branch's reviews resolved so far. Review is the number of reviews
+// recorded.
+func (l *FindingLedger) Lifecycle() *FindingLifecycle {
+	lc := &FindingLifecycle{Review: l.Reviews}
+	for _, e := range l.Entries {
+		switch e.Status {
+		case FindingNew:
+			lc.New++
+		case FindingOpen:
+			lc.Open++
+		case FindingRegressed:
+			lc.Regressed++
+		case FindingResolved:
+			lc.Resolved++
+			resolved := e.Finding
+			lc.ResolvedFindings = append(lc.ResolvedFindings, &resolved)
+		}
+	}
+	return lc
+}
@@ -0,0 +1,191 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ledgerFinding returns a finding with its fingerprint set, as consolidation
+// leaves it.
+func ledgerFinding(file string, line int, category, description string) *Finding {
+	f := &Finding{Severity: SeverityMedium, Category: category, File: file, Line: line, Description: description}
+	f.Fingerprint = FindingFingerprint(f)
+	return f
+}
+
+func TestFindingLedger_Record(t *testing.T) {
+	ledger := NewFindingLedger("feature/login")
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// Review 1: two new findings.
+	first := &ConsolidatedReview{Findings: []*Finding{
+		ledgerFinding("auth.go", 10, "security", "Password is compared with == instead of a constant-time comparison"),
+		ledgerFinding("db.go", 40, "performance", "Query runs inside the loop for every user"),
+	}}
+	lc := ledger.Record(first, now)
+	assert.Equal(t, &FindingLifecycle{Review: 1, New: 2}, lc)
+	assert.Same(t, lc, first.Lifecycle)
+	assert.Equal(t, FindingNew, first.Findings[0].Status)
+
+	// Review 2: the security finding moved down and is still open, the
+	// performance finding is fixed, and a new finding appears.
+	second := &ConsolidatedReview{Findings: []*Finding{
+		ledgerFinding("auth.go", 14, "security", "Password is compared with == instead of a constant-time comparison"),
+		ledgerFinding("api.go", 3, "error-handling", "Error from Close is ignored"),
+	}}
+	lc = ledger.Record(second, now.Add(time.Hour))
+	assert.Equal(t, 2, lc.Review)
+	assert.Equal(t, 1, lc.New)
+	assert.Equal(t, 1, lc.Open)
+	assert.Equal(t, 1, lc.Resolved)
+	assert.Equal(t, 0, lc.Regressed)
+	require.Len(t, lc.ResolvedFindings, 1)
+	assert.Equal(t, "db.go", lc.ResolvedFindings[0].File)
+	assert.Equal(t, FindingResolved, lc.ResolvedFindings[0].Status)
+	assert.Equal(t, FindingOpen, second.Findings[0].Status)
+	assert.Equal(t, FindingNew, second.Findings[1].Status)
+
+	// Review 3: the performance finding comes back, reworded and a couple of
+	// lines down.
+	third := &ConsolidatedReview{Findings: []*Finding{
+		ledgerFinding("auth.go", 14, "security", "Password is compared with == instead of a constant-time comparison"),
+		ledgerFinding("api.go", 3, "error-handling", "Error from Close is ignored"),
+		ledgerFinding("db.go", 42, "performance", "The query runs inside the loop for each user"),
+	}}
+	lc = ledger.Record(third, now.Add(2*time.Hour))
+	assert.Equal(t, &FindingLifecycle{Review: 3, Open: 2, Regressed: 1}, lc)
+	assert.Equal(t, FindingRegressed, third.Findings[2].Status)
+
+	assert.Equal(t, 3, ledger.Reviews)
+	assert.Equal(t, now.Add(2*time.Hour), ledger.UpdatedAt)
+	require.Len(t, ledger.Entries, 3)
+	perf := ledger.Entries[1]
+	assert.Equal(t, 1, perf.FirstReview)
+	assert.Equal(t, 3, perf.LastReview)
+	assert.Equal(t, 2, perf.ResolvedReview)
+	assert.Equal(t, 1, perf.Regressions)
+	assert.Equal(t, 42, perf.Finding.Line)
+}
+
+func TestFindingLedger_Record_RewordedFarAwayIsNew(t *testing.T) {
+	ledger := NewFindingLedger("feature/login")
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	ledger.Record(&ConsolidatedReview{Findings: []*Finding{
+		ledgerFinding("db.go", 40, "performance", "Query runs inside the loop for every user"),
+	}}, now)
+
+	// A similarly worded finding elsewhere in the file is a different issue:
+	// the first one is resolved and the second is new.
+	second := &ConsolidatedReview{Findings: []*Finding{
+		ledgerFinding("db.go", 120, "performance", "Slow query runs inside the loop for every user"),
+	}}
+	lc := ledger.Record(second, now.Add(time.Hour))
+	assert.Equal(t, 1, lc.New)
+	assert.Equal(t, 1, lc.Resolved)
+	assert.Equal(t, FindingNew, second.Findings[0].Status)
+	assert.Len(t, ledger.Entries, 2)
+}
+
+func TestFindingLedger_Lifecycle(t *testing.T) {
+	ledger := NewFindingLedger("main")
+	ledger.Record(&ConsolidatedReview{Findings: []*Finding{
+		ledgerFinding("a.go", 1, "bug", "Nil map write"),
+		ledgerFinding("b.go", 2, "bug", "Off by one in the loop bound"),
+	}}, time.Now())
+	ledger.Record(&ConsolidatedReview{Findings: []*Finding{
+		ledgerFinding("a.go", 1, "bug", "Nil map write"),
+		ledgerFinding("c.go", 3, "tests", "Missing test for the error path"),
+	}}, time.Now())
+
+	lc := ledger.Lifecycle()
+	assert.Equal(t, 2, lc.Review)
+	assert.Equal(t, 1, lc.New)
+	assert.Equal(t, 1, lc.Open)
+	assert.Equal(t, 1, lc.Resolved)
+	require.Len(t, lc.ResolvedFindings, 1)
+	assert.Equal(t, "b.go", lc.ResolvedFindings[0].File)
+}
+
+func TestFindingLedger_Record_WaivedIsNotResolved(t *testing.T) {
+	ledger := NewFindingLedger("main")
+	f := ledgerFinding("auth.go", 10, "security", "Token is logged")
+	ledger.Record(&ConsolidatedReview{Findings: []*Finding{f}}, time.Now())
+
+	waived := *f
+	waived.Waiver = &Waiver{Fingerprint: f.Fingerprint, Reason: "accepted"}
+	lc := ledger.Record(&ConsolidatedReview{Waived: []*Finding{&waived}}, time.Now())
+
+	assert.Equal(t, 0, lc.Resolved)
+	assert.Equal(t, FindingNew, ledger.Entries[0].Status)
+}
+
+func TestFindingLedger_Record_Duplicates(t *testing.T) {
+	// Two distinct findings with the same fingerprint are tracked separately.
+	ledger := NewFindingLedger("main")
+	a := ledgerFinding("a.go", 1, "style", "Name is too short")
+	b := ledgerFinding("a.go", 9, "style", "Name is too short")
+	ledger.Record(&ConsolidatedReview{Findings: []*Finding{a, b}}, time.Now())
+	require.Len(t, ledger.Entries, 2)
+
+	lc := ledger.Record(&ConsolidatedReview{Findings: []*Finding{ledgerFinding("a.go", 1, "style", "Name is too short")}}, time.Now())
+	assert.Equal(t, 1, lc.Open)
+	assert.Equal(t, 1, lc.Resolved)
+}
+
+func TestReviewStateStore_Ledger(t *testing.T) {
+	store := NewReviewStateStore(t.TempDir())
+
+	ledger, err := store.LoadLedger("feature/x")
+	require.NoError(t, err)
+	assert.Equal(t, NewFindingLedger("feature/x"), ledger)
+
+	ledger.Record(&ConsolidatedReview{Findings: []*Finding{ledgerFinding("a.go", 1, "bug", "Nil map write")}}, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.NoError(t, store.SaveLedger(ledger))
+
+	loaded, err := store.LoadLedger("feature/x")
+	require.NoError(t, err)
+	assert.Equal(t, ledger, loaded)
+
+	// The ledger does not collide with the branch's review state.
+	require.NoError(t, store.Save(&ReviewState{Branch: "feature/x", HeadSHA: "abc"}))
+	state, err := store.Load("feature/x")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", state.HeadSHA)
+
+	assert.Error(t, store.SaveLedger(&FindingLedger{}))
+}
+
+func TestReviewStateStore_LedgerDistinctBranches(t *testing.T) {
+	dir := t.TempDir()
+	store := NewReviewStateStore(dir)
+
+	slash := NewFindingLedger("feature/x")
+	slash.Record(&ConsolidatedReview{Findings: []*Finding{ledgerFinding("a.go", 1, "bug", "Nil map write")}}, time.Now())
+	require.NoError(t, store.SaveLedger(slash))
+	require.NoError(t, store.SaveLedger(NewFindingLedger("feature_x")))
+	assert.FileExists(t, filepath.Join(dir, "ledger", "feature%2Fx.json"))
+
+	loaded, err := store.LoadLedger("feature/x")
+	require.NoError(t, err)
+	assert.Len(t, loaded.Entries, 1)
+	loaded, err = store.LoadLedger("feature_x")
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Entries)
+}
+
+func TestReviewStateStore_LedgerBranchMismatch(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "ledger"), 0o755))
+	data := []byte(`{"branch": "Main"}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ledger", "main.json"), data, 0o600))
+
+	_, err := NewReviewStateStore(dir).LoadLedger("main")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `file records branch "Main"`)
+}
@@ -2,15 +2,16 @@ package review
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/AbdelazizMoustafa10m/Raven/internal/agent"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/git"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/jsonutil"
 )
 
@@ -19,7 +20,7 @@ import (
 // empty for pipeline-level events (e.g. review_started, consolidated).
 type ReviewEvent struct {
 	// Type is one of: review_started, agent_started, agent_completed,
-	// agent_error, rate_limited, consolidated.
+	// agent_error, analyzers_skipped, rate_limited, consolidated.
 	Type      string
 	Agent     string
 	Message   string
@@ -27,8 +28,10 @@ type ReviewEvent struct {
 }
 
 // AgentError records a per-agent failure that did not abort the overall review.
+// Persona is set when the agent was acting as a reviewer persona.
 type AgentError struct {
 	Agent   string
+	Persona string
 	Err     error
 	Message string
 }
@@ -54,6 +57,8 @@ type ReviewOrchestrator struct {
 	concurrency   int
 	logger        *log.Logger
 	events        chan<- ReviewEvent
+	tokenBudget   int
+	tokenBudgets  map[string]int
 }
 
 // NewReviewOrchestrator creates a ReviewOrchestrator with the given
@@ -82,49 +87,90 @@ func NewReviewOrchestrator(
 	}
 }
 
+// WithTokenBudget sets the maximum estimated number of diff tokens in a
+// single review prompt, overridden per agent name by perAgent. A larger diff
+// is split into chunks that are reviewed separately and whose results are
+// merged. A budget of zero, the default, disables chunking; diffs over 100KB
+// are then truncated.
+func (ro *ReviewOrchestrator) WithTokenBudget(budget int, perAgent map[string]int) *ReviewOrchestrator {
+	ro.tokenBudget = budget
+	ro.tokenBudgets = perAgent
+	return ro
+}
+
+// agentTokenBudget returns the token budget of the named agent.
+func (ro *ReviewOrchestrator) agentTokenBudget(agentName string) int {
+	if budget, ok := ro.tokenBudgets[agentName]; ok {
+		return budget
+	}
+	return ro.tokenBudget
+}
+
+// reviewer is an agent resolved from the registry together with the persona
+// it acts as, nil for a general review.
+type reviewer struct {
+	agent   agent.Agent
+	persona *Persona
+}
+
+// label names the reviewer in logs and events: "persona (agent)" for a
+// persona, the agent name otherwise.
+func (rv reviewer) label() string {
+	if rv.persona != nil {
+		return fmt.Sprintf("%s (%s)", rv.persona.Name, rv.agent.Name())
+	}
+	return rv.agent.Name()
+}
+
 // Run executes the full review pipeline.
 //
 // Steps:
-//  1. Validate inputs and resolve agents from the registry.
-//  2. Generate a unified diff against opts.BaseBranch.
-//  3. Assign files to agents according to opts.Mode.
-//  4. Fan out review requests to agents concurrently via errgroup.
-//  5. Extract ReviewResult JSON from each agent's stdout.
-//  6. Consolidate all results.
+//  1. Validate inputs and resolve agents, or the agents of opts.Personas,
+//     from the registry.
+//  2. Generate a unified diff of opts.Target, against opts.BaseBranch, or of
+//     the commits since opts.Since for an incremental review.
+//  3. Run opts.Analyzers and keep their findings on the changed files.
+//  4. Assign files to agents according to opts.Mode, or to each persona
+//     according to its paths. Personas without matching files are skipped.
+//  5. Split each agent's diff into chunks that fit its token budget.
+//  6. Fan out review requests, one per chunk, to agents concurrently via
+//     errgroup.
+//  7. Extract ReviewResult JSON from each agent's stdout and merge the
+//     results of an agent's chunks.
+//  8. Consolidate the analyzer and agent results.
 //
-// Per-agent errors are captured in OrchestratorResult.AgentErrors and do NOT
-// abort the pipeline — review continues with the remaining agents.
+// Per-agent and per-analyzer errors are captured in
+// OrchestratorResult.AgentErrors and do NOT abort the pipeline — review
+// continues with the remaining agents.
 func (ro *ReviewOrchestrator) Run(ctx context.Context, opts ReviewOpts) (*OrchestratorResult, error) {
 	start := time.Now()
 
 	// --- Input validation ---
-	if len(opts.Agents) == 0 {
-		return nil, fmt.Errorf("review: orchestrator: at least one agent is required")
-	}
 	concurrency := opts.Concurrency
 	if concurrency <= 0 {
 		concurrency = 1
 	}
 
 	// Resolve all agents up-front so we fail fast on unknown names.
-	agents := make([]agent.Agent, 0, len(opts.Agents))
-	for _, name := range opts.Agents {
-		ag, err := ro.agentRegistry.Get(name)
-		if err != nil {
-			return nil, fmt.Errorf("review: orchestrator: resolving agent %q: %w", name, err)
-		}
-		agents = append(agents, ag)
+	reviewers, err := ro.resolveReviewers(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAnalyzers(opts.Analyzers); err != nil {
+		return nil, err
 	}
 
 	ro.emit(ReviewEvent{
 		Type:      "review_started",
-		Message:   fmt.Sprintf("starting review with %d agent(s)", len(agents)),
+		Message:   fmt.Sprintf("starting review with %d agent(s)", len(reviewers)),
 		Timestamp: time.Now(),
 	})
 
 	if ro.logger != nil {
 		ro.logger.Info("review started",
 			"agents", opts.Agents,
+			"personas", len(opts.Personas),
+			"analyzers", len(opts.Analyzers),
 			"mode", opts.Mode,
 			"base_branch", opts.BaseBranch,
 			"concurrency", concurrency,
@@ -132,40 +178,86 @@ func (ro *ReviewOrchestrator) Run(ctx context.Context, opts ReviewOpts) (*Orches
 	}
 
 	// --- Diff generation ---
-	diffResult, err := ro.diffGen.Generate(ctx, opts.BaseBranch)
+	diffResult, err := ro.generateDiff(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("review: orchestrator: generating diff: %w", err)
 	}
 
+	// --- Static analysis ---
+	// Analyzers run before the agents so that their findings can be part of
+	// the agents' prompts.
+	analyzerResults, analyzerErrors := ro.runAnalyzers(ctx, opts.Analyzers, diffResult, opts.Target)
+
 	// --- File assignment ---
-	// fileBuckets[i] is the slice of files assigned to agents[i].
-	fileBuckets := ro.assignFiles(diffResult, opts.Mode, len(agents))
+	// fileBuckets[i] is the slice of files assigned to reviewers[i].
+	fileBuckets := ro.assignReviewerFiles(diffResult, opts.Mode, reviewers)
+
+	// --- Chunking ---
+	// Each reviewer's diff is split into chunks that fit its agent's token
+	// budget; without a budget the whole diff is a single task.
+	tasks := make([][]*DiffResult, len(reviewers))
+	for i, rv := range reviewers {
+		files := fileBuckets[i]
+		if rv.persona != nil && len(files) == 0 {
+			if ro.logger != nil {
+				ro.logger.Info("persona skipped: no matching files", "persona", rv.persona.Name)
+			}
+			continue
+		}
+		chunks, warnings := ro.planChunks(rv, diffResult, files)
+		for _, w := range warnings {
+			if ro.logger != nil {
+				ro.logger.Warn("review chunking", "agent", rv.label(), "warning", w)
+			}
+		}
+		if chunks == nil {
+			if size := len(reviewerDiff(rv, diffResult, files)); size > maxDiffBytes && ro.logger != nil {
+				ro.logger.Warn("diff exceeds prompt limit and will be truncated",
+					"agent", rv.label(), "bytes", size, "limit", maxDiffBytes)
+			}
+			tasks[i] = []*DiffResult{diffResult}
+			continue
+		}
+		for j, c := range chunks {
+			tasks[i] = append(tasks[i], c.diffResult(diffResult, j+1, len(chunks)))
+		}
+		if len(chunks) > 1 && ro.logger != nil {
+			ro.logger.Info("diff split into chunks", "agent", rv.label(), "chunks", len(chunks))
+		}
+	}
 
 	// --- Parallel agent fan-out ---
 	g, gctx := errgroup.WithContext(ctx)
 	g.SetLimit(concurrency)
 
-	var mu sync.Mutex
-	var agentResults []AgentReviewResult
-	var agentErrors []AgentError
-
-	for i, ag := range agents {
-		i, ag := i, ag // capture loop variables
-		files := fileBuckets[i]
-
-		g.Go(func() error {
-			result, agErr := ro.runAgent(gctx, ag, diffResult, files, opts.Mode)
-
-			mu.Lock()
-			agentResults = append(agentResults, result)
-			if agErr != nil {
-				agentErrors = append(agentErrors, *agErr)
+	taskResults := make([][]AgentReviewResult, len(reviewers))
+	taskErrors := make([][]*AgentError, len(reviewers))
+	for i, rv := range reviewers {
+		taskResults[i] = make([]AgentReviewResult, len(tasks[i]))
+		taskErrors[i] = make([]*AgentError, len(tasks[i]))
+		for j, taskDiff := range tasks[i] {
+			i, j, rv, taskDiff := i, j, rv, taskDiff // capture loop variables
+			files := fileBuckets[i]
+			if taskDiff.Parts > 0 {
+				files = taskDiff.Files
 			}
-			mu.Unlock()
 
-			// ALWAYS return nil — per-agent errors must not abort the errgroup.
-			return nil
-		})
+			g.Go(func() error {
+				result, agErr := ro.runAgent(gctx, rv, taskDiff, files, opts.Mode)
+				if rv.persona != nil {
+					result.Persona = rv.persona.Name
+					if agErr != nil {
+						agErr.Persona = rv.persona.Name
+					}
+				}
+				// Each task writes its own slot, so no locking is needed.
+				taskResults[i][j] = result
+				taskErrors[i][j] = agErr
+
+				// ALWAYS return nil — per-agent errors must not abort the errgroup.
+				return nil
+			})
+		}
 	}
 
 	// Wait for all workers. The only non-nil error from g.Wait() would be a
@@ -174,8 +266,22 @@ func (ro *ReviewOrchestrator) Run(ctx context.Context, opts ReviewOpts) (*Orches
 		return nil, fmt.Errorf("review: orchestrator: agent workers: %w", err)
 	}
 
+	agentResults := analyzerResults
+	agentErrors := analyzerErrors
+	for i := range reviewers {
+		if len(taskResults[i]) == 0 {
+			continue
+		}
+		agentResults = append(agentResults, mergeChunkResults(taskResults[i]))
+		for _, agErr := range taskErrors[i] {
+			if agErr != nil {
+				agentErrors = append(agentErrors, *agErr)
+			}
+		}
+	}
+
 	// --- Consolidation ---
-	consolidated, stats := ro.consolidator.Consolidate(agentResults)
+	consolidated, stats := ro.consolidator.ConsolidateDiff(agentResults, diffResult)
 	consolidated.Duration = time.Since(start)
 
 	ro.emit(ReviewEvent{
@@ -206,79 +312,297 @@ func (ro *ReviewOrchestrator) Run(ctx context.Context, opts ReviewOpts) (*Orches
 // without invoking any agent. It performs diff generation and file assignment
 // so the output accurately reflects what Run() would do.
 func (ro *ReviewOrchestrator) DryRun(ctx context.Context, opts ReviewOpts) (string, error) {
-	if len(opts.Agents) == 0 {
-		return "", fmt.Errorf("review: orchestrator: at least one agent is required")
-	}
-
 	// Resolve agents to get DryRunCommand output.
-	agents := make([]agent.Agent, 0, len(opts.Agents))
-	for _, name := range opts.Agents {
-		ag, err := ro.agentRegistry.Get(name)
-		if err != nil {
-			return "", fmt.Errorf("review: orchestrator: resolving agent %q: %w", name, err)
-		}
-		agents = append(agents, ag)
+	reviewers, err := ro.resolveReviewers(opts)
+	if err != nil {
+		return "", err
+	}
+	if err := validateAnalyzers(opts.Analyzers); err != nil {
+		return "", err
 	}
 
 	// Generate diff for accurate file counts.
-	diffResult, err := ro.diffGen.Generate(ctx, opts.BaseBranch)
+	diffResult, err := ro.generateDiff(ctx, opts)
 	if err != nil {
 		return "", fmt.Errorf("review: orchestrator: generating diff for dry run: %w", err)
 	}
 
-	fileBuckets := ro.assignFiles(diffResult, opts.Mode, len(agents))
+	fileBuckets := ro.assignReviewerFiles(diffResult, opts.Mode, reviewers)
 
 	var sb strings.Builder
 	sb.WriteString("Review Plan (dry run)\n")
-	fmt.Fprintf(&sb, "Base branch: %s\n", opts.BaseBranch)
-	fmt.Fprintf(&sb, "Mode: %s\n", opts.Mode)
-	fmt.Fprintf(&sb, "Agents: %d\n", len(agents))
+	if opts.Target.Kind != "" {
+		fmt.Fprintf(&sb, "Target: %s\n", opts.Target)
+	} else {
+		fmt.Fprintf(&sb, "Base branch: %s\n", opts.BaseBranch)
+	}
+	if opts.Since != "" && opts.Target.Kind == "" {
+		fmt.Fprintf(&sb, "Incremental: commits since %s\n", opts.Since)
+	}
+	if len(opts.Personas) > 0 {
+		fmt.Fprintf(&sb, "Personas: %d\n", len(reviewers))
+	} else {
+		fmt.Fprintf(&sb, "Mode: %s\n", opts.Mode)
+		fmt.Fprintf(&sb, "Agents: %d\n", len(reviewers))
+	}
 
-	for i, ag := range agents {
+	for i, rv := range reviewers {
 		files := fileBuckets[i]
+		if rv.persona != nil && len(files) == 0 {
+			fmt.Fprintf(&sb, "  %s: skipped, no matching files\n", rv.label())
+			continue
+		}
 		// Build a representative RunOpts so DryRunCommand can produce a useful string.
 		runOpts := agent.RunOpts{
 			Prompt:  "(review prompt)",
 			WorkDir: ".",
 		}
-		cmd := ag.DryRunCommand(runOpts)
-		fmt.Fprintf(&sb, "  %s: %d files, command: %s\n", ag.Name(), len(files), cmd)
+		if rv.persona != nil {
+			runOpts.Model = rv.persona.Model
+		}
+		cmd := rv.agent.DryRunCommand(runOpts)
+		chunks, warnings := ro.planChunks(rv, diffResult, files)
+		if len(chunks) > 1 {
+			fmt.Fprintf(&sb, "  %s: %d files in %d chunks (token budget %d), command: %s\n",
+				rv.label(), len(files), len(chunks), ro.agentTokenBudget(rv.agent.Name()), cmd)
+			for j, c := range chunks {
+				fmt.Fprintf(&sb, "    chunk %d: %d files, ~%d tokens\n", j+1, len(c.Files), c.Tokens)
+			}
+		} else {
+			fmt.Fprintf(&sb, "  %s: %d files, command: %s\n", rv.label(), len(files), cmd)
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(&sb, "    warning: %s\n", w)
+		}
+		if chunks == nil {
+			if size := len(reviewerDiff(rv, diffResult, files)); size > maxDiffBytes {
+				fmt.Fprintf(&sb, "    warning: the diff (%d KB) exceeds %d KB and will be truncated; set a token budget to review it in chunks\n",
+					size/1024, maxDiffBytes/1024)
+			}
+		}
+	}
+
+	if len(opts.Analyzers) > 0 {
+		fmt.Fprintf(&sb, "Analyzers: %d\n", len(opts.Analyzers))
+		for _, a := range opts.Analyzers {
+			fmt.Fprintf(&sb, "  %s (%s): %s\n", a.Name, a.Format, a.Command)
+		}
 	}
 
 	return sb.String(), nil
 }
 
+// resolveReviewers resolves the agents of opts.Personas, or opts.Agents when
+// no personas are set, from the registry.
+func (ro *ReviewOrchestrator) resolveReviewers(opts ReviewOpts) ([]reviewer, error) {
+	if len(opts.Personas) > 0 {
+		reviewers := make([]reviewer, 0, len(opts.Personas))
+		for i := range opts.Personas {
+			persona := &opts.Personas[i]
+			if err := persona.Validate(); err != nil {
+				return nil, fmt.Errorf("review: orchestrator: %w", err)
+			}
+			ag, err := ro.agentRegistry.Get(persona.Agent)
+			if err != nil {
+				return nil, fmt.Errorf("review: orchestrator: resolving agent %q of persona %q: %w", persona.Agent, persona.Name, err)
+			}
+			reviewers = append(reviewers, reviewer{agent: ag, persona: persona})
+		}
+		return reviewers, nil
+	}
+
+	if len(opts.Agents) == 0 {
+		return nil, fmt.Errorf("review: orchestrator: at least one agent is required")
+	}
+	reviewers := make([]reviewer, 0, len(opts.Agents))
+	for _, name := range opts.Agents {
+		ag, err := ro.agentRegistry.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("review: orchestrator: resolving agent %q: %w", name, err)
+		}
+		reviewers = append(reviewers, reviewer{agent: ag})
+	}
+	return reviewers, nil
+}
+
+// validateAnalyzers checks every analyzer up-front so that a misconfigured
+// analyzer fails the review before any agent runs.
+func validateAnalyzers(analyzers []Analyzer) error {
+	for _, a := range analyzers {
+		if err := a.Validate(); err != nil {
+			return fmt.Errorf("review: orchestrator: %w", err)
+		}
+	}
+	return nil
+}
+
+// runAnalyzers runs the analyzers one after another. Only their findings on
+// the diff's files are kept: analyzers check the whole tree, but the review
+// is about the change. An analyzer votes, and its context findings are handed
+// to the prompt builder, only for findings on lines inside the diff's hunks,
+// so that pre-existing issues in a touched file do not block the change.
+//
+// Analyzers inspect the checked-out working tree, so they are skipped with a
+// warning when target reviews other revisions (a range, a single commit, or
+// a pull request).
+func (ro *ReviewOrchestrator) runAnalyzers(
+	ctx context.Context,
+	analyzers []Analyzer,
+	diff *DiffResult,
+	target git.DiffTarget,
+) ([]AgentReviewResult, []AgentError) {
+	if len(analyzers) == 0 {
+		return nil, nil
+	}
+	if target.Kind == git.TargetRange || target.Kind == git.TargetCommit {
+		ro.emit(ReviewEvent{
+			Type:      "analyzers_skipped",
+			Message:   fmt.Sprintf("skipping %d analyzer(s): they check the working tree, not %s", len(analyzers), target),
+			Timestamp: time.Now(),
+		})
+		if ro.logger != nil {
+			ro.logger.Warn("skipping analyzers for a target other than the working tree",
+				"analyzers", len(analyzers),
+				"target", target.String(),
+			)
+		}
+		return nil, nil
+	}
+
+	changed := make(map[string]bool, len(diff.Files))
+	for _, f := range diff.Files {
+		changed[f.Path] = true
+	}
+	validator := NewFindingValidator(diff, "")
+
+	var results []AgentReviewResult
+	var agentErrors []AgentError
+	var contextFindings []*Finding
+	for _, a := range analyzers {
+		name := a.AgentName()
+		start := time.Now()
+		ro.emit(ReviewEvent{
+			Type:      "agent_started",
+			Agent:     name,
+			Message:   fmt.Sprintf("analyzer %s running: %s", a.Name, a.Command),
+			Timestamp: time.Now(),
+		})
+
+		findings, err := a.Run(ctx, "")
+		duration := time.Since(start)
+		if err != nil {
+			msg := fmt.Sprintf("analyzer %s failed: %v", a.Name, err)
+			ro.emit(ReviewEvent{
+				Type:      "agent_error",
+				Agent:     name,
+				Message:   msg,
+				Timestamp: time.Now(),
+			})
+			if ro.logger != nil {
+				ro.logger.Error("analyzer failed", "analyzer", a.Name, "error", err)
+			}
+			agentErrors = append(agentErrors, AgentError{Agent: name, Err: err, Message: msg})
+			results = append(results, AgentReviewResult{Agent: name, Duration: duration, Err: err})
+			continue
+		}
+
+		kept := make([]Finding, 0, len(findings))
+		var inDiff []Finding
+		for _, f := range findings {
+			if !changed[normalizeFindingPath(f.File)] {
+				continue
+			}
+			kept = append(kept, f)
+			if validator.InDiff(&f) {
+				inDiff = append(inDiff, f)
+			}
+		}
+		verdict := analyzerVerdict(inDiff)
+		results = append(results, AgentReviewResult{
+			Agent:    name,
+			Result:   &ReviewResult{Findings: kept, Verdict: verdict},
+			Duration: duration,
+		})
+		if a.Context {
+			for i := range inDiff {
+				contextFindings = append(contextFindings, &inDiff[i])
+			}
+		}
+
+		ro.emit(ReviewEvent{
+			Type:  "agent_completed",
+			Agent: name,
+			Message: fmt.Sprintf("analyzer %s completed: %d finding(s) on changed files, %d in the diff, verdict %s",
+				a.Name, len(kept), len(inDiff), verdict),
+			Timestamp: time.Now(),
+		})
+		if ro.logger != nil {
+			ro.logger.Info("analyzer completed",
+				"analyzer", a.Name,
+				"findings", len(findings),
+				"on_changed_files", len(kept),
+				"in_diff", len(inDiff),
+				"duration", duration,
+			)
+		}
+	}
+
+	ro.promptBuilder.WithAnalyzerFindings(contextFindings)
+	return results, agentErrors
+}
+
+// generateDiff diffs opts.Target when set, opts.Since..HEAD for an
+// incremental review, and opts.BaseBranch...HEAD otherwise.
+func (ro *ReviewOrchestrator) generateDiff(ctx context.Context, opts ReviewOpts) (*DiffResult, error) {
+	if opts.Target.Kind != "" {
+		return ro.diffGen.GenerateTarget(ctx, opts.Target)
+	}
+	if opts.Since != "" {
+		return ro.diffGen.GenerateSince(ctx, opts.BaseBranch, opts.Since)
+	}
+	return ro.diffGen.Generate(ctx, opts.BaseBranch)
+}
+
 // runAgent executes a single agent review pass: builds the prompt, calls
 // agent.Run(), extracts JSON, and validates the result. It returns both the
 // AgentReviewResult (always populated) and an optional *AgentError (non-nil
 // when the agent produced an error or unusable output).
 func (ro *ReviewOrchestrator) runAgent(
 	ctx context.Context,
-	ag agent.Agent,
+	rv reviewer,
 	diff *DiffResult,
 	files []ChangedFile,
 	mode ReviewMode,
 ) (AgentReviewResult, *AgentError) {
 	agentStart := time.Now()
+	ag := rv.agent
 	agentName := ag.Name()
 
 	ro.emit(ReviewEvent{
 		Type:      "agent_started",
 		Agent:     agentName,
-		Message:   fmt.Sprintf("agent %s starting review of %d file(s)", agentName, len(files)),
+		Message:   fmt.Sprintf("agent %s starting review of %d file(s)%s", rv.label(), len(files), chunkLabel(diff)),
 		Timestamp: time.Now(),
 	})
 
 	if ro.logger != nil {
 		ro.logger.Info("agent review started",
-			"agent", agentName,
+			"agent", rv.label(),
 			"files", len(files),
 			"mode", mode,
+			"part", diff.Part,
+			"parts", diff.Parts,
 		)
 	}
 
 	// Build the review prompt.
-	prompt, err := ro.promptBuilder.BuildForAgent(ctx, agentName, diff, files, mode)
+	var prompt string
+	var err error
+	if rv.persona != nil {
+		prompt, err = ro.promptBuilder.BuildForPersona(ctx, *rv.persona, diff, files, mode)
+	} else {
+		prompt, err = ro.promptBuilder.BuildForAgent(ctx, agentName, diff, files, mode)
+	}
 	if err != nil {
 		agErr := &AgentError{
 			Agent:   agentName,
@@ -302,6 +626,9 @@ func (ro *ReviewOrchestrator) runAgent(
 	runOpts := agent.RunOpts{
 		Prompt: prompt,
 	}
+	if rv.persona != nil {
+		runOpts.Model = rv.persona.Model
+	}
 	result, err := ag.Run(ctx, runOpts)
 	duration := time.Since(agentStart)
 
@@ -462,6 +789,105 @@ func (ro *ReviewOrchestrator) runAgent(
 	}, nil
 }
 
+// planChunks splits the diff of the reviewer's files into chunks that fit
+// the token budget of its agent. It returns nil chunks when the agent has no
+// budget, in which case the whole diff is reviewed in one prompt.
+func (ro *ReviewOrchestrator) planChunks(
+	rv reviewer,
+	diff *DiffResult,
+	files []ChangedFile,
+) ([]DiffChunk, []string) {
+	budget := ro.agentTokenBudget(rv.agent.Name())
+	if budget <= 0 {
+		return nil, nil
+	}
+	return ChunkDiff(diff.FullDiff, files, budget)
+}
+
+// reviewerDiff returns the diff text the reviewer's prompt carries without
+// chunking: the part of the diff on its files for a persona, the full diff
+// otherwise.
+func reviewerDiff(rv reviewer, diff *DiffResult, files []ChangedFile) string {
+	if rv.persona != nil {
+		return filterDiff(diff.FullDiff, files)
+	}
+	return diff.FullDiff
+}
+
+// mergeChunkResults merges the results of an agent's reviews of the chunks
+// of one diff. The findings of every successful chunk are kept and the
+// verdict aggregates their verdicts. A failed chunk leaves part of the diff
+// unreviewed, so its error is kept as ChunkErr for the verdict policy to
+// apply [review.policy] agent_failure. The merged result only carries Err
+// when every chunk failed.
+func mergeChunkResults(results []AgentReviewResult) AgentReviewResult {
+	if len(results) == 1 {
+		return results[0]
+	}
+
+	merged := AgentReviewResult{Agent: results[0].Agent, Persona: results[0].Persona}
+	var combined ReviewResult
+	var chunkErr error
+	verdicts := make([]Verdict, 0, len(results))
+	outputs := make([]string, 0, len(results))
+	for _, r := range results {
+		merged.Duration += r.Duration
+		if r.RawOutput != "" {
+			outputs = append(outputs, r.RawOutput)
+		}
+		if r.Err != nil || r.Result == nil {
+			if chunkErr == nil {
+				chunkErr = r.Err
+				if chunkErr == nil {
+					chunkErr = errors.New("review: chunk produced no result")
+				}
+			}
+			continue
+		}
+		if r.ChunkErr != nil && chunkErr == nil {
+			chunkErr = r.ChunkErr
+		}
+		combined.Findings = append(combined.Findings, r.Result.Findings...)
+		verdicts = append(verdicts, r.Result.Verdict)
+	}
+	merged.RawOutput = strings.Join(outputs, "\n")
+	if len(verdicts) == 0 {
+		merged.Err = chunkErr
+		return merged
+	}
+
+	combined.Verdict = AggregateVerdicts(verdicts)
+	if combined.Findings == nil {
+		combined.Findings = []Finding{}
+	}
+	merged.Result = &combined
+	merged.ChunkErr = chunkErr
+	return merged
+}
+
+// chunkLabel describes the chunk a diff is, e.g. " (part 2 of 3)", or
+// returns "" for a whole diff.
+func chunkLabel(diff *DiffResult) string {
+	if diff.Parts <= 1 {
+		return ""
+	}
+	return fmt.Sprintf(" (part %d of %d)", diff.Part, diff.Parts)
+}
+
+// assignReviewerFiles builds per-reviewer file buckets: each persona gets the
+// changed files matching its paths, and agents without a persona get their
+// files from assignFiles.
+func (ro *ReviewOrchestrator) assignReviewerFiles(diff *DiffResult, mode ReviewMode, reviewers []reviewer) [][]ChangedFile {
+	if len(reviewers) == 0 || reviewers[0].persona == nil {
+		return ro.assignFiles(diff, mode, len(reviewers))
+	}
+	buckets := make([][]ChangedFile, len(reviewers))
+	for i, rv := range reviewers {
+		buckets[i] = rv.persona.FilterFiles(diff.Files)
+	}
+	return buckets
+}
+
 // assignFiles builds per-agent file buckets according to the review mode.
 // In "all" mode every agent receives all files. In "split" mode files are
 // partitioned across agents via SplitFiles. The returned slice always has
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -1166,3 +1167,487 @@ func BenchmarkOrchestrator_TwoAgents(b *testing.B) {
 		})
 	}
 }
+
+// ---------------------------------------------------------------------------
+// Incremental review
+// ---------------------------------------------------------------------------
+
+func TestRun_IncrementalDiffsSince(t *testing.T) {
+	t.Parallel()
+	mock := buildMockGit()
+	ro := buildOrchestrator(t, mock, map[string]string{
+		"claude": approvedReviewJSON,
+	}, nil)
+
+	result, err := ro.Run(context.Background(), ReviewOpts{
+		Agents:     []string{"claude"},
+		BaseBranch: "main",
+		Since:      "abc1234",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "abc1234..HEAD", mock.diffFilesBase)
+	assert.Equal(t, "abc1234", result.DiffResult.Since)
+	assert.Equal(t, "main", result.DiffResult.BaseBranch)
+}
+
+func TestRun_Target(t *testing.T) {
+	t.Parallel()
+	mock := buildMockGit()
+	ro := buildOrchestrator(t, mock, map[string]string{
+		"claude": approvedReviewJSON,
+	}, nil)
+
+	target := git.DiffTarget{Kind: git.TargetStaged}
+	result, err := ro.Run(context.Background(), ReviewOpts{
+		Agents:     []string{"claude"},
+		BaseBranch: "main",
+		Since:      "abc1234",
+		Target:     target,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, target, mock.diffTarget)
+	assert.Equal(t, target, result.DiffResult.Target)
+	assert.Empty(t, result.DiffResult.Since)
+
+	plan, err := ro.DryRun(context.Background(), ReviewOpts{
+		Agents: []string{"claude"},
+		Target: git.DiffTarget{Kind: git.TargetCommit, Ref: "abc1234"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, plan, "Target: commit abc1234\n")
+	assert.NotContains(t, plan, "Base branch")
+}
+
+func TestDryRun_Incremental(t *testing.T) {
+	t.Parallel()
+	ro := buildOrchestrator(t, buildMockGit(), map[string]string{
+		"claude": approvedReviewJSON,
+	}, nil)
+
+	plan, err := ro.DryRun(context.Background(), ReviewOpts{
+		Agents:     []string{"claude"},
+		BaseBranch: "main",
+		Since:      "abc1234",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, plan, "Incremental: commits since abc1234")
+}
+
+// ---------------------------------------------------------------------------
+// Personas
+// ---------------------------------------------------------------------------
+
+// personaTestGit returns a mock git client with one auth file, one test file,
+// and one README, each with its own diff section.
+func personaTestGit() *mockGitClient {
+	return &mockGitClient{
+		diffFilesResult: []git.DiffEntry{
+			{Path: "internal/auth/login.go", Status: "M"},
+			{Path: "internal/auth/login_test.go", Status: "A"},
+			{Path: "README.md", Status: "M"},
+		},
+		numStatResult: []git.NumStatEntry{
+			{Path: "internal/auth/login.go", Added: 1, Deleted: 1},
+			{Path: "internal/auth/login_test.go", Added: 1},
+			{Path: "README.md", Added: 1},
+		},
+		unifiedResult: "diff --git a/internal/auth/login.go b/internal/auth/login.go\n" +
+			"--- a/internal/auth/login.go\n+++ b/internal/auth/login.go\n@@ -1 +1 @@\n-old\n+new\n" +
+			"diff --git a/internal/auth/login_test.go b/internal/auth/login_test.go\n" +
+			"--- /dev/null\n+++ b/internal/auth/login_test.go\n@@ -0,0 +1 @@\n+test\n" +
+			"diff --git a/README.md b/README.md\n" +
+			"--- a/README.md\n+++ b/README.md\n@@ -1 +1 @@\n-a\n+b\n",
+	}
+}
+
+func TestRun_Personas(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var calls []agent.RunOpts
+	registry := agent.NewRegistry()
+	mock := agent.NewMockAgent("claude")
+	mock.RunFunc = func(_ context.Context, opts agent.RunOpts) (*agent.RunResult, error) {
+		mu.Lock()
+		calls = append(calls, opts)
+		mu.Unlock()
+		out := `{"findings":[{"severity":"low","category":"testing","file":"internal/auth/login_test.go","line":1,"description":"no table test"}],"verdict":"APPROVED"}`
+		if strings.Contains(opts.Prompt, "## Reviewer Focus: security") {
+			out = `{"findings":[{"severity":"high","category":"security","file":"internal/auth/login.go","line":1,"description":"weak hash"}],"verdict":"CHANGES_NEEDED"}`
+		}
+		return &agent.RunResult{Stdout: out}, nil
+	}
+	require.NoError(t, registry.Register(mock))
+
+	diffGen, err := NewDiffGenerator(personaTestGit(), ReviewConfig{}, nil)
+	require.NoError(t, err)
+	ro := NewReviewOrchestrator(registry, diffGen, NewPromptBuilder(ReviewConfig{}, nil), NewConsolidator(nil), 2, nil, nil)
+
+	result, err := ro.Run(context.Background(), ReviewOpts{
+		BaseBranch:  "main",
+		Concurrency: 2,
+		Personas: []Persona{
+			{Name: "security", Agent: "claude", Model: "opus", Paths: []string{"internal/auth/**"}},
+			{Name: "tests", Agent: "claude", Paths: []string{"*_test.go"}},
+			{Name: "api", Agent: "claude", Paths: []string{"api/**"}},
+		},
+	})
+	require.NoError(t, err)
+
+	// The api persona has no matching files and is skipped.
+	require.Len(t, calls, 2)
+	require.Len(t, result.Consolidated.AgentResults, 2)
+	for _, opts := range calls {
+		if strings.Contains(opts.Prompt, "## Reviewer Focus: security") {
+			assert.Equal(t, "opus", opts.Model)
+			assert.Contains(t, opts.Prompt, "internal/auth/login.go")
+		} else {
+			assert.Empty(t, opts.Model)
+			assert.Contains(t, opts.Prompt, "## Reviewer Focus: tests")
+			assert.NotContains(t, opts.Prompt, "diff --git a/internal/auth/login.go")
+		}
+		assert.NotContains(t, opts.Prompt, "README.md")
+	}
+
+	assert.Equal(t, VerdictChangesNeeded, result.Consolidated.Verdict)
+	require.Len(t, result.Consolidated.Findings, 2)
+	assert.Equal(t, []string{"security"}, result.Consolidated.Findings[0].Personas)
+	assert.Equal(t, []string{"tests"}, result.Consolidated.Findings[1].Personas)
+	assert.Equal(t, "claude", result.Consolidated.Findings[0].Agent)
+	assert.Equal(t, map[string]int{"security": 1, "tests": 1}, result.Stats.FindingsPerPersona)
+	for _, ar := range result.Consolidated.AgentResults {
+		assert.Contains(t, []string{"security", "tests"}, ar.Persona)
+	}
+}
+
+func TestRun_PersonaUnknownAgent(t *testing.T) {
+	t.Parallel()
+	ro := buildOrchestrator(t, personaTestGit(), map[string]string{"claude": approvedReviewJSON}, nil)
+
+	_, err := ro.Run(context.Background(), ReviewOpts{
+		BaseBranch: "main",
+		Personas:   []Persona{{Name: "security", Agent: "gemini"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"gemini" of persona "security"`)
+
+	_, err = ro.Run(context.Background(), ReviewOpts{
+		BaseBranch: "main",
+		Personas:   []Persona{{Name: "security"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "agent is required")
+}
+
+func TestDryRun_Personas(t *testing.T) {
+	t.Parallel()
+	ro := buildOrchestrator(t, personaTestGit(), map[string]string{"claude": approvedReviewJSON}, nil)
+
+	plan, err := ro.DryRun(context.Background(), ReviewOpts{
+		BaseBranch: "main",
+		Personas: []Persona{
+			{Name: "security", Agent: "claude", Paths: []string{"internal/**"}},
+			{Name: "api", Agent: "claude", Paths: []string{"api/**"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, plan, "Personas: 2")
+	assert.Contains(t, plan, "security (claude): 2 files")
+	assert.Contains(t, plan, "api (claude): skipped, no matching files")
+	assert.NotContains(t, plan, "Mode:")
+}
+
+func TestRun_Analyzers(t *testing.T) {
+	t.Parallel()
+
+	var prompts []string
+	var mu sync.Mutex
+	registry := agent.NewRegistry()
+	mock := agent.NewMockAgent("claude")
+	mock.RunFunc = func(_ context.Context, opts agent.RunOpts) (*agent.RunResult, error) {
+		mu.Lock()
+		prompts = append(prompts, opts.Prompt)
+		mu.Unlock()
+		return &agent.RunResult{Stdout: approvedReviewJSON}, nil
+	}
+	require.NoError(t, registry.Register(mock))
+	diffGen, err := NewDiffGenerator(personaTestGit(), ReviewConfig{}, nil)
+	require.NoError(t, err)
+	ro := NewReviewOrchestrator(registry, diffGen, NewPromptBuilder(ReviewConfig{}, nil), NewConsolidator(nil), 2, nil, nil)
+
+	vetCmd := writeAnalyzerOutput(t, "internal/auth/login.go:1:1: result of fmt.Sprintf call not used\n"+
+		"internal/other/unchanged.go:5:1: unreachable code\n")
+	result, err := ro.Run(context.Background(), ReviewOpts{
+		Agents:      []string{"claude"},
+		Mode:        ReviewModeAll,
+		BaseBranch:  "main",
+		Concurrency: 1,
+		Analyzers: []Analyzer{
+			{Name: "vet", Command: vetCmd + "; exit 1", Format: AnalyzerFormatLine, Context: true},
+			{Name: "lint", Command: "echo '{\"Issues\":[]}'", Format: AnalyzerFormatGolangci},
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.AgentErrors)
+
+	// Only the finding on a changed file is kept.
+	require.Len(t, result.Consolidated.Findings, 1)
+	f := result.Consolidated.Findings[0]
+	assert.Equal(t, "analyzer:vet", f.Agent)
+	assert.Equal(t, "internal/auth/login.go", f.File)
+	assert.Equal(t, VerdictChangesNeeded, result.Consolidated.Verdict)
+	assert.Equal(t, 1, result.Stats.FindingsPerAgent["analyzer:vet"])
+	require.Len(t, result.Consolidated.AgentResults, 3)
+	assert.Equal(t, "analyzer:vet", result.Consolidated.AgentResults[0].Agent)
+	assert.Equal(t, "analyzer:lint", result.Consolidated.AgentResults[1].Agent)
+
+	// The vet findings are context for the agents.
+	require.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0], "## Static Analysis Findings")
+	assert.Contains(t, prompts[0], "internal/auth/login.go:1: result of fmt.Sprintf call not used")
+	assert.NotContains(t, prompts[0], "unreachable code")
+}
+
+func TestRun_Analyzers_OutsideHunksDoNotVote(t *testing.T) {
+	t.Parallel()
+
+	var prompts []string
+	var mu sync.Mutex
+	registry := agent.NewRegistry()
+	mock := agent.NewMockAgent("claude")
+	mock.RunFunc = func(_ context.Context, opts agent.RunOpts) (*agent.RunResult, error) {
+		mu.Lock()
+		prompts = append(prompts, opts.Prompt)
+		mu.Unlock()
+		return &agent.RunResult{Stdout: approvedReviewJSON}, nil
+	}
+	require.NoError(t, registry.Register(mock))
+	diffGen, err := NewDiffGenerator(personaTestGit(), ReviewConfig{}, nil)
+	require.NoError(t, err)
+	ro := NewReviewOrchestrator(registry, diffGen, NewPromptBuilder(ReviewConfig{}, nil), NewConsolidator(nil), 2, nil, nil)
+
+	// Line 40 of login.go is in a changed file but outside its only hunk:
+	// a pre-existing issue that the change did not introduce.
+	vetCmd := writeAnalyzerOutput(t, "internal/auth/login.go:40:1: result of fmt.Sprintf call not used\n")
+	result, err := ro.Run(context.Background(), ReviewOpts{
+		Agents:      []string{"claude"},
+		Mode:        ReviewModeAll,
+		BaseBranch:  "main",
+		Concurrency: 1,
+		Analyzers:   []Analyzer{{Name: "vet", Command: vetCmd + "; exit 1", Format: AnalyzerFormatLine, Context: true}},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Consolidated.AgentResults, 2)
+	vet := result.Consolidated.AgentResults[0]
+	assert.Equal(t, "analyzer:vet", vet.Agent)
+	require.NotNil(t, vet.Result)
+	assert.Len(t, vet.Result.Findings, 1)
+	assert.Equal(t, VerdictApproved, vet.Result.Verdict)
+	assert.Equal(t, VerdictApproved, result.Consolidated.Verdict)
+
+	require.Len(t, prompts, 1)
+	assert.NotContains(t, prompts[0], "## Static Analysis Findings")
+}
+
+func TestRun_Analyzers_SkippedForOtherRevisions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		target git.DiffTarget
+	}{
+		{name: "range", target: git.DiffTarget{Kind: git.TargetRange, Ref: "main...feature"}},
+		{name: "commit", target: git.DiffTarget{Kind: git.TargetCommit, Ref: "abc1234"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			events := make(chan ReviewEvent, 64)
+			ro := buildOrchestrator(t, personaTestGit(), map[string]string{"claude": approvedReviewJSON}, events)
+
+			vetCmd := writeAnalyzerOutput(t, "internal/auth/login.go:1:1: result of fmt.Sprintf call not used\n")
+			result, err := ro.Run(context.Background(), ReviewOpts{
+				Agents:    []string{"claude"},
+				Mode:      ReviewModeAll,
+				Target:    tt.target,
+				Analyzers: []Analyzer{{Name: "vet", Command: vetCmd + "; exit 1", Format: AnalyzerFormatLine}},
+			})
+			require.NoError(t, err)
+
+			require.Len(t, result.Consolidated.AgentResults, 1)
+			assert.Equal(t, "claude", result.Consolidated.AgentResults[0].Agent)
+			assert.Empty(t, result.Consolidated.Findings)
+			assert.Equal(t, VerdictApproved, result.Consolidated.Verdict)
+
+			close(events)
+			var skipped bool
+			for e := range events {
+				if e.Type == "analyzers_skipped" {
+					skipped = true
+				}
+			}
+			assert.True(t, skipped, "expected an analyzers_skipped event")
+		})
+	}
+}
+
+func TestRun_AnalyzerFailure_CapturedNotAborted(t *testing.T) {
+	t.Parallel()
+	ro := buildOrchestrator(t, personaTestGit(), map[string]string{"claude": approvedReviewJSON}, nil)
+
+	result, err := ro.Run(context.Background(), ReviewOpts{
+		Agents:     []string{"claude"},
+		BaseBranch: "main",
+		Analyzers:  []Analyzer{{Name: "vet", Command: "exit 3", Format: AnalyzerFormatLine}},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.AgentErrors, 1)
+	assert.Equal(t, "analyzer:vet", result.AgentErrors[0].Agent)
+	assert.Equal(t, VerdictChangesNeeded, result.Consolidated.Verdict)
+}
+
+func TestRun_InvalidAnalyzer(t *testing.T) {
+	t.Parallel()
+	ro := buildOrchestrator(t, personaTestGit(), map[string]string{"claude": approvedReviewJSON}, nil)
+
+	_, err := ro.Run(context.Background(), ReviewOpts{
+		Agents:     []string{"claude"},
+		BaseBranch: "main",
+		Analyzers:  []Analyzer{{Name: "vet", Command: "go vet ./...", Format: "xml"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `analyzer "vet": unknown format "xml"`)
+}
+
+func TestDryRun_Analyzers(t *testing.T) {
+	t.Parallel()
+	ro := buildOrchestrator(t, personaTestGit(), map[string]string{"claude": approvedReviewJSON}, nil)
+
+	plan, err := ro.DryRun(context.Background(), ReviewOpts{
+		Agents:     []string{"claude"},
+		Mode:       ReviewModeAll,
+		BaseBranch: "main",
+		Analyzers:  []Analyzer{{Name: "vet", Command: "go vet ./...", Format: AnalyzerFormatLine}},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, plan, "Analyzers: 1")
+	assert.Contains(t, plan, "  vet (line): go vet ./...")
+}
+
+func TestRun_TokenBudgetChunks(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var prompts []string
+	registry := agent.NewRegistry()
+	mock := agent.NewMockAgent("claude")
+	mock.RunFunc = func(_ context.Context, opts agent.RunOpts) (*agent.RunResult, error) {
+		mu.Lock()
+		prompts = append(prompts, opts.Prompt)
+		mu.Unlock()
+		out := `{"findings":[{"severity":"low","category":"style","file":"internal/auth/login.go","line":1,"description":"naming"}],"verdict":"APPROVED"}`
+		if strings.Contains(opts.Prompt, "+test") {
+			out = `{"findings":[{"severity":"high","category":"testing","file":"internal/auth/login_test.go","line":1,"description":"no assertions"}],"verdict":"CHANGES_NEEDED"}`
+		}
+		return &agent.RunResult{Stdout: out}, nil
+	}
+	require.NoError(t, registry.Register(mock))
+	diffGen, err := NewDiffGenerator(personaTestGit(), ReviewConfig{}, nil)
+	require.NoError(t, err)
+	ro := NewReviewOrchestrator(registry, diffGen, NewPromptBuilder(ReviewConfig{}, nil), NewConsolidator(nil), 2, nil, nil).
+		WithTokenBudget(1000, map[string]int{"claude": 80})
+
+	result, err := ro.Run(context.Background(), ReviewOpts{
+		Agents:      []string{"claude"},
+		Mode:        ReviewModeAll,
+		BaseBranch:  "main",
+		Concurrency: 2,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.AgentErrors)
+
+	// Two prompts, each with only its part of the diff.
+	require.Len(t, prompts, 2)
+	if !strings.Contains(prompts[0], "This is part 1;") {
+		prompts[0], prompts[1] = prompts[1], prompts[0]
+	}
+	assert.Contains(t, prompts[0], "split into 2 parts. This is part 1;")
+	assert.Contains(t, prompts[0], "+++ b/README.md")
+	assert.NotContains(t, prompts[0], "+test")
+	assert.Contains(t, prompts[1], "This is part 2;")
+	assert.Contains(t, prompts[1], "+test")
+	assert.NotContains(t, prompts[1], "+++ b/README.md")
+
+	// The chunk results are merged into one result per agent.
+	require.Len(t, result.Consolidated.AgentResults, 1)
+	assert.Len(t, result.Consolidated.Findings, 2)
+	assert.Equal(t, VerdictChangesNeeded, result.Consolidated.Verdict)
+}
+
+func TestRun_NoTokenBudget_SinglePrompt(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	registry := agent.NewRegistry()
+	mock := agent.NewMockAgent("claude")
+	mock.RunFunc = func(_ context.Context, opts agent.RunOpts) (*agent.RunResult, error) {
+		calls.Add(1)
+		assert.NotContains(t, opts.Prompt, "split into")
+		return &agent.RunResult{Stdout: approvedReviewJSON}, nil
+	}
+	require.NoError(t, registry.Register(mock))
+	diffGen, err := NewDiffGenerator(personaTestGit(), ReviewConfig{}, nil)
+	require.NoError(t, err)
+	ro := NewReviewOrchestrator(registry, diffGen, NewPromptBuilder(ReviewConfig{}, nil), NewConsolidator(nil), 2, nil, nil).
+		WithTokenBudget(0, map[string]int{"codex": 10})
+
+	_, err = ro.Run(context.Background(), ReviewOpts{
+		Agents:     []string{"claude"},
+		Mode:       ReviewModeAll,
+		BaseBranch: "main",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestDryRun_TokenBudgetChunks(t *testing.T) {
+	t.Parallel()
+	ro := buildOrchestrator(t, personaTestGit(), map[string]string{"claude": approvedReviewJSON}, nil)
+
+	ro.WithTokenBudget(80, nil)
+	plan, err := ro.DryRun(context.Background(), ReviewOpts{
+		Agents:     []string{"claude"},
+		Mode:       ReviewModeAll,
+		BaseBranch: "main",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, plan, "claude: 3 files in 2 chunks (token budget 80), command:")
+	assert.Contains(t, plan, "    chunk 1: 2 files, ~")
+	assert.Contains(t, plan, "    chunk 2: 1 files, ~")
+
+	ro.WithTokenBudget(30, nil)
+	plan, err = ro.DryRun(context.Background(), ReviewOpts{
+		Agents:     []string{"claude"},
+		Mode:       ReviewModeAll,
+		BaseBranch: "main",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, plan, "    warning: internal/auth/login.go (~47 tokens) exceeds the 30-token budget and is truncated")
+}
+
+func TestDryRun_TruncationWarning(t *testing.T) {
+	t.Parallel()
+	gc := personaTestGit()
+	gc.unifiedResult += chunkTestSection("README.md", maxDiffBytes/10)
+	ro := buildOrchestrator(t, gc, map[string]string{"claude": approvedReviewJSON}, nil)
+
+	plan, err := ro.DryRun(context.Background(), ReviewOpts{
+		Agents:     []string{"claude"},
+		Mode:       ReviewModeAll,
+		BaseBranch: "main",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, plan, "KB and will be truncated; set a token budget to review it in chunks")
+}
@@ -0,0 +1,232 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// Persona is a specialised reviewer: an agent reviewing the diff with a
+// particular focus (e.g. security or API design), its own prompt and rules,
+// and only the changed files its path globs select.
+type Persona struct {
+	// Name identifies the persona in prompts, events, and reports
+	// (e.g. "security").
+	Name string
+
+	// Agent is the name of the agent that runs the persona's review.
+	Agent string
+
+	// Model overrides the agent's configured model. Empty keeps it.
+	Model string
+
+	// PromptFile is a markdown file describing what the persona focuses on.
+	// Its content is added to the review prompt; empty uses a generic focus
+	// on the persona's name.
+	PromptFile string
+
+	// RuleFiles are extra rule files included in the persona's prompt after
+	// the rules from the [review] rules_dir.
+	RuleFiles []string
+
+	// Paths are the globs of the changed files the persona reviews. Empty
+	// means every changed file. See MatchPathGlob for the syntax.
+	Paths []string
+}
+
+// Matches reports whether the persona reviews the file at filePath.
+func (p Persona) Matches(filePath string) bool {
+	if len(p.Paths) == 0 {
+		return true
+	}
+	for _, pattern := range p.Paths {
+		if MatchPathGlob(pattern, filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterFiles returns the files the persona reviews, in their original order.
+func (p Persona) FilterFiles(files []ChangedFile) []ChangedFile {
+	if len(p.Paths) == 0 {
+		return files
+	}
+	var matched []ChangedFile
+	for _, f := range files {
+		if p.Matches(f.Path) {
+			matched = append(matched, f)
+		}
+	}
+	return matched
+}
+
+// Validate checks that the persona names an agent and that its globs are
+// well formed.
+func (p Persona) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("persona name is required")
+	}
+	if p.Agent == "" {
+		return fmt.Errorf("persona %q: agent is required", p.Name)
+	}
+	for _, pattern := range p.Paths {
+		if err := ValidatePathGlob(pattern); err != nil {
+			return fmt.Errorf("persona %q: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// loadContext reads the persona's prompt file and rule files. Unlike the
+// project-wide brief and rules, the files are named explicitly, so a missing
+// file is an error.
+func (p Persona) loadContext() (string, []string, error) {
+	var focus string
+	if p.PromptFile != "" {
+		if err := validatePath(p.PromptFile); err != nil {
+			return "", nil, fmt.Errorf("review: persona %s: prompt path: %w", p.Name, err)
+		}
+		data, err := os.ReadFile(p.PromptFile)
+		if err != nil {
+			return "", nil, fmt.Errorf("review: persona %s: reading prompt %q: %w", p.Name, p.PromptFile, err)
+		}
+		focus = strings.TrimSpace(string(data))
+	}
+
+	rules := make([]string, 0, len(p.RuleFiles))
+	for _, file := range p.RuleFiles {
+		if err := validatePath(file); err != nil {
+			return "", nil, fmt.Errorf("review: persona %s: rule path: %w", p.Name, err)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", nil, fmt.Errorf("review: persona %s: reading rule %q: %w", p.Name, file, err)
+		}
+		rules = append(rules, string(data))
+	}
+	return focus, rules, nil
+}
+
+// MatchPathGlob reports whether the slash-separated filePath matches pattern.
+// Each pattern segment is matched with path.Match, and a "**" segment matches
+// any number of directories, so "internal/**/*.go" matches every Go file
+// below internal. A pattern without a slash matches the file's base name in
+// any directory ("*_test.go"). Malformed patterns match nothing.
+func MatchPathGlob(pattern, filePath string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	filePath = strings.TrimPrefix(filePath, "./")
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(filePath))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(filePath, "/"))
+}
+
+// matchSegments matches path segments against pattern segments, with "**"
+// matching zero or more segments.
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// ValidatePathGlob returns an error if pattern is empty or has a malformed
+// segment.
+func ValidatePathGlob(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("empty path glob")
+	}
+	for _, seg := range strings.Split(pattern, "/") {
+		if _, err := path.Match(seg, ""); err != nil {
+			return fmt.Errorf("invalid path glob %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// filterDiff returns the sections of the unified diff that change one of
+// files, so that a persona's prompt only carries the diff it reviews.
+func filterDiff(diff string, files []ChangedFile) string {
+	paths := make(map[string]bool, len(files)*2)
+	for _, f := range files {
+		paths[f.Path] = true
+		if f.OldPath != "" {
+			paths[f.OldPath] = true
+		}
+	}
+
+	var sb strings.Builder
+	for _, section := range splitDiffSections(diff) {
+		for _, p := range diffSectionPaths(section) {
+			if paths[p] {
+				sb.WriteString(section)
+				break
+			}
+		}
+	}
+	return sb.String()
+}
+
+// splitDiffSections splits a unified diff into one section per file, each
+// starting at its "diff --git" line. Text before the first section is dropped.
+func splitDiffSections(diff string) []string {
+	var sections []string
+	start := -1
+	offset := 0
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			if start >= 0 {
+				sections = append(sections, diff[start:offset])
+			}
+			start = offset
+		}
+		offset += len(line)
+	}
+	if start >= 0 {
+		sections = append(sections, diff[start:])
+	}
+	return sections
+}
+
+// diffSectionPaths returns the old and new paths of a diff section, read from
+// its "---"/"+++" lines, or from the "diff --git a/... b/..." header for
+// sections without them (binary files, pure renames).
+func diffSectionPaths(section string) []string {
+	var paths []string
+	lines := strings.Split(section, "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "@@") {
+			break
+		}
+		if strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "+++ ") {
+			if p := diffPath(line[4:]); p != "" {
+				paths = append(paths, p)
+			}
+		}
+	}
+	if len(paths) > 0 {
+		return paths
+	}
+	header := strings.TrimPrefix(lines[0], "diff --git ")
+	if i := strings.LastIndex(header, " b/"); i >= 0 {
+		paths = append(paths, diffPath(header[:i]), diffPath(header[i+1:]))
+	}
+	return paths
+}
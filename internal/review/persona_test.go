@@ -0,0 +1,106 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchPathGlob(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"*.go", "main.go", true},
+		{"*_test.go", "internal/auth/login_test.go", true},
+		{"*_test.go", "internal/auth/login.go", false},
+		{"internal/auth/*", "internal/auth/login.go", true},
+		{"internal/auth/*", "internal/auth/oauth/google.go", false},
+		{"internal/**", "internal/auth/oauth/google.go", true},
+		{"internal/**/*.go", "internal/main.go", true},
+		{"internal/**/*.go", "internal/a/b/c.go", true},
+		{"internal/**/*.go", "cmd/main.go", false},
+		{"**/api/*.proto", "services/user/api/user.proto", true},
+		{"./docs/**", "docs/guide.md", true},
+		{"docs/**", "docs", true},
+		{"[", "main.go", false},
+	} {
+		assert.Equal(t, tt.want, MatchPathGlob(tt.pattern, tt.path), "%s vs %s", tt.pattern, tt.path)
+	}
+}
+
+func TestValidatePathGlob(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidatePathGlob("internal/**/*.go"))
+	require.Error(t, ValidatePathGlob(""))
+	require.Error(t, ValidatePathGlob("internal/[a-"))
+}
+
+func TestPersona_FilterFiles(t *testing.T) {
+	t.Parallel()
+
+	files := []ChangedFile{{Path: "api/user.go"}, {Path: "main.go"}, {Path: "api/user_test.go"}}
+
+	p := Persona{Name: "api", Paths: []string{"api/*.go"}}
+	got := p.FilterFiles(files)
+	require.Len(t, got, 2)
+	assert.Equal(t, "api/user.go", got[0].Path)
+	assert.Equal(t, "api/user_test.go", got[1].Path)
+
+	assert.Len(t, Persona{Name: "all"}.FilterFiles(files), 3)
+	assert.Empty(t, Persona{Name: "none", Paths: []string{"docs/**"}}.FilterFiles(files))
+}
+
+func TestPersona_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Persona{Name: "security", Agent: "claude", Paths: []string{"**/*.go"}}.Validate())
+	require.Error(t, Persona{Agent: "claude"}.Validate())
+	require.Error(t, Persona{Name: "security"}.Validate())
+	err := Persona{Name: "security", Agent: "claude", Paths: []string{"["}}.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `persona "security"`)
+}
+
+func TestPersona_LoadContext(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	prompt := filepath.Join(dir, "security.md")
+	rule := filepath.Join(dir, "owasp.md")
+	require.NoError(t, os.WriteFile(prompt, []byte("Look for injection.\n"), 0o600))
+	require.NoError(t, os.WriteFile(rule, []byte("Rule: no raw SQL"), 0o600))
+
+	focus, rules, err := Persona{Name: "security", PromptFile: prompt, RuleFiles: []string{rule}}.loadContext()
+	require.NoError(t, err)
+	assert.Equal(t, "Look for injection.", focus)
+	assert.Equal(t, []string{"Rule: no raw SQL"}, rules)
+
+	_, _, err = Persona{Name: "security", PromptFile: filepath.Join(dir, "missing.md")}.loadContext()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reading prompt")
+}
+
+func TestFilterDiff(t *testing.T) {
+	t.Parallel()
+
+	diff := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-x\n+y\n" +
+		"diff --git a/old.go b/new.go\nsimilarity index 100%\nrename from old.go\nrename to new.go\n" +
+		"diff --git a/img.png b/img.png\nBinary files a/img.png and b/img.png differ\n"
+
+	got := filterDiff(diff, []ChangedFile{{Path: "a.go"}})
+	assert.Equal(t, "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-x\n+y\n", got)
+
+	got = filterDiff(diff, []ChangedFile{{Path: "new.go", OldPath: "old.go"}, {Path: "img.png"}})
+	assert.NotContains(t, got, "a/a.go")
+	assert.Contains(t, got, "rename to new.go")
+	assert.Contains(t, got, "Binary files")
+
+	assert.Empty(t, filterDiff(diff, nil))
+}
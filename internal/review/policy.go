@@ -0,0 +1,276 @@
+package review
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AgentFailureMode controls how a failed agent counts toward the verdict.
+type AgentFailureMode string
+
+const (
+	// AgentFailureChangesNeeded makes a failed agent count as
+	// CHANGES_NEEDED: a review that is incomplete cannot approve. This is
+	// the default.
+	AgentFailureChangesNeeded AgentFailureMode = "changes_needed"
+
+	// AgentFailureIgnore leaves failed agents out of the verdict, which is
+	// reached by the agents that completed.
+	AgentFailureIgnore AgentFailureMode = "ignore"
+
+	// AgentFailureBlocking makes a failed agent block the review.
+	AgentFailureBlocking AgentFailureMode = "blocking"
+)
+
+// validAgentFailureModes is the set of all known AgentFailureMode values.
+var validAgentFailureModes = map[AgentFailureMode]bool{
+	"":                        true,
+	AgentFailureChangesNeeded: true,
+	AgentFailureIgnore:        true,
+	AgentFailureBlocking:      true,
+}
+
+// VerdictPolicy decides the consolidated verdict from the agents' votes and
+// the findings. The zero value reproduces the default behaviour: the worst
+// verdict of any agent wins, and a failed agent counts as CHANGES_NEEDED.
+type VerdictPolicy struct {
+	// Weights maps persona, agent, or "analyzer:<name>" names to the weight
+	// of their vote. A persona's weight takes precedence over its agent's;
+	// reviewers not listed weigh 1, and a weight of 0 makes one advisory.
+	Weights map[string]float64
+
+	// BlockingQuorum is the total weight of BLOCKING votes needed for a
+	// BLOCKING verdict. BLOCKING votes short of it count as CHANGES_NEEDED.
+	// Zero means 1.
+	BlockingQuorum float64
+
+	// ChangesQuorum is the total weight of CHANGES_NEEDED or BLOCKING votes
+	// needed for a CHANGES_NEEDED verdict. Zero means 1.
+	ChangesQuorum float64
+
+	// BlockSeverity blocks the review when any finding is at or above this
+	// severity, however the agents voted. Empty disables the rule.
+	BlockSeverity Severity
+
+	// ApproveBelow approves the review when no finding is at or above this
+	// severity, disregarding CHANGES_NEEDED votes, and requires changes when
+	// there is one. Empty leaves the decision to the votes.
+	ApproveBelow Severity
+
+	// AgentFailure controls how failed agents count. Empty means
+	// AgentFailureChangesNeeded.
+	AgentFailure AgentFailureMode
+}
+
+// Validate checks that the policy's weights and quorums are not negative and
+// its severities and failure mode are known.
+func (p VerdictPolicy) Validate() error {
+	for name, w := range p.Weights {
+		if w < 0 {
+			return fmt.Errorf("verdict policy: weight of %q must not be negative, got %g", name, w)
+		}
+	}
+	if p.BlockingQuorum < 0 {
+		return fmt.Errorf("verdict policy: blocking quorum must not be negative, got %g", p.BlockingQuorum)
+	}
+	if p.ChangesQuorum < 0 {
+		return fmt.Errorf("verdict policy: changes quorum must not be negative, got %g", p.ChangesQuorum)
+	}
+	if p.BlockSeverity != "" && !validSeverities[p.BlockSeverity] {
+		return fmt.Errorf("verdict policy: invalid block severity %q: must be one of info, low, medium, high, critical", p.BlockSeverity)
+	}
+	if p.ApproveBelow != "" && !validSeverities[p.ApproveBelow] {
+		return fmt.Errorf("verdict policy: invalid approve-below severity %q: must be one of info, low, medium, high, critical", p.ApproveBelow)
+	}
+	if !validAgentFailureModes[p.AgentFailure] {
+		return fmt.Errorf("verdict policy: unknown agent failure mode %q: must be one of changes_needed, ignore, blocking", p.AgentFailure)
+	}
+	return nil
+}
+
+// verdictVote is one agent's say in the verdict. failed is set when the
+// agent produced no usable result, in which case verdict is unused.
+type verdictVote struct {
+	agent   string
+	persona string
+	verdict Verdict
+	failed  bool
+}
+
+// reviewer returns the name the vote is reported under: the persona when the
+// review used personas, the agent otherwise.
+func (v verdictVote) reviewer() string {
+	if v.persona != "" {
+		return v.persona
+	}
+	return v.agent
+}
+
+// weight returns the weight of v: the weight of its persona if listed, else
+// that of its agent, else 1.
+func (p VerdictPolicy) weight(v verdictVote) float64 {
+	if w, ok := p.Weights[v.persona]; ok && v.persona != "" {
+		return w
+	}
+	if w, ok := p.Weights[v.agent]; ok {
+		return w
+	}
+	return 1
+}
+
+// quorum returns q, or 1 when q is unset.
+func quorum(q float64) float64 {
+	if q <= 0 {
+		return 1
+	}
+	return q
+}
+
+// decide applies the policy. findings are the open findings after waivers
+// were applied and waived the number of waived findings. It returns the
+// verdict and the reasons that led to it, one sentence each.
+func (p VerdictPolicy) decide(votes []verdictVote, findings []*Finding, waived int) (Verdict, []string) {
+	var reasons []string
+	verdict := VerdictApproved
+	raise := func(v Verdict, reason string) {
+		verdict = AggregateVerdicts([]Verdict{verdict, v})
+		reasons = append(reasons, reason)
+	}
+
+	// --- Agent votes ---
+	var blocking, changes, approving, failed []string
+	var blockingWeight, changesWeight float64
+	for _, v := range votes {
+		if v.failed {
+			failed = append(failed, v.reviewer())
+			continue
+		}
+		switch v.verdict {
+		case VerdictBlocking:
+			blocking = append(blocking, v.reviewer())
+			blockingWeight += p.weight(v)
+			changesWeight += p.weight(v)
+		case VerdictChangesNeeded:
+			changes = append(changes, v.reviewer())
+			changesWeight += p.weight(v)
+		default:
+			approving = append(approving, v.reviewer())
+		}
+	}
+
+	voted := VerdictApproved
+	blockingQuorum, changesQuorum := quorum(p.BlockingQuorum), quorum(p.ChangesQuorum)
+	switch {
+	case len(blocking) > 0 && blockingWeight >= blockingQuorum:
+		voted = VerdictBlocking
+		reasons = append(reasons, fmt.Sprintf("%s voted BLOCKING (weight %s, quorum %s)",
+			joinReviewers(blocking), formatWeight(blockingWeight), formatWeight(blockingQuorum)))
+	case len(blocking)+len(changes) > 0 && changesWeight >= changesQuorum:
+		voted = VerdictChangesNeeded
+		if len(blocking) > 0 {
+			reasons = append(reasons, fmt.Sprintf("%s voted BLOCKING, short of the blocking quorum (weight %s of %s)",
+				joinReviewers(blocking), formatWeight(blockingWeight), formatWeight(blockingQuorum)))
+		}
+		reasons = append(reasons, fmt.Sprintf("%s voted CHANGES_NEEDED or worse (weight %s, quorum %s)",
+			joinReviewers(append(blocking, changes...)), formatWeight(changesWeight), formatWeight(changesQuorum)))
+	case len(blocking)+len(changes) > 0:
+		reasons = append(reasons, fmt.Sprintf("%s voted CHANGES_NEEDED or worse, short of the quorum (weight %s of %s)",
+			joinReviewers(append(blocking, changes...)), formatWeight(changesWeight), formatWeight(changesQuorum)))
+	case len(approving) > 0:
+		reasons = append(reasons, fmt.Sprintf("%s voted APPROVED", joinReviewers(approving)))
+	}
+
+	// The agents judged the findings before waivers were applied, so their
+	// votes count for no more than the remaining findings justify.
+	if waived > 0 {
+		remaining := VerdictApproved
+		for _, f := range findings {
+			remaining = AggregateVerdicts([]Verdict{remaining, severityVerdict(f.Severity)})
+		}
+		if voted != VerdictApproved && AggregateVerdicts([]Verdict{remaining, voted}) != remaining {
+			voted = remaining
+			reasons = append(reasons, fmt.Sprintf("%d waived finding(s) disregarded; the remaining findings call for %s", waived, remaining))
+		}
+	}
+
+	// --- Severity thresholds ---
+	if p.ApproveBelow != "" {
+		n := countAtOrAbove(findings, p.ApproveBelow)
+		if n > 0 {
+			raise(VerdictChangesNeeded, fmt.Sprintf("%d finding(s) at or above %s", n, p.ApproveBelow))
+		} else if voted == VerdictChangesNeeded {
+			voted = VerdictApproved
+			reasons = append(reasons, fmt.Sprintf("no findings at or above %s, so CHANGES_NEEDED votes are disregarded", p.ApproveBelow))
+		}
+	}
+	verdict = AggregateVerdicts([]Verdict{verdict, voted})
+
+	if p.BlockSeverity != "" {
+		if n := countAtOrAbove(findings, p.BlockSeverity); n > 0 {
+			raise(VerdictBlocking, fmt.Sprintf("%d finding(s) at or above %s block the review", n, p.BlockSeverity))
+		}
+	}
+
+	// --- Carried findings ---
+	// Findings of the previous review that the new commits did not address
+	// keep the review from being approved. With ApproveBelow set they count
+	// like any other finding.
+	if p.ApproveBelow == "" {
+		carried := 0
+		for _, f := range findings {
+			if f.Carried && f.Severity != SeverityInfo {
+				carried++
+			}
+		}
+		if carried > 0 {
+			raise(VerdictChangesNeeded, fmt.Sprintf("%d finding(s) from the previous review are still open", carried))
+		}
+	}
+
+	// --- Agent failures ---
+	if len(failed) > 0 {
+		switch p.AgentFailure {
+		case AgentFailureIgnore:
+			reasons = append(reasons, fmt.Sprintf("%s failed and %s ignored", joinReviewers(failed), pluralIs(len(failed))))
+		case AgentFailureBlocking:
+			raise(VerdictBlocking, fmt.Sprintf("%s failed, and failed agents block the review", joinReviewers(failed)))
+		default:
+			raise(VerdictChangesNeeded, fmt.Sprintf("%s failed, so the review is incomplete", joinReviewers(failed)))
+		}
+	}
+
+	return verdict, reasons
+}
+
+// countAtOrAbove returns the number of findings with severity s or higher.
+func countAtOrAbove(findings []*Finding, s Severity) int {
+	n := 0
+	for _, f := range findings {
+		if severityRank(f.Severity) >= severityRank(s) {
+			n++
+		}
+	}
+	return n
+}
+
+// joinReviewers lists reviewer names in sorted order.
+func joinReviewers(names []string) string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ", ")
+}
+
+// formatWeight formats a vote weight without trailing zeros.
+func formatWeight(w float64) string {
+	return strconv.FormatFloat(w, 'g', -1, 64)
+}
+
+// pluralIs returns "is" for one and "are" for several.
+func pluralIs(n int) string {
+	if n == 1 {
+		return "is"
+	}
+	return "are"
+}
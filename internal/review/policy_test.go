@@ -0,0 +1,233 @@
+package review
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerdictPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		policy  VerdictPolicy
+		wantErr string
+	}{
+		{name: "zero value", policy: VerdictPolicy{}},
+		{
+			name: "full policy",
+			policy: VerdictPolicy{
+				Weights:        map[string]float64{"claude": 2, "codex": 0},
+				BlockingQuorum: 2,
+				ChangesQuorum:  1.5,
+				BlockSeverity:  SeverityCritical,
+				ApproveBelow:   SeverityMedium,
+				AgentFailure:   AgentFailureIgnore,
+			},
+		},
+		{name: "negative weight", policy: VerdictPolicy{Weights: map[string]float64{"claude": -1}}, wantErr: `weight of "claude"`},
+		{name: "negative blocking quorum", policy: VerdictPolicy{BlockingQuorum: -1}, wantErr: "blocking quorum"},
+		{name: "negative changes quorum", policy: VerdictPolicy{ChangesQuorum: -1}, wantErr: "changes quorum"},
+		{name: "unknown block severity", policy: VerdictPolicy{BlockSeverity: "severe"}, wantErr: "block severity"},
+		{name: "unknown approve-below severity", policy: VerdictPolicy{ApproveBelow: "severe"}, wantErr: "approve-below severity"},
+		{name: "unknown failure mode", policy: VerdictPolicy{AgentFailure: "retry"}, wantErr: "agent failure mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.policy.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestConsolidate_Policy_DefaultExplainsVerdict(t *testing.T) {
+	t.Parallel()
+
+	consolidated, _ := NewConsolidator(nil).Consolidate([]AgentReviewResult{
+		makeAgentResult("claude", VerdictChangesNeeded, []Finding{
+			{Severity: SeverityHigh, Category: "bug", File: "a.go", Line: 1, Description: "Nil dereference"},
+		}, nil),
+		makeAgentResult("codex", VerdictApproved, nil, nil),
+	})
+	assert.Equal(t, VerdictChangesNeeded, consolidated.Verdict)
+	assert.Equal(t, []string{"claude voted CHANGES_NEEDED or worse (weight 1, quorum 1)"}, consolidated.VerdictReasons)
+
+	consolidated, _ = NewConsolidator(nil).Consolidate([]AgentReviewResult{
+		makeAgentResult("claude", VerdictApproved, nil, nil),
+		makeAgentResult("codex", VerdictApproved, nil, nil),
+	})
+	assert.Equal(t, VerdictApproved, consolidated.Verdict)
+	assert.Equal(t, []string{"claude, codex voted APPROVED"}, consolidated.VerdictReasons)
+}
+
+func TestConsolidate_Policy_BlockingQuorum(t *testing.T) {
+	t.Parallel()
+
+	critical := Finding{Severity: SeverityCritical, Category: "security", File: "auth.go", Line: 3, Description: "Token logged"}
+	results := []AgentReviewResult{
+		makeAgentResult("claude", VerdictBlocking, nil, nil),
+		makeAgentResult("codex", VerdictApproved, nil, nil),
+	}
+	policy := VerdictPolicy{BlockingQuorum: 2}
+
+	// One BLOCKING vote short of the quorum only asks for changes.
+	consolidated, _ := NewConsolidator(nil).WithPolicy(policy).Consolidate(results)
+	assert.Equal(t, VerdictChangesNeeded, consolidated.Verdict)
+	assert.Equal(t, []string{
+		"claude voted BLOCKING, short of the blocking quorum (weight 1 of 2)",
+		"claude voted CHANGES_NEEDED or worse (weight 1, quorum 1)",
+	}, consolidated.VerdictReasons)
+
+	// A weighted vote meets it.
+	policy.Weights = map[string]float64{"claude": 2}
+	consolidated, _ = NewConsolidator(nil).WithPolicy(policy).Consolidate(results)
+	assert.Equal(t, VerdictBlocking, consolidated.Verdict)
+	assert.Equal(t, []string{"claude voted BLOCKING (weight 2, quorum 2)"}, consolidated.VerdictReasons)
+
+	// So does a single critical finding under block_severity.
+	policy = VerdictPolicy{BlockingQuorum: 2, BlockSeverity: SeverityCritical}
+	results[0] = makeAgentResult("claude", VerdictBlocking, []Finding{critical}, nil)
+	consolidated, _ = NewConsolidator(nil).WithPolicy(policy).Consolidate(results)
+	assert.Equal(t, VerdictBlocking, consolidated.Verdict)
+	assert.Contains(t, consolidated.VerdictReasons, "1 finding(s) at or above critical block the review")
+}
+
+func TestConsolidate_Policy_ChangesQuorumAndAdvisoryWeights(t *testing.T) {
+	t.Parallel()
+
+	results := []AgentReviewResult{
+		makeAgentResult("claude", VerdictApproved, nil, nil),
+		makeAgentResult("codex", VerdictChangesNeeded, nil, nil),
+	}
+
+	consolidated, _ := NewConsolidator(nil).WithPolicy(VerdictPolicy{
+		Weights: map[string]float64{"codex": 0},
+	}).Consolidate(results)
+	assert.Equal(t, VerdictApproved, consolidated.Verdict)
+	assert.Equal(t, []string{"codex voted CHANGES_NEEDED or worse, short of the quorum (weight 0 of 1)"}, consolidated.VerdictReasons)
+
+	consolidated, _ = NewConsolidator(nil).WithPolicy(VerdictPolicy{ChangesQuorum: 2}).Consolidate(results)
+	assert.Equal(t, VerdictApproved, consolidated.Verdict)
+}
+
+func TestConsolidate_Policy_PersonaWeightsTakePrecedence(t *testing.T) {
+	t.Parallel()
+
+	security := makeAgentResult("claude", VerdictBlocking, nil, nil)
+	security.Persona = "security"
+	style := makeAgentResult("claude", VerdictBlocking, nil, nil)
+	style.Persona = "style"
+
+	consolidated, _ := NewConsolidator(nil).WithPolicy(VerdictPolicy{
+		Weights:        map[string]float64{"claude": 0, "security": 2},
+		BlockingQuorum: 2,
+	}).Consolidate([]AgentReviewResult{security, style})
+	assert.Equal(t, VerdictBlocking, consolidated.Verdict)
+	assert.Equal(t, []string{"security, style voted BLOCKING (weight 2, quorum 2)"}, consolidated.VerdictReasons)
+}
+
+func TestConsolidate_Policy_ApproveBelow(t *testing.T) {
+	t.Parallel()
+
+	policy := VerdictPolicy{ApproveBelow: SeverityMedium}
+
+	// Only low findings: the CHANGES_NEEDED vote is disregarded.
+	consolidated, _ := NewConsolidator(nil).WithPolicy(policy).Consolidate([]AgentReviewResult{
+		makeAgentResult("claude", VerdictChangesNeeded, []Finding{
+			{Severity: SeverityLow, Category: "style", File: "a.go", Line: 1, Description: "Long line"},
+		}, nil),
+	})
+	assert.Equal(t, VerdictApproved, consolidated.Verdict)
+	assert.Contains(t, consolidated.VerdictReasons, "no findings at or above medium, so CHANGES_NEEDED votes are disregarded")
+
+	// A medium finding requires changes even though every agent approved.
+	consolidated, _ = NewConsolidator(nil).WithPolicy(policy).Consolidate([]AgentReviewResult{
+		makeAgentResult("claude", VerdictApproved, []Finding{
+			{Severity: SeverityMedium, Category: "bug", File: "a.go", Line: 1, Description: "Unchecked error"},
+		}, nil),
+	})
+	assert.Equal(t, VerdictChangesNeeded, consolidated.Verdict)
+	assert.Contains(t, consolidated.VerdictReasons, "1 finding(s) at or above medium")
+}
+
+func TestConsolidate_Policy_AgentFailure(t *testing.T) {
+	t.Parallel()
+
+	results := []AgentReviewResult{
+		makeAgentResult("claude", VerdictApproved, nil, nil),
+		makeAgentResult("codex", "", nil, errors.New("timeout")),
+	}
+
+	tests := []struct {
+		mode        AgentFailureMode
+		wantVerdict Verdict
+		wantReason  string
+	}{
+		{mode: "", wantVerdict: VerdictChangesNeeded, wantReason: "codex failed, so the review is incomplete"},
+		{mode: AgentFailureChangesNeeded, wantVerdict: VerdictChangesNeeded, wantReason: "codex failed, so the review is incomplete"},
+		{mode: AgentFailureIgnore, wantVerdict: VerdictApproved, wantReason: "codex failed and is ignored"},
+		{mode: AgentFailureBlocking, wantVerdict: VerdictBlocking, wantReason: "codex failed, and failed agents block the review"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			t.Parallel()
+			consolidated, _ := NewConsolidator(nil).WithPolicy(VerdictPolicy{AgentFailure: tt.mode}).Consolidate(results)
+			assert.Equal(t, tt.wantVerdict, consolidated.Verdict)
+			assert.Contains(t, consolidated.VerdictReasons, tt.wantReason)
+		})
+	}
+}
+
+func TestConsolidate_Policy_FailedChunk(t *testing.T) {
+	t.Parallel()
+
+	// codex reviewed one chunk of the diff and failed on another.
+	partial := mergeChunkResults([]AgentReviewResult{
+		makeAgentResult("codex", VerdictApproved, nil, nil),
+		makeAgentResult("codex", "", nil, errors.New("timeout")),
+	})
+	results := []AgentReviewResult{makeAgentResult("claude", VerdictApproved, nil, nil), partial}
+
+	tests := []struct {
+		mode        AgentFailureMode
+		wantVerdict Verdict
+		wantReason  string
+	}{
+		{mode: "", wantVerdict: VerdictChangesNeeded, wantReason: "codex failed, so the review is incomplete"},
+		{mode: AgentFailureIgnore, wantVerdict: VerdictApproved, wantReason: "codex failed and is ignored"},
+		{mode: AgentFailureBlocking, wantVerdict: VerdictBlocking, wantReason: "codex failed, and failed agents block the review"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			t.Parallel()
+			consolidated, _ := NewConsolidator(nil).WithPolicy(VerdictPolicy{AgentFailure: tt.mode}).Consolidate(results)
+			assert.Equal(t, tt.wantVerdict, consolidated.Verdict)
+			assert.Contains(t, consolidated.VerdictReasons, tt.wantReason)
+		})
+	}
+}
+
+func TestConsolidate_Policy_WaivedReason(t *testing.T) {
+	t.Parallel()
+
+	accepted := Finding{Severity: SeverityHigh, Category: "security", File: "auth.go", Line: 5, Description: "Weak hash"}
+	baseline := &Baseline{}
+	baseline.Add(NewWaiver(&accepted, "legacy", "", nil, time.Now()))
+
+	consolidated, _ := NewConsolidator(nil).WithBaseline(baseline).Consolidate([]AgentReviewResult{
+		makeAgentResult("claude", VerdictChangesNeeded, []Finding{accepted}, nil),
+	})
+	assert.Equal(t, VerdictApproved, consolidated.Verdict)
+	assert.Contains(t, consolidated.VerdictReasons, "1 waived finding(s) disregarded; the remaining findings call for APPROVED")
+}
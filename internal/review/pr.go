@@ -1,12 +1,9 @@
 package review
 
 import (
-	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
@@ -29,6 +26,7 @@ var prNumberRe = regexp.MustCompile(`/pull/(\d+)`)
 type PRCreator struct {
 	workDir string
 	logger  *log.Logger
+	runner  *CLIRunner
 }
 
 // PRCreateOpts specifies the options for creating a GitHub pull request.
@@ -80,6 +78,7 @@ func NewPRCreator(workDir string, logger *log.Logger) *PRCreator {
 	return &PRCreator{
 		workDir: workDir,
 		logger:  logger,
+		runner:  NewCLIRunner(workDir),
 	}
 }
 
@@ -334,35 +333,9 @@ func (pc *PRCreator) runGit(ctx context.Context, args ...string) (int, string, s
 	return pc.runBin(ctx, "git", args...)
 }
 
-// runBin executes an arbitrary binary and returns (exitCode, stdout, stderr, error).
-// A non-zero exit code is returned as an error. exitCode is -1 when the binary
-// itself could not be started (e.g. not in PATH).
+// runBin executes an arbitrary binary through the creator's CLIRunner.
 func (pc *PRCreator) runBin(ctx context.Context, bin string, args ...string) (int, string, string, error) {
-	cmd := exec.CommandContext(ctx, bin, args...)
-	if pc.workDir != "" {
-		cmd.Dir = pc.workDir
-	}
-
-	var stdoutBuf, stderrBuf bytes.Buffer
-	cmd.Stdout = &stdoutBuf
-	cmd.Stderr = &stderrBuf
-
-	runErr := cmd.Run()
-
-	if runErr == nil {
-		return 0, stdoutBuf.String(), stderrBuf.String(), nil
-	}
-
-	var exitErr *exec.ExitError
-	if errors.As(runErr, &exitErr) {
-		code := exitErr.ExitCode()
-		stdout := stdoutBuf.String()
-		stderr := strings.TrimSpace(stderrBuf.String())
-		return code, stdout, stderr, fmt.Errorf("exit status %d: %s", code, stderr)
-	}
-
-	// Binary could not be started.
-	return -1, "", "", runErr
+	return pc.runner.Run(ctx, bin, args...)
 }
 
 // extractPRURL returns the last non-empty line from gh output, which is
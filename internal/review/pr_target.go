@@ -0,0 +1,88 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/git"
+)
+
+// prViewFields are the fields requested from `gh pr view --json`.
+const prViewFields = "number,title,url,baseRefName,headRefOid"
+
+// commitSHARe matches a full hexadecimal commit SHA.
+var commitSHARe = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
+// PullRequest is a GitHub pull request to review, as reported by
+// `gh pr view`.
+type PullRequest struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	BaseRefName string `json:"baseRefName"`
+	HeadRefOid  string `json:"headRefOid"`
+}
+
+// RefFetcher fetches refs from a git remote. *git.GitClient implements it.
+type RefFetcher interface {
+	FetchRefs(ctx context.Context, remote string, refspecs ...string) error
+}
+
+// FetchPullRequest looks up pull request number with gh and fetches its base
+// branch and head commit from remote, so that its changes can be diffed
+// without checking the pull request out. Pull requests from forks work too:
+// their head is fetched from the base repository's refs/pull/<n>/head.
+func FetchPullRequest(ctx context.Context, runner *CLIRunner, fetcher RefFetcher, remote string, number int) (*PullRequest, error) {
+	if number <= 0 {
+		return nil, fmt.Errorf("review: pull request: invalid number %d", number)
+	}
+	pr, err := viewPullRequest(ctx, runner, number)
+	if err != nil {
+		return nil, err
+	}
+	if !validRef(pr.BaseRefName) {
+		return nil, fmt.Errorf("review: pull request #%d: invalid base branch %q", number, pr.BaseRefName)
+	}
+	if !commitSHARe.MatchString(pr.HeadRefOid) {
+		return nil, fmt.Errorf("review: pull request #%d: invalid head commit %q", number, pr.HeadRefOid)
+	}
+
+	if err := fetcher.FetchRefs(ctx, remote, pr.BaseRefName, fmt.Sprintf("refs/pull/%d/head", number)); err != nil {
+		return nil, fmt.Errorf("review: pull request #%d: %w", number, err)
+	}
+	return pr, nil
+}
+
+// viewPullRequest looks up pull request number with `gh pr view`, or the
+// pull request of the current branch when number is zero.
+func viewPullRequest(ctx context.Context, runner *CLIRunner, number int) (*PullRequest, error) {
+	args := []string{"pr", "view"}
+	name := "of the current branch"
+	if number > 0 {
+		args = append(args, strconv.Itoa(number))
+		name = "#" + strconv.Itoa(number)
+	}
+	out, err := runner.GH(ctx, append(args, "--json", prViewFields)...)
+	if err != nil {
+		return nil, fmt.Errorf("review: pull request %s: %w", name, err)
+	}
+
+	var pr PullRequest
+	if err := json.Unmarshal([]byte(out), &pr); err != nil {
+		return nil, fmt.Errorf("review: pull request %s: parsing gh output: %w", name, err)
+	}
+	return &pr, nil
+}
+
+// Target returns the diff target of the pull request's changes: its head
+// commit against the merge base with the base branch fetched from remote,
+// which is what GitHub shows as the pull request's diff.
+func (pr *PullRequest) Target(remote string) git.DiffTarget {
+	return git.DiffTarget{
+		Kind: git.TargetRange,
+		Ref:  remote + "/" + pr.BaseRefName + "..." + pr.HeadRefOid,
+	}
+}
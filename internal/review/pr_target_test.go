@@ -0,0 +1,93 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/git"
+)
+
+// fakeRefFetcher records FetchRefs calls.
+type fakeRefFetcher struct {
+	remote   string
+	refspecs []string
+	err      error
+}
+
+func (f *fakeRefFetcher) FetchRefs(_ context.Context, remote string, refspecs ...string) error {
+	f.remote = remote
+	f.refspecs = refspecs
+	return f.err
+}
+
+const testPRHead = "0123456789abcdef0123456789abcdef01234567"
+
+// fakeGHPRView returns a gh script that prints output for `gh pr view`.
+func fakeGHPRView(output string) string {
+	return fmt.Sprintf(`#!/bin/sh
+if [ "$1" = "pr" ] && [ "$2" = "view" ]; then
+  echo '%s'
+  exit 0
+fi
+echo "unexpected: $*" >&2
+exit 1
+`, output)
+}
+
+func TestFetchPullRequest(t *testing.T) {
+	dir := t.TempDir()
+	writeFakeScript(t, dir, "gh", fakeGHPRView(
+		`{"number":123,"title":"Add login","url":"https://github.com/o/r/pull/123","baseRefName":"main","headRefOid":"`+testPRHead+`"}`))
+	withFakePath(t, dir)
+
+	fetcher := &fakeRefFetcher{}
+	pr, err := FetchPullRequest(context.Background(), NewCLIRunner(""), fetcher, "origin", 123)
+	require.NoError(t, err)
+	assert.Equal(t, &PullRequest{
+		Number:      123,
+		Title:       "Add login",
+		URL:         "https://github.com/o/r/pull/123",
+		BaseRefName: "main",
+		HeadRefOid:  testPRHead,
+	}, pr)
+	assert.Equal(t, "origin", fetcher.remote)
+	assert.Equal(t, []string{"main", "refs/pull/123/head"}, fetcher.refspecs)
+	assert.Equal(t, git.DiffTarget{Kind: git.TargetRange, Ref: "origin/main..." + testPRHead}, pr.Target("origin"))
+}
+
+func TestFetchPullRequest_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := FetchPullRequest(ctx, NewCLIRunner(""), &fakeRefFetcher{}, "origin", 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid number")
+
+	tests := []struct {
+		name    string
+		output  string
+		fetch   error
+		wantErr string
+	}{
+		{name: "bad json", output: "not json", wantErr: "parsing gh output"},
+		{name: "unsafe base", output: `{"baseRefName":"--upload-pack=x","headRefOid":"` + testPRHead + `"}`, wantErr: "invalid base branch"},
+		{name: "bad head", output: `{"baseRefName":"main","headRefOid":"HEAD"}`, wantErr: "invalid head commit"},
+		{name: "fetch fails", output: `{"baseRefName":"main","headRefOid":"` + testPRHead + `"}`, fetch: errors.New("no remote"), wantErr: "no remote"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFakeScript(t, dir, "gh", fakeGHPRView(tt.output))
+			withFakePath(t, dir)
+
+			_, err := FetchPullRequest(ctx, NewCLIRunner(""), &fakeRefFetcher{err: tt.fetch}, "origin", 7)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "pull request #7")
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
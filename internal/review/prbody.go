@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/charmbracelet/log"
 
@@ -54,6 +55,10 @@ type PRBodyData struct {
 	// ReviewReport is the full review report in markdown. May be empty.
 	ReviewReport string
 
+	// ReviewLifecycle summarises the branch's finding ledger: the findings
+	// resolved across its reviews and those still open. May be nil.
+	ReviewLifecycle *FindingLifecycle
+
 	// FixReport holds fix-cycle results. May be nil when no fix cycles ran.
 	FixReport *FixReport
 
@@ -72,9 +77,20 @@ type PRBodyData struct {
 }
 
 // TaskSummary holds the minimal task information needed for the PR body.
+// The metrics fields are optional; when any task has Iterations set, the
+// task table gains iteration, time, token and cost columns.
 type TaskSummary struct {
 	ID    string
 	Title string
+
+	// Iterations is the number of agent invocations spent on the task.
+	Iterations int
+	// WallTime is the total time the implementation loop spent on the task.
+	WallTime time.Duration
+	// Tokens is the total number of input and output tokens.
+	Tokens int
+	// CostUSD is the total agent cost in US dollars.
+	CostUSD float64
 }
 
 // prBodyTemplateData is the unexported structure passed to the template. It is
@@ -105,6 +121,9 @@ type prBodyTemplateData struct {
 
 	// FixFinalStatusLabel is a human-readable final status for the fix report.
 	FixFinalStatusLabel string
+
+	// HasTaskMetrics is true when any completed task has recorded metrics.
+	HasTaskMetrics bool
 }
 
 // NewPRBodyGenerator creates a PRBodyGenerator.
@@ -118,6 +137,7 @@ type prBodyTemplateData struct {
 func NewPRBodyGenerator(ag agent.Agent, templatePath string, logger *log.Logger) *PRBodyGenerator {
 	funcMap := template.FuncMap{
 		"escapeCell": escapeCellContent,
+		"formatWall": formatWallTime,
 	}
 
 	tmpl := template.Must(
@@ -293,6 +313,13 @@ func (pg *PRBodyGenerator) buildTemplateData(data PRBodyData) prBodyTemplateData
 		}
 	}
 
+	for _, t := range data.TasksCompleted {
+		if t.Iterations > 0 {
+			td.HasTaskMetrics = true
+			break
+		}
+	}
+
 	// Adjust heading levels in AI summary so it doesn't conflict with PR body
 	// top-level headings.
 	if data.Summary != "" {
@@ -457,6 +484,15 @@ func adjustSummaryHeadings(summary string) string {
 	})
 }
 
+// formatWallTime renders a task's wall time for the task table, rounded to
+// the second, or to the minute from one hour up ("1h5m").
+func formatWallTime(d time.Duration) string {
+	if d >= time.Hour {
+		return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+	}
+	return d.Round(time.Second).String()
+}
+
 // extractPhaseNumber parses a phase number from branch names of the form
 // "phase/3-description" or "phase/3". Returns an empty string when no number
 // is found.
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -192,6 +193,29 @@ func TestPRBodyGenerator_Generate_basicBody(t *testing.T) {
 	assert.Contains(t, body, "T-036")
 }
 
+func TestPRBodyGenerator_Generate_taskMetrics(t *testing.T) {
+	pg := NewPRBodyGenerator(nil, "", nil)
+
+	data := PRBodyData{
+		TasksCompleted: []TaskSummary{
+			{ID: "T-035", Title: "Orchestrator", Iterations: 2, WallTime: 75 * time.Minute, Tokens: 48000, CostUSD: 1.234},
+			{ID: "T-036", Title: "Report"},
+		},
+	}
+
+	body, err := pg.Generate(context.Background(), data)
+	require.NoError(t, err)
+	assert.Contains(t, body, "| Task | Title | Iterations | Wall Time | Tokens | Cost |")
+	assert.Contains(t, body, "| `T-035` | Orchestrator | 2 | 1h15m | 48000 | $1.23 |")
+	assert.Contains(t, body, "| `T-036` | Report | - | - | - | - |")
+
+	// Without metrics the table keeps its two columns.
+	body, err = pg.Generate(context.Background(), PRBodyData{TasksCompleted: []TaskSummary{{ID: "T-036", Title: "Report"}}})
+	require.NoError(t, err)
+	assert.Contains(t, body, "| Task | Title |\n")
+	assert.NotContains(t, body, "Iterations")
+}
+
 func TestPRBodyGenerator_Generate_reviewReportSection(t *testing.T) {
 	pg := NewPRBodyGenerator(nil, "", nil)
 
@@ -225,6 +249,27 @@ func TestPRBodyGenerator_Generate_omitsReviewSectionWhenEmpty(t *testing.T) {
 	assert.NotContains(t, body, "## Review Results")
 }
 
+func TestPRBodyGenerator_Generate_reviewLifecycleSection(t *testing.T) {
+	pg := NewPRBodyGenerator(nil, "", nil)
+
+	data := PRBodyData{
+		Summary:         "Some changes.",
+		ReviewLifecycle: &FindingLifecycle{Review: 3, New: 1, Open: 2, Resolved: 5},
+	}
+
+	body, err := pg.Generate(context.Background(), data)
+	require.NoError(t, err)
+	assert.Contains(t, body, "## Review Findings Lifecycle")
+	assert.Contains(t, body, "Across 3 review(s) of this branch:")
+	assert.Contains(t, body, "| Resolved | 5 |")
+	assert.Contains(t, body, "| Still Open | 2 |")
+
+	data.ReviewLifecycle = nil
+	body, err = pg.Generate(context.Background(), data)
+	require.NoError(t, err)
+	assert.NotContains(t, body, "## Review Findings Lifecycle")
+}
+
 func TestPRBodyGenerator_Generate_fixCyclesSection(t *testing.T) {
 	pg := NewPRBodyGenerator(nil, "", nil)
 
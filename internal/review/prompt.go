@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"text/template"
 
 	"github.com/charmbracelet/log"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/git"
 )
 
 //go:embed review_template.tmpl
@@ -73,6 +76,35 @@ type PromptData struct {
 
 	// ReviewMode is the mode the review is running in ("all" or "split").
 	ReviewMode ReviewMode
+
+	// Since is the previously reviewed commit when the diff only covers the
+	// commits made after it. Empty for a full review.
+	Since string
+
+	// Target describes the reviewed changes when they are not a branch
+	// diff, e.g. "staged changes" or "commit abc1234". Empty for a branch.
+	Target string
+
+	// Part and Parts are set when the diff was split into Parts chunks to
+	// fit the agent's token budget and this prompt covers chunk Part.
+	Part  int
+	Parts int
+
+	// PriorFindings is a pre-formatted list of the open findings of the
+	// previous review on the files to review, for the agent to re-check.
+	PriorFindings string
+
+	// AnalyzerFindings is a pre-formatted list of the static-analysis
+	// findings on the files to review, which the review already includes.
+	AnalyzerFindings string
+
+	// Persona is the name of the reviewer persona the agent is acting as.
+	// Empty for a general review.
+	Persona string
+
+	// PersonaFocus is the content of the persona's prompt file describing
+	// what to focus on. Empty when the persona has no prompt file.
+	PersonaFocus string
 }
 
 // ProjectContext holds the loaded project brief and review rules.
@@ -191,6 +223,8 @@ type PromptBuilder struct {
 	cfg    ReviewConfig
 	loader *ContextLoader
 	logger *log.Logger
+	prior  []*Finding
+	static []*Finding
 }
 
 // NewPromptBuilder creates a PromptBuilder configured from cfg. A ContextLoader
@@ -203,6 +237,22 @@ func NewPromptBuilder(cfg ReviewConfig, logger *log.Logger) *PromptBuilder {
 	}
 }
 
+// WithPriorFindings sets the open findings of a previous review. Prompts built
+// by BuildForAgent list the ones on the agent's files and ask the agent to
+// report them again if they still apply.
+func (pb *PromptBuilder) WithPriorFindings(findings []*Finding) *PromptBuilder {
+	pb.prior = findings
+	return pb
+}
+
+// WithAnalyzerFindings sets the findings of the static analyzers marked as
+// context. Prompts list the ones on the agent's files so the agent does not
+// report them again.
+func (pb *PromptBuilder) WithAnalyzerFindings(findings []*Finding) *PromptBuilder {
+	pb.static = findings
+	return pb
+}
+
 // Build renders the review prompt template with the supplied data. It loads
 // the custom template from cfg.PromptsDir when available, falling back to the
 // embedded default. ctx is accepted for future cancellation support.
@@ -236,19 +286,56 @@ func (pb *PromptBuilder) BuildForAgent(
 	diff *DiffResult,
 	files []ChangedFile,
 	mode ReviewMode,
+) (string, error) {
+	return pb.buildFor(ctx, agentName, nil, diff, files, mode)
+}
+
+// BuildForPersona is BuildForAgent for an agent acting as persona: the prompt
+// also carries the persona's focus and its rule files.
+func (pb *PromptBuilder) BuildForPersona(
+	ctx context.Context,
+	persona Persona,
+	diff *DiffResult,
+	files []ChangedFile,
+	mode ReviewMode,
+) (string, error) {
+	return pb.buildFor(ctx, persona.Agent, &persona, diff, files, mode)
+}
+
+// buildFor implements BuildForAgent and BuildForPersona. persona is nil for
+// a general review.
+func (pb *PromptBuilder) buildFor(
+	ctx context.Context,
+	agentName string,
+	persona *Persona,
+	diff *DiffResult,
+	files []ChangedFile,
+	mode ReviewMode,
 ) (string, error) {
 	projectCtx, err := pb.loader.Load()
 	if err != nil {
 		return "", fmt.Errorf("review: prompt: loading project context: %w", err)
 	}
 
+	rules := projectCtx.Rules
+	var personaName, personaFocus string
+	if persona != nil {
+		focus, personaRules, loadErr := persona.loadContext()
+		if loadErr != nil {
+			return "", fmt.Errorf("review: prompt: loading persona context: %w", loadErr)
+		}
+		personaName, personaFocus = persona.Name, focus
+		rules = append(slices.Clone(rules), personaRules...)
+	}
+
 	if pb.logger != nil {
 		pb.logger.Debug("building review prompt",
 			"agent", agentName,
+			"persona", personaName,
 			"files", len(files),
 			"mode", mode,
 			"has_brief", projectCtx.Brief != "",
-			"rules", len(projectCtx.Rules),
+			"rules", len(rules),
 		)
 	}
 
@@ -262,8 +349,15 @@ func (pb *PromptBuilder) BuildForAgent(
 		stats = computeStats(files)
 	}
 
-	// Truncate very large diffs.
-	if len(fullDiff) > maxDiffBytes {
+	// A persona only reviews the files matching its paths, so its prompt only
+	// carries their part of the diff.
+	if persona != nil {
+		fullDiff = filterDiff(fullDiff, files)
+		stats = computeStats(files)
+	}
+
+	// Truncate very large diffs. A chunk already fits its token budget.
+	if diff.Parts == 0 && len(fullDiff) > maxDiffBytes {
 		fullDiff = fullDiff[:maxDiffBytes] + "\n... [diff truncated at 100KB] ..."
 	}
 
@@ -271,16 +365,24 @@ func (pb *PromptBuilder) BuildForAgent(
 	fileList, highRiskFiles := formatFileList(files)
 
 	data := PromptData{
-		ProjectBrief:  projectCtx.Brief,
-		Rules:         projectCtx.Rules,
-		Diff:          fullDiff,
-		Files:         files,
-		FileList:      fileList,
-		HighRiskFiles: highRiskFiles,
-		Stats:         stats,
-		JSONSchema:    jsonSchemaExample,
-		AgentName:     agentName,
-		ReviewMode:    mode,
+		ProjectBrief:     projectCtx.Brief,
+		Rules:            rules,
+		Diff:             fullDiff,
+		Files:            files,
+		FileList:         fileList,
+		HighRiskFiles:    highRiskFiles,
+		Stats:            stats,
+		JSONSchema:       jsonSchemaExample,
+		AgentName:        agentName,
+		ReviewMode:       mode,
+		Since:            diff.Since,
+		Target:           targetLabel(diff.Target),
+		Part:             diff.Part,
+		Parts:            diff.Parts,
+		PriorFindings:    formatPriorFindings(pb.prior, files),
+		AnalyzerFindings: formatPriorFindings(pb.static, files),
+		Persona:          personaName,
+		PersonaFocus:     personaFocus,
 	}
 
 	return pb.Build(ctx, data)
@@ -357,6 +459,33 @@ func formatFileList(files []ChangedFile) (string, []string) {
 	return strings.TrimRight(sb.String(), "\n"), highRisk
 }
 
+// formatPriorFindings lists the findings on files, one per line, as
+// "- [severity] category at path:line: description". Returns "" when none of
+// the findings is on one of the files.
+func formatPriorFindings(findings []*Finding, files []ChangedFile) string {
+	if len(findings) == 0 {
+		return ""
+	}
+	paths := make(map[string]bool, len(files))
+	for _, f := range files {
+		paths[f.Path] = true
+	}
+
+	var sb strings.Builder
+	for _, f := range findings {
+		path := normalizeFindingPath(f.File)
+		if !paths[path] {
+			continue
+		}
+		location := path
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s:%d", path, f.Line)
+		}
+		fmt.Fprintf(&sb, "- [%s] %s at %s: %s\n", f.Severity, f.Category, location, f.Description)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
 // buildChangeSummary returns a short string like "modified, +42/-10" or
 // "added, +150" describing the file's change type and line deltas.
 func buildChangeSummary(f ChangedFile) string {
@@ -376,3 +505,12 @@ func buildChangeSummary(f ChangedFile) string {
 		return changeStr
 	}
 }
+
+// targetLabel describes target for prompts and reports. It is empty for a
+// branch diff, the default that needs no explanation.
+func targetLabel(target git.DiffTarget) string {
+	if target.Kind == "" || target.Kind == git.TargetBranch {
+		return ""
+	}
+	return target.String()
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/charmbracelet/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/git"
 )
 
 // ---------------------------------------------------------------------------
@@ -586,6 +588,28 @@ func TestPromptBuilder_BuildForAgent_TruncatesLargeDiff(t *testing.T) {
 	assert.Contains(t, result, "[diff truncated at 100KB]")
 }
 
+func TestPromptBuilder_BuildForAgent_ChunkNotTruncated(t *testing.T) {
+	t.Parallel()
+
+	pb := NewPromptBuilder(ReviewConfig{}, nil)
+
+	// A chunk was sized by its token budget, which may exceed 100KB.
+	largeDiff := strings.Repeat("x", maxDiffBytes+1000)
+	diff := &DiffResult{
+		Files:      []ChangedFile{{Path: "big.go", ChangeType: ChangeModified, Risk: RiskNormal}},
+		FullDiff:   largeDiff,
+		BaseBranch: "main",
+		Part:       2,
+		Parts:      3,
+	}
+
+	result, err := pb.BuildForAgent(context.Background(), "claude", diff, diff.Files, ReviewModeAll)
+	require.NoError(t, err)
+	assert.NotContains(t, result, "[diff truncated at 100KB]")
+	assert.Contains(t, result, largeDiff)
+	assert.Contains(t, result, "split into 3 parts. This is part 2;")
+}
+
 func TestPromptBuilder_BuildForAgent_HighRiskFilesHighlighted(t *testing.T) {
 	t.Parallel()
 
@@ -1260,3 +1284,125 @@ func TestPromptBuilder_BuildForAgent_AllModeUsesFullStats(t *testing.T) {
 	// In "all" mode the diff's own Stats are passed through unchanged.
 	assert.Contains(t, result, "Total files changed: 4")
 }
+
+func TestPromptBuilder_BuildForAgent_PriorFindings(t *testing.T) {
+	t.Parallel()
+
+	pb := NewPromptBuilder(ReviewConfig{}, nil).WithPriorFindings([]*Finding{
+		{Severity: SeverityHigh, Category: "security", File: "./main.go", Line: 42, Description: "SQL injection"},
+		{Severity: SeverityLow, Category: "style", File: "main.go", Description: "Long file"},
+		{Severity: SeverityHigh, Category: "security", File: "other.go", Line: 1, Description: "Not assigned"},
+	})
+	diff := &DiffResult{
+		Files:    []ChangedFile{{Path: "main.go", ChangeType: ChangeModified}},
+		FullDiff: "diff output",
+		Since:    "abc1234",
+	}
+
+	result, err := pb.BuildForAgent(context.Background(), "claude", diff, diff.Files, ReviewModeAll)
+	require.NoError(t, err)
+	assert.Contains(t, result, "## Previously Reported Findings")
+	assert.Contains(t, result, "- [high] security at main.go:42: SQL injection\n- [low] style at main.go: Long file\n")
+	assert.NotContains(t, result, "Not assigned")
+	assert.Contains(t, result, "commits made since the last review (at abc1234)")
+}
+
+func TestPromptBuilder_BuildForAgent_AnalyzerFindings(t *testing.T) {
+	t.Parallel()
+
+	pb := NewPromptBuilder(ReviewConfig{}, nil).WithAnalyzerFindings([]*Finding{
+		{Severity: SeverityMedium, Category: "SA4006", File: "main.go", Line: 7, Description: "value never used"},
+		{Severity: SeverityMedium, Category: "SA4006", File: "other.go", Line: 1, Description: "Not assigned"},
+	})
+	diff := &DiffResult{
+		Files:    []ChangedFile{{Path: "main.go", ChangeType: ChangeModified}},
+		FullDiff: "diff output",
+	}
+
+	result, err := pb.BuildForAgent(context.Background(), "claude", diff, diff.Files, ReviewModeAll)
+	require.NoError(t, err)
+	assert.Contains(t, result, "## Static Analysis Findings")
+	assert.Contains(t, result, "- [medium] SA4006 at main.go:7: value never used\n")
+	assert.NotContains(t, result, "Not assigned")
+	assert.NotContains(t, result, "Previously Reported Findings")
+}
+
+func TestPromptBuilder_BuildForAgent_Target(t *testing.T) {
+	t.Parallel()
+
+	pb := NewPromptBuilder(ReviewConfig{}, nil)
+	diff := &DiffResult{
+		Files:    []ChangedFile{{Path: "main.go", ChangeType: ChangeModified}},
+		FullDiff: "diff output",
+		Target:   git.DiffTarget{Kind: git.TargetWorktree},
+	}
+
+	result, err := pb.BuildForAgent(context.Background(), "claude", diff, diff.Files, ReviewModeAll)
+	require.NoError(t, err)
+	assert.Contains(t, result, "Changes under review: working tree.")
+
+	diff.Target = git.DiffTarget{Kind: git.TargetBranch, Ref: "main"}
+	result, err = pb.BuildForAgent(context.Background(), "claude", diff, diff.Files, ReviewModeAll)
+	require.NoError(t, err)
+	assert.NotContains(t, result, "Changes under review")
+}
+
+func TestPromptBuilder_BuildForAgent_NoPriorFindings(t *testing.T) {
+	t.Parallel()
+
+	pb := NewPromptBuilder(ReviewConfig{}, nil)
+	diff := &DiffResult{
+		Files:    []ChangedFile{{Path: "main.go", ChangeType: ChangeModified}},
+		FullDiff: "diff output",
+	}
+
+	result, err := pb.BuildForAgent(context.Background(), "claude", diff, diff.Files, ReviewModeAll)
+	require.NoError(t, err)
+	assert.NotContains(t, result, "Previously Reported Findings")
+	assert.NotContains(t, result, "Static Analysis Findings")
+	assert.NotContains(t, result, "since the last review")
+}
+
+func TestPromptBuilder_BuildForPersona(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	rulesDir := filepath.Join(dir, "rules")
+	require.NoError(t, os.MkdirAll(rulesDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(rulesDir, "general.md"), []byte("General rule"), 0o600))
+	focus := filepath.Join(dir, "security.md")
+	require.NoError(t, os.WriteFile(focus, []byte("Hunt for injection flaws."), 0o600))
+	rule := filepath.Join(dir, "owasp.md")
+	require.NoError(t, os.WriteFile(rule, []byte("OWASP rule"), 0o600))
+
+	pb := NewPromptBuilder(ReviewConfig{RulesDir: rulesDir}, nil)
+	diff := &DiffResult{
+		Files: []ChangedFile{
+			{Path: "auth.go", ChangeType: ChangeModified, LinesAdded: 1},
+			{Path: "README.md", ChangeType: ChangeModified, LinesAdded: 3},
+		},
+		FullDiff: "diff --git a/auth.go b/auth.go\n--- a/auth.go\n+++ b/auth.go\n@@ -1 +1 @@\n+x\n" +
+			"diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1 +1 @@\n+y\n",
+		BaseBranch: "main",
+	}
+	persona := Persona{Name: "security", Agent: "claude", PromptFile: focus, RuleFiles: []string{rule}}
+
+	result, err := pb.BuildForPersona(context.Background(), persona, diff, persona.FilterFiles(diff.Files[:1]), ReviewModeAll)
+	require.NoError(t, err)
+	assert.Contains(t, result, "You are claude")
+	assert.Contains(t, result, "## Reviewer Focus: security\n\nHunt for injection flaws.")
+	assert.Less(t, strings.Index(result, "General rule"), strings.Index(result, "OWASP rule"))
+	assert.Contains(t, result, "Total files changed: 1")
+	assert.NotContains(t, result, "README.md")
+
+	// Without a prompt file the persona gets a generic focus.
+	result, err = pb.BuildForPersona(context.Background(), Persona{Name: "performance", Agent: "codex"}, diff, diff.Files, ReviewModeAll)
+	require.NoError(t, err)
+	assert.Contains(t, result, "reviewing as the performance specialist")
+	assert.NotContains(t, result, "OWASP rule")
+
+	// A general review has no focus section.
+	result, err = pb.BuildForAgent(context.Background(), "claude", diff, diff.Files, ReviewModeAll)
+	require.NoError(t, err)
+	assert.NotContains(t, result, "Reviewer Focus")
+}
@@ -0,0 +1,448 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// PublishEvent is the event of a GitHub pull request review.
+type PublishEvent string
+
+const (
+	// PublishApprove approves the pull request.
+	PublishApprove PublishEvent = "APPROVE"
+
+	// PublishComment leaves the review as a comment without approving or
+	// requesting changes.
+	PublishComment PublishEvent = "COMMENT"
+
+	// PublishRequestChanges requests changes to the pull request.
+	PublishRequestChanges PublishEvent = "REQUEST_CHANGES"
+)
+
+// publishEventFor maps a verdict to the event of the published review:
+// APPROVED approves, and CHANGES_NEEDED and BLOCKING request changes. Any
+// other verdict only comments.
+func publishEventFor(v Verdict) PublishEvent {
+	switch v {
+	case VerdictApproved:
+		return PublishApprove
+	case VerdictChangesNeeded, VerdictBlocking:
+		return PublishRequestChanges
+	default:
+		return PublishComment
+	}
+}
+
+// Hidden markers that identify the comments Raven posts, so that a later
+// publish updates them instead of posting duplicates.
+const (
+	publishReviewMarker     = "<!-- raven-review -->"
+	publishSupersededMarker = "<!-- raven-review superseded -->"
+)
+
+// publishFindingMarkerRe matches the marker of an inline finding comment,
+// e.g. "<!-- raven-finding: 3f2a9c01b7de -->", with an optional "resolved"
+// suffix once the finding is no longer reported.
+var publishFindingMarkerRe = regexp.MustCompile(`<!-- raven-finding: ([0-9a-f]+)( resolved)? -->`)
+
+// PublishResult describes a review published to a pull request.
+type PublishResult struct {
+	// Number and URL identify the pull request.
+	Number int
+	URL    string
+
+	// ReviewURL is the HTML URL of the posted review.
+	ReviewURL string
+
+	// Event is the event the review was posted with. It is PublishComment
+	// when GitHub rejected the verdict's event, e.g. on the author's own pull
+	// request.
+	Event PublishEvent
+
+	// Inline is the number of findings posted as new inline comments.
+	Inline int
+
+	// Updated is the number of earlier Raven comments updated in place for
+	// findings that are still reported.
+	Updated int
+
+	// Resolved is the number of earlier Raven comments marked resolved
+	// because their findings are no longer reported.
+	Resolved int
+
+	// Summary is the number of findings listed in the review body because
+	// they cannot be anchored to a line of the diff.
+	Summary int
+}
+
+// ReviewPublisher posts a consolidated review to a GitHub pull request
+// through `gh api`. Findings on lines of the diff become inline comments and
+// the remaining findings are listed in the review body.
+type ReviewPublisher struct {
+	runner *CLIRunner
+	logger *log.Logger
+
+	// headSHA and baseBranch are the commit and base branch that were
+	// reviewed; see WithReviewedHead.
+	headSHA    string
+	baseBranch string
+}
+
+// NewReviewPublisher creates a ReviewPublisher that runs gh through runner.
+// logger may be nil.
+func NewReviewPublisher(runner *CLIRunner, logger *log.Logger) *ReviewPublisher {
+	return &ReviewPublisher{runner: runner, logger: logger}
+}
+
+// WithReviewedHead sets the commit and base branch the review was run on.
+// Publish then refuses to post to a pull request whose head commit or base
+// branch differs, since the findings would not match its diff. Empty values
+// are not checked.
+func (p *ReviewPublisher) WithReviewedHead(headSHA, baseBranch string) *ReviewPublisher {
+	p.headSHA = headSHA
+	p.baseBranch = baseBranch
+	return p
+}
+
+// prComment is an inline pull request comment as listed by the GitHub API.
+type prComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// prReview is a pull request review as listed by the GitHub API.
+type prReview struct {
+	ID      int64  `json:"id"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// reviewRequest is the body of a "create a review" API request.
+type reviewRequest struct {
+	CommitID string          `json:"commit_id"`
+	Body     string          `json:"body"`
+	Event    PublishEvent    `json:"event"`
+	Comments []reviewComment `json:"comments"`
+}
+
+// reviewComment is an inline comment of a reviewRequest.
+type reviewComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Side string `json:"side"`
+	Body string `json:"body"`
+}
+
+// Publish posts consolidated as a review of pull request number, or of the
+// pull request of the current branch when number is zero. The review event
+// follows the verdict. Nothing is posted when the pull request does not match
+// the head commit and base branch set by WithReviewedHead. Raven comments from earlier publishes are updated in
+// place: a finding that is still reported keeps its comment, a finding that
+// is no longer reported has its comment marked resolved, and earlier review
+// summaries are marked superseded.
+func (p *ReviewPublisher) Publish(ctx context.Context, number int, consolidated *ConsolidatedReview) (*PublishResult, error) {
+	pr, err := viewPullRequest(ctx, p.runner, number)
+	if err != nil {
+		return nil, fmt.Errorf("publish: %w", err)
+	}
+	if pr.Number <= 0 || !commitSHARe.MatchString(pr.HeadRefOid) {
+		return nil, fmt.Errorf("publish: pull request %d: unexpected gh output", number)
+	}
+	if p.headSHA != "" && p.headSHA != pr.HeadRefOid {
+		return nil, fmt.Errorf("publish: pull request #%d is at %s but the review ran on %s; push the branch or review with --pr %d",
+			pr.Number, shortCommit(pr.HeadRefOid), shortCommit(p.headSHA), pr.Number)
+	}
+	if p.baseBranch != "" && p.baseBranch != pr.BaseRefName {
+		return nil, fmt.Errorf("publish: pull request #%d targets %q but the review diffed against %q; review with --base %s",
+			pr.Number, pr.BaseRefName, p.baseBranch, pr.BaseRefName)
+	}
+	base := fmt.Sprintf("repos/{owner}/{repo}/pulls/%d", pr.Number)
+	result := &PublishResult{Number: pr.Number, URL: pr.URL}
+
+	var existing []prComment
+	if err := p.list(ctx, base+"/comments", &existing); err != nil {
+		return nil, fmt.Errorf("publish: listing comments of #%d: %w", pr.Number, err)
+	}
+	// posted maps the fingerprint of each earlier Raven comment to the
+	// comment; a comment marked resolved is reopened when its finding is
+	// reported again.
+	posted := make(map[string]prComment)
+	for _, c := range existing {
+		if m := publishFindingMarkerRe.FindStringSubmatch(c.Body); m != nil {
+			if _, dup := posted[m[1]]; !dup {
+				posted[m[1]] = c
+			}
+		}
+	}
+
+	current := make(map[string]bool, len(consolidated.Findings))
+	var inline []reviewComment
+	var summary []*Finding
+	for _, f := range consolidated.Findings {
+		if !publishInline(f) {
+			summary = append(summary, f)
+			continue
+		}
+		current[f.Fingerprint] = true
+		body := publishFindingBody(f)
+		if prev, ok := posted[f.Fingerprint]; ok {
+			if prev.Body != body {
+				if err := p.editComment(ctx, prev.ID, body); err != nil {
+					return nil, fmt.Errorf("publish: updating comment on %s:%d: %w", f.File, f.Line, err)
+				}
+			}
+			result.Updated++
+			continue
+		}
+		inline = append(inline, reviewComment{
+			Path: normalizeFindingPath(f.File),
+			Line: f.Line,
+			Side: "RIGHT",
+			Body: body,
+		})
+	}
+	for _, c := range existing {
+		m := publishFindingMarkerRe.FindStringSubmatch(c.Body)
+		if m == nil || m[2] != "" || current[m[1]] || posted[m[1]].ID != c.ID {
+			continue
+		}
+		if err := p.editComment(ctx, c.ID, publishResolvedBody(c.Body, m[1])); err != nil {
+			return nil, fmt.Errorf("publish: resolving comment %d: %w", c.ID, err)
+		}
+		result.Resolved++
+	}
+	result.Inline = len(inline)
+	result.Summary = len(summary)
+
+	req := reviewRequest{
+		CommitID: pr.HeadRefOid,
+		Body:     publishSummaryBody(consolidated, result, summary),
+		Event:    publishEventFor(consolidated.Verdict),
+		Comments: inline,
+	}
+	if req.Comments == nil {
+		req.Comments = []reviewComment{}
+	}
+	created, err := p.postReview(ctx, base+"/reviews", req)
+	if err != nil && req.Event != PublishComment {
+		// GitHub refuses to approve or request changes on the author's own
+		// pull request; the findings are still worth posting.
+		if p.logger != nil {
+			p.logger.Warn("publish: review event rejected, posting as comment", "event", req.Event, "error", err)
+		}
+		req.Event = PublishComment
+		created, err = p.postReview(ctx, base+"/reviews", req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("publish: posting review of #%d: %w", pr.Number, err)
+	}
+	result.Event = req.Event
+	result.ReviewURL = created.HTMLURL
+
+	if err := p.supersede(ctx, base+"/reviews", created.ID); err != nil {
+		return nil, fmt.Errorf("publish: %w", err)
+	}
+
+	if p.logger != nil {
+		p.logger.Info("publish: review posted",
+			"pr", pr.Number,
+			"event", result.Event,
+			"inline", result.Inline,
+			"updated", result.Updated,
+			"resolved", result.Resolved,
+			"summary", result.Summary,
+		)
+	}
+	return result, nil
+}
+
+// publishInline reports whether f can be posted as an inline comment: it
+// points at a line covered by the diff and has a fingerprint to recognise
+// its comment by on the next publish.
+func publishInline(f *Finding) bool {
+	return f.Location == LocationInDiff && f.Line > 0 && f.File != "" && f.Fingerprint != ""
+}
+
+// publishFindingBody renders the inline comment of f.
+func publishFindingBody(f *Finding) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**[%s] %s**", f.Severity, strings.TrimSpace(f.Category))
+	if reporters := findingReporters(f); reporters != "" {
+		fmt.Fprintf(&sb, " (%s)", reporters)
+	}
+	sb.WriteString("\n\n" + strings.TrimSpace(f.Description))
+	if s := strings.TrimSpace(f.Suggestion); s != "" {
+		sb.WriteString("\n\n**Suggestion:** " + s)
+	}
+	fmt.Fprintf(&sb, "\n\n<!-- raven-finding: %s -->", f.Fingerprint)
+	return sb.String()
+}
+
+// findingReporters names the personas, or else the agent, that reported f.
+func findingReporters(f *Finding) string {
+	if len(f.Personas) > 0 {
+		return strings.Join(f.Personas, ", ")
+	}
+	return f.Agent
+}
+
+// publishResolvedBody rewrites an earlier inline comment for a finding that
+// is no longer reported, keeping the original text folded away.
+func publishResolvedBody(body, fingerprint string) string {
+	original := strings.TrimSpace(publishFindingMarkerRe.ReplaceAllString(body, ""))
+	return fmt.Sprintf("**Resolved:** no longer reported by the latest Raven review.\n\n"+
+		"<details><summary>Original comment</summary>\n\n%s\n\n</details>\n\n"+
+		"<!-- raven-finding: %s resolved -->", original, fingerprint)
+}
+
+// publishSummaryBody renders the body of the review: the verdict and its
+// reasons, followed by the findings that are not posted inline.
+func publishSummaryBody(consolidated *ConsolidatedReview, result *PublishResult, summary []*Finding) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Raven review: %s %s\n", verdictIndicator(consolidated.Verdict), consolidated.Verdict)
+	if len(consolidated.VerdictReasons) > 0 {
+		sb.WriteString("\nWhy this verdict:\n")
+		for _, r := range consolidated.VerdictReasons {
+			fmt.Fprintf(&sb, "- %s\n", r)
+		}
+	}
+
+	fmt.Fprintf(&sb, "\n%d finding(s): %d new inline, %d updated inline, %d below.",
+		len(consolidated.Findings), result.Inline, result.Updated, len(summary))
+	if result.Resolved > 0 {
+		fmt.Fprintf(&sb, " %d earlier finding(s) resolved.", result.Resolved)
+	}
+	if len(consolidated.Waived) > 0 {
+		fmt.Fprintf(&sb, " %d waived finding(s) not shown.", len(consolidated.Waived))
+	}
+	sb.WriteString("\n")
+	if lc := consolidated.Lifecycle; lc != nil && lc.Review > 1 {
+		fmt.Fprintf(&sb, "\nSince the previous review: %d resolved, %d still open, %d new, %d regressed.\n",
+			lc.Resolved, lc.Open, lc.New, lc.Regressed)
+	}
+
+	if len(summary) > 0 {
+		sb.WriteString("\n### Other findings\n\n")
+		sb.WriteString("| Severity | File | Line | Category | Description |\n")
+		sb.WriteString("|----------|------|------|----------|-------------|\n")
+		for _, f := range summary {
+			line := ""
+			if f.Line > 0 {
+				line = strconv.Itoa(f.Line)
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n",
+				f.Severity,
+				escapeCellContent(f.File),
+				line,
+				escapeCellContent(f.Category),
+				escapeCellContent(findingMessage(f)),
+			)
+		}
+	}
+
+	sb.WriteString("\n" + publishReviewMarker)
+	return sb.String()
+}
+
+// list reads every page of a GitHub API list endpoint into out, a pointer to
+// a slice. `gh api --paginate` prints one JSON array per page.
+func (p *ReviewPublisher) list(ctx context.Context, endpoint string, out any) error {
+	raw, err := p.runner.GH(ctx, "api", "--paginate", endpoint)
+	if err != nil {
+		return err
+	}
+	var all []json.RawMessage
+	dec := json.NewDecoder(strings.NewReader(raw))
+	for {
+		var page []json.RawMessage
+		if err := dec.Decode(&page); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("parsing gh output: %w", err)
+		}
+		all = append(all, page...)
+	}
+	data, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("parsing gh output: %w", err)
+	}
+	return nil
+}
+
+// editComment replaces the body of inline comment id.
+func (p *ReviewPublisher) editComment(ctx context.Context, id int64, body string) error {
+	_, err := p.runner.GH(ctx, "api", "--method", "PATCH",
+		fmt.Sprintf("repos/{owner}/{repo}/pulls/comments/%d", id), "-f", "body="+body)
+	return err
+}
+
+// postReview creates a review from req and returns the created review.
+func (p *ReviewPublisher) postReview(ctx context.Context, endpoint string, req reviewRequest) (*prReview, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	input, err := os.CreateTemp("", "raven-review-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("creating request file: %w", err)
+	}
+	defer os.Remove(input.Name())
+	if _, err := input.Write(data); err != nil {
+		input.Close()
+		return nil, fmt.Errorf("writing request file: %w", err)
+	}
+	if err := input.Close(); err != nil {
+		return nil, fmt.Errorf("writing request file: %w", err)
+	}
+
+	out, err := p.runner.GH(ctx, "api", "--method", "POST", endpoint, "--input", input.Name())
+	if err != nil {
+		return nil, err
+	}
+	var created prReview
+	if err := json.Unmarshal([]byte(out), &created); err != nil {
+		return nil, fmt.Errorf("parsing gh output: %w", err)
+	}
+	return &created, nil
+}
+
+// supersede marks the summaries of earlier Raven reviews other than keep as
+// superseded, so that only the latest summary is shown in full.
+func (p *ReviewPublisher) supersede(ctx context.Context, endpoint string, keep int64) error {
+	var reviews []prReview
+	if err := p.list(ctx, endpoint, &reviews); err != nil {
+		return fmt.Errorf("listing reviews: %w", err)
+	}
+	for _, r := range reviews {
+		if r.ID == keep || !strings.Contains(r.Body, publishReviewMarker) {
+			continue
+		}
+		body := "_Superseded by a later Raven review._\n\n" + publishSupersededMarker
+		if _, err := p.runner.GH(ctx, "api", "--method", "PUT",
+			fmt.Sprintf("%s/%d", endpoint, r.ID), "-f", "body="+body); err != nil {
+			return fmt.Errorf("superseding review %d: %w", r.ID, err)
+		}
+	}
+	return nil
+}
+
+// shortCommit abbreviates a commit SHA for display.
+func shortCommit(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,264 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePublishGH installs a fake gh that answers the calls of
+// ReviewPublisher.Publish. comments and reviews are the JSON pages returned
+// for the comment and review lists. When rejectEvent is set, creating a
+// review with that event fails. It returns a function reading the logged gh
+// invocations, one per line with newlines replaced by spaces, and the
+// request bodies posted with --input.
+func fakePublishGH(t *testing.T, comments, reviews, rejectEvent string) func() (calls []string, inputs []string) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "comments.json"), []byte(comments), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "reviews.json"), []byte(reviews), 0o644))
+
+	script := fmt.Sprintf(`#!/bin/sh
+printf '%%s' "$*" | tr '\n' ' ' >> %[1]s/calls.log
+echo >> %[1]s/calls.log
+case "$*" in
+  "pr view"*)
+    echo '{"number":42,"title":"Feature","url":"https://github.com/o/r/pull/42","baseRefName":"main","headRefOid":"%[2]s"}'
+    ;;
+  *"--paginate repos/{owner}/{repo}/pulls/42/comments")
+    cat %[1]s/comments.json
+    ;;
+  *"--paginate repos/{owner}/{repo}/pulls/42/reviews")
+    cat %[1]s/reviews.json
+    ;;
+  *"--method POST"*)
+    input=""
+    while [ $# -gt 0 ]; do
+      if [ "$1" = "--input" ]; then input="$2"; fi
+      shift
+    done
+    cat "$input" >> %[1]s/inputs.log
+    echo >> %[1]s/inputs.log
+    if [ -n "%[3]s" ] && grep -q '"event":"%[3]s"' "$input"; then
+      echo "Can not approve your own pull request" >&2
+      exit 1
+    fi
+    echo '{"id":99,"body":"","html_url":"https://github.com/o/r/pull/42#pullrequestreview-99"}'
+    ;;
+  *)
+    echo '{}'
+    ;;
+esac
+`, dir, testPRHead, rejectEvent)
+	writeFakeScript(t, dir, "gh", script)
+	withFakePath(t, dir)
+
+	return func() ([]string, []string) {
+		var calls, inputs []string
+		if data, err := os.ReadFile(filepath.Join(dir, "calls.log")); err == nil {
+			calls = strings.Split(strings.TrimSpace(string(data)), "\n")
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, "inputs.log")); err == nil {
+			inputs = strings.Split(strings.TrimSpace(string(data)), "\n")
+		}
+		return calls, inputs
+	}
+}
+
+// publishTestReview returns a review with one inline finding, one finding
+// outside the diff, and one file-level finding.
+func publishTestReview(verdict Verdict) *ConsolidatedReview {
+	return &ConsolidatedReview{
+		Verdict:        verdict,
+		VerdictReasons: []string{"claude voted " + string(verdict)},
+		Findings: []*Finding{
+			{Severity: SeverityHigh, Category: "security", File: "auth.go", Line: 12, Description: "Token is logged", Suggestion: "Redact it", Agent: "claude", Location: LocationInDiff, Fingerprint: "aaaa0001"},
+			{Severity: SeverityLow, Category: "style", File: "util.go", Line: 80, Description: "Long function", Agent: "codex", Location: LocationInContext, Fingerprint: "aaaa0002"},
+			{Severity: SeverityMedium, Category: "tests", File: "auth.go", Description: "No tests for refresh", Agent: "claude", Location: LocationInContext, Fingerprint: "aaaa0003"},
+		},
+	}
+}
+
+// decodeReviewRequest decodes a logged request body.
+func decodeReviewRequest(t *testing.T, input string) reviewRequest {
+	t.Helper()
+	var req reviewRequest
+	require.NoError(t, json.Unmarshal([]byte(input), &req))
+	return req
+}
+
+func TestPublishEventFor(t *testing.T) {
+	assert.Equal(t, PublishApprove, publishEventFor(VerdictApproved))
+	assert.Equal(t, PublishRequestChanges, publishEventFor(VerdictChangesNeeded))
+	assert.Equal(t, PublishRequestChanges, publishEventFor(VerdictBlocking))
+}
+
+func TestReviewPublisher_Publish_New(t *testing.T) {
+	logged := fakePublishGH(t, "[]", "[]", "")
+
+	result, err := NewReviewPublisher(NewCLIRunner(""), nil).Publish(context.Background(), 0, publishTestReview(VerdictBlocking))
+	require.NoError(t, err)
+	assert.Equal(t, &PublishResult{
+		Number:    42,
+		URL:       "https://github.com/o/r/pull/42",
+		ReviewURL: "https://github.com/o/r/pull/42#pullrequestreview-99",
+		Event:     PublishRequestChanges,
+		Inline:    1,
+		Summary:   2,
+	}, result)
+
+	calls, inputs := logged()
+	assert.Equal(t, "pr view --json "+prViewFields, calls[0])
+	require.Len(t, inputs, 1)
+	req := decodeReviewRequest(t, inputs[0])
+	assert.Equal(t, testPRHead, req.CommitID)
+	assert.Equal(t, PublishRequestChanges, req.Event)
+	require.Len(t, req.Comments, 1)
+	assert.Equal(t, "auth.go", req.Comments[0].Path)
+	assert.Equal(t, 12, req.Comments[0].Line)
+	assert.Equal(t, "RIGHT", req.Comments[0].Side)
+	assert.Contains(t, req.Comments[0].Body, "**[high] security** (claude)")
+	assert.Contains(t, req.Comments[0].Body, "**Suggestion:** Redact it")
+	assert.Contains(t, req.Comments[0].Body, "<!-- raven-finding: aaaa0001 -->")
+
+	assert.Contains(t, req.Body, "## Raven review: [BLOCK] BLOCKING")
+	assert.Contains(t, req.Body, "- claude voted BLOCKING")
+	assert.Contains(t, req.Body, "| low | util.go | 80 | style | Long function |")
+	assert.Contains(t, req.Body, "| medium | auth.go |  | tests | No tests for refresh |")
+	assert.NotContains(t, req.Body, "Token is logged")
+	assert.True(t, strings.HasSuffix(req.Body, publishReviewMarker))
+}
+
+func TestReviewPublisher_Publish_UpdatesEarlierComments(t *testing.T) {
+	rev := publishTestReview(VerdictChangesNeeded)
+	unchanged := publishFindingBody(rev.Findings[0])
+	comments := fmt.Sprintf(`[{"id":1,"body":%q},{"id":2,"body":"Looks odd to me"}]`+
+		`[{"id":3,"body":"old finding\n\n<!-- raven-finding: bbbb0001 -->"},{"id":4,"body":"x <!-- raven-finding: bbbb0002 resolved -->"}]`,
+		unchanged)
+	reviews := `[{"id":7,"body":"## Raven review` + "\\n\\n" + publishReviewMarker + `"},{"id":8,"body":"LGTM"}]`
+	logged := fakePublishGH(t, comments, reviews, "")
+
+	result, err := NewReviewPublisher(NewCLIRunner(""), nil).Publish(context.Background(), 42, rev)
+	require.NoError(t, err)
+	assert.Equal(t, PublishRequestChanges, result.Event)
+	assert.Equal(t, 0, result.Inline)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Resolved)
+
+	calls, inputs := logged()
+	assert.Equal(t, "pr view 42 --json "+prViewFields, calls[0])
+	var edits []string
+	for _, c := range calls {
+		if strings.Contains(c, "--method PATCH") || strings.Contains(c, "--method PUT") {
+			edits = append(edits, c)
+		}
+	}
+	// The unchanged comment is left alone, the comment of the finding that is
+	// no longer reported is resolved, and the earlier summary is superseded.
+	require.Len(t, edits, 2)
+	assert.Contains(t, edits[0], "repos/{owner}/{repo}/pulls/comments/3")
+	assert.Contains(t, edits[0], "<!-- raven-finding: bbbb0001 resolved -->")
+	assert.Contains(t, edits[0], "old finding")
+	assert.Contains(t, edits[1], "repos/{owner}/{repo}/pulls/42/reviews/7")
+	assert.Contains(t, edits[1], publishSupersededMarker)
+
+	require.Len(t, inputs, 1)
+	req := decodeReviewRequest(t, inputs[0])
+	assert.Empty(t, req.Comments)
+	assert.Contains(t, req.Body, "1 updated inline")
+	assert.Contains(t, req.Body, "1 earlier finding(s) resolved")
+}
+
+func TestReviewPublisher_Publish_ReopensResolvedComment(t *testing.T) {
+	rev := publishTestReview(VerdictChangesNeeded)
+	comments := `[{"id":5,"body":"**Resolved:** gone\n\n<!-- raven-finding: aaaa0001 resolved -->"}]`
+	logged := fakePublishGH(t, comments, "[]", "")
+
+	result, err := NewReviewPublisher(NewCLIRunner(""), nil).Publish(context.Background(), 42, rev)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 0, result.Resolved)
+
+	calls, _ := logged()
+	var patched bool
+	for _, c := range calls {
+		if strings.Contains(c, "--method PATCH repos/{owner}/{repo}/pulls/comments/5") {
+			patched = true
+			assert.Contains(t, c, "<!-- raven-finding: aaaa0001 -->")
+		}
+	}
+	assert.True(t, patched, "resolved comment should be reopened")
+}
+
+func TestReviewPublisher_Publish_FallsBackToComment(t *testing.T) {
+	logged := fakePublishGH(t, "[]", "[]", string(PublishApprove))
+
+	result, err := NewReviewPublisher(NewCLIRunner(""), nil).Publish(context.Background(), 42, &ConsolidatedReview{Verdict: VerdictApproved})
+	require.NoError(t, err)
+	assert.Equal(t, PublishComment, result.Event)
+
+	_, inputs := logged()
+	require.Len(t, inputs, 2)
+	assert.Equal(t, PublishApprove, decodeReviewRequest(t, inputs[0]).Event)
+	second := decodeReviewRequest(t, inputs[1])
+	assert.Equal(t, PublishComment, second.Event)
+	assert.NotNil(t, second.Comments)
+}
+
+func TestReviewPublisher_Publish_ReviewedHead(t *testing.T) {
+	tests := []struct {
+		name    string
+		head    string
+		base    string
+		wantErr string
+	}{
+		{name: "matching", head: testPRHead, base: "main"},
+		{name: "unchecked", head: "", base: ""},
+		{name: "other head", head: strings.Repeat("b", 40), base: "main", wantErr: "but the review ran on bbbbbbb"},
+		{name: "other base", head: testPRHead, base: "develop", wantErr: `targets "main" but the review diffed against "develop"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logged := fakePublishGH(t, "[]", "[]", "")
+
+			publisher := NewReviewPublisher(NewCLIRunner(""), nil).WithReviewedHead(tt.head, tt.base)
+			_, err := publisher.Publish(context.Background(), 0, publishTestReview(VerdictApproved))
+			_, inputs := logged()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				assert.Len(t, inputs, 1)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Empty(t, inputs, "nothing may be posted")
+		})
+	}
+}
+
+func TestReviewPublisher_Publish_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFakeScript(t, dir, "gh", "#!/bin/sh\necho 'no pull requests found for branch' >&2\nexit 1\n")
+	withFakePath(t, dir)
+
+	_, err := NewReviewPublisher(NewCLIRunner(""), nil).Publish(context.Background(), 0, &ConsolidatedReview{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pull request of the current branch")
+	assert.Contains(t, err.Error(), "no pull requests found")
+}
+
+func TestPublishSummaryBody_Lifecycle(t *testing.T) {
+	rev := &ConsolidatedReview{Verdict: VerdictChangesNeeded}
+	assert.NotContains(t, publishSummaryBody(rev, &PublishResult{}, nil), "Since the previous review")
+
+	rev.Lifecycle = &FindingLifecycle{Review: 2, New: 1, Open: 2, Resolved: 3}
+	assert.Contains(t, publishSummaryBody(rev, &PublishResult{}, nil),
+		"Since the previous review: 3 resolved, 2 still open, 1 new, 0 regressed.")
+}
@@ -31,8 +31,15 @@ type reportTemplateStats struct {
 	*ConsolidationStats
 	// FindingsPerAgentKeys is the sorted slice of agent names.
 	FindingsPerAgentKeys []string
+	// FindingsPerPersonaKeys is the sorted slice of persona names.
+	FindingsPerPersonaKeys []string
 	// FindingsPerSeverityKeys is the sorted slice of severity names (by rank, descending).
 	FindingsPerSeverityKeys []Severity
+	// Validated reports whether findings were classified against the diff;
+	// InDiff and InContext are the input findings in each location.
+	Validated bool
+	InDiff    int
+	InContext int
 }
 
 // ReportData is the complete data structure passed to the report template.
@@ -45,6 +52,9 @@ type ReportData struct {
 	// VerdictEmoji is a text indicator: [PASS], [FAIL], or [BLOCK].
 	VerdictEmoji string
 
+	// VerdictReasons explains how the verdict policy reached Verdict.
+	VerdictReasons []string
+
 	// Severity counts for the summary table.
 	TotalFindings int
 	CriticalCount int
@@ -66,6 +76,17 @@ type ReportData struct {
 	FindingsBySeverity     map[Severity][]*Finding
 	FindingsBySeverityKeys []Severity
 
+	// FindingsByPersona maps persona name to the findings it reported; a
+	// finding reported by several personas appears under each. Empty for a
+	// review without personas. Use FindingsByPersonaKeys for deterministic
+	// iteration.
+	FindingsByPersona     map[string][]*Finding
+	FindingsByPersonaKeys []string
+
+	// Waived are the findings matching a baseline waiver, which do not count
+	// toward the verdict.
+	Waived []*Finding
+
 	// AgentResults is the per-agent review result slice.
 	AgentResults []AgentReviewResult
 
@@ -75,6 +96,21 @@ type ReportData struct {
 	// DiffStats summarises the diff.
 	DiffStats DiffStats
 
+	// Incremental reports whether the review only covered the commits made
+	// since the previous review at Since.
+	Incremental bool
+	Since       string
+
+	// Target describes the reviewed changes when they are not a branch
+	// diff. Empty for a branch.
+	Target string
+
+	// Lifecycle compares the findings with the previous review of the
+	// branch; Regressed lists the findings reported again after being
+	// resolved. Nil when the review was not recorded in a finding ledger.
+	Lifecycle *FindingLifecycle
+	Regressed []*Finding
+
 	// GeneratedAt is the timestamp when the report was generated.
 	GeneratedAt time.Time
 }
@@ -100,6 +136,36 @@ func NewReportGenerator(logger *log.Logger) *ReportGenerator {
 			}
 			return fmt.Sprintf("%d", len(ar.Result.Findings))
 		},
+		"confidence": func(c float64) string {
+			if c <= 0 {
+				return "-"
+			}
+			return fmt.Sprintf("%.0f%%", c*100)
+		},
+		"locationLabel": func(l FindingLocation) string {
+			switch l {
+			case LocationInDiff:
+				return "in diff"
+			case LocationInContext:
+				return "file context"
+			case LocationInvalid:
+				return "outside the diff"
+			default:
+				return "unclassified"
+			}
+		},
+		"agentLabel": func(ar AgentReviewResult) string {
+			if ar.Persona != "" {
+				return fmt.Sprintf("%s (%s)", ar.Persona, ar.Agent)
+			}
+			return ar.Agent
+		},
+		"waiverExpiry": func(w *Waiver) string {
+			if w == nil || w.ExpiresAt == nil {
+				return "never"
+			}
+			return w.ExpiresAt.Format("2006-01-02")
+		},
 		"agentStatus": func(ar AgentReviewResult) string {
 			if ar.Err != nil {
 				return "[FAIL]"
@@ -217,6 +283,14 @@ func (rg *ReportGenerator) buildReportData(
 		}
 	}
 
+	// --- Regressed findings ---
+	var regressed []*Finding
+	for _, f := range consolidated.Findings {
+		if f.Status == FindingRegressed {
+			regressed = append(regressed, f)
+		}
+	}
+
 	// --- Findings by file ---
 	findingsByFile := make(map[string][]*Finding)
 	for _, f := range consolidated.Findings {
@@ -242,6 +316,19 @@ func (rg *ReportGenerator) buildReportData(
 		return severityRank(severityKeys[i]) > severityRank(severityKeys[j])
 	})
 
+	// --- Findings by persona ---
+	findingsByPersona := make(map[string][]*Finding)
+	for _, f := range consolidated.Findings {
+		for _, p := range f.Personas {
+			findingsByPersona[p] = append(findingsByPersona[p], f)
+		}
+	}
+	personaKeys := make([]string, 0, len(findingsByPersona))
+	for k := range findingsByPersona {
+		personaKeys = append(personaKeys, k)
+	}
+	sort.Strings(personaKeys)
+
 	// --- Stats with sorted keys ---
 	agentKeys := make([]string, 0, len(stats.FindingsPerAgent))
 	for k := range stats.FindingsPerAgent {
@@ -249,6 +336,12 @@ func (rg *ReportGenerator) buildReportData(
 	}
 	sort.Strings(agentKeys)
 
+	personaStatKeys := make([]string, 0, len(stats.FindingsPerPersona))
+	for k := range stats.FindingsPerPersona {
+		personaStatKeys = append(personaStatKeys, k)
+	}
+	sort.Strings(personaStatKeys)
+
 	sevStatKeys := make([]Severity, 0, len(stats.FindingsPerSeverity))
 	for k := range stats.FindingsPerSeverity {
 		sevStatKeys = append(sevStatKeys, k)
@@ -260,17 +353,25 @@ func (rg *ReportGenerator) buildReportData(
 	wrappedStats := &reportTemplateStats{
 		ConsolidationStats:      stats,
 		FindingsPerAgentKeys:    agentKeys,
+		FindingsPerPersonaKeys:  personaStatKeys,
 		FindingsPerSeverityKeys: sevStatKeys,
+		Validated:               len(stats.FindingsPerLocation) > 0,
+		InDiff:                  stats.FindingsPerLocation[LocationInDiff],
+		InContext:               stats.FindingsPerLocation[LocationInContext],
 	}
 
 	// --- DiffStats ---
 	var ds DiffStats
+	var since, target string
 	if diffResult != nil {
 		ds = diffResult.Stats
+		since = diffResult.Since
+		target = targetLabel(diffResult.Target)
 	}
 
 	return ReportData{
 		Verdict:                consolidated.Verdict,
+		VerdictReasons:         consolidated.VerdictReasons,
 		VerdictEmoji:           verdictEmoji,
 		TotalFindings:          len(consolidated.Findings),
 		CriticalCount:          criticalCount,
@@ -283,9 +384,17 @@ func (rg *ReportGenerator) buildReportData(
 		FindingsByFileKeys:     fileKeys,
 		FindingsBySeverity:     findingsBySeverity,
 		FindingsBySeverityKeys: severityKeys,
+		FindingsByPersona:      findingsByPersona,
+		FindingsByPersonaKeys:  personaKeys,
+		Waived:                 consolidated.Waived,
 		AgentResults:           consolidated.AgentResults,
 		Stats:                  wrappedStats,
 		DiffStats:              ds,
+		Incremental:            since != "",
+		Since:                  since,
+		Target:                 target,
+		Lifecycle:              consolidated.Lifecycle,
+		Regressed:              regressed,
 		GeneratedAt:            time.Now().UTC(),
 	}
 }
@@ -9,6 +9,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/git"
 )
 
 // ---------------------------------------------------------------------------
@@ -405,6 +407,47 @@ func TestGenerate_ConsolidationStats_AllFields(t *testing.T) {
 	assert.Contains(t, report, "codex")
 }
 
+func TestGenerate_FindingValidation(t *testing.T) {
+	t.Parallel()
+
+	rg := NewReportGenerator(nil)
+	findings := []*Finding{
+		{Severity: SeverityHigh, Category: "bug", File: "main.go", Line: 12, Description: "off by one",
+			Location: LocationInDiff, Snippet: "  11 | b := 3\n> 12 | c := 4"},
+	}
+	cr := makeConsolidatedReview(VerdictChangesNeeded, findings, nil)
+	stats := makeStats(4, 1, 0, 0, 0)
+	stats.FindingsPerAgent = map[string]int{"claude": 4}
+	stats.FindingsPerLocation = map[FindingLocation]int{LocationInDiff: 2, LocationInContext: 1, LocationInvalid: 1}
+	stats.InvalidFindings = 1
+	stats.FindingsDropped = 1
+	stats.InvalidPerAgent = map[string]int{"claude": 1}
+	stats.HallucinationRate = map[string]float64{"claude": 25}
+
+	report, err := rg.Generate(cr, stats, makeDiffResult(1, 2, 1))
+	require.NoError(t, err)
+
+	assert.Contains(t, report, "Line 12 (in diff):\n\n```\n  11 | b := 3\n> 12 | c := 4\n```")
+	assert.Contains(t, report, "| In Diff | 2 |")
+	assert.Contains(t, report, "| In File Context | 1 |")
+	assert.Contains(t, report, "| Invalid | 1 (dropped 1, downgraded 0) |")
+	assert.Contains(t, report, "| claude | 4 | 1 | 25.0% |")
+}
+
+func TestGenerate_NoFindingValidation_OmitsLocationStats(t *testing.T) {
+	t.Parallel()
+
+	rg := NewReportGenerator(nil)
+	stats := makeStats(1, 1, 0, 0, 0)
+	stats.FindingsPerAgent = map[string]int{"claude": 1}
+	report, err := rg.Generate(makeConsolidatedReview(VerdictApproved, nil, nil), stats, makeDiffResult(0, 0, 0))
+	require.NoError(t, err)
+
+	assert.NotContains(t, report, "In Diff")
+	assert.NotContains(t, report, "Hallucination Rate")
+	assert.Contains(t, report, "| claude | 1 |")
+}
+
 func TestGenerate_DiffStats_AllFields(t *testing.T) {
 	t.Parallel()
 
@@ -909,3 +952,179 @@ func TestGenerate_Integration_FullPipeline(t *testing.T) {
 	assert.Contains(t, report, "Consolidation Statistics")
 	assert.Contains(t, report, "Diff Statistics")
 }
+
+func TestGenerate_Incremental(t *testing.T) {
+	t.Parallel()
+
+	rg := NewReportGenerator(nil)
+	findings := []*Finding{
+		{Severity: SeverityHigh, Category: "security", File: "auth.go", Line: 3, Description: "open", Agent: "claude", Carried: true},
+	}
+	stats := makeStats(0, 1, 0, 0, 0)
+	stats.CarriedFindings = 1
+	stats.CarriedResolved = 2
+	diff := makeDiffResult(1, 2, 1)
+	diff.Since = "abc1234"
+
+	report, err := rg.Generate(makeConsolidatedReview(VerdictChangesNeeded, findings, nil), stats, diff)
+	require.NoError(t, err)
+	assert.Contains(t, report, "**Incremental:** commits since `abc1234`")
+	assert.Contains(t, report, "| claude (carried) |")
+	assert.Contains(t, report, "| Carried Forward | 1 |")
+	assert.Contains(t, report, "| Resolved Since Last Review | 2 |")
+
+	full, err := rg.Generate(makeConsolidatedReview(VerdictApproved, nil, nil), makeStats(0, 0, 0, 0, 0), makeDiffResult(1, 2, 1))
+	require.NoError(t, err)
+	assert.NotContains(t, full, "Incremental")
+	assert.NotContains(t, full, "Carried Forward")
+}
+
+func TestGenerate_ConfidenceAndSimilarMerged(t *testing.T) {
+	t.Parallel()
+
+	rg := NewReportGenerator(nil)
+	findings := []*Finding{
+		{Severity: SeverityHigh, Category: "bug", File: "a.go", Line: 1, Description: "nil deref", Agent: "claude, codex", Confidence: 0.92},
+		{Severity: SeverityLow, Category: "style", File: "a.go", Line: 2, Description: "naming", Agent: "claude"},
+	}
+	stats := makeStats(3, 2, 0, 0, 50)
+	stats.SimilarMerged = 1
+
+	report, err := rg.Generate(makeConsolidatedReview(VerdictChangesNeeded, findings, nil), stats, makeDiffResult(1, 1, 0))
+	require.NoError(t, err)
+	assert.Contains(t, report, "| Description | Agents | Confidence |")
+	assert.Contains(t, report, "| nil deref | claude, codex | 92% |")
+	assert.Contains(t, report, "| naming | claude | - |")
+	assert.Contains(t, report, "| Similar Findings Merged | 1 |")
+}
+
+func TestGenerate_FindingsByPersona(t *testing.T) {
+	t.Parallel()
+
+	rg := NewReportGenerator(nil)
+	findings := []*Finding{
+		{Severity: SeverityHigh, Category: "security", File: "auth.go", Line: 10, Description: "SQL injection", Agent: "claude", Personas: []string{"security", "api"}},
+		{Severity: SeverityLow, Category: "api", File: "api.go", Line: 3, Description: "Breaking change", Agent: "claude", Personas: []string{"api"}},
+	}
+	agents := []AgentReviewResult{
+		{Agent: "claude", Persona: "security", Result: &ReviewResult{Verdict: VerdictChangesNeeded}},
+		{Agent: "claude", Persona: "api", Result: &ReviewResult{Verdict: VerdictChangesNeeded}},
+	}
+	stats := makeStats(3, 2, 1, 0, 50)
+	stats.FindingsPerPersona = map[string]int{"security": 1, "api": 2}
+
+	report, err := rg.Generate(makeConsolidatedReview(VerdictChangesNeeded, findings, agents), stats, makeDiffResult(2, 2, 0))
+	require.NoError(t, err)
+	assert.Contains(t, report, "## Findings by Persona")
+	apiIdx := strings.Index(report, "### api (2)")
+	securityIdx := strings.Index(report, "### security (1)")
+	require.Positive(t, apiIdx)
+	assert.Greater(t, securityIdx, apiIdx)
+	assert.Contains(t, report, "| security (claude) | CHANGES_NEEDED |")
+	assert.Contains(t, report, "| api | 2 |")
+
+	// Reviews without personas have no persona sections.
+	findings[0].Personas, findings[1].Personas = nil, nil
+	report, err = rg.Generate(makeConsolidatedReview(VerdictChangesNeeded, findings, nil), makeStats(2, 2, 0, 0, 0), makeDiffResult(2, 2, 0))
+	require.NoError(t, err)
+	assert.NotContains(t, report, "Persona")
+}
+
+func TestGenerate_WaivedFindings(t *testing.T) {
+	t.Parallel()
+
+	rg := NewReportGenerator(nil)
+	expires := time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC)
+	findings := []*Finding{
+		{Severity: SeverityLow, Category: "style", File: "main.go", Line: 1, Description: "Long line", Agent: "claude", Fingerprint: "0123456789ab"},
+	}
+	consolidated := makeConsolidatedReview(VerdictChangesNeeded, findings, nil)
+	consolidated.Waived = []*Finding{
+		{Severity: SeverityHigh, Category: "security", File: "auth.go", Line: 10, Description: "Weak hash", Agent: "codex", Fingerprint: "ba9876543210",
+			Waiver: &Waiver{Reason: "legacy | API", Author: "alice", ExpiresAt: &expires}},
+	}
+	stats := makeStats(2, 1, 0, 0, 0)
+	stats.WaivedFindings = 1
+
+	report, err := rg.Generate(consolidated, stats, makeDiffResult(2, 2, 0))
+	require.NoError(t, err)
+	assert.Contains(t, report, "| `0123456789ab` | low | style | main.go | 1 |")
+	assert.Contains(t, report, "## Waived Findings")
+	assert.Contains(t, report, "| `ba9876543210` | high | security | auth.go | 10 | Weak hash | legacy \\| API | alice | 2027-03-01 |")
+	assert.Contains(t, report, "| Waived | 1 |")
+
+	consolidated.Waived = nil
+	report, err = rg.Generate(consolidated, makeStats(1, 1, 0, 0, 0), makeDiffResult(2, 2, 0))
+	require.NoError(t, err)
+	assert.NotContains(t, report, "Waived")
+}
+
+func TestGenerate_VerdictReasons(t *testing.T) {
+	t.Parallel()
+
+	rg := NewReportGenerator(nil)
+	consolidated := makeConsolidatedReview(VerdictChangesNeeded, nil, nil)
+	consolidated.VerdictReasons = []string{
+		"claude voted CHANGES_NEEDED or worse (weight 1, quorum 1)",
+		"codex failed, so the review is incomplete",
+	}
+
+	report, err := rg.Generate(consolidated, makeStats(0, 0, 0, 0, 0), makeDiffResult(1, 1, 0))
+	require.NoError(t, err)
+	assert.Contains(t, report, "Why this verdict:\n\n- claude voted CHANGES_NEEDED or worse (weight 1, quorum 1)\n- codex failed, so the review is incomplete\n")
+
+	consolidated.VerdictReasons = nil
+	report, err = rg.Generate(consolidated, makeStats(0, 0, 0, 0, 0), makeDiffResult(1, 1, 0))
+	require.NoError(t, err)
+	assert.NotContains(t, report, "Why this verdict")
+}
+
+func TestGenerate_Target(t *testing.T) {
+	t.Parallel()
+
+	rg := NewReportGenerator(nil)
+	diff := makeDiffResult(1, 1, 0)
+	diff.Target = git.DiffTarget{Kind: git.TargetCommit, Ref: "abc1234"}
+
+	report, err := rg.Generate(makeConsolidatedReview(VerdictApproved, nil, nil), makeStats(0, 0, 0, 0, 0), diff)
+	require.NoError(t, err)
+	assert.Contains(t, report, "**Target:** commit abc1234")
+
+	diff.Target = git.DiffTarget{Kind: git.TargetBranch, Ref: "main"}
+	report, err = rg.Generate(makeConsolidatedReview(VerdictApproved, nil, nil), makeStats(0, 0, 0, 0, 0), diff)
+	require.NoError(t, err)
+	assert.NotContains(t, report, "**Target:**")
+}
+
+func TestGenerate_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	rg := NewReportGenerator(nil)
+	regressed := &Finding{Severity: SeverityHigh, Category: "security", File: "auth.go", Line: 9, Description: "Token is logged", Fingerprint: "aaaa0001", Status: FindingRegressed}
+	open := &Finding{Severity: SeverityLow, Category: "style", File: "util.go", Line: 3, Description: "Long line", Fingerprint: "aaaa0002", Status: FindingOpen}
+	consolidated := makeConsolidatedReview(VerdictChangesNeeded, []*Finding{regressed, open}, nil)
+	consolidated.Lifecycle = &FindingLifecycle{
+		Review:    4,
+		Open:      1,
+		Resolved:  1,
+		Regressed: 1,
+		ResolvedFindings: []*Finding{
+			{Severity: SeverityMedium, Category: "performance", File: "db.go", Line: 40, Description: "Query in loop", Fingerprint: "bbbb0001", Status: FindingResolved},
+		},
+	}
+
+	report, err := rg.Generate(consolidated, makeStats(2, 2, 0, 0, 0), makeDiffResult(2, 1, 1))
+	require.NoError(t, err)
+	assert.Contains(t, report, "## Finding Lifecycle")
+	assert.Contains(t, report, "Review #4 of this branch, compared with the previous review.")
+	assert.Contains(t, report, "| Resolved | 1 |")
+	assert.Contains(t, report, "### Regressed")
+	assert.Contains(t, report, "| `aaaa0001` | high | security | auth.go | 9 | Token is logged |")
+	assert.Contains(t, report, "### Resolved")
+	assert.Contains(t, report, "| `bbbb0001` | medium | performance | db.go | 40 | Query in loop |")
+	assert.Contains(t, report, "| `aaaa0002` (open) | low |")
+
+	report, err = rg.Generate(makeConsolidatedReview(VerdictApproved, nil, nil), makeStats(0, 0, 0, 0, 0), makeDiffResult(1, 1, 0))
+	require.NoError(t, err)
+	assert.NotContains(t, report, "## Finding Lifecycle")
+}
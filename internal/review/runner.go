@@ -0,0 +1,75 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CLIRunner executes command-line tools such as gh and git and captures their
+// output. PRCreator runs every subprocess through it, and other GitHub
+// integrations share it so that they report failures the same way and can be
+// tested with fake gh scripts on PATH.
+type CLIRunner struct {
+	// WorkDir is the working directory for commands. If empty, commands run
+	// in the current directory.
+	WorkDir string
+}
+
+// NewCLIRunner creates a CLIRunner that runs commands in workDir.
+func NewCLIRunner(workDir string) *CLIRunner {
+	return &CLIRunner{WorkDir: workDir}
+}
+
+// Run executes bin with args and returns (exitCode, stdout, stderr, error).
+// A non-zero exit code is returned as an error carrying the trimmed stderr.
+// exitCode is -1 when the binary itself could not be started (e.g. not in
+// PATH).
+func (r *CLIRunner) Run(ctx context.Context, bin string, args ...string) (int, string, string, error) {
+	cmd := exec.CommandContext(ctx, bin, args...)
+	if r.WorkDir != "" {
+		cmd.Dir = r.WorkDir
+	}
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	cmd.Stdout = &stdoutBuf
+	cmd.Stderr = &stderrBuf
+
+	runErr := cmd.Run()
+
+	if runErr == nil {
+		return 0, stdoutBuf.String(), stderrBuf.String(), nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) {
+		code := exitErr.ExitCode()
+		stdout := stdoutBuf.String()
+		stderr := strings.TrimSpace(stderrBuf.String())
+		return code, stdout, stderr, fmt.Errorf("exit status %d: %s", code, stderr)
+	}
+
+	// Binary could not be started.
+	return -1, "", "", runErr
+}
+
+// GH executes a gh command and returns its stdout.
+func (r *CLIRunner) GH(ctx context.Context, args ...string) (string, error) {
+	_, stdout, _, err := r.Run(ctx, "gh", args...)
+	if err != nil {
+		return "", fmt.Errorf("gh %s: %w", firstArgs(args, 2), err)
+	}
+	return stdout, nil
+}
+
+// firstArgs joins up to n leading arguments for error messages, e.g.
+// "issue create".
+func firstArgs(args []string, n int) string {
+	if len(args) > n {
+		args = args[:n]
+	}
+	return strings.Join(args, " ")
+}
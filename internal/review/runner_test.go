@@ -0,0 +1,39 @@
+package review
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCLIRunner_GH(t *testing.T) {
+	dir := t.TempDir()
+	writeFakeScript(t, dir, "gh", `#!/bin/sh
+if [ "$1" = "fail" ]; then
+  echo "HTTP 404: not found" >&2
+  exit 1
+fi
+echo "args: $*"
+`)
+	withFakePath(t, dir)
+
+	r := NewCLIRunner(dir)
+	out, err := r.GH(context.Background(), "issue", "list", "--json", "number")
+	require.NoError(t, err)
+	assert.Equal(t, "args: issue list --json number\n", out)
+
+	_, err = r.GH(context.Background(), "fail", "now", "please")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gh fail now:")
+	assert.Contains(t, err.Error(), "HTTP 404: not found")
+}
+
+func TestCLIRunner_RunMissingBinary(t *testing.T) {
+	t.Parallel()
+
+	code, _, _, err := NewCLIRunner("").Run(context.Background(), "raven-no-such-binary")
+	require.Error(t, err)
+	assert.Equal(t, -1, code)
+}
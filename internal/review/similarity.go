@@ -0,0 +1,201 @@
+package review
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DedupMode selects how consolidation decides that findings from different
+// agents describe the same issue.
+type DedupMode string
+
+const (
+	// DedupSemantic merges findings that are close in the same file and
+	// similar in category and description, in addition to exact duplicates.
+	DedupSemantic DedupMode = "semantic"
+
+	// DedupExact only merges findings with the same file, line, and category.
+	DedupExact DedupMode = "exact"
+)
+
+// Default similarity thresholds, used when [review] does not set them.
+const (
+	DefaultDedupLineWindow = 3
+	DefaultDedupThreshold  = 0.6
+)
+
+// DedupConfig holds the thresholds of the semantic deduplication pass.
+type DedupConfig struct {
+	// LineWindow is the maximum distance in lines between two findings that
+	// may be merged.
+	LineWindow int
+
+	// Threshold is the minimum FindingSimilarity score (0-1) at which two
+	// findings are merged.
+	Threshold float64
+}
+
+// categorySynonyms maps category spellings agents commonly use to a
+// canonical category. Categories are looked up after NormalizeCategory has
+// lower-cased them and joined words with "-".
+var categorySynonyms = map[string]string{
+	"bug":                 "correctness",
+	"bugs":                "correctness",
+	"logic":               "correctness",
+	"logic-error":         "correctness",
+	"correctness":         "correctness",
+	"error":               "error-handling",
+	"errors":              "error-handling",
+	"error-handling":      "error-handling",
+	"errorhandling":       "error-handling",
+	"security":            "security",
+	"vulnerability":       "security",
+	"sec":                 "security",
+	"performance":         "performance",
+	"perf":                "performance",
+	"efficiency":          "performance",
+	"style":               "style",
+	"formatting":          "style",
+	"naming":              "style",
+	"convention":          "style",
+	"conventions":         "style",
+	"readability":         "style",
+	"documentation":       "documentation",
+	"docs":                "documentation",
+	"doc":                 "documentation",
+	"comments":            "documentation",
+	"testing":             "testing",
+	"tests":               "testing",
+	"test":                "testing",
+	"test-coverage":       "testing",
+	"coverage":            "testing",
+	"concurrency":         "concurrency",
+	"race":                "concurrency",
+	"race-condition":      "concurrency",
+	"thread-safety":       "concurrency",
+	"maintainability":     "maintainability",
+	"complexity":          "maintainability",
+	"design":              "maintainability",
+	"resource-leak":       "resource-management",
+	"resource-leaks":      "resource-management",
+	"memory-leak":         "resource-management",
+	"resource":            "resource-management",
+	"resources":           "resource-management",
+	"resource-management": "resource-management",
+}
+
+// NormalizeCategory returns the canonical form of a finding category: lower
+// case, words joined by "-", and common synonyms mapped to one name (e.g.
+// "bug" and "Logic Error" both become "correctness").
+func NormalizeCategory(category string) string {
+	c := strings.ToLower(strings.TrimSpace(category))
+	c = strings.Join(strings.FieldsFunc(c, func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-' || r == '/'
+	}), "-")
+	if canonical, ok := categorySynonyms[c]; ok {
+		return canonical
+	}
+	return c
+}
+
+// stopWords are dropped when comparing descriptions because they carry no
+// information about the issue itself.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "can": true, "could": true, "for": true, "from": true,
+	"here": true, "if": true, "in": true, "is": true, "it": true, "its": true,
+	"line": true, "may": true, "might": true, "of": true, "on": true, "or": true,
+	"possible": true, "potential": true, "potentially": true, "should": true,
+	"that": true, "the": true, "this": true, "to": true, "when": true,
+	"which": true, "with": true,
+}
+
+// tokenSynonyms maps description words to a shared token so that different
+// phrasings of the same issue compare as similar.
+var tokenSynonyms = map[string]string{
+	"null":        "nil",
+	"deref":       "dereference",
+	"ptr":         "pointer",
+	"err":         "error",
+	"unchecked":   "unhandled",
+	"ignored":     "unhandled",
+	"leaked":      "leak",
+	"crash":       "panic",
+	"concurrent":  "race",
+	"unsanitized": "unvalidated",
+}
+
+// descriptionTokens returns the set of significant words in s: lower-cased,
+// without stop words, numbers, or one-letter words, with a plural "s"
+// trimmed and synonyms mapped to a shared token.
+func descriptionTokens(s string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(w) < 2 || stopWords[w] || strings.IndexFunc(w, unicode.IsLetter) < 0 {
+			continue
+		}
+		if len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+			w = strings.TrimSuffix(w, "s")
+		}
+		if syn, ok := tokenSynonyms[w]; ok {
+			w = syn
+		}
+		tokens[w] = true
+	}
+	return tokens
+}
+
+// DescriptionSimilarity returns the Dice coefficient (0-1) of the significant
+// words of two descriptions. Two empty descriptions have similarity 0.
+func DescriptionSimilarity(a, b string) float64 {
+	ta, tb := descriptionTokens(a), descriptionTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ta)+len(tb))
+}
+
+// FindingSimilarity scores (0-1) how likely a and b describe the same issue.
+// Findings in different files, or further than window lines apart, score 0.
+// Otherwise the score weighs line proximity and category agreement once each
+// and description similarity twice:
+//
+//	(proximity + category + 2*description) / 4
+//
+// where proximity falls linearly from 1 on the same line to 1/(window+1) at
+// the edge of the window. File-level findings (line 0) are only close to
+// other file-level findings.
+func FindingSimilarity(a, b *Finding, window int) float64 {
+	if normalizeFindingPath(a.File) != normalizeFindingPath(b.File) {
+		return 0
+	}
+	if (a.Line == 0) != (b.Line == 0) {
+		return 0
+	}
+	dist := a.Line - b.Line
+	if dist < 0 {
+		dist = -dist
+	}
+	if window < 0 {
+		window = 0
+	}
+	if dist > window {
+		return 0
+	}
+	proximity := 1 - float64(dist)/float64(window+1)
+
+	category := 0.0
+	if NormalizeCategory(a.Category) == NormalizeCategory(b.Category) {
+		category = 1
+	}
+
+	return (proximity + category + 2*DescriptionSimilarity(a.Description, b.Description)) / 4
+}
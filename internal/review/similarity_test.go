@@ -0,0 +1,62 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCategory(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{
+		"bug":            "correctness",
+		"Logic Error":    "correctness",
+		"Correctness":    "correctness",
+		"error_handling": "error-handling",
+		"Error Handling": "error-handling",
+		"perf":           "performance",
+		"Race condition": "concurrency",
+		" docs ":         "documentation",
+		"api-design":     "api-design",
+	} {
+		assert.Equal(t, want, NormalizeCategory(in), in)
+	}
+}
+
+func TestDescriptionSimilarity(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 1.0, DescriptionSimilarity("Nil pointer dereference", "nil pointer dereferences"), 0.001)
+	// {nil, dereference} vs {nil, pointer, dereference}.
+	assert.InDelta(t, 0.8, DescriptionSimilarity("nil deref at line 41", "possible nil pointer dereference at line 42"), 0.001)
+	assert.InDelta(t, 0.0, DescriptionSimilarity("SQL injection in query", "missing doc comment"), 0.001)
+	assert.InDelta(t, 0.0, DescriptionSimilarity("", "anything"), 0.001)
+	assert.InDelta(t, 0.0, DescriptionSimilarity("the a of", "the a of"), 0.001)
+}
+
+func TestFindingSimilarity(t *testing.T) {
+	t.Parallel()
+
+	claude := &Finding{File: "svc/user.go", Line: 41, Category: "bug", Description: "nil deref"}
+	codex := &Finding{File: "./svc/user.go", Line: 42, Category: "correctness", Description: "possible nil pointer dereference"}
+
+	// proximity 0.75, category 1, description 0.8: (0.75 + 1 + 1.6) / 4.
+	assert.InDelta(t, 0.8375, FindingSimilarity(claude, codex, 3), 0.001)
+	assert.InDelta(t, FindingSimilarity(codex, claude, 3), FindingSimilarity(claude, codex, 3), 0.001)
+
+	far := *codex
+	far.Line = 45
+	assert.Zero(t, FindingSimilarity(claude, &far, 3))
+
+	otherFile := *codex
+	otherFile.File = "svc/order.go"
+	assert.Zero(t, FindingSimilarity(claude, &otherFile, 3))
+
+	fileLevel := *codex
+	fileLevel.Line = 0
+	assert.Zero(t, FindingSimilarity(claude, &fileLevel, 50))
+
+	unrelated := &Finding{File: "svc/user.go", Line: 42, Category: "style", Description: "exported function lacks doc comment"}
+	assert.Less(t, FindingSimilarity(claude, unrelated, 3), DefaultDedupThreshold)
+}
@@ -0,0 +1,149 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ReviewState records the outcome of the last completed review of a branch,
+// so that the next incremental review only diffs the commits made since and
+// can carry forward the findings that were still open.
+type ReviewState struct {
+	// Branch is the branch that was reviewed.
+	Branch string `json:"branch"`
+
+	// BaseBranch is the base the branch was reviewed against.
+	BaseBranch string `json:"base_branch"`
+
+	// HeadSHA is the full SHA of the reviewed HEAD commit.
+	HeadSHA string `json:"head_sha"`
+
+	// ReviewedAt is when the review completed.
+	ReviewedAt time.Time `json:"reviewed_at"`
+
+	// Verdict is the consolidated verdict of the review.
+	Verdict Verdict `json:"verdict"`
+
+	// Findings are the findings that were open after the review.
+	Findings []*Finding `json:"findings"`
+}
+
+// ReviewStateStore persists ReviewState as one JSON file per branch inside a
+// single directory (by default .raven/review). Writes are atomic: the file is
+// written to a .tmp path and then renamed.
+type ReviewStateStore struct {
+	dir string
+}
+
+// NewReviewStateStore creates a ReviewStateStore backed by dir. The directory
+// is created on the first Save.
+func NewReviewStateStore(dir string) *ReviewStateStore {
+	return &ReviewStateStore{dir: dir}
+}
+
+// branchFileName returns the name of the file recorded for branch. The
+// branch is path-escaped, which is reversible, so that distinct branches such
+// as "feature/x" and "feature_x" never share a file.
+func branchFileName(branch string) string {
+	return url.PathEscape(branch) + ".json"
+}
+
+// path returns the state file for branch.
+func (s *ReviewStateStore) path(branch string) string {
+	return filepath.Join(s.dir, branchFileName(branch))
+}
+
+// Load returns the saved state for branch, or nil when the branch has not
+// been reviewed yet. A file that records a different branch, e.g. one
+// written on a case-insensitive file system, is an error.
+func (s *ReviewStateStore) Load(branch string) (*ReviewState, error) {
+	data, err := os.ReadFile(s.path(branch))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("review: state: read %q: %w", branch, err)
+	}
+	var state ReviewState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("review: state: parse %q: %w", branch, err)
+	}
+	if state.Branch != branch {
+		return nil, fmt.Errorf("review: state: %q: file records branch %q", branch, state.Branch)
+	}
+	return &state, nil
+}
+
+// Save writes state, replacing any previous state of the same branch.
+func (s *ReviewStateStore) Save(state *ReviewState) error {
+	if state.Branch == "" {
+		return fmt.Errorf("review: state: branch is required")
+	}
+	if err := writeJSONAtomic(s.path(state.Branch), state); err != nil {
+		return fmt.Errorf("review: state: %q: %w", state.Branch, err)
+	}
+	return nil
+}
+
+// ledgerPath returns the finding ledger file for branch, kept in the
+// "ledger" subdirectory so that it cannot collide with a state file.
+func (s *ReviewStateStore) ledgerPath(branch string) string {
+	return filepath.Join(s.dir, "ledger", branchFileName(branch))
+}
+
+// LoadLedger returns the finding ledger of branch, or an empty ledger when
+// no review of the branch has been recorded in one yet. Like Load, it rejects
+// a file that records a different branch.
+func (s *ReviewStateStore) LoadLedger(branch string) (*FindingLedger, error) {
+	data, err := os.ReadFile(s.ledgerPath(branch))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewFindingLedger(branch), nil
+		}
+		return nil, fmt.Errorf("review: ledger: read %q: %w", branch, err)
+	}
+	var ledger FindingLedger
+	if err := json.Unmarshal(data, &ledger); err != nil {
+		return nil, fmt.Errorf("review: ledger: parse %q: %w", branch, err)
+	}
+	if ledger.Branch != branch {
+		return nil, fmt.Errorf("review: ledger: %q: file records branch %q", branch, ledger.Branch)
+	}
+	return &ledger, nil
+}
+
+// SaveLedger writes ledger, replacing the previous ledger of its branch.
+func (s *ReviewStateStore) SaveLedger(ledger *FindingLedger) error {
+	if ledger.Branch == "" {
+		return fmt.Errorf("review: ledger: branch is required")
+	}
+	if err := writeJSONAtomic(s.ledgerPath(ledger.Branch), ledger); err != nil {
+		return fmt.Errorf("review: ledger: %q: %w", ledger.Branch, err)
+	}
+	return nil
+}
+
+// writeJSONAtomic writes v as indented JSON to path, creating its directory.
+// The file is written to a .tmp path and then renamed.
+func writeJSONAtomic(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("rename: %w", err)
+	}
+	return nil
+}
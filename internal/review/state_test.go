@@ -0,0 +1,96 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewStateStore_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), ".raven", "review")
+	store := NewReviewStateStore(dir)
+
+	state := &ReviewState{
+		Branch:     "feature/login",
+		BaseBranch: "main",
+		HeadSHA:    "0123456789abcdef0123456789abcdef01234567",
+		ReviewedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Verdict:    VerdictChangesNeeded,
+		Findings: []*Finding{
+			{Severity: SeverityHigh, Category: "security", File: "auth.go", Line: 3, Description: "d", Agent: "claude"},
+		},
+	}
+	require.NoError(t, store.Save(state))
+	assert.FileExists(t, filepath.Join(dir, "feature%2Flogin.json"))
+	assert.NoFileExists(t, filepath.Join(dir, "feature%2Flogin.json.tmp"))
+
+	got, err := store.Load("feature/login")
+	require.NoError(t, err)
+	assert.Equal(t, state, got)
+
+	// Saving again replaces the previous state.
+	state.HeadSHA = "fedcba9876543210fedcba9876543210fedcba98"
+	state.Findings = nil
+	require.NoError(t, store.Save(state))
+	got, err = store.Load("feature/login")
+	require.NoError(t, err)
+	assert.Equal(t, state.HeadSHA, got.HeadSHA)
+	assert.Empty(t, got.Findings)
+}
+
+func TestReviewStateStore_LoadMissing(t *testing.T) {
+	t.Parallel()
+
+	got, err := NewReviewStateStore(t.TempDir()).Load("main")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestReviewStateStore_LoadCorrupt(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.json"), []byte("{"), 0o600))
+	_, err := NewReviewStateStore(dir).Load("main")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parse")
+}
+
+func TestReviewStateStore_DistinctBranchFiles(t *testing.T) {
+	t.Parallel()
+
+	store := NewReviewStateStore(t.TempDir())
+	require.NoError(t, store.Save(&ReviewState{Branch: "feature/x", HeadSHA: "aaa"}))
+	require.NoError(t, store.Save(&ReviewState{Branch: "feature_x", HeadSHA: "bbb"}))
+
+	got, err := store.Load("feature/x")
+	require.NoError(t, err)
+	assert.Equal(t, "aaa", got.HeadSHA)
+	got, err = store.Load("feature_x")
+	require.NoError(t, err)
+	assert.Equal(t, "bbb", got.HeadSHA)
+}
+
+func TestReviewStateStore_LoadBranchMismatch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	data := []byte(`{"branch": "Main", "head_sha": "aaa"}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.json"), data, 0o600))
+	_, err := NewReviewStateStore(dir).Load("main")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `file records branch "Main"`)
+}
+
+func TestReviewStateStore_SaveRequiresBranch(t *testing.T) {
+	t.Parallel()
+
+	err := NewReviewStateStore(t.TempDir()).Save(&ReviewState{})
+	require.Error(t, err)
+}
@@ -3,6 +3,8 @@ package review
 import (
 	"fmt"
 	"time"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/git"
 )
 
 // Verdict represents the overall outcome of a code review.
@@ -56,7 +58,8 @@ var validSeverities = map[Severity]bool{
 }
 
 // Finding represents a single issue identified by a reviewing agent.
-// The Agent field is empty in raw agent output and is populated during consolidation.
+// The Agent, Location, and Snippet fields are empty in raw agent output and
+// are populated during consolidation.
 type Finding struct {
 	Severity    Severity `json:"severity"`
 	Category    string   `json:"category"`
@@ -65,6 +68,34 @@ type Finding struct {
 	Description string   `json:"description"`
 	Suggestion  string   `json:"suggestion"`
 	Agent       string   `json:"agent,omitempty"`
+
+	// Location classifies File and Line against the reviewed diff. Empty
+	// when the findings were consolidated without a diff.
+	Location FindingLocation `json:"location,omitempty"`
+
+	// Snippet is a numbered excerpt of the lines around Line.
+	Snippet string `json:"snippet,omitempty"`
+	// Carried marks an open finding from a previous review that an
+	// incremental review carried forward unchanged because none of the new
+	// commits touched its file.
+	Carried bool `json:"carried,omitempty"`
+	// Confidence (0-1) is the share of the reviewing agents that reported
+	// the finding, with agents whose report was merged in by similarity
+	// counting as much as their similarity score.
+	Confidence float64 `json:"confidence,omitempty"`
+	// Personas are the reviewer personas that reported the finding, in the
+	// order they reported it. Empty for a review without personas.
+	Personas []string `json:"personas,omitempty"`
+	// Fingerprint is the finding's stable ID (see FindingFingerprint), set
+	// during consolidation.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Waiver is the baseline waiver the finding matched. Set only on the
+	// findings in ConsolidatedReview.Waived.
+	Waiver *Waiver `json:"waiver,omitempty"`
+	// Status is the finding's lifecycle status compared with the previous
+	// review of the branch (see FindingLedger). Empty when the review was not
+	// recorded in a ledger.
+	Status FindingStatus `json:"status,omitempty"`
 }
 
 // DeduplicationKey returns a composite key of "file:line:category" used to
@@ -100,22 +131,36 @@ func (rr *ReviewResult) Validate() error {
 // AgentReviewResult captures the outcome of a single agent's review, including
 // timing information and any error that occurred during the review run.
 // RawOutput preserves the full agent output for debugging and extraction retries.
+// Persona is the reviewer persona the agent acted as, empty for a review
+// without personas. ChunkErr is set when some, but not all, chunks of a
+// chunked review failed: Result then holds the other chunks' findings and
+// the verdict policy counts the agent as failed as well.
 type AgentReviewResult struct {
 	Agent     string
+	Persona   string
 	Result    *ReviewResult
 	Duration  time.Duration
 	Err       error
+	ChunkErr  error
 	RawOutput string
 }
 
 // ConsolidatedReview is the merged result of all agent review runs for a single
 // diff. Findings from all agents are de-duplicated and sorted by severity.
+// Findings matching a baseline waiver are in Waived instead of Findings and
+// do not count toward the verdict. VerdictReasons explains, one sentence
+// each, how the verdict policy reached Verdict.
 type ConsolidatedReview struct {
-	Findings     []*Finding
-	Verdict      Verdict
-	AgentResults []AgentReviewResult
-	TotalAgents  int
-	Duration     time.Duration
+	Findings       []*Finding
+	Waived         []*Finding
+	Verdict        Verdict
+	VerdictReasons []string
+	AgentResults   []AgentReviewResult
+	TotalAgents    int
+	Duration       time.Duration
+	// Lifecycle compares the findings with the previous review of the
+	// branch. Nil when the review was not recorded in a FindingLedger.
+	Lifecycle *FindingLifecycle
 }
 
 // ReviewConfig holds configuration for the review pipeline, read from the [review]
@@ -137,6 +182,22 @@ type ReviewConfig struct {
 
 	// ProjectBriefFile is the path to the project brief used to give agents context.
 	ProjectBriefFile string `toml:"project_brief_file"`
+
+	// InvalidFindings selects what happens to findings on lines outside the
+	// diff's files or past the end of a file: "drop" (the default) or
+	// "downgrade" to info severity.
+	InvalidFindings string `toml:"invalid_findings"`
+
+	// Dedup selects how findings from different agents are merged: "semantic"
+	// (the default) or "exact" file, line, and category matches only.
+	Dedup string `toml:"dedup"`
+
+	// DedupLineWindow and DedupThreshold tune semantic deduplication: the
+	// maximum line distance between merged findings and the minimum
+	// similarity score (0-1). A nil window and a zero threshold mean the
+	// default; a window of 0 merges findings on the same line only.
+	DedupLineWindow *int    `toml:"dedup_line_window"`
+	DedupThreshold  float64 `toml:"dedup_threshold"`
 }
 
 // ReviewMode controls how the diff is distributed across reviewing agents.
@@ -165,6 +226,23 @@ type ReviewOpts struct {
 	// BaseBranch is the Git ref to diff against (e.g. "main").
 	BaseBranch string
 
+	// Since makes the review incremental: when set, only the commits in
+	// Since..HEAD are reviewed instead of BaseBranch...HEAD.
+	Since string
+
+	// Target, when its Kind is set, selects the changes to review instead of
+	// BaseBranch...HEAD: a commit range, a single commit, the staged changes,
+	// or the working tree. Since is ignored for such a target.
+	Target git.DiffTarget
+
+	// Personas, when set, replace Agents: each persona's agent reviews the
+	// changed files matching the persona's paths, and Mode is ignored.
+	Personas []Persona
+
+	// Analyzers are static-analysis commands run before the agents. Their
+	// findings on the changed files are consolidated with the agents'.
+	Analyzers []Analyzer
+
 	// DryRun prints the review plan without executing any agent.
 	DryRun bool
 }
@@ -193,13 +193,7 @@ func (vr *VerificationRunner) RunSingle(ctx context.Context, command string) (*C
 		defer cancel()
 	}
 
-	// Choose the shell wrapper based on the OS.
-	var shellCmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		shellCmd = exec.CommandContext(execCtx, "cmd", "/c", command)
-	} else {
-		shellCmd = exec.CommandContext(execCtx, "sh", "-c", command)
-	}
+	shellCmd := shellCommand(execCtx, command)
 
 	if vr.workDir != "" {
 		shellCmd.Dir = vr.workDir
@@ -274,6 +268,15 @@ func (vr *VerificationRunner) RunSingle(ctx context.Context, command string) (*C
 	return result, nil
 }
 
+// shellCommand wraps command in the platform's shell: "sh -c" or, on
+// Windows, "cmd /c".
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/c", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
 // FormatReport produces a human-readable terminal summary of the verification
 // results, suitable for direct output to a terminal or log file.
 //
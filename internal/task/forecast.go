@@ -0,0 +1,173 @@
+package task
+
+import (
+	"strings"
+	"time"
+)
+
+// Effort buckets used to group tasks for velocity and forecasting.
+const (
+	EffortSmall  = "small"
+	EffortMedium = "medium"
+	EffortLarge  = "large"
+)
+
+// effortBuckets lists the effort buckets from smallest to largest.
+var effortBuckets = []string{EffortSmall, EffortMedium, EffortLarge}
+
+// EffortBucket classifies a free-form effort estimate as small (up to 3
+// hours), medium (up to 9 hours) or large, using EffortHours. Tasks without
+// an estimate count as medium.
+func EffortBucket(effort string) string {
+	if strings.TrimSpace(effort) == "" {
+		return EffortMedium
+	}
+	switch h := EffortHours(effort); {
+	case h <= 3:
+		return EffortSmall
+	case h <= 9:
+		return EffortMedium
+	default:
+		return EffortLarge
+	}
+}
+
+// Velocity is the average measured effort of completed tasks in one effort
+// bucket.
+type Velocity struct {
+	// Bucket is the effort bucket: small, medium or large.
+	Bucket string `json:"bucket"`
+	// Samples is the number of completed tasks with recorded metrics.
+	Samples int `json:"samples"`
+	// WallTime, AgentTime and CostUSD are per-task averages; zero when
+	// Samples is zero.
+	WallTime  time.Duration `json:"wall_time_ns"`
+	AgentTime time.Duration `json:"agent_time_ns"`
+	CostUSD   float64       `json:"cost_usd"`
+}
+
+// PhaseForecast estimates the effort left in one phase.
+type PhaseForecast struct {
+	// PhaseID is the phase ID, or 0 for the total over all tasks.
+	PhaseID int `json:"phase_id"`
+	// PhaseName is the phase name.
+	PhaseName string `json:"phase_name"`
+	// Remaining is the number of tasks not yet completed or skipped.
+	Remaining int `json:"remaining"`
+	// Unestimated is the number of remaining tasks for which no velocity
+	// data exists; they are excluded from the estimates.
+	Unestimated int `json:"unestimated"`
+	// WallTime, AgentTime and CostUSD are the estimated totals for the
+	// remaining tasks, run one after another.
+	WallTime  time.Duration `json:"wall_time_ns"`
+	AgentTime time.Duration `json:"agent_time_ns"`
+	CostUSD   float64       `json:"cost_usd"`
+}
+
+// Forecast estimates remaining time and cost per phase from the metrics of
+// completed tasks.
+type Forecast struct {
+	// Velocity holds one entry per effort bucket, smallest first.
+	Velocity []Velocity `json:"velocity"`
+	// Phases holds one entry per phase in phase order. When no phases are
+	// configured it holds a single entry with PhaseID 0.
+	Phases []PhaseForecast `json:"phases"`
+	// Total sums the per-phase forecasts.
+	Total PhaseForecast `json:"total"`
+}
+
+// BuildForecast estimates the remaining effort for specs. Each remaining task
+// is assumed to take the average of the completed tasks in its effort bucket;
+// a bucket without samples falls back to the average over all completed
+// tasks, and without any samples the task is counted as unestimated. Tasks
+// outside every phase count towards Total only.
+func BuildForecast(specs []*ParsedTaskSpec, states map[string]*TaskState, metrics map[string]TaskMetrics, phases []Phase) Forecast {
+	statusOf := func(id string) TaskStatus {
+		if s, ok := states[id]; ok && s != nil {
+			return s.Status
+		}
+		return StatusNotStarted
+	}
+
+	// Average the metrics of completed tasks per bucket and overall.
+	sums := make(map[string]*Velocity, len(effortBuckets))
+	for _, b := range effortBuckets {
+		sums[b] = &Velocity{Bucket: b}
+	}
+	overall := &Velocity{}
+	for _, spec := range specs {
+		m, ok := metrics[spec.ID]
+		if !ok || m.Iterations == 0 || statusOf(spec.ID) != StatusCompleted {
+			continue
+		}
+		for _, v := range []*Velocity{sums[EffortBucket(spec.Effort)], overall} {
+			v.Samples++
+			v.WallTime += m.WallTime
+			v.AgentTime += m.AgentTime
+			v.CostUSD += m.CostUSD
+		}
+	}
+	average := func(v *Velocity) {
+		if v.Samples == 0 {
+			return
+		}
+		v.WallTime /= time.Duration(v.Samples)
+		v.AgentTime /= time.Duration(v.Samples)
+		v.CostUSD /= float64(v.Samples)
+	}
+	average(overall)
+
+	fc := Forecast{Velocity: make([]Velocity, 0, len(effortBuckets))}
+	for _, b := range effortBuckets {
+		average(sums[b])
+		fc.Velocity = append(fc.Velocity, *sums[b])
+	}
+
+	estimate := func(pf *PhaseForecast, spec *ParsedTaskSpec) {
+		pf.Remaining++
+		v := sums[EffortBucket(spec.Effort)]
+		if v.Samples == 0 {
+			v = overall
+		}
+		if v.Samples == 0 {
+			pf.Unestimated++
+			return
+		}
+		pf.WallTime += v.WallTime
+		pf.AgentTime += v.AgentTime
+		pf.CostUSD += v.CostUSD
+	}
+
+	var phaseForecasts []*PhaseForecast
+	byPhase := make(map[int]*PhaseForecast, len(phases))
+	for _, p := range phases {
+		pf := &PhaseForecast{PhaseID: p.ID, PhaseName: p.Name}
+		phaseForecasts = append(phaseForecasts, pf)
+		byPhase[p.ID] = pf
+	}
+	membership := NewPhaseMembership(phases, specs)
+	fc.Total = PhaseForecast{PhaseName: "Total"}
+	for _, spec := range specs {
+		if st := statusOf(spec.ID); st == StatusCompleted || st == StatusSkipped {
+			continue
+		}
+		estimate(&fc.Total, spec)
+		if p := membership.PhaseOf(spec.ID); p != nil {
+			if pf, ok := byPhase[p.ID]; ok {
+				estimate(pf, spec)
+			}
+		}
+	}
+
+	if len(phases) == 0 {
+		all := fc.Total
+		all.PhaseName = "All Tasks"
+		fc.Phases = []PhaseForecast{all}
+		return fc
+	}
+	fc.Phases = make([]PhaseForecast, 0, len(phaseForecasts))
+	for _, pf := range phaseForecasts {
+		fc.Phases = append(fc.Phases, *pf)
+	}
+	return fc
+}
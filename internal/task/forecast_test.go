@@ -0,0 +1,111 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffortBucket(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		effort string
+		want   string
+	}{
+		{"", EffortMedium},
+		{"small", EffortSmall},
+		{"Small: 1-3hrs", EffortSmall},
+		{"Medium: 4-8hrs", EffortMedium},
+		{"medium", EffortMedium},
+		{"Large: 10-16hrs", EffortLarge},
+		{"large", EffortLarge},
+		{"12h", EffortLarge},
+		{"2 hours", EffortSmall},
+	}
+	for _, tt := range tests {
+		t.Run(tt.effort, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, EffortBucket(tt.effort))
+		})
+	}
+}
+
+func forecastSpec(id, effort string) *ParsedTaskSpec {
+	spec := makeSpec(id, nil)
+	spec.Effort = effort
+	return spec
+}
+
+func TestBuildForecast(t *testing.T) {
+	t.Parallel()
+
+	specs := []*ParsedTaskSpec{
+		forecastSpec("T-001", "small"),
+		forecastSpec("T-002", "small"),
+		forecastSpec("T-003", "large"),
+		forecastSpec("T-004", "small"),
+		forecastSpec("T-005", "large"),
+		forecastSpec("T-006", "medium"),
+		forecastSpec("T-007", "small"),
+	}
+	states := map[string]*TaskState{
+		"T-001": {TaskID: "T-001", Status: StatusCompleted},
+		"T-002": {TaskID: "T-002", Status: StatusCompleted},
+		"T-003": {TaskID: "T-003", Status: StatusCompleted},
+		"T-004": {TaskID: "T-004", Status: StatusInProgress},
+		"T-007": {TaskID: "T-007", Status: StatusSkipped},
+	}
+	metrics := map[string]TaskMetrics{
+		"T-001": {TaskID: "T-001", Iterations: 1, WallTime: 10 * time.Minute, AgentTime: 8 * time.Minute, CostUSD: 1},
+		"T-002": {TaskID: "T-002", Iterations: 2, WallTime: 20 * time.Minute, AgentTime: 16 * time.Minute, CostUSD: 3},
+		"T-003": {TaskID: "T-003", Iterations: 3, WallTime: 60 * time.Minute, AgentTime: 30 * time.Minute, CostUSD: 8},
+		// In progress: not a velocity sample.
+		"T-004": {TaskID: "T-004", Iterations: 1, WallTime: time.Hour, CostUSD: 100},
+	}
+	phases := []Phase{
+		{ID: 1, Name: "Foundation", StartTask: "T-001", EndTask: "T-004"},
+		{ID: 2, Name: "Interface", StartTask: "T-005", EndTask: "T-006"},
+	}
+
+	fc := BuildForecast(specs, states, metrics, phases)
+
+	assert.Equal(t, []Velocity{
+		{Bucket: EffortSmall, Samples: 2, WallTime: 15 * time.Minute, AgentTime: 12 * time.Minute, CostUSD: 2},
+		{Bucket: EffortMedium},
+		{Bucket: EffortLarge, Samples: 1, WallTime: 60 * time.Minute, AgentTime: 30 * time.Minute, CostUSD: 8},
+	}, fc.Velocity)
+
+	require.Len(t, fc.Phases, 2)
+	// Phase 1: T-004 (small) remains.
+	assert.Equal(t, PhaseForecast{
+		PhaseID: 1, PhaseName: "Foundation", Remaining: 1,
+		WallTime: 15 * time.Minute, AgentTime: 12 * time.Minute, CostUSD: 2,
+	}, fc.Phases[0])
+	// Phase 2: T-005 (large) plus T-006 (medium, no samples: overall average
+	// of 30m wall, 18m agent, $4).
+	assert.Equal(t, PhaseForecast{
+		PhaseID: 2, PhaseName: "Interface", Remaining: 2,
+		WallTime: 90 * time.Minute, AgentTime: 48 * time.Minute, CostUSD: 12,
+	}, fc.Phases[1])
+
+	assert.Equal(t, 3, fc.Total.Remaining)
+	assert.Equal(t, 105*time.Minute, fc.Total.WallTime)
+	assert.InDelta(t, 14.0, fc.Total.CostUSD, 1e-9)
+}
+
+func TestBuildForecast_NoHistory(t *testing.T) {
+	t.Parallel()
+
+	specs := []*ParsedTaskSpec{forecastSpec("T-001", "small"), forecastSpec("T-002", "")}
+	fc := BuildForecast(specs, map[string]*TaskState{}, map[string]TaskMetrics{}, nil)
+
+	require.Len(t, fc.Phases, 1)
+	assert.Equal(t, 0, fc.Phases[0].PhaseID)
+	assert.Equal(t, 2, fc.Phases[0].Remaining)
+	assert.Equal(t, 2, fc.Phases[0].Unestimated)
+	assert.Zero(t, fc.Phases[0].WallTime)
+	assert.Equal(t, 2, fc.Total.Unestimated)
+}
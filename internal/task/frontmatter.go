@@ -0,0 +1,212 @@
+package task
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Front matter delimiters. A task spec may begin with a YAML block fenced by
+// "---" lines or a TOML block fenced by "+++" lines (the Hugo convention).
+const (
+	yamlFrontMatterDelim = "---"
+	tomlFrontMatterDelim = "+++"
+)
+
+// TaskFrontMatter is the optional structured metadata block at the top of a
+// task spec file. When present, its non-empty fields take precedence over the
+// values parsed from the markdown heading and metadata table.
+//
+// Example (YAML):
+//
+//	---
+//	id: T-042
+//	title: Add retry budget
+//	dependencies: [T-040, T-041]
+//	priority: Must Have
+//	effort: small
+//	labels: [api, reliability]
+//	owners: [alice]
+//	files: ["internal/agent/*.go"]
+//	acceptance_criteria:
+//	  - Retries stop after the configured budget
+//	agent: codex
+//	model: gpt-5-codex
+//	verification_commands:
+//	  - go test ./internal/agent/...
+//	github_issue: 128
+//	---
+type TaskFrontMatter struct {
+	ID                   string   `yaml:"id" toml:"id"`
+	Title                string   `yaml:"title" toml:"title"`
+	Dependencies         []string `yaml:"dependencies" toml:"dependencies"`
+	Priority             string   `yaml:"priority" toml:"priority"`
+	Effort               string   `yaml:"effort" toml:"effort"`
+	Labels               []string `yaml:"labels" toml:"labels"`
+	Owners               []string `yaml:"owners" toml:"owners"`
+	Files                []string `yaml:"files" toml:"files"`
+	AcceptanceCriteria   []string `yaml:"acceptance_criteria" toml:"acceptance_criteria"`
+	Agent                string   `yaml:"agent" toml:"agent"`
+	Model                string   `yaml:"model" toml:"model"`
+	VerificationCommands []string `yaml:"verification_commands" toml:"verification_commands"`
+	GitHubIssue          int      `yaml:"github_issue" toml:"github_issue"`
+}
+
+// splitFrontMatter detects a front matter block at the very start of content.
+// It returns the raw block (without delimiters), the delimiter that fenced it,
+// the markdown body following the closing delimiter, and ok=true when a
+// complete block was found. content must already be BOM-stripped and use "\n"
+// line endings. A missing closing delimiter is an error so that a truncated
+// block is not silently treated as markdown.
+func splitFrontMatter(content string) (block, delim, body string, ok bool, err error) {
+	firstLine, rest, _ := strings.Cut(content, "\n")
+	delim = strings.TrimRight(firstLine, " \t")
+	if delim != yamlFrontMatterDelim && delim != tomlFrontMatterDelim {
+		return "", "", content, false, nil
+	}
+
+	lines := strings.Split(rest, "\n")
+	for i, line := range lines {
+		if strings.TrimRight(line, " \t") == delim {
+			return strings.Join(lines[:i], "\n"), delim, strings.Join(lines[i+1:], "\n"), true, nil
+		}
+	}
+	return "", "", content, false, fmt.Errorf("front matter opened with %q on line 1 is never closed", delim)
+}
+
+// parseFrontMatter decodes a raw front matter block. YAML blocks ("---") are
+// decoded with yaml.v3 and TOML blocks ("+++") with BurntSushi/toml. Unknown
+// keys are rejected so that typos (e.g. "dependancies") surface immediately
+// instead of being silently ignored.
+func parseFrontMatter(block, delim string) (*TaskFrontMatter, error) {
+	var fm TaskFrontMatter
+	switch delim {
+	case yamlFrontMatterDelim:
+		if strings.TrimSpace(block) == "" {
+			return &fm, nil
+		}
+		dec := yaml.NewDecoder(bytes.NewReader([]byte(block)))
+		dec.KnownFields(true)
+		if err := dec.Decode(&fm); err != nil {
+			return nil, fmt.Errorf("decoding YAML front matter: %w", err)
+		}
+	case tomlFrontMatterDelim:
+		md, err := toml.Decode(block, &fm)
+		if err != nil {
+			return nil, fmt.Errorf("decoding TOML front matter: %w", err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, 0, len(undecoded))
+			for _, k := range undecoded {
+				keys = append(keys, k.String())
+			}
+			sort.Strings(keys)
+			return nil, fmt.Errorf("decoding TOML front matter: unknown keys: %s", strings.Join(keys, ", "))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported front matter delimiter %q", delim)
+	}
+	return &fm, nil
+}
+
+// applyFrontMatter overlays the non-empty fields of fm onto spec. Front matter
+// always wins over values parsed from the markdown heading and table.
+// Returns an error when fm.ID is set but is not a valid task ID.
+func applyFrontMatter(spec *ParsedTaskSpec, fm *TaskFrontMatter) error {
+	if id := strings.TrimSpace(fm.ID); id != "" {
+		if !reTaskID.MatchString(id) {
+			return fmt.Errorf("front matter id %q is not a valid task ID (expected PREFIX-NNN, e.g. T-042)", fm.ID)
+		}
+		spec.ID = id
+	}
+	if title := strings.TrimSpace(fm.Title); title != "" {
+		spec.Title = title
+	}
+	if fm.Dependencies != nil {
+		spec.Dependencies = extractTaskRefs(strings.Join(fm.Dependencies, ","))
+	}
+	if p := strings.TrimSpace(fm.Priority); p != "" {
+		spec.Priority = p
+	}
+	if e := strings.TrimSpace(fm.Effort); e != "" {
+		spec.Effort = e
+	}
+	if a := strings.TrimSpace(fm.Agent); a != "" {
+		spec.Agent = a
+	}
+	if m := strings.TrimSpace(fm.Model); m != "" {
+		spec.Model = m
+	}
+	spec.Labels = cleanList(fm.Labels)
+	spec.Owners = cleanList(fm.Owners)
+	spec.Files = cleanList(fm.Files)
+	spec.AcceptanceCriteria = cleanList(fm.AcceptanceCriteria)
+	spec.VerificationCommands = cleanList(fm.VerificationCommands)
+	if fm.GitHubIssue < 0 {
+		return fmt.Errorf("front matter github_issue %d must be positive", fm.GitHubIssue)
+	}
+	spec.GitHubIssue = fm.GitHubIssue
+	spec.HasFrontMatter = true
+	return nil
+}
+
+// cleanList trims every entry of items and drops empty ones. It always returns
+// a non-nil slice so that JSON output renders [] rather than null.
+func cleanList(items []string) []string {
+	out := make([]string, 0, len(items))
+	for _, it := range items {
+		if it = strings.TrimSpace(it); it != "" {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// SetGitHubIssue returns the spec content with the github_issue front matter
+// key set to number. An existing key is replaced and a missing one is added at
+// the end of the front matter block; a spec without front matter gets a new
+// YAML block. The new line uses the file's line ending, taken from its first
+// line, and the rest of the content is left unchanged.
+func SetGitHubIssue(content string, number int) (string, error) {
+	bom := ""
+	if strings.HasPrefix(content, utf8BOM) {
+		bom = utf8BOM
+		content = strings.TrimPrefix(content, utf8BOM)
+	}
+	eol := "\n"
+	if first, _, found := strings.Cut(content, "\n"); found && strings.HasSuffix(first, "\r") {
+		eol = "\r\n"
+	}
+
+	_, delim, _, ok, err := splitFrontMatter(strings.ReplaceAll(content, "\r\n", "\n"))
+	if err != nil {
+		return "", fmt.Errorf("setting github_issue: %w", err)
+	}
+	if !ok {
+		return fmt.Sprintf("%s---%sgithub_issue: %d%s---%s%s", bom, eol, number, eol, eol, content), nil
+	}
+
+	sep, line := ":", fmt.Sprintf("github_issue: %d", number)
+	if delim == tomlFrontMatterDelim {
+		sep, line = "=", fmt.Sprintf("github_issue = %d", number)
+	}
+	// lines keep their own endings so that untouched lines are copied as-is.
+	lines := strings.SplitAfter(content, "\n")
+	for i := 1; i < len(lines); i++ {
+		l := strings.TrimRight(lines[i], "\r\n")
+		if strings.TrimRight(l, " \t") == delim {
+			lines = append(lines[:i], append([]string{line + eol}, lines[i:]...)...)
+			break
+		}
+		key, _, found := strings.Cut(l, sep)
+		if found && strings.TrimSpace(key) == "github_issue" && !strings.HasPrefix(l, " ") {
+			lines[i] = line + lines[i][len(l):]
+			break
+		}
+	}
+	return bom + strings.Join(lines, ""), nil
+}
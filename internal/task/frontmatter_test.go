@@ -0,0 +1,231 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaskSpec_YAMLFrontMatter(t *testing.T) {
+	t.Parallel()
+
+	content := `---
+id: T-042
+title: Add retry budget
+dependencies: [T-040, T-041]
+priority: Must Have
+effort: small
+labels: [api, reliability]
+owners: [alice]
+files: ["internal/agent/*.go"]
+acceptance_criteria:
+  - Retries stop after the configured budget
+  - "  "
+agent: codex
+model: gpt-5-codex
+verification_commands:
+  - go test ./internal/agent/...
+---
+# T-042: Add retry budget
+
+## Goal
+Stop retrying forever.
+`
+	spec, err := ParseTaskSpec(content)
+	require.NoError(t, err)
+	assert.True(t, spec.HasFrontMatter)
+	assert.Equal(t, "T-042", spec.ID)
+	assert.Equal(t, "Add retry budget", spec.Title)
+	assert.Equal(t, []string{"T-040", "T-041"}, spec.Dependencies)
+	assert.Equal(t, "Must Have", spec.Priority)
+	assert.Equal(t, "small", spec.Effort)
+	assert.Equal(t, []string{"api", "reliability"}, spec.Labels)
+	assert.Equal(t, []string{"alice"}, spec.Owners)
+	assert.Equal(t, []string{"internal/agent/*.go"}, spec.Files)
+	assert.Equal(t, []string{"Retries stop after the configured budget"}, spec.AcceptanceCriteria)
+	assert.Equal(t, "codex", spec.Agent)
+	assert.Equal(t, "gpt-5-codex", spec.Model)
+	assert.Equal(t, []string{"go test ./internal/agent/..."}, spec.VerificationCommands)
+	assert.Equal(t, content, spec.Content)
+}
+
+func TestParseTaskSpec_TOMLFrontMatter(t *testing.T) {
+	t.Parallel()
+
+	content := `+++
+id = "T-007"
+title = "Config loader"
+dependencies = ["T-001"]
+labels = ["config"]
++++
+## Goal
+Load raven.toml.
+`
+	spec, err := ParseTaskSpec(content)
+	require.NoError(t, err)
+	assert.Equal(t, "T-007", spec.ID)
+	assert.Equal(t, "Config loader", spec.Title)
+	assert.Equal(t, []string{"T-001"}, spec.Dependencies)
+	assert.Equal(t, []string{"config"}, spec.Labels)
+	assert.Empty(t, spec.Owners)
+	assert.NotNil(t, spec.Owners)
+}
+
+func TestParseTaskSpec_FrontMatterWinsOverTable(t *testing.T) {
+	t.Parallel()
+
+	content := "---\npriority: Should Have\ndependencies: []\n---\n" +
+		makeFullSpec(t, "T-010", "Table title", "Must Have", "Large", "T-003, T-004", "None", "None")
+
+	spec, err := ParseTaskSpec(content)
+	require.NoError(t, err)
+	assert.Equal(t, "T-010", spec.ID, "heading ID kept when front matter has none")
+	assert.Equal(t, "Table title", spec.Title)
+	assert.Equal(t, "Should Have", spec.Priority)
+	assert.Equal(t, "Large", spec.Effort, "table effort kept when front matter has none")
+	assert.Empty(t, spec.Dependencies, "explicit empty dependency list overrides the table")
+}
+
+func TestParseTaskSpec_FrontMatterErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "unclosed YAML block",
+			content: "---\nid: T-001\n# T-001: Title\n",
+			wantErr: "never closed",
+		},
+		{
+			name:    "unknown YAML key",
+			content: "---\nid: T-001\ntitle: X\ndependancies: [T-002]\n---\n",
+			wantErr: "dependancies",
+		},
+		{
+			name:    "unknown TOML key",
+			content: "+++\nid = \"T-001\"\ntitle = \"X\"\nowner = \"bob\"\n+++\n",
+			wantErr: "unknown keys: owner",
+		},
+		{
+			name:    "invalid id",
+			content: "---\nid: TASK-1\ntitle: X\n---\n",
+			wantErr: "not a valid task ID",
+		},
+		{
+			name:    "no heading and no title",
+			content: "---\nid: T-001\n---\nBody only.\n",
+			wantErr: "parsing task spec",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseTaskSpec(tt.content)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestParseTaskSpec_NoFrontMatter(t *testing.T) {
+	t.Parallel()
+
+	spec, err := ParseTaskSpec(makeFullSpec(t, "T-001", "Plain", "Must Have", "Small", "None", "None", "None"))
+	require.NoError(t, err)
+	assert.False(t, spec.HasFrontMatter)
+	assert.Empty(t, spec.Labels)
+	assert.Empty(t, spec.VerificationCommands)
+	assert.Empty(t, spec.Agent)
+}
+
+func TestParsedTaskSpec_HasLabel(t *testing.T) {
+	t.Parallel()
+
+	spec := &ParsedTaskSpec{Labels: []string{"API", "reliability"}}
+	assert.True(t, spec.HasLabel("api"))
+	assert.True(t, spec.HasLabel(" reliability "))
+	assert.False(t, spec.HasLabel("ui"))
+}
+
+func TestSetGitHubIssue(t *testing.T) {
+	t.Parallel()
+
+	body := "# T-001: Setup\n"
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "no front matter",
+			content: body,
+			want:    "---\ngithub_issue: 12\n---\n" + body,
+		},
+		{
+			name:    "append to yaml",
+			content: "---\nlabels: [api]\n---\n" + body,
+			want:    "---\nlabels: [api]\ngithub_issue: 12\n---\n" + body,
+		},
+		{
+			name:    "replace in yaml",
+			content: "---\ngithub_issue: 3\nlabels: [api]\n---\n" + body,
+			want:    "---\ngithub_issue: 12\nlabels: [api]\n---\n" + body,
+		},
+		{
+			name:    "toml",
+			content: "+++\nlabels = [\"api\"]\n+++\n" + body,
+			want:    "+++\nlabels = [\"api\"]\ngithub_issue = 12\n+++\n" + body,
+		},
+		{
+			name:    "crlf",
+			content: "---\r\nlabels: [api]\r\n---\r\n# T-001: Setup\r\n",
+			want:    "---\r\nlabels: [api]\r\ngithub_issue: 12\r\n---\r\n# T-001: Setup\r\n",
+		},
+		{
+			name:    "crlf replace",
+			content: "---\r\ngithub_issue: 3\r\nlabels: [api]\r\n---\r\n# T-001: Setup\r\n",
+			want:    "---\r\ngithub_issue: 12\r\nlabels: [api]\r\n---\r\n# T-001: Setup\r\n",
+		},
+		{
+			name:    "crlf without front matter",
+			content: "# T-001: Setup\r\n\r\nBody\r\n",
+			want:    "---\r\ngithub_issue: 12\r\n---\r\n# T-001: Setup\r\n\r\nBody\r\n",
+		},
+		{
+			name:    "mixed endings are kept",
+			content: "---\nlabels: [api]\n---\n# T-001: Setup\r\nBody\r\n",
+			want:    "---\nlabels: [api]\ngithub_issue: 12\n---\n# T-001: Setup\r\nBody\r\n",
+		},
+		{
+			name:    "bom",
+			content: utf8BOM + "---\nlabels: [api]\n---\n" + body,
+			want:    utf8BOM + "---\nlabels: [api]\ngithub_issue: 12\n---\n" + body,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := SetGitHubIssue(tt.content, 12)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTaskSpec_GitHubIssue(t *testing.T) {
+	t.Parallel()
+
+	content := "---\ngithub_issue: 42\n---\n" + makeFullSpec(t, "T-001", "Plain", "Must Have", "Small", "None", "None", "None")
+	spec, err := ParseTaskSpec(content)
+	require.NoError(t, err)
+	assert.Equal(t, 42, spec.GitHubIssue)
+
+	_, err = ParseTaskSpec("---\ngithub_issue: -1\n---\n" + makeFullSpec(t, "T-001", "Plain", "Must Have", "Small", "None", "None", "None"))
+	require.Error(t, err)
+}
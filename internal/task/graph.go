@@ -0,0 +1,503 @@
+package task
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultEffortHours is the weight given to tasks without a recognisable
+// effort estimate, so that unestimated tasks still contribute to path length.
+const defaultEffortHours = 1.0
+
+// effortKeywordHours maps effort size keywords to the midpoint of the hour
+// ranges used by generated task specs ("Small: 1-3hrs", "Medium: 4-8hrs",
+// "Large: 10-16hrs").
+var effortKeywordHours = []struct {
+	keyword string
+	hours   float64
+}{
+	{"small", 2},
+	{"medium", 6},
+	{"large", 13},
+}
+
+var (
+	// reEffortRange matches an hour range such as "4-8hrs" or "1.5 - 3 hours".
+	reEffortRange = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*-\s*(\d+(?:\.\d+)?)\s*h`)
+
+	// reEffortHours matches a single hour estimate such as "6hrs" or "2 h".
+	reEffortHours = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*h`)
+)
+
+// EffortHours converts a free-form effort estimate into an hour weight.
+// Explicit hours win ("Medium: 4-8hrs" is 6, the midpoint of the range);
+// otherwise the size keyword is used (small=2, medium=6, large=13). Empty or
+// unrecognised estimates weigh 1.
+func EffortHours(effort string) float64 {
+	if m := reEffortRange.FindStringSubmatch(effort); m != nil {
+		lo, errLo := strconv.ParseFloat(m[1], 64)
+		hi, errHi := strconv.ParseFloat(m[2], 64)
+		if errLo == nil && errHi == nil && hi >= lo {
+			return (lo + hi) / 2
+		}
+	}
+	if m := reEffortHours.FindStringSubmatch(effort); m != nil {
+		if h, err := strconv.ParseFloat(m[1], 64); err == nil && h > 0 {
+			return h
+		}
+	}
+	lower := strings.ToLower(effort)
+	for _, k := range effortKeywordHours {
+		if strings.Contains(lower, k.keyword) {
+			return k.hours
+		}
+	}
+	return defaultEffortHours
+}
+
+// GraphNode is a single task in a TaskGraph.
+type GraphNode struct {
+	// ID is the task identifier, e.g. "T-016".
+	ID string `json:"id"`
+	// Title is the task title; empty for dependencies without a spec file.
+	Title string `json:"title,omitempty"`
+	// Status is the task status from the state file (not_started when absent).
+	Status TaskStatus `json:"status"`
+	// Phase is the ID of the phase containing the task, or 0 when unknown.
+	Phase int `json:"phase,omitempty"`
+	// Effort is the raw effort estimate from the task spec.
+	Effort string `json:"effort,omitempty"`
+	// Weight is the effort in hours as computed by EffortHours.
+	Weight float64 `json:"weight"`
+	// Dependencies are the IDs of tasks this task depends on.
+	Dependencies []string `json:"dependencies"`
+	// External reports that the node lies outside the graph's scope (another
+	// phase, or a dependency with no spec file) and is present only because a
+	// task in scope depends on it.
+	External bool `json:"external,omitempty"`
+	// Ready reports that the task is not started and all of its dependencies
+	// are completed, i.e. it is on the parallelisable frontier.
+	Ready bool `json:"ready,omitempty"`
+	// Critical reports that the task lies on the critical path.
+	Critical bool `json:"critical,omitempty"`
+}
+
+// GraphEdge is a dependency edge: To depends on From.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// TaskGraph is the dependency DAG of a set of tasks annotated with live status,
+// the remaining critical path, and the frontier of tasks that can start now.
+type TaskGraph struct {
+	// Nodes are the graph's tasks sorted by task ID.
+	Nodes []GraphNode `json:"nodes"`
+	// Edges are the dependency edges sorted by (To, From).
+	Edges []GraphEdge `json:"edges"`
+	// CriticalPath is the longest effort-weighted chain of unfinished tasks,
+	// ordered from the first task to do to the last.
+	CriticalPath []string `json:"critical_path"`
+	// CriticalPathHours is the total effort weight of CriticalPath.
+	CriticalPathHours float64 `json:"critical_path_hours"`
+	// Frontier are the IDs of in-scope tasks that are ready to start now and
+	// can be worked on in parallel.
+	Frontier []string `json:"frontier"`
+}
+
+// BuildTaskGraph builds the dependency graph for specs using the statuses in
+// stateMap (missing entries count as not_started). phases is used to label
+// each node with its phase and may be nil.
+//
+// When scope is non-nil only the specs for which scope returns true are in
+// the graph; out-of-scope dependencies of in-scope tasks are included as
+// External nodes so that cross-phase blockers remain visible. Dependencies
+// that have no spec file are always included as External nodes.
+//
+// Returns an error when the dependencies contain a cycle.
+func BuildTaskGraph(specs []*ParsedTaskSpec, stateMap map[string]*TaskState, phases []Phase, scope func(*ParsedTaskSpec) bool) (*TaskGraph, error) {
+	specMap := make(map[string]*ParsedTaskSpec, len(specs))
+	for _, s := range specs {
+		specMap[s.ID] = s
+	}
+	membership := NewPhaseMembership(phases, specs)
+
+	statusOf := func(id string) TaskStatus {
+		if ts, ok := stateMap[id]; ok {
+			return ts.Status
+		}
+		return StatusNotStarted
+	}
+
+	nodes := make(map[string]*GraphNode)
+	addNode := func(id string, external bool) *GraphNode {
+		if n, ok := nodes[id]; ok {
+			return n
+		}
+		n := &GraphNode{
+			ID:           id,
+			Status:       statusOf(id),
+			Weight:       defaultEffortHours,
+			Dependencies: []string{},
+			External:     external,
+		}
+		if spec, ok := specMap[id]; ok {
+			n.Title = spec.Title
+			n.Effort = spec.Effort
+			n.Weight = EffortHours(spec.Effort)
+			n.Dependencies = append(n.Dependencies, spec.Dependencies...)
+		}
+		if ph := membership.PhaseOf(id); ph != nil {
+			n.Phase = ph.ID
+		}
+		nodes[id] = n
+		return n
+	}
+
+	for _, s := range specs {
+		if scope == nil || scope(s) {
+			addNode(s.ID, false)
+		}
+	}
+
+	// Pull in the direct dependencies of in-scope nodes. Dependencies of
+	// External nodes are not followed; edges between External nodes are kept
+	// only when both ends are already in the graph.
+	inScope := make([]*GraphNode, 0, len(nodes))
+	for _, n := range nodes {
+		inScope = append(inScope, n)
+	}
+	for _, n := range inScope {
+		for _, dep := range n.Dependencies {
+			addNode(dep, true)
+		}
+	}
+	edges := []GraphEdge{}
+	for _, n := range nodes {
+		kept := n.Dependencies[:0]
+		for _, dep := range n.Dependencies {
+			if _, ok := nodes[dep]; ok {
+				kept = append(kept, dep)
+				edges = append(edges, GraphEdge{From: dep, To: n.ID})
+			}
+		}
+		n.Dependencies = kept
+	}
+
+	g := &TaskGraph{
+		Nodes:        make([]GraphNode, 0, len(nodes)),
+		Edges:        edges,
+		CriticalPath: []string{},
+		Frontier:     []string{},
+	}
+	for _, n := range nodes {
+		g.Nodes = append(g.Nodes, *n)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return CompareTaskIDs(g.Nodes[i].ID, g.Nodes[j].ID) < 0
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if c := CompareTaskIDs(g.Edges[i].To, g.Edges[j].To); c != 0 {
+			return c < 0
+		}
+		return CompareTaskIDs(g.Edges[i].From, g.Edges[j].From) < 0
+	})
+
+	if err := g.analyze(); err != nil {
+		return nil, fmt.Errorf("building task graph: %w", err)
+	}
+	return g, nil
+}
+
+// Node returns the node with the given ID, or nil when it is not in the graph.
+func (g *TaskGraph) Node(id string) *GraphNode {
+	for i := range g.Nodes {
+		if g.Nodes[i].ID == id {
+			return &g.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// analyze computes the frontier and the critical path and marks the
+// corresponding nodes. g.Nodes must be sorted by ID.
+func (g *TaskGraph) analyze() error {
+	index := make(map[string]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		index[n.ID] = i
+	}
+
+	// Frontier: in-scope, not started, every dependency completed, as the
+	// selector requires. A skipped dependency does not count.
+	for i := range g.Nodes {
+		n := &g.Nodes[i]
+		if n.External || n.Status != StatusNotStarted {
+			continue
+		}
+		ready := true
+		for _, dep := range n.Dependencies {
+			if d, ok := index[dep]; !ok || g.Nodes[d].Status != StatusCompleted {
+				ready = false
+				break
+			}
+		}
+		if ready {
+			n.Ready = true
+			g.Frontier = append(g.Frontier, n.ID)
+		}
+	}
+
+	order, err := g.topoOrder(index)
+	if err != nil {
+		return err
+	}
+
+	// Longest path over unfinished tasks. Only completed tasks are done and
+	// do not extend a path; a skipped task still blocks its dependents, as
+	// in the selector.
+	done := func(n *GraphNode) bool {
+		return n.Status == StatusCompleted
+	}
+	dist := make([]float64, len(g.Nodes))
+	prev := make([]int, len(g.Nodes))
+	best := -1
+	for _, i := range order {
+		n := &g.Nodes[i]
+		prev[i] = -1
+		if done(n) {
+			continue
+		}
+		for _, dep := range n.Dependencies {
+			d := index[dep]
+			if done(&g.Nodes[d]) {
+				continue
+			}
+			if prev[i] == -1 || dist[d] > dist[prev[i]] {
+				prev[i] = d
+			}
+		}
+		dist[i] = n.Weight
+		if prev[i] != -1 {
+			dist[i] += dist[prev[i]]
+		}
+		if best == -1 || dist[i] > dist[best] || (dist[i] == dist[best] && i < best) {
+			best = i
+		}
+	}
+
+	if best == -1 {
+		return nil
+	}
+	g.CriticalPathHours = dist[best]
+	for i := best; i != -1; i = prev[i] {
+		g.Nodes[i].Critical = true
+		g.CriticalPath = append(g.CriticalPath, g.Nodes[i].ID)
+	}
+	for l, r := 0, len(g.CriticalPath)-1; l < r; l, r = l+1, r-1 {
+		g.CriticalPath[l], g.CriticalPath[r] = g.CriticalPath[r], g.CriticalPath[l]
+	}
+	return nil
+}
+
+// topoOrder returns node indexes in dependency order (dependencies first),
+// breaking ties by task ID. Returns an error naming the tasks involved when
+// the graph contains a cycle.
+func (g *TaskGraph) topoOrder(index map[string]int) ([]int, error) {
+	indegree := make([]int, len(g.Nodes))
+	dependents := make([][]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		for _, dep := range n.Dependencies {
+			d := index[dep]
+			indegree[i]++
+			dependents[d] = append(dependents[d], i)
+		}
+	}
+
+	// g.Nodes is sorted by ID, so scanning for the lowest ready index keeps
+	// the order deterministic.
+	var queue []int
+	for i := range g.Nodes {
+		if indegree[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	order := make([]int, 0, len(g.Nodes))
+	for len(queue) > 0 {
+		sort.Ints(queue)
+		i := queue[0]
+		queue = queue[1:]
+		order = append(order, i)
+		for _, d := range dependents[i] {
+			indegree[d]--
+			if indegree[d] == 0 {
+				queue = append(queue, d)
+			}
+		}
+	}
+
+	if len(order) < len(g.Nodes) {
+		var cyclic []string
+		for i, n := range g.Nodes {
+			if indegree[i] > 0 {
+				cyclic = append(cyclic, n.ID)
+			}
+		}
+		return nil, fmt.Errorf("dependency cycle among tasks %s", strings.Join(cyclic, ", "))
+	}
+	return order, nil
+}
+
+// graphStatusColors are the fill colours used for each status when rendering
+// a graph. Unknown statuses render as not_started.
+var graphStatusColors = map[TaskStatus]string{
+	StatusNotStarted: "#ffffff",
+	StatusInProgress: "#ffeb9c",
+	StatusCompleted:  "#c6efce",
+	StatusBlocked:    "#ffc7ce",
+	StatusSkipped:    "#d9d9d9",
+}
+
+// graphCriticalColor is the outline colour of critical-path nodes and edges.
+const graphCriticalColor = "#d32f2f"
+
+// statusColor returns the fill colour for status.
+func statusColor(status TaskStatus) string {
+	if c, ok := graphStatusColors[status]; ok {
+		return c
+	}
+	return graphStatusColors[StatusNotStarted]
+}
+
+// criticalEdge reports whether e connects two consecutive critical-path tasks.
+func (g *TaskGraph) criticalEdge(e GraphEdge) bool {
+	for i := 1; i < len(g.CriticalPath); i++ {
+		if g.CriticalPath[i-1] == e.From && g.CriticalPath[i] == e.To {
+			return true
+		}
+	}
+	return false
+}
+
+// DOT renders the graph in Graphviz DOT format. Nodes are filled by status,
+// critical-path nodes and edges are outlined in red, frontier nodes get a
+// double border, and External nodes are dashed.
+func (g *TaskGraph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph tasks {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+
+	for _, n := range g.Nodes {
+		label := n.ID
+		if n.Title != "" {
+			label += "\n" + n.Title
+		}
+		styles := []string{"rounded", "filled"}
+		if n.External {
+			styles = append(styles, "dashed")
+		}
+		attrs := []string{
+			fmt.Sprintf("label=%s", dotQuote(label)),
+			fmt.Sprintf("fillcolor=%s", dotQuote(statusColor(n.Status))),
+			fmt.Sprintf("style=%s", dotQuote(strings.Join(styles, ","))),
+			fmt.Sprintf("tooltip=%s", dotQuote(string(n.Status))),
+		}
+		if n.Critical {
+			attrs = append(attrs, fmt.Sprintf("color=%s", dotQuote(graphCriticalColor)), "penwidth=2")
+		}
+		if n.Ready {
+			attrs = append(attrs, "peripheries=2")
+		}
+		fmt.Fprintf(&sb, "  %s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+	}
+
+	for _, e := range g.Edges {
+		if g.criticalEdge(e) {
+			fmt.Fprintf(&sb, "  %s -> %s [color=%s, penwidth=2];\n",
+				dotQuote(e.From), dotQuote(e.To), dotQuote(graphCriticalColor))
+			continue
+		}
+		fmt.Fprintf(&sb, "  %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. Nodes are styled by
+// status through classDef entries; critical-path nodes and edges are
+// highlighted and External nodes are dashed.
+func (g *TaskGraph) Mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+
+	for _, n := range g.Nodes {
+		label := n.ID
+		if n.Title != "" {
+			label += ": " + n.Title
+		}
+		fmt.Fprintf(&sb, "    %s[\"%s\"]\n", mermaidID(n.ID), mermaidEscape(label))
+	}
+
+	var critical []string
+	for i, e := range g.Edges {
+		fmt.Fprintf(&sb, "    %s --> %s\n", mermaidID(e.From), mermaidID(e.To))
+		if g.criticalEdge(e) {
+			critical = append(critical, strconv.Itoa(i))
+		}
+	}
+
+	for _, status := range ValidStatuses() {
+		fmt.Fprintf(&sb, "    classDef %s fill:%s,stroke:#555555\n", status, statusColor(status))
+	}
+	fmt.Fprintf(&sb, "    classDef critical stroke:%s,stroke-width:3px\n", graphCriticalColor)
+	sb.WriteString("    classDef external stroke-dasharray:5 5\n")
+	sb.WriteString("    classDef ready stroke-width:3px\n")
+
+	for _, n := range g.Nodes {
+		status := n.Status
+		if _, ok := graphStatusColors[status]; !ok {
+			status = StatusNotStarted
+		}
+		classes := []string{string(status)}
+		if n.Ready {
+			classes = append(classes, "ready")
+		}
+		if n.External {
+			classes = append(classes, "external")
+		}
+		if n.Critical {
+			classes = append(classes, "critical")
+		}
+		for _, c := range classes {
+			fmt.Fprintf(&sb, "    class %s %s\n", mermaidID(n.ID), c)
+		}
+	}
+
+	if len(critical) > 0 {
+		fmt.Fprintf(&sb, "    linkStyle %s stroke:%s,stroke-width:3px\n",
+			strings.Join(critical, ","), graphCriticalColor)
+	}
+	return sb.String()
+}
+
+// dotQuote returns s as a double-quoted DOT string.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// mermaidID converts a task ID into a Mermaid-safe node identifier.
+func mermaidID(id string) string {
+	return strings.NewReplacer("-", "_", " ", "_").Replace(id)
+}
+
+// mermaidEscape escapes characters that would terminate a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
@@ -0,0 +1,201 @@
+package task
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// graphSpec builds a spec with an effort estimate for graph tests.
+func graphSpec(id, effort string, deps ...string) *ParsedTaskSpec {
+	s := makeSpec(id, deps)
+	s.Effort = effort
+	return s
+}
+
+// graphState builds a state map from alternating task ID / status pairs.
+func graphState(pairs ...string) map[string]*TaskState {
+	m := make(map[string]*TaskState, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		m[pairs[i]] = &TaskState{TaskID: pairs[i], Status: TaskStatus(pairs[i+1])}
+	}
+	return m
+}
+
+func TestEffortHours(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		effort string
+		want   float64
+	}{
+		{"Medium: 4-8hrs", 6},
+		{"Large: 10-16hrs", 13},
+		{"1.5 - 2.5 hours", 2},
+		{"5h", 5},
+		{"small", 2},
+		{"Medium", 6},
+		{"LARGE", 13},
+		{"", 1},
+		{"unknown", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.effort, func(t *testing.T) {
+			t.Parallel()
+			assert.InDelta(t, tt.want, EffortHours(tt.effort), 0.001)
+		})
+	}
+}
+
+func TestBuildTaskGraph_CriticalPathAndFrontier(t *testing.T) {
+	t.Parallel()
+
+	// T-001 (done) -> T-002 (small) -> T-004 (small)
+	// T-001 (done) -> T-003 (large) -> T-004
+	// T-005 is independent and not started.
+	specs := []*ParsedTaskSpec{
+		graphSpec("T-001", "Large"),
+		graphSpec("T-002", "Small", "T-001"),
+		graphSpec("T-003", "Large", "T-001"),
+		graphSpec("T-004", "Small", "T-002", "T-003"),
+		graphSpec("T-005", "Medium"),
+	}
+	state := graphState("T-001", "completed", "T-003", "in_progress")
+
+	g, err := BuildTaskGraph(specs, state, selectorPhases(), nil)
+	require.NoError(t, err)
+
+	assert.Len(t, g.Nodes, 5)
+	assert.Len(t, g.Edges, 4)
+	assert.Equal(t, []string{"T-003", "T-004"}, g.CriticalPath)
+	assert.InDelta(t, 15.0, g.CriticalPathHours, 0.001)
+	assert.Equal(t, []string{"T-002", "T-005"}, g.Frontier)
+
+	assert.True(t, g.Node("T-003").Critical)
+	assert.False(t, g.Node("T-001").Critical, "completed tasks carry no remaining work")
+	assert.True(t, g.Node("T-002").Ready)
+	assert.False(t, g.Node("T-004").Ready)
+	assert.Equal(t, StatusInProgress, g.Node("T-003").Status)
+	assert.Equal(t, StatusNotStarted, g.Node("T-004").Status)
+	assert.Equal(t, 1, g.Node("T-004").Phase)
+}
+
+func TestBuildTaskGraph_ScopeAddsExternalDependencies(t *testing.T) {
+	t.Parallel()
+
+	specs := []*ParsedTaskSpec{
+		graphSpec("T-001", "Small"),
+		graphSpec("T-002", "Small", "T-001"),
+		graphSpec("T-006", "Small", "T-002", "T-099"),
+		graphSpec("T-007", "Small", "T-006"),
+	}
+	phases := selectorPhases()
+	inPhase2 := func(s *ParsedTaskSpec) bool {
+		ph := PhaseForTask(phases, s.ID)
+		return ph != nil && ph.ID == 2
+	}
+
+	g, err := BuildTaskGraph(specs, graphState("T-002", "completed"), phases, inPhase2)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		ids = append(ids, n.ID)
+	}
+	assert.Equal(t, []string{"T-002", "T-006", "T-007", "T-099"}, ids)
+	assert.True(t, g.Node("T-002").External)
+	assert.True(t, g.Node("T-099").External, "dependency without a spec is external")
+	assert.False(t, g.Node("T-006").External)
+	assert.Empty(t, g.Node("T-002").Dependencies, "external dependencies are not followed")
+
+	// T-006 waits on T-099, which is not completed, so nothing is ready.
+	assert.Empty(t, g.Frontier)
+	assert.Equal(t, []string{"T-099", "T-006", "T-007"}, g.CriticalPath)
+}
+
+func TestBuildTaskGraph_Cycle(t *testing.T) {
+	t.Parallel()
+
+	specs := []*ParsedTaskSpec{
+		graphSpec("T-001", "", "T-002"),
+		graphSpec("T-002", "", "T-001"),
+		graphSpec("T-003", ""),
+	}
+	_, err := BuildTaskGraph(specs, nil, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle among tasks T-001, T-002")
+}
+
+func TestBuildTaskGraph_AllDone(t *testing.T) {
+	t.Parallel()
+
+	specs := []*ParsedTaskSpec{
+		graphSpec("T-001", "Small"),
+		graphSpec("T-002", "Small", "T-001"),
+	}
+	g, err := BuildTaskGraph(specs, graphState("T-001", "completed", "T-002", "completed"), nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, g.CriticalPath)
+	assert.NotNil(t, g.CriticalPath)
+	assert.Zero(t, g.CriticalPathHours)
+	assert.Empty(t, g.Frontier)
+}
+
+func TestBuildTaskGraph_SkippedDependency(t *testing.T) {
+	t.Parallel()
+
+	// As in the selector, only a completed dependency is satisfied: the
+	// skipped T-001 keeps T-002 off the frontier and stays on the path.
+	specs := []*ParsedTaskSpec{
+		graphSpec("T-001", "Small"),
+		graphSpec("T-002", "Small", "T-001"),
+		graphSpec("T-003", "Small", "T-002"),
+	}
+	g, err := BuildTaskGraph(specs, graphState("T-001", "skipped"), nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, g.Frontier)
+	assert.Equal(t, []string{"T-001", "T-002", "T-003"}, g.CriticalPath)
+}
+
+func TestTaskGraph_DOT(t *testing.T) {
+	t.Parallel()
+
+	specs := []*ParsedTaskSpec{
+		graphSpec("T-001", "Small"),
+		graphSpec("T-002", "Small", "T-001"),
+	}
+	specs[1].Title = `Say "hi"`
+	g, err := BuildTaskGraph(specs, graphState("T-001", "completed"), nil, nil)
+	require.NoError(t, err)
+
+	dot := g.DOT()
+	assert.True(t, strings.HasPrefix(dot, "digraph tasks {\n"))
+	assert.Contains(t, dot, `"T-001" [label="T-001\nTask T-001", fillcolor="#c6efce"`)
+	assert.Contains(t, dot, `label="T-002\nSay \"hi\""`)
+	assert.Contains(t, dot, "peripheries=2", "ready node has a double border")
+	assert.Contains(t, dot, `"T-001" -> "T-002";`)
+	assert.True(t, strings.HasSuffix(dot, "}\n"))
+}
+
+func TestTaskGraph_Mermaid(t *testing.T) {
+	t.Parallel()
+
+	specs := []*ParsedTaskSpec{
+		graphSpec("T-001", "Small"),
+		graphSpec("T-002", "Small", "T-001"),
+	}
+	g, err := BuildTaskGraph(specs, graphState("T-001", "in_progress"), nil, nil)
+	require.NoError(t, err)
+
+	mm := g.Mermaid()
+	assert.True(t, strings.HasPrefix(mm, "graph LR\n"))
+	assert.Contains(t, mm, `T_001["T-001: Task T-001"]`)
+	assert.Contains(t, mm, "T_001 --> T_002")
+	assert.Contains(t, mm, "classDef in_progress fill:#ffeb9c")
+	assert.Contains(t, mm, "class T_001 in_progress")
+	assert.Contains(t, mm, "class T_002 critical")
+	assert.Contains(t, mm, "linkStyle 0 stroke:#d32f2f")
+}
@@ -0,0 +1,142 @@
+package task
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxHistoryLineSize is the maximum length of a single history line. Entries
+// are small; the limit only guards against corrupt files.
+const maxHistoryLineSize = 1 << 20 // 1 MiB
+
+// HistoryEntry is a single recorded task state change. Entries are appended
+// to the history file as JSON lines by StateManager.Update and
+// StateManager.UpdateStatus and are never rewritten.
+type HistoryEntry struct {
+	// Timestamp is when the change was recorded (UTC).
+	Timestamp time.Time `json:"timestamp"`
+	// TaskID is the task whose state changed.
+	TaskID string `json:"task_id"`
+	// PreviousStatus is the status before the change; empty when the task
+	// had no state entry.
+	PreviousStatus TaskStatus `json:"previous_status,omitempty"`
+	// Status is the status after the change.
+	Status TaskStatus `json:"status"`
+	// Agent is the agent recorded with the new state.
+	Agent string `json:"agent,omitempty"`
+	// RunID identifies the workflow run that made the change, when known.
+	RunID string `json:"run_id,omitempty"`
+	// Notes are the notes recorded with the new state.
+	Notes string `json:"notes,omitempty"`
+}
+
+// HistoryFilePath returns the default history file path for a state file:
+// the state file path with its extension replaced by ".history.jsonl", e.g.
+// "task-state.conf" becomes "task-state.history.jsonl".
+func HistoryFilePath(stateFile string) string {
+	return strings.TrimSuffix(stateFile, filepath.Ext(stateFile)) + ".history.jsonl"
+}
+
+// LoadHistory reads all entries from the history file at path in the order
+// they were recorded. A missing file yields an empty slice. A malformed final
+// line (e.g. a write torn by a crash) is ignored; malformed lines elsewhere
+// are reported as errors.
+func LoadHistory(path string) ([]HistoryEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []HistoryEntry{}, nil
+		}
+		return nil, fmt.Errorf("loading history file %q: %w", path, err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	entries := []HistoryEntry{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxHistoryLineSize)
+	lineNum := 0
+	var pending error
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if pending != nil {
+			return nil, pending
+		}
+		var e HistoryEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			pending = fmt.Errorf("parsing history file %q line %d: %w", path, lineNum, err)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanning history file %q: %w", path, err)
+	}
+	return entries, nil
+}
+
+// appendHistory appends entry to the history file at path as a single JSON
+// line, creating the file and its directory when needed.
+func appendHistory(path string, entry HistoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding history entry for task %q: %w", entry.TaskID, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating history directory for %q: %w", path, err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("opening history file %q: %w", path, err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close() //nolint:errcheck
+		return fmt.Errorf("appending to history file %q: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing history file %q: %w", path, err)
+	}
+	return nil
+}
+
+// StatesAt replays entries and returns the state of each task as of t: the
+// last entry recorded at or before t wins. Tasks with no entry by then are
+// absent from the result (implicitly not_started).
+func StatesAt(entries []HistoryEntry, t time.Time) []TaskState {
+	index := make(map[string]int)
+	var states []TaskState
+	for _, e := range entries {
+		if e.Timestamp.After(t) {
+			continue
+		}
+		s := TaskState{
+			TaskID:    e.TaskID,
+			Status:    e.Status,
+			Agent:     e.Agent,
+			Timestamp: e.Timestamp,
+			Notes:     e.Notes,
+		}
+		if i, ok := index[e.TaskID]; ok {
+			if !e.Timestamp.Before(states[i].Timestamp) {
+				states[i] = s
+			}
+			continue
+		}
+		index[e.TaskID] = len(states)
+		states = append(states, s)
+	}
+	if states == nil {
+		states = []TaskState{}
+	}
+	return states
+}
@@ -0,0 +1,220 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryFilePath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		stateFile string
+		want      string
+	}{
+		{".raven/task-state.conf", ".raven/task-state.history.jsonl"},
+		{"state", "state.history.jsonl"},
+		{"/tmp/a.b/state.txt", "/tmp/a.b/state.history.jsonl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.stateFile, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, HistoryFilePath(tt.stateFile))
+		})
+	}
+}
+
+func TestStateManager_RecordsHistory(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sm := NewStateManager(filepath.Join(dir, "task-state.conf"))
+	sm.SetRunID("run-1")
+
+	require.NoError(t, sm.UpdateStatus("T-001", StatusInProgress, "claude"))
+	require.NoError(t, sm.UpdateStatus("T-001", StatusBlocked, "claude"))
+	sm.SetRunID("")
+	require.NoError(t, sm.Update(TaskState{TaskID: "T-001", Status: StatusNotStarted, Notes: "reset by hand"}))
+	require.NoError(t, sm.UpdateStatus("T-002", StatusCompleted, "codex"))
+
+	assert.FileExists(t, filepath.Join(dir, "task-state.history.jsonl"))
+
+	all, err := sm.History("")
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+
+	history, err := sm.History("T-001")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+
+	assert.Equal(t, TaskStatus(""), history[0].PreviousStatus)
+	assert.Equal(t, StatusInProgress, history[0].Status)
+	assert.Equal(t, "claude", history[0].Agent)
+	assert.Equal(t, "run-1", history[0].RunID)
+	assert.False(t, history[0].Timestamp.IsZero())
+
+	assert.Equal(t, StatusInProgress, history[1].PreviousStatus)
+	assert.Equal(t, StatusBlocked, history[1].Status)
+
+	assert.Equal(t, StatusBlocked, history[2].PreviousStatus)
+	assert.Equal(t, StatusNotStarted, history[2].Status)
+	assert.Equal(t, "reset by hand", history[2].Notes)
+	assert.Empty(t, history[2].RunID)
+}
+
+func TestStateManager_HistoryDisabled(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sm := NewStateManager(filepath.Join(dir, "task-state.conf"))
+	sm.SetHistoryFile("")
+
+	require.NoError(t, sm.UpdateStatus("T-001", StatusCompleted, "claude"))
+	assert.NoFileExists(t, filepath.Join(dir, "task-state.history.jsonl"))
+
+	history, err := sm.History("T-001")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestStateManager_InitializeDoesNotRecordHistory(t *testing.T) {
+	t.Parallel()
+
+	sm := NewStateManager(filepath.Join(t.TempDir(), "task-state.conf"))
+	require.NoError(t, sm.Initialize([]string{"T-001", "T-002"}))
+
+	history, err := sm.History("")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestLoadHistory(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr string
+	}{
+		{name: "missing file", content: "", want: 0},
+		{
+			name: "valid lines",
+			content: `{"timestamp":"2026-01-02T10:00:00Z","task_id":"T-001","status":"in_progress"}` + "\n\n" +
+				`{"timestamp":"2026-01-02T11:00:00Z","task_id":"T-001","previous_status":"in_progress","status":"completed"}` + "\n",
+			want: 2,
+		},
+		{
+			name: "torn final line ignored",
+			content: `{"timestamp":"2026-01-02T10:00:00Z","task_id":"T-001","status":"in_progress"}` + "\n" +
+				`{"timestamp":"2026-01-02T11:00`,
+			want: 1,
+		},
+		{
+			name: "malformed middle line",
+			content: "not json\n" +
+				`{"timestamp":"2026-01-02T10:00:00Z","task_id":"T-001","status":"in_progress"}` + "\n",
+			wantErr: "line 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "history.jsonl")
+			if tt.content != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+			}
+			entries, err := LoadHistory(path)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, entries, tt.want)
+		})
+	}
+}
+
+func TestStatesAt(t *testing.T) {
+	t.Parallel()
+
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	entries := []HistoryEntry{
+		{Timestamp: t0, TaskID: "T-001", Status: StatusInProgress, Agent: "claude"},
+		{Timestamp: t0.Add(time.Hour), TaskID: "T-001", PreviousStatus: StatusInProgress, Status: StatusCompleted, Agent: "claude"},
+		{Timestamp: t0.Add(30 * time.Minute), TaskID: "T-002", Status: StatusBlocked},
+	}
+
+	assert.Empty(t, StatesAt(entries, t0.Add(-time.Second)))
+
+	at := StatesAt(entries, t0.Add(45*time.Minute))
+	require.Len(t, at, 2)
+	assert.Equal(t, "T-001", at[0].TaskID)
+	assert.Equal(t, StatusInProgress, at[0].Status)
+	assert.Equal(t, StatusBlocked, at[1].Status)
+
+	at = StatesAt(entries, t0.Add(time.Hour))
+	assert.Equal(t, StatusCompleted, at[0].Status, "entries at exactly t are included")
+}
+
+func TestStateManager_At(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sm := NewStateManager(filepath.Join(dir, "task-state.conf"))
+
+	require.NoError(t, sm.UpdateStatus("T-001", StatusInProgress, "claude"))
+	mid := time.Now().UTC()
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, sm.UpdateStatus("T-001", StatusCompleted, "claude"))
+
+	past, err := sm.At(mid)
+	require.NoError(t, err)
+
+	ts, err := past.Get("T-001")
+	require.NoError(t, err)
+	require.NotNil(t, ts)
+	assert.Equal(t, StatusInProgress, ts.Status)
+
+	current, err := sm.Get("T-001")
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, current.Status)
+
+	err = past.UpdateStatus("T-001", StatusBlocked, "claude")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read-only")
+}
+
+func TestStateManager_UpdateNotes(t *testing.T) {
+	t.Parallel()
+
+	sm := NewStateManager(filepath.Join(t.TempDir(), "task-state.conf"))
+	require.NoError(t, sm.UpdateStatus("T-001", StatusBlocked, "claude"))
+	require.NoError(t, sm.UpdateNotes("T-001", "waiting on\nAPI   keys"))
+
+	ts, err := sm.Get("T-001")
+	require.NoError(t, err)
+	require.NotNil(t, ts)
+	assert.Equal(t, StatusBlocked, ts.Status)
+	assert.Equal(t, "claude", ts.Agent)
+	assert.Equal(t, "waiting on API keys", ts.Notes)
+
+	require.NoError(t, sm.UpdateNotes("T-002", "new task"))
+	ts, err = sm.Get("T-002")
+	require.NoError(t, err)
+	assert.Equal(t, StatusNotStarted, ts.Status)
+
+	history, err := sm.History("T-001")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, StatusBlocked, history[1].PreviousStatus)
+	assert.Equal(t, StatusBlocked, history[1].Status)
+	assert.Equal(t, "waiting on API keys", history[1].Notes)
+}
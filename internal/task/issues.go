@@ -0,0 +1,510 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GHRunner runs a gh CLI command and returns its stdout. review.CLIRunner
+// implements it; tests substitute a fake gh script on PATH or a stub.
+type GHRunner interface {
+	GH(ctx context.Context, args ...string) (string, error)
+}
+
+// Label prefixes used for the phase and priority labels of exported issues.
+const (
+	IssuePhaseLabelPrefix    = "phase:"
+	IssuePriorityLabelPrefix = "priority:"
+)
+
+// issueMarkerRe matches the hidden marker Raven appends to exported issue
+// bodies, e.g. "<!-- raven-task: T-012 -->".
+var issueMarkerRe = regexp.MustCompile(`<!-- raven-task: (\S+) -->`)
+
+// issueNumberRe extracts the issue number from an issue URL printed by
+// "gh issue create".
+var issueNumberRe = regexp.MustCompile(`/issues/(\d+)`)
+
+// issueDependsRe matches a "Depends on:" line in an issue body.
+var issueDependsRe = regexp.MustCompile(`(?im)^\**depends on:?\**:?\s*(.+)$`)
+
+// issueRefRe matches issue references like "#12".
+var issueRefRe = regexp.MustCompile(`#(\d+)\b`)
+
+// issueCheckboxRe matches a markdown task list item.
+var issueCheckboxRe = regexp.MustCompile(`^\s*[-*] \[[ xX]\] (.+)$`)
+
+// GitHubIssue is an issue as listed by "gh issue list --json".
+type GitHubIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	URL    string `json:"url"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// LabelNames returns the names of the issue's labels.
+func (i GitHubIssue) LabelNames() []string {
+	names := make([]string, 0, len(i.Labels))
+	for _, l := range i.Labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+// IssueExport describes the issue created or updated for one task.
+type IssueExport struct {
+	// TaskID is the exported task.
+	TaskID string `json:"task_id"`
+	// Number is the issue number; zero for an issue that would be created
+	// in a dry run.
+	Number int `json:"number"`
+	// Action is "create" or "update".
+	Action string `json:"action"`
+	// Title, Body and Labels are the issue fields sent to GitHub.
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels"`
+}
+
+// IssueSyncer exports task specs to GitHub issues and imports issues as task
+// specs through the gh CLI.
+type IssueSyncer struct {
+	gh         GHRunner
+	membership *PhaseMembership
+}
+
+// NewIssueSyncer creates an IssueSyncer that runs gh through gh. phases and
+// specs determine the phase label of each exported task; phases may be nil.
+func NewIssueSyncer(gh GHRunner, phases []Phase, specs []*ParsedTaskSpec) *IssueSyncer {
+	return &IssueSyncer{gh: gh, membership: NewPhaseMembership(phases, specs)}
+}
+
+// Export creates an issue for every spec in specs without a linked issue and
+// updates the issue of every spec that has one. Specs are exported in
+// dependency order so that an issue can reference the issues of the tasks it
+// depends on; all is the complete spec list used to resolve those references.
+// The numbers of newly created issues are written to the spec files'
+// github_issue front matter key.
+//
+// With dryRun no gh command is run and no file is changed; the returned
+// exports describe what would be sent.
+func (s *IssueSyncer) Export(ctx context.Context, specs, all []*ParsedTaskSpec, dryRun bool) ([]IssueExport, error) {
+	issueOf := make(map[string]int, len(all))
+	for _, spec := range all {
+		if spec.GitHubIssue > 0 {
+			issueOf[spec.ID] = spec.GitHubIssue
+		}
+	}
+
+	ordered := exportOrder(specs)
+	exports := make([]IssueExport, 0, len(ordered))
+	for _, spec := range ordered {
+		exports = append(exports, IssueExport{
+			TaskID: spec.ID,
+			Number: spec.GitHubIssue,
+			Title:  spec.ID + ": " + spec.Title,
+			Labels: s.issueLabels(spec),
+		})
+	}
+	if dryRun {
+		for i, spec := range ordered {
+			exports[i].Action = exportAction(spec)
+			exports[i].Body = issueBody(spec, issueOf)
+		}
+		return exports, nil
+	}
+
+	if err := s.ensureLabels(ctx, exports); err != nil {
+		return nil, err
+	}
+
+	for i, spec := range ordered {
+		exp := &exports[i]
+		exp.Action = exportAction(spec)
+		exp.Body = issueBody(spec, issueOf)
+
+		bodyFile, err := writeIssueBody(exp.Body)
+		if err != nil {
+			return exports[:i], fmt.Errorf("exporting %s: %w", spec.ID, err)
+		}
+		number, err := s.sendIssue(ctx, exp, bodyFile)
+		os.Remove(bodyFile) //nolint:errcheck
+		if err != nil {
+			return exports[:i], fmt.Errorf("exporting %s: %w", spec.ID, err)
+		}
+		exp.Number = number
+
+		if spec.GitHubIssue != number {
+			if err := linkSpecIssue(spec, number); err != nil {
+				return exports[:i+1], fmt.Errorf("exporting %s: %w", spec.ID, err)
+			}
+		}
+		issueOf[spec.ID] = number
+	}
+	return exports, nil
+}
+
+// exportAction returns the export action for spec.
+func exportAction(spec *ParsedTaskSpec) string {
+	if spec.GitHubIssue > 0 {
+		return "update"
+	}
+	return "create"
+}
+
+// sendIssue creates or edits the issue for exp and returns its number. An
+// edited issue loses the phase and priority labels exp no longer has, so a
+// task that moved phase or changed priority does not keep its old labels.
+func (s *IssueSyncer) sendIssue(ctx context.Context, exp *IssueExport, bodyFile string) (int, error) {
+	if exp.Action == "update" {
+		stale, err := s.staleLabels(ctx, exp)
+		if err != nil {
+			return 0, err
+		}
+		args := []string{"issue", "edit", strconv.Itoa(exp.Number), "--title", exp.Title, "--body-file", bodyFile}
+		if len(exp.Labels) > 0 {
+			args = append(args, "--add-label", strings.Join(exp.Labels, ","))
+		}
+		if len(stale) > 0 {
+			args = append(args, "--remove-label", strings.Join(stale, ","))
+		}
+		if _, err := s.gh.GH(ctx, args...); err != nil {
+			return 0, err
+		}
+		return exp.Number, nil
+	}
+
+	args := []string{"issue", "create", "--title", exp.Title, "--body-file", bodyFile}
+	for _, l := range exp.Labels {
+		args = append(args, "--label", l)
+	}
+	out, err := s.gh.GH(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	m := issueNumberRe.FindStringSubmatch(out)
+	if m == nil {
+		return 0, fmt.Errorf("gh issue create: no issue URL in output %q", strings.TrimSpace(out))
+	}
+	return strconv.Atoi(m[1])
+}
+
+// staleLabels returns the phase and priority labels of exp's issue that
+// exp.Labels no longer contains. Other labels are left to the user.
+func (s *IssueSyncer) staleLabels(ctx context.Context, exp *IssueExport) ([]string, error) {
+	out, err := s.gh.GH(ctx, "issue", "view", strconv.Itoa(exp.Number), "--json", "labels")
+	if err != nil {
+		return nil, fmt.Errorf("reading labels of issue #%d: %w", exp.Number, err)
+	}
+	var issue GitHubIssue
+	if err := json.Unmarshal([]byte(out), &issue); err != nil {
+		return nil, fmt.Errorf("reading labels of issue #%d: parsing gh output: %w", exp.Number, err)
+	}
+
+	var stale []string
+	for _, l := range issue.LabelNames() {
+		lower := strings.ToLower(l)
+		if !strings.HasPrefix(lower, IssuePhaseLabelPrefix) && !strings.HasPrefix(lower, IssuePriorityLabelPrefix) {
+			continue
+		}
+		if !containsFold(exp.Labels, l) {
+			stale = append(stale, l)
+		}
+	}
+	return stale, nil
+}
+
+// ensureLabels creates the labels used by exports that do not exist in the
+// repository yet; "gh issue create --label" fails on unknown labels.
+func (s *IssueSyncer) ensureLabels(ctx context.Context, exports []IssueExport) error {
+	out, err := s.gh.GH(ctx, "label", "list", "--limit", "1000", "--json", "name")
+	if err != nil {
+		return fmt.Errorf("listing labels: %w", err)
+	}
+	var existing []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(out), &existing); err != nil {
+		return fmt.Errorf("listing labels: parsing gh output: %w", err)
+	}
+	have := make(map[string]bool, len(existing))
+	for _, l := range existing {
+		have[strings.ToLower(l.Name)] = true
+	}
+
+	for _, exp := range exports {
+		for _, l := range exp.Labels {
+			if have[strings.ToLower(l)] {
+				continue
+			}
+			args := []string{"label", "create", l}
+			if desc := s.labelDescription(l); desc != "" {
+				args = append(args, "--description", desc)
+			}
+			if _, err := s.gh.GH(ctx, args...); err != nil {
+				return fmt.Errorf("creating label %q: %w", l, err)
+			}
+			have[strings.ToLower(l)] = true
+		}
+	}
+	return nil
+}
+
+// labelDescription describes a phase or priority label for "gh label create".
+func (s *IssueSyncer) labelDescription(label string) string {
+	if rest, ok := strings.CutPrefix(label, IssuePhaseLabelPrefix); ok {
+		if id, err := strconv.Atoi(rest); err == nil {
+			if p := s.membership.Phase(id); p != nil {
+				return "Raven phase " + rest + ": " + p.Name
+			}
+		}
+		return "Raven phase " + rest
+	}
+	if strings.HasPrefix(label, IssuePriorityLabelPrefix) {
+		return "Raven task priority"
+	}
+	return ""
+}
+
+// issueLabels returns the labels of the issue for spec: its phase, its
+// priority, and its own labels.
+func (s *IssueSyncer) issueLabels(spec *ParsedTaskSpec) []string {
+	var labels []string
+	if p := s.membership.PhaseOf(spec.ID); p != nil {
+		labels = append(labels, IssuePhaseLabelPrefix+strconv.Itoa(p.ID))
+	}
+	if pr := labelSlug(spec.Priority); pr != "" {
+		labels = append(labels, IssuePriorityLabelPrefix+pr)
+	}
+	for _, l := range spec.Labels {
+		if !containsFold(labels, l) {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
+// labelSlug lowercases s and joins its words with hyphens: "Must Have"
+// becomes "must-have".
+func labelSlug(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), "-")
+}
+
+// containsFold reports whether ss contains s, ignoring case.
+func containsFold(ss []string, s string) bool {
+	for _, v := range ss {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// issueBody renders the issue body for spec: the spec markdown without front
+// matter, the dependencies as issue references where known, and a hidden
+// marker linking the issue back to the task.
+func issueBody(spec *ParsedTaskSpec, issueOf map[string]int) string {
+	body := spec.Content
+	if _, _, rest, ok, err := splitFrontMatter(strings.TrimPrefix(body, utf8BOM)); err == nil && ok {
+		body = rest
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.TrimSpace(body))
+	sb.WriteString("\n\n---\n")
+	if len(spec.Dependencies) > 0 {
+		refs := make([]string, 0, len(spec.Dependencies))
+		for _, dep := range spec.Dependencies {
+			if n, ok := issueOf[dep]; ok {
+				refs = append(refs, fmt.Sprintf("#%d (%s)", n, dep))
+			} else {
+				refs = append(refs, dep)
+			}
+		}
+		fmt.Fprintf(&sb, "**Depends on:** %s\n\n", strings.Join(refs, ", "))
+	}
+	fmt.Fprintf(&sb, "<!-- raven-task: %s -->\n", spec.ID)
+	return sb.String()
+}
+
+// exportOrder returns specs sorted so that every task comes after the tasks
+// it depends on, with ties broken by task ID.
+func exportOrder(specs []*ParsedTaskSpec) []*ParsedTaskSpec {
+	sorted := make([]*ParsedTaskSpec, len(specs))
+	copy(sorted, specs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return CompareTaskIDs(sorted[i].ID, sorted[j].ID) < 0
+	})
+
+	byID := make(map[string]*ParsedTaskSpec, len(sorted))
+	for _, spec := range sorted {
+		byID[spec.ID] = spec
+	}
+	visited := make(map[string]bool, len(sorted))
+	ordered := make([]*ParsedTaskSpec, 0, len(sorted))
+	var visit func(spec *ParsedTaskSpec)
+	visit = func(spec *ParsedTaskSpec) {
+		if visited[spec.ID] {
+			return
+		}
+		// Marking before recursing keeps dependency cycles from looping.
+		visited[spec.ID] = true
+		for _, dep := range spec.Dependencies {
+			if d, ok := byID[dep]; ok {
+				visit(d)
+			}
+		}
+		ordered = append(ordered, spec)
+	}
+	for _, spec := range sorted {
+		visit(spec)
+	}
+	return ordered
+}
+
+// writeIssueBody writes body to a temp file for "gh --body-file", which
+// avoids argument length limits and shell escaping.
+func writeIssueBody(body string) (string, error) {
+	f, err := os.CreateTemp("", "raven-issue-*.md")
+	if err != nil {
+		return "", fmt.Errorf("creating issue body file: %w", err)
+	}
+	if _, err := f.WriteString(body); err != nil {
+		f.Close()           //nolint:errcheck
+		os.Remove(f.Name()) //nolint:errcheck
+		return "", fmt.Errorf("writing issue body file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name()) //nolint:errcheck
+		return "", fmt.Errorf("closing issue body file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// linkSpecIssue records number as the spec's GitHub issue in its spec file.
+func linkSpecIssue(spec *ParsedTaskSpec, number int) error {
+	if spec.SpecFile == "" {
+		spec.GitHubIssue = number
+		return nil
+	}
+	data, err := os.ReadFile(spec.SpecFile)
+	if err != nil {
+		return fmt.Errorf("linking issue #%d: %w", number, err)
+	}
+	updated, err := SetGitHubIssue(string(data), number)
+	if err != nil {
+		return fmt.Errorf("linking issue #%d in %s: %w", number, spec.SpecFile, err)
+	}
+	if err := writeFileAtomic(spec.SpecFile, []byte(updated)); err != nil {
+		return fmt.Errorf("linking issue #%d in %s: %w", number, spec.SpecFile, err)
+	}
+	spec.GitHubIssue = number
+	return nil
+}
+
+// ListIssues returns the issues with label in state ("open", "closed" or
+// "all"), at most limit of them, oldest first.
+func (s *IssueSyncer) ListIssues(ctx context.Context, label, state string, limit int) ([]GitHubIssue, error) {
+	args := []string{"issue", "list", "--state", state, "--limit", strconv.Itoa(limit),
+		"--json", "number,title,body,url,labels"}
+	if label != "" {
+		args = append(args, "--label", label)
+	}
+	out, err := s.gh.GH(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing issues: %w", err)
+	}
+	var issues []GitHubIssue
+	if err := json.Unmarshal([]byte(out), &issues); err != nil {
+		return nil, fmt.Errorf("listing issues: parsing gh output: %w", err)
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Number < issues[j].Number })
+	return issues, nil
+}
+
+// IssueDrafts converts issues into spec drafts with consecutive task IDs after
+// the highest existing ID with prefix. Issues already linked to a spec (by its
+// github_issue key or by the marker of an exported task) are skipped.
+//
+// The draft takes the issue title, its body as the goal, its checklist items
+// as acceptance criteria, its priority label as the priority, and its other
+// labels except importLabel and phase labels. Issue references on a
+// "Depends on:" line become dependencies when the referenced issue belongs to
+// an existing or imported task.
+func IssueDrafts(issues []GitHubIssue, specs []*ParsedTaskSpec, prefix, importLabel string) []SpecDraft {
+	known := make(map[string]bool, len(specs))
+	taskOfIssue := make(map[int]string, len(specs))
+	for _, spec := range specs {
+		known[spec.ID] = true
+		if spec.GitHubIssue > 0 {
+			taskOfIssue[spec.GitHubIssue] = spec.ID
+		}
+	}
+
+	existing := append([]*ParsedTaskSpec(nil), specs...)
+	var pending []GitHubIssue
+	var drafts []SpecDraft
+	for _, issue := range issues {
+		if _, linked := taskOfIssue[issue.Number]; linked {
+			continue
+		}
+		if m := issueMarkerRe.FindStringSubmatch(issue.Body); m != nil && known[m[1]] {
+			continue
+		}
+		id := NextTaskID(existing, prefix)
+		existing = append(existing, &ParsedTaskSpec{ID: id})
+		taskOfIssue[issue.Number] = id
+		pending = append(pending, issue)
+		drafts = append(drafts, SpecDraft{ID: id, GitHubIssue: issue.Number})
+	}
+
+	for i, issue := range pending {
+		d := &drafts[i]
+		d.Title = strings.TrimSpace(issue.Title)
+		d.Priority = "should-have"
+		d.Effort = EffortMedium
+		for _, l := range issue.LabelNames() {
+			switch {
+			case strings.EqualFold(l, importLabel), strings.HasPrefix(l, IssuePhaseLabelPrefix):
+			case strings.HasPrefix(l, IssuePriorityLabelPrefix):
+				d.Priority = strings.TrimPrefix(l, IssuePriorityLabelPrefix)
+			default:
+				d.Labels = append(d.Labels, l)
+			}
+		}
+
+		body := strings.ReplaceAll(issue.Body, "\r\n", "\n")
+		body = issueMarkerRe.ReplaceAllString(body, "")
+		var goal []string
+		for _, line := range strings.Split(body, "\n") {
+			if m := issueCheckboxRe.FindStringSubmatch(line); m != nil {
+				d.AcceptanceCriteria = append(d.AcceptanceCriteria, strings.TrimSpace(m[1]))
+				continue
+			}
+			if m := issueDependsRe.FindStringSubmatch(line); m != nil {
+				for _, ref := range issueRefRe.FindAllStringSubmatch(m[1], -1) {
+					n, _ := strconv.Atoi(ref[1])
+					if dep, ok := taskOfIssue[n]; ok && dep != d.ID && !containsFold(d.Dependencies, dep) {
+						d.Dependencies = append(d.Dependencies, dep)
+					}
+				}
+				continue
+			}
+			goal = append(goal, line)
+		}
+		d.Goal = strings.TrimSpace(strings.Join(goal, "\n"))
+		if d.Goal == "" {
+			d.Goal = "See issue #" + strconv.Itoa(issue.Number) + "."
+		}
+	}
+	return drafts
+}
@@ -0,0 +1,277 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGH is a GHRunner that answers gh commands from memory and records the
+// commands it ran along with the contents of any --body-file.
+type fakeGH struct {
+	labels    string // JSON printed by "gh label list"
+	issues    string // JSON printed by "gh issue list"
+	issue     string // JSON printed by "gh issue view"
+	next      int    // number of the next created issue
+	calls     [][]string
+	bodies    map[string]string // issue title -> body
+	createErr error
+}
+
+func (f *fakeGH) GH(_ context.Context, args ...string) (string, error) {
+	f.calls = append(f.calls, args)
+	if f.bodies == nil {
+		f.bodies = make(map[string]string)
+	}
+	var title string
+	for i := 0; i+1 < len(args); i++ {
+		switch args[i] {
+		case "--title":
+			title = args[i+1]
+		case "--body-file":
+			data, err := os.ReadFile(args[i+1])
+			if err != nil {
+				return "", err
+			}
+			f.bodies[title] = string(data)
+		}
+	}
+
+	switch strings.Join(args[:2], " ") {
+	case "label list":
+		if f.labels == "" {
+			return "[]", nil
+		}
+		return f.labels, nil
+	case "issue list":
+		return f.issues, nil
+	case "issue view":
+		if f.issue == "" {
+			return "{}", nil
+		}
+		return f.issue, nil
+	case "issue create":
+		if f.createErr != nil {
+			return "", f.createErr
+		}
+		f.next++
+		return fmt.Sprintf("https://github.com/o/r/issues/%d\n", f.next), nil
+	}
+	return "", nil
+}
+
+// callsOf returns the recorded calls starting with the given gh subcommand.
+func (f *fakeGH) callsOf(group, verb string) [][]string {
+	var out [][]string
+	for _, c := range f.calls {
+		if c[0] == group && c[1] == verb {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// writeIssueSpec writes a task spec file and returns it parsed.
+func writeIssueSpec(t *testing.T, dir, id, frontMatter, deps string) *ParsedTaskSpec {
+	t.Helper()
+	content := fmt.Sprintf("# %s: Task %s\n\n| Field | Value |\n|-------|-------|\n| Priority | Must Have |\n| Dependencies | %s |\n\n## Goal\nDo %s.\n",
+		id, id, deps, id)
+	if frontMatter != "" {
+		content = "---\n" + frontMatter + "---\n" + content
+	}
+	path := filepath.Join(dir, id+".md")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	spec, err := ParseTaskFile(path)
+	require.NoError(t, err)
+	return spec
+}
+
+func TestIssueSyncer_Export(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s1 := writeIssueSpec(t, dir, "T-001", "", "None")
+	s2 := writeIssueSpec(t, dir, "T-002", "labels: [api]\n", "T-001, T-003")
+	s3 := writeIssueSpec(t, dir, "T-003", "github_issue: 7\n", "None")
+	specs := []*ParsedTaskSpec{s2, s1, s3}
+	phases := []Phase{{ID: 1, Name: "Foundation", StartTask: "T-001", EndTask: "T-003"}}
+
+	gh := &fakeGH{labels: `[{"name":"phase:1"}]`, next: 10}
+	exports, err := NewIssueSyncer(gh, phases, specs).Export(context.Background(), specs, specs, false)
+	require.NoError(t, err)
+
+	require.Len(t, exports, 3)
+	// T-002 depends on T-001 and T-003, so it is exported last.
+	assert.Equal(t, "T-001", exports[0].TaskID)
+	assert.Equal(t, "create", exports[0].Action)
+	assert.Equal(t, 11, exports[0].Number)
+	assert.Equal(t, "T-003", exports[1].TaskID)
+	assert.Equal(t, "update", exports[1].Action)
+	assert.Equal(t, 7, exports[1].Number)
+	assert.Equal(t, "T-002", exports[2].TaskID)
+	assert.Equal(t, 12, exports[2].Number)
+	assert.Equal(t, []string{"phase:1", "priority:must-have", "api"}, exports[2].Labels)
+
+	// Only missing labels are created.
+	var created []string
+	for _, c := range gh.callsOf("label", "create") {
+		created = append(created, c[2])
+	}
+	assert.Equal(t, []string{"priority:must-have", "api"}, created)
+
+	edits := gh.callsOf("issue", "edit")
+	require.Len(t, edits, 1)
+	assert.Equal(t, "7", edits[0][2])
+
+	body := gh.bodies["T-002: Task T-002"]
+	assert.Contains(t, body, "## Goal\nDo T-002.")
+	assert.NotContains(t, body, "labels:")
+	assert.Contains(t, body, "**Depends on:** #11 (T-001), #7 (T-003)")
+	assert.Contains(t, body, "<!-- raven-task: T-002 -->")
+
+	// Created issues are linked in the spec files; linked ones are untouched.
+	linked, err := ParseTaskFile(s2.SpecFile)
+	require.NoError(t, err)
+	assert.Equal(t, 12, linked.GitHubIssue)
+	assert.Equal(t, []string{"api"}, linked.Labels)
+	assert.Equal(t, 11, s1.GitHubIssue)
+}
+
+func TestIssueSyncer_ExportRemovesStaleLabels(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	spec := writeIssueSpec(t, dir, "T-001", "github_issue: 7\n", "None")
+	phases := []Phase{{ID: 2, Name: "API", StartTask: "T-001", EndTask: "T-001"}}
+
+	// The task moved from phase 1 to phase 2 and its priority changed.
+	gh := &fakeGH{
+		labels: `[{"name":"phase:2"},{"name":"priority:must-have"}]`,
+		issue:  `{"labels":[{"name":"phase:1"},{"name":"Priority:Low"},{"name":"priority:must-have"},{"name":"bug"}]}`,
+	}
+	_, err := NewIssueSyncer(gh, phases, []*ParsedTaskSpec{spec}).Export(context.Background(), []*ParsedTaskSpec{spec}, []*ParsedTaskSpec{spec}, false)
+	require.NoError(t, err)
+
+	edits := gh.callsOf("issue", "edit")
+	require.Len(t, edits, 1)
+	edit := strings.Join(edits[0], " ")
+	assert.Contains(t, edit, "--add-label phase:2,priority:must-have")
+	// Labels without a Raven prefix are kept.
+	assert.Contains(t, edit, "--remove-label phase:1,Priority:Low")
+}
+
+func TestIssueSyncer_ExportDryRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	spec := writeIssueSpec(t, dir, "T-001", "", "None")
+	before, err := os.ReadFile(spec.SpecFile)
+	require.NoError(t, err)
+
+	gh := &fakeGH{}
+	specs := []*ParsedTaskSpec{spec}
+	exports, err := NewIssueSyncer(gh, nil, specs).Export(context.Background(), specs, specs, true)
+	require.NoError(t, err)
+	require.Len(t, exports, 1)
+	assert.Equal(t, "create", exports[0].Action)
+	assert.Contains(t, exports[0].Body, "<!-- raven-task: T-001 -->")
+	assert.Empty(t, gh.calls)
+
+	after, err := os.ReadFile(spec.SpecFile)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestIssueSyncer_ExportError(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	spec := writeIssueSpec(t, dir, "T-001", "", "None")
+	gh := &fakeGH{createErr: fmt.Errorf("gh issue create: HTTP 403")}
+	specs := []*ParsedTaskSpec{spec}
+
+	exports, err := NewIssueSyncer(gh, nil, specs).Export(context.Background(), specs, specs, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exporting T-001")
+	assert.Empty(t, exports)
+	assert.Zero(t, spec.GitHubIssue)
+}
+
+func TestIssueSyncer_ListIssues(t *testing.T) {
+	t.Parallel()
+
+	gh := &fakeGH{issues: `[{"number":9,"title":"B"},{"number":4,"title":"A","labels":[{"name":"raven"}]}]`}
+	issues, err := NewIssueSyncer(gh, nil, nil).ListIssues(context.Background(), "raven", "open", 50)
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	assert.Equal(t, 4, issues[0].Number)
+	assert.Equal(t, []string{"raven"}, issues[0].LabelNames())
+	assert.Equal(t, []string{"issue", "list", "--state", "open", "--limit", "50",
+		"--json", "number,title,body,url,labels", "--label", "raven"}, gh.calls[0])
+
+	_, err = NewIssueSyncer(&fakeGH{issues: "not json"}, nil, nil).ListIssues(context.Background(), "", "all", 1)
+	require.Error(t, err)
+}
+
+func TestIssueDrafts(t *testing.T) {
+	t.Parallel()
+
+	existing := makeSpec("T-001", nil)
+	existing.GitHubIssue = 3
+	exported := makeSpec("T-002", nil)
+
+	label := func(names ...string) []struct {
+		Name string `json:"name"`
+	} {
+		out := make([]struct {
+			Name string `json:"name"`
+		}, len(names))
+		for i, n := range names {
+			out[i].Name = n
+		}
+		return out
+	}
+	issues := []GitHubIssue{
+		{Number: 3, Title: "Already linked"},
+		{Number: 4, Title: "Exported", Body: "x\n<!-- raven-task: T-002 -->"},
+		{
+			Number: 5,
+			Title:  " Add retries ",
+			Body:   "Retry failed calls.\r\n\r\n- [ ] Retries stop\r\n- [x] Budget configurable\r\n\r\nDepends on: #3, #6, #99\r\n",
+			Labels: label("raven", "priority:must-have", "phase:2", "api"),
+		},
+		{Number: 6, Title: "Empty"},
+	}
+
+	drafts := IssueDrafts(issues, []*ParsedTaskSpec{existing, exported}, "T", "raven")
+	require.Len(t, drafts, 2)
+
+	d := drafts[0]
+	assert.Equal(t, "T-003", d.ID)
+	assert.Equal(t, 5, d.GitHubIssue)
+	assert.Equal(t, "Add retries", d.Title)
+	assert.Equal(t, "must-have", d.Priority)
+	assert.Equal(t, EffortMedium, d.Effort)
+	assert.Equal(t, []string{"api"}, d.Labels)
+	assert.Equal(t, "Retry failed calls.", d.Goal)
+	assert.Equal(t, []string{"Retries stop", "Budget configurable"}, d.AcceptanceCriteria)
+	assert.Equal(t, []string{"T-001", "T-004"}, d.Dependencies)
+
+	assert.Equal(t, "T-004", drafts[1].ID)
+	assert.Equal(t, "should-have", drafts[1].Priority)
+	assert.Equal(t, "See issue #6.", drafts[1].Goal)
+
+	// The draft renders to a spec that links back to the issue.
+	content, err := RenderTaskSpec(d)
+	require.NoError(t, err)
+	spec, err := ParseTaskSpec(string(content))
+	require.NoError(t, err)
+	assert.Equal(t, 5, spec.GitHubIssue)
+	assert.Equal(t, []string{"T-001", "T-004"}, spec.Dependencies)
+}
@@ -0,0 +1,254 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// DefaultLeaseDuration is how long a claim on a task stays valid without a
+// heartbeat. Holders renew well before it runs out; a claim whose lease has
+// expired is treated as abandoned and may be taken over.
+const DefaultLeaseDuration = 10 * time.Minute
+
+// ErrTaskUnavailable is returned by Claim when another owner holds an
+// active lease on the task, or the task is no longer waiting to be run.
+var ErrTaskUnavailable = errors.New("task is unavailable")
+
+// ErrLeaseLost is returned by Renew when the caller no longer holds the
+// claim on the task, e.g. because its lease expired and another owner took
+// the task over, or the task's status was changed.
+var ErrLeaseLost = errors.New("task lease is no longer held")
+
+// stateLockTimeout is how long a state write waits for another process to
+// release the state file lock.
+var stateLockTimeout = 10 * time.Second
+
+// staleStateLockAge is the age after which a state file lock is assumed to
+// belong to a crashed process and is removed. State writes take
+// milliseconds, so a lock this old is never legitimately held.
+var staleStateLockAge = 30 * time.Second
+
+// leaseFieldReplacer strips the characters that would break the
+// pipe-delimited, line-oriented state file out of claim owners.
+var leaseFieldReplacer = strings.NewReplacer("|", "-", "\n", " ", "\r", " ")
+
+// DefaultClaimOwner returns the owner recorded for claims made by this
+// process: "<hostname>:<pid>".
+func DefaultClaimOwner() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// LeaseActive reports whether the task is claimed and its lease has not
+// expired at now.
+func (s TaskState) LeaseActive(now time.Time) bool {
+	return s.ClaimedBy != "" && now.Before(s.LeaseExpiry)
+}
+
+// LeaseExpired reports whether the task is claimed but its lease has
+// expired at now, i.e. its holder stopped sending heartbeats.
+func (s TaskState) LeaseExpired(now time.Time) bool {
+	return s.ClaimedBy != "" && !now.Before(s.LeaseExpiry)
+}
+
+// Claim marks taskID in_progress for agent under a lease held by owner that
+// expires after ttl (DefaultLeaseDuration when ttl is not positive). Only a
+// not_started task, or an in_progress task whose lease has expired or is
+// held by owner, can be claimed: Claim fails with ErrTaskUnavailable when a
+// different owner holds an active lease or the task was completed, skipped,
+// or blocked since it was selected. An expired lease is taken over.
+// Claiming a task the owner already holds refreshes the lease.
+//
+// The read-modify-write cycle holds the state file lock, so concurrent
+// claims from separate processes sharing the file cannot both succeed.
+func (sm *StateManager) Claim(taskID, owner, agent string, ttl time.Duration) error {
+	if taskID == "" {
+		return fmt.Errorf("claiming task: task ID must not be empty")
+	}
+	owner = strings.TrimSpace(leaseFieldReplacer.Replace(owner))
+	if owner == "" {
+		return fmt.Errorf("claiming task %q: owner must not be empty", taskID)
+	}
+	if ttl <= 0 {
+		ttl = DefaultLeaseDuration
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	unlock, err := sm.lockFile()
+	if err != nil {
+		return fmt.Errorf("claiming task %q: %w", taskID, err)
+	}
+	defer unlock()
+
+	states, err := sm.load()
+	if err != nil {
+		return fmt.Errorf("claiming task %q: %w", taskID, err)
+	}
+
+	now := time.Now().UTC()
+	entry := TaskState{
+		TaskID:      taskID,
+		Status:      StatusInProgress,
+		Agent:       agent,
+		Timestamp:   now,
+		ClaimedBy:   owner,
+		LeaseExpiry: now.Add(ttl),
+		Heartbeat:   now,
+	}
+	var previous TaskStatus
+	updated := false
+	for i, s := range states {
+		if s.TaskID != taskID {
+			continue
+		}
+		if s.LeaseActive(now) && s.ClaimedBy != owner {
+			return fmt.Errorf("claiming task %q: %w: held by %s until %s",
+				taskID, ErrTaskUnavailable, s.ClaimedBy, s.LeaseExpiry.UTC().Format(time.RFC3339))
+		}
+		if s.Status != StatusNotStarted && s.Status != StatusInProgress {
+			return fmt.Errorf("claiming task %q: %w: status is %s", taskID, ErrTaskUnavailable, s.Status)
+		}
+		if s.LeaseExpired(now) && s.ClaimedBy != owner {
+			log.Info("reclaiming task with expired lease", "task", taskID,
+				"previous_owner", s.ClaimedBy, "expired", s.LeaseExpiry.UTC().Format(time.RFC3339))
+		}
+		previous = s.Status
+		entry.Notes = s.Notes
+		states[i] = entry
+		updated = true
+		break
+	}
+	if !updated {
+		states = append(states, entry)
+	}
+
+	if err := sm.writeAtomic(states); err != nil {
+		return err
+	}
+	return sm.recordHistory(previous, entry)
+}
+
+// Renew extends owner's lease on taskID to ttl from now
+// (DefaultLeaseDuration when ttl is not positive) and records a heartbeat.
+// It fails with ErrLeaseLost when owner no longer holds the claim. Renewals
+// are not recorded in the history file.
+func (sm *StateManager) Renew(taskID, owner string, ttl time.Duration) error {
+	owner = strings.TrimSpace(leaseFieldReplacer.Replace(owner))
+	if ttl <= 0 {
+		ttl = DefaultLeaseDuration
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	unlock, err := sm.lockFile()
+	if err != nil {
+		return fmt.Errorf("renewing lease on task %q: %w", taskID, err)
+	}
+	defer unlock()
+
+	states, err := sm.load()
+	if err != nil {
+		return fmt.Errorf("renewing lease on task %q: %w", taskID, err)
+	}
+
+	now := time.Now().UTC()
+	for i := range states {
+		if states[i].TaskID != taskID {
+			continue
+		}
+		if owner == "" || states[i].ClaimedBy != owner {
+			break
+		}
+		states[i].LeaseExpiry = now.Add(ttl)
+		states[i].Heartbeat = now
+		return sm.writeAtomic(states)
+	}
+	return fmt.Errorf("renewing lease on task %q: %w", taskID, ErrLeaseLost)
+}
+
+// Release drops owner's claim on taskID, leaving its status unchanged. It
+// is a no-op when owner does not hold the claim. Releases are not recorded
+// in the history file.
+func (sm *StateManager) Release(taskID, owner string) error {
+	owner = strings.TrimSpace(leaseFieldReplacer.Replace(owner))
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	unlock, err := sm.lockFile()
+	if err != nil {
+		return fmt.Errorf("releasing task %q: %w", taskID, err)
+	}
+	defer unlock()
+
+	states, err := sm.load()
+	if err != nil {
+		return fmt.Errorf("releasing task %q: %w", taskID, err)
+	}
+
+	for i := range states {
+		if states[i].TaskID != taskID {
+			continue
+		}
+		if owner == "" || states[i].ClaimedBy != owner {
+			return nil
+		}
+		states[i].ClaimedBy = ""
+		states[i].LeaseExpiry = time.Time{}
+		states[i].Heartbeat = time.Time{}
+		return sm.writeAtomic(states)
+	}
+	return nil
+}
+
+// lockFile acquires the cross-process lock guarding the state file, a
+// sibling file with a ".lock" suffix created exclusively. It waits up to
+// stateLockTimeout for another holder, removing locks older than
+// staleStateLockAge. The returned function releases the lock. Read-only
+// snapshots need no lock. Callers must hold sm.mu.
+func (sm *StateManager) lockFile() (func(), error) {
+	if sm.snapshot != nil {
+		return func() {}, nil
+	}
+
+	dir := filepath.Dir(sm.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating state directory %q: %w", dir, err)
+	}
+
+	lockPath := sm.filePath + ".lock"
+	deadline := time.Now().Add(stateLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			fmt.Fprintln(f, DefaultClaimOwner())       //nolint:errcheck
+			f.Close()                                  //nolint:errcheck
+			return func() { os.Remove(lockPath) }, nil //nolint:errcheck
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("acquiring state lock %q: %w", lockPath, err)
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleStateLockAge {
+			log.Debug("removing stale state lock", "path", lockPath, "age", time.Since(info.ModTime()))
+			os.Remove(lockPath) //nolint:errcheck
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("acquiring state lock %q: timed out after %s", lockPath, stateLockTimeout)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
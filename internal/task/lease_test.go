@@ -0,0 +1,375 @@
+package task
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---- state file claim columns -----------------------------------------------
+
+func TestParseLine_ClaimColumns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		line      string
+		wantNotes string
+		wantOwner string
+	}{
+		{
+			name:      "claimed",
+			line:      "T-001|in_progress|claude|2026-02-17T10:30:00Z|started|ci:42|2026-02-17T10:40:00Z|2026-02-17T10:33:00Z",
+			wantNotes: "started",
+			wantOwner: "ci:42",
+		},
+		{
+			name:      "claimed with empty notes",
+			line:      "T-001|in_progress|claude|2026-02-17T10:30:00Z||ci:42|2026-02-17T10:40:00Z|2026-02-17T10:33:00Z",
+			wantNotes: "",
+			wantOwner: "ci:42",
+		},
+		{
+			name:      "claimed with pipes in notes",
+			line:      "T-001|in_progress|claude|2026-02-17T10:30:00Z|a|b|ci:42|2026-02-17T10:40:00Z|2026-02-17T10:33:00Z",
+			wantNotes: "a|b",
+			wantOwner: "ci:42",
+		},
+		{
+			name:      "pipes in notes without claim",
+			line:      "T-001|blocked|claude|2026-02-17T10:30:00Z|waiting|on|keys|today",
+			wantNotes: "waiting|on|keys|today",
+		},
+		{
+			name:      "legacy five columns",
+			line:      "T-001|completed|claude|2026-02-17T10:30:00Z|done",
+			wantNotes: "done",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			state, err := parseLine(tt.line)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNotes, state.Notes)
+			assert.Equal(t, tt.wantOwner, state.ClaimedBy)
+			if tt.wantOwner != "" {
+				assert.Equal(t, mustParseRFC3339("2026-02-17T10:40:00Z"), state.LeaseExpiry)
+				assert.Equal(t, mustParseRFC3339("2026-02-17T10:33:00Z"), state.Heartbeat)
+			} else {
+				assert.True(t, state.LeaseExpiry.IsZero())
+			}
+		})
+	}
+}
+
+func TestFormatLine_ClaimRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	state := TaskState{
+		TaskID:      "T-001",
+		Status:      StatusInProgress,
+		Agent:       "claude",
+		Timestamp:   mustParseRFC3339("2026-02-17T10:30:00Z"),
+		Notes:       "a|b",
+		ClaimedBy:   "ci:42",
+		LeaseExpiry: mustParseRFC3339("2026-02-17T10:40:00Z"),
+		Heartbeat:   mustParseRFC3339("2026-02-17T10:33:00Z"),
+	}
+	line := formatLine(state)
+	assert.Equal(t, "T-001|in_progress|claude|2026-02-17T10:30:00Z|a|b|ci:42|2026-02-17T10:40:00Z|2026-02-17T10:33:00Z", line)
+
+	got, err := parseLine(line)
+	require.NoError(t, err)
+	assert.Equal(t, state, *got)
+
+	// Unclaimed tasks keep the five-column format.
+	state.ClaimedBy = ""
+	assert.Equal(t, "T-001|in_progress|claude|2026-02-17T10:30:00Z|a|b", formatLine(state))
+}
+
+func TestTaskState_Lease(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	tests := []struct {
+		name        string
+		state       TaskState
+		wantActive  bool
+		wantExpired bool
+	}{
+		{name: "unclaimed", state: TaskState{}},
+		{name: "active", state: TaskState{ClaimedBy: "a", LeaseExpiry: now.Add(time.Minute)}, wantActive: true},
+		{name: "expired", state: TaskState{ClaimedBy: "a", LeaseExpiry: now.Add(-time.Minute)}, wantExpired: true},
+		{name: "expiring now", state: TaskState{ClaimedBy: "a", LeaseExpiry: now}, wantExpired: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.wantActive, tt.state.LeaseActive(now))
+			assert.Equal(t, tt.wantExpired, tt.state.LeaseExpired(now))
+		})
+	}
+}
+
+func TestDefaultClaimOwner(t *testing.T) {
+	t.Parallel()
+
+	owner := DefaultClaimOwner()
+	assert.True(t, strings.HasSuffix(owner, ":"+strconv.Itoa(os.Getpid())), owner)
+}
+
+// ---- Claim / Renew / Release ------------------------------------------------
+
+func TestStateManager_Claim(t *testing.T) {
+	t.Parallel()
+
+	sm := NewStateManager(writeTempState(t, "T-001|not_started|||keep me\n"))
+	require.NoError(t, sm.Claim("T-001", "host-a:1", "claude", time.Minute))
+
+	got, err := sm.Get("T-001")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, StatusInProgress, got.Status)
+	assert.Equal(t, "claude", got.Agent)
+	assert.Equal(t, "keep me", got.Notes)
+	assert.Equal(t, "host-a:1", got.ClaimedBy)
+	assert.True(t, got.LeaseActive(time.Now()))
+
+	history, err := sm.History("T-001")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, StatusNotStarted, history[0].PreviousStatus)
+	assert.Equal(t, StatusInProgress, history[0].Status)
+
+	// The lock file is removed after the write.
+	_, err = os.Stat(sm.filePath + ".lock")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStateManager_Claim_HeldByOtherOwner(t *testing.T) {
+	t.Parallel()
+
+	sm := NewStateManager(writeTempState(t, ""))
+	require.NoError(t, sm.Claim("T-001", "host-a:1", "claude", time.Minute))
+
+	err := sm.Claim("T-001", "host-b:2", "codex", time.Minute)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrTaskUnavailable))
+	assert.Contains(t, err.Error(), "held by host-a:1")
+
+	// The same owner may claim again, refreshing its lease.
+	require.NoError(t, sm.Claim("T-001", "host-a:1", "claude", time.Minute))
+}
+
+func TestStateManager_Claim_FinishedTask(t *testing.T) {
+	t.Parallel()
+
+	for _, status := range []TaskStatus{StatusCompleted, StatusSkipped, StatusBlocked} {
+		t.Run(string(status), func(t *testing.T) {
+			t.Parallel()
+
+			// The task finished after the runner selected it.
+			sm := NewStateManager(writeTempState(t, "T-001|"+string(status)+"|claude||done\n"))
+
+			err := sm.Claim("T-001", "host-a:1", "claude", time.Minute)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrTaskUnavailable))
+			assert.Contains(t, err.Error(), "status is "+string(status))
+
+			got, err := sm.Get("T-001")
+			require.NoError(t, err)
+			assert.Equal(t, status, got.Status)
+			assert.Empty(t, got.ClaimedBy)
+		})
+	}
+}
+
+func TestStateManager_Claim_TakesOverExpiredLease(t *testing.T) {
+	t.Parallel()
+
+	expired := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	sm := NewStateManager(writeTempState(t,
+		"T-001|in_progress|claude||notes|host-a:1|"+expired+"|"+expired+"\n"))
+
+	require.NoError(t, sm.Claim("T-001", "host-b:2", "codex", time.Minute))
+	got, err := sm.Get("T-001")
+	require.NoError(t, err)
+	assert.Equal(t, "host-b:2", got.ClaimedBy)
+	assert.Equal(t, "codex", got.Agent)
+	assert.Equal(t, "notes", got.Notes)
+}
+
+func TestStateManager_Claim_SanitizesOwner(t *testing.T) {
+	t.Parallel()
+
+	sm := NewStateManager(writeTempState(t, ""))
+	require.NoError(t, sm.Claim("T-001", "ci|runner\n", "claude", time.Minute))
+	got, err := sm.Get("T-001")
+	require.NoError(t, err)
+	assert.Equal(t, "ci-runner", got.ClaimedBy)
+
+	require.Error(t, sm.Claim("T-001", "  ", "claude", time.Minute))
+	require.Error(t, sm.Claim("", "owner", "claude", time.Minute))
+}
+
+func TestStateManager_Claim_ConcurrentManagers(t *testing.T) {
+	t.Parallel()
+
+	// Separate managers share only the file, like separate processes.
+	path := writeTempState(t, "")
+	owners := []string{"a:1", "b:2", "c:3", "d:4", "e:5", "f:6"}
+
+	var wg sync.WaitGroup
+	results := make([]error, len(owners))
+	for i, owner := range owners {
+		wg.Add(1)
+		go func(i int, owner string) {
+			defer wg.Done()
+			results[i] = NewStateManager(path).Claim("T-001", owner, "claude", time.Minute)
+		}(i, owner)
+	}
+	wg.Wait()
+
+	won := 0
+	for _, err := range results {
+		if err == nil {
+			won++
+			continue
+		}
+		assert.True(t, errors.Is(err, ErrTaskUnavailable), err)
+	}
+	assert.Equal(t, 1, won)
+}
+
+func TestStateManager_Renew(t *testing.T) {
+	t.Parallel()
+
+	sm := NewStateManager(writeTempState(t, ""))
+	require.NoError(t, sm.Claim("T-001", "host-a:1", "claude", time.Second))
+	before, err := sm.Get("T-001")
+	require.NoError(t, err)
+
+	require.NoError(t, sm.Renew("T-001", "host-a:1", time.Hour))
+	after, err := sm.Get("T-001")
+	require.NoError(t, err)
+	assert.True(t, after.LeaseExpiry.After(before.LeaseExpiry))
+	assert.False(t, after.Heartbeat.Before(before.Heartbeat))
+
+	// Renewals are not history entries.
+	history, err := sm.History("T-001")
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	err = sm.Renew("T-001", "host-b:2", time.Hour)
+	assert.True(t, errors.Is(err, ErrLeaseLost))
+	err = sm.Renew("T-999", "host-a:1", time.Hour)
+	assert.True(t, errors.Is(err, ErrLeaseLost))
+}
+
+func TestStateManager_Release(t *testing.T) {
+	t.Parallel()
+
+	sm := NewStateManager(writeTempState(t, ""))
+	require.NoError(t, sm.Claim("T-001", "host-a:1", "claude", time.Minute))
+
+	// Another owner cannot release the claim.
+	require.NoError(t, sm.Release("T-001", "host-b:2"))
+	got, err := sm.Get("T-001")
+	require.NoError(t, err)
+	assert.Equal(t, "host-a:1", got.ClaimedBy)
+
+	require.NoError(t, sm.Release("T-001", "host-a:1"))
+	got, err = sm.Get("T-001")
+	require.NoError(t, err)
+	assert.Empty(t, got.ClaimedBy)
+	assert.True(t, got.LeaseExpiry.IsZero())
+	assert.Equal(t, StatusInProgress, got.Status)
+
+	// Releasing an unknown task is a no-op.
+	require.NoError(t, sm.Release("T-999", "host-a:1"))
+}
+
+func TestStateManager_StatusChangesAndClaims(t *testing.T) {
+	t.Parallel()
+
+	sm := NewStateManager(writeTempState(t, ""))
+	require.NoError(t, sm.Claim("T-001", "host-a:1", "claude", time.Minute))
+
+	// Editing notes keeps the claim.
+	require.NoError(t, sm.UpdateNotes("T-001", "halfway"))
+	got, err := sm.Get("T-001")
+	require.NoError(t, err)
+	assert.Equal(t, "host-a:1", got.ClaimedBy)
+
+	// A status change ends it.
+	require.NoError(t, sm.UpdateStatus("T-001", StatusCompleted, "claude"))
+	got, err = sm.Get("T-001")
+	require.NoError(t, err)
+	assert.Empty(t, got.ClaimedBy)
+}
+
+func TestStateManager_Claim_SnapshotIsReadOnly(t *testing.T) {
+	t.Parallel()
+
+	sm := NewStateManager(writeTempState(t, ""))
+	require.NoError(t, sm.UpdateStatus("T-001", StatusNotStarted, ""))
+	snap, err := sm.At(time.Now())
+	require.NoError(t, err)
+	require.Error(t, snap.Claim("T-001", "host-a:1", "claude", time.Minute))
+}
+
+// ---- state file lock --------------------------------------------------------
+
+// These tests change the package-level lock timings and must not run in
+// parallel.
+
+func TestStateManager_LockFile_WaitsAndTimesOut(t *testing.T) {
+	oldTimeout := stateLockTimeout
+	stateLockTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { stateLockTimeout = oldTimeout })
+
+	path := writeTempState(t, "")
+	require.NoError(t, os.WriteFile(path+".lock", []byte("other:1\n"), 0644))
+
+	sm := NewStateManager(path)
+	err := sm.UpdateStatus("T-001", StatusCompleted, "claude")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+
+	// Once the other holder releases the lock, writes go through.
+	require.NoError(t, os.Remove(path+".lock"))
+	require.NoError(t, sm.UpdateStatus("T-001", StatusCompleted, "claude"))
+}
+
+func TestStateManager_LockFile_RemovesStaleLock(t *testing.T) {
+	path := writeTempState(t, "")
+	lockPath := path + ".lock"
+	require.NoError(t, os.WriteFile(lockPath, []byte("crashed:1\n"), 0644))
+	old := time.Now().Add(-2 * staleStateLockAge)
+	require.NoError(t, os.Chtimes(lockPath, old, old))
+
+	sm := NewStateManager(path)
+	require.NoError(t, sm.Claim("T-001", "host-a:1", "claude", time.Minute))
+	_, err := os.Stat(lockPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStateManager_LockFile_CreatesStateDir(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "task-state.conf")
+	sm := NewStateManager(path)
+	require.NoError(t, sm.Claim("T-001", "host-a:1", "claude", time.Minute))
+	_, err := os.Stat(path)
+	require.NoError(t, err)
+}
@@ -0,0 +1,109 @@
+package task
+
+import (
+	"slices"
+	"sort"
+)
+
+// PhaseMembership resolves which tasks belong to which phase. Unlike
+// PhaseForTask and TasksInPhase it sees the task specs, so glob patterns and
+// labels are resolved and every task is assigned to exactly one phase.
+type PhaseMembership struct {
+	phases  []Phase
+	byTask  map[string]int
+	members map[int][]string
+}
+
+// NewPhaseMembership assigns every task to its phase. The candidate tasks are
+// the spec IDs plus the IDs enumerated by each phase's range and literal Tasks
+// entries, so a phase also counts tasks whose spec file does not exist yet.
+// Each task goes to the phase PhaseForTask picks, consulting the spec's labels.
+func NewPhaseMembership(phases []Phase, specs []*ParsedTaskSpec) *PhaseMembership {
+	m := &PhaseMembership{
+		phases:  phases,
+		byTask:  make(map[string]int),
+		members: make(map[int][]string, len(phases)),
+	}
+
+	labels := make(map[string][]string, len(specs))
+	candidates := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		if _, seen := labels[spec.ID]; !seen {
+			candidates = append(candidates, spec.ID)
+		}
+		labels[spec.ID] = spec.Labels
+	}
+	for _, p := range phases {
+		for _, id := range TasksInPhase(p) {
+			if _, seen := labels[id]; !seen {
+				candidates = append(candidates, id)
+				labels[id] = nil
+			}
+		}
+	}
+
+	for _, id := range candidates {
+		p := phaseForTask(phases, id, labels[id])
+		if p == nil {
+			continue
+		}
+		m.byTask[id] = p.ID
+		m.members[p.ID] = append(m.members[p.ID], id)
+	}
+	for _, ids := range m.members {
+		sort.SliceStable(ids, func(i, j int) bool {
+			return CompareTaskIDs(ids[i], ids[j]) < 0
+		})
+	}
+	return m
+}
+
+// Phases returns the phases the membership was built from.
+func (m *PhaseMembership) Phases() []Phase {
+	return m.phases
+}
+
+// Phase returns the phase with the given ID, or nil if there is none.
+func (m *PhaseMembership) Phase(phaseID int) *Phase {
+	return PhaseByID(m.phases, phaseID)
+}
+
+// PhaseOf returns the phase containing taskID, or nil if the task belongs
+// to no phase.
+func (m *PhaseMembership) PhaseOf(taskID string) *Phase {
+	id, ok := m.byTask[taskID]
+	if !ok {
+		return nil
+	}
+	return PhaseByID(m.phases, id)
+}
+
+// Tasks returns the IDs of the tasks in phaseID sorted by CompareTaskIDs.
+// Returns nil for an unknown or empty phase.
+func (m *PhaseMembership) Tasks(phaseID int) []string {
+	return slices.Clone(m.members[phaseID])
+}
+
+// Contains reports whether taskID belongs to phaseID.
+func (m *PhaseMembership) Contains(phaseID int, taskID string) bool {
+	id, ok := m.byTask[taskID]
+	return ok && id == phaseID
+}
+
+// Bounds returns the first and last task of phaseID for display: the
+// StartTask/EndTask range when the phase has one, otherwise its lowest and
+// highest member. Both are empty when the phase is unknown or has no tasks.
+func (m *PhaseMembership) Bounds(phaseID int) (first, last string) {
+	p := m.Phase(phaseID)
+	if p == nil {
+		return "", ""
+	}
+	if p.HasRange() {
+		return p.StartTask, p.EndTask
+	}
+	ids := m.members[phaseID]
+	if len(ids) == 0 {
+		return "", ""
+	}
+	return ids[0], ids[len(ids)-1]
+}
@@ -0,0 +1,61 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func labeledSpec(id string, labels ...string) *ParsedTaskSpec {
+	spec := makeSpec(id, nil)
+	spec.Labels = labels
+	return spec
+}
+
+func TestPhaseMembership(t *testing.T) {
+	t.Parallel()
+
+	phases := []Phase{
+		{ID: 1, Name: "Foundation", StartTask: "T-001", EndTask: "T-004"},
+		{ID: 2, Name: "API", Tasks: []string{"T-003", "T-02*"}, Labels: []string{"api"}},
+		{ID: 3, Name: "Docs", Labels: []string{"docs"}},
+	}
+	specs := []*ParsedTaskSpec{
+		labeledSpec("T-001"),
+		labeledSpec("T-002", "api"),
+		labeledSpec("T-003"),
+		labeledSpec("T-010", "docs"),
+		labeledSpec("T-021"),
+		labeledSpec("T-030"),
+	}
+
+	m := NewPhaseMembership(phases, specs)
+
+	// T-004 has no spec but is in phase 1's range; T-003 is listed by phase 2.
+	assert.Equal(t, []string{"T-001", "T-004"}, m.Tasks(1))
+	assert.Equal(t, []string{"T-002", "T-003", "T-021"}, m.Tasks(2))
+	assert.Equal(t, []string{"T-010"}, m.Tasks(3))
+	assert.Nil(t, m.Tasks(9))
+
+	require.NotNil(t, m.PhaseOf("T-002"))
+	assert.Equal(t, 2, m.PhaseOf("T-002").ID)
+	assert.Nil(t, m.PhaseOf("T-030"))
+	assert.True(t, m.Contains(3, "T-010"))
+	assert.False(t, m.Contains(1, "T-003"))
+
+	first, last := m.Bounds(1)
+	assert.Equal(t, [2]string{"T-001", "T-004"}, [2]string{first, last})
+	first, last = m.Bounds(2)
+	assert.Equal(t, [2]string{"T-002", "T-021"}, [2]string{first, last})
+	first, last = m.Bounds(9)
+	assert.Empty(t, first+last)
+
+	assert.Len(t, m.Phases(), 3)
+	assert.Equal(t, "Docs", m.Phase(3).Name)
+
+	// Tasks returns a copy.
+	ids := m.Tasks(2)
+	ids[0] = "T-999"
+	assert.Equal(t, "T-002", m.Tasks(2)[0])
+}
@@ -0,0 +1,169 @@
+package task
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TaskMetrics is the measured effort spent implementing a task, summed over
+// every loop iteration that worked on it. The implementation loop records a
+// TaskMetrics per iteration with StateManager.RecordMetrics; the metrics file
+// holds the running totals.
+type TaskMetrics struct {
+	// TaskID is the task the metrics belong to.
+	TaskID string `json:"task_id"`
+	// Agent is the agent of the most recent iteration.
+	Agent string `json:"agent,omitempty"`
+	// Iterations is the number of agent invocations spent on the task.
+	Iterations int `json:"iterations"`
+	// WallTime is the time from task selection to the end of each agent
+	// invocation, including rate-limit waits.
+	WallTime time.Duration `json:"wall_time_ns"`
+	// AgentTime is the time the agent process ran.
+	AgentTime time.Duration `json:"agent_time_ns"`
+	// TokensIn and TokensOut are the input and output tokens reported by the
+	// agent's session statistics.
+	TokensIn  int `json:"tokens_in"`
+	TokensOut int `json:"tokens_out"`
+	// CostUSD is the session cost reported by the agent, in US dollars.
+	CostUSD float64 `json:"cost_usd"`
+	// Updated is when metrics were last recorded for the task (UTC).
+	Updated time.Time `json:"updated,omitzero"`
+}
+
+// Add accumulates other into m. Agent and Updated take other's values when
+// set.
+func (m *TaskMetrics) Add(other TaskMetrics) {
+	m.Iterations += other.Iterations
+	m.WallTime += other.WallTime
+	m.AgentTime += other.AgentTime
+	m.TokensIn += other.TokensIn
+	m.TokensOut += other.TokensOut
+	m.CostUSD += other.CostUSD
+	if other.Agent != "" {
+		m.Agent = other.Agent
+	}
+	if other.Updated.After(m.Updated) {
+		m.Updated = other.Updated
+	}
+}
+
+// MetricsFilePath returns the default metrics file path for a state file:
+// the state file path with its extension replaced by ".metrics.json", e.g.
+// "task-state.conf" becomes "task-state.metrics.json".
+func MetricsFilePath(stateFile string) string {
+	return strings.TrimSuffix(stateFile, filepath.Ext(stateFile)) + ".metrics.json"
+}
+
+// LoadMetrics reads the metrics file at path, keyed by task ID. A missing
+// file yields an empty map.
+func LoadMetrics(path string) (map[string]TaskMetrics, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]TaskMetrics{}, nil
+		}
+		return nil, fmt.Errorf("reading metrics file %q: %w", path, err)
+	}
+
+	var entries []TaskMetrics
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing metrics file %q: %w", path, err)
+	}
+	metrics := make(map[string]TaskMetrics, len(entries))
+	for _, m := range entries {
+		if m.TaskID == "" {
+			continue
+		}
+		metrics[m.TaskID] = m
+	}
+	return metrics, nil
+}
+
+// writeMetrics writes metrics to path as a JSON array sorted by task ID.
+func writeMetrics(path string, metrics map[string]TaskMetrics) error {
+	entries := make([]TaskMetrics, 0, len(metrics))
+	for _, m := range metrics {
+		entries = append(entries, m)
+	}
+	sort.Slice(entries, func(i, j int) bool { return CompareTaskIDs(entries[i].TaskID, entries[j].TaskID) < 0 })
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding metrics: %w", err)
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// SetMetricsFile overrides the metrics file path. An empty path disables
+// metrics recording.
+func (sm *StateManager) SetMetricsFile(path string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.metricsPath = path
+}
+
+// MetricsFile returns the metrics file path, or "" when recording is
+// disabled.
+func (sm *StateManager) MetricsFile() string {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.metricsPath
+}
+
+// Metrics returns the recorded metrics keyed by task ID. Tasks that were
+// never worked on by the implementation loop have no entry.
+func (sm *StateManager) Metrics() (map[string]TaskMetrics, error) {
+	path := sm.MetricsFile()
+	if path == "" {
+		return map[string]TaskMetrics{}, nil
+	}
+	return LoadMetrics(path)
+}
+
+// RecordMetrics adds delta to the recorded totals of delta.TaskID. Updated
+// defaults to now. The read-modify-write cycle holds the state file lock.
+func (sm *StateManager) RecordMetrics(delta TaskMetrics) error {
+	if delta.TaskID == "" {
+		return fmt.Errorf("recording metrics: task ID must not be empty")
+	}
+	if delta.Updated.IsZero() {
+		delta.Updated = time.Now().UTC()
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if sm.metricsPath == "" {
+		return nil
+	}
+	if sm.snapshot != nil {
+		return fmt.Errorf("recording metrics for task %q: state snapshot is read-only", delta.TaskID)
+	}
+
+	unlock, err := sm.lockFile()
+	if err != nil {
+		return fmt.Errorf("recording metrics for task %q: %w", delta.TaskID, err)
+	}
+	defer unlock()
+
+	metrics, err := LoadMetrics(sm.metricsPath)
+	if err != nil {
+		return fmt.Errorf("recording metrics for task %q: %w", delta.TaskID, err)
+	}
+	m := metrics[delta.TaskID]
+	m.TaskID = delta.TaskID
+	m.Add(delta)
+	metrics[delta.TaskID] = m
+
+	if err := writeMetrics(sm.metricsPath, metrics); err != nil {
+		return fmt.Errorf("recording metrics for task %q: %w", delta.TaskID, err)
+	}
+	return nil
+}
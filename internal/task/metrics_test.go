@@ -0,0 +1,134 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsFilePath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "docs/tasks/task-state.metrics.json", MetricsFilePath("docs/tasks/task-state.conf"))
+	assert.Equal(t, "state.metrics.json", MetricsFilePath("state"))
+}
+
+func TestTaskMetrics_Add(t *testing.T) {
+	t.Parallel()
+
+	earlier := mustParseRFC3339("2026-03-01T10:00:00Z")
+	later := mustParseRFC3339("2026-03-01T11:00:00Z")
+
+	m := TaskMetrics{TaskID: "T-001", Agent: "claude", Iterations: 1, WallTime: time.Minute, CostUSD: 0.5, Updated: later}
+	m.Add(TaskMetrics{Iterations: 2, WallTime: 2 * time.Minute, AgentTime: time.Minute, TokensIn: 10, TokensOut: 5, CostUSD: 0.25, Updated: earlier})
+
+	assert.Equal(t, TaskMetrics{
+		TaskID:     "T-001",
+		Agent:      "claude",
+		Iterations: 3,
+		WallTime:   3 * time.Minute,
+		AgentTime:  time.Minute,
+		TokensIn:   10,
+		TokensOut:  5,
+		CostUSD:    0.75,
+		Updated:    later,
+	}, m)
+}
+
+func TestStateManager_RecordMetrics(t *testing.T) {
+	t.Parallel()
+
+	statePath := filepath.Join(t.TempDir(), "task-state.conf")
+	sm := NewStateManager(statePath)
+	assert.Equal(t, MetricsFilePath(statePath), sm.MetricsFile())
+
+	metrics, err := sm.Metrics()
+	require.NoError(t, err)
+	assert.Empty(t, metrics, "missing file yields no metrics")
+
+	require.NoError(t, sm.RecordMetrics(TaskMetrics{TaskID: "T-002", Agent: "codex", Iterations: 1, WallTime: time.Minute, TokensIn: 100, CostUSD: 0.1}))
+	require.NoError(t, sm.RecordMetrics(TaskMetrics{TaskID: "T-002", Agent: "claude", Iterations: 1, WallTime: 2 * time.Minute, TokensIn: 50, CostUSD: 0.2}))
+	require.NoError(t, sm.RecordMetrics(TaskMetrics{TaskID: "T-001", Iterations: 1, AgentTime: 30 * time.Second}))
+
+	metrics, err = NewStateManager(statePath).Metrics()
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+	m := metrics["T-002"]
+	assert.Equal(t, "claude", m.Agent)
+	assert.Equal(t, 2, m.Iterations)
+	assert.Equal(t, 3*time.Minute, m.WallTime)
+	assert.Equal(t, 150, m.TokensIn)
+	assert.InDelta(t, 0.3, m.CostUSD, 1e-9)
+	assert.False(t, m.Updated.IsZero())
+	assert.Equal(t, 30*time.Second, metrics["T-001"].AgentTime)
+
+	_, err = os.Stat(statePath + ".lock")
+	assert.True(t, os.IsNotExist(err), "lock released")
+
+	require.Error(t, sm.RecordMetrics(TaskMetrics{}), "task ID is required")
+}
+
+func TestStateManager_RecordMetrics_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	statePath := filepath.Join(t.TempDir(), "task-state.conf")
+	const writers = 8
+
+	var wg sync.WaitGroup
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Separate managers behave like separate processes.
+			assert.NoError(t, NewStateManager(statePath).RecordMetrics(TaskMetrics{TaskID: "T-001", Iterations: 1}))
+		}()
+	}
+	wg.Wait()
+
+	metrics, err := LoadMetrics(MetricsFilePath(statePath))
+	require.NoError(t, err)
+	assert.Equal(t, writers, metrics["T-001"].Iterations)
+}
+
+func TestStateManager_RecordMetrics_Disabled(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sm := NewStateManager(filepath.Join(dir, "task-state.conf"))
+	sm.SetMetricsFile("")
+
+	require.NoError(t, sm.RecordMetrics(TaskMetrics{TaskID: "T-001", Iterations: 1}))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestStateManager_RecordMetrics_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	sm := NewStateManager(filepath.Join(t.TempDir(), "task-state.conf"))
+	require.NoError(t, sm.RecordMetrics(TaskMetrics{TaskID: "T-001", Iterations: 1}))
+
+	past, err := sm.At(time.Now())
+	require.NoError(t, err)
+	metrics, err := past.Metrics()
+	require.NoError(t, err)
+	assert.Equal(t, 1, metrics["T-001"].Iterations, "snapshots report current metrics")
+	require.Error(t, past.RecordMetrics(TaskMetrics{TaskID: "T-001", Iterations: 1}))
+}
+
+func TestLoadMetrics_Malformed(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "task-state.metrics.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o644))
+
+	_, err := LoadMetrics(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing metrics file")
+}
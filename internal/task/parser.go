@@ -20,8 +20,8 @@ var utf8BOM = "\xef\xbb\xbf"
 
 // Pre-compiled regexes for parsing task spec markdown files.
 var (
-	// reTitleLine matches "# T-001: Some Title" or "# T-001 - Some Title" at the start of a line.
-	reTitleLine = regexp.MustCompile(`^#\s+T-(\d{3})(?::\s*|\s+-\s+)(.+)$`)
+	// reTitleLine matches "# T-001: Some Title" or "# API-1042 - Some Title" at the start of a line.
+	reTitleLine = regexp.MustCompile(`^#\s+(` + taskIDPattern + `)(?::\s*|\s+-\s+)(.+)$`)
 
 	// reMetaDeps matches "| Dependencies | T-001, T-003 |" in metadata table.
 	reMetaDeps = regexp.MustCompile(`(?i)\|\s*Dependencies\s*\|\s*([^|]+)\|`)
@@ -38,16 +38,20 @@ var (
 	// reMetaBlocks matches "| Blocks | T-005, T-006 |".
 	reMetaBlocks = regexp.MustCompile(`(?i)\|\s*Blocks\s*\|\s*([^|]+)\|`)
 
-	// reTaskRef matches task ID references like "T-001", "T-123".
-	reTaskRef = regexp.MustCompile(`T-(\d{3})`)
+	// reTaskRef matches task ID references like "T-001", "T-1234", "API-0042".
+	reTaskRef = regexp.MustCompile(`\b` + taskIDPattern + `\b`)
 
-	// reTaskFilename matches task spec filenames like "T-001-some-description.md".
-	reTaskFilename = regexp.MustCompile(`^T-(\d{3})-[\w-]+\.md$`)
+	// reTaskID matches a complete task ID like "T-001" or "API-0042".
+	reTaskID = regexp.MustCompile(`^` + taskIDPattern + `$`)
+
+	// reTaskFilename matches task spec filenames like "T-001-some-description.md"
+	// or "API-1042-some-description.md".
+	reTaskFilename = regexp.MustCompile(`^` + taskIDPattern + `-[\w-]+\.md$`)
 )
 
 // ParsedTaskSpec holds all data extracted from a task spec markdown file.
 type ParsedTaskSpec struct {
-	// ID is the zero-padded task identifier, e.g. "T-016".
+	// ID is the zero-padded task identifier, e.g. "T-016" or "API-1042".
 	ID string
 	// Title is the human-readable task name extracted from the heading.
 	Title string
@@ -61,6 +65,29 @@ type ParsedTaskSpec struct {
 	BlockedBy []string
 	// Blocks are task IDs listed in the Blocks metadata row.
 	Blocks []string
+
+	// The following fields are only populated from front matter.
+
+	// Labels are free-form tags used for filtering and grouping.
+	Labels []string
+	// Owners are the people responsible for the task.
+	Owners []string
+	// Files are the target files or glob patterns the task is expected to touch.
+	Files []string
+	// AcceptanceCriteria are the conditions that must hold for completion.
+	AcceptanceCriteria []string
+	// Agent overrides the agent used to implement this task.
+	Agent string
+	// Model overrides the model configured for the implementing agent.
+	Model string
+	// VerificationCommands override the project verification commands.
+	VerificationCommands []string
+	// GitHubIssue is the number of the GitHub issue the task is synced with;
+	// zero when the task has not been exported.
+	GitHubIssue int
+	// HasFrontMatter reports whether the spec declared a front matter block.
+	HasFrontMatter bool
+
 	// Content is the complete, unmodified markdown content.
 	Content string
 	// SpecFile is the filesystem path the spec was read from.
@@ -70,6 +97,11 @@ type ParsedTaskSpec struct {
 // ParseTaskSpec parses raw markdown content of a task spec file.
 // It returns a ParsedTaskSpec or an error if the content does not contain
 // a valid task spec heading ("# T-NNN: Title" or "# T-NNN - Title").
+//
+// The content may start with a YAML ("---") or TOML ("+++") front matter
+// block (see TaskFrontMatter). Front matter values take precedence over the
+// heading and metadata table; when it supplies both id and title the heading
+// becomes optional.
 func ParseTaskSpec(content string) (*ParsedTaskSpec, error) {
 	// Strip UTF-8 BOM if present.
 	content = strings.TrimPrefix(content, utf8BOM)
@@ -78,19 +110,36 @@ func ParseTaskSpec(content string) (*ParsedTaskSpec, error) {
 	content = strings.ReplaceAll(content, "\r\n", "\n")
 
 	spec := &ParsedTaskSpec{
-		Content:      content,
-		Dependencies: []string{},
-		BlockedBy:    []string{},
-		Blocks:       []string{},
+		Content:              content,
+		Dependencies:         []string{},
+		BlockedBy:            []string{},
+		Blocks:               []string{},
+		Labels:               []string{},
+		Owners:               []string{},
+		Files:                []string{},
+		AcceptanceCriteria:   []string{},
+		VerificationCommands: []string{},
+	}
+
+	var fm *TaskFrontMatter
+	block, delim, body, hasFM, err := splitFrontMatter(content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing task spec: %w", err)
+	}
+	if hasFM {
+		fm, err = parseFrontMatter(block, delim)
+		if err != nil {
+			return nil, fmt.Errorf("parsing task spec: %w", err)
+		}
 	}
 
 	foundTitle := false
-	for _, line := range strings.Split(content, "\n") {
+	for _, line := range strings.Split(body, "\n") {
 		// --- Title line ---
 		if !foundTitle {
 			if m := reTitleLine.FindStringSubmatch(line); m != nil {
-				spec.ID = "T-" + m[1]
-				spec.Title = strings.TrimSpace(m[2])
+				spec.ID = m[1]
+				spec.Title = strings.TrimSpace(m[4])
 				foundTitle = true
 				continue
 			}
@@ -123,6 +172,15 @@ func ParseTaskSpec(content string) (*ParsedTaskSpec, error) {
 		}
 	}
 
+	if fm != nil {
+		if err := applyFrontMatter(spec, fm); err != nil {
+			return nil, fmt.Errorf("parsing task spec: %w", err)
+		}
+		if spec.ID != "" && spec.Title != "" {
+			foundTitle = true
+		}
+	}
+
 	if !foundTitle {
 		return nil, fmt.Errorf("parsing task spec: no valid task heading found (expected '# T-NNN: Title' or '# T-NNN - Title')")
 	}
@@ -158,12 +216,13 @@ func ParseTaskFile(path string) (*ParsedTaskSpec, error) {
 	return spec, nil
 }
 
-// DiscoverTasks scans dir for files matching "T-[0-9][0-9][0-9]-*.md",
-// parses each one, and returns the results sorted by task ID.
+// DiscoverTasks scans dir for files named "<TASK-ID>-<slug>.md" (for example
+// "T-001-setup.md" or "API-1042-retries.md"), parses each one, and returns the
+// results sorted by task ID (prefix, then numerically).
 // An error is returned if any file cannot be parsed or if duplicate task IDs
 // are found.
 func DiscoverTasks(dir string) ([]*ParsedTaskSpec, error) {
-	pattern := filepath.Join(dir, "T-[0-9][0-9][0-9]-*.md")
+	pattern := filepath.Join(dir, "*-[0-9][0-9][0-9]*-*.md")
 	matches, err := filepath.Glob(pattern)
 	if err != nil {
 		return nil, fmt.Errorf("globbing task specs in %q: %w", dir, err)
@@ -198,9 +257,9 @@ func DiscoverTasks(dir string) ([]*ParsedTaskSpec, error) {
 		specs = append(specs, spec)
 	}
 
-	// Sort lexicographically by ID; zero-padded IDs sort correctly.
+	// Sort numerically so that "T-999" precedes "T-1000".
 	sort.Slice(specs, func(i, j int) bool {
-		return specs[i].ID < specs[j].ID
+		return CompareTaskIDs(specs[i].ID, specs[j].ID) < 0
 	})
 
 	return specs, nil
@@ -223,7 +282,19 @@ func (p *ParsedTaskSpec) ToTask() *Task {
 	}
 }
 
-// extractTaskRefs returns all T-NNN references found in s.
+// HasLabel reports whether the spec carries label (case-insensitive).
+func (p *ParsedTaskSpec) HasLabel(label string) bool {
+	label = strings.TrimSpace(label)
+	for _, l := range p.Labels {
+		if strings.EqualFold(l, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractTaskRefs returns all task ID references (e.g. "T-001", "API-1042")
+// found in s.
 // If s is "None" (case-insensitive) or contains no task references,
 // an empty (non-nil) slice is returned.
 func extractTaskRefs(s string) []string {
@@ -239,7 +310,7 @@ func extractTaskRefs(s string) []string {
 
 	refs := make([]string, 0, len(all))
 	for _, m := range all {
-		refs = append(refs, "T-"+m[1])
+		refs = append(refs, m[0])
 	}
 	return refs
 }
@@ -141,6 +141,16 @@ func TestParseTaskSpec_HeadingFormats(t *testing.T) {
 			content: "# T-001 - Setup\n",
 			wantID:  "T-001",
 		},
+		{
+			name:    "four-digit ID",
+			content: "# T-1042: Beyond 999\n",
+			wantID:  "T-1042",
+		},
+		{
+			name:    "custom prefix",
+			content: "# API-0042: Retry budget\n",
+			wantID:  "API-0042",
+		},
 		{
 			name:    "missing colon entirely",
 			content: "# T-001 Setup\n",
@@ -151,11 +161,6 @@ func TestParseTaskSpec_HeadingFormats(t *testing.T) {
 			content: "# T-01: Too Short\n",
 			wantErr: true,
 		},
-		{
-			name:    "four-digit ID rejected",
-			content: "# T-0001: Too Long\n",
-			wantErr: true,
-		},
 		{
 			name:    "missing hash prefix rejected",
 			content: "T-001: No Hash\n",
@@ -537,6 +542,23 @@ func TestDiscoverTasks_SortedByID(t *testing.T) {
 	assert.Equal(t, "T-003", specs[2].ID)
 }
 
+func TestDiscoverTasks_VariableWidthAndPrefix(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, dir, "T-1000-big.md", "# T-1000: Big\n\n| Dependencies | T-999, API-0042 |\n")
+	writeFile(t, dir, "T-999-almost.md", "# T-999: Almost\n")
+	writeFile(t, dir, "API-0042-retries.md", "# API-0042: Retries\n")
+
+	specs, err := DiscoverTasks(dir)
+	require.NoError(t, err)
+	require.Len(t, specs, 3)
+	assert.Equal(t, "API-0042", specs[0].ID)
+	assert.Equal(t, "T-999", specs[1].ID)
+	assert.Equal(t, "T-1000", specs[2].ID)
+	assert.Equal(t, []string{"T-999", "API-0042"}, specs[2].Dependencies)
+}
+
 func TestDiscoverTasks_EmptyDirectory(t *testing.T) {
 	t.Parallel()
 
@@ -866,7 +888,8 @@ func TestReTaskFilename(t *testing.T) {
 		{name: "wrong extension txt", filename: "T-001-setup.txt", want: false},
 		{name: "wrong extension no extension", filename: "T-001-setup", want: false},
 		{name: "two digit ID", filename: "T-01-setup.md", want: false},
-		{name: "four digit ID", filename: "T-0001-setup.md", want: false},
+		{name: "four digit ID", filename: "T-0001-setup.md", want: true},
+		{name: "custom prefix", filename: "API-1042-retries.md", want: true},
 		{name: "lowercase t prefix", filename: "t-001-setup.md", want: false},
 		{name: "space in filename", filename: "T-001-some task.md", want: false},
 		{name: "description starts with digit", filename: "T-002-2nd-task.md", want: true},
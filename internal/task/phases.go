@@ -4,9 +4,14 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/BurntSushi/toml"
 )
 
 // maxPhasesFileSize is the maximum number of bytes read from phases.conf.
@@ -22,8 +27,11 @@ const maxPhasesFileSize = 64 * 1024 // 64 KiB
 //	 1|foundation|Foundation|001|015|🏗
 //
 // Empty lines and lines whose first non-space character is '#' are skipped.
+// Files with a .toml extension are parsed with ParsePhasesTOML instead.
+// Bare numeric task bounds get prefix, the configured task ID prefix; an
+// empty prefix means DefaultTaskIDPrefix.
 // Returns an error if the file cannot be read or contains malformed lines.
-func LoadPhases(path string) ([]Phase, error) {
+func LoadPhases(path, prefix string) ([]Phase, error) {
 	info, err := os.Stat(path)
 	if err != nil {
 		return nil, fmt.Errorf("loading phases file %q: %w", path, err)
@@ -32,6 +40,18 @@ func LoadPhases(path string) ([]Phase, error) {
 		return nil, fmt.Errorf("loading phases file %q: file exceeds 64 KiB limit", path)
 	}
 
+	if IsPhasesTOML(path) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading phases file %q: %w", path, err)
+		}
+		phases, err := ParsePhasesTOML(data)
+		if err != nil {
+			return nil, fmt.Errorf("loading phases file %q: %w", path, err)
+		}
+		return phases, nil
+	}
+
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("loading phases file %q: %w", path, err)
@@ -53,7 +73,7 @@ func LoadPhases(path string) ([]Phase, error) {
 			continue
 		}
 
-		p, err := ParsePhaseLine(trimmed)
+		p, err := parsePhaseLine(trimmed, prefix)
 		if err != nil {
 			return nil, fmt.Errorf("loading phases file %q line %d: %w", path, lineNum, err)
 		}
@@ -72,6 +92,64 @@ func LoadPhases(path string) ([]Phase, error) {
 	return phases, nil
 }
 
+// IsPhasesTOML reports whether path names a TOML phases file (".toml"
+// extension) rather than the pipe-delimited phases.conf format.
+func IsPhasesTOML(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".toml")
+}
+
+// phasesTOML is the document layout of a TOML phases file.
+type phasesTOML struct {
+	Phase []Phase `toml:"phase"`
+}
+
+// ParsePhasesTOML parses a TOML phases file made of [[phase]] tables:
+//
+//	[[phase]]
+//	id = 1
+//	name = "Foundation"
+//	start_task = "T-001"
+//	end_task = "T-010"
+//
+//	[[phase]]
+//	id = 2
+//	name = "API"
+//	tasks = ["T-011", "T-05*"]
+//	labels = ["api"]
+//	depends_on = [1]
+//
+// Each phase needs a name and at least one of a start_task/end_task range,
+// tasks or labels. Unknown keys are rejected so typos do not silently empty a
+// phase. The result is sorted by ascending phase ID.
+func ParsePhasesTOML(data []byte) ([]Phase, error) {
+	var doc phasesTOML
+	md, err := toml.Decode(string(data), &doc)
+	if err != nil {
+		return nil, fmt.Errorf("parsing phases TOML: %w", err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("parsing phases TOML: unknown key %q", undecoded[0].String())
+	}
+
+	for i := range doc.Phase {
+		p := &doc.Phase[i]
+		if p.Name == "" {
+			return nil, fmt.Errorf("parsing phases TOML: phase %d has empty name", p.ID)
+		}
+		if p.HasRange() && (p.StartTask == "" || p.EndTask == "") {
+			return nil, fmt.Errorf("parsing phases TOML: phase %d needs both start_task and end_task", p.ID)
+		}
+		if !p.HasRange() && len(p.Tasks) == 0 && len(p.Labels) == 0 {
+			return nil, fmt.Errorf("parsing phases TOML: phase %d has no start_task/end_task, tasks or labels", p.ID)
+		}
+	}
+
+	sort.SliceStable(doc.Phase, func(i, j int) bool {
+		return doc.Phase[i].ID < doc.Phase[j].ID
+	})
+	return doc.Phase, nil
+}
+
 // ParsePhaseLine parses a single pipe-delimited phases.conf line into a Phase
 // struct.
 //
@@ -79,17 +157,24 @@ func LoadPhases(path string) ([]Phase, error) {
 //
 //  1. Four-field: "1|Foundation & Setup|T-001|T-010"
 //     (phase_id|name|start_task|end_task)
-//     Detected when field[2] starts with "T-".
+//     Detected when field[2] is a task ID (any prefix, e.g. "API-0042").
 //
 //  2. Six-or-more-field: "1|foundation|Foundation|001|015|🏗"
 //     (phase_id|slug|display_name|task_start_num|task_end_num|icon…)
-//     Detected when field[2] does NOT start with "T-".
+//     Detected when field[2] is NOT a task ID.
 //
-// In the six-field format the numeric task start/end are zero-padded numbers
-// that are converted to canonical "T-NNN" IDs automatically.
+// In the six-field format the task start/end are either zero-padded numbers,
+// which are converted to IDs with DefaultTaskIDPrefix keeping their width
+// ("0042" -> "T-0042"), or full task IDs such as "API-0042".
 // Leading/trailing whitespace around each field is trimmed.
 // Returns an error if the line has fewer than 4 fields or a non-numeric ID.
 func ParsePhaseLine(line string) (*Phase, error) {
+	return parsePhaseLine(line, DefaultTaskIDPrefix)
+}
+
+// parsePhaseLine is ParsePhaseLine with the prefix given to bare numeric
+// task bounds.
+func parsePhaseLine(line, prefix string) (*Phase, error) {
 	// Trim BOM just in case the line is the very first line of a BOM-prefixed file.
 	line = strings.TrimPrefix(line, "\xef\xbb\xbf")
 
@@ -111,15 +196,15 @@ func ParsePhaseLine(line string) (*Phase, error) {
 	p := &Phase{ID: id}
 
 	// Detect format by checking whether the third field (index 2) is a task ID
-	// ("T-NNN") or a display name (which means we have the six-field format
+	// ("PREFIX-NNN") or a display name (which means we have the six-field format
 	// with ID|Slug|DisplayName|StartNum|EndNum|Icon…).
 	//
 	// Four-field format:  ID | Name        | T-NNN | T-NNN
 	// Six-field format:   ID | slug        | Name  | NNN   | NNN | Icon
 	//
-	// The heuristic: if field[2] starts with "T-" it is the start task (four-
+	// The heuristic: if field[2] is a task ID it is the start task (four-
 	// field); otherwise it is the display name (six-field).
-	fourField := strings.HasPrefix(parts[2], "T-")
+	fourField := IsTaskID(parts[2])
 
 	if fourField {
 		// Four-field format: ID|Name|StartTask|EndTask
@@ -134,16 +219,16 @@ func ParsePhaseLine(line string) (*Phase, error) {
 		}
 		p.Name = parts[2]
 
-		start, err := strconv.Atoi(parts[3])
+		start, err := phaseBoundTaskID(parts[3], prefix)
 		if err != nil {
 			return nil, fmt.Errorf("parsing phase line: non-numeric task start %q in %q", parts[3], line)
 		}
-		end, err := strconv.Atoi(parts[4])
+		end, err := phaseBoundTaskID(parts[4], prefix)
 		if err != nil {
 			return nil, fmt.Errorf("parsing phase line: non-numeric task end %q in %q", parts[4], line)
 		}
-		p.StartTask = fmt.Sprintf("T-%03d", start)
-		p.EndTask = fmt.Sprintf("T-%03d", end)
+		p.StartTask = start
+		p.EndTask = end
 	}
 
 	if p.Name == "" {
@@ -156,30 +241,113 @@ func ParsePhaseLine(line string) (*Phase, error) {
 	return p, nil
 }
 
-// PhaseForTask returns the Phase that contains the given task ID based on the
-// task ID's numeric component falling within [StartTask, EndTask] (inclusive).
-// Returns nil if no phase contains the task.
-func PhaseForTask(phases []Phase, taskID string) *Phase {
-	num, err := TaskIDNumber(taskID)
-	if err != nil {
-		return nil
+// phaseBoundTaskID converts a six-field phases.conf task bound into a task ID.
+// A full task ID ("API-0042") is returned unchanged; a bare number ("0042")
+// gets prefix and keeps its digit width.
+func phaseBoundTaskID(field, prefix string) (string, error) {
+	if IsTaskID(field) {
+		return field, nil
+	}
+	n, err := strconv.Atoi(field)
+	if err != nil || n < 0 {
+		return "", fmt.Errorf("invalid task bound %q", field)
 	}
+	return FormatTaskID(prefix, n, len(field)), nil
+}
+
+// Phase match ranks, from weakest to strongest. When several phases match a
+// task, the strongest match wins.
+const (
+	phaseMatchNone = iota
+	phaseMatchRange
+	phaseMatchLabel
+	phaseMatchGlob
+	phaseMatchExplicit
+)
 
+// PhaseForTask returns the Phase that contains the given task ID, or nil if
+// no phase contains it. Labels are not consulted; use PhaseMembership to
+// place tasks by their spec labels as well.
+//
+// A task may match several phases. An explicit ID in Tasks beats a glob
+// pattern in Tasks, which beats a label, which beats a StartTask/EndTask
+// range; among equally strong matches the phase listed first wins.
+func PhaseForTask(phases []Phase, taskID string) *Phase {
+	return phaseForTask(phases, taskID, nil)
+}
+
+// phaseForTask is PhaseForTask with the task's labels.
+func phaseForTask(phases []Phase, taskID string, labels []string) *Phase {
+	var best *Phase
+	bestRank := phaseMatchNone
 	for i := range phases {
-		p := &phases[i]
-		start, err := TaskIDNumber(p.StartTask)
-		if err != nil {
-			continue
+		if rank := phaseMatch(phases[i], taskID, labels); rank > bestRank {
+			best, bestRank = &phases[i], rank
+		}
+	}
+	return best
+}
+
+// phaseMatch returns how strongly phase claims the task: one of the
+// phaseMatch constants.
+func phaseMatch(phase Phase, taskID string, labels []string) int {
+	rank := phaseMatchNone
+	for _, entry := range phase.Tasks {
+		if entry == taskID {
+			return phaseMatchExplicit
+		}
+		if isTaskGlob(entry) {
+			if ok, _ := path.Match(entry, taskID); ok {
+				rank = phaseMatchGlob
+			}
 		}
-		end, err := TaskIDNumber(p.EndTask)
+	}
+	if rank != phaseMatchNone {
+		return rank
+	}
+
+	for _, want := range phase.Labels {
+		for _, l := range labels {
+			if strings.EqualFold(want, l) {
+				return phaseMatchLabel
+			}
+		}
+	}
+
+	if phase.HasRange() {
+		id, err := ParseTaskID(taskID)
 		if err != nil {
-			continue
+			return phaseMatchNone
 		}
-		if num >= start && num <= end {
-			return p
+		start, end, err := phaseBounds(phase)
+		if err == nil && start.Prefix == id.Prefix && id.Number >= start.Number && id.Number <= end.Number {
+			return phaseMatchRange
 		}
 	}
-	return nil
+	return phaseMatchNone
+}
+
+// isTaskGlob reports whether a Tasks entry is a glob pattern rather than a
+// literal task ID.
+func isTaskGlob(entry string) bool {
+	return strings.ContainsAny(entry, "*?[\\")
+}
+
+// phaseBounds parses a phase's StartTask and EndTask. Returns an error when
+// either cannot be parsed or when they use different prefixes.
+func phaseBounds(phase Phase) (start, end TaskIDParts, err error) {
+	start, err = ParseTaskID(phase.StartTask)
+	if err != nil {
+		return TaskIDParts{}, TaskIDParts{}, err
+	}
+	end, err = ParseTaskID(phase.EndTask)
+	if err != nil {
+		return TaskIDParts{}, TaskIDParts{}, err
+	}
+	if start.Prefix != end.Prefix {
+		return TaskIDParts{}, TaskIDParts{}, fmt.Errorf("phase %d: start task %s and end task %s use different prefixes", phase.ID, phase.StartTask, phase.EndTask)
+	}
+	return start, end, nil
 }
 
 // PhaseByID returns the Phase with the given numeric ID.
@@ -194,52 +362,68 @@ func PhaseByID(phases []Phase, id int) *Phase {
 }
 
 // TaskIDNumber extracts the numeric portion of a task ID.
-// Examples: "T-016" -> 16, "T-001" -> 1.
-// Returns an error if the ID does not follow the "T-NNN" pattern.
+// Examples: "T-016" -> 16, "T-1042" -> 1042, "API-0042" -> 42.
+// Returns an error if the ID does not follow the "PREFIX-NNN" pattern.
 func TaskIDNumber(taskID string) (int, error) {
-	trimmed := strings.TrimSpace(taskID)
-	if !strings.HasPrefix(trimmed, "T-") {
-		return 0, fmt.Errorf("task ID %q does not have the required 'T-' prefix", taskID)
-	}
-	numStr := trimmed[2:]
-	if numStr == "" {
-		return 0, fmt.Errorf("task ID %q has no numeric suffix", taskID)
-	}
-	n, err := strconv.Atoi(numStr)
+	id, err := ParseTaskID(taskID)
 	if err != nil {
-		return 0, fmt.Errorf("task ID %q has non-numeric suffix %q", taskID, numStr)
+		return 0, err
 	}
-	return n, nil
+	return id.Number, nil
 }
 
-// TasksInPhase returns all task IDs (as "T-NNN" strings) that fall within a
-// phase's [StartTask, EndTask] range (inclusive). Task IDs are zero-padded to
-// three digits.
+// TasksInPhase returns all task IDs that fall within a phase's [StartTask,
+// EndTask] range (inclusive) plus the literal task IDs in its Tasks list,
+// sorted by CompareTaskIDs. Range IDs use StartTask's prefix and are
+// zero-padded to StartTask's digit width, growing naturally past it ("T-998"
+// .. "T-1002").
 //
-// Returns an empty slice if StartTask or EndTask cannot be parsed, or if
-// StartTask > EndTask.
+// Glob patterns and labels can only be resolved against task specs, and
+// tasks claimed more strongly by another phase are not excluded; use
+// PhaseMembership for the exact membership. The range part is empty if
+// StartTask or EndTask cannot be parsed, if they use different prefixes, or
+// if StartTask > EndTask.
 func TasksInPhase(phase Phase) []string {
-	start, err := TaskIDNumber(phase.StartTask)
-	if err != nil {
+	ids := phaseRangeIDs(phase)
+	explicit := false
+	for _, entry := range phase.Tasks {
+		if !isTaskGlob(entry) && !slices.Contains(ids, entry) {
+			ids = append(ids, entry)
+			explicit = true
+		}
+	}
+	if explicit {
+		sort.SliceStable(ids, func(i, j int) bool {
+			return CompareTaskIDs(ids[i], ids[j]) < 0
+		})
+	}
+	return ids
+}
+
+// phaseRangeIDs enumerates the task IDs in a phase's [StartTask, EndTask]
+// range. Returns an empty slice when the phase has no valid range.
+func phaseRangeIDs(phase Phase) []string {
+	if !phase.HasRange() {
 		return []string{}
 	}
-	end, err := TaskIDNumber(phase.EndTask)
+	start, end, err := phaseBounds(phase)
 	if err != nil {
 		return []string{}
 	}
-	if start > end {
+	if start.Number > end.Number {
 		return []string{}
 	}
 
-	ids := make([]string, 0, end-start+1)
-	for i := start; i <= end; i++ {
-		ids = append(ids, fmt.Sprintf("T-%03d", i))
+	ids := make([]string, 0, end.Number-start.Number+1)
+	for i := start.Number; i <= end.Number; i++ {
+		ids = append(ids, FormatTaskID(start.Prefix, i, start.Digits))
 	}
 	return ids
 }
 
 // FormatPhaseLine formats a Phase back into the canonical four-field
-// pipe-delimited form: "ID|Name|StartTask|EndTask".
+// pipe-delimited form: "ID|Name|StartTask|EndTask". The pipe format cannot
+// express Tasks, Labels or DependsOn; they are dropped.
 func FormatPhaseLine(phase Phase) string {
 	return strings.Join([]string{
 		strconv.Itoa(phase.ID),
@@ -254,13 +438,21 @@ func FormatPhaseLine(phase Phase) string {
 //
 //   - Every phase has a non-empty Name and non-zero ID.
 //   - No two phases share the same ID.
-//   - Every phase has StartTask <= EndTask (by numeric value).
-//   - No two phases have overlapping task-ID ranges.
+//   - Every phase defines a StartTask/EndTask range, Tasks or Labels.
+//   - A range has StartTask and EndTask with the same ID prefix and
+//     StartTask <= EndTask (by numeric value).
+//   - Every Tasks entry is a task ID or a valid glob pattern, and no task ID
+//     is listed explicitly by two phases.
+//   - DependsOn references existing phases other than the phase itself and
+//     does not form a cycle.
+//   - No two phases with the same prefix have overlapping task-ID ranges.
 //
 // Returns a non-nil error describing the first violation found.
 func ValidatePhases(phases []Phase) error {
 	// Check IDs for uniqueness.
 	seenIDs := make(map[int]bool, len(phases))
+	listedBy := make(map[string]int)
+	var ranged []Phase
 	for _, p := range phases {
 		if p.ID == 0 {
 			return fmt.Errorf("validating phases: phase has ID 0 (must be positive)")
@@ -273,33 +465,61 @@ func ValidatePhases(phases []Phase) error {
 		}
 		seenIDs[p.ID] = true
 
-		start, err := TaskIDNumber(p.StartTask)
-		if err != nil {
-			return fmt.Errorf("validating phases: phase %d has invalid StartTask: %w", p.ID, err)
+		if !p.HasRange() && len(p.Tasks) == 0 && len(p.Labels) == 0 {
+			return fmt.Errorf("validating phases: phase %d has no task range, tasks or labels", p.ID)
 		}
-		end, err := TaskIDNumber(p.EndTask)
-		if err != nil {
-			return fmt.Errorf("validating phases: phase %d has invalid EndTask: %w", p.ID, err)
+
+		if p.HasRange() {
+			start, err := TaskIDNumber(p.StartTask)
+			if err != nil {
+				return fmt.Errorf("validating phases: phase %d has invalid StartTask: %w", p.ID, err)
+			}
+			end, err := TaskIDNumber(p.EndTask)
+			if err != nil {
+				return fmt.Errorf("validating phases: phase %d has invalid EndTask: %w", p.ID, err)
+			}
+			if _, _, err := phaseBounds(p); err != nil {
+				return fmt.Errorf("validating phases: %w", err)
+			}
+			if start > end {
+				return fmt.Errorf("validating phases: phase %d StartTask %s is after EndTask %s",
+					p.ID, p.StartTask, p.EndTask)
+			}
+			ranged = append(ranged, p)
 		}
-		if start > end {
-			return fmt.Errorf("validating phases: phase %d StartTask %s is after EndTask %s",
-				p.ID, p.StartTask, p.EndTask)
+
+		for _, entry := range p.Tasks {
+			if isTaskGlob(entry) {
+				if _, err := path.Match(entry, ""); err != nil {
+					return fmt.Errorf("validating phases: phase %d has invalid task pattern %q: %w", p.ID, entry, err)
+				}
+				continue
+			}
+			if !IsTaskID(entry) {
+				return fmt.Errorf("validating phases: phase %d lists invalid task ID %q", p.ID, entry)
+			}
+			if other, ok := listedBy[entry]; ok && other != p.ID {
+				return fmt.Errorf("validating phases: task %s is listed by both phase %d and phase %d", entry, other, p.ID)
+			}
+			listedBy[entry] = p.ID
 		}
 	}
 
-	// Build a copy sorted by StartTask number to check for overlaps.
-	sorted := make([]Phase, len(phases))
-	copy(sorted, phases)
+	if err := validatePhaseDependencies(phases, seenIDs); err != nil {
+		return fmt.Errorf("validating phases: %w", err)
+	}
+
+	// Build a copy of the ranged phases sorted by StartTask (prefix, then
+	// number) to check for overlaps.
+	sorted := ranged
 	sort.Slice(sorted, func(i, j int) bool {
-		si, _ := TaskIDNumber(sorted[i].StartTask)
-		sj, _ := TaskIDNumber(sorted[j].StartTask)
-		return si < sj
+		return CompareTaskIDs(sorted[i].StartTask, sorted[j].StartTask) < 0
 	})
 
 	for i := 1; i < len(sorted); i++ {
-		prevEnd, _ := TaskIDNumber(sorted[i-1].EndTask)
-		curStart, _ := TaskIDNumber(sorted[i].StartTask)
-		if curStart <= prevEnd {
+		prevEnd, _ := ParseTaskID(sorted[i-1].EndTask)
+		curStart, _ := ParseTaskID(sorted[i].StartTask)
+		if curStart.Prefix == prevEnd.Prefix && curStart.Number <= prevEnd.Number {
 			return fmt.Errorf(
 				"validating phases: phase %d (%s-%s) overlaps with phase %d (%s-%s)",
 				sorted[i-1].ID, sorted[i-1].StartTask, sorted[i-1].EndTask,
@@ -310,3 +530,55 @@ func ValidatePhases(phases []Phase) error {
 
 	return nil
 }
+
+// validatePhaseDependencies checks that every DependsOn entry names a known
+// phase other than the phase itself and that the dependencies are acyclic.
+func validatePhaseDependencies(phases []Phase, known map[int]bool) error {
+	deps := make(map[int][]int, len(phases))
+	for _, p := range phases {
+		for _, dep := range p.DependsOn {
+			if dep == p.ID {
+				return fmt.Errorf("phase %d depends on itself", p.ID)
+			}
+			if !known[dep] {
+				return fmt.Errorf("phase %d depends on unknown phase %d", p.ID, dep)
+			}
+		}
+		deps[p.ID] = p.DependsOn
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	marks := make(map[int]int, len(phases))
+	var visit func(id int, chain []int) error
+	visit = func(id int, chain []int) error {
+		switch marks[id] {
+		case visiting:
+			cycle := append(chain[slices.Index(chain, id):], id)
+			parts := make([]string, 0, len(cycle))
+			for _, c := range cycle {
+				parts = append(parts, strconv.Itoa(c))
+			}
+			return fmt.Errorf("phase dependency cycle: %s", strings.Join(parts, " -> "))
+		case done:
+			return nil
+		}
+		marks[id] = visiting
+		for _, dep := range deps[id] {
+			if err := visit(dep, append(chain, id)); err != nil {
+				return err
+			}
+		}
+		marks[id] = done
+		return nil
+	}
+	for _, p := range phases {
+		if err := visit(p.ID, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -153,7 +153,7 @@ func TestParsePhaseLine_NonNumericTaskEndInSixField(t *testing.T) {
 func TestLoadPhases_FourFieldFixture(t *testing.T) {
 	t.Parallel()
 
-	phases, err := LoadPhases(fixturePhasespath(t, "valid-4field.conf"))
+	phases, err := LoadPhases(fixturePhasespath(t, "valid-4field.conf"), "")
 	require.NoError(t, err)
 	require.Len(t, phases, 3)
 
@@ -169,7 +169,7 @@ func TestLoadPhases_FourFieldFixture(t *testing.T) {
 func TestLoadPhases_SixFieldFixture(t *testing.T) {
 	t.Parallel()
 
-	phases, err := LoadPhases(fixturePhasespath(t, "valid-6field.conf"))
+	phases, err := LoadPhases(fixturePhasespath(t, "valid-6field.conf"), "")
 	require.NoError(t, err)
 	require.Len(t, phases, 3)
 
@@ -182,7 +182,7 @@ func TestLoadPhases_SixFieldFixture(t *testing.T) {
 func TestLoadPhases_EmptyFile(t *testing.T) {
 	t.Parallel()
 
-	phases, err := LoadPhases(fixturePhasespath(t, "empty.conf"))
+	phases, err := LoadPhases(fixturePhasespath(t, "empty.conf"), "")
 	require.NoError(t, err)
 	assert.Empty(t, phases)
 }
@@ -190,14 +190,14 @@ func TestLoadPhases_EmptyFile(t *testing.T) {
 func TestLoadPhases_NonExistentFile(t *testing.T) {
 	t.Parallel()
 
-	_, err := LoadPhases("/tmp/raven-test-nonexistent-phases.conf")
+	_, err := LoadPhases("/tmp/raven-test-nonexistent-phases.conf", "")
 	assert.Error(t, err)
 }
 
 func TestLoadPhases_SortedByID(t *testing.T) {
 	t.Parallel()
 
-	phases, err := LoadPhases(fixturePhasespath(t, "unordered.conf"))
+	phases, err := LoadPhases(fixturePhasespath(t, "unordered.conf"), "")
 	require.NoError(t, err)
 	require.Len(t, phases, 3)
 
@@ -212,7 +212,7 @@ func TestLoadPhases_SkipsCommentsAndBlanks(t *testing.T) {
 
 	// valid-4field.conf has comment lines and no blank lines; load it and
 	// verify only data lines are returned.
-	phases, err := LoadPhases(fixturePhasespath(t, "valid-4field.conf"))
+	phases, err := LoadPhases(fixturePhasespath(t, "valid-4field.conf"), "")
 	require.NoError(t, err)
 	// The fixture has 3 data lines.
 	assert.Len(t, phases, 3)
@@ -239,7 +239,7 @@ func TestLoadPhases_RealisticMultiPhaseFile(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "phases.conf")
 	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
 
-	phases, err := LoadPhases(path)
+	phases, err := LoadPhases(path, "")
 	require.NoError(t, err)
 	assert.Len(t, phases, 8, "should load all 8 phases")
 
@@ -253,6 +253,26 @@ func TestLoadPhases_RealisticMultiPhaseFile(t *testing.T) {
 	assert.NoError(t, err, "phases should be valid (no overlaps)")
 }
 
+func TestLoadPhases_ConfiguredPrefix(t *testing.T) {
+	t.Parallel()
+
+	content := "1|api|API|0001|0010|x\n2|web|Web|WEB-0001|WEB-0005|x\n"
+	path := filepath.Join(t.TempDir(), "phases.conf")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	phases, err := LoadPhases(path, "API")
+	require.NoError(t, err)
+	require.Len(t, phases, 2)
+	assert.Equal(t, "API-0001", phases[0].StartTask)
+	assert.Equal(t, "API-0010", phases[0].EndTask)
+	// Full task IDs keep their own prefix.
+	assert.Equal(t, "WEB-0001", phases[1].StartTask)
+
+	phases, err = LoadPhases(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "T-0001", phases[0].StartTask)
+}
+
 // ---- PhaseForTask tests -----------------------------------------------------
 
 func TestPhaseForTask_TaskInFirstPhase(t *testing.T) {
@@ -388,7 +408,8 @@ func TestTaskIDNumber_InvalidInputs(t *testing.T) {
 	}{
 		{name: "no prefix", taskID: "invalid"},
 		{name: "empty string", taskID: ""},
-		{name: "wrong prefix", taskID: "X-001"},
+		{name: "lowercase prefix", taskID: "x-001"},
+		{name: "too few digits", taskID: "T-01"},
 		{name: "T- only", taskID: "T-"},
 		{name: "non-numeric suffix", taskID: "T-abc"},
 	}
@@ -510,7 +531,7 @@ func TestValidatePhases_ValidPhases(t *testing.T) {
 func TestValidatePhases_OverlappingRanges(t *testing.T) {
 	t.Parallel()
 
-	phases, err := LoadPhases(fixturePhasespath(t, "overlapping.conf"))
+	phases, err := LoadPhases(fixturePhasespath(t, "overlapping.conf"), "")
 	require.NoError(t, err)
 
 	err = ValidatePhases(phases)
@@ -592,3 +613,241 @@ func samplePhases() []Phase {
 		{ID: 3, Name: "Review Pipeline", StartTask: "T-021", EndTask: "T-030"},
 	}
 }
+
+func TestTasksInPhase_VariableWidthAndPrefix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		phase Phase
+		want  []string
+	}{
+		{
+			name:  "crosses 999",
+			phase: Phase{ID: 1, StartTask: "T-998", EndTask: "T-1001"},
+			want:  []string{"T-998", "T-999", "T-1000", "T-1001"},
+		},
+		{
+			name:  "keeps start padding",
+			phase: Phase{ID: 1, StartTask: "API-0009", EndTask: "API-0011"},
+			want:  []string{"API-0009", "API-0010", "API-0011"},
+		},
+		{
+			name:  "mismatched prefixes",
+			phase: Phase{ID: 1, StartTask: "API-001", EndTask: "T-003"},
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, TasksInPhase(tt.phase))
+		})
+	}
+}
+
+func TestParsePhaseLine_CustomPrefix(t *testing.T) {
+	t.Parallel()
+
+	four, err := ParsePhaseLine("2|API Work|API-0042|API-0050")
+	require.NoError(t, err)
+	assert.Equal(t, "API Work", four.Name)
+	assert.Equal(t, "API-0042", four.StartTask)
+	assert.Equal(t, "API-0050", four.EndTask)
+
+	six, err := ParsePhaseLine("3|api|API Work|API-0051|API-0060|*")
+	require.NoError(t, err)
+	assert.Equal(t, "API-0051", six.StartTask)
+	assert.Equal(t, "API-0060", six.EndTask)
+
+	wide, err := ParsePhaseLine("4|big|Big Phase|0998|1005|*")
+	require.NoError(t, err)
+	assert.Equal(t, "T-0998", wide.StartTask)
+	assert.Equal(t, "T-1005", wide.EndTask)
+}
+
+func TestPhaseForTask_RespectsPrefix(t *testing.T) {
+	t.Parallel()
+
+	phases := []Phase{
+		{ID: 1, Name: "Core", StartTask: "T-001", EndTask: "T-010"},
+		{ID: 2, Name: "API", StartTask: "API-001", EndTask: "API-010"},
+		{ID: 3, Name: "Large", StartTask: "T-999", EndTask: "T-1010"},
+	}
+
+	require.NotNil(t, PhaseForTask(phases, "API-005"))
+	assert.Equal(t, 2, PhaseForTask(phases, "API-005").ID)
+	assert.Equal(t, 1, PhaseForTask(phases, "T-005").ID)
+	assert.Equal(t, 3, PhaseForTask(phases, "T-1005").ID)
+	assert.Nil(t, PhaseForTask(phases, "WEB-005"))
+
+	assert.NoError(t, ValidatePhases(phases), "same numbers under different prefixes do not overlap")
+	assert.Error(t, ValidatePhases([]Phase{{ID: 1, Name: "Mixed", StartTask: "T-001", EndTask: "API-002"}}))
+}
+
+// ---- TOML phases -------------------------------------------------------------
+
+func TestLoadPhases_TOML(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "phases.toml")
+	content := `[[phase]]
+id = 2
+name = "API"
+tasks = ["T-011", "T-05*"]
+labels = ["api"]
+depends_on = [1]
+
+[[phase]]
+id = 1
+name = "Foundation"
+start_task = "T-001"
+end_task = "T-010"
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	phases, err := LoadPhases(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, []Phase{
+		{ID: 1, Name: "Foundation", StartTask: "T-001", EndTask: "T-010"},
+		{ID: 2, Name: "API", Tasks: []string{"T-011", "T-05*"}, Labels: []string{"api"}, DependsOn: []int{1}},
+	}, phases)
+	assert.NoError(t, ValidatePhases(phases))
+}
+
+func TestParsePhasesTOML_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"syntax", "[[phase]\n", "parsing phases TOML"},
+		{"unknown key", "[[phase]]\nid = 1\nname = \"A\"\ntask = [\"T-001\"]\n", `unknown key "phase.task"`},
+		{"empty name", "[[phase]]\nid = 1\ntasks = [\"T-001\"]\n", "phase 1 has empty name"},
+		{"half range", "[[phase]]\nid = 1\nname = \"A\"\nstart_task = \"T-001\"\n", "needs both start_task and end_task"},
+		{"no members", "[[phase]]\nid = 1\nname = \"A\"\n", "no start_task/end_task, tasks or labels"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParsePhasesTOML([]byte(tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestPhaseForTask_Precedence(t *testing.T) {
+	t.Parallel()
+
+	phases := []Phase{
+		{ID: 1, Name: "Range", StartTask: "T-001", EndTask: "T-099"},
+		{ID: 2, Name: "Labels", Labels: []string{"api"}},
+		{ID: 3, Name: "Glob", Tasks: []string{"T-05*"}},
+		{ID: 4, Name: "Explicit", Tasks: []string{"T-055"}},
+	}
+
+	tests := []struct {
+		taskID string
+		labels []string
+		want   int
+	}{
+		{"T-010", nil, 1},
+		{"T-010", []string{"API"}, 2},
+		{"T-051", []string{"api"}, 3},
+		{"T-055", []string{"api"}, 4},
+		{"T-150", nil, 0},
+		{"T-150", []string{"api"}, 2},
+		{"T-150", []string{"ui"}, 0},
+	}
+	for _, tt := range tests {
+		p := phaseForTask(phases, tt.taskID, tt.labels)
+		got := 0
+		if p != nil {
+			got = p.ID
+		}
+		assert.Equal(t, tt.want, got, "%s %v", tt.taskID, tt.labels)
+	}
+
+	// Without labels PhaseForTask still resolves ranges, globs and IDs.
+	require.NotNil(t, PhaseForTask(phases, "T-055"))
+	assert.Equal(t, 4, PhaseForTask(phases, "T-055").ID)
+}
+
+func TestTasksInPhase_ExplicitTasks(t *testing.T) {
+	t.Parallel()
+
+	phase := Phase{ID: 1, StartTask: "T-002", EndTask: "T-003", Tasks: []string{"T-010", "T-002", "T-00*", "T-001"}}
+	assert.Equal(t, []string{"T-001", "T-002", "T-003", "T-010"}, TasksInPhase(phase))
+	assert.Equal(t, []string{"T-007"}, TasksInPhase(Phase{ID: 2, Tasks: []string{"T-007"}, Labels: []string{"api"}}))
+}
+
+func TestValidatePhases_Membership(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		phases []Phase
+		want   string
+	}{
+		{
+			name:   "no members",
+			phases: []Phase{{ID: 1, Name: "Empty"}},
+			want:   "phase 1 has no task range, tasks or labels",
+		},
+		{
+			name:   "bad pattern",
+			phases: []Phase{{ID: 1, Name: "A", Tasks: []string{"T-[0"}}},
+			want:   `invalid task pattern "T-[0"`,
+		},
+		{
+			name:   "bad task ID",
+			phases: []Phase{{ID: 1, Name: "A", Tasks: []string{"setup"}}},
+			want:   `invalid task ID "setup"`,
+		},
+		{
+			name: "listed twice",
+			phases: []Phase{
+				{ID: 1, Name: "A", Tasks: []string{"T-001"}},
+				{ID: 2, Name: "B", Tasks: []string{"T-001"}},
+			},
+			want: "task T-001 is listed by both phase 1 and phase 2",
+		},
+		{
+			name:   "self dependency",
+			phases: []Phase{{ID: 1, Name: "A", Labels: []string{"x"}, DependsOn: []int{1}}},
+			want:   "phase 1 depends on itself",
+		},
+		{
+			name:   "unknown dependency",
+			phases: []Phase{{ID: 1, Name: "A", Labels: []string{"x"}, DependsOn: []int{7}}},
+			want:   "phase 1 depends on unknown phase 7",
+		},
+		{
+			name: "cycle",
+			phases: []Phase{
+				{ID: 1, Name: "A", Labels: []string{"a"}},
+				{ID: 2, Name: "B", Labels: []string{"b"}, DependsOn: []int{3}},
+				{ID: 3, Name: "C", Labels: []string{"c"}, DependsOn: []int{1, 2}},
+			},
+			want: "phase dependency cycle: 2 -> 3 -> 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidatePhases(tt.phases)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+
+	// Ranges only overlap-check against other ranges.
+	assert.NoError(t, ValidatePhases([]Phase{
+		{ID: 1, Name: "A", StartTask: "T-001", EndTask: "T-010"},
+		{ID: 2, Name: "B", Tasks: []string{"T-005"}, DependsOn: []int{1}},
+	}))
+}
@@ -87,17 +87,22 @@ type TaskProgressData struct {
 	Timestamp string
 }
 
-// ProgressGenerator creates PROGRESS.md content from task state and phase data.
-// It is designed to be called after each task state change (e.g., task
-// completion, task blocked) to keep PROGRESS.md in sync with task-state.conf.
-// The implementation loop runner (internal/loop) should hold a reference and
-// invoke WriteFile after handleCompletion updates task state.
+// ProgressGenerator creates PROGRESS.md content from task state and phase data,
+// and the JSON, HTML, and burndown CSV reports next to it (see
+// ProgressFormat). It is designed to be called after each task state change
+// (e.g., task completion, task blocked) to keep the reports in sync with
+// task-state.conf. The implementation loop runner (internal/loop) should hold
+// a reference and invoke WriteFiles after handleCompletion updates task state.
 type ProgressGenerator struct {
 	specs   []*ParsedTaskSpec
 	specMap map[string]*ParsedTaskSpec
 	state   *StateManager
 	phases  []Phase
 	tmpl    *template.Template
+	// formats are written by WriteFiles; empty means markdown only.
+	formats []ProgressFormat
+	// membership resolves which tasks belong to each phase.
+	membership *PhaseMembership
 }
 
 // NewProgressGenerator creates a ProgressGenerator from task system components.
@@ -121,6 +126,8 @@ func NewProgressGenerator(specs []*ParsedTaskSpec, state *StateManager, phases [
 		state:   state,
 		phases:  phases,
 		tmpl:    tmpl,
+
+		membership: NewPhaseMembership(phases, specs),
 	}, nil
 }
 
@@ -205,7 +212,7 @@ func (pg *ProgressGenerator) buildProgressData(projectName string) (ProgressData
 
 // buildPhaseProgressData builds the per-phase progress data for a single phase.
 func (pg *ProgressGenerator) buildPhaseProgressData(phase Phase, stateMap map[string]*TaskState) (PhaseProgressData, error) { //nolint:unparam // error return reserved for future phases
-	ids := TasksInPhase(phase)
+	ids := pg.membership.Tasks(phase.ID)
 
 	phaseData := PhaseProgressData{
 		ID:    phase.ID,
@@ -216,7 +223,7 @@ func (pg *ProgressGenerator) buildPhaseProgressData(phase Phase, stateMap map[st
 	for _, id := range ids {
 		spec, inSpec := pg.specMap[id]
 		if !inSpec {
-			// Task in range but no spec file: treat as not_started, skip row.
+			// Task in phase but no spec file: treat as not_started, skip row.
 			phaseData.NotStarted++
 			continue
 		}
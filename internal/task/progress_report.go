@@ -0,0 +1,611 @@
+package task
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProgressFormat names an output format of ProgressGenerator.
+type ProgressFormat string
+
+// Progress report formats, as accepted by ParseProgressFormat,
+// project.progress_formats, and "raven status --format".
+const (
+	// ProgressFormatMarkdown is the PROGRESS.md document.
+	ProgressFormatMarkdown ProgressFormat = "markdown"
+	// ProgressFormatJSON is the ProgressReport as indented JSON.
+	ProgressFormatJSON ProgressFormat = "json"
+	// ProgressFormatHTML is a self-contained static HTML dashboard.
+	ProgressFormatHTML ProgressFormat = "html"
+	// ProgressFormatBurndown is a CSV of completed and remaining tasks per
+	// day, derived from task completion timestamps.
+	ProgressFormatBurndown ProgressFormat = "burndown"
+)
+
+// progressFormats lists the formats in the order they are documented.
+var progressFormats = []ProgressFormat{
+	ProgressFormatMarkdown,
+	ProgressFormatJSON,
+	ProgressFormatHTML,
+	ProgressFormatBurndown,
+}
+
+// recentActivityLimit is the number of history entries in a report's
+// recent activity list.
+const recentActivityLimit = 15
+
+// ProgressFormats returns all progress report formats.
+func ProgressFormats() []ProgressFormat {
+	out := make([]ProgressFormat, len(progressFormats))
+	copy(out, progressFormats)
+	return out
+}
+
+// ParseProgressFormat returns the ProgressFormat called name.
+func ParseProgressFormat(name string) (ProgressFormat, error) {
+	for _, f := range progressFormats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	names := make([]string, len(progressFormats))
+	for i, f := range progressFormats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown progress format %q: must be one of %s", name, strings.Join(names, ", "))
+}
+
+// ProgressFilePath returns where format is written next to the markdown
+// progress file at markdownPath: the markdown file itself, or a sibling with
+// the same base name, e.g. "PROGRESS.json", "PROGRESS.html", and
+// "PROGRESS-burndown.csv" for "PROGRESS.md".
+func ProgressFilePath(markdownPath string, format ProgressFormat) string {
+	base := strings.TrimSuffix(markdownPath, filepath.Ext(markdownPath))
+	switch format {
+	case ProgressFormatJSON:
+		return base + ".json"
+	case ProgressFormatHTML:
+		return base + ".html"
+	case ProgressFormatBurndown:
+		return base + "-burndown.csv"
+	default:
+		return markdownPath
+	}
+}
+
+// ProgressReport is the machine-readable progress snapshot behind the JSON
+// and HTML formats.
+type ProgressReport struct {
+	// ProjectName is the name of the project.
+	ProjectName string `json:"project_name"`
+	// GeneratedAt is when the report was generated (UTC).
+	GeneratedAt time.Time `json:"generated_at"`
+	// Total is the count of all tasks across all phases.
+	Total int `json:"total"`
+	// Completed is the count of completed tasks across all phases.
+	Completed int `json:"completed"`
+	// Percent is the overall completion percentage (0-100).
+	Percent int `json:"percent"`
+	// Phases contains per-phase progress in phase ID order.
+	Phases []ProgressReportPhase `json:"phases"`
+	// Blocked lists the tasks whose status is blocked.
+	Blocked []ProgressReportTask `json:"blocked"`
+	// RecentActivity holds the latest task state changes from the history
+	// file, newest first.
+	RecentActivity []HistoryEntry `json:"recent_activity"`
+	// Burndown holds one point per day from the first completion to
+	// GeneratedAt.
+	Burndown []BurndownPoint `json:"burndown"`
+	// Graph is the dependency graph of all tasks, or nil when the
+	// dependencies contain a cycle.
+	Graph *TaskGraph `json:"graph,omitempty"`
+}
+
+// ProgressReportPhase is a phase's progress in a ProgressReport.
+type ProgressReportPhase struct {
+	ID         int                  `json:"id"`
+	Name       string               `json:"name"`
+	Total      int                  `json:"total"`
+	Completed  int                  `json:"completed"`
+	InProgress int                  `json:"in_progress"`
+	Blocked    int                  `json:"blocked"`
+	Skipped    int                  `json:"skipped"`
+	NotStarted int                  `json:"not_started"`
+	Percent    int                  `json:"percent"`
+	Tasks      []ProgressReportTask `json:"tasks"`
+}
+
+// ProgressReportTask is a task row in a ProgressReport.
+type ProgressReportTask struct {
+	ID           string     `json:"id"`
+	Title        string     `json:"title"`
+	Status       TaskStatus `json:"status"`
+	Agent        string     `json:"agent,omitempty"`
+	Updated      time.Time  `json:"updated,omitzero"`
+	Dependencies []string   `json:"dependencies"`
+	Notes        string     `json:"notes,omitempty"`
+}
+
+// BurndownPoint is the state of the project at the end of one day.
+type BurndownPoint struct {
+	// Date is the UTC day, formatted "2006-01-02".
+	Date string `json:"date"`
+	// Completed is the number of tasks completed or skipped by the end of
+	// the day.
+	Completed int `json:"completed"`
+	// Remaining is Total minus Completed.
+	Remaining int `json:"remaining"`
+	// Total is the number of tasks in the report.
+	Total int `json:"total"`
+}
+
+// SetFormats sets the formats written by WriteFiles. Without a call, only
+// markdown is written.
+func (pg *ProgressGenerator) SetFormats(formats ...ProgressFormat) {
+	pg.formats = append([]ProgressFormat(nil), formats...)
+}
+
+// Formats returns the formats written by WriteFiles.
+func (pg *ProgressGenerator) Formats() []ProgressFormat {
+	if len(pg.formats) == 0 {
+		return []ProgressFormat{ProgressFormatMarkdown}
+	}
+	return append([]ProgressFormat(nil), pg.formats...)
+}
+
+// Render writes the progress report in format to w.
+func (pg *ProgressGenerator) Render(w io.Writer, format ProgressFormat, projectName string) error {
+	if _, err := ParseProgressFormat(string(format)); err != nil {
+		return err
+	}
+	if format == ProgressFormatMarkdown {
+		return pg.WriteTo(w, projectName)
+	}
+	report, err := pg.Report(projectName)
+	if err != nil {
+		return err
+	}
+	return renderReport(w, format, report)
+}
+
+// WriteFiles writes every format in Formats next to the markdown progress
+// file at path (see ProgressFilePath), each atomically. It stops at the
+// first error.
+func (pg *ProgressGenerator) WriteFiles(path string, projectName string) error {
+	var report *ProgressReport
+	for _, format := range pg.Formats() {
+		if format == ProgressFormatMarkdown {
+			if err := pg.WriteFile(path, projectName); err != nil {
+				return err
+			}
+			continue
+		}
+		if report == nil {
+			var err error
+			if report, err = pg.Report(projectName); err != nil {
+				return err
+			}
+		}
+		var buf bytes.Buffer
+		if err := renderReport(&buf, format, report); err != nil {
+			return err
+		}
+		if err := writeFileAtomic(ProgressFilePath(path, format), buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderReport writes report in one of the non-markdown formats to w.
+func renderReport(w io.Writer, format ProgressFormat, report *ProgressReport) error {
+	switch format {
+	case ProgressFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("rendering progress JSON: %w", err)
+		}
+		return nil
+	case ProgressFormatHTML:
+		return renderProgressHTML(w, report)
+	case ProgressFormatBurndown:
+		return renderBurndownCSV(w, report.Burndown)
+	default:
+		return fmt.Errorf("rendering progress report: unsupported format %q", format)
+	}
+}
+
+// Report builds the ProgressReport for the current task state.
+func (pg *ProgressGenerator) Report(projectName string) (*ProgressReport, error) {
+	data, err := pg.buildProgressData(projectName)
+	if err != nil {
+		return nil, fmt.Errorf("building progress data: %w", err)
+	}
+	stateMap, err := pg.state.LoadMap()
+	if err != nil {
+		return nil, fmt.Errorf("loading task state: %w", err)
+	}
+
+	report := &ProgressReport{
+		ProjectName:    projectName,
+		GeneratedAt:    data.GeneratedAt,
+		Total:          data.OverallTotal,
+		Completed:      data.OverallComplete,
+		Percent:        data.OverallPercent,
+		Phases:         []ProgressReportPhase{},
+		Blocked:        []ProgressReportTask{},
+		RecentActivity: []HistoryEntry{},
+	}
+
+	// Report tasks: those in a phase, or every spec when there are none.
+	var tasks []ProgressReportTask
+	for _, ph := range data.Phases {
+		rp := ProgressReportPhase{
+			ID:         ph.ID,
+			Name:       ph.Name,
+			Total:      ph.Total,
+			Completed:  ph.Completed,
+			InProgress: ph.InProgress,
+			Blocked:    ph.Blocked,
+			Skipped:    ph.Skipped,
+			NotStarted: ph.NotStarted,
+			Percent:    ph.Percent,
+			Tasks:      []ProgressReportTask{},
+		}
+		for _, row := range ph.Tasks {
+			rt := pg.reportTask(row.ID, stateMap)
+			rp.Tasks = append(rp.Tasks, rt)
+			tasks = append(tasks, rt)
+		}
+		report.Phases = append(report.Phases, rp)
+	}
+	if len(pg.phases) == 0 {
+		for _, spec := range pg.specs {
+			tasks = append(tasks, pg.reportTask(spec.ID, stateMap))
+		}
+	}
+
+	for _, t := range tasks {
+		if t.Status == StatusBlocked {
+			report.Blocked = append(report.Blocked, t)
+		}
+	}
+
+	history, err := pg.state.History("")
+	if err != nil {
+		return nil, fmt.Errorf("loading task history: %w", err)
+	}
+	report.Burndown = burndown(tasks, completionTimes(history), report.GeneratedAt)
+	for i := len(history) - 1; i >= 0 && len(report.RecentActivity) < recentActivityLimit; i-- {
+		report.RecentActivity = append(report.RecentActivity, history[i])
+	}
+
+	// A dependency cycle leaves the report without a graph; "raven task
+	// lint" reports the cycle.
+	if g, err := BuildTaskGraph(pg.specs, stateMap, pg.phases, nil); err == nil {
+		report.Graph = g
+	}
+
+	return report, nil
+}
+
+// reportTask builds the report row for the task id, which must have a spec.
+func (pg *ProgressGenerator) reportTask(id string, stateMap map[string]*TaskState) ProgressReportTask {
+	spec := pg.specMap[id]
+	rt := ProgressReportTask{
+		ID:           id,
+		Title:        spec.Title,
+		Status:       StatusNotStarted,
+		Dependencies: append([]string{}, spec.Dependencies...),
+	}
+	if ts, ok := stateMap[id]; ok {
+		rt.Status = ts.Status
+		rt.Agent = ts.Agent
+		rt.Updated = ts.Timestamp
+		rt.Notes = ts.Notes
+	}
+	return rt
+}
+
+// completionTimes returns when each task last became completed or skipped
+// according to history, which must be in chronological order. A task that
+// was later reopened has no entry. Entries that keep the status, such as
+// notes edits, do not move the completion time.
+func completionTimes(history []HistoryEntry) map[string]time.Time {
+	out := make(map[string]time.Time)
+	for _, e := range history {
+		switch {
+		case e.Status != StatusCompleted && e.Status != StatusSkipped:
+			delete(out, e.TaskID)
+		case e.PreviousStatus != e.Status:
+			out[e.TaskID] = e.Timestamp
+		}
+	}
+	return out
+}
+
+// burndown returns one BurndownPoint per UTC day from the earliest
+// completion among tasks to now. A task counts as done on the day it became
+// completed or skipped according to completedAt, taken from the history
+// file. Done tasks missing from completedAt fall back to their state
+// timestamp, and done tasks without either count from the first day.
+// Without any done task the result is a single point for today.
+func burndown(tasks []ProgressReportTask, completedAt map[string]time.Time, now time.Time) []BurndownPoint {
+	const day = 24 * time.Hour
+	today := now.UTC().Truncate(day)
+
+	doneOn := make(map[time.Time]int)
+	undated := 0
+	first := today
+	for _, t := range tasks {
+		if t.Status != StatusCompleted && t.Status != StatusSkipped {
+			continue
+		}
+		at, ok := completedAt[t.ID]
+		if !ok {
+			at = t.Updated
+		}
+		if at.IsZero() {
+			undated++
+			continue
+		}
+		d := at.UTC().Truncate(day)
+		if d.After(today) {
+			d = today
+		}
+		doneOn[d]++
+		if d.Before(first) {
+			first = d
+		}
+	}
+
+	total := len(tasks)
+	points := []BurndownPoint{}
+	done := undated
+	for d := first; !d.After(today); d = d.Add(day) {
+		done += doneOn[d]
+		points = append(points, BurndownPoint{
+			Date:      d.Format("2006-01-02"),
+			Completed: done,
+			Remaining: total - done,
+			Total:     total,
+		})
+	}
+	return points
+}
+
+// renderBurndownCSV writes points as CSV with a header row.
+func renderBurndownCSV(w io.Writer, points []BurndownPoint) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{{"date", "completed", "remaining", "total"}}
+	for _, p := range points {
+		rows = append(rows, []string{
+			p.Date,
+			strconv.Itoa(p.Completed),
+			strconv.Itoa(p.Remaining),
+			strconv.Itoa(p.Total),
+		})
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("rendering burndown CSV: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes content to path via a temporary file and rename,
+// creating parent directories as needed.
+func writeFileAtomic(path string, content []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating directory %q: %w", dir, err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0o644); err != nil {
+		return fmt.Errorf("writing temp file %q: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath) //nolint:errcheck
+		return fmt.Errorf("renaming temp file to %q: %w", path, err)
+	}
+	return nil
+}
+
+// ---- HTML dashboard ----------------------------------------------------------
+
+// Dependency graph layout, in SVG user units.
+const (
+	svgNodeWidth  = 132
+	svgNodeHeight = 30
+	svgColumnGap  = 56
+	svgRowGap     = 14
+	svgMargin     = 10
+)
+
+// svgNode is a positioned graph node in the dashboard's dependency graph.
+type svgNode struct {
+	ID, Title, Fill, Stroke string
+	X, Y                    int
+	Dashed                  bool
+}
+
+// svgEdge is a dependency arrow in the dashboard's dependency graph.
+type svgEdge struct {
+	X1, Y1, X2, Y2 int
+	Stroke         string
+}
+
+// svgGraph is a laid-out dependency graph.
+type svgGraph struct {
+	Width, Height         int
+	NodeWidth, NodeHeight int
+	Nodes                 []svgNode
+	Edges                 []svgEdge
+}
+
+// layoutGraph places each node in the column given by the length of its
+// longest dependency chain, so that every edge points right. Nodes within a
+// column are ordered by task ID.
+func layoutGraph(g *TaskGraph) *svgGraph {
+	if g == nil || len(g.Nodes) == 0 {
+		return nil
+	}
+	index := make(map[string]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		index[n.ID] = i
+	}
+	depth := make([]int, len(g.Nodes))
+	computed := make([]bool, len(g.Nodes))
+	var depthOf func(i int) int
+	depthOf = func(i int) int {
+		if computed[i] {
+			return depth[i]
+		}
+		d := 0
+		for _, dep := range g.Nodes[i].Dependencies {
+			if j, ok := index[dep]; ok {
+				d = max(d, depthOf(j)+1)
+			}
+		}
+		depth[i], computed[i] = d, true
+		return d
+	}
+
+	rows := make(map[int]int)
+	out := &svgGraph{NodeWidth: svgNodeWidth, NodeHeight: svgNodeHeight}
+	pos := make([][2]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		col := depthOf(i)
+		row := rows[col]
+		rows[col]++
+		x := svgMargin + col*(svgNodeWidth+svgColumnGap)
+		y := svgMargin + row*(svgNodeHeight+svgRowGap)
+		pos[i] = [2]int{x, y}
+		stroke := "#555555"
+		if n.Critical {
+			stroke = graphCriticalColor
+		}
+		out.Nodes = append(out.Nodes, svgNode{
+			ID:     n.ID,
+			Title:  n.Title,
+			Fill:   statusColor(n.Status),
+			Stroke: stroke,
+			X:      x,
+			Y:      y,
+			Dashed: n.External,
+		})
+		out.Width = max(out.Width, x+svgNodeWidth+svgMargin)
+		out.Height = max(out.Height, y+svgNodeHeight+svgMargin)
+	}
+	for _, e := range g.Edges {
+		from, to := pos[index[e.From]], pos[index[e.To]]
+		stroke := "#999999"
+		if g.criticalEdge(e) {
+			stroke = graphCriticalColor
+		}
+		out.Edges = append(out.Edges, svgEdge{
+			X1:     from[0] + svgNodeWidth,
+			Y1:     from[1] + svgNodeHeight/2,
+			X2:     to[0],
+			Y2:     to[1] + svgNodeHeight/2,
+			Stroke: stroke,
+		})
+	}
+	return out
+}
+
+// progressHTMLTemplate is the self-contained dashboard: inline CSS, no
+// scripts, and no external resources.
+const progressHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Progress -- {{.Report.ProjectName}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #222; }
+h1 { margin-bottom: 0.2rem; }
+.meta { color: #666; margin-top: 0; }
+.bar { background: #eee; border-radius: 4px; height: 14px; width: 100%; max-width: 480px; overflow: hidden; }
+.bar > span { display: block; height: 100%; background: #4caf50; }
+table { border-collapse: collapse; margin: 0.5rem 0 1.5rem; }
+th, td { border-bottom: 1px solid #ddd; padding: 4px 10px; text-align: left; font-size: 0.9rem; }
+.status { padding: 1px 6px; border-radius: 3px; border: 1px solid #ccc; }
+.phase { margin-bottom: 1rem; }
+.graph { overflow-x: auto; border: 1px solid #eee; padding: 4px; }
+.empty { color: #666; }
+</style>
+</head>
+<body>
+<h1>Progress -- {{.Report.ProjectName}}</h1>
+<p class="meta">Generated by Raven at {{.Report.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</p>
+
+<h2>Overall</h2>
+<p><strong>{{.Report.Completed}}/{{.Report.Total}} tasks completed ({{.Report.Percent}}%)</strong></p>
+<div class="bar"><span style="width: {{.Report.Percent}}%"></span></div>
+
+<h2>Phases</h2>
+{{range .Report.Phases}}<div class="phase">
+<div>Phase {{.ID}}: {{.Name}} -- {{.Completed}}/{{.Total}} ({{.Percent}}%){{if .InProgress}}, {{.InProgress}} in progress{{end}}{{if .Blocked}}, {{.Blocked}} blocked{{end}}</div>
+<div class="bar"><span style="width: {{.Percent}}%"></span></div>
+</div>
+{{else}}<p class="empty">No phases configured.</p>
+{{end}}
+<h2>Blocked</h2>
+{{if .Report.Blocked}}<table>
+<tr><th>Task</th><th>Title</th><th>Notes</th></tr>
+{{range .Report.Blocked}}<tr><td>{{.ID}}</td><td>{{.Title}}</td><td>{{.Notes}}</td></tr>
+{{end}}</table>
+{{else}}<p class="empty">No blocked tasks.</p>
+{{end}}
+<h2>Dependency Graph</h2>
+{{with .Graph}}<div class="graph">
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}" font-size="11">
+<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#999999"/></marker></defs>
+{{range .Edges}}<line x1="{{.X1}}" y1="{{.Y1}}" x2="{{.X2}}" y2="{{.Y2}}" stroke="{{.Stroke}}" marker-end="url(#arrow)"/>
+{{end}}{{range .Nodes}}<g><title>{{.ID}}{{if .Title}}: {{.Title}}{{end}}</title><rect x="{{.X}}" y="{{.Y}}" width="{{$.Graph.NodeWidth}}" height="{{$.Graph.NodeHeight}}" rx="4" fill="{{.Fill}}" stroke="{{.Stroke}}"{{if .Dashed}} stroke-dasharray="4 2"{{end}}/><text x="{{.X}}" y="{{.Y}}" dx="6" dy="19">{{.ID}}</text></g>
+{{end}}</svg>
+</div>
+{{else}}<p class="empty">No dependency graph (no tasks, or the dependencies contain a cycle).</p>
+{{end}}
+<h2>Recent Activity</h2>
+{{if .Report.RecentActivity}}<table>
+<tr><th>When</th><th>Task</th><th>Change</th><th>Agent</th></tr>
+{{range .Report.RecentActivity}}<tr><td>{{.Timestamp.Format "2006-01-02 15:04"}}</td><td>{{.TaskID}}</td><td>{{if .PreviousStatus}}{{.PreviousStatus}} &rarr; {{end}}{{.Status}}</td><td>{{.Agent}}</td></tr>
+{{end}}</table>
+{{else}}<p class="empty">No recorded activity.</p>
+{{end}}
+<h2>Tasks</h2>
+{{range .Report.Phases}}<h3>Phase {{.ID}}: {{.Name}}</h3>
+<table>
+<tr><th>Task</th><th>Title</th><th>Status</th><th>Agent</th><th>Updated</th></tr>
+{{range .Tasks}}<tr><td>{{.ID}}</td><td>{{.Title}}</td><td><span class="status" style="background: {{statusColor .Status}}">{{.Status}}</span></td><td>{{.Agent}}</td><td>{{if not .Updated.IsZero}}{{.Updated.Format "2006-01-02"}}{{end}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`
+
+// progressHTML is the parsed dashboard template.
+var progressHTML = htmltemplate.Must(htmltemplate.New("progress-html").Funcs(htmltemplate.FuncMap{
+	"statusColor": func(s TaskStatus) htmltemplate.CSS { return htmltemplate.CSS(statusColor(s)) },
+}).Parse(progressHTMLTemplate))
+
+// renderProgressHTML writes the HTML dashboard for report to w.
+func renderProgressHTML(w io.Writer, report *ProgressReport) error {
+	data := struct {
+		Report *ProgressReport
+		Graph  *svgGraph
+	}{Report: report, Graph: layoutGraph(report.Graph)}
+	if err := progressHTML.Execute(w, data); err != nil {
+		return fmt.Errorf("rendering progress HTML: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package task
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reportFixture returns a generator over three tasks in one phase: T-001
+// completed, T-002 blocked (depends on T-001), T-003 not started (depends on
+// T-002). The state changes are made through the manager so that they are
+// recorded in the history file.
+func reportFixture(t *testing.T) *ProgressGenerator {
+	t.Helper()
+	specs := []*ParsedTaskSpec{
+		makeSpec("T-001", nil),
+		makeSpec("T-002", []string{"T-001"}),
+		makeSpec("T-003", []string{"T-002"}),
+	}
+	specs[1].Title = "Core <b>API</b>"
+	sm := NewStateManager(filepath.Join(t.TempDir(), "task-state.conf"))
+	require.NoError(t, sm.UpdateStatus("T-001", StatusCompleted, "claude"))
+	require.NoError(t, sm.Update(TaskState{
+		TaskID:    "T-002",
+		Status:    StatusBlocked,
+		Agent:     "codex",
+		Timestamp: time.Now().UTC(),
+		Notes:     "waiting on keys",
+	}))
+	pg, err := NewProgressGenerator(specs, sm, []Phase{{ID: 1, Name: "Foundation", StartTask: "T-001", EndTask: "T-003"}})
+	require.NoError(t, err)
+	return pg
+}
+
+func TestParseProgressFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range ProgressFormats() {
+		got, err := ParseProgressFormat(string(f))
+		require.NoError(t, err)
+		assert.Equal(t, f, got)
+	}
+
+	_, err := ParseProgressFormat("pdf")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "markdown, json, html, burndown")
+}
+
+func TestProgressFilePath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format ProgressFormat
+		want   string
+	}{
+		{ProgressFormatMarkdown, "docs/tasks/PROGRESS.md"},
+		{ProgressFormatJSON, "docs/tasks/PROGRESS.json"},
+		{ProgressFormatHTML, "docs/tasks/PROGRESS.html"},
+		{ProgressFormatBurndown, "docs/tasks/PROGRESS-burndown.csv"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, ProgressFilePath("docs/tasks/PROGRESS.md", tt.format))
+		})
+	}
+}
+
+func TestProgressGenerator_Report(t *testing.T) {
+	t.Parallel()
+
+	report, err := reportFixture(t).Report("demo")
+	require.NoError(t, err)
+
+	assert.Equal(t, "demo", report.ProjectName)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 1, report.Completed)
+	assert.Equal(t, 33, report.Percent)
+
+	require.Len(t, report.Phases, 1)
+	phase := report.Phases[0]
+	assert.Equal(t, "Foundation", phase.Name)
+	assert.Equal(t, 1, phase.Blocked)
+	require.Len(t, phase.Tasks, 3)
+	assert.Equal(t, "Core <b>API</b>", phase.Tasks[1].Title, "titles are not escaped")
+	assert.Equal(t, []string{"T-001"}, phase.Tasks[1].Dependencies)
+
+	require.Len(t, report.Blocked, 1)
+	assert.Equal(t, "T-002", report.Blocked[0].ID)
+	assert.Equal(t, "waiting on keys", report.Blocked[0].Notes)
+
+	require.Len(t, report.RecentActivity, 2)
+	assert.Equal(t, "T-002", report.RecentActivity[0].TaskID, "newest first")
+
+	require.NotEmpty(t, report.Burndown)
+	last := report.Burndown[len(report.Burndown)-1]
+	assert.Equal(t, BurndownPoint{Date: report.GeneratedAt.Format("2006-01-02"), Completed: 1, Remaining: 2, Total: 3}, last)
+
+	require.NotNil(t, report.Graph)
+	assert.Len(t, report.Graph.Edges, 2)
+}
+
+func TestProgressGenerator_Report_NoPhases(t *testing.T) {
+	t.Parallel()
+
+	specs := []*ParsedTaskSpec{makeSpec("T-001", nil), makeSpec("T-002", nil)}
+	pg, err := NewProgressGenerator(specs, emptyStateManager(t), nil)
+	require.NoError(t, err)
+
+	report, err := pg.Report("demo")
+	require.NoError(t, err)
+	assert.Empty(t, report.Phases)
+	require.Len(t, report.Burndown, 1)
+	assert.Equal(t, 2, report.Burndown[0].Remaining)
+}
+
+func TestBurndown(t *testing.T) {
+	t.Parallel()
+
+	now := mustParseRFC3339("2026-03-04T15:00:00Z")
+	day := func(s string) time.Time { return mustParseRFC3339(s + "T10:00:00Z") }
+	tasks := []ProgressReportTask{
+		{ID: "T-001", Status: StatusCompleted, Updated: day("2026-03-01")},
+		{ID: "T-002", Status: StatusSkipped, Updated: day("2026-03-03")},
+		{ID: "T-003", Status: StatusCompleted, Updated: day("2026-03-03")},
+		{ID: "T-004", Status: StatusCompleted},
+		{ID: "T-005", Status: StatusInProgress, Updated: day("2026-03-02")},
+		{ID: "T-006", Status: StatusNotStarted},
+	}
+
+	got := burndown(tasks, nil, now)
+	want := []BurndownPoint{
+		{Date: "2026-03-01", Completed: 2, Remaining: 4, Total: 6},
+		{Date: "2026-03-02", Completed: 2, Remaining: 4, Total: 6},
+		{Date: "2026-03-03", Completed: 4, Remaining: 2, Total: 6},
+		{Date: "2026-03-04", Completed: 4, Remaining: 2, Total: 6},
+	}
+	assert.Equal(t, want, got)
+
+	// Nothing done: a single point for today.
+	assert.Equal(t, []BurndownPoint{{Date: "2026-03-04", Completed: 0, Remaining: 1, Total: 1}},
+		burndown([]ProgressReportTask{{ID: "T-001", Status: StatusNotStarted}}, nil, now))
+}
+
+func TestCompletionTimes(t *testing.T) {
+	t.Parallel()
+
+	at := func(s string) time.Time { return mustParseRFC3339(s) }
+	history := []HistoryEntry{
+		{Timestamp: at("2026-03-01T10:00:00Z"), TaskID: "T-001", Status: StatusInProgress},
+		{Timestamp: at("2026-03-02T10:00:00Z"), TaskID: "T-001", PreviousStatus: StatusInProgress, Status: StatusCompleted},
+		// A notes edit keeps the completion time.
+		{Timestamp: at("2026-03-05T10:00:00Z"), TaskID: "T-001", PreviousStatus: StatusCompleted, Status: StatusCompleted, Notes: "done"},
+		{Timestamp: at("2026-03-02T11:00:00Z"), TaskID: "T-002", Status: StatusSkipped},
+		// T-003 was completed and then reopened.
+		{Timestamp: at("2026-03-03T10:00:00Z"), TaskID: "T-003", Status: StatusCompleted},
+		{Timestamp: at("2026-03-04T10:00:00Z"), TaskID: "T-003", PreviousStatus: StatusCompleted, Status: StatusNotStarted},
+	}
+
+	assert.Equal(t, map[string]time.Time{
+		"T-001": at("2026-03-02T10:00:00Z"),
+		"T-002": at("2026-03-02T11:00:00Z"),
+	}, completionTimes(history))
+}
+
+func TestProgressGenerator_Report_BurndownIgnoresNotesEdits(t *testing.T) {
+	t.Parallel()
+
+	specs := []*ParsedTaskSpec{makeSpec("T-001", nil), makeSpec("T-002", nil)}
+	sm := NewStateManager(filepath.Join(t.TempDir(), "task-state.conf"))
+	completed := time.Now().UTC().Add(-48 * time.Hour)
+	require.NoError(t, sm.Update(TaskState{TaskID: "T-001", Status: StatusCompleted, Agent: "claude", Timestamp: completed}))
+	require.NoError(t, sm.UpdateNotes("T-001", "follow-up filed"))
+
+	pg, err := NewProgressGenerator(specs, sm, nil)
+	require.NoError(t, err)
+	report, err := pg.Report("demo")
+	require.NoError(t, err)
+
+	require.Len(t, report.Burndown, 3)
+	assert.Equal(t, BurndownPoint{Date: completed.Format("2006-01-02"), Completed: 1, Remaining: 1, Total: 2}, report.Burndown[0])
+}
+
+func TestProgressGenerator_Render(t *testing.T) {
+	t.Parallel()
+
+	pg := reportFixture(t)
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, pg.Render(&buf, ProgressFormatJSON, "demo"))
+		var report ProgressReport
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+		assert.Equal(t, 3, report.Total)
+		assert.Len(t, report.Blocked, 1)
+	})
+
+	t.Run("html", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, pg.Render(&buf, ProgressFormatHTML, "demo"))
+		html := buf.String()
+		assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+		assert.Contains(t, html, "Phase 1: Foundation")
+		assert.Contains(t, html, "waiting on keys")
+		assert.Contains(t, html, "<svg")
+		assert.Contains(t, html, "Core &lt;b&gt;API&lt;/b&gt;")
+		assert.NotContains(t, html, "<b>API</b>")
+		assert.NotContains(t, html, "<script")
+		assert.NotContains(t, strings.ReplaceAll(html, `xmlns="http://www.w3.org/2000/svg"`, ""), "http",
+			"no external resources")
+	})
+
+	t.Run("burndown", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, pg.Render(&buf, ProgressFormatBurndown, "demo"))
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, len(rows), 2)
+		assert.Equal(t, []string{"date", "completed", "remaining", "total"}, rows[0])
+		assert.Equal(t, []string{"1", "2", "3"}, rows[len(rows)-1][1:])
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, pg.Render(&buf, ProgressFormatMarkdown, "demo"))
+		assert.Contains(t, buf.String(), "# Progress -- demo")
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		require.Error(t, pg.Render(&bytes.Buffer{}, "pdf", "demo"))
+	})
+}
+
+func TestProgressGenerator_HTMLWithoutGraph(t *testing.T) {
+	t.Parallel()
+
+	specs := []*ParsedTaskSpec{
+		makeSpec("T-001", []string{"T-002"}),
+		makeSpec("T-002", []string{"T-001"}),
+	}
+	pg, err := NewProgressGenerator(specs, emptyStateManager(t), nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, pg.Render(&buf, ProgressFormatHTML, "demo"))
+	assert.Contains(t, buf.String(), "No dependency graph")
+}
+
+func TestProgressGenerator_WriteFiles(t *testing.T) {
+	t.Parallel()
+
+	pg := reportFixture(t)
+	path := filepath.Join(t.TempDir(), "docs", "PROGRESS.md")
+
+	// By default only the markdown file is written.
+	require.NoError(t, pg.WriteFiles(path, "demo"))
+	_, err := os.Stat(path)
+	require.NoError(t, err)
+	_, err = os.Stat(ProgressFilePath(path, ProgressFormatJSON))
+	assert.True(t, os.IsNotExist(err))
+
+	pg.SetFormats(ProgressFormats()...)
+	require.NoError(t, pg.WriteFiles(path, "demo"))
+	for _, f := range ProgressFormats() {
+		info, err := os.Stat(ProgressFilePath(path, f))
+		require.NoError(t, err, f)
+		assert.Positive(t, info.Size(), f)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 4, "no temp files left behind")
+}
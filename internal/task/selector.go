@@ -1,17 +1,23 @@
 package task
 
 import (
+	"errors"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/charmbracelet/log"
 )
 
+// ErrPhaseNotReady is returned by TaskSelector.Select when a phase the
+// requested phase depends on is not complete yet.
+var ErrPhaseNotReady = errors.New("phase dependencies are not complete")
+
 // PhaseProgress holds aggregate task counts for a single phase.
 type PhaseProgress struct {
 	// PhaseID is the numeric identifier of the phase.
 	PhaseID int
-	// Total is the number of task IDs that fall within the phase range.
+	// Total is the number of task IDs that belong to the phase.
 	Total int
 	// Completed is the count of tasks with StatusCompleted.
 	Completed int
@@ -33,31 +39,57 @@ type TaskSelector struct {
 	state   *StateManager
 	phases  []Phase
 	specMap map[string]*ParsedTaskSpec
+	// membership resolves which tasks belong to each phase.
+	membership *PhaseMembership
+	// strategy picks among ready tasks; nil means lowest task ID first.
+	strategy SelectionStrategy
 }
 
 // NewTaskSelector constructs a TaskSelector. specs is the complete list of
 // parsed task specs; state is the StateManager used to query task status;
 // phases is the slice of Phase configurations used to enumerate phase task IDs.
 //
-// A specMap is built from specs for O(1) lookup by task ID.
+// A specMap is built from specs for O(1) lookup by task ID, and a
+// PhaseMembership resolves each phase's tasks from its range, task list and
+// labels.
 func NewTaskSelector(specs []*ParsedTaskSpec, state *StateManager, phases []Phase) *TaskSelector {
 	specMap := make(map[string]*ParsedTaskSpec, len(specs))
 	for _, s := range specs {
 		specMap[s.ID] = s
 	}
 	return &TaskSelector{
-		specs:   specs,
-		state:   state,
-		phases:  phases,
-		specMap: specMap,
+		specs:      specs,
+		state:      state,
+		phases:     phases,
+		specMap:    specMap,
+		membership: NewPhaseMembership(phases, specs),
 	}
 }
 
-// SelectNext returns the first task in phaseID that is not_started and has all
-// dependencies completed. Tasks are evaluated in the order returned by
-// TasksInPhase (numeric ascending). Returns nil, nil when no task is currently
-// actionable (all done, all blocked, or phase is empty / not found).
+// Membership returns the phase membership the selector enumerates phase
+// tasks from.
+func (s *TaskSelector) Membership() *PhaseMembership {
+	return s.membership
+}
+
+// SelectNext returns the task in phaseID chosen by the selector's strategy
+// among those that are not_started (or in_progress under an expired lease),
+// are not claimed by another process, and have all dependencies completed. With
+// the default strategy this is the lowest such task ID. Returns nil, nil when
+// no task is currently actionable (all done, all blocked, or phase is empty).
 func (s *TaskSelector) SelectNext(phaseID int) (*ParsedTaskSpec, error) {
+	sel, err := s.Select(phaseID)
+	if err != nil || sel == nil {
+		return nil, err
+	}
+	return sel.Spec, nil
+}
+
+// Select is SelectNext with the strategy's reasoning. Returns nil, nil when
+// no task in phaseID is currently actionable. Returns an error wrapping
+// ErrPhaseNotReady while a phase listed in the phase's DependsOn is
+// incomplete.
+func (s *TaskSelector) Select(phaseID int) (*Selection, error) {
 	phase := PhaseByID(s.phases, phaseID)
 	if phase == nil {
 		return nil, fmt.Errorf("selecting next task: phase %d not found", phaseID)
@@ -69,52 +101,53 @@ func (s *TaskSelector) SelectNext(phaseID int) (*ParsedTaskSpec, error) {
 		return nil, fmt.Errorf("selecting next task in phase %d: loading state: %w", phaseID, err)
 	}
 
-	ids := TasksInPhase(*phase)
-	for _, id := range ids {
-		spec, ok := s.specMap[id]
-		if !ok {
-			// Task ID is in the phase range but has no spec file -- skip it.
-			log.Debug("task has no spec, skipping", "task", id, "phase", phaseID)
-			continue
-		}
-
-		// Missing state entry is treated as not_started.
-		status := StatusNotStarted
-		if ts, exists := stateMap[id]; exists {
-			status = ts.Status
-		}
-
-		if status != StatusNotStarted {
-			continue
-		}
-
-		met := areDependenciesMetFromMap(spec, stateMap)
-		if !met {
-			log.Debug("task dependencies not met, skipping", "task", id)
-			continue
+	for _, depID := range phase.DependsOn {
+		if !s.phaseCompleteFromMap(depID, stateMap) {
+			dep := PhaseByID(s.phases, depID)
+			name := ""
+			if dep != nil {
+				name = " (" + dep.Name + ")"
+			}
+			return nil, fmt.Errorf("selecting next task: phase %d depends on phase %d%s: %w",
+				phaseID, depID, name, ErrPhaseNotReady)
 		}
-
-		log.Debug("selected next task", "task", id, "phase", phaseID)
-		return spec, nil
 	}
 
-	return nil, nil
+	sel := s.choose(s.membership.Tasks(phaseID), stateMap)
+	if sel != nil {
+		log.Debug("selected next task", "task", sel.Spec.ID, "phase", phaseID,
+			"strategy", sel.Strategy, "reason", sel.Reason)
+	}
+	return sel, nil
 }
 
-// SelectNextInRange returns the first task whose numeric ID falls within the
-// inclusive range [startTask, endTask] that is not_started and has all
-// dependencies completed. This is used by --phase all mode spanning multiple
-// phases. Returns nil, nil when no actionable task exists in the range.
+// SelectNextInRange returns the task chosen by the selector's strategy among
+// those whose numeric ID falls within the inclusive range [startTask,
+// endTask] that are not_started and have all dependencies completed. This is
+// used by --phase all mode spanning multiple phases. Returns nil, nil when no
+// actionable task exists in the range.
 func (s *TaskSelector) SelectNextInRange(startTask, endTask string) (*ParsedTaskSpec, error) {
-	startNum, err := TaskIDNumber(startTask)
+	sel, err := s.SelectInRange(startTask, endTask)
+	if err != nil || sel == nil {
+		return nil, err
+	}
+	return sel.Spec, nil
+}
+
+// SelectInRange is SelectNextInRange with the strategy's reasoning.
+func (s *TaskSelector) SelectInRange(startTask, endTask string) (*Selection, error) {
+	start, err := ParseTaskID(startTask)
 	if err != nil {
 		return nil, fmt.Errorf("selecting next task in range: invalid start task %q: %w", startTask, err)
 	}
-	endNum, err := TaskIDNumber(endTask)
+	end, err := ParseTaskID(endTask)
 	if err != nil {
 		return nil, fmt.Errorf("selecting next task in range: invalid end task %q: %w", endTask, err)
 	}
-	if startNum > endNum {
+	if start.Prefix != end.Prefix {
+		return nil, fmt.Errorf("selecting next task in range: start task %q and end task %q use different prefixes", startTask, endTask)
+	}
+	if start.Number > end.Number {
 		return nil, fmt.Errorf("selecting next task in range: start task %q is after end task %q", startTask, endTask)
 	}
 
@@ -124,31 +157,77 @@ func (s *TaskSelector) SelectNextInRange(startTask, endTask string) (*ParsedTask
 		return nil, fmt.Errorf("selecting next task in range [%s,%s]: loading state: %w", startTask, endTask, err)
 	}
 
-	for i := startNum; i <= endNum; i++ {
-		id := fmt.Sprintf("T-%03d", i)
+	ids := make([]string, 0, end.Number-start.Number+1)
+	for i := start.Number; i <= end.Number; i++ {
+		ids = append(ids, FormatTaskID(start.Prefix, i, start.Digits))
+	}
+	sel := s.choose(ids, stateMap)
+	if sel != nil {
+		log.Debug("selected next task in range", "task", sel.Spec.ID, "start", startTask, "end", endTask,
+			"strategy", sel.Strategy, "reason", sel.Reason)
+	}
+	return sel, nil
+}
+
+// choose collects the ready tasks among ids (in order) and lets the
+// strategy pick one. Returns nil when none is ready.
+func (s *TaskSelector) choose(ids []string, stateMap map[string]*TaskState) *Selection {
+	now := time.Now()
+	var candidates []*ParsedTaskSpec
+	for _, id := range ids {
 		spec, ok := s.specMap[id]
 		if !ok {
+			// Task ID is in the range but has no spec file -- skip it.
+			log.Debug("task has no spec, skipping", "task", id)
 			continue
 		}
 
-		status := StatusNotStarted
+		// Missing state entry is treated as not_started. A task another
+		// process holds an active lease on is skipped; an in_progress task
+		// whose lease expired was abandoned and is ready again.
 		if ts, exists := stateMap[id]; exists {
-			status = ts.Status
-		}
-
-		if status != StatusNotStarted {
-			continue
+			if ts.LeaseActive(now) {
+				log.Debug("task is claimed, skipping", "task", id, "claimed_by", ts.ClaimedBy)
+				continue
+			}
+			reclaimable := ts.Status == StatusInProgress && ts.LeaseExpired(now)
+			if ts.Status != StatusNotStarted && !reclaimable {
+				continue
+			}
 		}
 
 		if !areDependenciesMetFromMap(spec, stateMap) {
+			log.Debug("task dependencies not met, skipping", "task", id)
 			continue
 		}
+		candidates = append(candidates, spec)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
 
-		log.Debug("selected next task in range", "task", id, "start", startTask, "end", endTask)
-		return spec, nil
+	strategy := s.Strategy()
+	spec, reason := strategy.Select(candidates, SelectionContext{Specs: s.specMap, State: stateMap})
+	return &Selection{
+		Spec:       spec,
+		Strategy:   strategy.Name(),
+		Reason:     reason,
+		Candidates: len(candidates),
 	}
+}
+
+// SetStrategy sets the strategy used by SelectNext and SelectNextInRange. A
+// nil strategy restores the default (lowest task ID first).
+func (s *TaskSelector) SetStrategy(strategy SelectionStrategy) {
+	s.strategy = strategy
+}
 
-	return nil, nil
+// Strategy returns the selector's strategy.
+func (s *TaskSelector) Strategy() SelectionStrategy {
+	if s.strategy == nil {
+		return idStrategy{}
+	}
+	return s.strategy
 }
 
 // SelectByID returns the ParsedTaskSpec for the given task ID. Returns an
@@ -161,6 +240,19 @@ func (s *TaskSelector) SelectByID(taskID string) (*ParsedTaskSpec, error) {
 	return spec, nil
 }
 
+// TasksWithLabel returns the specs whose front matter labels include label
+// (case-insensitive), in the order the specs were supplied. Returns an empty
+// slice when no task carries the label.
+func (s *TaskSelector) TasksWithLabel(label string) []*ParsedTaskSpec {
+	result := make([]*ParsedTaskSpec, 0)
+	for _, spec := range s.specs {
+		if spec.HasLabel(label) {
+			result = append(result, spec)
+		}
+	}
+	return result
+}
+
 // GetPhaseProgress returns aggregate status counts for the given phaseID.
 // An error is returned if the phase is not found or state cannot be queried.
 func (s *TaskSelector) GetPhaseProgress(phaseID int) (PhaseProgress, error) {
@@ -198,16 +290,21 @@ func (s *TaskSelector) IsPhaseComplete(phaseID int) (bool, error) {
 		return false, fmt.Errorf("checking phase completion: phase %d not found", phaseID)
 	}
 
-	ids := TasksInPhase(*phase)
 	stateMap, err := s.state.LoadMap()
 	if err != nil {
 		return false, fmt.Errorf("checking phase completion for phase %d: %w", phaseID, err)
 	}
 
-	for _, id := range ids {
+	return s.phaseCompleteFromMap(phaseID, stateMap), nil
+}
+
+// phaseCompleteFromMap reports whether every task in phaseID with a spec is
+// completed or skipped according to the stateMap snapshot.
+func (s *TaskSelector) phaseCompleteFromMap(phaseID int, stateMap map[string]*TaskState) bool {
+	for _, id := range s.membership.Tasks(phaseID) {
 		if _, inSpec := s.specMap[id]; !inSpec {
-			// No spec means this task ID is unmanaged -- treat as not-started.
-			// A phase cannot be complete if unmanaged task IDs exist.
+			// No spec means this task ID is unmanaged -- there is nothing
+			// to run, so it does not hold up completion.
 			continue
 		}
 
@@ -219,11 +316,10 @@ func (s *TaskSelector) IsPhaseComplete(phaseID int) (bool, error) {
 
 		// Only completed and skipped count as "done" for phase completion.
 		if status != StatusCompleted && status != StatusSkipped {
-			return false, nil
+			return false
 		}
 	}
-
-	return true, nil
+	return true
 }
 
 // BlockedTasks returns specs for tasks in phaseID that are not_started but
@@ -239,7 +335,7 @@ func (s *TaskSelector) BlockedTasks(phaseID int) ([]*ParsedTaskSpec, error) {
 		return nil, fmt.Errorf("listing blocked tasks in phase %d: loading state: %w", phaseID, err)
 	}
 
-	ids := TasksInPhase(*phase)
+	ids := s.membership.Tasks(phaseID)
 	var blocked []*ParsedTaskSpec
 
 	for _, id := range ids {
@@ -293,7 +389,7 @@ func (s *TaskSelector) RemainingTaskIDs(phaseID int) ([]string, error) {
 		return nil, fmt.Errorf("listing remaining tasks: phase %d not found", phaseID)
 	}
 
-	ids := TasksInPhase(*phase)
+	ids := s.membership.Tasks(phaseID)
 	stateMap, err := s.state.LoadMap()
 	if err != nil {
 		return nil, fmt.Errorf("listing remaining tasks in phase %d: %w", phaseID, err)
@@ -352,9 +448,16 @@ func areDependenciesMetFromMap(spec *ParsedTaskSpec, stateMap map[string]*TaskSt
 	return true
 }
 
+// DependenciesMet reports whether spec's dependencies are satisfied in the
+// stateMap snapshot by the selector's rule: every dependency is completed.
+// Commands that show whether a task is ready use it to agree with selection.
+func DependenciesMet(spec *ParsedTaskSpec, stateMap map[string]*TaskState) bool {
+	return areDependenciesMetFromMap(spec, stateMap)
+}
+
 // phaseProgressFor computes aggregate counts for a single Phase.
 func (s *TaskSelector) phaseProgressFor(phase Phase) (PhaseProgress, error) {
-	ids := TasksInPhase(phase)
+	ids := s.membership.Tasks(phase.ID)
 	prog := PhaseProgress{PhaseID: phase.ID, Total: len(ids)}
 
 	stateMap, err := s.state.LoadMap()
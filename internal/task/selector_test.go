@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -221,6 +222,52 @@ func TestSelectNext_InProgressNotSelected(t *testing.T) {
 	assert.Equal(t, "T-002", got.ID)
 }
 
+func TestSelectNext_Leases(t *testing.T) {
+	t.Parallel()
+
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name  string
+		state []string
+		want  string
+	}{
+		{
+			name:  "actively leased task is skipped",
+			state: []string{"T-001|in_progress|claude||started|ci:42|" + future + "|" + future},
+			want:  "T-002",
+		},
+		{
+			name:  "in_progress task with expired lease is reclaimed",
+			state: []string{"T-001|in_progress|claude||started|ci:42|" + past + "|" + past},
+			want:  "T-001",
+		},
+		{
+			name:  "in_progress task without a lease is not reclaimed",
+			state: []string{"T-001|in_progress|claude||"},
+			want:  "T-002",
+		},
+		{
+			name:  "completed task with expired lease stays completed",
+			state: []string{"T-001|completed|claude||done|ci:42|" + past + "|" + past},
+			want:  "T-002",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			specs := []*ParsedTaskSpec{makeSpec("T-001", nil), makeSpec("T-002", nil)}
+			sel := NewTaskSelector(specs, writeStateContent(t, tt.state), selectorPhases())
+
+			got, err := sel.SelectNext(1)
+			require.NoError(t, err)
+			require.NotNil(t, got)
+			assert.Equal(t, tt.want, got.ID)
+		})
+	}
+}
+
 func TestSelectNext_MissingStateEntryTreatedAsNotStarted(t *testing.T) {
 	t.Parallel()
 
@@ -1703,6 +1750,61 @@ func TestSelectNext_RespectsPhaseRange(t *testing.T) {
 	assert.Equal(t, "T-006", got.ID, "phase 2 selector must not return T-001 from phase 1")
 }
 
+// ---- Phases defined by task lists, labels and dependencies ------------------
+
+func TestSelectNext_ListAndLabelPhases(t *testing.T) {
+	t.Parallel()
+
+	specs := []*ParsedTaskSpec{
+		makeSpec("T-001", nil),
+		labeledSpec("T-002", "api"),
+		makeSpec("T-003", nil),
+		makeSpec("T-020", nil),
+	}
+	phases := []Phase{
+		{ID: 1, Name: "Foundation", StartTask: "T-001", EndTask: "T-005"},
+		{ID: 2, Name: "API", Tasks: []string{"T-02*"}, Labels: []string{"api"}},
+	}
+	sm := writeStateContent(t, []string{"T-001|completed|claude|2026-01-01T00:00:00Z|"})
+	sel := NewTaskSelector(specs, sm, phases)
+
+	got, err := sel.SelectNext(1)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "T-003", got.ID, "T-002 is claimed by the API phase through its label")
+
+	got, err = sel.SelectNext(2)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "T-002", got.ID)
+
+	remaining, err := sel.RemainingTaskIDs(2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"T-002", "T-020"}, remaining)
+}
+
+func TestSelect_PhaseDependencies(t *testing.T) {
+	t.Parallel()
+
+	specs := []*ParsedTaskSpec{makeSpec("T-001", nil), makeSpec("T-002", nil)}
+	phases := []Phase{
+		{ID: 1, Name: "Foundation", Tasks: []string{"T-001"}},
+		{ID: 2, Name: "API", Tasks: []string{"T-002"}, DependsOn: []int{1}},
+	}
+
+	sel := NewTaskSelector(specs, emptyStateManager(t), phases)
+	_, err := sel.Select(2)
+	require.ErrorIs(t, err, ErrPhaseNotReady)
+	assert.Contains(t, err.Error(), "phase 2 depends on phase 1 (Foundation)")
+
+	sm := writeStateContent(t, []string{"T-001|skipped|||"})
+	sel = NewTaskSelector(specs, sm, phases)
+	got, err := sel.Select(2)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "T-002", got.Spec.ID)
+}
+
 // ---- Large spec set benchmarks ----------------------------------------------
 
 func BenchmarkSelectNext_LargePhase(b *testing.B) {
@@ -1839,3 +1941,26 @@ func BenchmarkCompletedTaskIDs_LargeSet(b *testing.B) {
 		_, _ = sel.CompletedTaskIDs()
 	}
 }
+
+// ---- TasksWithLabel ---------------------------------------------------------
+
+func TestTasksWithLabel(t *testing.T) {
+	t.Parallel()
+
+	a := makeSpec("T-001", nil)
+	a.Labels = []string{"api"}
+	b := makeSpec("T-002", nil)
+	c := makeSpec("T-003", nil)
+	c.Labels = []string{"ui", "API"}
+
+	sel := NewTaskSelector([]*ParsedTaskSpec{a, b, c}, emptyStateManager(t), selectorPhases())
+
+	got := sel.TasksWithLabel("api")
+	require.Len(t, got, 2)
+	assert.Equal(t, "T-001", got[0].ID)
+	assert.Equal(t, "T-003", got[1].ID)
+
+	none := sel.TasksWithLabel("docs")
+	assert.NotNil(t, none)
+	assert.Empty(t, none)
+}
@@ -0,0 +1,131 @@
+package task
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecDraft holds the fields of a new task spec file before it is rendered.
+type SpecDraft struct {
+	// ID is the task identifier, e.g. "T-042".
+	ID string
+	// Title is the human-readable task name.
+	Title string
+	// Priority is the priority label, e.g. "must-have".
+	Priority string
+	// Effort is the size estimate, e.g. "small".
+	Effort string
+	// Dependencies are the IDs of tasks that must be completed first.
+	Dependencies []string
+	// Goal describes what the task should achieve.
+	Goal string
+	// AcceptanceCriteria are the conditions that must hold for completion.
+	AcceptanceCriteria []string
+	// Labels are written to the front matter when non-empty.
+	Labels []string
+	// GitHubIssue is written to the front matter when non-zero.
+	GitHubIssue int
+}
+
+// specDraftFrontMatter is the front matter written for a SpecDraft that has
+// labels or a linked GitHub issue.
+type specDraftFrontMatter struct {
+	Labels      []string `yaml:"labels,omitempty"`
+	GitHubIssue int      `yaml:"github_issue,omitempty"`
+}
+
+// specDraftTemplate renders a SpecDraft in the same layout as the specs
+// generated by "raven prd", so that ParseTaskSpec reads it back unchanged.
+var specDraftTemplate = template.Must(
+	template.New("spec").
+		Funcs(template.FuncMap{
+			"joinDeps": func(deps []string) string {
+				if len(deps) == 0 {
+					return "None"
+				}
+				return strings.Join(deps, ", ")
+			},
+		}).
+		Parse(`{{ with .FrontMatter }}---
+{{ . }}---
+{{ end }}# {{ .ID }}: {{ .Title }}
+
+## Metadata
+| Field | Value |
+|-------|-------|
+| Priority | {{ .Priority }} |
+| Estimated Effort | {{ .Effort }} |
+| Dependencies | {{ joinDeps .Dependencies }} |
+
+## Goal
+{{ .Goal }}
+
+## Acceptance Criteria
+{{ range .AcceptanceCriteria }}- [ ] {{ . }}
+{{ end }}`),
+)
+
+// RenderTaskSpec renders d as task spec markdown. Returns an error when the ID
+// is not a valid task ID or the title is empty.
+func RenderTaskSpec(d SpecDraft) ([]byte, error) {
+	if !IsTaskID(d.ID) {
+		return nil, fmt.Errorf("rendering task spec: %q is not a valid task ID", d.ID)
+	}
+	d.Title = strings.TrimSpace(d.Title)
+	if d.Title == "" {
+		return nil, fmt.Errorf("rendering task spec %s: title must not be empty", d.ID)
+	}
+	for _, dep := range d.Dependencies {
+		if !IsTaskID(dep) {
+			return nil, fmt.Errorf("rendering task spec %s: dependency %q is not a valid task ID", d.ID, dep)
+		}
+	}
+	d.AcceptanceCriteria = cleanList(d.AcceptanceCriteria)
+	d.Labels = cleanList(d.Labels)
+
+	data := struct {
+		SpecDraft
+		FrontMatter string
+	}{SpecDraft: d}
+	if len(d.Labels) > 0 || d.GitHubIssue > 0 {
+		fm, err := yaml.Marshal(specDraftFrontMatter{Labels: d.Labels, GitHubIssue: d.GitHubIssue})
+		if err != nil {
+			return nil, fmt.Errorf("rendering task spec %s: front matter: %w", d.ID, err)
+		}
+		data.FrontMatter = string(fm)
+	}
+
+	var buf bytes.Buffer
+	if err := specDraftTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering task spec %s: %w", d.ID, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// NextTaskID returns the first unused task ID with the given prefix: one more
+// than the highest number among specs with that prefix, padded to the widest
+// existing ID (at least MinTaskIDDigits). An empty prefix means
+// DefaultTaskIDPrefix.
+func NextTaskID(specs []*ParsedTaskSpec, prefix string) string {
+	if prefix == "" {
+		prefix = DefaultTaskIDPrefix
+	}
+	highest, digits := 0, MinTaskIDDigits
+	for _, s := range specs {
+		p, err := ParseTaskID(s.ID)
+		if err != nil || p.Prefix != prefix {
+			continue
+		}
+		if p.Number > highest {
+			highest = p.Number
+		}
+		if p.Digits > digits {
+			digits = p.Digits
+		}
+	}
+	return FormatTaskID(prefix, highest+1, digits)
+}
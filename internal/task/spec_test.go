@@ -0,0 +1,119 @@
+package task
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTaskSpec_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	draft := SpecDraft{
+		ID:                 "T-042",
+		Title:              "  Add retry budget ",
+		Priority:           "must-have",
+		Effort:             "small",
+		Dependencies:       []string{"T-010", "T-011"},
+		Goal:               "Stop retrying forever.",
+		AcceptanceCriteria: []string{"Retries stop after the budget", " ", "Budget is configurable"},
+	}
+	content, err := RenderTaskSpec(draft)
+	require.NoError(t, err)
+
+	spec, err := ParseTaskSpec(string(content))
+	require.NoError(t, err)
+	assert.Equal(t, "T-042", spec.ID)
+	assert.Equal(t, "Add retry budget", spec.Title)
+	assert.Equal(t, "must-have", spec.Priority)
+	assert.Equal(t, "small", spec.Effort)
+	assert.Equal(t, []string{"T-010", "T-011"}, spec.Dependencies)
+	assert.Contains(t, string(content), "## Goal\nStop retrying forever.\n")
+	assert.Contains(t, string(content), "- [ ] Retries stop after the budget\n- [ ] Budget is configurable\n")
+}
+
+func TestRenderTaskSpec_NoDependencies(t *testing.T) {
+	t.Parallel()
+
+	content, err := RenderTaskSpec(SpecDraft{ID: "API-0001", Title: "Bootstrap"})
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "| Dependencies | None |")
+
+	spec, err := ParseTaskSpec(string(content))
+	require.NoError(t, err)
+	assert.Empty(t, spec.Dependencies)
+}
+
+func TestRenderTaskSpec_FrontMatter(t *testing.T) {
+	t.Parallel()
+
+	content, err := RenderTaskSpec(SpecDraft{
+		ID:          "T-007",
+		Title:       "Imported",
+		Labels:      []string{"api"},
+		GitHubIssue: 31,
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "---\n"))
+
+	spec, err := ParseTaskSpec(string(content))
+	require.NoError(t, err)
+	assert.Equal(t, "T-007", spec.ID)
+	assert.Equal(t, []string{"api"}, spec.Labels)
+	assert.Equal(t, 31, spec.GitHubIssue)
+}
+
+func TestRenderTaskSpec_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		draft   SpecDraft
+		wantErr string
+	}{
+		{name: "invalid id", draft: SpecDraft{ID: "T-1", Title: "X"}, wantErr: "not a valid task ID"},
+		{name: "empty title", draft: SpecDraft{ID: "T-001", Title: "  "}, wantErr: "title must not be empty"},
+		{name: "invalid dependency", draft: SpecDraft{ID: "T-002", Title: "X", Dependencies: []string{"nope"}}, wantErr: `dependency "nope"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := RenderTaskSpec(tt.draft)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestNextTaskID(t *testing.T) {
+	t.Parallel()
+
+	specs := func(ids ...string) []*ParsedTaskSpec {
+		out := make([]*ParsedTaskSpec, len(ids))
+		for i, id := range ids {
+			out[i] = makeSpec(id, nil)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		specs  []*ParsedTaskSpec
+		prefix string
+		want   string
+	}{
+		{name: "no tasks", specs: nil, prefix: "", want: "T-001"},
+		{name: "after highest", specs: specs("T-001", "T-007", "T-003"), prefix: "T", want: "T-008"},
+		{name: "grows past 999", specs: specs("T-999"), prefix: "T", want: "T-1000"},
+		{name: "keeps width", specs: specs("API-0041"), prefix: "API", want: "API-0042"},
+		{name: "other prefixes ignored", specs: specs("API-0041", "T-002"), prefix: "T", want: "T-003"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, NextTaskID(tt.specs, tt.prefix))
+		})
+	}
+}
@@ -14,26 +14,122 @@ import (
 // The file format is pipe-delimited with five fields:
 //
 //	task_id|status|agent|timestamp|notes
+//
+// A claimed task (see StateManager.Claim) carries three more fields after
+// the notes:
+//
+//	task_id|status|agent|timestamp|notes|claimed_by|lease_expiry|heartbeat
 type TaskState struct {
 	TaskID    string     `json:"task_id"`
 	Status    TaskStatus `json:"status"`
 	Agent     string     `json:"agent"`
 	Timestamp time.Time  `json:"timestamp"`
 	Notes     string     `json:"notes"`
+
+	// ClaimedBy identifies the process holding the task's lease, e.g.
+	// "build-host:4242"; empty when the task is not claimed.
+	ClaimedBy string `json:"claimed_by,omitempty"`
+	// LeaseExpiry is when the claim lapses unless renewed.
+	LeaseExpiry time.Time `json:"lease_expiry,omitzero"`
+	// Heartbeat is when the holder last renewed the claim.
+	Heartbeat time.Time `json:"heartbeat,omitzero"`
 }
 
 // StateManager manages the task-state.conf file. It reads, writes, and
 // queries task state using an atomic write pattern (write to temp file then
 // rename) for cross-platform concurrent safety. A mutex serializes concurrent
-// reads and writes within the same process.
+// reads and writes within the same process, and every read-modify-write
+// cycle also holds a lock file next to the state file so that processes
+// sharing it (see Claim) do not lose each other's updates.
+//
+// Every Update and UpdateStatus is also appended to an append-only JSONL
+// history file (see HistoryEntry), so past states can be audited and
+// reconstructed with At. Per-task implementation metrics (see TaskMetrics)
+// are kept in a JSON file next to the state file.
 type StateManager struct {
-	mu       sync.Mutex
-	filePath string
+	mu          sync.Mutex
+	filePath    string
+	historyPath string
+	metricsPath string
+	runID       string
+
+	// snapshot, when non-nil, makes the manager a read-only view of these
+	// states instead of the state file. See At.
+	snapshot []TaskState
 }
 
-// NewStateManager creates a StateManager for the given state file path.
+// NewStateManager creates a StateManager for the given state file path. The
+// history file defaults to HistoryFilePath(filePath) and the metrics file to
+// MetricsFilePath(filePath).
 func NewStateManager(filePath string) *StateManager {
-	return &StateManager{filePath: filePath}
+	return &StateManager{
+		filePath:    filePath,
+		historyPath: HistoryFilePath(filePath),
+		metricsPath: MetricsFilePath(filePath),
+	}
+}
+
+// SetHistoryFile overrides the history file path. An empty path disables
+// history recording.
+func (sm *StateManager) SetHistoryFile(path string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.historyPath = path
+}
+
+// HistoryFile returns the history file path, or "" when recording is disabled.
+func (sm *StateManager) HistoryFile() string {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.historyPath
+}
+
+// SetRunID sets the workflow run ID recorded with subsequent history entries.
+// An empty ID clears it.
+func (sm *StateManager) SetRunID(runID string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.runID = runID
+}
+
+// History returns the recorded state changes for taskID in the order they
+// happened. An empty taskID returns the changes for all tasks.
+func (sm *StateManager) History(taskID string) ([]HistoryEntry, error) {
+	path := sm.HistoryFile()
+	if path == "" {
+		return []HistoryEntry{}, nil
+	}
+	entries, err := LoadHistory(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading history: %w", err)
+	}
+	if taskID == "" {
+		return entries, nil
+	}
+	filtered := []HistoryEntry{}
+	for _, e := range entries {
+		if e.TaskID == taskID {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered, nil
+}
+
+// At returns a read-only StateManager reflecting task state as of t,
+// reconstructed from the history file. Tasks whose last change predates the
+// history file (or that never changed) read as not_started. Metrics are not
+// versioned, so the returned manager reports the current metrics. Writes to
+// the returned manager fail.
+func (sm *StateManager) At(t time.Time) (*StateManager, error) {
+	entries, err := sm.History("")
+	if err != nil {
+		return nil, fmt.Errorf("reconstructing state at %s: %w", t.Format(time.RFC3339), err)
+	}
+	return &StateManager{
+		filePath:    sm.filePath,
+		metricsPath: sm.metricsPath,
+		snapshot:    StatesAt(entries, t),
+	}, nil
 }
 
 // Load reads the state file and returns all task states.
@@ -46,6 +142,12 @@ func (sm *StateManager) Load() ([]TaskState, error) {
 
 // load is the internal, mutex-free version of Load. Callers must hold sm.mu.
 func (sm *StateManager) load() ([]TaskState, error) {
+	if sm.snapshot != nil {
+		states := make([]TaskState, len(sm.snapshot))
+		copy(states, sm.snapshot)
+		return states, nil
+	}
+
 	f, err := os.Open(sm.filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -98,7 +200,8 @@ func (sm *StateManager) Get(taskID string) (*TaskState, error) {
 // Update sets the state for a specific task. If the task does not exist in
 // the file, a new line is appended. If it exists, the line is updated in
 // place. The entire read-modify-write cycle is serialized by the internal
-// mutex, and the write uses an atomic rename for cross-platform safety.
+// mutex, and the write uses an atomic rename for cross-platform safety. The
+// new state replaces any claim on the task.
 func (sm *StateManager) Update(state TaskState) error {
 	if state.TaskID == "" {
 		return fmt.Errorf("updating state: task ID must not be empty")
@@ -107,14 +210,22 @@ func (sm *StateManager) Update(state TaskState) error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	unlock, err := sm.lockFile()
+	if err != nil {
+		return fmt.Errorf("updating state: %w", err)
+	}
+	defer unlock()
+
 	states, err := sm.load()
 	if err != nil {
 		return fmt.Errorf("updating state: %w", err)
 	}
 
+	var previous TaskStatus
 	updated := false
 	for i, s := range states {
 		if s.TaskID == state.TaskID {
+			previous = s.Status
 			states[i] = state
 			updated = true
 			break
@@ -124,12 +235,16 @@ func (sm *StateManager) Update(state TaskState) error {
 		states = append(states, state)
 	}
 
-	return sm.writeAtomic(states)
+	if err := sm.writeAtomic(states); err != nil {
+		return err
+	}
+	return sm.recordHistory(previous, state)
 }
 
 // UpdateStatus is a convenience method that updates only the status, agent,
 // and timestamp for a task, preserving any existing notes. If the task has
-// no existing entry, a new one is created with empty notes. The entire
+// no existing entry, a new one is created with empty notes. Any claim on the
+// task is released: a status change ends the holder's work on it. The entire
 // read-modify-write cycle is serialized by the internal mutex.
 func (sm *StateManager) UpdateStatus(taskID string, status TaskStatus, agent string) error {
 	if taskID == "" {
@@ -139,6 +254,12 @@ func (sm *StateManager) UpdateStatus(taskID string, status TaskStatus, agent str
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	unlock, err := sm.lockFile()
+	if err != nil {
+		return fmt.Errorf("updating status for task %q: %w", taskID, err)
+	}
+	defer unlock()
+
 	states, err := sm.load()
 	if err != nil {
 		return fmt.Errorf("updating status for task %q: %w", taskID, err)
@@ -146,6 +267,7 @@ func (sm *StateManager) UpdateStatus(taskID string, status TaskStatus, agent str
 
 	// Find existing notes; build the updated entry.
 	notes := ""
+	var previous TaskStatus
 	updated := false
 	newEntry := TaskState{
 		TaskID:    taskID,
@@ -155,6 +277,7 @@ func (sm *StateManager) UpdateStatus(taskID string, status TaskStatus, agent str
 	}
 	for i, s := range states {
 		if s.TaskID == taskID {
+			previous = s.Status
 			newEntry.Notes = s.Notes
 			states[i] = newEntry
 			updated = true
@@ -166,7 +289,91 @@ func (sm *StateManager) UpdateStatus(taskID string, status TaskStatus, agent str
 		states = append(states, newEntry)
 	}
 
-	return sm.writeAtomic(states)
+	if err := sm.writeAtomic(states); err != nil {
+		return err
+	}
+	return sm.recordHistory(previous, newEntry)
+}
+
+// UpdateNotes replaces the notes of a task, preserving its status, agent,
+// and claim and refreshing its timestamp. A task without a state entry is created as
+// not_started. Notes are collapsed onto a single line (runs of whitespace,
+// including newlines, become one space) because the state file is
+// line-oriented.
+func (sm *StateManager) UpdateNotes(taskID, notes string) error {
+	if taskID == "" {
+		return fmt.Errorf("updating notes: task ID must not be empty")
+	}
+	notes = strings.Join(strings.Fields(notes), " ")
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	unlock, err := sm.lockFile()
+	if err != nil {
+		return fmt.Errorf("updating notes for task %q: %w", taskID, err)
+	}
+	defer unlock()
+
+	states, err := sm.load()
+	if err != nil {
+		return fmt.Errorf("updating notes for task %q: %w", taskID, err)
+	}
+
+	newEntry := TaskState{
+		TaskID:    taskID,
+		Status:    StatusNotStarted,
+		Timestamp: time.Now().UTC(),
+		Notes:     notes,
+	}
+	var previous TaskStatus
+	updated := false
+	for i, s := range states {
+		if s.TaskID == taskID {
+			previous = s.Status
+			newEntry.Status = s.Status
+			newEntry.Agent = s.Agent
+			newEntry.ClaimedBy = s.ClaimedBy
+			newEntry.LeaseExpiry = s.LeaseExpiry
+			newEntry.Heartbeat = s.Heartbeat
+			states[i] = newEntry
+			updated = true
+			break
+		}
+	}
+	if !updated {
+		states = append(states, newEntry)
+	}
+
+	if err := sm.writeAtomic(states); err != nil {
+		return err
+	}
+	return sm.recordHistory(previous, newEntry)
+}
+
+// recordHistory appends the transition from previous to state to the history
+// file. Callers must hold sm.mu. A zero state timestamp is recorded as now.
+func (sm *StateManager) recordHistory(previous TaskStatus, state TaskState) error {
+	if sm.historyPath == "" {
+		return nil
+	}
+	ts := state.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	entry := HistoryEntry{
+		Timestamp:      ts.UTC(),
+		TaskID:         state.TaskID,
+		PreviousStatus: previous,
+		Status:         state.Status,
+		Agent:          state.Agent,
+		RunID:          sm.runID,
+		Notes:          state.Notes,
+	}
+	if err := appendHistory(sm.historyPath, entry); err != nil {
+		return fmt.Errorf("recording history for task %q: %w", state.TaskID, err)
+	}
+	return nil
 }
 
 // Initialize creates state file entries with StatusNotStarted for all
@@ -176,6 +383,12 @@ func (sm *StateManager) Initialize(taskIDs []string) error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	unlock, err := sm.lockFile()
+	if err != nil {
+		return fmt.Errorf("initializing state: %w", err)
+	}
+	defer unlock()
+
 	ordered, err := sm.load()
 	if err != nil {
 		return fmt.Errorf("initializing state: %w", err)
@@ -267,13 +480,19 @@ func parseStateFile(f *os.File) ([]TaskState, error) {
 }
 
 // parseLine parses a single pipe-delimited state file line into a TaskState.
-// The canonical format is: task_id|status|agent|timestamp|notes (5 columns).
+// The canonical format is: task_id|status|agent|timestamp|notes (5 columns),
+// followed by claimed_by|lease_expiry|heartbeat for claimed tasks.
 // For backward compatibility, lines with fewer than 5 columns are accepted;
 // missing columns default to zero values. When the state file is next written
-// via writeAtomic, all entries are serialized in the full 5-column format by
+// via writeAtomic, all entries are serialized in the full format by
 // formatLine, normalizing any incomplete rows that were read.
+//
+// Notes may contain pipes. The trailing columns are only read as a claim
+// when there are at least three of them after the notes column, the first is
+// non-empty and the last two are RFC3339 timestamps; otherwise everything
+// after the timestamp is notes.
 func parseLine(line string) (*TaskState, error) {
-	parts := strings.SplitN(line, "|", 5)
+	parts := strings.Split(line, "|")
 	if len(parts) < 1 || strings.TrimSpace(parts[0]) == "" {
 		return nil, fmt.Errorf("invalid state line: task ID is empty in %q", line)
 	}
@@ -304,9 +523,22 @@ func parseLine(line string) (*TaskState, error) {
 		}
 	}
 
-	// Notes field (index 4) -- may be absent or empty.
+	// Notes field (index 4) -- may be absent or empty -- and the optional
+	// claim columns after it.
 	if len(parts) > 4 {
-		state.Notes = parts[4]
+		rest := parts[4:]
+		if n := len(rest); n >= 4 {
+			owner := strings.TrimSpace(rest[n-3])
+			expiry, expErr := time.Parse(time.RFC3339, strings.TrimSpace(rest[n-2]))
+			heartbeat, hbErr := time.Parse(time.RFC3339, strings.TrimSpace(rest[n-1]))
+			if owner != "" && expErr == nil && hbErr == nil {
+				state.ClaimedBy = owner
+				state.LeaseExpiry = expiry
+				state.Heartbeat = heartbeat
+				rest = rest[:n-3]
+			}
+		}
+		state.Notes = strings.Join(rest, "|")
 	}
 
 	return state, nil
@@ -316,25 +548,42 @@ func parseLine(line string) (*TaskState, error) {
 // the canonical 5-column schema: task_id|status|agent|timestamp|notes.
 // Empty timestamp is rendered as an empty field; notes are kept verbatim.
 // This ensures that every line written by writeAtomic conforms to the full
-// 5-column format required by the task state specification (T-064).
+// 5-column format required by the task state specification (T-064). Claimed
+// tasks get the claimed_by|lease_expiry|heartbeat columns appended.
 func formatLine(state TaskState) string {
 	ts := ""
 	if !state.Timestamp.IsZero() {
 		ts = state.Timestamp.UTC().Format(time.RFC3339)
 	}
-	return strings.Join([]string{
+	fields := []string{
 		state.TaskID,
 		string(state.Status),
 		state.Agent,
 		ts,
 		state.Notes,
-	}, "|")
+	}
+	if state.ClaimedBy != "" {
+		heartbeat := state.Heartbeat
+		if heartbeat.IsZero() {
+			heartbeat = state.LeaseExpiry
+		}
+		fields = append(fields,
+			state.ClaimedBy,
+			state.LeaseExpiry.UTC().Format(time.RFC3339),
+			heartbeat.UTC().Format(time.RFC3339),
+		)
+	}
+	return strings.Join(fields, "|")
 }
 
 // writeAtomic writes states to a temporary file in the same directory as
 // sm.filePath, then renames it atomically to sm.filePath. File permissions
 // are 0644.
 func (sm *StateManager) writeAtomic(states []TaskState) error {
+	if sm.snapshot != nil {
+		return fmt.Errorf("writing state file %q: state snapshot is read-only", sm.filePath)
+	}
+
 	// Ensure the parent directory exists.
 	dir := filepath.Dir(sm.filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
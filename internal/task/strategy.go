@@ -0,0 +1,276 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Built-in selection strategy names, as accepted by NewSelectionStrategy,
+// project.task_strategy, and "raven implement --strategy".
+const (
+	StrategyID           = "id"
+	StrategyPriority     = "priority"
+	StrategyEffort       = "effort"
+	StrategyCriticalPath = "critical-path"
+	StrategyUnblock      = "unblock"
+)
+
+// DefaultSelectionStrategy is the strategy used when none is configured:
+// the lowest ready task ID.
+const DefaultSelectionStrategy = StrategyID
+
+// SelectionContext is the read-only view of the project given to a
+// SelectionStrategy.
+type SelectionContext struct {
+	// Specs maps every known task ID to its spec, across all phases.
+	Specs map[string]*ParsedTaskSpec
+	// State maps task IDs to their current state; missing entries are
+	// not_started.
+	State map[string]*TaskState
+}
+
+// status returns the status of id, treating a missing entry as not_started.
+func (c SelectionContext) status(id string) TaskStatus {
+	if ts, ok := c.State[id]; ok {
+		return ts.Status
+	}
+	return StatusNotStarted
+}
+
+// done reports whether id carries no remaining work.
+func (c SelectionContext) done(id string) bool {
+	s := c.status(id)
+	return s == StatusCompleted || s == StatusSkipped
+}
+
+// SelectionStrategy chooses the next task among the ready candidates.
+type SelectionStrategy interface {
+	// Name returns the strategy name, e.g. "priority".
+	Name() string
+	// Select returns the chosen candidate and a short, human-readable reason
+	// for the choice. candidates is non-empty and sorted by task ID.
+	Select(candidates []*ParsedTaskSpec, ctx SelectionContext) (*ParsedTaskSpec, string)
+}
+
+// Selection is the result of TaskSelector.Select.
+type Selection struct {
+	// Spec is the chosen task.
+	Spec *ParsedTaskSpec
+	// Strategy is the name of the strategy that made the choice.
+	Strategy string
+	// Reason explains why Spec was chosen over the other candidates.
+	Reason string
+	// Candidates is the number of ready tasks the strategy chose from.
+	Candidates int
+}
+
+// selectionStrategies maps the built-in strategy names to constructors.
+var selectionStrategies = map[string]func() SelectionStrategy{
+	StrategyID:           func() SelectionStrategy { return idStrategy{} },
+	StrategyPriority:     func() SelectionStrategy { return priorityStrategy{} },
+	StrategyEffort:       func() SelectionStrategy { return effortStrategy{} },
+	StrategyCriticalPath: func() SelectionStrategy { return criticalPathStrategy{} },
+	StrategyUnblock:      func() SelectionStrategy { return unblockStrategy{} },
+}
+
+// NewSelectionStrategy returns the built-in strategy called name. An empty
+// name returns DefaultSelectionStrategy.
+func NewSelectionStrategy(name string) (SelectionStrategy, error) {
+	if name == "" {
+		name = DefaultSelectionStrategy
+	}
+	ctor, ok := selectionStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown task selection strategy %q: must be one of %s",
+			name, strings.Join(SelectionStrategyNames(), ", "))
+	}
+	return ctor(), nil
+}
+
+// SelectionStrategyNames returns the names of the built-in strategies,
+// sorted.
+func SelectionStrategyNames() []string {
+	names := make([]string, 0, len(selectionStrategies))
+	for name := range selectionStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pickBest returns the candidate with the highest score, preferring the
+// lower task ID on ties (candidates are sorted by ID).
+func pickBest(candidates []*ParsedTaskSpec, score func(*ParsedTaskSpec) float64) (*ParsedTaskSpec, float64) {
+	best, bestScore := candidates[0], score(candidates[0])
+	for _, c := range candidates[1:] {
+		if s := score(c); s > bestScore {
+			best, bestScore = c, s
+		}
+	}
+	return best, bestScore
+}
+
+// idStrategy picks the lowest ready task ID.
+type idStrategy struct{}
+
+func (idStrategy) Name() string { return StrategyID }
+
+func (idStrategy) Select(candidates []*ParsedTaskSpec, _ SelectionContext) (*ParsedTaskSpec, string) {
+	return candidates[0], fmt.Sprintf("lowest task ID of %d ready", len(candidates))
+}
+
+// priorityStrategy picks the highest-priority ready task.
+type priorityStrategy struct{}
+
+func (priorityStrategy) Name() string { return StrategyPriority }
+
+func (priorityStrategy) Select(candidates []*ParsedTaskSpec, _ SelectionContext) (*ParsedTaskSpec, string) {
+	best, _ := pickBest(candidates, func(s *ParsedTaskSpec) float64 {
+		return -float64(PriorityRank(s.Priority))
+	})
+	priority := best.Priority
+	if priority == "" {
+		priority = "unset"
+	}
+	return best, fmt.Sprintf("highest priority (%s) of %d ready", priority, len(candidates))
+}
+
+// PriorityRank orders free-form priority labels: 0 for must-have, critical,
+// high, or P0; 1 for should-have, medium, or P1 (and for empty or
+// unrecognised labels); 2 for nice-to-have, could-have, low, or P2; and the
+// number itself for P3 and above.
+func PriorityRank(priority string) int {
+	p := strings.ToLower(strings.TrimSpace(priority))
+	p = strings.NewReplacer(" ", "-", "_", "-").Replace(p)
+	switch {
+	case strings.HasPrefix(p, "must"), strings.HasPrefix(p, "critical"), strings.HasPrefix(p, "high"), p == "p0":
+		return 0
+	case strings.HasPrefix(p, "nice"), strings.HasPrefix(p, "could"), strings.HasPrefix(p, "low"), p == "p2":
+		return 2
+	case len(p) == 2 && p[0] == 'p' && p[1] >= '3' && p[1] <= '9':
+		return int(p[1] - '0')
+	default:
+		return 1
+	}
+}
+
+// effortStrategy picks the ready task with the smallest estimated effort.
+type effortStrategy struct{}
+
+func (effortStrategy) Name() string { return StrategyEffort }
+
+func (effortStrategy) Select(candidates []*ParsedTaskSpec, _ SelectionContext) (*ParsedTaskSpec, string) {
+	best, score := pickBest(candidates, func(s *ParsedTaskSpec) float64 {
+		return -strategyEffortHours(s)
+	})
+	return best, fmt.Sprintf("smallest effort (%.1fh) of %d ready", -score, len(candidates))
+}
+
+// strategyEffortHours is EffortHours, except that a task without an
+// estimate counts as medium rather than as the minimal graph weight, so that
+// unestimated tasks are not always picked first.
+func strategyEffortHours(s *ParsedTaskSpec) float64 {
+	if strings.TrimSpace(s.Effort) == "" {
+		return EffortHours("medium")
+	}
+	return EffortHours(s.Effort)
+}
+
+// criticalPathStrategy picks the ready task that heads the longest chain of
+// remaining work, weighted by effort.
+type criticalPathStrategy struct{}
+
+func (criticalPathStrategy) Name() string { return StrategyCriticalPath }
+
+func (criticalPathStrategy) Select(candidates []*ParsedTaskSpec, ctx SelectionContext) (*ParsedTaskSpec, string) {
+	dependents := dependentsIndex(ctx)
+	memo := make(map[string]float64)
+	visiting := make(map[string]bool)
+
+	// chain returns the effort of id plus the longest chain of unfinished
+	// tasks that depend on it. Cycles contribute nothing.
+	var chain func(id string) float64
+	chain = func(id string) float64 {
+		if v, ok := memo[id]; ok {
+			return v
+		}
+		if visiting[id] {
+			return 0
+		}
+		visiting[id] = true
+		longest := 0.0
+		for _, d := range dependents[id] {
+			if ctx.done(d) {
+				continue
+			}
+			longest = max(longest, chain(d))
+		}
+		visiting[id] = false
+		memo[id] = strategyEffortHours(ctx.Specs[id]) + longest
+		return memo[id]
+	}
+
+	best, score := pickBest(candidates, func(s *ParsedTaskSpec) float64 { return chain(s.ID) })
+	return best, fmt.Sprintf("heads the longest remaining chain (%.1fh) of %d ready", score, len(candidates))
+}
+
+// unblockStrategy picks the ready task with the most unfinished tasks
+// depending on it, directly or transitively.
+type unblockStrategy struct{}
+
+func (unblockStrategy) Name() string { return StrategyUnblock }
+
+func (unblockStrategy) Select(candidates []*ParsedTaskSpec, ctx SelectionContext) (*ParsedTaskSpec, string) {
+	dependents := dependentsIndex(ctx)
+
+	// reach counts the unfinished tasks reachable from id through
+	// dependents, and how many of them depend on id directly.
+	reach := func(id string) (total, direct int) {
+		seen := map[string]bool{id: true}
+		queue := []string{id}
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+			for _, d := range dependents[cur] {
+				if seen[d] || ctx.done(d) {
+					continue
+				}
+				seen[d] = true
+				total++
+				if cur == id {
+					direct++
+				}
+				queue = append(queue, d)
+			}
+		}
+		return total, direct
+	}
+
+	// Score by total reach, then by direct dependents.
+	best, _ := pickBest(candidates, func(s *ParsedTaskSpec) float64 {
+		total, direct := reach(s.ID)
+		return float64(total) + float64(direct)/float64(len(ctx.Specs)+1)
+	})
+	total, direct := reach(best.ID)
+	return best, fmt.Sprintf("unblocks %d remaining tasks (%d directly) of %d ready", total, direct, len(candidates))
+}
+
+// dependentsIndex maps each task ID to the IDs of the specs that depend on
+// it, sorted by task ID.
+func dependentsIndex(ctx SelectionContext) map[string][]string {
+	idx := make(map[string][]string)
+	for id, s := range ctx.Specs {
+		for _, dep := range s.Dependencies {
+			if dep != id {
+				idx[dep] = append(idx[dep], id)
+			}
+		}
+	}
+	for dep := range idx {
+		sort.Slice(idx[dep], func(i, j int) bool {
+			return CompareTaskIDs(idx[dep][i], idx[dep][j]) < 0
+		})
+	}
+	return idx
+}
@@ -0,0 +1,152 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// strategyFixture returns specs for a small graph and the selection context
+// with T-001 completed:
+//
+//	T-001 (done) -> T-002 (small, nice-to-have) -> T-005 (large)
+//	T-003 (large, must-have)
+//	T-004 (medium) -> T-006 -> T-007
+//	               -> T-008
+func strategyFixture() ([]*ParsedTaskSpec, SelectionContext) {
+	spec := func(id, priority, effort string, deps ...string) *ParsedTaskSpec {
+		s := makeSpec(id, deps)
+		s.Priority = priority
+		s.Effort = effort
+		return s
+	}
+	specs := []*ParsedTaskSpec{
+		spec("T-001", "", "small"),
+		spec("T-002", "nice-to-have", "small", "T-001"),
+		spec("T-003", "Must Have", "large"),
+		spec("T-004", "should-have", "medium"),
+		spec("T-005", "", "Large: 20-30hrs", "T-002"),
+		spec("T-006", "", "small", "T-004"),
+		spec("T-007", "", "small", "T-006"),
+		spec("T-008", "", "small", "T-004"),
+	}
+	ctx := SelectionContext{
+		Specs: make(map[string]*ParsedTaskSpec, len(specs)),
+		State: map[string]*TaskState{"T-001": {TaskID: "T-001", Status: StatusCompleted}},
+	}
+	for _, s := range specs {
+		ctx.Specs[s.ID] = s
+	}
+	return specs, ctx
+}
+
+func TestSelectionStrategies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		strategy   string
+		want       string
+		wantReason string
+	}{
+		{strategy: StrategyID, want: "T-002", wantReason: "lowest task ID of 3 ready"},
+		{strategy: StrategyPriority, want: "T-003", wantReason: "highest priority (Must Have) of 3 ready"},
+		{strategy: StrategyEffort, want: "T-002", wantReason: "smallest effort (2.0h) of 3 ready"},
+		{strategy: StrategyCriticalPath, want: "T-002", wantReason: "heads the longest remaining chain (27.0h) of 3 ready"},
+		{strategy: StrategyUnblock, want: "T-004", wantReason: "unblocks 3 remaining tasks (2 directly) of 3 ready"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			t.Parallel()
+			specs, ctx := strategyFixture()
+			candidates := []*ParsedTaskSpec{specs[1], specs[2], specs[3]}
+
+			st, err := NewSelectionStrategy(tt.strategy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.strategy, st.Name())
+
+			got, reason := st.Select(candidates, ctx)
+			assert.Equal(t, tt.want, got.ID)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func TestSelectionStrategies_TiesPreferLowerID(t *testing.T) {
+	t.Parallel()
+
+	candidates := []*ParsedTaskSpec{makeSpec("T-001", nil), makeSpec("T-002", nil)}
+	ctx := SelectionContext{Specs: map[string]*ParsedTaskSpec{"T-001": candidates[0], "T-002": candidates[1]}}
+	for _, name := range SelectionStrategyNames() {
+		st, err := NewSelectionStrategy(name)
+		require.NoError(t, err)
+		got, _ := st.Select(candidates, ctx)
+		assert.Equal(t, "T-001", got.ID, name)
+	}
+}
+
+func TestNewSelectionStrategy(t *testing.T) {
+	t.Parallel()
+
+	st, err := NewSelectionStrategy("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSelectionStrategy, st.Name())
+
+	_, err = NewSelectionStrategy("random")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown task selection strategy "random"`)
+	assert.Contains(t, err.Error(), "critical-path, effort, id, priority, unblock")
+}
+
+func TestPriorityRank(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		priority string
+		want     int
+	}{
+		{"must-have", 0},
+		{"Must Have", 0},
+		{"P0", 0},
+		{"critical", 0},
+		{"should-have", 1},
+		{"", 1},
+		{"whenever", 1},
+		{"nice_to_have", 2},
+		{"Low", 2},
+		{"p3", 3},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, PriorityRank(tt.priority), tt.priority)
+	}
+}
+
+func TestTaskSelector_SelectWithStrategy(t *testing.T) {
+	t.Parallel()
+
+	specs, _ := strategyFixture()
+	sm := writeStateContent(t, []string{"T-001|completed|claude||"})
+	phases := []Phase{{ID: 1, Name: "All", StartTask: "T-001", EndTask: "T-008"}}
+	sel := NewTaskSelector(specs, sm, phases)
+
+	got, err := sel.Select(1)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "T-002", got.Spec.ID)
+	assert.Equal(t, StrategyID, got.Strategy)
+	assert.Equal(t, 3, got.Candidates)
+
+	st, err := NewSelectionStrategy(StrategyPriority)
+	require.NoError(t, err)
+	sel.SetStrategy(st)
+
+	spec, err := sel.SelectNext(1)
+	require.NoError(t, err)
+	assert.Equal(t, "T-003", spec.ID)
+
+	got, err = sel.SelectInRange("T-004", "T-008")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "T-004", got.Spec.ID)
+	assert.Equal(t, "highest priority (should-have) of 1 ready", got.Reason)
+}
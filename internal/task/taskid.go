@@ -0,0 +1,122 @@
+package task
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultTaskIDPrefix is the task ID prefix used when none is configured.
+const DefaultTaskIDPrefix = "T"
+
+// MinTaskIDDigits is the minimum number of digits in a task ID number. Numbers
+// are zero-padded to at least this width ("T-001") but may grow beyond it
+// ("T-1000").
+const MinTaskIDDigits = 3
+
+// taskIDPattern is the unanchored regular expression for a task ID: an
+// uppercase prefix, a hyphen, and at least MinTaskIDDigits digits. Examples:
+// "T-001", "T-1042", "API-0042". Submatch 1 is the prefix, submatch 2 the
+// digits.
+const taskIDPattern = `([A-Z][A-Z0-9]*)-(\d{3,})`
+
+// reTaskIDPrefix validates a configured task ID prefix.
+var reTaskIDPrefix = regexp.MustCompile(`^[A-Z][A-Z0-9]*$`)
+
+// TaskIDParts is a task ID split into its components.
+type TaskIDParts struct {
+	// Prefix is the part before the hyphen, e.g. "T" or "API".
+	Prefix string
+	// Number is the numeric value of the digits, e.g. 42 for "T-042".
+	Number int
+	// Digits is the number of digits as written, e.g. 3 for "T-042" and 4 for
+	// "API-0042". It is used to preserve zero-padding when generating
+	// neighbouring IDs.
+	Digits int
+}
+
+// String formats the parts back into a task ID.
+func (p TaskIDParts) String() string {
+	return FormatTaskID(p.Prefix, p.Number, p.Digits)
+}
+
+// ParseTaskID splits a task ID such as "T-042" or "API-1042" into its prefix,
+// number, and digit width. Surrounding whitespace is ignored. Returns an error
+// when id does not match the PREFIX-NNN form.
+func ParseTaskID(id string) (TaskIDParts, error) {
+	trimmed := strings.TrimSpace(id)
+	prefix, digits, ok := strings.Cut(trimmed, "-")
+	if !ok || prefix == "" {
+		return TaskIDParts{}, fmt.Errorf("task ID %q does not have the required 'PREFIX-' form", id)
+	}
+	if !reTaskIDPrefix.MatchString(prefix) {
+		return TaskIDParts{}, fmt.Errorf("task ID %q has invalid prefix %q", id, prefix)
+	}
+	if digits == "" {
+		return TaskIDParts{}, fmt.Errorf("task ID %q has no numeric suffix", id)
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil || n < 0 || strings.ContainsAny(digits, "+-") {
+		return TaskIDParts{}, fmt.Errorf("task ID %q has non-numeric suffix %q", id, digits)
+	}
+	if len(digits) < MinTaskIDDigits {
+		return TaskIDParts{}, fmt.Errorf("task ID %q must have at least %d digits", id, MinTaskIDDigits)
+	}
+	return TaskIDParts{Prefix: prefix, Number: n, Digits: len(digits)}, nil
+}
+
+// IsTaskID reports whether id is a well-formed task ID.
+func IsTaskID(id string) bool {
+	_, err := ParseTaskID(id)
+	return err == nil
+}
+
+// FormatTaskID builds a task ID from prefix and n, zero-padding the number to
+// digits (never fewer than MinTaskIDDigits). An empty prefix falls back to
+// DefaultTaskIDPrefix.
+func FormatTaskID(prefix string, n, digits int) string {
+	if prefix == "" {
+		prefix = DefaultTaskIDPrefix
+	}
+	if digits < MinTaskIDDigits {
+		digits = MinTaskIDDigits
+	}
+	return fmt.Sprintf("%s-%0*d", prefix, digits, n)
+}
+
+// ValidateTaskIDPrefix returns an error when prefix cannot be used as a task
+// ID prefix. Valid prefixes are an uppercase letter followed by uppercase
+// letters or digits, e.g. "T", "API", "WEB2".
+func ValidateTaskIDPrefix(prefix string) error {
+	if !reTaskIDPrefix.MatchString(prefix) {
+		return fmt.Errorf("invalid task ID prefix %q: must be an uppercase letter followed by uppercase letters or digits", prefix)
+	}
+	return nil
+}
+
+// CompareTaskIDs orders task IDs by prefix and then numerically, so that
+// "T-999" sorts before "T-1000". IDs that cannot be parsed sort after valid
+// ones, in lexical order. Returns -1, 0, or +1.
+func CompareTaskIDs(a, b string) int {
+	pa, errA := ParseTaskID(a)
+	pb, errB := ParseTaskID(b)
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return 1
+	case errB != nil:
+		return -1
+	}
+	if c := strings.Compare(pa.Prefix, pb.Prefix); c != 0 {
+		return c
+	}
+	switch {
+	case pa.Number < pb.Number:
+		return -1
+	case pa.Number > pb.Number:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package task
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaskID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		id      string
+		want    TaskIDParts
+		wantErr bool
+	}{
+		{name: "three digits", id: "T-042", want: TaskIDParts{Prefix: "T", Number: 42, Digits: 3}},
+		{name: "four digits", id: "T-1042", want: TaskIDParts{Prefix: "T", Number: 1042, Digits: 4}},
+		{name: "padded custom prefix", id: "API-0042", want: TaskIDParts{Prefix: "API", Number: 42, Digits: 4}},
+		{name: "prefix with digit", id: "WEB2-001", want: TaskIDParts{Prefix: "WEB2", Number: 1, Digits: 3}},
+		{name: "surrounding whitespace", id: "  T-007 ", want: TaskIDParts{Prefix: "T", Number: 7, Digits: 3}},
+		{name: "too few digits", id: "T-42", wantErr: true},
+		{name: "lowercase prefix", id: "api-042", wantErr: true},
+		{name: "no prefix", id: "-042", wantErr: true},
+		{name: "no hyphen", id: "T042", wantErr: true},
+		{name: "signed number", id: "T-+42", wantErr: true},
+		{name: "non-numeric", id: "T-abc", wantErr: true},
+		{name: "empty", id: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseTaskID(tt.id)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.False(t, IsTaskID(tt.id))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, IsTaskID(tt.id))
+		})
+	}
+}
+
+func TestFormatTaskID(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "T-001", FormatTaskID("T", 1, 3))
+	assert.Equal(t, "T-1000", FormatTaskID("T", 1000, 3))
+	assert.Equal(t, "API-0042", FormatTaskID("API", 42, 4))
+	assert.Equal(t, "T-007", FormatTaskID("", 7, 0), "empty prefix and narrow width fall back to defaults")
+	assert.Equal(t, "API-0042", TaskIDParts{Prefix: "API", Number: 42, Digits: 4}.String())
+}
+
+func TestValidateTaskIDPrefix(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateTaskIDPrefix("T"))
+	assert.NoError(t, ValidateTaskIDPrefix("API"))
+	assert.NoError(t, ValidateTaskIDPrefix("WEB2"))
+	assert.Error(t, ValidateTaskIDPrefix(""))
+	assert.Error(t, ValidateTaskIDPrefix("api"))
+	assert.Error(t, ValidateTaskIDPrefix("2WEB"))
+	assert.Error(t, ValidateTaskIDPrefix("T-"))
+}
+
+func TestCompareTaskIDs(t *testing.T) {
+	t.Parallel()
+
+	ids := []string{"T-1000", "bogus", "API-0002", "T-999", "T-001", "API-0010"}
+	sort.Slice(ids, func(i, j int) bool { return CompareTaskIDs(ids[i], ids[j]) < 0 })
+
+	assert.Equal(t, []string{"API-0002", "API-0010", "T-001", "T-999", "T-1000", "bogus"}, ids)
+	assert.Equal(t, 0, CompareTaskIDs("T-042", "T-0042"), "padding does not affect ordering")
+}
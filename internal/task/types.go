@@ -39,12 +39,28 @@ type Task struct {
 	SpecFile     string     `json:"spec_file"`
 }
 
-// Phase represents a group of related tasks executed together.
+// Phase represents a group of related tasks executed together. Membership is
+// defined by an inclusive task-ID range, an explicit list of task IDs or glob
+// patterns, task labels, or any combination of these; see PhaseForTask for
+// how overlapping definitions are resolved.
 type Phase struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	StartTask string `json:"start_task"`
-	EndTask   string `json:"end_task"`
+	ID        int    `json:"id" toml:"id"`
+	Name      string `json:"name" toml:"name"`
+	StartTask string `json:"start_task" toml:"start_task"`
+	EndTask   string `json:"end_task" toml:"end_task"`
+	// Tasks lists task IDs ("T-011") and path.Match glob patterns ("T-05*")
+	// belonging to the phase.
+	Tasks []string `json:"tasks,omitempty" toml:"tasks"`
+	// Labels assigns every task carrying one of these labels to the phase.
+	Labels []string `json:"labels,omitempty" toml:"labels"`
+	// DependsOn lists phase IDs that must be complete before tasks in this
+	// phase are selected.
+	DependsOn []int `json:"depends_on,omitempty" toml:"depends_on"`
+}
+
+// HasRange reports whether the phase defines a StartTask/EndTask range.
+func (p Phase) HasRange() bool {
+	return p.StartTask != "" || p.EndTask != ""
 }
 
 // IsReady returns true if all dependencies are in the completed set.
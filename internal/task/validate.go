@@ -0,0 +1,725 @@
+package task
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// IssueSeverity classifies a validation issue.
+type IssueSeverity string
+
+const (
+	// SeverityError marks an issue that breaks task selection or the loop.
+	SeverityError IssueSeverity = "error"
+	// SeverityWarning marks an issue that is likely a mistake but is tolerated.
+	SeverityWarning IssueSeverity = "warning"
+)
+
+// Issue codes reported by Validate.
+const (
+	IssueParseError     = "parse-error"
+	IssueFilename       = "filename"
+	IssueIDMismatch     = "id-mismatch"
+	IssueDuplicateID    = "duplicate-id"
+	IssueDanglingDep    = "dangling-dependency"
+	IssueSelfDep        = "self-dependency"
+	IssueCycle          = "cycle"
+	IssueLineEndings    = "line-endings"
+	IssuePhaseError     = "phase-error"
+	IssuePhaseOverlap   = "phase-overlap"
+	IssueUnphasedTask   = "unphased-task"
+	IssueStateError     = "state-error"
+	IssueInvalidStatus  = "invalid-status"
+	IssueOrphanState    = "orphan-state"
+	IssueDuplicateState = "duplicate-state"
+)
+
+// Issue is a single problem found by Validate, located at a file and line.
+type Issue struct {
+	File     string        `json:"file"`
+	Line     int           `json:"line,omitempty"`
+	Severity IssueSeverity `json:"severity"`
+	Code     string        `json:"code"`
+	TaskID   string        `json:"task_id,omitempty"`
+	Message  string        `json:"message"`
+	// Fixable reports whether ValidateOptions.Fix can repair the issue.
+	Fixable bool `json:"fixable,omitempty"`
+	// Fixed reports whether the issue was repaired during this run.
+	Fixed bool `json:"fixed,omitempty"`
+}
+
+// String formats the issue in the conventional "file:line: severity: message
+// (code)" form understood by editors.
+func (i Issue) String() string {
+	loc := i.File
+	if i.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", i.File, i.Line)
+	}
+	s := fmt.Sprintf("%s: %s: %s (%s)", loc, i.Severity, i.Message, i.Code)
+	if i.Fixed {
+		s += " [fixed]"
+	}
+	return s
+}
+
+// ValidateOptions selects the files checked by Validate.
+type ValidateOptions struct {
+	// TasksDir is the directory holding task spec markdown files.
+	TasksDir string
+	// PhasesFile is the phases.conf path. Empty or missing skips phase checks.
+	PhasesFile string
+	// StateFile is the task state file. Empty or missing skips state checks.
+	StateFile string
+	// TaskIDPrefix is the prefix given to bare numeric task bounds in the
+	// phases file. Empty means DefaultTaskIDPrefix.
+	TaskIDPrefix string
+	// Fix applies safe normalisations: renaming misnamed spec files,
+	// normalising line endings, and dropping orphaned or duplicate state rows.
+	Fix bool
+}
+
+// ValidationReport is the result of Validate.
+type ValidationReport struct {
+	// Tasks is the number of task specs that parsed successfully.
+	Tasks int `json:"tasks"`
+	// Issues are sorted by file and line.
+	Issues []Issue `json:"issues"`
+}
+
+// Errors returns the number of unfixed error-severity issues.
+func (r *ValidationReport) Errors() int {
+	return r.count(SeverityError)
+}
+
+// Warnings returns the number of unfixed warning-severity issues.
+func (r *ValidationReport) Warnings() int {
+	return r.count(SeverityWarning)
+}
+
+// Fixed returns the number of issues repaired during validation.
+func (r *ValidationReport) Fixed() int {
+	n := 0
+	for _, i := range r.Issues {
+		if i.Fixed {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *ValidationReport) count(sev IssueSeverity) int {
+	n := 0
+	for _, i := range r.Issues {
+		if i.Severity == sev && !i.Fixed {
+			n++
+		}
+	}
+	return n
+}
+
+var (
+	// reFileTaskID extracts the task ID a spec filename starts with.
+	reFileTaskID = regexp.MustCompile(`^` + taskIDPattern)
+
+	// reFrontMatterDeps matches the dependencies key of a YAML or TOML front
+	// matter block.
+	reFrontMatterDeps = regexp.MustCompile(`^\s*dependencies\s*[:=]`)
+
+	// reSlugUnsafe matches runs of characters not allowed in a spec filename slug.
+	reSlugUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// lintSpec is a task spec file considered by Validate.
+type lintSpec struct {
+	path        string
+	spec        *ParsedTaskSpec
+	headingLine int
+	depsLine    int
+}
+
+// validator accumulates issues for a single Validate run.
+type validator struct {
+	opts   ValidateOptions
+	issues []Issue
+	// parseFailed is set when any spec failed to parse; orphaned state rows
+	// are then not removed because they may belong to the broken spec.
+	parseFailed bool
+}
+
+func (v *validator) add(i Issue) *Issue {
+	v.issues = append(v.issues, i)
+	return &v.issues[len(v.issues)-1]
+}
+
+// Validate checks the task specs in opts.TasksDir together with the phases
+// and state files for problems that otherwise surface as confusing loop
+// behaviour: unparseable or misnamed specs, duplicate IDs, dangling or cyclic
+// dependencies, invalid or overlapping phase ranges, tasks outside every
+// phase, and state rows that are invalid, duplicated, or refer to tasks that
+// do not exist.
+//
+// Problems are reported as issues; the returned error is non-nil only when a
+// file cannot be read or a fix cannot be written.
+func Validate(opts ValidateOptions) (*ValidationReport, error) {
+	v := &validator{opts: opts}
+
+	specs, err := v.checkSpecs()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]*lintSpec, len(specs))
+	for _, s := range specs {
+		known[s.spec.ID] = s
+	}
+	v.checkDependencies(specs, known)
+
+	phases, err := v.checkPhases()
+	if err != nil {
+		return nil, err
+	}
+	if len(phases) > 0 {
+		parsed := make([]*ParsedTaskSpec, 0, len(specs))
+		for _, s := range specs {
+			parsed = append(parsed, s.spec)
+		}
+		membership := NewPhaseMembership(phases, parsed)
+		for _, s := range specs {
+			if membership.PhaseOf(s.spec.ID) == nil {
+				v.add(Issue{
+					File: s.path, Line: s.headingLine, Severity: SeverityWarning, Code: IssueUnphasedTask,
+					TaskID: s.spec.ID, Message: fmt.Sprintf("task %s is not in any phase", s.spec.ID),
+				})
+			}
+		}
+	}
+
+	if err := v.checkState(known); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		a, b := v.issues[i], v.issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return &ValidationReport{Tasks: len(specs), Issues: v.issues}, nil
+}
+
+// checkSpecs parses every markdown file in the tasks directory that is named
+// like a task spec or contains a task heading. It returns the specs with
+// unique IDs, in filename order.
+func (v *validator) checkSpecs() ([]*lintSpec, error) {
+	entries, err := os.ReadDir(v.opts.TasksDir)
+	if err != nil {
+		return nil, fmt.Errorf("validating tasks: reading %q: %w", v.opts.TasksDir, err)
+	}
+
+	var specs []*lintSpec
+	seen := make(map[string]*lintSpec)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") {
+			continue
+		}
+		s, err := v.checkSpecFile(filepath.Join(v.opts.TasksDir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			continue
+		}
+		if first, dup := seen[s.spec.ID]; dup {
+			v.add(Issue{
+				File: s.path, Line: s.headingLine, Severity: SeverityError, Code: IssueDuplicateID, TaskID: s.spec.ID,
+				Message: fmt.Sprintf("duplicate task ID %s (first defined in %s)", s.spec.ID, first.path),
+			})
+			continue
+		}
+		seen[s.spec.ID] = s
+		specs = append(specs, s)
+	}
+
+	sort.SliceStable(specs, func(i, j int) bool {
+		return CompareTaskIDs(specs[i].spec.ID, specs[j].spec.ID) < 0
+	})
+	return specs, nil
+}
+
+// checkSpecFile validates a single markdown file. It returns nil without
+// reporting anything for markdown files that are not task specs (for example
+// INDEX.md or PROGRESS.md).
+func (v *validator) checkSpecFile(path string) (*lintSpec, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("validating tasks: reading %q: %w", path, err)
+	}
+	base := filepath.Base(path)
+	wellNamed := reTaskFilename.MatchString(base)
+
+	content := strings.ReplaceAll(strings.TrimPrefix(string(raw), utf8BOM), "\r\n", "\n")
+	lines := strings.Split(content, "\n")
+
+	if len(raw) > maxTaskFileSize {
+		if wellNamed {
+			v.parseFailed = true
+			v.add(Issue{File: path, Severity: SeverityError, Code: IssueParseError, Message: "task spec exceeds 1 MiB limit"})
+		}
+		return nil, nil
+	}
+
+	spec, err := ParseTaskSpec(content)
+	if err != nil {
+		if !wellNamed {
+			return nil, nil
+		}
+		v.parseFailed = true
+		v.add(Issue{
+			File: path, Line: 1, Severity: SeverityError, Code: IssueParseError,
+			Message: strings.TrimPrefix(err.Error(), "parsing task spec: "),
+		})
+		return nil, nil
+	}
+	spec.SpecFile = path
+
+	s := &lintSpec{path: path, spec: spec, headingLine: 1}
+	for i, line := range lines {
+		if reTitleLine.MatchString(line) {
+			s.headingLine = i + 1
+			break
+		}
+	}
+	s.depsLine = s.headingLine
+	for i, line := range lines {
+		if (strings.HasPrefix(strings.TrimSpace(line), "|") && reMetaDeps.MatchString(line)) ||
+			(spec.HasFrontMatter && reFrontMatterDeps.MatchString(line)) {
+			s.depsLine = i + 1
+			break
+		}
+	}
+
+	if !bytes.Equal(raw, []byte(content)) {
+		issue := v.add(Issue{
+			File: path, Line: 1, Severity: SeverityWarning, Code: IssueLineEndings, TaskID: spec.ID,
+			Message: "spec has a byte-order mark or Windows line endings", Fixable: true,
+		})
+		if v.opts.Fix {
+			if err := rewriteFile(path, []byte(content)); err != nil {
+				return nil, fmt.Errorf("fixing %q: %w", path, err)
+			}
+			issue.Fixed = true
+		}
+	}
+
+	if !wellNamed {
+		s.path = v.checkSpecFilename(s)
+	} else if fileID := reFileTaskID.FindString(base); fileID != spec.ID {
+		v.add(Issue{
+			File: path, Line: s.headingLine, Severity: SeverityError, Code: IssueIDMismatch, TaskID: spec.ID,
+			Message: fmt.Sprintf("file name says %s but the spec declares %s", fileID, spec.ID),
+		})
+	}
+	return s, nil
+}
+
+// checkSpecFilename reports a spec whose filename is not discovered by
+// DiscoverTasks and, when fixing, renames it to "<ID>-<slug>.md". It returns
+// the spec's path after any rename.
+func (v *validator) checkSpecFilename(s *lintSpec) string {
+	target := filepath.Join(filepath.Dir(s.path), specFileName(s.spec.ID, filepath.Base(s.path), s.spec.Title))
+	issue := v.add(Issue{
+		File: s.path, Line: s.headingLine, Severity: SeverityWarning, Code: IssueFilename, TaskID: s.spec.ID,
+		Message: fmt.Sprintf("file name does not match <ID>-<slug>.md, so task %s is never discovered; expected %s",
+			s.spec.ID, filepath.Base(target)),
+		Fixable: true,
+	})
+	if !v.opts.Fix {
+		return s.path
+	}
+	if _, err := os.Stat(target); err == nil {
+		return s.path
+	}
+	if err := os.Rename(s.path, target); err != nil {
+		return s.path
+	}
+	issue.Fixed = true
+	return target
+}
+
+// specFileName builds "<ID>-<slug>.md" for a misnamed spec file, deriving the
+// slug from the old filename and falling back to the title.
+func specFileName(id, oldBase, title string) string {
+	stem := strings.TrimSuffix(oldBase, filepath.Ext(oldBase))
+	if fileID := reFileTaskID.FindString(stem); fileID == id {
+		stem = stem[len(fileID):]
+	}
+	slug := strings.Trim(reSlugUnsafe.ReplaceAllString(strings.ToLower(stem), "-"), "-")
+	if slug == "" {
+		slug = strings.Trim(reSlugUnsafe.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	}
+	if slug == "" {
+		slug = "task"
+	}
+	return id + "-" + slug + ".md"
+}
+
+// checkDependencies reports self, dangling, and cyclic dependencies.
+func (v *validator) checkDependencies(specs []*lintSpec, known map[string]*lintSpec) {
+	for _, s := range specs {
+		for _, dep := range s.spec.Dependencies {
+			switch {
+			case dep == s.spec.ID:
+				v.add(Issue{
+					File: s.path, Line: s.depsLine, Severity: SeverityError, Code: IssueSelfDep, TaskID: s.spec.ID,
+					Message: fmt.Sprintf("task %s depends on itself", s.spec.ID),
+				})
+			case known[dep] == nil:
+				v.add(Issue{
+					File: s.path, Line: s.depsLine, Severity: SeverityError, Code: IssueDanglingDep, TaskID: s.spec.ID,
+					Message: fmt.Sprintf("task %s depends on %s, which does not exist", s.spec.ID, dep),
+				})
+			}
+		}
+	}
+
+	for _, cycle := range dependencyCycles(specs, known) {
+		first := known[cycle[0]]
+		v.add(Issue{
+			File: first.path, Line: first.depsLine, Severity: SeverityError, Code: IssueCycle, TaskID: first.spec.ID,
+			Message: fmt.Sprintf("dependency cycle among tasks %s", strings.Join(cycle, ", ")),
+		})
+	}
+}
+
+// dependencyCycles returns the strongly connected components with more than
+// one task (Tarjan's algorithm), each sorted by task ID. Self-dependencies
+// and dangling references are ignored.
+func dependencyCycles(specs []*lintSpec, known map[string]*lintSpec) [][]string {
+	index := make(map[string]int, len(specs))
+	low := make(map[string]int, len(specs))
+	onStack := make(map[string]bool, len(specs))
+	var stack []string
+	var cycles [][]string
+	next := 0
+
+	var visit func(id string)
+	visit = func(id string) {
+		index[id], low[id] = next, next
+		next++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, dep := range known[id].spec.Dependencies {
+			if dep == id || known[dep] == nil {
+				continue
+			}
+			if _, seen := index[dep]; !seen {
+				visit(dep)
+				low[id] = min(low[id], low[dep])
+			} else if onStack[dep] {
+				low[id] = min(low[id], index[dep])
+			}
+		}
+
+		if low[id] != index[id] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == id {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Slice(component, func(i, j int) bool {
+				return CompareTaskIDs(component[i], component[j]) < 0
+			})
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, s := range specs {
+		if _, seen := index[s.spec.ID]; !seen {
+			visit(s.spec.ID)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool {
+		return CompareTaskIDs(cycles[i][0], cycles[j][0]) < 0
+	})
+	return cycles
+}
+
+// checkPhases parses the phases file line by line and reports malformed
+// lines, invalid ranges, duplicate phase IDs, and overlapping ranges. It
+// returns the phases that parsed.
+func (v *validator) checkPhases() ([]Phase, error) {
+	path := v.opts.PhasesFile
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("validating phases: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	var phases []Phase
+	lineOf := make(map[int]int) // phase ID -> line
+	if IsPhasesTOML(path) {
+		phases, err = v.checkPhasesTOML(path, f)
+		if err != nil {
+			return nil, err
+		}
+		return v.checkPhaseRelations(path, phases, lineOf), nil
+	}
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		trimmed := strings.TrimSpace(scanner.Text())
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		p, err := parsePhaseLine(trimmed, v.opts.TaskIDPrefix)
+		if err != nil {
+			v.add(Issue{
+				File: path, Line: lineNum, Severity: SeverityError, Code: IssuePhaseError,
+				Message: strings.TrimPrefix(err.Error(), "parsing phase line: "),
+			})
+			continue
+		}
+		if first, dup := lineOf[p.ID]; dup {
+			v.add(Issue{
+				File: path, Line: lineNum, Severity: SeverityError, Code: IssuePhaseError,
+				Message: fmt.Sprintf("duplicate phase ID %d (first defined on line %d)", p.ID, first),
+			})
+			continue
+		}
+		if err := ValidatePhases([]Phase{*p}); err != nil {
+			v.add(Issue{
+				File: path, Line: lineNum, Severity: SeverityError, Code: IssuePhaseError,
+				Message: strings.TrimPrefix(err.Error(), "validating phases: "),
+			})
+			continue
+		}
+		lineOf[p.ID] = lineNum
+		phases = append(phases, *p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("validating phases: scanning %q: %w", path, err)
+	}
+
+	return v.checkPhaseRelations(path, phases, lineOf), nil
+}
+
+// checkPhasesTOML parses a TOML phases file and checks each phase on its
+// own. TOML phases carry no line numbers, so issues point at the file.
+func (v *validator) checkPhasesTOML(path string, r io.Reader) ([]Phase, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxPhasesFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("validating phases: reading %q: %w", path, err)
+	}
+	parsed, err := ParsePhasesTOML(data)
+	if err != nil {
+		v.add(Issue{
+			File: path, Severity: SeverityError, Code: IssuePhaseError,
+			Message: strings.TrimPrefix(err.Error(), "parsing phases TOML: "),
+		})
+		return nil, nil
+	}
+
+	var phases []Phase
+	seen := make(map[int]bool, len(parsed))
+	for _, p := range parsed {
+		if seen[p.ID] {
+			v.add(Issue{
+				File: path, Severity: SeverityError, Code: IssuePhaseError,
+				Message: fmt.Sprintf("duplicate phase ID %d", p.ID),
+			})
+			continue
+		}
+		// Dependencies refer to other phases; they are checked together
+		// in checkPhaseRelations.
+		alone := p
+		alone.DependsOn = nil
+		if err := ValidatePhases([]Phase{alone}); err != nil {
+			v.add(Issue{
+				File: path, Severity: SeverityError, Code: IssuePhaseError,
+				Message: strings.TrimPrefix(err.Error(), "validating phases: "),
+			})
+			continue
+		}
+		seen[p.ID] = true
+		phases = append(phases, p)
+	}
+	return phases, nil
+}
+
+// checkPhaseRelations reports problems between valid phases: overlapping
+// ranges, tasks listed by two phases and invalid phase dependencies. It
+// returns the phases sorted by ID.
+func (v *validator) checkPhaseRelations(path string, phases []Phase, lineOf map[int]int) []Phase {
+	for i := range phases {
+		if !phases[i].HasRange() {
+			continue
+		}
+		for j := i + 1; j < len(phases); j++ {
+			a, b := phases[i], phases[j]
+			if !b.HasRange() {
+				continue
+			}
+			aStart, aEnd, _ := phaseBounds(a)
+			bStart, bEnd, _ := phaseBounds(b)
+			if aStart.Prefix != bStart.Prefix || aEnd.Number < bStart.Number || bEnd.Number < aStart.Number {
+				continue
+			}
+			v.add(Issue{
+				File: path, Line: lineOf[b.ID], Severity: SeverityError, Code: IssuePhaseOverlap,
+				Message: fmt.Sprintf("phase %d (%s-%s) overlaps with phase %d (%s-%s) on line %d",
+					b.ID, b.StartTask, b.EndTask, a.ID, a.StartTask, a.EndTask, lineOf[a.ID]),
+			})
+		}
+	}
+
+	listedBy := make(map[string]int)
+	known := make(map[int]bool, len(phases))
+	for _, p := range phases {
+		known[p.ID] = true
+		for _, id := range p.Tasks {
+			if isTaskGlob(id) {
+				continue
+			}
+			if other, dup := listedBy[id]; dup {
+				v.add(Issue{
+					File: path, Line: lineOf[p.ID], Severity: SeverityError, Code: IssuePhaseOverlap,
+					TaskID: id, Message: fmt.Sprintf("task %s is listed by both phase %d and phase %d", id, other, p.ID),
+				})
+				continue
+			}
+			listedBy[id] = p.ID
+		}
+	}
+	if err := validatePhaseDependencies(phases, known); err != nil {
+		v.add(Issue{File: path, Severity: SeverityError, Code: IssuePhaseError, Message: err.Error()})
+	}
+
+	sort.Slice(phases, func(i, j int) bool { return phases[i].ID < phases[j].ID })
+	return phases
+}
+
+// checkState reports malformed rows, invalid statuses, duplicate rows, and
+// rows for tasks without a spec. When fixing, duplicate rows (the last one
+// wins, as in StateManager.LoadMap) and orphaned rows are dropped and the
+// file is rewritten in the canonical format.
+func (v *validator) checkState(known map[string]*lintSpec) error {
+	path := v.opts.StateFile
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("validating task state: %w", err)
+	}
+
+	type row struct {
+		state TaskState
+		line  int
+	}
+	var rows []row
+	lastRow := make(map[string]int) // task ID -> index in rows
+	malformed := false
+	for i, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		lineNum := i + 1
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		st, err := parseLine(trimmed)
+		if err != nil {
+			malformed = true
+			v.add(Issue{File: path, Line: lineNum, Severity: SeverityError, Code: IssueStateError,
+				Message: strings.TrimPrefix(err.Error(), "invalid state line: ")})
+			continue
+		}
+		if !ValidStatus(st.Status) {
+			v.add(Issue{File: path, Line: lineNum, Severity: SeverityError, Code: IssueInvalidStatus, TaskID: st.TaskID,
+				Message: fmt.Sprintf("task %s has invalid status %q", st.TaskID, st.Status)})
+		}
+		if prev, dup := lastRow[st.TaskID]; dup {
+			v.add(Issue{File: path, Line: rows[prev].line, Severity: SeverityWarning, Code: IssueDuplicateState,
+				TaskID: st.TaskID, Fixable: true,
+				Message: fmt.Sprintf("task %s has another state row on line %d, which takes precedence", st.TaskID, lineNum)})
+		}
+		lastRow[st.TaskID] = len(rows)
+		rows = append(rows, row{state: *st, line: lineNum})
+	}
+
+	orphanFixable := !v.parseFailed
+	for _, r := range rows {
+		if known[r.state.TaskID] == nil {
+			v.add(Issue{File: path, Line: r.line, Severity: SeverityWarning, Code: IssueOrphanState,
+				TaskID: r.state.TaskID, Fixable: orphanFixable,
+				Message: fmt.Sprintf("state row for task %s, which has no spec", r.state.TaskID)})
+		}
+	}
+
+	if !v.opts.Fix || malformed {
+		return nil
+	}
+	var kept []TaskState
+	changed := false
+	for i, r := range rows {
+		if lastRow[r.state.TaskID] != i || (orphanFixable && known[r.state.TaskID] == nil) {
+			changed = true
+			continue
+		}
+		kept = append(kept, r.state)
+	}
+	if !changed {
+		return nil
+	}
+	sm := NewStateManager(path)
+	sm.mu.Lock()
+	unlock, err := sm.lockFile()
+	if err == nil {
+		err = sm.writeAtomic(kept)
+		unlock()
+	}
+	sm.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("fixing task state: %w", err)
+	}
+	for i := range v.issues {
+		is := &v.issues[i]
+		if is.File == path && (is.Code == IssueDuplicateState || (is.Code == IssueOrphanState && is.Fixable)) {
+			is.Fixed = true
+		}
+	}
+	return nil
+}
+
+// rewriteFile replaces the contents of path, keeping its permissions.
+func rewriteFile(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, info.Mode().Perm())
+}
@@ -0,0 +1,259 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lintSpecContent returns a minimal task spec with a metadata table.
+func lintSpecContent(id, title, deps string) string {
+	return "# " + id + ": " + title + "\n\n## Metadata\n| Field | Value |\n|-------|-------|\n| Dependencies | " + deps + " |\n"
+}
+
+// writeLintProject writes files (relative to a temp dir) and returns the
+// options pointing at its tasks dir, phases.conf, and task-state.conf.
+func writeLintProject(t *testing.T, files map[string]string) ValidateOptions {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "tasks"), 0o755))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return ValidateOptions{
+		TasksDir:   filepath.Join(dir, "tasks"),
+		PhasesFile: filepath.Join(dir, "phases.conf"),
+		StateFile:  filepath.Join(dir, "task-state.conf"),
+	}
+}
+
+// issueCodes returns "code@base:line" for each issue for compact assertions.
+func issueCodes(r *ValidationReport) []string {
+	out := make([]string, 0, len(r.Issues))
+	for _, i := range r.Issues {
+		out = append(out, fmt.Sprintf("%s@%s:%d", i.Code, filepath.Base(i.File), i.Line))
+	}
+	return out
+}
+
+func TestValidate_Clean(t *testing.T) {
+	t.Parallel()
+
+	opts := writeLintProject(t, map[string]string{
+		"tasks/T-001-setup.md": lintSpecContent("T-001", "Setup", "None"),
+		"tasks/T-002-core.md":  lintSpecContent("T-002", "Core", "T-001"),
+		"tasks/INDEX.md":       "# Task Index\n",
+		"phases.conf":          "1|Foundation|T-001|T-002\n",
+		"task-state.conf":      "T-001|completed|claude||\n",
+	})
+
+	report, err := Validate(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Tasks)
+	assert.Empty(t, report.Issues)
+}
+
+func TestValidate_Specs(t *testing.T) {
+	t.Parallel()
+
+	opts := writeLintProject(t, map[string]string{
+		"tasks/T-001-setup.md":  lintSpecContent("T-001", "Setup", "T-003"),
+		"tasks/T-002-core.md":   lintSpecContent("T-002", "Core", "T-002, T-009"),
+		"tasks/T-003-cli.md":    lintSpecContent("T-003", "CLI", "T-001"),
+		"tasks/T-004-dup.md":    lintSpecContent("T-003", "Dup", "None"),
+		"tasks/T-005-broken.md": "no heading here\n",
+		"tasks/notes-t6.md":     lintSpecContent("T-006", "Misnamed", "None"),
+	})
+
+	report, err := Validate(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"cycle@T-001-setup.md:6",
+		"self-dependency@T-002-core.md:6",
+		"dangling-dependency@T-002-core.md:6",
+		"id-mismatch@T-004-dup.md:1",
+		"duplicate-id@T-004-dup.md:1",
+		"parse-error@T-005-broken.md:1",
+		"filename@notes-t6.md:1",
+	}, issueCodes(report))
+	assert.Contains(t, report.Issues[0].Message, "dependency cycle among tasks T-001, T-003")
+	assert.Contains(t, report.Issues[6].Message, "expected T-006-notes-t6.md")
+	assert.Equal(t, 6, report.Errors())
+	assert.Equal(t, 1, report.Warnings())
+}
+
+func TestValidate_FrontMatterDependencyLine(t *testing.T) {
+	t.Parallel()
+
+	opts := writeLintProject(t, map[string]string{
+		"tasks/T-001-setup.md": "---\nid: T-001\ntitle: Setup\ndependencies: [T-042]\n---\nBody.\n",
+	})
+
+	report, err := Validate(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dangling-dependency@T-001-setup.md:4"}, issueCodes(report))
+}
+
+func TestValidate_Phases(t *testing.T) {
+	t.Parallel()
+
+	opts := writeLintProject(t, map[string]string{
+		"tasks/T-001-setup.md": lintSpecContent("T-001", "Setup", "None"),
+		"tasks/T-002-core.md":  lintSpecContent("T-002", "Core", "None"),
+		"tasks/T-009-late.md":  lintSpecContent("T-009", "Late", "None"),
+		"phases.conf": "# id|name|start|end\n" +
+			"1|Foundation|T-001|T-002\n" +
+			"2|Overlap|T-002|T-003\n" +
+			"1|Again|T-004|T-005\n" +
+			"3|Backwards|T-008|T-007\n" +
+			"x|Bad|T-001|T-002\n",
+	})
+
+	report, err := Validate(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"phase-overlap@phases.conf:3",
+		"phase-error@phases.conf:4",
+		"phase-error@phases.conf:5",
+		"phase-error@phases.conf:6",
+		"unphased-task@T-009-late.md:1",
+	}, issueCodes(report))
+}
+
+func TestValidate_PhasesTOML(t *testing.T) {
+	t.Parallel()
+
+	opts := writeLintProject(t, map[string]string{
+		"tasks/T-001-setup.md": lintSpecContent("T-001", "Setup", "None"),
+		"tasks/T-002-core.md":  lintSpecContent("T-002", "Core", "None"),
+		"tasks/T-009-late.md":  lintSpecContent("T-009", "Late", "None"),
+		"phases.toml": `[[phase]]
+id = 1
+name = "Foundation"
+tasks = ["T-001", "T-002"]
+
+[[phase]]
+id = 2
+name = "Again"
+tasks = ["T-002"]
+depends_on = [3]
+
+[[phase]]
+id = 3
+name = "Bad glob"
+tasks = ["T-[0"]
+`,
+	})
+	opts.PhasesFile = filepath.Join(filepath.Dir(opts.TasksDir), "phases.toml")
+
+	report, err := Validate(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"phase-error@phases.toml:0",
+		"phase-overlap@phases.toml:0",
+		"phase-error@phases.toml:0",
+		"unphased-task@T-009-late.md:1",
+	}, issueCodes(report))
+
+	var messages []string
+	for _, i := range report.Issues {
+		messages = append(messages, i.Message)
+	}
+	assert.Contains(t, messages, "task T-002 is listed by both phase 1 and phase 2")
+	assert.Contains(t, messages, "phase 2 depends on unknown phase 3")
+}
+
+func TestValidate_State(t *testing.T) {
+	t.Parallel()
+
+	opts := writeLintProject(t, map[string]string{
+		"tasks/T-001-setup.md": lintSpecContent("T-001", "Setup", "None"),
+		"task-state.conf": "T-001|in_progress|claude||\n" +
+			"T-404|completed|claude||\n" +
+			"T-001|done|claude||\n",
+	})
+
+	report, err := Validate(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"duplicate-state@task-state.conf:1",
+		"orphan-state@task-state.conf:2",
+		"invalid-status@task-state.conf:3",
+	}, issueCodes(report))
+}
+
+func TestValidate_Fix(t *testing.T) {
+	t.Parallel()
+
+	opts := writeLintProject(t, map[string]string{
+		"tasks/T-001-setup.md": "\xef\xbb\xbf" + strings.ReplaceAll(lintSpecContent("T-001", "Setup", "None"), "\n", "\r\n"),
+		"tasks/T-002.md":       lintSpecContent("T-002", "Core Work", "T-001"),
+		"task-state.conf": "T-001|in_progress|claude||\n" +
+			"T-404|completed|claude||\n" +
+			"T-001|completed|claude||\n",
+	})
+	opts.Fix = true
+
+	report, err := Validate(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Fixed())
+	assert.Equal(t, 0, report.Errors()+report.Warnings())
+
+	data, err := os.ReadFile(filepath.Join(opts.TasksDir, "T-001-setup.md"))
+	require.NoError(t, err)
+	assert.Equal(t, lintSpecContent("T-001", "Setup", "None"), string(data))
+
+	assert.FileExists(t, filepath.Join(opts.TasksDir, "T-002-core-work.md"))
+	specs, err := DiscoverTasks(opts.TasksDir)
+	require.NoError(t, err)
+	assert.Len(t, specs, 2)
+
+	states, err := NewStateManager(opts.StateFile).Load()
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	assert.Equal(t, StatusCompleted, states[0].Status)
+
+	report, err = Validate(ValidateOptions{TasksDir: opts.TasksDir, StateFile: opts.StateFile})
+	require.NoError(t, err)
+	assert.Empty(t, report.Issues, "a second run finds nothing")
+}
+
+func TestValidate_FixKeepsOrphansWhenSpecsBroken(t *testing.T) {
+	t.Parallel()
+
+	opts := writeLintProject(t, map[string]string{
+		"tasks/T-001-setup.md": "not a spec\n",
+		"task-state.conf":      "T-001|completed|claude||\n",
+	})
+	opts.Fix = true
+
+	report, err := Validate(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Fixed())
+
+	states, err := NewStateManager(opts.StateFile).Load()
+	require.NoError(t, err)
+	assert.Len(t, states, 1)
+}
+
+func TestValidate_MissingTasksDir(t *testing.T) {
+	t.Parallel()
+
+	_, err := Validate(ValidateOptions{TasksDir: filepath.Join(t.TempDir(), "missing")})
+	require.Error(t, err)
+}
+
+func TestIssue_String(t *testing.T) {
+	t.Parallel()
+
+	i := Issue{File: "tasks/T-001-a.md", Line: 7, Severity: SeverityError, Code: IssueDanglingDep, Message: "task T-001 depends on T-009, which does not exist"}
+	assert.Equal(t, "tasks/T-001-a.md:7: error: task T-001 depends on T-009, which does not exist (dangling-dependency)", i.String())
+
+	i = Issue{File: "task-state.conf", Severity: SeverityWarning, Code: IssueOrphanState, Message: "x", Fixed: true}
+	assert.Equal(t, "task-state.conf: warning: x (orphan-state) [fixed]", i.String())
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/AbdelazizMoustafa10m/Raven/internal/logging"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/loop"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/workflow"
 )
 
@@ -55,6 +56,12 @@ type AppConfig struct {
 
 	// Engine is the workflow engine reference. May be nil in idle mode.
 	Engine *workflow.Engine
+
+	// Phases resolves which tasks belong to which phase for the sidebar's
+	// phase progress. May be nil when no phases are configured.
+	Phases *task.PhaseMembership
+	// CompletedTasks lists the IDs of tasks already completed at launch.
+	CompletedTasks []string
 }
 
 // PipelineStartMsg is dispatched when the wizard completes to trigger
@@ -120,6 +127,9 @@ func NewApp(cfg AppConfig) App {
 
 	sidebar := NewSidebarModel(theme)
 	sidebar.SetFocused(true)
+	if cfg.Phases != nil {
+		sidebar.SetPhaseMembership(cfg.Phases, cfg.CompletedTasks)
+	}
 
 	ctx := cfg.Ctx
 	if ctx == nil {
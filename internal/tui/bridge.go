@@ -96,11 +96,16 @@ func convertLoopEvent(ev loop.LoopEvent) tea.Msg {
 		}
 	}
 
+	detail := ev.Message
+	if ev.Reason != "" {
+		// Task selections carry the selection strategy's reasoning.
+		detail = ev.Message + " (" + ev.Reason + ")"
+	}
 	return LoopEventMsg{
 		Type:      mapLoopEventType(ev.Type),
 		TaskID:    ev.TaskID,
 		Iteration: ev.Iteration,
-		Detail:    ev.Message,
+		Detail:    detail,
 		Timestamp: ev.Timestamp,
 	}
 }
@@ -178,6 +178,27 @@ func TestEventBridge_LoopEventCmd_NormalEventConvertsToLoopEventMsg(t *testing.T
 	assert.Equal(t, "task done", loopMsg.Detail)
 }
 
+// TestEventBridge_LoopEventCmd_TaskSelectedIncludesReason verifies that the
+// selection reason is appended to the event detail.
+func TestEventBridge_LoopEventCmd_TaskSelectedIncludesReason(t *testing.T) {
+	t.Parallel()
+
+	b := NewEventBridge()
+	ch := make(chan loop.LoopEvent, 1)
+	ch <- loop.LoopEvent{
+		Type:    loop.EventTaskSelected,
+		TaskID:  "T-002",
+		Message: "Add retries",
+		Reason:  "highest priority (must-have) of 3 ready",
+	}
+
+	msg := b.LoopEventCmd(context.Background(), ch)()
+	loopMsg, ok := msg.(LoopEventMsg)
+	require.True(t, ok, "expected LoopEventMsg, got %T", msg)
+	assert.Equal(t, LoopTaskSelected, loopMsg.Type)
+	assert.Equal(t, "Add retries (highest priority (must-have) of 3 ready)", loopMsg.Detail)
+}
+
 // TestEventBridge_LoopEventCmd_ClosedChannel verifies that the command
 // returns nil when the loop event channel is closed.
 func TestEventBridge_LoopEventCmd_ClosedChannel(t *testing.T) {
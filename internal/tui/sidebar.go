@@ -7,6 +7,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
 )
 
 // ---------------------------------------------------------------------------
@@ -106,6 +108,13 @@ type TaskProgressSection struct {
 	totalPhases    int
 	phaseTasks     int // Total tasks in the current phase.
 	phaseCompleted int // Completed tasks in the current phase.
+
+	// membership, when set, places completed tasks in their phase instead
+	// of assuming phases finish in order. done records the completed task
+	// IDs; both maps are replaced, never mutated, so copies stay independent.
+	membership *task.PhaseMembership
+	done       map[string]bool
+	phaseDone  map[int]int
 }
 
 // NewTaskProgressSection creates a TaskProgressSection with the given theme
@@ -144,12 +153,89 @@ func (tp *TaskProgressSection) SetPhase(phase, phaseTasks, phaseCompleted int) {
 	tp.phaseCompleted = phaseCompleted
 }
 
+// SetPhaseMembership configures the phases shown by the section from m and
+// the IDs of the tasks already completed. The current phase becomes the first
+// phase with an incomplete task. Phases may be defined by ranges, task lists
+// or labels; completed tasks are counted towards the phase they belong to.
+func (tp *TaskProgressSection) SetPhaseMembership(m *task.PhaseMembership, completedTaskIDs []string) {
+	tp.membership = m
+	tp.done = make(map[string]bool, len(completedTaskIDs))
+	tp.phaseDone = make(map[int]int)
+	for _, id := range completedTaskIDs {
+		if tp.done[id] {
+			continue
+		}
+		tp.done[id] = true
+		if ph := m.PhaseOf(id); ph != nil {
+			tp.phaseDone[ph.ID]++
+		}
+	}
+
+	phases := m.Phases()
+	tp.totalPhases = len(phases)
+	tp.currentPhase = 0
+	for i, ph := range phases {
+		if tp.phaseDone[ph.ID] < len(m.Tasks(ph.ID)) {
+			tp.showPhase(i)
+			return
+		}
+	}
+	if len(phases) > 0 {
+		tp.showPhase(len(phases) - 1)
+	}
+}
+
+// showPhase makes the phase at index i of the membership's phases current.
+func (tp *TaskProgressSection) showPhase(i int) {
+	ph := tp.membership.Phases()[i]
+	tp.currentPhase = i + 1
+	tp.phaseTasks = len(tp.membership.Tasks(ph.ID))
+	tp.phaseCompleted = tp.phaseDone[ph.ID]
+}
+
+// completeTask records taskID as completed under the phase membership and
+// switches the current phase to the task's phase.
+func (tp *TaskProgressSection) completeTask(taskID string) {
+	if tp.done[taskID] {
+		return
+	}
+
+	done := make(map[string]bool, len(tp.done)+1)
+	for id := range tp.done {
+		done[id] = true
+	}
+	done[taskID] = true
+	tp.done = done
+	if tp.completedTasks < tp.totalTasks {
+		tp.completedTasks++
+	}
+	ph := tp.membership.PhaseOf(taskID)
+	if ph == nil {
+		return
+	}
+
+	phaseDone := make(map[int]int, len(tp.phaseDone)+1)
+	for id, n := range tp.phaseDone {
+		phaseDone[id] = n
+	}
+	phaseDone[ph.ID]++
+	tp.phaseDone = phaseDone
+	for i, p := range tp.membership.Phases() {
+		if p.ID == ph.ID {
+			tp.showPhase(i)
+			break
+		}
+	}
+}
+
 // Update processes tea.Msg values relevant to task progress and returns the
 // updated section. Handled messages:
 //   - TaskProgressMsg     — updates overall completedTasks / totalTasks from
 //     the message's Completed / Total fields.
 //   - LoopEventMsg        — LoopPhaseComplete increments currentPhase and
 //     resets phaseCompleted; LoopTaskCompleted increments phaseCompleted.
+//     With a phase membership set, a completed task is instead counted
+//     towards its own phase, which becomes the current phase.
 func (tp TaskProgressSection) Update(msg tea.Msg) TaskProgressSection {
 	switch msg := msg.(type) {
 	case TaskProgressMsg:
@@ -171,9 +257,17 @@ func (tp TaskProgressSection) Update(msg tea.Msg) TaskProgressSection {
 	case LoopEventMsg:
 		switch msg.Type {
 		case LoopPhaseComplete:
+			if tp.membership != nil && tp.currentPhase < tp.totalPhases {
+				tp.showPhase(tp.currentPhase)
+				break
+			}
 			tp.currentPhase++
 			tp.phaseCompleted = 0
 		case LoopTaskCompleted:
+			if tp.membership != nil && msg.TaskID != "" {
+				tp.completeTask(msg.TaskID)
+				break
+			}
 			tp.phaseCompleted++
 			// Also increment the overall completed count to stay in sync when
 			// the overall total has been set via SetTotals but no TaskProgressMsg
@@ -563,6 +657,13 @@ func (m *SidebarModel) SetTotals(totalTasks, totalPhases int) {
 	m.taskProgress.SetTotals(totalTasks, totalPhases)
 }
 
+// SetPhaseMembership configures per-phase progress from the phase membership
+// and the IDs of already completed tasks. It delegates to
+// TaskProgressSection.SetPhaseMembership.
+func (m *SidebarModel) SetPhaseMembership(membership *task.PhaseMembership, completedTaskIDs []string) {
+	m.taskProgress.SetPhaseMembership(membership, completedTaskIDs)
+}
+
 // SetPhase updates the current phase number and its task counts in the task
 // progress section. It delegates to TaskProgressSection.SetPhase.
 func (m *SidebarModel) SetPhase(phase, phaseTasks, phaseCompleted int) {
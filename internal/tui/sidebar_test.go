@@ -10,6 +10,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/task"
 )
 
 // stripANSISidebar removes ANSI escape sequences from a string so tests can
@@ -717,6 +719,53 @@ func TestTaskProgressSection_Update_LoopEventMsg_TaskCompleted_ClampedAtTotal(t
 	assert.Equal(t, 5, tp.completedTasks, "completedTasks must not exceed totalTasks")
 }
 
+func TestTaskProgressSection_PhaseMembership(t *testing.T) {
+	t.Parallel()
+
+	phases := []task.Phase{
+		{ID: 1, Name: "Foundation", StartTask: "T-001", EndTask: "T-002"},
+		{ID: 2, Name: "API", Labels: []string{"api"}},
+	}
+	specs := []*task.ParsedTaskSpec{
+		{ID: "T-001"},
+		{ID: "T-002"},
+		{ID: "T-003", Labels: []string{"api"}},
+		{ID: "T-004", Labels: []string{"api"}},
+	}
+
+	tp := NewTaskProgressSection(DefaultTheme())
+	tp.totalTasks = 4
+	tp.completedTasks = 1
+	tp.SetPhaseMembership(task.NewPhaseMembership(phases, specs), []string{"T-001"})
+
+	assert.Equal(t, 2, tp.totalPhases)
+	assert.Equal(t, 1, tp.currentPhase)
+	assert.Equal(t, 2, tp.phaseTasks)
+	assert.Equal(t, 1, tp.phaseCompleted)
+
+	// A task completing in another phase switches to that phase.
+	before := tp
+	tp = tp.Update(LoopEventMsg{Type: LoopTaskCompleted, TaskID: "T-004"})
+	assert.Equal(t, 2, tp.currentPhase)
+	assert.Equal(t, 2, tp.phaseTasks)
+	assert.Equal(t, 1, tp.phaseCompleted)
+	assert.Equal(t, 2, tp.completedTasks)
+	assert.False(t, before.done["T-004"], "updates must not mutate earlier copies")
+
+	// Repeated completions are counted once.
+	tp = tp.Update(LoopEventMsg{Type: LoopTaskCompleted, TaskID: "T-004"})
+	assert.Equal(t, 1, tp.phaseCompleted)
+	assert.Equal(t, 2, tp.completedTasks)
+
+	tp = tp.Update(LoopEventMsg{Type: LoopTaskCompleted, TaskID: "T-002"})
+	assert.Equal(t, 1, tp.currentPhase)
+	assert.Equal(t, 2, tp.phaseCompleted)
+
+	tp = tp.Update(LoopEventMsg{Type: LoopPhaseComplete})
+	assert.Equal(t, 2, tp.currentPhase)
+	assert.Equal(t, 1, tp.phaseCompleted)
+}
+
 func TestTaskProgressSection_Update_UnhandledMsg_NoChange(t *testing.T) {
 	t.Parallel()
 	tp := NewTaskProgressSection(DefaultTheme())
@@ -205,11 +205,27 @@ type HandlerDeps struct {
 	// ReviewHandler.
 	ReviewOrchestrator *review.ReviewOrchestrator
 
+	// ReviewPersonas are the reviewer personas ReviewHandler runs when the
+	// workflow state does not name review agents.
+	ReviewPersonas []review.Persona
+
+	// ReviewAnalyzers are the static analyzers ReviewHandler runs alongside
+	// the review agents.
+	ReviewAnalyzers []review.Analyzer
+
+	// ReviewLedgers stores the per-branch finding ledgers ReviewHandler
+	// records each review in.
+	ReviewLedgers *review.ReviewStateStore
+
 	// FixEngine is the review fix engine used by FixHandler.
 	FixEngine *review.FixEngine
 
 	// PRCreator is the GitHub PR creation helper used by PRHandler.
 	PRCreator *review.PRCreator
+
+	// ReviewPublisher posts the last review to the PR created by PRHandler
+	// when the workflow state has publish_review set.
+	ReviewPublisher *review.ReviewPublisher
 }
 
 // RegisterBuiltinHandlers registers all built-in step handlers into registry,
@@ -232,13 +248,17 @@ func RegisterBuiltinHandlers(registry *Registry, deps *HandlerDeps) {
 	})
 	registry.Register(&ReviewHandler{
 		Orchestrator: deps.ReviewOrchestrator,
+		Personas:     deps.ReviewPersonas,
+		Analyzers:    deps.ReviewAnalyzers,
+		Ledgers:      deps.ReviewLedgers,
 	})
 	registry.Register(&CheckReviewHandler{})
 	registry.Register(&FixHandler{
 		Engine: deps.FixEngine,
 	})
 	registry.Register(&PRHandler{
-		Creator: deps.PRCreator,
+		Creator:   deps.PRCreator,
+		Publisher: deps.ReviewPublisher,
 	})
 	registry.Register(&InitPhaseHandler{})
 	registry.Register(&RunPhaseWorkflowHandler{
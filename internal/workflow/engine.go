@@ -338,7 +338,7 @@ func (e *Engine) safeExecute(ctx context.Context, handler StepHandler, state *Wo
 			err = fmt.Errorf("engine: step %q panicked: %v", stepName, r)
 		}
 	}()
-	return handler.Execute(ctx, state)
+	return handler.Execute(withCheckpointHook(ctx, e.postStepHook), state)
 }
 
 // emit sends ev to the event channel using a non-blocking select so that a
@@ -7,8 +7,10 @@ package workflow
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/AbdelazizMoustafa10m/Raven/internal/loop"
 	"github.com/AbdelazizMoustafa10m/Raven/internal/review"
@@ -54,7 +56,9 @@ func (h *ImplementHandler) Name() string { return "run_implement" }
 // Execute runs the implementation loop using configuration values merged from
 // the workflow state metadata and h.RunConfig defaults. It calls
 // Runner.RunSingleTask when a task_id is present in the metadata; otherwise
-// it calls Runner.Run in phase mode.
+// it calls Runner.Run in phase mode. The loop checkpoint is written to the
+// loop_checkpoint metadata key after every iteration so that a resumed
+// workflow continues the loop where it stopped.
 func (h *ImplementHandler) Execute(ctx context.Context, state *WorkflowState) (string, error) {
 	if h.Runner == nil {
 		return EventFailure, fmt.Errorf("implement handler: runner not configured")
@@ -76,14 +80,7 @@ func (h *ImplementHandler) Execute(ctx context.Context, state *WorkflowState) (s
 		TemplateName:  h.RunConfig.TemplateName,
 	}
 
-	var err error
-	if taskID != "" {
-		err = h.Runner.RunSingleTask(ctx, runCfg)
-	} else {
-		err = h.Runner.Run(ctx, runCfg)
-	}
-
-	if err != nil {
+	if err := runLoopWithCheckpoint(ctx, h.Runner, state, runCfg); err != nil {
 		return EventFailure, fmt.Errorf("implement handler: %w", err)
 	}
 
@@ -114,6 +111,18 @@ type ReviewHandler struct {
 	// Orchestrator is the multi-agent review coordinator injected at runtime.
 	// May be nil when the handler is registered for registry-only use.
 	Orchestrator *review.ReviewOrchestrator
+
+	// Personas are the reviewer personas run when the workflow state has no
+	// review_agents. May be nil for a plain agent review.
+	Personas []review.Persona
+
+	// Analyzers are the static analyzers whose findings are consolidated with
+	// the agents'. May be nil.
+	Analyzers []review.Analyzer
+
+	// Ledgers stores the per-branch finding ledgers that track findings
+	// across review/fix cycles. May be nil to skip lifecycle tracking.
+	Ledgers *review.ReviewStateStore
 }
 
 // Name returns the unique step name "run_review".
@@ -121,7 +130,9 @@ func (h *ReviewHandler) Name() string { return "run_review" }
 
 // Execute runs the review orchestrator, reads agent names, base branch, and
 // mode from the workflow state metadata, and stores the resulting verdict and
-// findings count back into the metadata.
+// findings count back into the metadata. When Ledgers is set, the review is
+// also recorded in the branch's finding ledger and the lifecycle counts are
+// stored as review_new, review_open, review_resolved, and review_regressed.
 func (h *ReviewHandler) Execute(ctx context.Context, state *WorkflowState) (string, error) {
 	if h.Orchestrator == nil {
 		return EventFailure, fmt.Errorf("review handler: orchestrator not configured")
@@ -135,6 +146,10 @@ func (h *ReviewHandler) Execute(ctx context.Context, state *WorkflowState) (stri
 		Agents:     agents,
 		BaseBranch: baseBranch,
 		Mode:       review.ReviewMode(modeStr),
+		Analyzers:  h.Analyzers,
+	}
+	if len(agents) == 0 {
+		opts.Personas = h.Personas
 	}
 
 	result, err := h.Orchestrator.Run(ctx, opts)
@@ -145,11 +160,54 @@ func (h *ReviewHandler) Execute(ctx context.Context, state *WorkflowState) (stri
 	if result.Consolidated != nil {
 		state.Metadata["review_verdict"] = string(result.Consolidated.Verdict)
 		state.Metadata["review_findings_count"] = len(result.Consolidated.Findings)
+		if metaBool(state, "publish_review", false) {
+			// Kept for PRHandler to publish; the agent results are left out
+			// to keep checkpoints small.
+			state.Metadata[reviewConsolidatedKey] = &review.ConsolidatedReview{
+				Findings:       result.Consolidated.Findings,
+				Waived:         result.Consolidated.Waived,
+				Verdict:        result.Consolidated.Verdict,
+				VerdictReasons: result.Consolidated.VerdictReasons,
+				TotalAgents:    result.Consolidated.TotalAgents,
+			}
+		}
+		if len(result.AgentErrors) == 0 {
+			if err := h.recordLifecycle(state, result.Consolidated); err != nil {
+				return EventFailure, fmt.Errorf("review handler: %w", err)
+			}
+		}
 	}
 
 	return EventSuccess, nil
 }
 
+// recordLifecycle records consolidated in the finding ledger of the workflow's
+// branch and stores the lifecycle counts in the metadata. It does nothing
+// without Ledgers or a branch_name. Reviews with failed agents are not
+// recorded, since their missing findings would count as resolved.
+func (h *ReviewHandler) recordLifecycle(state *WorkflowState, consolidated *review.ConsolidatedReview) error {
+	branch := metaString(state, "branch_name", "")
+	if h.Ledgers == nil || branch == "" {
+		return nil
+	}
+
+	ledger, err := h.Ledgers.LoadLedger(branch)
+	if err != nil {
+		return err
+	}
+	lc := ledger.Record(consolidated, time.Now())
+	if err := h.Ledgers.SaveLedger(ledger); err != nil {
+		return err
+	}
+
+	state.Metadata["review_new"] = lc.New
+	state.Metadata["review_open"] = lc.Open
+	state.Metadata["review_resolved"] = lc.Resolved
+	state.Metadata["review_regressed"] = lc.Regressed
+	state.Metadata["review_runs"] = metaInt(state, "review_runs", 0) + 1
+	return nil
+}
+
 // DryRun returns a human-readable description of what Execute would do.
 func (h *ReviewHandler) DryRun(state *WorkflowState) string {
 	baseBranch := metaString(state, "base_branch", "main")
@@ -165,6 +223,10 @@ func (h *ReviewHandler) DryRun(state *WorkflowState) string {
 // transition event: EventSuccess for an approved verdict, EventNeedsHuman for
 // changes-needed or blocking verdicts, and EventSuccess for an empty or unknown
 // verdict (treated as approved when no prior review has run).
+//
+// When the finding ledger shows that a review following a fix cycle resolved
+// none of the open findings, another fix cycle is unlikely to help, so the
+// handler returns EventFailure instead of looping back to the fix step.
 type CheckReviewHandler struct{}
 
 // Name returns the unique step name "check_review".
@@ -183,6 +245,9 @@ func (h *CheckReviewHandler) Execute(ctx context.Context, state *WorkflowState)
 	case string(review.VerdictApproved):
 		return EventSuccess, nil
 	case string(review.VerdictChangesNeeded), string(review.VerdictBlocking):
+		if err := checkFixProgress(state); err != nil {
+			return EventFailure, err
+		}
 		return EventNeedsHuman, nil
 	default:
 		// Empty or unrecognised verdict: treat as approved (no review ran yet).
@@ -190,6 +255,23 @@ func (h *CheckReviewHandler) Execute(ctx context.Context, state *WorkflowState)
 	}
 }
 
+// checkFixProgress returns an error when the latest review was not the
+// workflow's first and resolved none of the findings of the review before it.
+// Without lifecycle metadata it returns nil.
+func checkFixProgress(state *WorkflowState) error {
+	if metaInt(state, "review_runs", 0) < 2 {
+		return nil
+	}
+	if _, ok := state.Metadata["review_resolved"]; !ok {
+		return nil
+	}
+	if metaInt(state, "review_resolved", 0) > 0 {
+		return nil
+	}
+	open := metaInt(state, "review_open", 0) + metaInt(state, "review_regressed", 0)
+	return fmt.Errorf("check_review handler: fix cycle resolved none of the %d open finding(s); stopping", open)
+}
+
 // DryRun returns a human-readable description of what Execute would do.
 func (h *CheckReviewHandler) DryRun(_ *WorkflowState) string {
 	return "would check review verdict from metadata"
@@ -256,6 +338,10 @@ type PRHandler struct {
 	// Creator is the PR creation helper injected at runtime.
 	// May be nil when the handler is registered for registry-only use.
 	Creator *review.PRCreator
+
+	// Publisher posts the last review to the created PR when the workflow
+	// state has publish_review set. May be nil.
+	Publisher *review.ReviewPublisher
 }
 
 // Name returns the unique step name "create_pr".
@@ -263,7 +349,9 @@ func (h *PRHandler) Name() string { return "create_pr" }
 
 // Execute reads PR options from the workflow state metadata, calls
 // Creator.Create, and writes the resulting URL and PR number back into the
-// metadata.
+// metadata. With publish_review set, the last review is then posted to the
+// PR; a failure to post it is recorded in review_publish_error rather than
+// failing the step, since the PR already exists.
 func (h *PRHandler) Execute(ctx context.Context, state *WorkflowState) (string, error) {
 	if h.Creator == nil {
 		return EventFailure, fmt.Errorf("PR handler: creator not configured")
@@ -286,6 +374,16 @@ func (h *PRHandler) Execute(ctx context.Context, state *WorkflowState) (string,
 
 	state.Metadata["pr_url"] = result.URL
 	state.Metadata["pr_number"] = result.Number
+
+	consolidated := consolidatedReviewFromState(state)
+	if metaBool(state, "publish_review", false) && h.Publisher != nil && consolidated != nil && result.Number > 0 {
+		published, pubErr := h.Publisher.Publish(ctx, result.Number, consolidated)
+		if pubErr != nil {
+			state.Metadata["review_publish_error"] = pubErr.Error()
+		} else {
+			state.Metadata["review_published_url"] = published.ReviewURL
+		}
+	}
 	return EventSuccess, nil
 }
 
@@ -340,7 +438,8 @@ type RunPhaseWorkflowHandler struct {
 func (h *RunPhaseWorkflowHandler) Name() string { return "run_phase_workflow" }
 
 // Execute reads phase_id and agent_name from the workflow metadata and
-// delegates to Runner.Run for the resolved phase.
+// delegates to Runner.Run for the resolved phase, checkpointing loop progress
+// into the workflow metadata like ImplementHandler.
 func (h *RunPhaseWorkflowHandler) Execute(ctx context.Context, state *WorkflowState) (string, error) {
 	if h.Runner == nil {
 		return EventFailure, fmt.Errorf("run_phase_workflow handler: runner not configured")
@@ -354,7 +453,7 @@ func (h *RunPhaseWorkflowHandler) Execute(ctx context.Context, state *WorkflowSt
 		AgentName: agentName,
 	}
 
-	if err := h.Runner.Run(ctx, runCfg); err != nil {
+	if err := runLoopWithCheckpoint(ctx, h.Runner, state, runCfg); err != nil {
 		return EventFailure, fmt.Errorf("run_phase_workflow handler: %w", err)
 	}
 
@@ -551,10 +650,104 @@ func metaBool(state *WorkflowState, key string, defaultVal bool) bool {
 	return b
 }
 
+// LoopCheckpointFromState decodes the implementation loop checkpoint stored
+// under the loop_checkpoint metadata key. It accepts both the in-memory
+// loop.LoopCheckpoint value and the generic map produced by a JSON round-trip
+// through the StateStore. Returns nil when no usable checkpoint is present.
+func LoopCheckpointFromState(state *WorkflowState) *loop.LoopCheckpoint {
+	if state == nil || state.Metadata == nil {
+		return nil
+	}
+	v, ok := state.Metadata[loopCheckpointKey]
+	if !ok || v == nil {
+		return nil
+	}
+	switch cp := v.(type) {
+	case loop.LoopCheckpoint:
+		return &cp
+	case *loop.LoopCheckpoint:
+		return cp
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var cp loop.LoopCheckpoint
+	if err := json.Unmarshal(raw, &cp); err != nil {
+		return nil
+	}
+	return &cp
+}
+
+// consolidatedReviewFromState decodes the review stored under the
+// review_consolidated metadata key by ReviewHandler. Like
+// LoopCheckpointFromState it accepts the in-memory value and the generic map
+// produced by a JSON round-trip through the StateStore. Returns nil when no
+// review is present.
+func consolidatedReviewFromState(state *WorkflowState) *review.ConsolidatedReview {
+	if state == nil || state.Metadata == nil {
+		return nil
+	}
+	v, ok := state.Metadata[reviewConsolidatedKey]
+	if !ok || v == nil {
+		return nil
+	}
+	if cr, ok := v.(*review.ConsolidatedReview); ok {
+		return cr
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var cr review.ConsolidatedReview
+	if err := json.Unmarshal(raw, &cr); err != nil {
+		return nil
+	}
+	return &cr
+}
+
 // -----------------------------------------------------------------------
 // Internal helpers
 // -----------------------------------------------------------------------
 
+// reviewConsolidatedKey is the metadata key under which ReviewHandler keeps
+// the last review for PRHandler to publish.
+const reviewConsolidatedKey = "review_consolidated"
+
+// loopCheckpointKey is the metadata key under which the implementation loop
+// checkpoint of the current step is stored.
+const loopCheckpointKey = "loop_checkpoint"
+
+// runLoopWithCheckpoint runs the implementation loop for runCfg, resuming from
+// any loop checkpoint recorded in state. While the loop runs, every checkpoint
+// it reports is stored in state.Metadata and persisted through SaveCheckpoint.
+// The checkpoint is removed once the loop finishes successfully so that a
+// later run of the same step starts fresh. Task state changes made by the
+// loop are recorded in the history under the workflow run ID.
+func runLoopWithCheckpoint(ctx context.Context, runner *loop.Runner, state *WorkflowState, runCfg loop.RunConfig) error {
+	runCfg.Resume = LoopCheckpointFromState(state)
+	runCfg.RunID = state.ID
+
+	runner.SetCheckpointFunc(func(cp loop.LoopCheckpoint) error {
+		state.Metadata[loopCheckpointKey] = cp
+		return SaveCheckpoint(ctx, state)
+	})
+	defer runner.SetCheckpointFunc(nil)
+
+	var err error
+	if runCfg.TaskID != "" {
+		err = runner.RunSingleTask(ctx, runCfg)
+	} else {
+		err = runner.Run(ctx, runCfg)
+	}
+	if err != nil {
+		return err
+	}
+
+	delete(state.Metadata, loopCheckpointKey)
+	return nil
+}
+
 // resolveAgents extracts the agent list from state.Metadata["review_agents"].
 // It accepts either a []string value (set programmatically) or a
 // comma-separated string (set from TOML / CLI flags). A nil slice is returned
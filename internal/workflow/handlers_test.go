@@ -2,10 +2,14 @@ package workflow
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/AbdelazizMoustafa10m/Raven/internal/loop"
+	"github.com/AbdelazizMoustafa10m/Raven/internal/review"
 )
 
 // -----------------------------------------------------------------------
@@ -312,6 +316,43 @@ func TestReviewHandler_DryRun(t *testing.T) {
 	assert.Contains(t, got, "develop")
 }
 
+func TestReviewHandler_RecordLifecycle(t *testing.T) {
+	t.Parallel()
+	store := review.NewReviewStateStore(t.TempDir())
+	h := &ReviewHandler{Ledgers: store}
+	state := NewWorkflowState("run-1", "implement-review-pr", "run_review")
+	state.Metadata["branch_name"] = "phase/1"
+
+	finding := func(file, description string) *review.Finding {
+		return &review.Finding{Severity: review.SeverityMedium, Category: "bug", File: file, Line: 1, Description: description}
+	}
+
+	require.NoError(t, h.recordLifecycle(state, &review.ConsolidatedReview{Findings: []*review.Finding{
+		finding("a.go", "Nil map write"),
+		finding("b.go", "Off by one in the loop bound"),
+	}}))
+	assert.Equal(t, 2, state.Metadata["review_new"])
+	assert.Equal(t, 0, state.Metadata["review_resolved"])
+	assert.Equal(t, 1, state.Metadata["review_runs"])
+
+	require.NoError(t, h.recordLifecycle(state, &review.ConsolidatedReview{Findings: []*review.Finding{
+		finding("a.go", "Nil map write"),
+	}}))
+	assert.Equal(t, 0, state.Metadata["review_new"])
+	assert.Equal(t, 1, state.Metadata["review_open"])
+	assert.Equal(t, 1, state.Metadata["review_resolved"])
+	assert.Equal(t, 2, state.Metadata["review_runs"])
+
+	ledger, err := store.LoadLedger("phase/1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, ledger.Reviews)
+
+	// Without a branch nothing is recorded.
+	other := NewWorkflowState("run-2", "implement-review-pr", "run_review")
+	require.NoError(t, h.recordLifecycle(other, &review.ConsolidatedReview{}))
+	assert.NotContains(t, other.Metadata, "review_runs")
+}
+
 // -----------------------------------------------------------------------
 // CheckReviewHandler tests
 // -----------------------------------------------------------------------
@@ -371,6 +412,61 @@ func TestCheckReviewHandler_Execute(t *testing.T) {
 	}
 }
 
+func TestCheckReviewHandler_FixProgress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		metadata  map[string]any
+		wantEvent string
+	}{
+		{
+			name:      "first review loops to fix",
+			metadata:  map[string]any{"review_runs": 1, "review_resolved": 0, "review_open": 0, "review_new": 3},
+			wantEvent: EventNeedsHuman,
+		},
+		{
+			name:      "fix cycle that resolved findings loops again",
+			metadata:  map[string]any{"review_runs": 2, "review_resolved": 1, "review_open": 2},
+			wantEvent: EventNeedsHuman,
+		},
+		{
+			name:      "fix cycle that resolved nothing stops",
+			metadata:  map[string]any{"review_runs": 2, "review_resolved": 0, "review_open": 2, "review_regressed": 1},
+			wantEvent: EventFailure,
+		},
+		{
+			name:      "no lifecycle metadata loops to fix",
+			metadata:  map[string]any{},
+			wantEvent: EventNeedsHuman,
+		},
+		{
+			name:      "counts restored from a checkpoint",
+			metadata:  map[string]any{"review_runs": float64(3), "review_resolved": float64(0), "review_open": float64(1)},
+			wantEvent: EventFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			state := NewWorkflowState("run-1", "implement-review-pr", "check_review")
+			state.Metadata["review_verdict"] = string(review.VerdictChangesNeeded)
+			for k, v := range tt.metadata {
+				state.Metadata[k] = v
+			}
+			event, err := (&CheckReviewHandler{}).Execute(context.Background(), state)
+			assert.Equal(t, tt.wantEvent, event)
+			if tt.wantEvent == EventFailure {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "resolved none of the")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestCheckReviewHandler_ContextCancelled(t *testing.T) {
 	t.Parallel()
 	h := &CheckReviewHandler{}
@@ -681,3 +777,77 @@ func TestHandlerNames(t *testing.T) {
 		})
 	}
 }
+
+// -----------------------------------------------------------------------
+// LoopCheckpointFromState tests
+// -----------------------------------------------------------------------
+
+func TestLoopCheckpointFromState(t *testing.T) {
+	t.Parallel()
+
+	cp := loop.LoopCheckpoint{
+		PhaseID:        2,
+		CurrentTask:    "T-012",
+		Iteration:      4,
+		RateLimitWaits: 1,
+		RecentTasks:    []string{"T-011", "T-012"},
+		TaskAttempts:   map[string]int{"T-011": 1, "T-012": 2},
+		SessionIDs:     map[string]string{"T-011": "sess-1"},
+	}
+
+	// Simulate the JSON round-trip performed by StateStore.Save/Load.
+	raw, err := json.Marshal(map[string]any{"loop_checkpoint": cp})
+	require.NoError(t, err)
+	var roundTripped map[string]any
+	require.NoError(t, json.Unmarshal(raw, &roundTripped))
+
+	tests := []struct {
+		name  string
+		state *WorkflowState
+		want  *loop.LoopCheckpoint
+	}{
+		{name: "nil state", state: nil, want: nil},
+		{name: "missing key", state: &WorkflowState{Metadata: map[string]any{}}, want: nil},
+		{name: "in-memory value", state: &WorkflowState{Metadata: map[string]any{"loop_checkpoint": cp}}, want: &cp},
+		{name: "pointer value", state: &WorkflowState{Metadata: map[string]any{"loop_checkpoint": &cp}}, want: &cp},
+		{name: "JSON round-trip map", state: &WorkflowState{Metadata: roundTripped}, want: &cp},
+		{name: "unusable value", state: &WorkflowState{Metadata: map[string]any{"loop_checkpoint": "garbage"}}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := LoopCheckpointFromState(tt.state)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, *tt.want, *got)
+		})
+	}
+}
+
+func TestConsolidatedReviewFromState(t *testing.T) {
+	t.Parallel()
+
+	cr := &review.ConsolidatedReview{
+		Verdict:        review.VerdictChangesNeeded,
+		VerdictReasons: []string{"claude voted CHANGES_NEEDED"},
+		Findings: []*review.Finding{
+			{Severity: review.SeverityHigh, Category: "security", File: "a.go", Line: 3, Description: "d", Fingerprint: "abc123"},
+		},
+	}
+
+	// Simulate the JSON round-trip performed by StateStore.Save/Load.
+	raw, err := json.Marshal(map[string]any{"review_consolidated": cr})
+	require.NoError(t, err)
+	var roundTripped map[string]any
+	require.NoError(t, json.Unmarshal(raw, &roundTripped))
+
+	assert.Nil(t, consolidatedReviewFromState(nil))
+	assert.Nil(t, consolidatedReviewFromState(&WorkflowState{Metadata: map[string]any{}}))
+	assert.Same(t, cr, consolidatedReviewFromState(&WorkflowState{Metadata: map[string]any{"review_consolidated": cr}}))
+	assert.Equal(t, cr, consolidatedReviewFromState(&WorkflowState{Metadata: roundTripped}))
+	assert.Nil(t, consolidatedReviewFromState(&WorkflowState{Metadata: map[string]any{"review_consolidated": "garbage"}}))
+}
@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -240,7 +241,8 @@ func (s *StateStore) LatestRun() (*WorkflowState, error) {
 
 // WithCheckpointing returns an EngineOption that auto-saves the WorkflowState
 // to store after every step completes. Hook errors are logged but do not abort
-// the workflow.
+// the workflow. The same hook is made available to handlers through
+// SaveCheckpoint for persisting progress within a long-running step.
 func WithCheckpointing(store *StateStore) EngineOption {
 	return func(e *Engine) {
 		e.postStepHook = func(state *WorkflowState) error {
@@ -249,6 +251,33 @@ func WithCheckpointing(store *StateStore) EngineOption {
 	}
 }
 
+// checkpointKey is the context key under which the engine exposes its
+// post-step hook to the handler that is currently executing.
+type checkpointKey struct{}
+
+// withCheckpointHook returns a copy of ctx carrying hook so that long-running
+// handlers can persist intermediate state via SaveCheckpoint.
+func withCheckpointHook(ctx context.Context, hook func(*WorkflowState) error) context.Context {
+	if hook == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, checkpointKey{}, hook)
+}
+
+// SaveCheckpoint persists state in the middle of a step using the checkpoint
+// hook of the engine executing that step (see WithCheckpointing). It lets
+// long-running handlers such as run_implement record progress that would
+// otherwise be lost if the process is interrupted before the step completes.
+// It is a no-op when the engine has no checkpoint hook configured.
+func SaveCheckpoint(ctx context.Context, state *WorkflowState) error {
+	hook, ok := ctx.Value(checkpointKey{}).(func(*WorkflowState) error)
+	if !ok || hook == nil {
+		return nil
+	}
+	state.UpdatedAt = time.Now()
+	return hook(state)
+}
+
 // sanitizeID replaces any character outside [a-zA-Z0-9_-] with an underscore
 // so that run IDs are safe to use as filesystem filenames.
 func sanitizeID(id string) string {
@@ -763,6 +763,57 @@ func TestWithCheckpointing_HookErrorDoesNotAbortWorkflow(t *testing.T) {
 	}
 }
 
+// midStepCheckpointHandler writes a metadata key and persists the state via
+// SaveCheckpoint before returning, recording whether the checkpoint landed on
+// disk while the step was still running.
+type midStepCheckpointHandler struct {
+	name  string
+	store *StateStore
+	seen  *WorkflowState
+}
+
+func (h *midStepCheckpointHandler) Execute(ctx context.Context, state *WorkflowState) (string, error) {
+	state.Metadata["progress"] = "halfway"
+	if err := SaveCheckpoint(ctx, state); err != nil {
+		return EventFailure, err
+	}
+	loaded, err := h.store.Load(state.ID)
+	if err != nil {
+		return EventFailure, err
+	}
+	h.seen = loaded
+	return EventSuccess, nil
+}
+func (h *midStepCheckpointHandler) DryRun(_ *WorkflowState) string { return "dry-run: " + h.name }
+func (h *midStepCheckpointHandler) Name() string                   { return h.name }
+
+func TestSaveCheckpoint_PersistsMidStep(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewStateStore(t.TempDir())
+	require.NoError(t, err)
+
+	h := &midStepCheckpointHandler{name: "step-a", store: store}
+	reg := registerAll(h)
+	def := linearDef("step-a")
+
+	eng := NewEngine(reg, WithCheckpointing(store))
+	_, err = eng.Run(context.Background(), def, nil)
+	require.NoError(t, err)
+
+	require.NotNil(t, h.seen, "checkpoint must be readable while the step is running")
+	assert.Equal(t, "step-a", h.seen.CurrentStep, "mid-step checkpoint must still point at the running step")
+	assert.Equal(t, "halfway", h.seen.Metadata["progress"])
+	assert.Empty(t, h.seen.StepHistory)
+}
+
+func TestSaveCheckpoint_NoHookIsNoop(t *testing.T) {
+	t.Parallel()
+
+	state := NewWorkflowState("wf-1", "wf", "step-a")
+	assert.NoError(t, SaveCheckpoint(context.Background(), state))
+}
+
 // ---------------------------------------------------------------------------
 // TestSanitizeID -- additional edge cases
 // ---------------------------------------------------------------------------